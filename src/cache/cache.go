@@ -1,8 +1,8 @@
 // Package cache implements the server.cache.* backend documented in AI.md
 // PART 12 "Cache Configuration". It provides a single Store abstraction
-// used for sliding-window rate-limit counters (and, in future, general
-// response/session caching): "memory" (default, in-process, lost on
-// restart) or "valkey"/"redis" (shared, persists across restarts).
+// used for sliding-window rate-limit counters and outbound-provider
+// response caching: "memory" (default, in-process, lost on restart) or
+// "valkey"/"redis" (shared, persists across restarts).
 package cache
 
 import (
@@ -13,7 +13,7 @@ import (
 	"github.com/apimgr/api/src/config"
 )
 
-// Store is a sliding-window counter backend.
+// Store is a sliding-window counter and key/value backend.
 type Store interface {
 	// SlidingWindow records a hit for key at now and returns the number of
 	// hits still inside the window (now-window, now], plus the time at
@@ -21,6 +21,22 @@ type Store interface {
 	// (used for the Retry-After / X-RateLimit-Reset calculation).
 	SlidingWindow(ctx context.Context, key string, now time.Time, window time.Duration) (count int, resetAt time.Time, err error)
 
+	// Get returns the value stored under key, with ok=false when the key
+	// is absent or has expired. A missing key is not an error.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Set stores value under key for ttl; a ttl <= 0 stores it without
+	// expiry.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// TTL returns the remaining lifetime of key, with ok=false when the
+	// key is absent or has expired. A key stored without expiry reports
+	// a zero ttl and ok=true.
+	TTL(ctx context.Context, key string) (ttl time.Duration, ok bool, err error)
+
 	// Close releases any underlying connections. Safe to call on a store
 	// that was never connected.
 	Close() error
@@ -7,14 +7,33 @@ import (
 )
 
 // memoryStore is the default in-process Store: a sliding window of
-// timestamps per key, guarded by a per-key mutex. Lost on restart, not
-// shared across instances - the documented tradeoff of server.cache.type:
-// memory (or none) versus valkey/redis.
+// timestamps per key, guarded by a per-key mutex, plus a key/value map for
+// Get/Set. Lost on restart, not shared across instances - the documented
+// tradeoff of server.cache.type: memory (or none) versus valkey/redis.
 type memoryStore struct {
 	mu      sync.Mutex
 	windows map[string]*window
+
+	entriesMu sync.Mutex
+	entries   map[string]entry
+}
+
+// entry is a single Get/Set value; a zero expiresAt never expires.
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// expired reports whether e has passed its expiry at now.
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
 }
 
+// maxMemoryEntries caps the number of Get/Set values held in process so
+// that a stream of distinct cache keys cannot grow the map without bound;
+// once full, Set evicts expired entries and then arbitrary ones.
+const maxMemoryEntries = 10000
+
 // window tracks the recent hit timestamps for a single key.
 type window struct {
 	mu         sync.Mutex
@@ -29,18 +48,21 @@ const staleAfter = 10 * time.Minute
 // newMemoryStore creates an empty in-process store and starts its
 // background sweep of stale, idle keys.
 func newMemoryStore() *memoryStore {
-	m := &memoryStore{windows: make(map[string]*window)}
+	m := &memoryStore{windows: make(map[string]*window), entries: make(map[string]entry)}
 	go m.sweepLoop()
 	return m
 }
 
 // sweepLoop periodically removes keys with no timestamps inside staleAfter,
-// mirroring the pre-cache.Store cleanup behaviour of the in-process limiter.
+// mirroring the pre-cache.Store cleanup behaviour of the in-process limiter,
+// and drops expired Get/Set entries.
 func (m *memoryStore) sweepLoop() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
 	for range ticker.C {
+		m.sweepEntries(time.Now())
+
 		cutoff := time.Now().Add(-staleAfter)
 
 		m.mu.Lock()
@@ -83,6 +105,87 @@ func (m *memoryStore) SlidingWindow(_ context.Context, key string, now time.Time
 	return len(valid), resetAt, nil
 }
 
+// sweepEntries removes every Get/Set entry that has expired at now.
+func (m *memoryStore) sweepEntries(now time.Time) {
+	m.entriesMu.Lock()
+	defer m.entriesMu.Unlock()
+	for key, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// Get implements Store.
+func (m *memoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.entriesMu.Lock()
+	defer m.entriesMu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if e.expired(time.Now()) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return append([]byte(nil), e.value...), true, nil
+}
+
+// Set implements Store.
+func (m *memoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	e := entry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+
+	m.entriesMu.Lock()
+	defer m.entriesMu.Unlock()
+
+	if _, exists := m.entries[key]; !exists && len(m.entries) >= maxMemoryEntries {
+		for k, old := range m.entries {
+			if old.expired(now) {
+				delete(m.entries, k)
+			}
+		}
+		// Map iteration order is unspecified, so this evicts an arbitrary
+		// entry - a cheap stand-in for LRU that still bounds memory.
+		for k := range m.entries {
+			if len(m.entries) < maxMemoryEntries {
+				break
+			}
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = e
+	return nil
+}
+
+// Delete implements Store.
+func (m *memoryStore) Delete(_ context.Context, key string) error {
+	m.entriesMu.Lock()
+	delete(m.entries, key)
+	m.entriesMu.Unlock()
+	return nil
+}
+
+// TTL implements Store.
+func (m *memoryStore) TTL(_ context.Context, key string) (time.Duration, bool, error) {
+	m.entriesMu.Lock()
+	defer m.entriesMu.Unlock()
+
+	now := time.Now()
+	e, ok := m.entries[key]
+	if !ok || e.expired(now) {
+		return 0, false, nil
+	}
+	if e.expiresAt.IsZero() {
+		return 0, true, nil
+	}
+	return e.expiresAt.Sub(now), true, nil
+}
+
 // Close is a no-op for the in-process store.
 func (m *memoryStore) Close() error {
 	return nil
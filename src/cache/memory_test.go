@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -68,3 +69,88 @@ func TestMemoryStoreSweepStaleKeys(t *testing.T) {
 	assert.False(t, staleStillPresent, "stale key must be swept")
 	assert.True(t, freshStillPresent, "fresh key must survive the sweep")
 }
+
+// Get/Set must round-trip values, report missing keys without an error,
+// and stop returning a value once its ttl has elapsed.
+func TestMemoryStoreGetSet(t *testing.T) {
+	m := newMemoryStore()
+	ctx := context.Background()
+
+	_, ok, err := m.Get(ctx, "missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, m.Set(ctx, "key", []byte("value"), time.Minute))
+	value, ok, err := m.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	// The returned slice must be a copy, not the stored backing array.
+	value[0] = 'X'
+	value, _, _ = m.Get(ctx, "key")
+	assert.Equal(t, []byte("value"), value)
+
+	assert.NoError(t, m.Set(ctx, "short", []byte("v"), 20*time.Millisecond))
+	time.Sleep(40 * time.Millisecond)
+	_, ok, err = m.Get(ctx, "short")
+	assert.NoError(t, err)
+	assert.False(t, ok, "expired key must not be returned")
+
+	assert.NoError(t, m.Delete(ctx, "key"))
+	_, ok, _ = m.Get(ctx, "key")
+	assert.False(t, ok)
+}
+
+// TTL must report the remaining lifetime, a zero ttl for keys stored
+// without expiry, and ok=false for missing keys.
+func TestMemoryStoreTTL(t *testing.T) {
+	m := newMemoryStore()
+	ctx := context.Background()
+
+	assert.NoError(t, m.Set(ctx, "expiring", []byte("v"), time.Minute))
+	ttl, ok, err := m.TTL(ctx, "expiring")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, ttl > 59*time.Second && ttl <= time.Minute)
+
+	assert.NoError(t, m.Set(ctx, "forever", []byte("v"), 0))
+	ttl, ok, err = m.TTL(ctx, "forever")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	_, ok, err = m.TTL(ctx, "missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// Set must never grow the map past maxMemoryEntries, while still storing
+// the newest value.
+func TestMemoryStoreEntryCap(t *testing.T) {
+	m := &memoryStore{windows: make(map[string]*window), entries: make(map[string]entry)}
+	ctx := context.Background()
+
+	for i := 0; i < maxMemoryEntries+10; i++ {
+		assert.NoError(t, m.Set(ctx, fmt.Sprintf("key-%d", i), []byte("v"), time.Minute))
+	}
+	assert.Len(t, m.entries, maxMemoryEntries)
+
+	_, ok, _ := m.Get(ctx, fmt.Sprintf("key-%d", maxMemoryEntries+9))
+	assert.True(t, ok, "the most recent Set must survive eviction")
+}
+
+// sweepEntries must drop expired values and keep live ones.
+func TestMemoryStoreSweepEntries(t *testing.T) {
+	m := &memoryStore{windows: make(map[string]*window), entries: make(map[string]entry)}
+	now := time.Now()
+	m.entries["expired"] = entry{value: []byte("v"), expiresAt: now.Add(-time.Second)}
+	m.entries["live"] = entry{value: []byte("v"), expiresAt: now.Add(time.Minute)}
+	m.entries["forever"] = entry{value: []byte("v")}
+
+	m.sweepEntries(now)
+
+	assert.NotContains(t, m.entries, "expired")
+	assert.Contains(t, m.entries, "live")
+	assert.Contains(t, m.entries, "forever")
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"time"
 
@@ -127,6 +128,68 @@ func (r *redisStore) SlidingWindow(ctx context.Context, key string, now time.Tim
 	return int(card.Val()), resetAt, nil
 }
 
+// Get implements Store; a redis.Nil reply (missing or expired key) is
+// reported as ok=false rather than an error.
+func (r *redisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("cache get: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set implements Store. go-redis treats a zero expiration as "no expiry",
+// which matches the Store contract for ttl <= 0.
+func (r *redisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := r.client.Set(ctx, r.prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("cache set: %w", err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (r *redisStore) Delete(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if err := r.client.Del(ctx, r.prefix+key).Err(); err != nil {
+		return fmt.Errorf("cache delete: %w", err)
+	}
+	return nil
+}
+
+// TTL implements Store using PTTL, which replies -2 for a missing key and
+// -1 for a key without expiry (surfaced by go-redis as those raw
+// nanosecond durations).
+func (r *redisStore) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	ttl, err := r.client.PTTL(ctx, r.prefix+key).Result()
+	if err != nil {
+		return 0, false, fmt.Errorf("cache ttl: %w", err)
+	}
+	switch ttl {
+	case -2:
+		return 0, false, nil
+	case -1:
+		return 0, true, nil
+	}
+	return ttl, true, nil
+}
+
 // Close closes the underlying redis client connection pool.
 func (r *redisStore) Close() error {
 	return r.client.Close()
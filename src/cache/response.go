@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/apimgr/api/src/config"
+)
+
+// Status reports how a ResponseCache.Fetch was served; the server surfaces
+// it in the X-Cache response header.
+type Status string
+
+// Fetch outcomes
+const (
+	// StatusHit is a fresh cached value; the upstream was not contacted.
+	StatusHit Status = "HIT"
+	// StatusMiss is a value fetched from the upstream and then cached.
+	StatusMiss Status = "MISS"
+	// StatusStale is a value past its TTL but inside the stale window,
+	// served while a background refresh runs (or while the upstream is
+	// failing).
+	StatusStale Status = "STALE"
+	// StatusBypass is a value fetched from the upstream with caching
+	// disabled.
+	StatusBypass Status = "BYPASS"
+)
+
+// refreshTimeout bounds a background stale-while-revalidate refresh, which
+// runs detached from the request that triggered it.
+const refreshTimeout = 30 * time.Second
+
+// defaultResponseTTL and defaultStaleWindow apply when server.cache.ttl or
+// server.cache.responses.stale are empty or invalid.
+const (
+	defaultResponseTTL = time.Hour
+	defaultStaleWindow = 24 * time.Hour
+)
+
+// ResponseCache caches outbound-provider lookups in a Store with a
+// per-provider TTL and stale-while-revalidate: an entry past its TTL but
+// within the stale window is served immediately while one background
+// refresh per key replaces it, so an upstream outage or throttling keeps
+// serving the last good value until the stale window runs out. A nil
+// *ResponseCache is valid and bypasses caching.
+type ResponseCache struct {
+	store      Store
+	ttls       map[string]time.Duration
+	defaultTTL time.Duration
+	stale      time.Duration
+
+	mu         sync.Mutex
+	refreshing map[string]struct{}
+}
+
+// NewResponseCache builds the server.cache.responses cache over store. It
+// returns nil (caching disabled) when server.cache.type is "none" or
+// server.cache.responses.enabled is false.
+func NewResponseCache(store Store, cfg config.CacheConfig) *ResponseCache {
+	if normalizeType(cfg.Type) == "none" || !cfg.Responses.Enabled {
+		return nil
+	}
+
+	c := &ResponseCache{
+		store:      store,
+		ttls:       make(map[string]time.Duration, len(cfg.Responses.Providers)),
+		defaultTTL: parseDuration(cfg.TTL, defaultResponseTTL),
+		stale:      parseDuration(cfg.Responses.Stale, defaultStaleWindow),
+		refreshing: make(map[string]struct{}),
+	}
+	for provider, ttl := range cfg.Responses.Providers {
+		c.ttls[lowerASCII(provider)] = parseDuration(ttl, c.defaultTTL)
+	}
+	return c
+}
+
+// Enabled reports whether c caches anything.
+func (c *ResponseCache) Enabled() bool {
+	return c != nil
+}
+
+// TTL returns the freshness lifetime configured for provider.
+func (c *ResponseCache) TTL(provider string) time.Duration {
+	if ttl, ok := c.ttls[lowerASCII(provider)]; ok {
+		return ttl
+	}
+	return c.defaultTTL
+}
+
+// Fetch returns the cached value for (provider, key), calling fetch on a
+// miss. A Store error never fails the lookup: it is logged and the
+// upstream is called directly, matching the rate limiter's fail-open
+// handling of a degraded cache.
+func (c *ResponseCache) Fetch(ctx context.Context, provider, key string, fetch func(ctx context.Context) ([]byte, error)) ([]byte, Status, error) {
+	if c == nil {
+		value, err := fetch(ctx)
+		return value, StatusBypass, err
+	}
+
+	storeKey := responseKey(provider, key)
+	ttl := c.TTL(provider)
+
+	raw, ok, err := c.store.Get(ctx, storeKey)
+	if err != nil {
+		slog.Warn("cache: response lookup failed, calling upstream", "provider", provider, "error", err)
+	}
+	if ok {
+		if storedAt, value, valid := decodeResponse(raw); valid {
+			if time.Since(storedAt) < ttl {
+				return value, StatusHit, nil
+			}
+			c.refresh(provider, storeKey, ttl, fetch)
+			return value, StatusStale, nil
+		}
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return nil, StatusMiss, err
+	}
+	c.put(ctx, provider, storeKey, ttl, value)
+	return value, StatusMiss, nil
+}
+
+// FetchJSON is Fetch for a typed lookup, JSON-encoding fetch's result for
+// the store and decoding the cached copy back into T.
+func FetchJSON[T any](ctx context.Context, c *ResponseCache, provider, key string, fetch func(ctx context.Context) (T, error)) (T, Status, error) {
+	var result T
+	raw, status, err := c.Fetch(ctx, provider, key, func(ctx context.Context) ([]byte, error) {
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	})
+	if err != nil {
+		return result, status, err
+	}
+	err = json.Unmarshal(raw, &result)
+	return result, status, err
+}
+
+// refresh re-fetches a stale entry in the background, at most once at a
+// time per key. A failed refresh leaves the stale entry in place.
+func (c *ResponseCache) refresh(provider, storeKey string, ttl time.Duration, fetch func(ctx context.Context) ([]byte, error)) {
+	c.mu.Lock()
+	if _, busy := c.refreshing[storeKey]; busy {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[storeKey] = struct{}{}
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, storeKey)
+			c.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		value, err := fetch(ctx)
+		if err != nil {
+			slog.Warn("cache: stale response refresh failed, serving stale", "provider", provider, "error", err)
+			return
+		}
+		c.put(ctx, provider, storeKey, ttl, value)
+	}()
+}
+
+// put stores value for ttl plus the stale window, so it outlives its
+// freshness long enough to be served stale.
+func (c *ResponseCache) put(ctx context.Context, provider, storeKey string, ttl time.Duration, value []byte) {
+	if err := c.store.Set(ctx, storeKey, encodeResponse(time.Now(), value), ttl+c.stale); err != nil {
+		slog.Warn("cache: storing response failed", "provider", provider, "error", err)
+	}
+}
+
+// responseKey namespaces a provider lookup in the Store. The caller's key
+// (often a raw user-supplied location or word) is hashed so arbitrary
+// input never ends up verbatim in a shared cache key.
+func responseKey(provider, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "resp:" + lowerASCII(provider) + ":" + hex.EncodeToString(sum[:])
+}
+
+// encodeResponse frames value behind its 8-byte big-endian storage time in
+// Unix nanoseconds, which Fetch compares against the provider TTL.
+func encodeResponse(storedAt time.Time, value []byte) []byte {
+	buf := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(buf, uint64(storedAt.UnixNano()))
+	copy(buf[8:], value)
+	return buf
+}
+
+// decodeResponse reverses encodeResponse, reporting valid=false for a
+// value too short to carry the timestamp header.
+func decodeResponse(raw []byte) (storedAt time.Time, value []byte, valid bool) {
+	if len(raw) < 8 {
+		return time.Time{}, nil, false
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(raw))), raw[8:], true
+}
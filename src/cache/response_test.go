@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apimgr/api/src/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResponseConfig returns an enabled responses config with a single
+// "weather" provider TTL.
+func testResponseConfig(ttl string) config.CacheConfig {
+	return config.CacheConfig{
+		Type: "memory",
+		TTL:  "1h",
+		Responses: config.ResponseCacheConfig{
+			Enabled:   true,
+			Stale:     "1h",
+			Providers: map[string]string{"weather": ttl},
+		},
+	}
+}
+
+// NewResponseCache must be disabled (nil, bypassing) for type "none" or
+// responses.enabled=false, and a nil cache must still call the upstream.
+func TestNewResponseCacheDisabled(t *testing.T) {
+	cfg := testResponseConfig("10m")
+	cfg.Type = "none"
+	assert.Nil(t, NewResponseCache(newMemoryStore(), cfg))
+
+	cfg = testResponseConfig("10m")
+	cfg.Responses.Enabled = false
+	c := NewResponseCache(newMemoryStore(), cfg)
+	assert.False(t, c.Enabled())
+
+	value, status, err := c.Fetch(context.Background(), "weather", "paris", func(context.Context) ([]byte, error) {
+		return []byte("sunny"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusBypass, status)
+	assert.Equal(t, []byte("sunny"), value)
+}
+
+// Providers without a configured TTL fall back to server.cache.ttl.
+func TestResponseCacheTTL(t *testing.T) {
+	c := NewResponseCache(newMemoryStore(), testResponseConfig("10m"))
+	require.NotNil(t, c)
+	assert.Equal(t, 10*time.Minute, c.TTL("weather"))
+	assert.Equal(t, 10*time.Minute, c.TTL("Weather"))
+	assert.Equal(t, time.Hour, c.TTL("isbn"))
+}
+
+// Fetch must call the upstream once on a miss and serve later lookups
+// from the cache, keyed per provider and key.
+func TestResponseCacheHitMiss(t *testing.T) {
+	c := NewResponseCache(newMemoryStore(), testResponseConfig("10m"))
+	ctx := context.Background()
+	var calls int32
+	fetch := func(context.Context) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return []byte("sunny"), nil
+	}
+
+	value, status, err := c.Fetch(ctx, "weather", "paris", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMiss, status)
+	assert.Equal(t, []byte("sunny"), value)
+
+	value, status, err = c.Fetch(ctx, "weather", "paris", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, StatusHit, status)
+	assert.Equal(t, []byte("sunny"), value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, status, _ = c.Fetch(ctx, "weather", "london", fetch)
+	assert.Equal(t, StatusMiss, status)
+	_, status, _ = c.Fetch(ctx, "geocode", "paris", fetch)
+	assert.Equal(t, StatusMiss, status)
+}
+
+// An upstream error on a miss is returned and nothing is cached.
+func TestResponseCacheMissError(t *testing.T) {
+	c := NewResponseCache(newMemoryStore(), testResponseConfig("10m"))
+	ctx := context.Background()
+	upstreamErr := errors.New("upstream down")
+
+	_, _, err := c.Fetch(ctx, "weather", "paris", func(context.Context) ([]byte, error) {
+		return nil, upstreamErr
+	})
+	assert.ErrorIs(t, err, upstreamErr)
+
+	_, status, err := c.Fetch(ctx, "weather", "paris", func(context.Context) ([]byte, error) {
+		return []byte("sunny"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusMiss, status)
+}
+
+// Past its TTL an entry is served stale while a background refresh runs;
+// a failing refresh keeps serving the stale value, and a successful one
+// replaces it.
+func TestResponseCacheStaleWhileRevalidate(t *testing.T) {
+	store := newMemoryStore()
+	c := NewResponseCache(store, testResponseConfig("1m"))
+	ctx := context.Background()
+	key := responseKey("weather", "paris")
+
+	// Seed an entry stored two minutes ago: past the 1m TTL, inside the
+	// 1h stale window.
+	require.NoError(t, store.Set(ctx, key, encodeResponse(time.Now().Add(-2*time.Minute), []byte("old")), time.Hour))
+
+	value, status, err := c.Fetch(ctx, "weather", "paris", func(context.Context) ([]byte, error) {
+		return nil, errors.New("upstream down")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusStale, status)
+	assert.Equal(t, []byte("old"), value)
+
+	// Wait for the failed refresh to finish, then check the stale value
+	// survived it.
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.refreshing) == 0
+	}, time.Second, 5*time.Millisecond)
+	raw, ok, _ := store.Get(ctx, key)
+	require.True(t, ok)
+	_, stored, _ := decodeResponse(raw)
+	assert.Equal(t, []byte("old"), stored)
+
+	_, status, _ = c.Fetch(ctx, "weather", "paris", func(context.Context) ([]byte, error) {
+		return []byte("new"), nil
+	})
+	assert.Equal(t, StatusStale, status)
+
+	assert.Eventually(t, func() bool {
+		value, status, _ := c.Fetch(ctx, "weather", "paris", func(context.Context) ([]byte, error) {
+			return []byte("new"), nil
+		})
+		return status == StatusHit && string(value) == "new"
+	}, time.Second, 5*time.Millisecond)
+}
+
+// encodeResponse/decodeResponse must round-trip the storage time and
+// payload, and reject a truncated value.
+func TestEncodeDecodeResponse(t *testing.T) {
+	now := time.Now()
+	storedAt, value, ok := decodeResponse(encodeResponse(now, []byte("payload")))
+	require.True(t, ok)
+	assert.Equal(t, now.UnixNano(), storedAt.UnixNano())
+	assert.Equal(t, []byte("payload"), value)
+
+	_, _, ok = decodeResponse([]byte("short"))
+	assert.False(t, ok)
+}
+
+// responseKey must namespace by provider and never embed the raw key.
+func TestResponseKey(t *testing.T) {
+	key := responseKey("Weather", "Paris, France")
+	assert.Contains(t, key, "resp:weather:")
+	assert.NotContains(t, key, "Paris")
+	assert.NotEqual(t, key, responseKey("geocode", "Paris, France"))
+}
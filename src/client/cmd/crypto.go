@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/apimgr/api/src/client/api"
 	"github.com/apimgr/api/src/client/output"
 )
@@ -187,4 +190,55 @@ func init() {
 			return output.Print(body, output.Format(out.Format))
 		},
 	})
+
+	register(Command{
+		Category: "crypto", Name: "mnemonic",
+		Usage: "crypto mnemonic [generate [words] [language] | validate <mnemonic> | seed <mnemonic> [passphrase] | derive <mnemonic> <path> [passphrase]]",
+		Desc:  "Generate, validate or derive keys from a BIP39 mnemonic",
+		Run: func(c *api.Client, out *OutputOptions, args []string) error {
+			action := argAt(args, 0, "generate")
+			var path string
+			payload := map[string]any{}
+			switch action {
+			case "generate":
+				path = "/api/v1/crypto/mnemonic/generate"
+				if words := argAt(args, 1, ""); words != "" {
+					n, err := strconv.Atoi(words)
+					if err != nil {
+						return fmt.Errorf("words must be a number, got %q", words)
+					}
+					payload["words"] = n
+				}
+				if language := argAt(args, 2, ""); language != "" {
+					payload["language"] = language
+				}
+			case "validate", "seed", "derive":
+				path = "/api/v1/crypto/mnemonic/" + action
+				mnemonic, err := requireArg(args, 1, "mnemonic")
+				if err != nil {
+					return err
+				}
+				payload["mnemonic"] = mnemonic
+				passphraseAt := 2
+				if action == "derive" {
+					derivationPath, err := requireArg(args, 2, "path")
+					if err != nil {
+						return err
+					}
+					payload["path"] = derivationPath
+					passphraseAt = 3
+				}
+				if passphrase := argAt(args, passphraseAt, ""); passphrase != "" && action != "validate" {
+					payload["passphrase"] = passphrase
+				}
+			default:
+				return fmt.Errorf("unknown mnemonic action %q (expected generate, validate, seed, or derive)", action)
+			}
+			body, err := c.PostJSON(path, payload)
+			if err != nil {
+				return err
+			}
+			return output.Print(body, output.Format(out.Format))
+		},
+	})
 }
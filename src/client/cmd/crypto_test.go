@@ -115,3 +115,47 @@ func TestCryptoCommands_ConnectionErrorPropagates(t *testing.T) {
 	_, err := runCommand(t, client, "crypto", "pin", nil)
 	require.Error(t, err)
 }
+
+// TestCryptoCommands_Mnemonic verifies each `crypto mnemonic` action POSTs
+// to its endpoint with the positional arguments mapped into the JSON body,
+// and that a bare `crypto mnemonic` generates with the server defaults.
+func TestCryptoCommands_Mnemonic(t *testing.T) {
+	const phrase = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantBody string
+	}{
+		{"default generate", nil, "/api/v1/crypto/mnemonic/generate", `{}`},
+		{"generate with words and language", []string{"generate", "24", "french"}, "/api/v1/crypto/mnemonic/generate", `{"words":24,"language":"french"}`},
+		{"validate", []string{"validate", phrase}, "/api/v1/crypto/mnemonic/validate", `{"mnemonic":"` + phrase + `"}`},
+		{"seed with passphrase", []string{"seed", phrase, "TREZOR"}, "/api/v1/crypto/mnemonic/seed", `{"mnemonic":"` + phrase + `","passphrase":"TREZOR"}`},
+		{"derive", []string{"derive", phrase, "m/44'/0'/0'/0/0"}, "/api/v1/crypto/mnemonic/derive", `{"mnemonic":"` + phrase + `","path":"m/44'/0'/0'/0/0"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, rec := newRecordingServer(t, 200, `{"result":"ok"}`)
+			client := api.New(srv.URL, "")
+
+			_, err := runCommand(t, client, "crypto", "mnemonic", tc.args)
+			require.NoError(t, err)
+
+			assert.Equal(t, "POST", rec.Method)
+			assert.Equal(t, tc.wantPath, rec.Path)
+			assert.JSONEq(t, tc.wantBody, string(rec.Body))
+		})
+	}
+
+	for _, args := range [][]string{{"validate"}, {"derive", phrase}, {"generate", "twelve"}, {"explode"}} {
+		t.Run("rejects "+args[0], func(t *testing.T) {
+			srv, rec := newRecordingServer(t, 200, `{}`)
+			client := api.New(srv.URL, "")
+
+			_, err := runCommand(t, client, "crypto", "mnemonic", args)
+			require.Error(t, err)
+			assert.Empty(t, rec.Method, "server must not be called for invalid arguments")
+		})
+	}
+}
@@ -31,7 +31,7 @@ func init() {
 	register(Command{
 		Category: "text", Name: "hash",
 		Usage: "text hash <algorithm> <input>",
-		Desc:  "Hash input with the given algorithm (md5, sha1, sha256, sha512, ...)",
+		Desc:  "Hash input (md5, sha1, sha256, sha3-256, blake2b, blake3, crc32, crc64, adler32, xxhash64, murmur3, ...)",
 		Run: func(c *api.Client, out *OutputOptions, args []string) error {
 			algorithm, err := requireArg(args, 0, "algorithm")
 			if err != nil {
@@ -43,6 +43,8 @@ type ServerConfig struct {
 	Schedule       ScheduleConfig       `yaml:"schedule"`
 	TrustedProxies TrustedProxiesConfig `yaml:"trusted_proxies"`
 	RateLimit      RateLimitConfig      `yaml:"rate_limit"`
+	Batch          BatchConfig          `yaml:"batch"`
+	Stream         StreamConfig         `yaml:"stream"`
 	Database       DatabaseConfig       `yaml:"database"`
 	Cache          CacheConfig          `yaml:"cache"`
 	Healthz        HealthzConfig        `yaml:"healthz"`
@@ -203,6 +205,31 @@ type RateLimitClassConfig struct {
 	Window   int `yaml:"window"`
 }
 
+// BatchConfig holds server.batch.* settings for POST /api/v1/batch
+type BatchConfig struct {
+	// Most sub-requests a single batch may carry
+	MaxRequests int `yaml:"max_requests"`
+	// Sub-requests dispatched at the same time within one batch
+	Concurrency int `yaml:"concurrency"`
+}
+
+// StreamConfig holds server.stream.* settings for the POST tool routes
+// that stream the request body (hash, encode/decode, compress/decompress)
+// rather than reading it whole. Each tool class has its own body cap in
+// megabytes; Timeout is a Go duration string that replaces the server's
+// 30s read/write timeouts for one streaming request.
+type StreamConfig struct {
+	// Largest body the hash routes read
+	HashMaxMB int `yaml:"hash_max_mb"`
+	// Largest body the encode/decode routes read
+	EncodeMaxMB int `yaml:"encode_max_mb"`
+	// Largest body the compress/decompress routes read, and the most
+	// output a decompression may produce
+	CompressMaxMB int `yaml:"compress_max_mb"`
+	// How long one streaming request may take end to end
+	Timeout string `yaml:"timeout"`
+}
+
 // DatabaseConfig holds database/storage settings. Driver accepts the
 // friendly config aliases from AI.md PART 3 ("sqlite"/"sqlite2"/"sqlite3"
 // all normalize to sqlite; "libsql"/"turso" both normalize to libsql).
@@ -247,6 +274,22 @@ type CacheConfig struct {
 	Prefix string `yaml:"prefix"`
 	// TTL is the default entry lifetime as a duration string.
 	TTL string `yaml:"ttl"`
+	// Responses configures caching of outbound-provider lookups.
+	Responses ResponseCacheConfig `yaml:"responses"`
+}
+
+// ResponseCacheConfig holds server.cache.responses.* settings: the cache
+// in front of the free, keyless upstream providers (Open-Meteo, Nominatim,
+// dictionaryapi.dev, Datamuse, arXiv, Open Library, Frankfurter) so
+// repeated lookups don't hit them fresh. Providers maps a provider name
+// (weather, geocode, dictionary, thesaurus, arxiv, isbn, currency) to its
+// TTL as a duration string; a provider not listed uses server.cache.ttl.
+// Stale is how long past its TTL an entry may still be served while it is
+// refreshed in the background, including while the upstream is failing.
+type ResponseCacheConfig struct {
+	Enabled   bool              `yaml:"enabled"`
+	Stale     string            `yaml:"stale"`
+	Providers map[string]string `yaml:"providers"`
 }
 
 // LogsConfig holds logging settings
@@ -637,6 +680,16 @@ func defaultConfig() *Config {
 				Health:      RateLimitClassConfig{Requests: 120, Window: 60},
 				GlobalBurst: 240,
 			},
+			Batch: BatchConfig{
+				MaxRequests: 50,
+				Concurrency: 8,
+			},
+			Stream: StreamConfig{
+				HashMaxMB:     1024,
+				EncodeMaxMB:   256,
+				CompressMaxMB: 256,
+				Timeout:       "10m",
+			},
 			Database: DatabaseConfig{
 				Driver: "sqlite",
 				URL:    filepath.Join(paths.DataDir(), "db", "server.db"),
@@ -650,6 +703,19 @@ func defaultConfig() *Config {
 				Timeout:  "5s",
 				Prefix:   "api:",
 				TTL:      "1h",
+				Responses: ResponseCacheConfig{
+					Enabled: true,
+					Stale:   "24h",
+					Providers: map[string]string{
+						"weather":    "10m",
+						"geocode":    "168h",
+						"dictionary": "168h",
+						"thesaurus":  "168h",
+						"arxiv":      "24h",
+						"isbn":       "168h",
+						"currency":   "1h",
+					},
+				},
 			},
 			Healthz: HealthzConfig{
 				Root: HealthzRootConfig{
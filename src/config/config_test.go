@@ -55,7 +55,15 @@ func TestDefaultConfig(t *testing.T) {
 	assert.False(t, cfg.Server.SSL.Enabled)
 	assert.True(t, cfg.Server.Schedule.Enabled)
 	assert.True(t, cfg.Server.RateLimit.Enabled)
+	assert.Equal(t, 50, cfg.Server.Batch.MaxRequests)
+	assert.Equal(t, 8, cfg.Server.Batch.Concurrency)
+	assert.Equal(t, 1024, cfg.Server.Stream.HashMaxMB)
+	assert.Equal(t, 256, cfg.Server.Stream.EncodeMaxMB)
+	assert.Equal(t, 256, cfg.Server.Stream.CompressMaxMB)
+	assert.Equal(t, "10m", cfg.Server.Stream.Timeout)
 	assert.Equal(t, "sqlite", cfg.Server.Database.Driver)
+	assert.True(t, cfg.Server.Cache.Responses.Enabled)
+	assert.Equal(t, "10m", cfg.Server.Cache.Responses.Providers["weather"])
 	assert.Equal(t, "dark", cfg.Web.UI.Theme)
 	assert.Equal(t, []string{"*"}, cfg.Web.CORS.AllowedOrigins)
 	assert.NotEmpty(t, cfg.Server.FQDN)
@@ -0,0 +1,826 @@
+// Package cron parses cron expressions and computes their run times. It
+// is the one engine behind both the background scheduler and the
+// /api/v1/datetime/cron endpoint.
+//
+// Accepted syntax:
+//
+//	[second] minute hour day-of-month month day-of-week [year]
+//
+// Five fields is classic cron; six adds a leading seconds field and seven
+// a trailing year (1970-2099), as in Quartz. Fields take "*", values,
+// "a-b" ranges, "*/n", "a/n" and "a-b/n" steps, and comma lists of those.
+// Months and weekdays also take names (JAN-DEC, SUN-SAT). Weekdays use
+// classic cron numbering, 0-7 with both 0 and 7 for Sunday, not
+// Quartz's 1-7. Quartz's modifiers are supported as well:
+//
+//	day-of-month: L (last day), L-n (n days before it), LW (last
+//	              weekday), nW (weekday nearest day n), ? (no value)
+//	day-of-week:  nL (last weekday n of the month), n#k (k-th weekday n
+//	              of the month), ? (no value)
+//
+// The macros @yearly (@annually), @monthly, @weekly, @daily (@midnight)
+// and @hourly stand for their usual expressions, and a "CRON_TZ=<zone> "
+// prefix overrides the time zone the schedule is evaluated in.
+//
+// As in POSIX cron, when both day fields are restricted (neither "*" nor
+// "?") a day matches if either does.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far Next and Prev look for a matching time, so
+// a schedule that can never fire (e.g. Feb 30) returns rather than loops.
+const searchYears = 100
+
+// maxSegments bounds the constant-offset zone segments Next and Prev walk
+// through; even zones with several transitions a year stay well inside it
+// over searchYears.
+const maxSegments = searchYears * 12
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+var dayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// bounds describes one field's name and accepted range. names maps
+// three-letter abbreviations to values for month and day-of-week.
+type bounds struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	secondBounds = bounds{name: "second", min: 0, max: 59}
+	minuteBounds = bounds{name: "minute", min: 0, max: 59}
+	hourBounds   = bounds{name: "hour", min: 0, max: 23}
+	domBounds    = bounds{name: "day-of-month", min: 1, max: 31}
+	monthBounds  = bounds{name: "month", min: 1, max: 12, names: monthNames}
+	dowBounds    = bounds{name: "day-of-week", min: 0, max: 7, names: dayNames}
+	yearBounds   = bounds{name: "year", min: 1970, max: 2099}
+)
+
+// Modifiers a part can carry; see the package comment.
+const (
+	modNone        = iota
+	modLast        // day-of-month L-n (n in part.n), day-of-week nL
+	modWeekday     // day-of-month nW
+	modLastWeekday // day-of-month LW
+	modNth         // day-of-week n#k (k in part.n)
+)
+
+// part is one comma-separated element of a field.
+type part struct {
+	lo, hi, step int
+	star         bool
+	mod          int
+	n            int
+}
+
+// field is a parsed cron field. bits holds every value matched by plain
+// parts; modifier parts depend on the month and are kept in special.
+type field struct {
+	raw     string
+	star    bool
+	parts   []part
+	special []part
+	bits    uint64
+}
+
+func (f *field) has(v int) bool {
+	return f.bits&(1<<uint(v)) != 0
+}
+
+// hasYear is has for the year field, whose values do not fit in bits.
+func (f *field) hasYear(y int) bool {
+	for _, p := range f.parts {
+		if y >= p.lo && y <= p.hi && (y-p.lo)%p.step == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule is a parsed cron expression bound to a time zone.
+type Schedule struct {
+	second, minute, hour, dom, month, dow, year *field
+	hasSeconds, hasYear                         bool
+	loc                                         *time.Location
+}
+
+// Parse parses a cron expression, evaluated in loc (UTC when nil) unless
+// the expression carries a CRON_TZ prefix.
+func Parse(expr string, loc *time.Location) (*Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	expr = strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(expr, "CRON_TZ="); ok {
+		name, spec, _ := strings.Cut(rest, " ")
+		l, err := time.LoadLocation(name)
+		if err != nil || name == "" {
+			return nil, fmt.Errorf("unknown time zone %q", name)
+		}
+		loc, expr = l, strings.TrimSpace(spec)
+	}
+	if strings.HasPrefix(expr, "@") {
+		m, ok := macros[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown macro %q (expected @yearly, @annually, @monthly, @weekly, @daily, @midnight or @hourly)", expr)
+		}
+		expr = m
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) < 5 || len(fields) > 7 {
+		return nil, fmt.Errorf("cron expression %q must have 5, 6 or 7 fields ([second] minute hour day-of-month month day-of-week [year]), got %d", expr, len(fields))
+	}
+	s := &Schedule{loc: loc, hasSeconds: len(fields) >= 6, hasYear: len(fields) == 7}
+	if !s.hasSeconds {
+		fields = append([]string{"0"}, fields...)
+	}
+	if !s.hasYear {
+		fields = append(fields, "*")
+	}
+
+	targets := []struct {
+		dst *(*field)
+		b   bounds
+	}{
+		{&s.second, secondBounds}, {&s.minute, minuteBounds}, {&s.hour, hourBounds},
+		{&s.dom, domBounds}, {&s.month, monthBounds}, {&s.dow, dowBounds}, {&s.year, yearBounds},
+	}
+	for i, t := range targets {
+		f, err := parseField(fields[i], t.b)
+		if err != nil {
+			return nil, fmt.Errorf("%s field: %w", t.b.name, err)
+		}
+		*t.dst = f
+	}
+	// Cron allows both 0 and 7 for Sunday - normalize 7 to 0.
+	if s.dow.has(7) {
+		s.dow.bits = s.dow.bits&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// parseField parses one field: a comma list of "*", "?", values, ranges
+// and steps, plus the L/W/# modifiers in the day fields.
+func parseField(raw string, b bounds) (*field, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("empty field")
+	}
+	f := &field{raw: raw, star: raw == "*" || raw == "?"}
+	if raw == "?" {
+		if b.name != domBounds.name && b.name != dowBounds.name {
+			return nil, fmt.Errorf("? is only allowed in the day-of-month and day-of-week fields")
+		}
+		raw = "*"
+	}
+	for _, s := range strings.Split(raw, ",") {
+		p, err := parsePart(strings.ToUpper(s), b)
+		if err != nil {
+			return nil, err
+		}
+		f.parts = append(f.parts, p)
+		if p.mod != modNone {
+			f.special = append(f.special, p)
+			continue
+		}
+		if b.name == yearBounds.name {
+			continue
+		}
+		for v := p.lo; v <= p.hi; v += p.step {
+			f.bits |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+func parsePart(s string, b bounds) (part, error) {
+	if p, ok, err := parseModifier(s, b); ok || err != nil {
+		return p, err
+	}
+
+	p := part{lo: b.min, hi: b.max, step: 1}
+	rangePart, stepPart, hasStep := strings.Cut(s, "/")
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return part{}, fmt.Errorf("invalid step in %q", s)
+		}
+		p.step = n
+	}
+
+	switch {
+	case rangePart == "*":
+		p.star = true
+	case strings.Contains(rangePart, "-"):
+		lo, hi, _ := strings.Cut(rangePart, "-")
+		var err error
+		if p.lo, err = b.value(lo); err != nil {
+			return part{}, fmt.Errorf("invalid range start in %q", s)
+		}
+		if p.hi, err = b.value(hi); err != nil {
+			return part{}, fmt.Errorf("invalid range end in %q", s)
+		}
+	default:
+		v, err := b.value(rangePart)
+		if err != nil {
+			return part{}, fmt.Errorf("invalid value %q", s)
+		}
+		// "a/n" starts at a and runs to the end of the range.
+		p.lo, p.hi = v, v
+		if hasStep {
+			p.hi = b.max
+		}
+	}
+
+	if p.lo < b.min || p.hi > b.max || p.lo > p.hi {
+		return part{}, fmt.Errorf("value %q out of range [%d-%d]", s, b.min, b.max)
+	}
+	return p, nil
+}
+
+// parseModifier parses the day fields' L, W and # forms. ok is false for
+// anything else.
+func parseModifier(s string, b bounds) (p part, ok bool, err error) {
+	switch b.name {
+	case domBounds.name:
+		switch {
+		case s == "L":
+			return part{mod: modLast}, true, nil
+		case s == "LW":
+			return part{mod: modLastWeekday}, true, nil
+		case strings.HasPrefix(s, "L-"):
+			n, err := strconv.Atoi(s[2:])
+			if err != nil || n < 0 || n > 30 {
+				return part{}, true, fmt.Errorf("invalid offset in %q (expected L-0 to L-30)", s)
+			}
+			return part{mod: modLast, n: n}, true, nil
+		case strings.HasSuffix(s, "W"):
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err != nil || n < b.min || n > b.max {
+				return part{}, true, fmt.Errorf("invalid day in %q (expected 1W to 31W)", s)
+			}
+			return part{lo: n, hi: n, mod: modWeekday}, true, nil
+		}
+	case dowBounds.name:
+		if day, k, found := strings.Cut(s, "#"); found {
+			v, err := b.value(day)
+			if err != nil || v < b.min || v > b.max {
+				return part{}, true, fmt.Errorf("invalid weekday in %q", s)
+			}
+			n, err := strconv.Atoi(k)
+			if err != nil || n < 1 || n > 5 {
+				return part{}, true, fmt.Errorf("invalid occurrence in %q (expected #1 to #5)", s)
+			}
+			return part{lo: v % 7, hi: v % 7, mod: modNth, n: n}, true, nil
+		}
+		if day, found := strings.CutSuffix(s, "L"); found {
+			v, err := b.value(day)
+			if err != nil || v < b.min || v > b.max {
+				return part{}, true, fmt.Errorf("invalid weekday in %q", s)
+			}
+			return part{lo: v % 7, hi: v % 7, mod: modLast}, true, nil
+		}
+	}
+	return part{}, false, nil
+}
+
+// value parses a number or, for month and day-of-week, a three-letter
+// name.
+func (b bounds) value(s string) (int, error) {
+	for i, name := range b.names {
+		if len(name) >= 3 && s == strings.ToUpper(name[:3]) {
+			return i, nil
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+// Location returns the time zone the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// String returns the expression in its expanded form: macros replaced,
+// and the seconds and year fields only when they were given.
+func (s *Schedule) String() string {
+	fields := []string{s.minute.raw, s.hour.raw, s.dom.raw, s.month.raw, s.dow.raw}
+	if s.hasSeconds {
+		fields = append([]string{s.second.raw}, fields...)
+	}
+	if s.hasYear {
+		fields = append(fields, s.year.raw)
+	}
+	return strings.Join(fields, " ")
+}
+
+// Fields returns each field of the expanded expression by name. second
+// and year are only present when the expression had them.
+func (s *Schedule) Fields() map[string]string {
+	out := map[string]string{
+		"minute":       s.minute.raw,
+		"hour":         s.hour.raw,
+		"day_of_month": s.dom.raw,
+		"month":        s.month.raw,
+		"day_of_week":  s.dow.raw,
+	}
+	if s.hasSeconds {
+		out["second"] = s.second.raw
+	}
+	if s.hasYear {
+		out["year"] = s.year.raw
+	}
+	return out
+}
+
+// matches reports whether t's wall clock in the schedule's zone matches
+// every field.
+func (s *Schedule) matches(t time.Time) bool {
+	t = t.In(s.loc)
+	return s.year.hasYear(t.Year()) && s.month.has(int(t.Month())) &&
+		s.dayMatches(t.Year(), t.Month(), t.Day()) &&
+		s.hour.has(t.Hour()) && s.minute.has(t.Minute()) && s.second.has(t.Second())
+}
+
+func (s *Schedule) dayMatches(y int, m time.Month, d int) bool {
+	domOK := s.dom.star || s.domMatches(y, m, d)
+	dowOK := s.dow.star || s.dowMatches(y, m, d)
+	if s.dom.star || s.dow.star {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+func (s *Schedule) domMatches(y int, m time.Month, d int) bool {
+	if s.dom.has(d) {
+		return true
+	}
+	last := daysIn(y, m)
+	for _, p := range s.dom.special {
+		switch p.mod {
+		case modLast:
+			if d == last-p.n {
+				return true
+			}
+		case modWeekday:
+			if p.lo <= last && d == nearestWeekday(y, m, p.lo) {
+				return true
+			}
+		case modLastWeekday:
+			if d == nearestWeekday(y, m, last) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Schedule) dowMatches(y int, m time.Month, d int) bool {
+	wd := int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Weekday())
+	if s.dow.has(wd) {
+		return true
+	}
+	for _, p := range s.dow.special {
+		if p.lo != wd {
+			continue
+		}
+		if p.mod == modLast && d+7 > daysIn(y, m) || p.mod == modNth && (d-1)/7+1 == p.n {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday returns the Monday-Friday day closest to day d without
+// leaving the month, as Quartz's W modifier does.
+func nearestWeekday(y int, m time.Month, d int) int {
+	switch time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if d == 1 {
+			return 3
+		}
+		return d - 1
+	case time.Sunday:
+		if d == daysIn(y, m) {
+			return d - 2
+		}
+		return d + 1
+	}
+	return d
+}
+
+// wallMatches is matches for a wall-clock time held in UTC.
+func (s *Schedule) wallMatches(w time.Time) bool {
+	return s.year.hasYear(w.Year()) && s.month.has(int(w.Month())) &&
+		s.dayMatches(w.Year(), w.Month(), w.Day()) &&
+		s.hour.has(w.Hour()) && s.minute.has(w.Minute()) && s.second.has(w.Second())
+}
+
+// nextWall returns the first matching wall-clock time at or after w, both
+// held in UTC so no zone transitions interfere, skipping whole years,
+// months, days, hours and minutes that cannot match.
+func (s *Schedule) nextWall(w time.Time, lastYear int) (time.Time, bool) {
+	for w.Year() <= lastYear {
+		y, m, d := w.Date()
+		switch {
+		case !s.year.hasYear(y):
+			w = time.Date(y+1, 1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.month.has(int(m)):
+			w = time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(y, m, d):
+			w = time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		case !s.hour.has(w.Hour()):
+			w = time.Date(y, m, d, w.Hour()+1, 0, 0, 0, time.UTC)
+		case !s.minute.has(w.Minute()):
+			w = time.Date(y, m, d, w.Hour(), w.Minute()+1, 0, 0, time.UTC)
+		case !s.second.has(w.Second()):
+			w = w.Add(time.Second)
+		default:
+			return w, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// prevWall is nextWall in reverse: the last matching wall-clock time at
+// or before w.
+func (s *Schedule) prevWall(w time.Time, firstYear int) (time.Time, bool) {
+	for w.Year() >= firstYear {
+		y, m, d := w.Date()
+		switch {
+		case !s.year.hasYear(y):
+			w = time.Date(y, 1, 1, 0, 0, -1, 0, time.UTC)
+		case !s.month.has(int(m)):
+			w = time.Date(y, m, 1, 0, 0, -1, 0, time.UTC)
+		case !s.dayMatches(y, m, d):
+			w = time.Date(y, m, d, 0, 0, -1, 0, time.UTC)
+		case !s.hour.has(w.Hour()):
+			w = time.Date(y, m, d, w.Hour(), 0, -1, 0, time.UTC)
+		case !s.minute.has(w.Minute()):
+			w = time.Date(y, m, d, w.Hour(), w.Minute(), -1, 0, time.UTC)
+		case !s.second.has(w.Second()):
+			w = w.Add(-time.Second)
+		default:
+			return w, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// wallAt returns instant t's wall clock at the given UTC offset, held in
+// UTC.
+func wallAt(t time.Time, offset int) time.Time {
+	return time.Unix(t.Unix()+int64(offset), 0).UTC()
+}
+
+// instantAt is the inverse of wallAt.
+func instantAt(w time.Time, offset int) time.Time {
+	return time.Unix(w.Unix()-int64(offset), 0)
+}
+
+// everyHour reports whether the hour field matches all 24 hours. Such a
+// schedule fires on both passes through a repeated hour, since it runs
+// by the clock hand rather than at a fixed time of day.
+func (s *Schedule) everyHour() bool {
+	return s.hour.bits == 1<<24-1
+}
+
+// Next returns the first run strictly after t, in the schedule's zone,
+// or the zero time when there is none within a century.
+//
+// Runs are computed on the zone's wall clock one constant-offset segment
+// (between two transitions) at a time. A run whose wall time is skipped
+// by a spring-forward transition fires at the transition instant; a run
+// whose wall time repeats after a fall-back transition fires only the
+// first time, unless the hour field is "*".
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Second).Add(time.Second)
+	lastYear := t.Year() + searchYears
+	for range maxSegments {
+		start, end := ZoneBounds(t, s.loc)
+		_, offset := t.In(s.loc).Zone()
+		w := wallAt(t, offset)
+		if !start.IsZero() && !s.everyHour() {
+			_, prevOffset := start.Add(-time.Second).In(s.loc).Zone()
+			if repeatEnd := wallAt(start, prevOffset); w.Before(repeatEnd) {
+				w = repeatEnd
+			}
+		}
+
+		c, ok := s.nextWall(w, lastYear)
+		if !ok {
+			return time.Time{}
+		}
+		if run := instantAt(c, offset); end.IsZero() || run.Before(end) {
+			return run.In(s.loc)
+		}
+		// The match lies past this segment; if it falls in the gap a
+		// spring-forward transition skips, it fires at the transition.
+		if _, nextOffset := end.In(s.loc).Zone(); nextOffset > offset && c.Before(wallAt(end, nextOffset)) {
+			return end.In(s.loc)
+		}
+		t = end
+	}
+	return time.Time{}
+}
+
+// Prev returns the last run strictly before t, in the schedule's zone,
+// or the zero time when there is none within a century. It follows the
+// same transition rules as Next.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	if tt := t.Truncate(time.Second); tt.Before(t) {
+		t = tt
+	} else {
+		t = tt.Add(-time.Second)
+	}
+	firstYear := t.Year() - searchYears
+	for range maxSegments {
+		start, _ := ZoneBounds(t, s.loc)
+		_, offset := t.In(s.loc).Zone()
+
+		c, ok := s.prevWall(wallAt(t, offset), firstYear)
+		if !ok {
+			return time.Time{}
+		}
+		if start.IsZero() {
+			return instantAt(c, offset).In(s.loc)
+		}
+		_, prevOffset := start.Add(-time.Second).In(s.loc).Zone()
+		if !c.Before(wallAt(start, offset)) {
+			// A repeated wall time already fired in the previous segment.
+			if s.everyHour() || !c.Before(wallAt(start, prevOffset)) {
+				return instantAt(c, offset).In(s.loc)
+			}
+		} else if prevOffset < offset && !c.Before(wallAt(start, prevOffset)) {
+			return start.In(s.loc)
+		}
+		t = start.Add(-time.Second)
+	}
+	return time.Time{}
+}
+
+// ZoneBounds is time.Time.ZoneBounds for t in loc, checked to contain t.
+// Past the zone's listed transitions (the end of 2040 for most zones) Go
+// can report a segment that ends at or before t, which would stop a walk
+// from segment to segment; such a bound is found by probing offsets
+// instead.
+func ZoneBounds(t time.Time, loc *time.Location) (start, end time.Time) {
+	start, end = t.In(loc).ZoneBounds()
+	if !end.IsZero() && !end.After(t) {
+		end = probeTransition(t, loc, 1)
+	}
+	if start.After(t) {
+		start = probeTransition(t, loc, -1)
+	}
+	return start, end
+}
+
+// probeTransition finds the edge of the constant-offset segment holding
+// t: the first second after it when dir is 1, or its first second when
+// dir is -1. It steps a day at a time for up to a year, then bisects to
+// the second, and returns the zero time when the offset never changes.
+func probeTransition(t time.Time, loc *time.Location, dir int) time.Time {
+	offsetAt := func(x time.Time) int {
+		_, offset := x.In(loc).Zone()
+		return offset
+	}
+	offset := offsetAt(t)
+	same, other := t, time.Time{}
+	for range 366 {
+		next := same.AddDate(0, 0, dir)
+		if offsetAt(next) != offset {
+			other = next
+			break
+		}
+		same = next
+	}
+	if other.IsZero() {
+		return time.Time{}
+	}
+	for other.Sub(same).Abs() > time.Second {
+		mid := same.Add(other.Sub(same) / 2).Truncate(time.Second)
+		if mid.Equal(same) {
+			break
+		}
+		if offsetAt(mid) == offset {
+			same = mid
+		} else {
+			other = mid
+		}
+	}
+	if dir > 0 {
+		return other
+	}
+	return same
+}
+
+// Between returns up to limit runs after start and no later than end.
+func (s *Schedule) Between(start, end time.Time, limit int) []time.Time {
+	var runs []time.Time
+	for t := s.Next(start); !t.IsZero() && !t.After(end) && len(runs) < limit; t = s.Next(t) {
+		runs = append(runs, t)
+	}
+	return runs
+}
+
+// Describe renders the schedule in plain English, for example "At 09:00,
+// Monday through Friday" or "Every 15 minutes, on the last Friday of the
+// month".
+func (s *Schedule) Describe() string {
+	phrases := []string{s.describeTime()}
+	if d := s.describeDays(); d != "" {
+		phrases = append(phrases, d)
+	}
+	if !s.month.star {
+		phrases = append(phrases, s.month.describe("month", "in", monthName))
+	}
+	if !s.year.star {
+		phrases = append(phrases, s.year.describe("year", "in", strconv.Itoa))
+	}
+	out := strings.Join(phrases, ", ")
+	return strings.ToUpper(out[:1]) + out[1:]
+}
+
+func monthName(v int) string { return monthNames[v] }
+
+func dayName(v int) string { return dayNames[v] }
+
+// single returns the field's value when it is exactly one plain value.
+func (f *field) single() (int, bool) {
+	if len(f.parts) == 1 && !f.parts[0].star && f.parts[0].mod == modNone && f.parts[0].lo == f.parts[0].hi {
+		return f.parts[0].lo, true
+	}
+	return 0, false
+}
+
+// stepped reports whether the field is "*" or "*/n".
+func (f *field) stepped() bool {
+	return len(f.parts) == 1 && f.parts[0].star
+}
+
+func (s *Schedule) describeTime() string {
+	sec, secFixed := s.second.single()
+	min, minFixed := s.minute.single()
+
+	// A fixed time of day: "at 09:00 and 17:00".
+	if secFixed && minFixed && !s.hour.star && len(s.hour.special) == 0 && s.hour.bits != 0 {
+		var times []string
+		allValues := true
+		for _, p := range s.hour.parts {
+			allValues = allValues && p.lo == p.hi
+		}
+		if allValues && len(s.hour.parts) <= 6 {
+			for _, p := range s.hour.parts {
+				t := fmt.Sprintf("%02d:%02d", p.lo, min)
+				if sec != 0 {
+					t += fmt.Sprintf(":%02d", sec)
+				}
+				times = append(times, t)
+			}
+			return "at " + joinAnd(times)
+		}
+	}
+
+	var phrases []string
+	if !secFixed || sec != 0 {
+		phrases = append(phrases, s.second.describe("second", "at", strconv.Itoa))
+	}
+	switch {
+	case minFixed && secFixed && s.hour.star && min == 0:
+		phrases = append(phrases, "every hour")
+	case minFixed && secFixed && s.hour.star:
+		phrases = append(phrases, fmt.Sprintf("at %s past the hour", plural(min, "minute")))
+	case s.minute.star && !secFixed:
+		// "every second" already says it.
+	default:
+		phrases = append(phrases, s.minute.describe("minute", "at", strconv.Itoa))
+	}
+	if !s.hour.star {
+		phrases = append(phrases, s.hour.describe("hour", "during", strconv.Itoa))
+	}
+	return strings.Join(phrases, ", ")
+}
+
+func (s *Schedule) describeDays() string {
+	var dom, dow []string
+	if !s.dom.star {
+		for _, p := range s.dom.special {
+			switch {
+			case p.mod == modLast && p.n == 0:
+				dom = append(dom, "on the last day of the month")
+			case p.mod == modLast:
+				dom = append(dom, fmt.Sprintf("%d days before the last day of the month", p.n))
+			case p.mod == modWeekday:
+				dom = append(dom, fmt.Sprintf("on the weekday nearest day %d of the month", p.lo))
+			case p.mod == modLastWeekday:
+				dom = append(dom, "on the last weekday of the month")
+			}
+		}
+		if len(s.dom.special) < len(s.dom.parts) {
+			dom = append(dom, s.dom.describe("day", "on", strconv.Itoa)+" of the month")
+		}
+	}
+	if !s.dow.star {
+		ordinals := []string{"", "first", "second", "third", "fourth", "fifth"}
+		for _, p := range s.dow.special {
+			switch p.mod {
+			case modLast:
+				dow = append(dow, fmt.Sprintf("on the last %s of the month", dayNames[p.lo]))
+			case modNth:
+				dow = append(dow, fmt.Sprintf("on the %s %s of the month", ordinals[p.n], dayNames[p.lo]))
+			}
+		}
+		if len(s.dow.special) < len(s.dow.parts) {
+			dow = append(dow, s.dow.describe("day of the week", "on", dayName))
+		}
+	}
+	days := strings.Join(dom, " and ")
+	if len(dow) > 0 {
+		if days != "" {
+			days += " or "
+		}
+		days += strings.Join(dow, " and ")
+	}
+	return days
+}
+
+// describe renders a field's plain parts: values as "at minutes 0 and 30"
+// (prefix, unit, list), ranges as "Monday through Friday" and steps as
+// "every 15 minutes". Day-of-week values drop the unit ("on Monday").
+func (f *field) describe(unit, prefix string, name func(int) string) string {
+	var values, others []string
+	for _, p := range f.parts {
+		switch {
+		case p.mod != modNone:
+		case p.star && p.step == 1:
+			others = append(others, "every "+unit)
+		case p.star:
+			others = append(others, fmt.Sprintf("every %d %ss", p.step, unit))
+		case p.lo == p.hi:
+			values = append(values, name(p.lo))
+		default:
+			r := name(p.lo) + " through " + name(p.hi)
+			switch unit {
+			case "hour":
+				r = fmt.Sprintf("between %02d:00 and %02d:59", p.lo, p.hi)
+			case "second", "minute", "day":
+				r = unit + "s " + r
+			}
+			if p.step > 1 {
+				r = fmt.Sprintf("every %d %ss, %s", p.step, unit, r)
+			}
+			others = append(others, r)
+		}
+	}
+	var phrases []string
+	if len(values) > 0 {
+		label := unit
+		if len(values) > 1 {
+			label += "s"
+		}
+		switch unit {
+		case "day of the week", "month", "year":
+			phrases = append(phrases, prefix+" "+joinAnd(values))
+		default:
+			phrases = append(phrases, prefix+" "+label+" "+joinAnd(values))
+		}
+	}
+	return strings.Join(append(phrases, others...), " and ")
+}
+
+// plural renders a count with its unit, pluralized when needed.
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// joinAnd joins items as "a", "a and b" or "a, b and c".
+func joinAnd(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
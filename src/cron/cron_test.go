@@ -0,0 +1,383 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// values lists the plain values a field matches, for table comparisons.
+func values(f *field) []int {
+	var out []int
+	for v := 0; v < 64; v++ {
+		if f.has(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// TestParseField covers the single-value, wildcard, range, step, name and
+// comma-list forms accepted by one cron field, plus the error paths for
+// out-of-range and malformed input.
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		b       bounds
+		want    []int
+		wantErr bool
+	}{
+		{"wildcard", "*", hourBounds, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}, false},
+		{"single value", "5", minuteBounds, []int{5}, false},
+		{"range", "1-3", minuteBounds, []int{1, 2, 3}, false},
+		{"step wildcard", "*/15", minuteBounds, []int{0, 15, 30, 45}, false},
+		{"step range", "0-10/5", minuteBounds, []int{0, 5, 10}, false},
+		{"step from value", "50/4", secondBounds, []int{50, 54, 58}, false},
+		{"comma list", "1,3,5", minuteBounds, []int{1, 3, 5}, false},
+		{"mixed list", "1,5-7,*/20", minuteBounds, []int{0, 1, 5, 6, 7, 20, 40}, false},
+		{"month names", "jan,MAR-apr", monthBounds, []int{1, 3, 4}, false},
+		{"weekday names", "MON-FRI", dowBounds, []int{1, 2, 3, 4, 5}, false},
+		{"question mark in day field", "?", domBounds, values(&field{bits: 1<<32 - 2}), false},
+		{"boundary min", "0", minuteBounds, []int{0}, false},
+		{"boundary max", "59", minuteBounds, []int{59}, false},
+		{"empty field", "", minuteBounds, nil, true},
+		{"out of range low", "-1", minuteBounds, nil, true},
+		{"out of range high", "60", minuteBounds, nil, true},
+		{"reversed range", "10-5", minuteBounds, nil, true},
+		{"invalid step", "*/0", minuteBounds, nil, true},
+		{"invalid step negative", "*/-5", minuteBounds, nil, true},
+		{"non-numeric", "abc", minuteBounds, nil, true},
+		{"non-numeric range start", "a-5", minuteBounds, nil, true},
+		{"non-numeric range end", "5-a", minuteBounds, nil, true},
+		{"question mark outside day fields", "?", hourBounds, nil, true},
+		{"unknown month name", "FOO", monthBounds, nil, true},
+		{"L offset too large", "L-31", domBounds, nil, true},
+		{"W on day 32", "32W", domBounds, nil, true},
+		{"nth weekday occurrence 6", "1#6", dowBounds, nil, true},
+		{"last weekday bad day", "9L", dowBounds, nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseField(tc.field, tc.b)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, values(got))
+		})
+	}
+}
+
+// TestParse covers field counts, per-field error messages, macros, the
+// "7 == Sunday == 0" weekday alias and the CRON_TZ prefix.
+func TestParse(t *testing.T) {
+	t.Run("five fields default seconds to zero", func(t *testing.T) {
+		s, err := Parse("30 4 1 6 2", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0}, values(s.second))
+		assert.Equal(t, []int{30}, values(s.minute))
+		assert.Equal(t, time.UTC, s.Location())
+		assert.Equal(t, "30 4 1 6 2", s.String())
+		assert.NotContains(t, s.Fields(), "second")
+	})
+
+	t.Run("six and seven fields", func(t *testing.T) {
+		s, err := Parse("15 30 4 * * ?", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []int{15}, values(s.second))
+		assert.Equal(t, "15", s.Fields()["second"])
+
+		s, err = Parse("0 0 12 1 1 * 2027-2029", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "2027-2029", s.Fields()["year"])
+		assert.True(t, s.year.hasYear(2028))
+		assert.False(t, s.year.hasYear(2030))
+	})
+
+	t.Run("weekday 7 normalizes to 0 (Sunday)", func(t *testing.T) {
+		s, err := Parse("0 0 * * 7", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0}, values(s.dow))
+	})
+
+	t.Run("macros expand", func(t *testing.T) {
+		for macro, want := range map[string]string{
+			"@yearly": "0 0 1 1 *", "@annually": "0 0 1 1 *", "@monthly": "0 0 1 * *",
+			"@weekly": "0 0 * * 0", "@daily": "0 0 * * *", "@midnight": "0 0 * * *", "@hourly": "0 * * * *",
+		} {
+			s, err := Parse(macro, nil)
+			require.NoError(t, err, macro)
+			assert.Equal(t, want, s.String(), macro)
+		}
+		_, err := Parse("@fortnightly", nil)
+		assert.ErrorContains(t, err, "unknown macro")
+	})
+
+	t.Run("CRON_TZ prefix overrides the zone", func(t *testing.T) {
+		s, err := Parse("CRON_TZ=Asia/Tokyo 0 9 * * *", time.UTC)
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", s.Location().String())
+
+		_, err = Parse("CRON_TZ=Not/AZone 0 9 * * *", nil)
+		assert.ErrorContains(t, err, "unknown time zone")
+	})
+
+	t.Run("wrong field count", func(t *testing.T) {
+		_, err := Parse("0 0 * *", nil)
+		assert.Error(t, err)
+		_, err = Parse("0 0 0 * * * * *", nil)
+		assert.Error(t, err)
+		_, err = Parse("", nil)
+		assert.Error(t, err)
+	})
+
+	for _, tc := range []struct{ expr, want string }{
+		{"60 0 0 * * *", "second field"},
+		{"60 0 * * *", "minute field"},
+		{"0 24 * * *", "hour field"},
+		{"0 0 32 * *", "day-of-month field"},
+		{"0 0 0 * *", "day-of-month field"},
+		{"0 0 * 13 *", "month field"},
+		{"0 0 * * 8", "day-of-week field"},
+		{"0 0 0 * * * 2100", "year field"},
+	} {
+		t.Run("bad "+tc.want, func(t *testing.T) {
+			_, err := Parse(tc.expr, nil)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+// TestScheduleMatches drives the POSIX "day-of-month OR day-of-week when
+// both are restricted" rule directly, since it is easy to get backwards.
+func TestScheduleMatches(t *testing.T) {
+	at := func(d int) time.Time { return time.Date(2026, 7, d, 0, 0, 0, 0, time.UTC) }
+
+	// Midnight, either the 1st of the month OR a Monday.
+	s, err := Parse("0 0 1 * 1", nil)
+	require.NoError(t, err)
+	assert.True(t, s.matches(at(1)), "Wednesday the 1st, via day-of-month")
+	assert.True(t, s.matches(at(6)), "Monday, via day-of-week")
+	assert.False(t, s.matches(at(7)), "Tuesday the 7th")
+	assert.False(t, s.matches(at(1).Add(time.Hour)))
+	assert.False(t, s.matches(at(1).Add(time.Minute)))
+	assert.False(t, s.matches(at(1).Add(time.Second)))
+
+	// "?" counts as unrestricted, so only the weekday applies.
+	s, err = Parse("0 0 ? * 1", nil)
+	require.NoError(t, err)
+	assert.True(t, s.matches(at(6)))
+	assert.False(t, s.matches(at(1)))
+
+	// Only day-of-month restricted.
+	s, err = Parse("0 0 15 * *", nil)
+	require.NoError(t, err)
+	assert.True(t, s.matches(at(15)))
+	assert.False(t, s.matches(at(16)))
+}
+
+// TestScheduleModifiers covers L, L-n, LW, nW, nL and n#k against known
+// calendar dates.
+func TestScheduleModifiers(t *testing.T) {
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"0 0 L * *", time.Date(2028, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 L-2 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 28, 0, 0, 0, 0, time.UTC)},
+		// 2026-05-31 is a Sunday, so the last weekday is Friday the 29th.
+		{"0 0 LW * *", time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 5, 29, 0, 0, 0, 0, time.UTC)},
+		// 2026-03-15 is a Sunday: the nearest weekday is Monday the 16th.
+		{"0 0 15W * *", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)},
+		// 2026-08-01 is a Saturday: 1W moves forward to Monday the 3rd
+		// rather than back into July.
+		{"0 0 1W * *", time.Date(2026, 7, 31, 12, 0, 0, 0, time.UTC), time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 5L", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * FRI#3", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 17, 0, 0, 0, 0, time.UTC)},
+		// A fifth Monday only exists in some months.
+		{"0 0 * * 1#5", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := Parse(tc.expr, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, s.Next(tc.from).UTC())
+		})
+	}
+}
+
+// TestScheduleNext exercises next-run computation: strictly-after
+// semantics, truncation to whole seconds, multi-field alignment and
+// unsatisfiable expressions.
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"next minute wildcard", "* * * * *", time.Date(2026, 7, 20, 10, 17, 33, 500, time.UTC), time.Date(2026, 7, 20, 10, 18, 0, 0, time.UTC)},
+		{"strictly after from, even on exact match", "0 * * * *", time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC), time.Date(2026, 7, 20, 11, 0, 0, 0, time.UTC)},
+		{"daily rolls to next day", "0 0 * * *", time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC), time.Date(2026, 7, 21, 0, 0, 0, 0, time.UTC)},
+		{"monthly rolls across year boundary", "0 0 1 * *", time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// 2026-07-20 is a Monday; the next Sunday is the 26th.
+		{"specific weekday and hour", "0 3 * * 0", time.Date(2026, 7, 20, 12, 0, 0, 0, time.UTC), time.Date(2026, 7, 26, 3, 0, 0, 0, time.UTC)},
+		{"seconds field", "*/20 * * * * *", time.Date(2026, 7, 20, 10, 0, 41, 0, time.UTC), time.Date(2026, 7, 20, 10, 1, 0, 0, time.UTC)},
+		{"year field", "0 0 0 1 1 * 2030", time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC), time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"Feb 30 never happens", "0 0 30 2 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"year already past", "0 0 0 1 1 * 2020", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := Parse(tc.expr, nil)
+			require.NoError(t, err)
+			got := s.Next(tc.from)
+			if tc.want.IsZero() {
+				assert.True(t, got.IsZero(), "got %v", got)
+				return
+			}
+			assert.Equal(t, tc.want, got.UTC())
+		})
+	}
+}
+
+// TestSchedulePrevAndBetween checks Prev mirrors Next and Between stops at
+// both its end and its limit.
+func TestSchedulePrevAndBetween(t *testing.T) {
+	s, err := Parse("0 9 * * 1-5", nil)
+	require.NoError(t, err)
+
+	// 2026-07-20 is a Monday; the previous weekday run is Friday the 17th.
+	from := time.Date(2026, 7, 20, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 7, 17, 9, 0, 0, 0, time.UTC), s.Prev(from).UTC())
+	assert.Equal(t, time.Date(2026, 7, 20, 9, 0, 0, 0, time.UTC), s.Prev(from.Add(time.Hour+time.Nanosecond)).UTC())
+	assert.Equal(t, time.Date(2026, 7, 17, 9, 0, 0, 0, time.UTC), s.Prev(from.Add(time.Hour)).UTC(), "strictly before")
+
+	runs := s.Between(from, from.AddDate(0, 0, 14), 100)
+	assert.Len(t, runs, 10)
+	assert.Len(t, s.Between(from, from.AddDate(0, 0, 14), 3), 3)
+	assert.Len(t, s.Between(from, from.Add(time.Hour), 100), 1, "end is inclusive")
+	assert.Empty(t, s.Between(from, from.Add(59*time.Minute), 100))
+
+	never, err := Parse("0 0 30 2 *", nil)
+	require.NoError(t, err)
+	assert.True(t, never.Prev(from).IsZero())
+	assert.Empty(t, never.Between(from, from.AddDate(5, 0, 0), 10))
+}
+
+// TestScheduleDST checks runs across daylight saving transitions in New
+// York: 2026-03-08 02:00 jumps to 03:00 and 2026-11-01 02:00 falls back
+// to 01:00.
+func TestScheduleDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := func(m time.Month, d, h, min int, offset int) time.Time {
+		return time.Date(2026, m, d, h, min, 0, 0, time.FixedZone("", offset*3600))
+	}
+
+	t.Run("time skipped by spring forward fires at the transition", func(t *testing.T) {
+		s, err := Parse("30 2 * * *", ny)
+		require.NoError(t, err)
+		runs := s.Between(at(3, 7, 12, 0, -5), at(3, 10, 0, 0, -4), 10)
+		require.Len(t, runs, 2)
+		assert.True(t, runs[0].Equal(at(3, 8, 3, 0, -4)), "got %v", runs[0])
+		assert.True(t, runs[1].Equal(at(3, 9, 2, 30, -4)), "got %v", runs[1])
+		assert.True(t, s.Prev(runs[1]).Equal(runs[0]))
+	})
+
+	t.Run("repeated time fires once on fall back", func(t *testing.T) {
+		s, err := Parse("30 1 * * *", ny)
+		require.NoError(t, err)
+		runs := s.Between(at(10, 31, 12, 0, -4), at(11, 2, 12, 0, -5), 10)
+		require.Len(t, runs, 2)
+		assert.True(t, runs[0].Equal(at(11, 1, 1, 30, -4)), "got %v", runs[0])
+		assert.True(t, runs[1].Equal(at(11, 2, 1, 30, -5)), "got %v", runs[1])
+		assert.True(t, s.Prev(runs[1]).Equal(runs[0]))
+	})
+
+	t.Run("every-hour schedule fires in both repeated hours", func(t *testing.T) {
+		s, err := Parse("*/30 * * * *", ny)
+		require.NoError(t, err)
+		runs := s.Between(at(11, 1, 0, 45, -4), at(11, 1, 2, 0, -5), 10)
+		require.Len(t, runs, 5)
+		assert.True(t, runs[2].Equal(at(11, 1, 1, 0, -5)), "got %v", runs[2])
+		assert.Equal(t, time.Hour, runs[2].Sub(runs[0]))
+		assert.True(t, s.Prev(runs[3]).Equal(runs[2]))
+	})
+
+	// Past the end of 2040 Go stops listing New York's transitions and
+	// computes them from the zone's rule, which Next and Prev must still
+	// step through.
+	t.Run("after 2040", func(t *testing.T) {
+		s, err := Parse("* * * * *", ny)
+		require.NoError(t, err)
+		from := time.Date(2040, 12, 31, 12, 0, 0, 0, time.UTC)
+		assert.True(t, s.Next(from).Equal(from.Add(time.Minute)), "got %v", s.Next(from))
+		assert.True(t, s.Prev(from).Equal(from.Add(-time.Minute)), "got %v", s.Prev(from))
+
+		s, err = Parse("0 0 0 1 1 ? 2041", ny)
+		require.NoError(t, err)
+		next := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, "2041-01-01T00:00:00-05:00", next.Format(time.RFC3339))
+		assert.True(t, s.Prev(time.Date(2060, 1, 1, 0, 0, 0, 0, time.UTC)).Equal(next))
+
+		s, err = Parse("30 2 * * *", ny)
+		require.NoError(t, err)
+		runs := s.Between(time.Date(2045, 3, 11, 12, 0, 0, 0, ny), time.Date(2045, 3, 14, 0, 0, 0, 0, ny), 10)
+		require.Len(t, runs, 2)
+		assert.Equal(t, "2045-03-12T03:00:00-04:00", runs[0].Format(time.RFC3339))
+		assert.Equal(t, "2045-03-13T02:30:00-04:00", runs[1].Format(time.RFC3339))
+		assert.True(t, s.Prev(runs[1]).Equal(runs[0]))
+
+		s, err = Parse("30 1 * * *", ny)
+		require.NoError(t, err)
+		runs = s.Between(time.Date(2045, 11, 4, 12, 0, 0, 0, ny), time.Date(2045, 11, 6, 12, 0, 0, 0, ny), 10)
+		require.Len(t, runs, 2)
+		assert.Equal(t, "2045-11-05T01:30:00-04:00", runs[0].Format(time.RFC3339))
+		assert.Equal(t, "2045-11-06T01:30:00-05:00", runs[1].Format(time.RFC3339))
+	})
+
+	t.Run("runs are returned in the schedule's zone", func(t *testing.T) {
+		s, err := Parse("0 9 * * *", ny)
+		require.NoError(t, err)
+		next := s.Next(at(7, 1, 0, 0, 0))
+		assert.Equal(t, ny, next.Location())
+		assert.Equal(t, 9, next.Hour())
+	})
+}
+
+// TestDescribe checks the plain-English rendering of common shapes.
+func TestDescribe(t *testing.T) {
+	tests := []struct{ expr, want string }{
+		{"0 9 * * *", "At 09:00"},
+		{"0 9,17 * * 1-5", "At 09:00 and 17:00, Monday through Friday"},
+		{"*/5 9-17 * * MON-FRI", "Every 5 minutes, between 09:00 and 17:59, Monday through Friday"},
+		{"* * * * *", "Every minute"},
+		{"*/10 * * * * *", "Every 10 seconds"},
+		{"30 0 12 * * *", "At 12:00:30"},
+		{"5 * * * *", "At 5 minutes past the hour"},
+		{"@hourly", "Every hour"},
+		{"@monthly", "At 00:00, on day 1 of the month"},
+		{"0 0 1,15 * *", "At 00:00, on days 1 and 15 of the month"},
+		{"0 0 L * *", "At 00:00, on the last day of the month"},
+		{"0 0 * * 5L", "At 00:00, on the last Friday of the month"},
+		{"0 0 * * 1#2", "At 00:00, on the second Monday of the month"},
+		{"0 0 1 * 1", "At 00:00, on day 1 of the month or on Monday"},
+		{"0 12 * JAN,JUL *", "At 12:00, in January and July"},
+		{"0 0 0 1 1 * 2030", "At 00:00, on day 1 of the month, in January, in 2030"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := Parse(tc.expr, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, s.Describe())
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	gomath "math"
+)
+
+// Argument accessors for resolvers. Literal arguments arrive from the
+// parser as string/int/float64/bool; variables arrive from the decoded
+// JSON request, where every number is a float64.
+
+func arg(typ, description string) *Argument {
+	return &Argument{Type: typ, Description: description}
+}
+
+func stringArg(args map[string]interface{}, name string) (string, error) {
+	v, ok := args[name].(string)
+	if !ok {
+		return "", fmt.Errorf("argument %q is required", name)
+	}
+	return v, nil
+}
+
+func optStringArg(args map[string]interface{}, name, def string) string {
+	if v, ok := args[name].(string); ok {
+		return v
+	}
+	return def
+}
+
+func intArg(args map[string]interface{}, name string) (int, error) {
+	v, present := args[name]
+	if !present || v == nil {
+		return 0, fmt.Errorf("argument %q is required", name)
+	}
+	return toInt(name, v)
+}
+
+func optIntArg(args map[string]interface{}, name string, def int) (int, error) {
+	v, present := args[name]
+	if !present || v == nil {
+		return def, nil
+	}
+	return toInt(name, v)
+}
+
+func toInt(name string, v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case float64:
+		if n != gomath.Trunc(n) || gomath.Abs(n) > gomath.MaxInt32 {
+			return 0, fmt.Errorf("argument %q must be an Int", name)
+		}
+		return int(n), nil
+	}
+	return 0, fmt.Errorf("argument %q must be an Int", name)
+}
+
+func floatArg(args map[string]interface{}, name string) (float64, error) {
+	v, present := args[name]
+	if !present || v == nil {
+		return 0, fmt.Errorf("argument %q is required", name)
+	}
+	return toFloat(name, v)
+}
+
+func optFloatArg(args map[string]interface{}, name string, def float64) (float64, error) {
+	v, present := args[name]
+	if !present || v == nil {
+		return def, nil
+	}
+	return toFloat(name, v)
+}
+
+func toFloat(name string, v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	}
+	return 0, fmt.Errorf("argument %q must be a Float", name)
+}
+
+func optBoolArg(args map[string]interface{}, name string, def bool) bool {
+	if v, ok := args[name].(bool); ok {
+		return v
+	}
+	return def
+}
+
+func stringListArg(args map[string]interface{}, name string) ([]string, error) {
+	items, ok := args[name].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument %q is required", name)
+	}
+	out := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("argument %q must be a list of String", name)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// optStringListArg is stringListArg for an optional argument; it returns
+// nil when the argument is absent or null.
+func optStringListArg(args map[string]interface{}, name string) ([]string, error) {
+	if args[name] == nil {
+		return nil, nil
+	}
+	return stringListArg(args, name)
+}
+
+func floatListArg(args map[string]interface{}, name string) ([]float64, error) {
+	items, ok := args[name].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument %q is required", name)
+	}
+	out := make([]float64, len(items))
+	for i, item := range items {
+		f, err := toFloat(name, item)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q must be a list of Float", name)
+		}
+		out[i] = f
+	}
+	return out, nil
+}
+
+// toValue converts a resolver result to the plain maps, slices and
+// scalars its JSON encoding describes, so selections can be applied to
+// struct results by their json field names. Numbers are kept as
+// json.Number to preserve int64 and big values exactly.
+func toValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var out interface{}
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// remap converts a map-shaped service result into the struct that
+// declares its GraphQL type.
+func remap(src interface{}, dst interface{}) error {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+// remapAs converts a map-shaped service result (and its error) into T.
+func remapAs[T any](src interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	var dst T
+	if err := remap(src, &dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// defineUnary defines a field that takes a single required String
+// argument. resultType is a GraphQL type reference such as "String!".
+func defineUnary(query map[string]*Field, name, description, resultType, argName, argDescription string, fn func(string) (interface{}, error)) {
+	define(query, name, &Field{
+		Type:        resultType,
+		Description: description,
+		Args: map[string]*Argument{
+			argName: arg("String!", argDescription),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			value, err := stringArg(args, argName)
+			if err != nil {
+				return nil, err
+			}
+			return fn(value)
+		},
+	})
+}
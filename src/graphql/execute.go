@@ -0,0 +1,510 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gomath "math"
+	"strconv"
+)
+
+// executeQuery parses, validates and executes a request against the
+// default schema. Syntax and validation errors are returned without
+// data; errors raised while executing fields are returned alongside the
+// partial result, each with the location and response path of the field.
+func executeQuery(req Request) Response {
+	doc, err := newDocParser(req.Query).parseDocument()
+	if err != nil {
+		return Response{Errors: []Error{asError(err)}}
+	}
+	schema := defaultSchema()
+	if errs := schema.validate(doc); len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return Response{Errors: []Error{asError(err)}}
+	}
+	variables, errs := coerceVariables(op, req.Variables)
+	if len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+
+	var root *ObjectType
+	switch op.Type {
+	case "query":
+		root = schema.Query
+	case "mutation":
+		root = schema.Mutation
+	}
+	if root == nil {
+		return Response{Errors: []Error{{
+			Message:   fmt.Sprintf("Schema is not configured to execute %s operation.", op.Type),
+			Locations: []Location{op.Loc},
+		}}}
+	}
+
+	ex := &executor{schema: schema, fragments: doc.Fragments, variables: variables}
+	data, ok := ex.executeFields(root, nil, op.Selections, nil)
+	resp := Response{Errors: ex.errs}
+	if ok {
+		resp.Data = data
+	} else {
+		// A non-null root field failed, so the whole result is null. Data
+		// is omitted when nil, and the spec requires "data": null once
+		// execution has started.
+		resp.Data = json.RawMessage("null")
+	}
+	return resp
+}
+
+// asError converts err to a GraphQL error, keeping the locations of
+// parser errors.
+func asError(err error) Error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		return *gqlErr
+	}
+	return Error{Message: err.Error()}
+}
+
+// operation selects the operation to execute: the one named name, or the
+// only one in the document when name is empty.
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.Operations) > 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		return d.Operations[0], nil
+	}
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+// coerceVariables checks the request's variables against the operation's
+// variable definitions and applies defaults. Variables that are neither
+// provided nor defaulted are left out, so arguments that use them count as
+// omitted.
+func coerceVariables(op *operation, given map[string]interface{}) (map[string]interface{}, []Error) {
+	variables := map[string]interface{}{}
+	var errs []Error
+	for _, def := range op.Variables {
+		value, present := given[def.Name]
+		if !present {
+			if def.Default != nil {
+				// Defaults are literals, already checked by validation.
+				variables[def.Name], _ = coerceInput(def.Type, def.Default.resolve(nil))
+			} else if isNonNull(def.Type) {
+				errs = append(errs, Error{
+					Message:   fmt.Sprintf("Variable \"$%s\" of required type %q was not provided.", def.Name, def.Type),
+					Locations: []Location{def.Loc},
+				})
+			}
+			continue
+		}
+		if value == nil && isNonNull(def.Type) {
+			errs = append(errs, Error{
+				Message:   fmt.Sprintf("Variable \"$%s\" of non-null type %q must not be null.", def.Name, def.Type),
+				Locations: []Location{def.Loc},
+			})
+			continue
+		}
+		coerced, err := coerceInput(def.Type, value)
+		if err != nil {
+			errs = append(errs, Error{
+				Message:   fmt.Sprintf("Variable \"$%s\" got invalid value %s; %s", def.Name, describeValue(value), err),
+				Locations: []Location{def.Loc},
+			})
+			continue
+		}
+		variables[def.Name] = coerced
+	}
+	return variables, errs
+}
+
+// coerceInput converts an input value (a resolved literal or a JSON
+// variable) to the Go value resolvers receive for type ref: string, int,
+// float64, bool, []interface{} or, for JSON, the value unchanged. A single
+// value given for a list type becomes a one-item list.
+func coerceInput(ref string, value interface{}) (interface{}, error) {
+	if value == nil {
+		if isNonNull(ref) {
+			return nil, fmt.Errorf("Expected non-nullable type %q not to be null.", ref)
+		}
+		return nil, nil
+	}
+	ref = nullable(ref)
+	if item, ok := listItem(ref); ok {
+		items, isList := value.([]interface{})
+		if !isList {
+			coerced, err := coerceInput(item, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{coerced}, nil
+		}
+		out := make([]interface{}, len(items))
+		for i, v := range items {
+			coerced, err := coerceInput(item, v)
+			if err != nil {
+				return nil, fmt.Errorf("In element #%d: %w", i, err)
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	}
+
+	switch ref {
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("String cannot represent a non string value: %s", describeValue(value))
+	case "ID":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int:
+			return strconv.Itoa(v), nil
+		case float64:
+			if v == gomath.Trunc(v) {
+				return strconv.FormatFloat(v, 'f', -1, 64), nil
+			}
+		}
+		return nil, fmt.Errorf("ID cannot represent value: %s", describeValue(value))
+	case "Int":
+		switch v := value.(type) {
+		case int:
+			if v >= gomath.MinInt32 && v <= gomath.MaxInt32 {
+				return v, nil
+			}
+		case float64:
+			if v == gomath.Trunc(v) && v >= gomath.MinInt32 && v <= gomath.MaxInt32 {
+				return int(v), nil
+			}
+		}
+		return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %s", describeValue(value))
+	case "Float":
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+		return nil, fmt.Errorf("Float cannot represent non numeric value: %s", describeValue(value))
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %s", describeValue(value))
+	case scalarJSON:
+		return value, nil
+	}
+	return nil, fmt.Errorf("Unknown input type %q.", ref)
+}
+
+// describeValue prints a Go input value the way it appeared in JSON.
+func describeValue(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
+
+// executor runs one operation of a validated document.
+type executor struct {
+	schema    *Schema
+	fragments map[string]*fragment
+	variables map[string]interface{}
+	errs      []Error
+}
+
+// fieldGroup is the set of selections that share a response key. They
+// are merged into one result entry whose sub-selections are the union of
+// theirs.
+type fieldGroup struct {
+	key  string
+	sels []selection
+}
+
+// collectFields flattens a selection set for an object type into field
+// groups in response order, applying @skip/@include and expanding the
+// fragments that apply to obj.
+func (ex *executor) collectFields(obj *ObjectType, sels []selection, groups []*fieldGroup, visited map[string]bool) []*fieldGroup {
+	for _, sel := range sels {
+		if !ex.included(sel.Directives) {
+			continue
+		}
+		switch sel.Kind {
+		case fieldSelection:
+			var group *fieldGroup
+			for _, g := range groups {
+				if g.key == sel.Alias {
+					group = g
+					break
+				}
+			}
+			if group == nil {
+				group = &fieldGroup{key: sel.Alias}
+				groups = append(groups, group)
+			}
+			group.sels = append(group.sels, sel)
+		case fragmentSpread:
+			if visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			frag, ok := ex.fragments[sel.Name]
+			if !ok || frag.TypeCondition != obj.Name {
+				continue
+			}
+			groups = ex.collectFields(obj, frag.Selections, groups, visited)
+		case inlineFragment:
+			if sel.TypeCondition != "" && sel.TypeCondition != obj.Name {
+				continue
+			}
+			groups = ex.collectFields(obj, sel.Selections, groups, visited)
+		}
+	}
+	return groups
+}
+
+// included evaluates the @skip and @include directives of a selection.
+func (ex *executor) included(directives []directive) bool {
+	for _, d := range directives {
+		cond, ok := d.Arguments["if"]
+		if !ok {
+			continue
+		}
+		value, _ := cond.resolve(ex.variables).(bool)
+		switch d.Name {
+		case "skip":
+			if value {
+				return false
+			}
+		case "include":
+			if !value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// executeFields executes a selection set against obj. source is the
+// parent value the fields are read from, nil for the root type, whose
+// fields have resolvers. The result is false when a non-null field came
+// back null, in which case the whole object is null.
+func (ex *executor) executeFields(obj *ObjectType, source map[string]interface{}, sels []selection, path []interface{}) (*resultMap, bool) {
+	out := &resultMap{values: map[string]interface{}{}}
+	for _, group := range ex.collectFields(obj, sels, nil, map[string]bool{}) {
+		sel := group.sels[0]
+		field := ex.schema.fieldDef(obj, sel.Name)
+		if field == nil {
+			continue
+		}
+		fieldPath := appendPath(path, group.key)
+		resolved, err := ex.resolveField(obj, source, field, sel)
+		if err != nil {
+			ex.fieldError(err.Error(), sel, fieldPath)
+			if isNonNull(field.Type) {
+				return nil, false
+			}
+			out.set(group.key, nil)
+			continue
+		}
+		value, ok := ex.completeValue(field.Type, resolved, obj, group.sels, fieldPath)
+		if !ok {
+			return nil, false
+		}
+		out.set(group.key, value)
+	}
+	return out, true
+}
+
+// resolveField produces the raw value of a field: from its resolver, from
+// the introspection system, or from the parent value.
+func (ex *executor) resolveField(obj *ObjectType, source map[string]interface{}, field *Field, sel selection) (interface{}, error) {
+	switch {
+	case sel.Name == "__typename":
+		return obj.Name, nil
+	case field == metaFields["__schema"]:
+		return ex.schema.introspectSchema(), nil
+	case field == metaFields["__type"]:
+		args, err := ex.argumentValues(field, sel)
+		if err != nil {
+			return nil, err
+		}
+		return ex.schema.introspectNamed(args["name"].(string)), nil
+	case field.Resolve != nil:
+		args, err := ex.argumentValues(field, sel)
+		if err != nil {
+			return nil, err
+		}
+		result, err := field.Resolve(args)
+		if err != nil {
+			return nil, err
+		}
+		return toValue(result)
+	}
+	return source[sel.Name], nil
+}
+
+// argumentValues builds a resolver's arguments from a selection,
+// substituting variables and applying the field's defaults. Arguments
+// that are omitted, or that use an unset variable, are left out.
+func (ex *executor) argumentValues(field *Field, sel selection) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	for _, name := range sortedKeys(field.Args) {
+		def := field.Args[name]
+		given, ok := sel.Arguments[name]
+		if ok && given.isVariable {
+			_, ok = ex.variables[given.varName]
+		}
+		if !ok {
+			if def.DefaultValue == "" {
+				continue
+			}
+			literal, err := parseLiteral(def.DefaultValue)
+			if err != nil {
+				return nil, fmt.Errorf("Argument %q has an invalid default value: %w", name, err)
+			}
+			given = literal
+		}
+		value, err := coerceInput(def.Type, given.resolve(ex.variables))
+		if err != nil {
+			return nil, fmt.Errorf("Argument %q has invalid value %s: %w", name, given, err)
+		}
+		args[name] = value
+	}
+	return args, nil
+}
+
+// completeValue shapes a resolved value to its declared type and the
+// query's selections. The result is false when the value is null in a
+// non-null position (the error is already recorded); the null then
+// propagates to the nearest nullable parent.
+func (ex *executor) completeValue(ref string, value interface{}, parent *ObjectType, sels []selection, path []interface{}) (interface{}, bool) {
+	if thunk, ok := value.(func() interface{}); ok {
+		value = thunk()
+	}
+
+	if isNonNull(ref) {
+		inner := nullable(ref)
+		if value == nil {
+			if _, isList := listItem(inner); isList {
+				// encoding/json writes a nil Go slice as null; it is
+				// still an empty list.
+				value = []interface{}{}
+			} else if inner == scalarJSON {
+				// null is itself a JSON value.
+				return nil, true
+			} else {
+				ex.fieldError(fmt.Sprintf("Cannot return null for non-nullable field %s.%s.", parent.Name, sels[0].Name), sels[0], path)
+				return nil, false
+			}
+		}
+		completed, ok := ex.completeValue(inner, value, parent, sels, path)
+		if !ok || completed == nil {
+			// value was not null, so a null result means its error has
+			// already been recorded.
+			return nil, false
+		}
+		return completed, true
+	}
+	if value == nil {
+		return nil, true
+	}
+
+	if item, isList := listItem(ref); isList {
+		items, ok := value.([]interface{})
+		if !ok {
+			ex.fieldError(fmt.Sprintf("Expected Iterable, but did not find one for field %s.%s.", parent.Name, sels[0].Name), sels[0], path)
+			return nil, true
+		}
+		out := make([]interface{}, len(items))
+		for i, v := range items {
+			completed, ok := ex.completeValue(item, v, parent, sels, appendPath(path, i))
+			if !ok {
+				return nil, true
+			}
+			out[i] = completed
+		}
+		return out, true
+	}
+
+	if ex.schema.isLeafType(ref) {
+		return value, true
+	}
+	obj := ex.schema.objectType(ref)
+	m, ok := value.(map[string]interface{})
+	if obj == nil || !ok {
+		ex.fieldError(fmt.Sprintf("Expected value of type %q for field %s.%s, found %s.", ref, parent.Name, sels[0].Name, describeValue(value)), sels[0], path)
+		return nil, true
+	}
+	var subSelections []selection
+	for _, sel := range sels {
+		subSelections = append(subSelections, sel.Selections...)
+	}
+	result, ok := ex.executeFields(obj, m, subSelections, path)
+	if !ok {
+		return nil, true
+	}
+	return result, true
+}
+
+// fieldError records an execution error against a field.
+func (ex *executor) fieldError(message string, sel selection, path []interface{}) {
+	ex.errs = append(ex.errs, Error{Message: message, Locations: []Location{sel.Loc}, Path: path})
+}
+
+// appendPath returns path+elem without sharing path's backing array.
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	out := make([]interface{}, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, elem)
+}
+
+// resultMap is an object result. It keeps the response keys in the
+// order the query selected them, as the spec requires.
+type resultMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *resultMap) set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON writes the object with its keys in selection order.
+func (m *resultMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
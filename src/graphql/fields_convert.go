@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apimgr/api/src/service/convert"
+)
+
+type unitConversion struct {
+	Category string  `json:"category"`
+	Value    float64 `json:"value"`
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Result   float64 `json:"result"`
+}
+
+type colorConversion struct {
+	Hex string      `json:"hex"`
+	RGB convert.RGB `json:"rgb"`
+	HSL convert.HSL `json:"hsl"`
+}
+
+// unitConversions lists the "from-to" pairs convert.Service implements per
+// category, using the same unit codes as the REST /convert endpoints.
+func unitConversions(s *convert.Service) map[string]map[string]func(float64) float64 {
+	return map[string]map[string]func(float64) float64{
+		"length": {
+			"ft-m": s.FeetToMeters, "m-ft": s.MetersToFeet,
+			"in-cm": s.InchesToCentimeters, "cm-in": s.CentimetersToInches,
+			"mi-km": s.MilesToKilometers, "km-mi": s.KilometersToMiles,
+		},
+		"temperature": {
+			"c-f": s.CelsiusToFahrenheit, "f-c": s.FahrenheitToCelsius,
+			"c-k": s.CelsiusToKelvin, "k-c": s.KelvinToCelsius,
+		},
+		"weight": {
+			"lb-kg": s.PoundsToKilograms, "kg-lb": s.KilogramsToPounds,
+			"oz-g": s.OuncesToGrams, "g-oz": s.GramsToOunces,
+		},
+		"volume": {
+			"gal-l": s.GallonsToLiters, "l-gal": s.LitersToGallons,
+		},
+		"time": {
+			"s-min": s.SecondsToMinutes, "min-s": s.MinutesToSeconds,
+			"hr-min": s.HoursToMinutes, "min-hr": s.MinutesToHours,
+			"day-hr": s.DaysToHours, "hr-day": s.HoursToDays,
+		},
+		"area": {
+			"sqm-sqft": s.SquareMetersToSquareFeet, "sqft-sqm": s.SquareFeetToSquareMeters,
+			"acre-ha": s.AcresToHectares, "ha-acre": s.HectaresToAcres,
+		},
+		"data": {
+			"b-kb": s.BytesToKilobytes, "kb-b": s.KilobytesToBytes,
+			"kb-mb": s.KilobytesToMegabytes, "mb-kb": s.MegabytesToKilobytes,
+			"mb-gb": s.MegabytesToGigabytes, "gb-mb": s.GigabytesToMegabytes,
+			"gb-tb": s.GigabytesToTerabytes, "tb-gb": s.TerabytesToGigabytes,
+		},
+		"energy": {
+			"j-cal": s.JoulesToCalories, "cal-j": s.CaloriesToJoules,
+			"j-kwh": s.JoulesToKilowattHours, "kwh-j": s.KilowattHoursToJoules,
+		},
+		"pressure": {
+			"pa-bar": s.PascalsToBar, "bar-pa": s.BarToPascals,
+			"pa-psi": s.PascalsToPSI, "psi-pa": s.PSIToPascals,
+			"pa-atm": s.PascalsToAtmospheres, "atm-pa": s.AtmospheresToPascals,
+		},
+		"speed": {
+			"mph-kmh": s.MphToKmh, "kmh-mph": s.KmhToMph,
+			"ms-kmh": s.MsToKmh, "kmh-ms": s.KmhToMs,
+			"knot-kmh": s.KnotsToKmh, "kmh-knot": s.KmhToKnots,
+		},
+	}
+}
+
+func addConvertFields(b *typeBuilder, query map[string]*Field) {
+	svc := convert.New()
+	units := unitConversions(svc)
+
+	define(query, "convertUnits", &Field{
+		Type:        b.ref((*unitConversion)(nil)),
+		Description: "Convert a value between units (length, temperature, weight, volume, time, area, data, energy, pressure, speed)",
+		Args: map[string]*Argument{
+			"category": arg("String!", "Unit category"),
+			"value":    arg("Float!", "Value to convert"),
+			"from":     arg("String!", "Source unit code, e.g. km"),
+			"to":       arg("String!", "Target unit code, e.g. mi"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			category, err := stringArg(args, "category")
+			if err != nil {
+				return nil, err
+			}
+			value, err := floatArg(args, "value")
+			if err != nil {
+				return nil, err
+			}
+			from, err := stringArg(args, "from")
+			if err != nil {
+				return nil, err
+			}
+			to, err := stringArg(args, "to")
+			if err != nil {
+				return nil, err
+			}
+			category, from, to = strings.ToLower(category), strings.ToLower(from), strings.ToLower(to)
+			pairs, ok := units[category]
+			if !ok {
+				return nil, fmt.Errorf("unsupported category: %s (supported: %s)", category, strings.Join(sortedKeys(units), ", "))
+			}
+			fn, ok := pairs[from+"-"+to]
+			if !ok {
+				return nil, fmt.Errorf("unsupported unit pair: %s-%s", from, to)
+			}
+			return unitConversion{Category: category, Value: value, From: from, To: to, Result: fn(value)}, nil
+		},
+	})
+
+	define(query, "convertColor", &Field{
+		Type:        b.ref((*colorConversion)(nil)),
+		Description: "A hex color as hex, RGB and HSL",
+		Args: map[string]*Argument{
+			"hex": arg("String!", "Hex color, e.g. #ff8800"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			hex, err := stringArg(args, "hex")
+			if err != nil {
+				return nil, err
+			}
+			rgb, err := svc.HexToRGB(hex)
+			if err != nil {
+				return nil, err
+			}
+			normalized, err := svc.RGBToHex(rgb)
+			if err != nil {
+				return nil, err
+			}
+			hsl, err := svc.RGBToHSL(rgb)
+			if err != nil {
+				return nil, err
+			}
+			return colorConversion{Hex: normalized, RGB: rgb, HSL: hsl}, nil
+		},
+	})
+
+	define(query, "convertCurrency", &Field{
+		Type:        b.ref((*convert.CurrencyResult)(nil)),
+		Description: "Convert an amount between currencies at ECB reference rates",
+		Args: map[string]*Argument{
+			"amount": arg("Float!", "Amount"),
+			"from":   arg("String!", "ISO 4217 source currency"),
+			"to":     arg("String!", "ISO 4217 target currency"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			amount, err := floatArg(args, "amount")
+			if err != nil {
+				return nil, err
+			}
+			from, err := stringArg(args, "from")
+			if err != nil {
+				return nil, err
+			}
+			to, err := stringArg(args, "to")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ConvertCurrency(amount, strings.ToUpper(from), strings.ToUpper(to))
+		},
+	})
+
+	define(query, "convertJSON", &Field{
+		Type:        "String!",
+		Description: "Pretty-print or minify a JSON document",
+		Args: map[string]*Argument{
+			"json":   arg("String!", "JSON document"),
+			"minify": arg("Boolean", "Minify instead of pretty-print"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			doc, err := stringArg(args, "json")
+			if err != nil {
+				return nil, err
+			}
+			if optBoolArg(args, "minify", false) {
+				return svc.JSONMinify(doc)
+			}
+			return svc.JSONPrettyPrint(doc)
+		},
+	})
+}
@@ -0,0 +1,1440 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apimgr/api/src/service/crypto"
+	"github.com/apimgr/api/src/service/generate"
+)
+
+type passwordStrength struct {
+	Score        int     `json:"score"`
+	Strength     string  `json:"strength"`
+	Length       int     `json:"length"`
+	EntropyBits  float64 `json:"entropy_bits"`
+	HasUppercase bool    `json:"has_uppercase"`
+	HasLowercase bool    `json:"has_lowercase"`
+	HasNumbers   bool    `json:"has_numbers"`
+	HasSymbols   bool    `json:"has_symbols"`
+	CharsetSize  int     `json:"charset_size"`
+
+	Guesses      float64                   `json:"guesses"`
+	GuessesLog10 float64                   `json:"guesses_log10"`
+	CrackTimes   crypto.PasswordCrackTimes `json:"crack_times"`
+	Feedback     crypto.PasswordFeedback   `json:"feedback"`
+	Sequence     []crypto.PasswordMatch    `json:"sequence"`
+}
+
+type cryptoKeyPair struct {
+	Type       string `json:"type"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+type cryptoTOTPSecret struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type cryptoCertificate struct {
+	Subject            string          `json:"subject"`
+	Issuer             string          `json:"issuer"`
+	SerialNumber       string          `json:"serial_number"`
+	NotBefore          string          `json:"not_before"`
+	NotAfter           string          `json:"not_after"`
+	DNSNames           []string        `json:"dns_names"`
+	IsCA               bool            `json:"is_ca"`
+	SignatureAlgorithm string          `json:"signature_algorithm"`
+	PublicKeyAlgorithm string          `json:"public_key_algorithm"`
+	Version            int             `json:"version"`
+	FingerprintSHA1    string          `json:"fingerprint_sha1"`
+	FingerprintSHA256  string          `json:"fingerprint_sha256"`
+	PublicKey          *crypto.KeyInfo `json:"public_key"`
+	IPAddresses        []string        `json:"ip_addresses"`
+	EmailAddresses     []string        `json:"email_addresses"`
+	URIs               []string        `json:"uris"`
+	KeyUsage           []string        `json:"key_usage"`
+	ExtKeyUsage        []string        `json:"ext_key_usage"`
+	SubjectKeyID       string          `json:"subject_key_id"`
+	AuthorityKeyID     string          `json:"authority_key_id"`
+	MaxPathLen         *int            `json:"max_path_len"`
+}
+
+type cryptoSelfSigned struct {
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key"`
+}
+
+type cryptoCSR struct {
+	CSR        string          `json:"csr"`
+	PrivateKey string          `json:"private_key"`
+	Info       *crypto.CSRInfo `json:"info"`
+}
+
+type cryptoPasswordVerification struct {
+	Valid     bool                      `json:"valid"`
+	Algorithm string                    `json:"algorithm"`
+	Params    crypto.PasswordHashParams `json:"params"`
+}
+
+type cryptoHOTPVerification struct {
+	Valid       bool    `json:"valid"`
+	Counter     *uint64 `json:"counter"`
+	NextCounter *uint64 `json:"next_counter"`
+}
+
+type cryptoMnemonic struct {
+	Mnemonic string `json:"mnemonic"`
+	Words    int    `json:"words"`
+	Language string `json:"language"`
+	Entropy  string `json:"entropy"`
+}
+
+type cryptoMnemonicSeed struct {
+	Seed               string `json:"seed"`
+	ExtendedPrivateKey string `json:"extended_private_key"`
+	ExtendedPublicKey  string `json:"extended_public_key"`
+}
+
+// otpOptionsArgs reads the optional algorithm, digits and period arguments
+// shared by the HOTP and TOTP fields.
+func otpOptionsArgs(args map[string]interface{}) (crypto.OTPOptions, error) {
+	digits, err := optIntArg(args, "digits", 6)
+	if err != nil {
+		return crypto.OTPOptions{}, err
+	}
+	period, err := optIntArg(args, "period", 30)
+	if err != nil {
+		return crypto.OTPOptions{}, err
+	}
+	return crypto.OTPOptions{Algorithm: optStringArg(args, "algorithm", "SHA1"), Digits: digits, Period: int64(period)}, nil
+}
+
+type cryptoCipherResult struct {
+	Ciphertext string             `json:"ciphertext"`
+	IV         string             `json:"iv"`
+	Key        string             `json:"key"`
+	KDF        *crypto.KDFOptions `json:"kdf"`
+}
+
+type cryptoShamirShares struct {
+	ID        string   `json:"id"`
+	Threshold int      `json:"threshold"`
+	Shares    []string `json:"shares"`
+}
+
+type cryptoKDFKey struct {
+	crypto.KDFOptions
+	Key string `json:"key"`
+}
+
+// cipherArgs adds the key, IV and mode arguments shared by
+// cryptoCipherEncrypt and cryptoCipherDecrypt to args.
+func cipherArgs(args map[string]*Argument) map[string]*Argument {
+	args["algorithm"] = arg("String", "Cipher (default aes-gcm)")
+	args["key"] = arg("String", "Hex key (or passphrase)")
+	args["passphrase"] = arg("String", "Passphrase run through kdf (or key)")
+	args["kdf"] = arg(scalarJSON, "{algorithm, salt (hex), params}; salt is required to decrypt")
+	args["iv"] = arg("String", "Hex IV or nonce (random when encrypting without one)")
+	args["aad"] = arg("String", "Hex additional authenticated data")
+	args["padding"] = arg("String", "aes-cbc padding: pkcs7 (default) or none")
+	return args
+}
+
+// cipherOptionsArgs reads the cipherArgs arguments. A passphrase key is
+// derived with the kdf argument (32-byte key unless params.key_length says
+// otherwise), whose effective options are returned.
+func cipherOptionsArgs(args map[string]interface{}, decrypt bool) (crypto.CipherOptions, *crypto.KDFOptions, error) {
+	opts := crypto.CipherOptions{
+		Algorithm: optStringArg(args, "algorithm", "aes-gcm"),
+		Padding:   optStringArg(args, "padding", ""),
+	}
+	var err error
+	if opts.IV, err = crypto.DecodeBytes("iv", optStringArg(args, "iv", ""), "hex"); err != nil {
+		return opts, nil, err
+	}
+	if opts.AAD, err = crypto.DecodeBytes("aad", optStringArg(args, "aad", ""), "hex"); err != nil {
+		return opts, nil, err
+	}
+	key, passphrase := optStringArg(args, "key", ""), optStringArg(args, "passphrase", "")
+	if (key == "") == (passphrase == "") {
+		return opts, nil, fmt.Errorf("exactly one of key and passphrase is required")
+	}
+	if key != "" {
+		opts.Key, err = crypto.DecodeBytes("key", key, "hex")
+		return opts, nil, err
+	}
+
+	var kdf crypto.KDFOptions
+	if args["kdf"] != nil {
+		if err := remap(args["kdf"], &kdf); err != nil {
+			return opts, nil, fmt.Errorf("invalid kdf: %w", err)
+		}
+	}
+	if decrypt && kdf.Salt == "" {
+		return opts, nil, fmt.Errorf("kdf.salt is required to decrypt with a passphrase")
+	}
+	if kdf.Params.KeyLength == 0 {
+		kdf.Params.KeyLength = 32
+	}
+	if opts.Key, kdf, err = crypto.DeriveKey(passphrase, kdf); err != nil {
+		return opts, nil, err
+	}
+	return opts, &kdf, nil
+}
+
+// hexArgPair reads two required hex arguments.
+func hexArgPair(args map[string]interface{}, a, b string) ([]byte, []byte, error) {
+	var out [2][]byte
+	for i, name := range []string{a, b} {
+		s, err := stringArg(args, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if out[i], err = crypto.DecodeBytes(name, s, "hex"); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out[0], out[1], nil
+}
+
+// cryptoMaxRSABits caps RSA key generation, which is CPU-bound.
+const cryptoMaxRSABits = 4096
+
+func addCryptoFields(b *typeBuilder, query map[string]*Field) {
+	define(query, "cryptoPassword", &Field{
+		Type:        "String!",
+		Description: "Generate a random password",
+		Args: map[string]*Argument{
+			"length":          arg("Int", "Length, 4-256 (default 16)"),
+			"uppercase":       arg("Boolean", "Include A-Z (default true)"),
+			"lowercase":       arg("Boolean", "Include a-z (default true)"),
+			"numbers":         arg("Boolean", "Include 0-9 (default true)"),
+			"symbols":         arg("Boolean", "Include symbols (default true)"),
+			"exclude_similar": arg("Boolean", "Leave out look-alike characters such as l/1/O/0"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			length, err := optIntArg(args, "length", 16)
+			if err != nil {
+				return nil, err
+			}
+			opts := crypto.DefaultPasswordOptions()
+			opts.Uppercase = optBoolArg(args, "uppercase", opts.Uppercase)
+			opts.Lowercase = optBoolArg(args, "lowercase", opts.Lowercase)
+			opts.Numbers = optBoolArg(args, "numbers", opts.Numbers)
+			opts.Symbols = optBoolArg(args, "symbols", opts.Symbols)
+			opts.ExcludeSimilar = optBoolArg(args, "exclude_similar", opts.ExcludeSimilar)
+			return crypto.GeneratePassword(length, opts)
+		},
+	})
+
+	define(query, "cryptoPIN", &Field{
+		Type:        "String!",
+		Description: "Generate a numeric PIN",
+		Args: map[string]*Argument{
+			"length": arg("Int", "Digits, 3-12 (default 4)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			length, err := optIntArg(args, "length", 4)
+			if err != nil {
+				return nil, err
+			}
+			return crypto.GeneratePIN(length)
+		},
+	})
+
+	define(query, "cryptoRandomBytes", &Field{
+		Type:        "String!",
+		Description: "Cryptographically random bytes, hex or base64 encoded",
+		Args: map[string]*Argument{
+			"count":    arg("Int", "Number of bytes, max 10000 (default 32)"),
+			"encoding": arg("String", "hex (default) or base64"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			count, err := optIntArg(args, "count", 32)
+			if err != nil {
+				return nil, err
+			}
+			raw, err := crypto.RandomBytes(count)
+			if err != nil {
+				return nil, err
+			}
+			switch encoding := strings.ToLower(optStringArg(args, "encoding", "hex")); encoding {
+			case "hex":
+				return hex.EncodeToString(raw), nil
+			case "base64":
+				return base64.StdEncoding.EncodeToString(raw), nil
+			default:
+				return nil, fmt.Errorf("unsupported encoding %q", encoding)
+			}
+		},
+	})
+
+	define(query, "cryptoBcryptVerify", &Field{
+		Type:        "Boolean!",
+		Description: "Check a password against a bcrypt hash",
+		Args: map[string]*Argument{
+			"password": arg("String!", "Password"),
+			"hash":     arg("String!", "bcrypt hash"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			password, err := stringArg(args, "password")
+			if err != nil {
+				return nil, err
+			}
+			hash, err := stringArg(args, "hash")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.BcryptVerify(password, hash), nil
+		},
+	})
+
+	define(query, "cryptoPasswordHash", &Field{
+		Type:        "String!",
+		Description: "Hash a password: " + strings.Join(crypto.PasswordHashAlgorithms, ", "),
+		Args: map[string]*Argument{
+			"algorithm": arg("String", "Algorithm (default argon2id)"),
+			"password":  arg("String!", "Password"),
+			"params":    arg(scalarJSON, "Work factors such as memory, time, threads, ln, r, p, iterations or cost"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			password, err := stringArg(args, "password")
+			if err != nil {
+				return nil, err
+			}
+			var params crypto.PasswordHashParams
+			if args["params"] != nil {
+				if err := remap(args["params"], &params); err != nil {
+					return nil, fmt.Errorf("invalid params: %w", err)
+				}
+			}
+			return crypto.PasswordHash(optStringArg(args, "algorithm", "argon2id"), password, params)
+		},
+	})
+
+	define(query, "cryptoPasswordVerify", &Field{
+		Type:        b.ref((*cryptoPasswordVerification)(nil)),
+		Description: "Check a password against an Argon2, scrypt, PBKDF2, crypt or bcrypt hash",
+		Args: map[string]*Argument{
+			"password": arg("String!", "Password"),
+			"hash":     arg("String!", "Encoded hash; the algorithm is detected from it"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			password, err := stringArg(args, "password")
+			if err != nil {
+				return nil, err
+			}
+			hash, err := stringArg(args, "hash")
+			if err != nil {
+				return nil, err
+			}
+			info, valid, err := crypto.PasswordVerify(password, hash)
+			if err != nil {
+				return nil, err
+			}
+			return cryptoPasswordVerification{Valid: valid, Algorithm: info.Algorithm, Params: info.Params}, nil
+		},
+	})
+
+	define(query, "cryptoHMAC", &Field{
+		Type:        "String!",
+		Description: "Hex HMAC, or keyed BLAKE2/BLAKE3 MAC, of a message (" + strings.Join(crypto.HMACAlgorithms, ", ") + ")",
+		Args: map[string]*Argument{
+			"algorithm": arg("String!", "Hash algorithm"),
+			"key":       arg("String!", "Secret key"),
+			"message":   arg("String!", "Message"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			algorithm, err := stringArg(args, "algorithm")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			message, err := stringArg(args, "message")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.HMACGenerate(algorithm, key, message)
+		},
+	})
+
+	define(query, "cryptoPasswordStrength", &Field{
+		Type:        b.ref((*passwordStrength)(nil)),
+		Description: "Estimate the strength of a password",
+		Args: map[string]*Argument{
+			"password":    arg("String!", "Password to score"),
+			"user_inputs": arg("[String!]", "Words tied to the account, such as its name or email, to check the password against"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			password, err := stringArg(args, "password")
+			if err != nil {
+				return nil, err
+			}
+			inputs, err := optStringListArg(args, "user_inputs")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[passwordStrength](crypto.PasswordStrength(password, inputs...), nil)
+		},
+	})
+
+	define(query, "cryptoTOTPSecret", &Field{
+		Type:        b.ref((*cryptoTOTPSecret)(nil)),
+		Description: "New TOTP secret and its otpauth:// URI",
+		Args: map[string]*Argument{
+			"issuer":  arg("String", "Issuer shown by authenticator apps"),
+			"account": arg("String", "Account name shown by authenticator apps"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			secret, err := crypto.GenerateTOTPSecret(20)
+			if err != nil {
+				return nil, err
+			}
+			issuer := optStringArg(args, "issuer", "API Toolkit")
+			account := optStringArg(args, "account", "user")
+			return cryptoTOTPSecret{Secret: secret, URI: crypto.GenerateTOTPURI(secret, issuer, account)}, nil
+		},
+	})
+
+	define(query, "cryptoTOTPCode", &Field{
+		Type:        "String!",
+		Description: "Current TOTP code for a base32 secret",
+		Args: map[string]*Argument{
+			"secret":    arg("String!", "Base32 secret"),
+			"digits":    arg("Int", "Code length, 6-8 (default 6)"),
+			"period":    arg("Int", "Step in seconds (default 30)"),
+			"algorithm": arg("String", "HMAC algorithm: "+strings.Join(crypto.OTPAlgorithms, ", ")+" (default SHA1)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			secret, err := stringArg(args, "secret")
+			if err != nil {
+				return nil, err
+			}
+			opts, err := otpOptionsArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return crypto.TOTPAt(secret, time.Now(), opts)
+		},
+	})
+
+	define(query, "cryptoTOTPVerify", &Field{
+		Type:        "Boolean!",
+		Description: "Check a TOTP code, allowing window steps of clock drift (default 1)",
+		Args: map[string]*Argument{
+			"secret":    arg("String!", "Base32 secret"),
+			"code":      arg("String!", "Code to check"),
+			"digits":    arg("Int", "Code length, 6-8 (default 6)"),
+			"period":    arg("Int", "Step in seconds (default 30)"),
+			"algorithm": arg("String", "HMAC algorithm (default SHA1)"),
+			"window":    arg("Int", "Steps either side of now to accept, 0-10 (default 1)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			secret, err := stringArg(args, "secret")
+			if err != nil {
+				return nil, err
+			}
+			code, err := stringArg(args, "code")
+			if err != nil {
+				return nil, err
+			}
+			opts, err := otpOptionsArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			window, err := optIntArg(args, "window", 1)
+			if err != nil {
+				return nil, err
+			}
+			return crypto.VerifyTOTPAt(secret, code, time.Now(), window, opts)
+		},
+	})
+
+	define(query, "cryptoHOTPCode", &Field{
+		Type:        "String!",
+		Description: "RFC 4226 counter-based code for a base32 secret",
+		Args: map[string]*Argument{
+			"secret":    arg("String!", "Base32 secret"),
+			"counter":   arg("Int!", "Counter value"),
+			"digits":    arg("Int", "Code length, 6-8 (default 6)"),
+			"algorithm": arg("String", "HMAC algorithm (default SHA1)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			secret, err := stringArg(args, "secret")
+			if err != nil {
+				return nil, err
+			}
+			counter, err := optIntArg(args, "counter", 0)
+			if err != nil {
+				return nil, err
+			}
+			if counter < 0 {
+				return nil, fmt.Errorf("counter must not be negative")
+			}
+			opts, err := otpOptionsArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return crypto.GenerateHOTP(secret, uint64(counter), opts)
+		},
+	})
+
+	define(query, "cryptoHOTPVerify", &Field{
+		Type:        b.ref((*cryptoHOTPVerification)(nil)),
+		Description: "Check an HOTP code against a counter and the look_ahead counters after it",
+		Args: map[string]*Argument{
+			"secret":     arg("String!", "Base32 secret"),
+			"code":       arg("String!", "Code to check"),
+			"counter":    arg("Int!", "Next expected counter"),
+			"look_ahead": arg("Int", "Further counters to accept, 0-100 (default 0)"),
+			"digits":     arg("Int", "Code length, 6-8 (default 6)"),
+			"algorithm":  arg("String", "HMAC algorithm (default SHA1)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			secret, err := stringArg(args, "secret")
+			if err != nil {
+				return nil, err
+			}
+			code, err := stringArg(args, "code")
+			if err != nil {
+				return nil, err
+			}
+			counter, err := optIntArg(args, "counter", 0)
+			if err != nil {
+				return nil, err
+			}
+			if counter < 0 {
+				return nil, fmt.Errorf("counter must not be negative")
+			}
+			lookAhead, err := optIntArg(args, "look_ahead", 0)
+			if err != nil {
+				return nil, err
+			}
+			opts, err := otpOptionsArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			valid, matched, err := crypto.VerifyHOTP(secret, code, uint64(counter), lookAhead, opts)
+			if err != nil {
+				return nil, err
+			}
+			result := cryptoHOTPVerification{Valid: valid}
+			if valid {
+				next := matched + 1
+				result.Counter, result.NextCounter = &matched, &next
+			}
+			return result, nil
+		},
+	})
+
+	define(query, "cryptoOTPAuthParse", &Field{
+		Type:        b.ref((*crypto.OTPAuthKey)(nil)),
+		Description: "Parse an otpauth:// key URI into its parameters",
+		Args: map[string]*Argument{
+			"uri": arg("String!", "otpauth://totp/... or otpauth://hotp/... URI"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			uri, err := stringArg(args, "uri")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.ParseOTPAuthURI(uri)
+		},
+	})
+
+	define(query, "cryptoOTPAuthQR", &Field{
+		Type:        "String!",
+		Description: "Base64-encoded PNG QR code of a validated otpauth:// URI",
+		Args: map[string]*Argument{
+			"uri":  arg("String!", "otpauth:// URI"),
+			"size": arg("Int", "Width and height in pixels (default 256)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			uri, err := stringArg(args, "uri")
+			if err != nil {
+				return nil, err
+			}
+			if _, err := crypto.ParseOTPAuthURI(uri); err != nil {
+				return nil, err
+			}
+			size, err := optIntArg(args, "size", 256)
+			if err != nil {
+				return nil, err
+			}
+			if size < 64 || size > generateMaxImageSize {
+				return nil, fmt.Errorf("size must be between 64 and %d", generateMaxImageSize)
+			}
+			return encodeImage(generate.New().QR(uri, size, size))
+		},
+	})
+
+	define(query, "cryptoKeyPair", &Field{
+		Type:        b.ref((*cryptoKeyPair)(nil)),
+		Description: "Generate a PEM key pair: rsa, ecdsa or ed25519",
+		Args: map[string]*Argument{
+			"type": arg("String!", "Key type"),
+			"bits": arg("Int", fmt.Sprintf("RSA key size, 2048-%d (default 2048)", cryptoMaxRSABits)),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			keyType, err := stringArg(args, "type")
+			if err != nil {
+				return nil, err
+			}
+			keyType = strings.ToLower(keyType)
+			var private, public string
+			switch keyType {
+			case "rsa":
+				bits, err := optIntArg(args, "bits", 2048)
+				if err != nil {
+					return nil, err
+				}
+				if bits > cryptoMaxRSABits {
+					return nil, fmt.Errorf("bits must be at most %d", cryptoMaxRSABits)
+				}
+				private, public, err = crypto.GenerateRSAKeys(bits)
+			case "ecdsa":
+				private, public, err = crypto.GenerateECDSAKeys()
+			case "ed25519":
+				private, public, err = crypto.GenerateEd25519Keys()
+			default:
+				return nil, fmt.Errorf("unsupported key type %q", keyType)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return cryptoKeyPair{Type: keyType, PrivateKey: private, PublicKey: public}, nil
+		},
+	})
+
+	define(query, "cryptoAESEncrypt", &Field{
+		Type:        "String!",
+		Description: "AES-GCM encrypt with a key derived from a passphrase",
+		Args: map[string]*Argument{
+			"plaintext": arg("String!", "Text to encrypt"),
+			"key":       arg("String!", "Passphrase"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			plaintext, err := stringArg(args, "plaintext")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.AESEncrypt(plaintext, key)
+		},
+	})
+
+	define(query, "cryptoAESDecrypt", &Field{
+		Type:        "String!",
+		Description: "Decrypt cryptoAESEncrypt output",
+		Args: map[string]*Argument{
+			"ciphertext": arg("String!", "Base64 ciphertext"),
+			"key":        arg("String!", "Passphrase"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ciphertext, err := stringArg(args, "ciphertext")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.AESDecrypt(ciphertext, key)
+		},
+	})
+
+	define(query, "cryptoCipherEncrypt", &Field{
+		Type:        b.ref((*cryptoCipherResult)(nil)),
+		Description: "Encrypt with explicit parameters: " + strings.Join(crypto.CipherAlgorithms, ", "),
+		Args: cipherArgs(map[string]*Argument{
+			"plaintext":          arg("String!", "Data to encrypt"),
+			"plaintext_encoding": arg("String", "utf8 (default), hex or base64"),
+			"encoding":           arg("String", "Ciphertext encoding: hex (default) or base64"),
+		}),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			text, err := stringArg(args, "plaintext")
+			if err != nil {
+				return nil, err
+			}
+			plaintext, err := crypto.DecodeBytes("plaintext", text, optStringArg(args, "plaintext_encoding", "utf8"))
+			if err != nil {
+				return nil, err
+			}
+			opts, kdf, err := cipherOptionsArgs(args, false)
+			if err != nil {
+				return nil, err
+			}
+			ciphertext, iv, err := crypto.CipherEncrypt(plaintext, opts)
+			if err != nil {
+				return nil, err
+			}
+			encoded, _ := crypto.EncodeBytes(ciphertext, optStringArg(args, "encoding", "hex"))
+			result := &cryptoCipherResult{Ciphertext: encoded, IV: hex.EncodeToString(iv), KDF: kdf}
+			if kdf != nil {
+				result.Key = hex.EncodeToString(opts.Key)
+			}
+			return result, nil
+		},
+	})
+
+	define(query, "cryptoCipherDecrypt", &Field{
+		Type:        "String!",
+		Description: "Decrypt cryptoCipherEncrypt output or any ciphertext with known parameters",
+		Args: cipherArgs(map[string]*Argument{
+			"ciphertext":         arg("String!", "Ciphertext, with the AEAD tag appended"),
+			"encoding":           arg("String", "Ciphertext encoding: hex (default) or base64"),
+			"plaintext_encoding": arg("String", "utf8 (default), hex or base64; non-UTF-8 utf8 output falls back to hex"),
+		}),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			text, err := stringArg(args, "ciphertext")
+			if err != nil {
+				return nil, err
+			}
+			ciphertext, err := crypto.DecodeBytes("ciphertext", text, optStringArg(args, "encoding", "hex"))
+			if err != nil {
+				return nil, err
+			}
+			opts, _, err := cipherOptionsArgs(args, true)
+			if err != nil {
+				return nil, err
+			}
+			plaintext, err := crypto.CipherDecrypt(ciphertext, opts)
+			if err != nil {
+				return nil, err
+			}
+			encoded, _ := crypto.EncodeBytes(plaintext, optStringArg(args, "plaintext_encoding", "utf8"))
+			return encoded, nil
+		},
+	})
+
+	define(query, "cryptoKDF", &Field{
+		Type:        b.ref((*cryptoKDFKey)(nil)),
+		Description: "Derive a raw key from a passphrase: " + strings.Join(crypto.KDFAlgorithms, ", "),
+		Args: map[string]*Argument{
+			"passphrase": arg("String!", "Passphrase"),
+			"kdf":        arg(scalarJSON, "{algorithm, salt (hex), params} (default argon2id, random salt)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			passphrase, err := stringArg(args, "passphrase")
+			if err != nil {
+				return nil, err
+			}
+			var opts crypto.KDFOptions
+			if args["kdf"] != nil {
+				if err := remap(args["kdf"], &opts); err != nil {
+					return nil, fmt.Errorf("invalid kdf: %w", err)
+				}
+			}
+			key, used, err := crypto.DeriveKey(passphrase, opts)
+			if err != nil {
+				return nil, err
+			}
+			return &cryptoKDFKey{KDFOptions: used, Key: hex.EncodeToString(key)}, nil
+		},
+	})
+
+	define(query, "cryptoKeyWrap", &Field{
+		Type:        "String!",
+		Description: "Hex AES key wrap (RFC 3394, or RFC 5649 with padding) of a key",
+		Args: map[string]*Argument{
+			"kek":     arg("String!", "Hex AES key-encryption key"),
+			"key":     arg("String!", "Hex key to wrap"),
+			"padding": arg("Boolean", "Use RFC 5649 padding (default false)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			kek, key, err := hexArgPair(args, "kek", "key")
+			if err != nil {
+				return nil, err
+			}
+			wrapped, err := crypto.AESKeyWrap(kek, key, optBoolArg(args, "padding", false))
+			if err != nil {
+				return nil, err
+			}
+			return hex.EncodeToString(wrapped), nil
+		},
+	})
+
+	define(query, "cryptoKeyUnwrap", &Field{
+		Type:        "String!",
+		Description: "Hex key recovered from cryptoKeyWrap output",
+		Args: map[string]*Argument{
+			"kek":     arg("String!", "Hex AES key-encryption key"),
+			"wrapped": arg("String!", "Hex wrapped key"),
+			"padding": arg("Boolean", "Use RFC 5649 padding (default false)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			kek, wrapped, err := hexArgPair(args, "kek", "wrapped")
+			if err != nil {
+				return nil, err
+			}
+			key, err := crypto.AESKeyUnwrap(kek, wrapped, optBoolArg(args, "padding", false))
+			if err != nil {
+				return nil, err
+			}
+			return hex.EncodeToString(key), nil
+		},
+	})
+
+	define(query, "cryptoAgeKeygen", &Field{
+		Type:        b.ref((*crypto.AgeKeyPair)(nil)),
+		Description: "Generate an age X25519 identity and recipient",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return crypto.GenerateAgeKeyPair()
+		},
+	})
+
+	define(query, "cryptoAgeEncrypt", &Field{
+		Type:        "String!",
+		Description: "Encrypt to age X25519 recipients or a passphrase",
+		Args: map[string]*Argument{
+			"plaintext":   arg("String!", "Text to encrypt"),
+			"recipients":  arg("[String!]", "age1... recipients"),
+			"passphrase":  arg("String", "Passphrase (instead of recipients)"),
+			"work_factor": arg("Int", fmt.Sprintf("scrypt log2 N for a passphrase, 1-%d (default %d)", crypto.AgeMaxWorkFactor, crypto.AgeMaxWorkFactor)),
+			"armor":       arg("Boolean", "ASCII-armor the output (default true); otherwise base64"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			plaintext, err := stringArg(args, "plaintext")
+			if err != nil {
+				return nil, err
+			}
+			recipients, err := optStringListArg(args, "recipients")
+			if err != nil {
+				return nil, err
+			}
+			workFactor, err := optIntArg(args, "work_factor", 0)
+			if err != nil {
+				return nil, err
+			}
+			return crypto.AgeEncrypt([]byte(plaintext), recipients, optStringArg(args, "passphrase", ""), workFactor, optBoolArg(args, "armor", true))
+		},
+	})
+
+	define(query, "cryptoAgeDecrypt", &Field{
+		Type:        "String!",
+		Description: "Decrypt an armored or base64 age file",
+		Args: map[string]*Argument{
+			"ciphertext": arg("String!", "age file"),
+			"identities": arg("[String!]", "AGE-SECRET-KEY-1... identities"),
+			"passphrase": arg("String", "Passphrase (instead of identities)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ciphertext, err := stringArg(args, "ciphertext")
+			if err != nil {
+				return nil, err
+			}
+			identities, err := optStringListArg(args, "identities")
+			if err != nil {
+				return nil, err
+			}
+			plaintext, err := crypto.AgeDecrypt(ciphertext, identities, optStringArg(args, "passphrase", ""))
+			if err != nil {
+				return nil, err
+			}
+			encoded, _ := crypto.EncodeBytes(plaintext, "utf8")
+			return encoded, nil
+		},
+	})
+
+	define(query, "cryptoShamirSplit", &Field{
+		Type:        b.ref((*cryptoShamirShares)(nil)),
+		Description: "Split a secret into Shamir shares, any threshold of which recover it",
+		Args: map[string]*Argument{
+			"secret":          arg("String!", "Secret to split"),
+			"secret_encoding": arg("String", "utf8 (default), hex or base64"),
+			"shares":          arg("Int!", "Number of shares, 2-255"),
+			"threshold":       arg("Int!", "Shares needed to recover, 2-shares"),
+			"encoding":        arg("String", "Share encoding: "+strings.Join(crypto.ShamirEncodings, ", ")+" (default hex)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			text, err := stringArg(args, "secret")
+			if err != nil {
+				return nil, err
+			}
+			secret, err := crypto.DecodeBytes("secret", text, optStringArg(args, "secret_encoding", "utf8"))
+			if err != nil {
+				return nil, err
+			}
+			count, err := intArg(args, "shares")
+			if err != nil {
+				return nil, err
+			}
+			threshold, err := intArg(args, "threshold")
+			if err != nil {
+				return nil, err
+			}
+			shares, err := crypto.ShamirSplit(secret, count, threshold)
+			if err != nil {
+				return nil, err
+			}
+			result := &cryptoShamirShares{Threshold: threshold, Shares: make([]string, len(shares))}
+			for i, share := range shares {
+				if result.Shares[i], err = crypto.EncodeShamirShare(share, optStringArg(args, "encoding", "hex")); err != nil {
+					return nil, err
+				}
+			}
+			info, _ := crypto.InspectShamirShare(shares[0])
+			result.ID = info.ID
+			return result, nil
+		},
+	})
+
+	define(query, "cryptoShamirCombine", &Field{
+		Type:        "String!",
+		Description: "Recover a secret from cryptoShamirSplit shares, checking their integrity",
+		Args: map[string]*Argument{
+			"shares":          arg("[String!]!", "At least threshold shares"),
+			"encoding":        arg("String", "Share encoding: "+strings.Join(crypto.ShamirEncodings, ", ")+" (default hex)"),
+			"secret_encoding": arg("String", "utf8 (default; falls back to hex), hex or base64"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			encoded, err := stringListArg(args, "shares")
+			if err != nil {
+				return nil, err
+			}
+			shares := make([][]byte, len(encoded))
+			for i, s := range encoded {
+				if shares[i], err = crypto.DecodeShamirShare(s, optStringArg(args, "encoding", "hex")); err != nil {
+					return nil, fmt.Errorf("share %d: %w", i+1, err)
+				}
+			}
+			secret, _, err := crypto.ShamirCombine(shares)
+			if err != nil {
+				return nil, err
+			}
+			out, _ := crypto.EncodeBytes(secret, optStringArg(args, "secret_encoding", "utf8"))
+			return out, nil
+		},
+	})
+
+	define(query, "cryptoEd25519Sign", &Field{
+		Type:        "String!",
+		Description: "Base64 Ed25519 signature of a message",
+		Args: map[string]*Argument{
+			"message":     arg("String!", "Message to sign"),
+			"private_key": arg("String!", "PEM private key"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			message, err := stringArg(args, "message")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "private_key")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.Ed25519Sign(message, key)
+		},
+	})
+
+	define(query, "cryptoEd25519Verify", &Field{
+		Type:        "Boolean!",
+		Description: "Verify an Ed25519 signature",
+		Args: map[string]*Argument{
+			"message":    arg("String!", "Signed message"),
+			"signature":  arg("String!", "Base64 signature"),
+			"public_key": arg("String!", "PEM public key"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			message, err := stringArg(args, "message")
+			if err != nil {
+				return nil, err
+			}
+			signature, err := stringArg(args, "signature")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "public_key")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.Ed25519Verify(message, signature, key)
+		},
+	})
+
+	define(query, "cryptoJWTSign", &Field{
+		Type:        "String!",
+		Description: "Sign claims as a JWT: " + strings.Join(crypto.JWTAlgorithms, ", "),
+		Args: map[string]*Argument{
+			"algorithm": arg("String!", "JWS algorithm"),
+			"key":       arg("String!", "HMAC secret, or PEM/JWK private key"),
+			"claims":    arg(scalarJSON, "Claims object"),
+			"header":    arg(scalarJSON, "Extra protected header members, such as kid"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			algorithm, err := stringArg(args, "algorithm")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			claims, _ := args["claims"].(map[string]interface{})
+			header, _ := args["header"].(map[string]interface{})
+			return crypto.JWTSign(algorithm, claims, header, key)
+		},
+	})
+
+	define(query, "cryptoJWTVerify", &Field{
+		Type:        b.ref((*crypto.JWTVerification)(nil)),
+		Description: "Verify a JWT's signature and its exp, nbf, iat, aud and iss claims",
+		Args: map[string]*Argument{
+			"token":      arg("String!", "Compact JWT"),
+			"key":        arg("String!", "HMAC secret, or PEM/JWK public key"),
+			"algorithms": arg("[String!]", "Accepted algorithms (default: all supported)"),
+			"audience":   arg("String", "Required aud value"),
+			"issuer":     arg("String", "Required iss value"),
+			"clock_skew": arg("Int", "Leeway in seconds for exp, nbf and iat (default 0)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			token, err := stringArg(args, "token")
+			if err != nil {
+				return nil, err
+			}
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			var algorithms []string
+			if args["algorithms"] != nil {
+				if algorithms, err = stringListArg(args, "algorithms"); err != nil {
+					return nil, err
+				}
+			}
+			skew, err := optIntArg(args, "clock_skew", 0)
+			if err != nil {
+				return nil, err
+			}
+			return crypto.JWTVerify(token, key, crypto.JWTVerifyOptions{
+				Algorithms: algorithms,
+				Audience:   optStringArg(args, "audience", ""),
+				Issuer:     optStringArg(args, "issuer", ""),
+				ClockSkew:  time.Duration(skew) * time.Second,
+			})
+		},
+	})
+
+	define(query, "cryptoKeyInspect", &Field{
+		Type:        b.ref((*crypto.KeyInfo)(nil)),
+		Description: "Type, size, curve and fingerprints of a PEM, DER, JWK or OpenSSH key",
+		Args: map[string]*Argument{
+			"key": arg("String!", "Key in any supported format"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.InspectKey(key)
+		},
+	})
+
+	define(query, "cryptoKeyConvert", &Field{
+		Type:        "String!",
+		Description: "Convert a key to " + strings.Join(crypto.KeyFormats, ", "),
+		Args: map[string]*Argument{
+			"key":         arg("String!", "Key in any supported format"),
+			"format":      arg("String!", "Output format"),
+			"public_only": arg("Boolean", "Output only the public half of a private key"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			key, err := stringArg(args, "key")
+			if err != nil {
+				return nil, err
+			}
+			format, err := stringArg(args, "format")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.ConvertKey(key, format, optBoolArg(args, "public_only", false))
+		},
+	})
+
+	define(query, "cryptoJWKS", &Field{
+		Type:        b.ref((*crypto.JWKS)(nil)),
+		Description: "JWKS document of the public halves of one or more keys",
+		Args: map[string]*Argument{
+			"keys": arg("[String!]!", "Keys in any supported format"),
+			"use":  arg("String", "Public key use for every entry, such as sig (default sig)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			keys, err := stringListArg(args, "keys")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.BuildJWKS(keys, optStringArg(args, "use", "sig"))
+		},
+	})
+
+	define(query, "cryptoCertificate", &Field{
+		Type:        b.ref((*cryptoCertificate)(nil)),
+		Description: "Decode a PEM X.509 certificate",
+		Args: map[string]*Argument{
+			"pem": arg("String!", "PEM certificate"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			pem, err := stringArg(args, "pem")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[cryptoCertificate](crypto.ParseCertificate(pem))
+		},
+	})
+
+	define(query, "cryptoSelfSignedCertificate", &Field{
+		Type:        b.ref((*cryptoSelfSigned)(nil)),
+		Description: "Generate a self-signed certificate and its private key",
+		Args: map[string]*Argument{
+			"common_name": arg("String!", "Subject common name"),
+			"days":        arg("Int", "Validity in days (default 365)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			commonName, err := stringArg(args, "common_name")
+			if err != nil {
+				return nil, err
+			}
+			days, err := optIntArg(args, "days", 365)
+			if err != nil {
+				return nil, err
+			}
+			cert, key, err := crypto.GenerateCertificate(commonName, days)
+			if err != nil {
+				return nil, err
+			}
+			return cryptoSelfSigned{Certificate: cert, PrivateKey: key}, nil
+		},
+	})
+
+	define(query, "cryptoCertificates", &Field{
+		Type:        b.ref((*[]cryptoCertificate)(nil)),
+		Description: "Decode every certificate in a PEM bundle, leaf first",
+		Args: map[string]*Argument{
+			"pem": arg("String!", "PEM certificate bundle"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			pem, err := stringArg(args, "pem")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[[]cryptoCertificate](crypto.DecodeCertificates(pem))
+		},
+	})
+
+	define(query, "cryptoCSR", &Field{
+		Type:        b.ref((*cryptoCSR)(nil)),
+		Description: "Generate a certificate signing request, with a new key unless one is given",
+		Args: map[string]*Argument{
+			"common_name":   arg("String", "Subject common name"),
+			"organization":  arg("String", "Subject organization"),
+			"dns_names":     arg("[String!]", "DNS subject alternative names"),
+			"ip_addresses":  arg("[String!]", "IP subject alternative names"),
+			"key_usage":     arg("[String!]", "Requested key usages, such as digital_signature"),
+			"ext_key_usage": arg("[String!]", "Requested extended key usages, such as server_auth"),
+			"key_type":      arg("String", "New key type: "+strings.Join(crypto.CertificateKeyTypes, ", ")+" (default ecdsa-p256)"),
+			"key":           arg("String", "Existing private key to sign the request with"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			opts := crypto.CSROptions{
+				Subject: crypto.CertificateSubject{
+					CommonName:   optStringArg(args, "common_name", ""),
+					Organization: optStringArg(args, "organization", ""),
+				},
+				KeyType: optStringArg(args, "key_type", ""),
+				Key:     optStringArg(args, "key", ""),
+			}
+			var err error
+			for name, dst := range map[string]*[]string{
+				"dns_names":     &opts.DNSNames,
+				"ip_addresses":  &opts.IPAddresses,
+				"key_usage":     &opts.KeyUsage,
+				"ext_key_usage": &opts.ExtKeyUsage,
+			} {
+				if *dst, err = optStringListArg(args, name); err != nil {
+					return nil, err
+				}
+			}
+			csr, key, err := crypto.GenerateCSR(opts)
+			if err != nil {
+				return nil, err
+			}
+			info, err := crypto.ParseCSR(csr)
+			if err != nil {
+				return nil, err
+			}
+			return cryptoCSR{CSR: csr, PrivateKey: key, Info: info}, nil
+		},
+	})
+
+	define(query, "cryptoCSRDecode", &Field{
+		Type:        b.ref((*crypto.CSRInfo)(nil)),
+		Description: "Decode a PEM certificate signing request and check its signature",
+		Args: map[string]*Argument{
+			"csr": arg("String!", "PEM certificate signing request"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			csr, err := stringArg(args, "csr")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.ParseCSR(csr)
+		},
+	})
+
+	define(query, "cryptoCertificateSign", &Field{
+		Type:        "String!",
+		Description: "Issue a PEM certificate for a CSR, signed by the given CA certificate and key",
+		Args: map[string]*Argument{
+			"csr":            arg("String!", "PEM certificate signing request"),
+			"ca_certificate": arg("String!", "PEM CA certificate"),
+			"ca_key":         arg("String!", "CA private key"),
+			"valid_days":     arg("Int", "Validity in days (default 365, at most 3650)"),
+			"is_ca":          arg("Boolean", "Issue a CA certificate"),
+			"path_len":       arg("Int", "CA path length constraint"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			csr, err := stringArg(args, "csr")
+			if err != nil {
+				return nil, err
+			}
+			caCert, err := stringArg(args, "ca_certificate")
+			if err != nil {
+				return nil, err
+			}
+			caKey, err := stringArg(args, "ca_key")
+			if err != nil {
+				return nil, err
+			}
+			days, err := optIntArg(args, "valid_days", 365)
+			if err != nil {
+				return nil, err
+			}
+			if days < 1 || days > 3650 {
+				return nil, fmt.Errorf("valid_days must be between 1 and 3650")
+			}
+			opts := crypto.SignCSROptions{ValidDays: days, IsCA: optBoolArg(args, "is_ca", false)}
+			if args["path_len"] != nil {
+				pathLen, err := optIntArg(args, "path_len", 0)
+				if err != nil {
+					return nil, err
+				}
+				opts.PathLen = &pathLen
+			}
+			return crypto.SignCSR(csr, caCert, caKey, opts)
+		},
+	})
+
+	define(query, "cryptoCertificateVerify", &Field{
+		Type:        b.ref((*crypto.ChainVerification)(nil)),
+		Description: "Build and validate a certificate chain against a root bundle (default: system roots)",
+		Args: map[string]*Argument{
+			"chain":    arg("[String!]!", "PEM certificates, leaf first, then intermediates"),
+			"roots":    arg("String", "PEM bundle of trusted roots"),
+			"dns_name": arg("String", "Host name the leaf must cover"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			chain, err := stringListArg(args, "chain")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.VerifyCertificateChain(chain, crypto.ChainOptions{
+				Roots:   optStringArg(args, "roots", ""),
+				DNSName: optStringArg(args, "dns_name", ""),
+			})
+		},
+	})
+
+	define(query, "cryptoPKCS12", &Field{
+		Type:        "String!",
+		Description: "Bundle a private key and its certificate chain as base64 PKCS#12",
+		Args: map[string]*Argument{
+			"private_key": arg("String!", "Private key"),
+			"certificate": arg("String!", "PEM certificate, optionally followed by CA certificates"),
+			"password":    arg("String", "Bundle password"),
+			"legacy":      arg("Boolean", "Use 3DES instead of AES for older consumers"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			key, err := stringArg(args, "private_key")
+			if err != nil {
+				return nil, err
+			}
+			cert, err := stringArg(args, "certificate")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.PKCS12Encode(key, cert, optStringArg(args, "password", ""), optBoolArg(args, "legacy", false))
+		},
+	})
+
+	define(query, "cryptoPKCS12Decode", &Field{
+		Type:        b.ref((*crypto.PKCS12Contents)(nil)),
+		Description: "Open a base64 PKCS#12 bundle",
+		Args: map[string]*Argument{
+			"pkcs12":   arg("String!", "Base64 PKCS#12 data"),
+			"password": arg("String", "Bundle password"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			pfx, err := stringArg(args, "pkcs12")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.PKCS12Decode(pfx, optStringArg(args, "password", ""))
+		},
+	})
+
+	define(query, "cryptoCRL", &Field{
+		Type:        b.ref((*crypto.CRLInfo)(nil)),
+		Description: "Decode a certificate revocation list, checking its signature when the issuer is given",
+		Args: map[string]*Argument{
+			"crl":    arg("String!", "PEM or base64 DER CRL"),
+			"issuer": arg("String", "PEM issuer certificate"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			crl, err := stringArg(args, "crl")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.ParseCRL(crl, optStringArg(args, "issuer", ""))
+		},
+	})
+
+	define(query, "cryptoMnemonic", &Field{
+		Type:        b.ref((*cryptoMnemonic)(nil)),
+		Description: "Generate a BIP39 mnemonic (" + strings.Join(crypto.MnemonicLanguages, ", ") + ")",
+		Args: map[string]*Argument{
+			"words":    arg("Int", "Word count: 12, 15, 18, 21 or 24 (default 12)"),
+			"language": arg("String", "Wordlist (default english)"),
+			"entropy":  arg("String", "Hex entropy (16-32 bytes) to encode instead of random bytes"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			words, err := optIntArg(args, "words", 12)
+			if err != nil {
+				return nil, err
+			}
+			language := optStringArg(args, "language", "")
+			var mnemonic string
+			if e := optStringArg(args, "entropy", ""); e != "" {
+				entropy, err := hex.DecodeString(e)
+				if err != nil {
+					return nil, fmt.Errorf("entropy must be hex: %w", err)
+				}
+				mnemonic, err = crypto.MnemonicFromEntropy(entropy, language)
+				if err != nil {
+					return nil, err
+				}
+			} else if mnemonic, err = crypto.GenerateMnemonic(words, language); err != nil {
+				return nil, err
+			}
+			info := crypto.ValidateMnemonic(mnemonic, language)
+			return cryptoMnemonic{Mnemonic: mnemonic, Words: info.Words, Language: info.Language, Entropy: info.Entropy}, nil
+		},
+	})
+
+	define(query, "cryptoMnemonicValidate", &Field{
+		Type:        b.ref((*crypto.MnemonicInfo)(nil)),
+		Description: "Check a BIP39 mnemonic's words and checksum, detecting its language",
+		Args: map[string]*Argument{
+			"mnemonic": arg("String!", "Mnemonic sentence"),
+			"language": arg("String", "Wordlist (detected when omitted)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			mnemonic, err := stringArg(args, "mnemonic")
+			if err != nil {
+				return nil, err
+			}
+			return crypto.ValidateMnemonic(mnemonic, optStringArg(args, "language", "")), nil
+		},
+	})
+
+	define(query, "cryptoMnemonicSeed", &Field{
+		Type:        b.ref((*cryptoMnemonicSeed)(nil)),
+		Description: "Derive the hex BIP39 seed and BIP32 master key for a mnemonic",
+		Args: map[string]*Argument{
+			"mnemonic":   arg("String!", "Mnemonic sentence"),
+			"passphrase": arg("String", "Optional BIP39 passphrase"),
+			"language":   arg("String", "Wordlist (detected when omitted)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			mnemonic, err := stringArg(args, "mnemonic")
+			if err != nil {
+				return nil, err
+			}
+			seed, err := crypto.MnemonicToSeed(mnemonic, optStringArg(args, "passphrase", ""), optStringArg(args, "language", ""))
+			if err != nil {
+				return nil, err
+			}
+			master, err := crypto.NewMasterKey(seed, "")
+			if err != nil {
+				return nil, err
+			}
+			return cryptoMnemonicSeed{
+				Seed:               hex.EncodeToString(seed),
+				ExtendedPrivateKey: master.String(),
+				ExtendedPublicKey:  master.Neuter().String(),
+			}, nil
+		},
+	})
+
+	define(query, "cryptoBIP32Derive", &Field{
+		Type:        b.ref((*crypto.DerivedKey)(nil)),
+		Description: "Derive the BIP32 key at a path from a mnemonic, hex seed or extended key (exactly one)",
+		Args: map[string]*Argument{
+			"path":         arg("String!", "Derivation path, e.g. m/44'/0'/0'/0/0"),
+			"mnemonic":     arg("String", "Mnemonic sentence"),
+			"passphrase":   arg("String", "Optional BIP39 passphrase for mnemonic"),
+			"language":     arg("String", "Mnemonic wordlist (detected when omitted)"),
+			"seed":         arg("String", "Hex seed (16-64 bytes)"),
+			"extended_key": arg("String", "xprv, xpub, tprv or tpub; m in path is this key"),
+			"network":      arg("String", "mainnet (default) or testnet, for mnemonic and seed roots"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			path, err := stringArg(args, "path")
+			if err != nil {
+				return nil, err
+			}
+			mnemonic := optStringArg(args, "mnemonic", "")
+			seedHex := optStringArg(args, "seed", "")
+			extended := optStringArg(args, "extended_key", "")
+			sources := 0
+			for _, s := range []string{mnemonic, seedHex, extended} {
+				if s != "" {
+					sources++
+				}
+			}
+			if sources != 1 {
+				return nil, fmt.Errorf("exactly one of mnemonic, seed or extended_key is required")
+			}
+
+			var root *crypto.ExtendedKey
+			switch {
+			case extended != "":
+				root, err = crypto.ParseExtendedKey(extended)
+			default:
+				var seed []byte
+				if mnemonic != "" {
+					seed, err = crypto.MnemonicToSeed(mnemonic, optStringArg(args, "passphrase", ""), optStringArg(args, "language", ""))
+				} else if seed, err = hex.DecodeString(seedHex); err != nil {
+					err = fmt.Errorf("seed must be hex: %w", err)
+				}
+				if err == nil {
+					root, err = crypto.NewMasterKey(seed, optStringArg(args, "network", ""))
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			return crypto.DeriveKeyInfo(root, path)
+		},
+	})
+}
@@ -0,0 +1,761 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apimgr/api/src/service/datetime"
+)
+
+type dateTimeNow struct {
+	Unix          int64  `json:"unix"`
+	UnixMS        int64  `json:"unix_ms"`
+	ISO8601       string `json:"iso8601"`
+	RFC2822       string `json:"rfc2822"`
+	Human         string `json:"human"`
+	Date          string `json:"date"`
+	Time          string `json:"time"`
+	Timezone      string `json:"timezone"`
+	Offset        string `json:"offset"`
+	OffsetSeconds int    `json:"offset_seconds"`
+	DayOfWeek     string `json:"day_of_week_name"`
+	DayOfYear     int    `json:"day_of_year"`
+	WeekNumber    int    `json:"week_number"`
+	Quarter       int    `json:"quarter"`
+	IsLeapYear    bool   `json:"is_leap_year"`
+	DaysInMonth   int    `json:"days_in_month"`
+}
+
+type dateTimeUnix struct {
+	Unix       int64  `json:"unix"`
+	UnixMS     int64  `json:"unix_ms"`
+	ISO8601    string `json:"iso8601"`
+	RFC2822    string `json:"rfc2822"`
+	Human      string `json:"human"`
+	Date       string `json:"date"`
+	Time       string `json:"time"`
+	Timezone   string `json:"timezone"`
+	Offset     string `json:"offset"`
+	DayOfWeek  string `json:"day_of_week"`
+	DayOfYear  int    `json:"day_of_year"`
+	IsLeapYear bool   `json:"is_leap_year"`
+}
+
+type dateTimeConversion struct {
+	Unix     int64  `json:"unix"`
+	From     string `json:"from"`
+	FromZone string `json:"from_zone"`
+	To       string `json:"to"`
+	ToZone   string `json:"to_zone"`
+}
+
+type dateTimeDiff struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Days         int    `json:"days"`
+	Hours        int    `json:"hours"`
+	Minutes      int    `json:"minutes"`
+	Seconds      int    `json:"seconds"`
+	Milliseconds int64  `json:"milliseconds"`
+	Human        string `json:"human"`
+}
+
+type dateTimeAdd struct {
+	Original   string `json:"original"`
+	Duration   string `json:"duration"`
+	Result     string `json:"result"`
+	ResultUnix int64  `json:"result_unix"`
+}
+
+type dateTimeZone struct {
+	Name          string `json:"name"`
+	Abbreviation  string `json:"abbreviation"`
+	Offset        string `json:"offset"`
+	OffsetSeconds int    `json:"offset_seconds"`
+	CurrentTime   string `json:"current_time"`
+	Date          string `json:"date"`
+	Time          string `json:"time"`
+}
+
+type dateTimeParsed struct {
+	Input           string  `json:"input"`
+	MatchedLayout   string  `json:"matched_layout"`
+	Kind            string  `json:"kind"`
+	Interpretation  string  `json:"interpretation"`
+	Reference       string  `json:"reference"`
+	Timezone        string  `json:"timezone"`
+	Unix            int64   `json:"unix"`
+	ISO8601         string  `json:"iso8601"`
+	Date            string  `json:"date"`
+	Time            string  `json:"time"`
+	Year            int     `json:"year"`
+	Month           int     `json:"month"`
+	Day             int     `json:"day"`
+	DayOfWeek       string  `json:"day_of_week"`
+	End             string  `json:"end"`
+	EndUnix         int64   `json:"end_unix"`
+	Duration        string  `json:"duration"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+type dateTimeHolidayDay struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type dateTimeCalendar struct {
+	Year         int                  `json:"year"`
+	Month        int                  `json:"month"`
+	MonthName    string               `json:"month_name"`
+	DaysInMonth  int                  `json:"days_in_month"`
+	StartsOn     string               `json:"starts_on"`
+	Weeks        [][]int              `json:"weeks"`
+	IsLeapYear   bool                 `json:"is_leap_year"`
+	Weekend      []string             `json:"weekend"`
+	Holidays     []dateTimeHolidayDay `json:"holidays"`
+	BusinessDays int                  `json:"business_days"`
+}
+
+type dateTimeWorkdays struct {
+	Start       string               `json:"start"`
+	End         string               `json:"end"`
+	TotalDays   int                  `json:"total_days"`
+	Workdays    int                  `json:"workdays"`
+	WeekendDays int                  `json:"weekend_days"`
+	HolidayDays int                  `json:"holiday_days"`
+	Holidays    []dateTimeHolidayDay `json:"holidays"`
+	Weekend     []string             `json:"weekend"`
+	Country     string               `json:"country"`
+	Region      string               `json:"region"`
+}
+
+type dateTimeBusinessDays struct {
+	Date            string               `json:"date"`
+	Days            int                  `json:"days"`
+	Result          string               `json:"result"`
+	ResultWeekday   string               `json:"result_weekday"`
+	CalendarDays    int                  `json:"calendar_days"`
+	Country         string               `json:"country"`
+	Region          string               `json:"region"`
+	Weekend         []string             `json:"weekend"`
+	SkippedHolidays []dateTimeHolidayDay `json:"skipped_holidays"`
+}
+
+type dateTimeHolidays struct {
+	Country     string             `json:"country"`
+	CountryName string             `json:"country_name"`
+	Region      string             `json:"region,omitempty"`
+	RegionName  string             `json:"region_name,omitempty"`
+	Year        int                `json:"year"`
+	Holidays    []datetime.Holiday `json:"holidays"`
+	Count       int                `json:"count"`
+}
+
+type dateTimeHolidayCalendar struct {
+	Country string            `json:"country"`
+	Name    string            `json:"name"`
+	Regions map[string]string `json:"regions"`
+}
+
+type dateTimeSun struct {
+	Date           string  `json:"date"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	PolarEvent     bool    `json:"polar_event"`
+	Description    string  `json:"description,omitempty"`
+	SunriseUTC     string  `json:"sunrise_utc,omitempty"`
+	SunsetUTC      string  `json:"sunset_utc,omitempty"`
+	SunriseISO8601 string  `json:"sunrise_iso8601,omitempty"`
+	SunsetISO8601  string  `json:"sunset_iso8601,omitempty"`
+}
+
+type dateTimeMoon struct {
+	Date             string  `json:"date"`
+	AgeDays          float64 `json:"age_days"`
+	Phase            string  `json:"phase"`
+	Illumination     float64 `json:"illumination"`
+	IlluminationPct  float64 `json:"illumination_pct"`
+	SynodicMonthDays float64 `json:"synodic_month_days"`
+}
+
+type dateTimeCronFields struct {
+	Second     string `json:"second"`
+	Minute     string `json:"minute"`
+	Hour       string `json:"hour"`
+	DayOfMonth string `json:"day_of_month"`
+	Month      string `json:"month"`
+	DayOfWeek  string `json:"day_of_week"`
+	Year       string `json:"year"`
+}
+
+type dateTimeCron struct {
+	Expression   string             `json:"expression"`
+	Normalized   string             `json:"normalized"`
+	Description  string             `json:"description"`
+	Timezone     string             `json:"timezone"`
+	Fields       dateTimeCronFields `json:"fields"`
+	NextRuns     []string           `json:"next_runs"`
+	PreviousRuns []string           `json:"previous_runs"`
+}
+
+type dateTimeRRule struct {
+	Rule        string   `json:"rule"`
+	Normalized  string   `json:"normalized"`
+	Description string   `json:"description"`
+	Timezone    string   `json:"timezone"`
+	Start       string   `json:"start"`
+	Occurrences []string `json:"occurrences"`
+	Count       int      `json:"count"`
+	Finite      bool     `json:"finite"`
+	HasMore     bool     `json:"has_more"`
+}
+
+type dateTimeICS struct {
+	ProdID    string             `json:"prod_id"`
+	Version   string             `json:"version"`
+	Name      string             `json:"name"`
+	Method    string             `json:"method"`
+	Timezones []string           `json:"timezones"`
+	Events    []datetime.ICSItem `json:"events"`
+	Todos     []datetime.ICSItem `json:"todos"`
+	Count     int                `json:"count"`
+}
+
+type dateTimeGregorianDate struct {
+	Year      int    `json:"year"`
+	Month     int    `json:"month"`
+	Day       int    `json:"day"`
+	MonthName string `json:"month_name"`
+	DayOfWeek string `json:"day_of_week"`
+	LeapYear  bool   `json:"leap_year"`
+	Date      string `json:"date"`
+}
+
+type dateTimeISOWeekDate struct {
+	Year    int    `json:"year"`
+	Week    int    `json:"week"`
+	Weekday int    `json:"weekday"`
+	Date    string `json:"date"`
+}
+
+type dateTimeOrdinalDate struct {
+	Year int    `json:"year"`
+	Day  int    `json:"day"`
+	Date string `json:"date"`
+}
+
+type dateTimeCalendarDate struct {
+	Year      int    `json:"year"`
+	Month     int    `json:"month"`
+	Day       int    `json:"day"`
+	MonthName string `json:"month_name"`
+	LeapYear  bool   `json:"leap_year"`
+	Date      string `json:"date"`
+	Formatted string `json:"formatted"`
+}
+
+type dateTimeChineseDate struct {
+	Year       int    `json:"year"`
+	Cycle      int    `json:"cycle"`
+	CycleYear  int    `json:"cycle_year"`
+	Month      int    `json:"month"`
+	LeapMonth  bool   `json:"leap_month"`
+	Day        int    `json:"day"`
+	StemBranch string `json:"stem_branch"`
+	Zodiac     string `json:"zodiac"`
+	Date       string `json:"date"`
+	Formatted  string `json:"formatted"`
+}
+
+type dateTimeCalendars struct {
+	Gregorian dateTimeGregorianDate `json:"gregorian"`
+	ISOWeek   dateTimeISOWeekDate   `json:"iso_week"`
+	Ordinal   dateTimeOrdinalDate   `json:"ordinal"`
+	Hebrew    dateTimeCalendarDate  `json:"hebrew"`
+	Islamic   dateTimeCalendarDate  `json:"islamic"`
+	Persian   *dateTimeCalendarDate `json:"persian"`
+	Chinese   *dateTimeChineseDate  `json:"chinese"`
+}
+
+type dateTimeEpochs struct {
+	Unix              float64  `json:"unix"`
+	UnixMS            int64    `json:"unix_ms"`
+	JulianDay         float64  `json:"julian_day"`
+	ModifiedJulianDay float64  `json:"modified_julian_day"`
+	NTP               float64  `json:"ntp"`
+	GPS               float64  `json:"gps"`
+	GPSWeek           int      `json:"gps_week"`
+	TAI               float64  `json:"tai"`
+	TAIUTCOffset      int      `json:"tai_utc_offset"`
+	Filetime          int64    `json:"filetime"`
+	DotnetTicks       int64    `json:"dotnet_ticks"`
+	ExcelSerial       *float64 `json:"excel_serial"`
+}
+
+type dateTimeCalendarConversion struct {
+	Input     string            `json:"input"`
+	From      string            `json:"from"`
+	Timezone  string            `json:"timezone"`
+	Datetime  dateTimeUnix      `json:"datetime"`
+	Calendars dateTimeCalendars `json:"calendars"`
+	Epochs    dateTimeEpochs    `json:"epochs"`
+}
+
+func addDateTimeFields(b *typeBuilder, query map[string]*Field) {
+	define(query, "datetimeNow", &Field{
+		Type:        b.ref((*dateTimeNow)(nil)),
+		Description: "Current time in a timezone",
+		Args: map[string]*Argument{
+			"timezone": arg("String", "IANA timezone (default UTC)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return remapAs[dateTimeNow](datetime.Now(optStringArg(args, "timezone", "UTC")))
+		},
+	})
+
+	define(query, "datetimeFromUnix", &Field{
+		Type:        b.ref((*dateTimeUnix)(nil)),
+		Description: "Unix timestamp in common formats",
+		Args: map[string]*Argument{
+			"timestamp": arg("String!", "Unix timestamp (seconds)"),
+			"timezone":  arg("String", "IANA timezone (default UTC)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ts, err := unixArg(args, "timestamp")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeUnix](datetime.FromUnix(ts, optStringArg(args, "timezone", "UTC")))
+		},
+	})
+
+	define(query, "datetimeToUnix", &Field{
+		Type:        "String!",
+		Description: "Unix timestamp of a date/time string",
+		Args: map[string]*Argument{
+			"datetime": arg("String!", "Date/time, e.g. 2024-01-02T15:04:05Z"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			value, err := stringArg(args, "datetime")
+			if err != nil {
+				return nil, err
+			}
+			unix, err := datetime.ToUnix(value)
+			if err != nil {
+				return nil, err
+			}
+			return strconv.FormatInt(unix, 10), nil
+		},
+	})
+
+	define(query, "datetimeConvert", &Field{
+		Type:        b.ref((*dateTimeConversion)(nil)),
+		Description: "Convert a Unix timestamp between timezones",
+		Args: map[string]*Argument{
+			"timestamp": arg("String!", "Unix timestamp (seconds)"),
+			"from":      arg("String!", "Source IANA timezone"),
+			"to":        arg("String!", "Target IANA timezone"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ts, err := unixArg(args, "timestamp")
+			if err != nil {
+				return nil, err
+			}
+			from, err := stringArg(args, "from")
+			if err != nil {
+				return nil, err
+			}
+			to, err := stringArg(args, "to")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeConversion](datetime.ConvertTimezone(ts, from, to))
+		},
+	})
+
+	define(query, "datetimeDiff", &Field{
+		Type:        b.ref((*dateTimeDiff)(nil)),
+		Description: "Difference between two Unix timestamps",
+		Args: map[string]*Argument{
+			"from": arg("String!", "Start Unix timestamp"),
+			"to":   arg("String!", "End Unix timestamp"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			from, err := unixArg(args, "from")
+			if err != nil {
+				return nil, err
+			}
+			to, err := unixArg(args, "to")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeDiff](datetime.Diff(from, to), nil)
+		},
+	})
+
+	define(query, "datetimeAdd", &Field{
+		Type:        b.ref((*dateTimeAdd)(nil)),
+		Description: "Add a Go duration (e.g. 36h, -90m) to a Unix timestamp",
+		Args: map[string]*Argument{
+			"timestamp": arg("String!", "Unix timestamp (seconds)"),
+			"duration":  arg("String!", "Duration"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ts, err := unixArg(args, "timestamp")
+			if err != nil {
+				return nil, err
+			}
+			duration, err := stringArg(args, "duration")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeAdd](datetime.AddDuration(ts, duration))
+		},
+	})
+
+	define(query, "datetimeTimezone", &Field{
+		Type:        b.ref((*dateTimeZone)(nil)),
+		Description: "Current offset and local time of a timezone",
+		Args: map[string]*Argument{
+			"name": arg("String!", "IANA timezone"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			name, err := stringArg(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeZone](datetime.TimezoneInfo(name))
+		},
+	})
+
+	define(query, "datetimeFormat", &Field{
+		Type:        "String!",
+		Description: "Format a Unix timestamp with a named or Go layout",
+		Args: map[string]*Argument{
+			"timestamp": arg("String!", "Unix timestamp (seconds)"),
+			"format":    arg("String!", "Layout name (iso8601, rfc2822, ...) or Go layout"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ts, err := unixArg(args, "timestamp")
+			if err != nil {
+				return nil, err
+			}
+			format, err := stringArg(args, "format")
+			if err != nil {
+				return nil, err
+			}
+			result, err := datetime.FormatDatetime(ts, format)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprint(result["result"]), nil
+		},
+	})
+
+	define(query, "datetimeParse", &Field{
+		Type:        b.ref((*dateTimeParsed)(nil)),
+		Description: "Parse a date string in any common layout, a natural-language expression, or an ISO 8601 duration or interval",
+		Args: map[string]*Argument{
+			"value":     arg("String!", "Date string, e.g. 2024-01-15, next tuesday 3pm, 2h45m ago or 2024-01-01/P1M"),
+			"reference": arg("String", "RFC3339 time relative expressions are resolved against (default now)"),
+			"timezone":  arg("String", "IANA timezone for times without a zone (default UTC)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			value, err := stringArg(args, "value")
+			if err != nil {
+				return nil, err
+			}
+			opts, err := parseOptionsArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeParsed](datetime.ParseDateString(value, opts))
+		},
+	})
+
+	define(query, "datetimeCalendar", &Field{
+		Type:        b.ref((*dateTimeCalendar)(nil)),
+		Description: "Week grid of a month (weeks start on Sunday, padding days are 0) with its holidays and business days",
+		Args: map[string]*Argument{
+			"year":    arg("Int!", "Year"),
+			"month":   arg("Int!", "Month 1-12"),
+			"country": arg("String", "Holiday calendar country code (see datetimeHolidayCalendars)"),
+			"region":  arg("String", "Holiday calendar region code"),
+			"weekend": arg("String", "Comma-separated weekend days (default sat,sun; \"none\" for no weekend)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			year, err := intArg(args, "year")
+			if err != nil {
+				return nil, err
+			}
+			month, err := intArg(args, "month")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeCalendar](datetime.GenerateCalendar(year, month, businessOptionsArgs(args)))
+		},
+	})
+
+	define(query, "datetimeWorkdays", &Field{
+		Type:        b.ref((*dateTimeWorkdays)(nil)),
+		Description: "Business days between two dates, inclusive, skipping weekends and public holidays",
+		Args: map[string]*Argument{
+			"start":   arg("String!", "Start date YYYY-MM-DD"),
+			"end":     arg("String!", "End date YYYY-MM-DD"),
+			"country": arg("String", "Holiday calendar country code (see datetimeHolidayCalendars)"),
+			"region":  arg("String", "Holiday calendar region code"),
+			"weekend": arg("String", "Comma-separated weekend days (default sat,sun; \"none\" for no weekend)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			start, err := stringArg(args, "start")
+			if err != nil {
+				return nil, err
+			}
+			end, err := stringArg(args, "end")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeWorkdays](datetime.WorkdaysBetween(start, end, businessOptionsArgs(args)))
+		},
+	})
+
+	define(query, "datetimeAddBusinessDays", &Field{
+		Type:        b.ref((*dateTimeBusinessDays)(nil)),
+		Description: "Add (or, when negative, subtract) business days to a date",
+		Args: map[string]*Argument{
+			"date":    arg("String!", "Date YYYY-MM-DD"),
+			"days":    arg("Int!", fmt.Sprintf("Business days, -%d to %d", datetime.MaxBusinessDays, datetime.MaxBusinessDays)),
+			"country": arg("String", "Holiday calendar country code (see datetimeHolidayCalendars)"),
+			"region":  arg("String", "Holiday calendar region code"),
+			"weekend": arg("String", "Comma-separated weekend days (default sat,sun; \"none\" for no weekend)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			date, err := stringArg(args, "date")
+			if err != nil {
+				return nil, err
+			}
+			days, err := intArg(args, "days")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeBusinessDays](datetime.AddBusinessDays(date, days, businessOptionsArgs(args)))
+		},
+	})
+
+	define(query, "datetimeHolidays", &Field{
+		Type:        b.ref((*dateTimeHolidays)(nil)),
+		Description: "Public holidays of a country (and optionally one region) for a year",
+		Args: map[string]*Argument{
+			"country": arg("String!", "Country code, e.g. US or GB"),
+			"year":    arg("Int!", "Year"),
+			"region":  arg("String", "Region code, e.g. CA or ENG"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			country, err := stringArg(args, "country")
+			if err != nil {
+				return nil, err
+			}
+			year, err := intArg(args, "year")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeHolidays](datetime.Holidays(country, optStringArg(args, "region", ""), year))
+		},
+	})
+
+	define(query, "datetimeHolidayCalendars", &Field{
+		Type:        b.ref((*[]dateTimeHolidayCalendar)(nil)),
+		Description: "Supported holiday calendars with their region codes",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			var calendars []dateTimeHolidayCalendar
+			if err := remap(datetime.HolidayCalendars(), &calendars); err != nil {
+				return nil, err
+			}
+			return calendars, nil
+		},
+	})
+
+	define(query, "datetimeSunriseSunset", &Field{
+		Type:        b.ref((*dateTimeSun)(nil)),
+		Description: "Sunrise and sunset (UTC) at a point",
+		Args: map[string]*Argument{
+			"lat":  arg("Float!", "Latitude"),
+			"lon":  arg("Float!", "Longitude"),
+			"date": arg("String", "Date YYYY-MM-DD (default today)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, err := floatArg(args, "lat")
+			if err != nil {
+				return nil, err
+			}
+			lon, err := floatArg(args, "lon")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeSun](datetime.SunriseSunset(lat, lon, optStringArg(args, "date", "")))
+		},
+	})
+
+	define(query, "datetimeMoonPhase", &Field{
+		Type:        b.ref((*dateTimeMoon)(nil)),
+		Description: "Moon phase and illumination on a date",
+		Args: map[string]*Argument{
+			"date": arg("String", "Date YYYY-MM-DD (default today)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return remapAs[dateTimeMoon](datetime.MoonPhase(optStringArg(args, "date", "")))
+		},
+	})
+
+	define(query, "datetimeCron", &Field{
+		Type:        b.ref((*dateTimeCron)(nil)),
+		Description: "Fields, description and next and previous runs of a cron expression",
+		Args: map[string]*Argument{
+			"expression": arg("String!", "Cron expression: 5, 6 (seconds) or 7 (seconds, year) fields, or a macro such as @daily"),
+			"timezone":   arg("String", "IANA timezone the schedule runs in (default UTC)"),
+			"count":      arg("Int", "Number of runs to list, 1-100 (default 5)"),
+			"start":      arg("String", "List runs after this RFC3339 time (default now)"),
+			"end":        arg("String", "List runs up to this RFC3339 time"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			expr, err := stringArg(args, "expression")
+			if err != nil {
+				return nil, err
+			}
+			opts := datetime.CronOptions{Timezone: optStringArg(args, "timezone", "")}
+			if opts.Count, err = optIntArg(args, "count", 0); err != nil {
+				return nil, err
+			}
+			if opts.From, err = optTimeArg(args, "start"); err != nil {
+				return nil, err
+			}
+			if opts.Until, err = optTimeArg(args, "end"); err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeCron](datetime.ParseCron(expr, opts))
+		},
+	})
+
+	define(query, "datetimeRRule", &Field{
+		Type:        b.ref((*dateTimeRRule)(nil)),
+		Description: "Occurrences of an RFC 5545 recurrence rule in a timezone",
+		Args: map[string]*Argument{
+			"rule":     arg("String!", "Recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"),
+			"start":    arg("String!", "First occurrence: local time in the timezone, a date, or RFC3339"),
+			"timezone": arg("String", "IANA timezone (default UTC)"),
+			"exdates":  arg("[String!]", "Excluded times, or dates to exclude whole days"),
+			"count":    arg("Int", "Number of occurrences to list, 1-1000 (default 10)"),
+			"from":     arg("String", "List occurrences from this RFC3339 time"),
+			"to":       arg("String", "List occurrences up to this RFC3339 time"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			rule, err := stringArg(args, "rule")
+			if err != nil {
+				return nil, err
+			}
+			opts := datetime.RRuleOptions{Timezone: optStringArg(args, "timezone", "")}
+			if opts.Start, err = stringArg(args, "start"); err != nil {
+				return nil, err
+			}
+			if opts.Exdates, err = optStringListArg(args, "exdates"); err != nil {
+				return nil, err
+			}
+			if opts.Count, err = optIntArg(args, "count", 0); err != nil {
+				return nil, err
+			}
+			if opts.From, err = optTimeArg(args, "from"); err != nil {
+				return nil, err
+			}
+			if opts.Until, err = optTimeArg(args, "to"); err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeRRule](datetime.ExpandRRule(rule, opts))
+		},
+	})
+
+	define(query, "datetimeICSParse", &Field{
+		Type:        b.ref((*dateTimeICS)(nil)),
+		Description: "Events, to-dos and timezones of an iCalendar (.ics) file",
+		Args: map[string]*Argument{
+			"ics":   arg("String!", "iCalendar text"),
+			"count": arg("Int", "Occurrences to list per recurring item, 1-100 (default 5)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			ics, err := stringArg(args, "ics")
+			if err != nil {
+				return nil, err
+			}
+			count, err := optIntArg(args, "count", 0)
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[dateTimeICS](datetime.ParseICS(ics, count))
+		},
+	})
+
+	define(query, "datetimeCalendarConvert", &Field{
+		Type:        b.ref((*dateTimeCalendarConversion)(nil)),
+		Description: "A date or timestamp in every supported calendar and time scale",
+		Args: map[string]*Argument{
+			"value":    arg("String!", "Date or number in the from system, e.g. 5787-Tishrei-1 or 2460000.5"),
+			"from":     arg("String", "gregorian (default), iso_week, ordinal, julian_day, modified_julian_day, unix, unix_ms, ntp, gps, tai, filetime, dotnet_ticks, excel_serial, hebrew, islamic, persian or chinese"),
+			"timezone": arg("String", "IANA timezone calendar dates are read and reported in (default UTC)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			value, err := stringArg(args, "value")
+			if err != nil {
+				return nil, err
+			}
+			opts := datetime.CalendarOptions{From: optStringArg(args, "from", ""), Timezone: optStringArg(args, "timezone", "")}
+			return remapAs[dateTimeCalendarConversion](datetime.ConvertCalendar(value, opts))
+		},
+	})
+}
+
+// unixArg reads a Unix timestamp passed as a string, since timestamps
+// overflow GraphQL's 32-bit Int.
+func unixArg(args map[string]interface{}, name string) (int64, error) {
+	raw, err := stringArg(args, name)
+	if err != nil {
+		return 0, err
+	}
+	ts, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", raw)
+	}
+	return ts, nil
+}
+
+// optTimeArg reads an optional RFC3339 timestamp, returning the zero time
+// when it is absent.
+func optTimeArg(args map[string]interface{}, name string) (time.Time, error) {
+	raw := optStringArg(args, name, "")
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("argument %q must be an RFC3339 timestamp", name)
+	}
+	return t, nil
+}
+
+// parseOptionsArgs reads the optional reference and timezone arguments
+// shared by the date-parsing fields.
+func parseOptionsArgs(args map[string]interface{}) (datetime.ParseOptions, error) {
+	opts := datetime.ParseOptions{Timezone: optStringArg(args, "timezone", "")}
+	var err error
+	opts.Reference, err = optTimeArg(args, "reference")
+	return opts, err
+}
+
+// businessOptionsArgs reads the optional country, region and weekend
+// arguments shared by the business-day fields.
+func businessOptionsArgs(args map[string]interface{}) datetime.BusinessOptions {
+	return datetime.BusinessOptions{
+		Country: optStringArg(args, "country", ""),
+		Region:  optStringArg(args, "region", ""),
+		Weekend: optStringArg(args, "weekend", ""),
+	}
+}
@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apimgr/api/src/service/dev"
+)
+
+func addDevFields(b *typeBuilder, query map[string]*Field) {
+	svc := dev.New()
+
+	plain := func(fn func(string) string) func(string) (interface{}, error) {
+		return func(s string) (interface{}, error) { return fn(s), nil }
+	}
+	checked := func(fn func(string) (string, error)) func(string) (interface{}, error) {
+		return func(s string) (interface{}, error) { return fn(s) }
+	}
+	formatters := map[string]map[string]func(string) (interface{}, error){
+		"format": {
+			"json": checked(svc.FormatJSON), "xml": checked(svc.FormatXML),
+			"css": plain(svc.FormatCSS), "html": plain(svc.FormatHTML),
+			"js": plain(svc.FormatJS), "sql": plain(svc.FormatSQL),
+		},
+		"minify": {
+			"json": checked(svc.MinifyJSON), "xml": checked(svc.MinifyXML),
+			"css": plain(svc.MinifyCSS), "html": plain(svc.MinifyHTML),
+			"js": plain(svc.MinifyJS),
+		},
+	}
+	for _, mode := range []string{"format", "minify"} {
+		languages := formatters[mode]
+		define(query, "dev"+upperFirst(mode), &Field{
+			Type:        "String!",
+			Description: upperFirst(mode) + " source code (" + strings.Join(sortedKeys(languages), ", ") + ")",
+			Args: map[string]*Argument{
+				"code":     arg("String!", "Source code"),
+				"language": arg("String!", "Source language"),
+			},
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				code, err := stringArg(args, "code")
+				if err != nil {
+					return nil, err
+				}
+				language, err := stringArg(args, "language")
+				if err != nil {
+					return nil, err
+				}
+				fn, ok := languages[strings.ToLower(language)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported language: %s", language)
+				}
+				return fn(code)
+			},
+		})
+	}
+
+	for name, fn := range map[string]struct {
+		description string
+		apply       func(string) string
+	}{
+		"devEscapeHTML":       {"Escape HTML special characters", svc.EscapeHTML},
+		"devUnescapeHTML":     {"Unescape HTML entities", svc.UnescapeHTML},
+		"devEscapeSQL":        {"Escape a SQL string literal", svc.EscapeSQL},
+		"devEscapeRegex":      {"Escape regular expression metacharacters", svc.EscapeRegex},
+		"devRemoveEmptyLines": {"Remove blank lines", svc.RemoveEmptyLines},
+		"devNumberLines":      {"Prefix each line with its number", svc.NumberLines},
+	} {
+		defineUnary(query, name, fn.description, "String!", "text", "Input text", plain(fn.apply))
+	}
+
+	define(query, "devIndent", &Field{
+		Type:        "String!",
+		Description: "Indent (or, with a negative count, dedent) every line",
+		Args: map[string]*Argument{
+			"code":   arg("String!", "Source code"),
+			"spaces": arg("Int!", "Spaces to add; negative removes"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			code, err := stringArg(args, "code")
+			if err != nil {
+				return nil, err
+			}
+			spaces, err := intArg(args, "spaces")
+			if err != nil {
+				return nil, err
+			}
+			if spaces < 0 {
+				return svc.Dedent(code, -spaces), nil
+			}
+			return svc.Indent(code, spaces), nil
+		},
+	})
+}
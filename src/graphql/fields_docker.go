@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"github.com/apimgr/api/src/service/docker"
+)
+
+type dockerPortMapping struct {
+	HostPort      int    `json:"host_port"`
+	ContainerPort int    `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+func addDockerFields(b *typeBuilder, query map[string]*Field) {
+	svc := docker.New()
+
+	defineUnary(query, "dockerImage", "Registry, namespace, repository and tag of an image reference", b.ref((*docker.ImageInfo)(nil)),
+		"image", "Image reference, e.g. ghcr.io/org/app:1.2",
+		func(image string) (interface{}, error) { return svc.ParseImageName(image), nil })
+
+	defineUnary(query, "dockerContainerNameValid", "Whether a string is a valid container name", "Boolean!",
+		"name", "Container name",
+		func(name string) (interface{}, error) { return svc.IsValidContainerName(name), nil })
+
+	defineUnary(query, "dockerPortMapping", "Parse a host:container[/protocol] port mapping", b.ref((*dockerPortMapping)(nil)),
+		"mapping", "Port mapping, e.g. 8080:80/tcp",
+		func(mapping string) (interface{}, error) {
+			host, container, protocol, err := svc.ParsePortMapping(mapping)
+			if err != nil {
+				return nil, err
+			}
+			return dockerPortMapping{HostPort: host, ContainerPort: container, Protocol: protocol}, nil
+		})
+
+	defineUnary(query, "dockerLint", "Common Dockerfile anti-patterns", b.ref((*docker.DockerfileLintResult)(nil)),
+		"dockerfile", "Dockerfile contents",
+		func(content string) (interface{}, error) { return svc.LintDockerfile(content), nil })
+
+	defineUnary(query, "dockerSecurityScan", "Security misconfigurations in a Dockerfile or compose file", b.ref((*docker.SecurityScanResult)(nil)),
+		"content", "Dockerfile or docker-compose.yml contents",
+		func(content string) (interface{}, error) { return svc.ScanSecurity(content), nil })
+
+	defineUnary(query, "dockerOptimizeSize", "Image-size reduction suggestions for a Dockerfile", b.ref((*docker.SizeOptimizationResult)(nil)),
+		"dockerfile", "Dockerfile contents",
+		func(content string) (interface{}, error) { return svc.OptimizeSize(content), nil })
+
+	defineUnary(query, "dockerValidateCompose", "Structural errors and warnings in a compose file", b.ref((*docker.ComposeValidationResult)(nil)),
+		"compose", "docker-compose.yml contents",
+		func(content string) (interface{}, error) { return svc.ValidateCompose(content), nil })
+
+	defineUnary(query, "dockerParseEnv", "Variables in a .env file", b.ref((*docker.EnvParseResult)(nil)),
+		"content", ".env file contents",
+		func(content string) (interface{}, error) { return svc.ParseEnvFile(content), nil })
+
+	defineUnary(query, "dockerRunToCompose", "Convert a docker run command to a compose file", "String!",
+		"command", "docker run command line",
+		func(command string) (interface{}, error) { return svc.RunCommandToCompose(command) })
+
+	define(query, "dockerComposeToRun", &Field{
+		Type:        "String!",
+		Description: "Convert a compose service to a docker run command",
+		Args: map[string]*Argument{
+			"compose": arg("String!", "docker-compose.yml contents"),
+			"service": arg("String", "Service name (default: the only service)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			compose, err := stringArg(args, "compose")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ComposeToRunCommand(compose, optStringArg(args, "service", ""))
+		},
+	})
+
+	define(query, "dockerBestPractices", &Field{
+		Type:        b.ref(([]docker.DockerBestPractice)(nil)),
+		Description: "Curated Docker best practices",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return svc.BestPracticesGuide(), nil
+		},
+	})
+}
@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/apimgr/api/src/service/fun"
+)
+
+// funMaxDice bounds the number of dice rolled at once.
+const funMaxDice = 100
+
+type diceRoll struct {
+	Sides int   `json:"sides"`
+	Rolls []int `json:"rolls"`
+	Total int   `json:"total"`
+}
+
+func addFunFields(b *typeBuilder, query map[string]*Field) {
+	svc := fun.New()
+
+	for name, fn := range map[string]struct {
+		description string
+		generate    func() (string, error)
+	}{
+		"funCoinFlip":        {"Heads or tails", svc.CoinFlip},
+		"funMagic8Ball":      {"Magic 8-ball answer", svc.Magic8Ball},
+		"funFortune":         {"Fortune cookie", svc.Fortune},
+		"funYesOrNo":         {"Yes or no", svc.YesOrNo},
+		"funEmoji":           {"Random emoji", svc.RandomEmoji},
+		"funDadJoke":         {"Dad joke", svc.DadJoke},
+		"funProgrammingJoke": {"Programming joke", svc.ProgrammingJoke},
+		"funQuote":           {"Quote", svc.Quote},
+		"funFact":            {"Fun fact", svc.Fact},
+		"funMotivational":    {"Motivational quote", svc.Motivational},
+		"funInsult":          {"Light-hearted insult", svc.Insult},
+		"funCompliment":      {"Compliment", svc.Compliment},
+		"funMeme":            {"Meme caption", svc.Meme},
+	} {
+		define(query, name, &Field{
+			Type:        "String!",
+			Description: fn.description,
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				return fn.generate()
+			},
+		})
+	}
+
+	for name, fn := range map[string]struct {
+		description string
+		generate    func() (fun.QAPair, error)
+	}{
+		"funRiddle": {"Riddle and answer", svc.Riddle},
+		"funTrivia": {"Trivia question and answer", svc.Trivia},
+	} {
+		define(query, name, &Field{
+			Type:        b.ref((*fun.QAPair)(nil)),
+			Description: fn.description,
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				return fn.generate()
+			},
+		})
+	}
+
+	define(query, "funDice", &Field{
+		Type:        b.ref((*diceRoll)(nil)),
+		Description: "Roll dice",
+		Args: map[string]*Argument{
+			"count": arg("Int", fmt.Sprintf("Number of dice 1-%d (default 1)", funMaxDice)),
+			"sides": arg("Int", "Sides per die (default 6)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			count, err := optIntArg(args, "count", 1)
+			if err != nil {
+				return nil, err
+			}
+			if count > funMaxDice {
+				return nil, fmt.Errorf("count must be at most %d", funMaxDice)
+			}
+			sides, err := optIntArg(args, "sides", 6)
+			if err != nil {
+				return nil, err
+			}
+			rolls, err := svc.RollMultipleDice(count, sides)
+			if err != nil {
+				return nil, err
+			}
+			total := 0
+			for _, r := range rolls {
+				total += r
+			}
+			return diceRoll{Sides: sides, Rolls: rolls, Total: total}, nil
+		},
+	})
+
+	define(query, "funChoice", &Field{
+		Type:        "String!",
+		Description: "Random pick from a list",
+		Args: map[string]*Argument{
+			"options": arg("[String!]!", "Options"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			options, err := stringListArg(args, "options")
+			if err != nil {
+				return nil, err
+			}
+			return svc.RandomChoice(options)
+		},
+	})
+
+	define(query, "funShuffle", &Field{
+		Type:        "[String!]!",
+		Description: "Shuffle a list",
+		Args: map[string]*Argument{
+			"items": arg("[String!]!", "Items"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			items, err := stringListArg(args, "items")
+			if err != nil {
+				return nil, err
+			}
+			return svc.Shuffle(items)
+		},
+	})
+
+	defineUnary(query, "funRockPaperScissors", "Play rock, paper, scissors", "String!",
+		"choice", "rock, paper or scissors",
+		func(choice string) (interface{}, error) { return svc.RockPaperScissors(choice) })
+}
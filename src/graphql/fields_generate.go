@@ -0,0 +1,247 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/apimgr/api/src/service/crypto"
+	"github.com/apimgr/api/src/service/generate"
+)
+
+// generateMaxLength bounds random strings, tokens and passwords.
+const generateMaxLength = 4096
+
+// generateMaxImageSize bounds QR, barcode, avatar and identicon dimensions.
+const generateMaxImageSize = 2048
+
+type generateSSHKeyPair struct {
+	PrivateKey   string                 `json:"private_key"`
+	PublicKey    string                 `json:"public_key"`
+	Fingerprints crypto.KeyFingerprints `json:"fingerprints"`
+}
+
+func addGenerateFields(b *typeBuilder, query map[string]*Field) {
+	svc := generate.New()
+
+	// length reads an optional length argument within generateMaxLength.
+	length := func(args map[string]interface{}, def int) (int, error) {
+		n, err := optIntArg(args, "length", def)
+		if err != nil {
+			return 0, err
+		}
+		if n < 1 || n > generateMaxLength {
+			return 0, fmt.Errorf("length must be between 1 and %d", generateMaxLength)
+		}
+		return n, nil
+	}
+	// size reads an optional square image size within generateMaxImageSize.
+	size := func(args map[string]interface{}, name string, def int) (int, error) {
+		n, err := optIntArg(args, name, def)
+		if err != nil {
+			return 0, err
+		}
+		if n < 1 || n > generateMaxImageSize {
+			return 0, fmt.Errorf("%s must be between 1 and %d", name, generateMaxImageSize)
+		}
+		return n, nil
+	}
+
+	for name, fn := range map[string]struct {
+		description string
+		generate    func(int) (string, error)
+		def         int
+	}{
+		"generateRandomString": {"Random string of letters, digits and symbols", svc.RandomString, 32},
+		"generateRandomAlpha":  {"Random letters", svc.RandomAlpha, 32},
+		"generateRandomDigits": {"Random digits", svc.RandomNumeric, 8},
+		"generateRandomAlnum":  {"Random letters and digits", svc.RandomAlphanumeric, 32},
+		"generateRandomHex":    {"Random hex string", svc.RandomHex, 32},
+		"generateToken":        {"URL-safe random token from length random bytes", svc.Token, 32},
+	} {
+		define(query, name, &Field{
+			Type:        "String!",
+			Description: fn.description,
+			Args: map[string]*Argument{
+				"length": arg("Int", fmt.Sprintf("Length (default %d)", fn.def)),
+			},
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				n, err := length(args, fn.def)
+				if err != nil {
+					return nil, err
+				}
+				return fn.generate(n)
+			},
+		})
+	}
+
+	for name, fn := range map[string]struct {
+		description string
+		generate    func() (string, error)
+	}{
+		"generateAPIKey":    {"Prefixed API key", svc.APIKey},
+		"generateNonce":     {"Random nonce", svc.Nonce},
+		"generateMAC":       {"Random locally administered MAC address", svc.RandomMAC},
+		"generateIPv4":      {"Random public IPv4 address", svc.RandomIPv4},
+		"generateColor":     {"Random hex color", func() (string, error) { return svc.RandomColor(), nil }},
+		"generateUUIDv4":    {"Random UUID v4", func() (string, error) { return svc.UUIDv4(), nil }},
+		"generateTimestamp": {"Current Unix time in milliseconds", func() (string, error) { return fmt.Sprint(svc.TimestampMillis()), nil }},
+	} {
+		define(query, name, &Field{
+			Type:        "String!",
+			Description: fn.description,
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				return fn.generate()
+			},
+		})
+	}
+
+	define(query, "generatePassword", &Field{
+		Type:        "String!",
+		Description: "Random password",
+		Args: map[string]*Argument{
+			"length":  arg("Int", "Length (default 16)"),
+			"special": arg("Boolean", "Include symbols (default true)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			n, err := length(args, 16)
+			if err != nil {
+				return nil, err
+			}
+			return svc.Password(n, optBoolArg(args, "special", true))
+		},
+	})
+
+	defineUnary(query, "generateSlug", "URL slug of a title", "String!", "text", "Title",
+		func(text string) (interface{}, error) { return svc.Slug(text), nil })
+
+	define(query, "generateSSHKey", &Field{
+		Type:        b.ref((*generateSSHKeyPair)(nil)),
+		Description: "New Ed25519 SSH key pair in OpenSSH format",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			pair, err := svc.SSHKey()
+			if err != nil {
+				return nil, err
+			}
+			return generateSSHKeyPair{PrivateKey: pair.PrivateKey, PublicKey: pair.PublicKey, Fingerprints: pair.Fingerprints}, nil
+		},
+	})
+
+	define(query, "generateQR", &Field{
+		Type:        "String!",
+		Description: "Base64-encoded PNG QR code",
+		Args: map[string]*Argument{
+			"content": arg("String!", "Encoded content"),
+			"size":    arg("Int", "Width and height in pixels (default 256)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			content, err := stringArg(args, "content")
+			if err != nil {
+				return nil, err
+			}
+			n, err := size(args, "size", 256)
+			if err != nil {
+				return nil, err
+			}
+			return encodeImage(svc.QR(content, n, n))
+		},
+	})
+
+	define(query, "generateBarcode", &Field{
+		Type:        "String!",
+		Description: "Base64-encoded PNG barcode",
+		Args: map[string]*Argument{
+			"data":   arg("String!", "Encoded data"),
+			"format": arg("String", "code128, code39, ean13 or upca (default code128)"),
+			"width":  arg("Int", "Width in pixels (default 300)"),
+			"height": arg("Int", "Height in pixels (default 100)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			data, err := stringArg(args, "data")
+			if err != nil {
+				return nil, err
+			}
+			width, err := size(args, "width", 300)
+			if err != nil {
+				return nil, err
+			}
+			height, err := size(args, "height", 100)
+			if err != nil {
+				return nil, err
+			}
+			return encodeImage(svc.Barcode(optStringArg(args, "format", "code128"), data, width, height))
+		},
+	})
+
+	define(query, "generateAvatar", &Field{
+		Type:        "String!",
+		Description: "Base64-encoded PNG initials avatar",
+		Args: map[string]*Argument{
+			"initials": arg("String!", "Initials"),
+			"size":     arg("Int", "Width and height in pixels (default 128)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			initials, err := stringArg(args, "initials")
+			if err != nil {
+				return nil, err
+			}
+			n, err := size(args, "size", 128)
+			if err != nil {
+				return nil, err
+			}
+			return encodeImage(svc.Avatar(initials, n))
+		},
+	})
+
+	define(query, "generateIdenticon", &Field{
+		Type:        "String!",
+		Description: "Base64-encoded PNG identicon",
+		Args: map[string]*Argument{
+			"seed": arg("String!", "Seed, e.g. a username"),
+			"size": arg("Int", "Width and height in pixels (default 128)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			seed, err := stringArg(args, "seed")
+			if err != nil {
+				return nil, err
+			}
+			n, err := size(args, "size", 128)
+			if err != nil {
+				return nil, err
+			}
+			return encodeImage(svc.Identicon(seed, n))
+		},
+	})
+
+	defineUnary(query, "generateGitignore", ".gitignore for a comma-separated list of languages", "String!",
+		"languages", "Languages, e.g. go,node",
+		func(langs string) (interface{}, error) { return svc.Gitignore(langs) })
+
+	defineUnary(query, "generateDockerfile", "Starter Dockerfile for a language", "String!",
+		"language", "Language, e.g. go",
+		func(lang string) (interface{}, error) { return svc.Dockerfile(lang) })
+
+	define(query, "generateLicense", &Field{
+		Type:        "String!",
+		Description: "License text",
+		Args: map[string]*Argument{
+			"type":   arg("String!", "mit, apache-2.0, gpl-3.0, bsd-3-clause or isc"),
+			"author": arg("String", "Copyright holder"),
+			"year":   arg("String", "Copyright year (default current year)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			licenseType, err := stringArg(args, "type")
+			if err != nil {
+				return nil, err
+			}
+			return svc.License(licenseType, optStringArg(args, "author", ""), optStringArg(args, "year", ""))
+		},
+	})
+}
+
+// encodeImage base64-encodes generated image bytes.
+func encodeImage(data []byte, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,282 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/apimgr/api/src/service/geo"
+)
+
+type geoDistance struct {
+	Kilometers float64 `json:"kilometers"`
+	Miles      float64 `json:"miles"`
+	Bearing    float64 `json:"bearing"`
+}
+
+func addGeoFields(b *typeBuilder, query map[string]*Field) {
+	svc := geo.New()
+
+	// coordinate reads and range-checks a latitude/longitude argument pair.
+	coordinate := func(args map[string]interface{}, latName, lonName string) (float64, float64, error) {
+		lat, err := floatArg(args, latName)
+		if err != nil {
+			return 0, 0, err
+		}
+		lon, err := floatArg(args, lonName)
+		if err != nil {
+			return 0, 0, err
+		}
+		if !svc.IsValidCoordinate(lat, lon) {
+			return 0, 0, fmt.Errorf("invalid coordinate %g,%g", lat, lon)
+		}
+		return lat, lon, nil
+	}
+	pointArgs := func() map[string]*Argument {
+		return map[string]*Argument{
+			"lat": arg("Float!", "Latitude"),
+			"lon": arg("Float!", "Longitude"),
+		}
+	}
+	pairArgs := func() map[string]*Argument {
+		return map[string]*Argument{
+			"lat1": arg("Float!", "First latitude"),
+			"lon1": arg("Float!", "First longitude"),
+			"lat2": arg("Float!", "Second latitude"),
+			"lon2": arg("Float!", "Second longitude"),
+		}
+	}
+
+	define(query, "geoDistance", &Field{
+		Type:        b.ref((*geoDistance)(nil)),
+		Description: "Great-circle distance and initial bearing between two points",
+		Args:        pairArgs(),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat1, lon1, err := coordinate(args, "lat1", "lon1")
+			if err != nil {
+				return nil, err
+			}
+			lat2, lon2, err := coordinate(args, "lat2", "lon2")
+			if err != nil {
+				return nil, err
+			}
+			return geoDistance{
+				Kilometers: svc.Distance(lat1, lon1, lat2, lon2),
+				Miles:      svc.DistanceInMiles(lat1, lon1, lat2, lon2),
+				Bearing:    svc.Bearing(lat1, lon1, lat2, lon2),
+			}, nil
+		},
+	})
+
+	define(query, "geoMidpoint", &Field{
+		Type:        b.ref((*geo.Coordinate)(nil)),
+		Description: "Geographic midpoint of two points",
+		Args:        pairArgs(),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat1, lon1, err := coordinate(args, "lat1", "lon1")
+			if err != nil {
+				return nil, err
+			}
+			lat2, lon2, err := coordinate(args, "lat2", "lon2")
+			if err != nil {
+				return nil, err
+			}
+			return svc.Midpoint(lat1, lon1, lat2, lon2), nil
+		},
+	})
+
+	destinationArgs := pointArgs()
+	destinationArgs["distance"] = arg("Float!", "Distance in kilometres")
+	destinationArgs["bearing"] = arg("Float!", "Initial bearing in degrees")
+	define(query, "geoDestination", &Field{
+		Type:        b.ref((*geo.Coordinate)(nil)),
+		Description: "Point reached by travelling distance km on a bearing",
+		Args:        destinationArgs,
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			distance, err := floatArg(args, "distance")
+			if err != nil {
+				return nil, err
+			}
+			bearing, err := floatArg(args, "bearing")
+			if err != nil {
+				return nil, err
+			}
+			return svc.Destination(lat, lon, distance, bearing), nil
+		},
+	})
+
+	bboxArgs := pointArgs()
+	bboxArgs["radius"] = arg("Float!", "Radius in kilometres")
+	define(query, "geoBoundingBox", &Field{
+		Type:        b.ref((*geo.BoundingBox)(nil)),
+		Description: "Bounding box around a point",
+		Args:        bboxArgs,
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			radius, err := floatArg(args, "radius")
+			if err != nil {
+				return nil, err
+			}
+			return svc.BoundingBoxFromRadius(lat, lon, radius)
+		},
+	})
+
+	define(query, "geoCountry", &Field{
+		Type:        b.ref((*geo.CountryInfo)(nil)),
+		Description: "Country by name or ISO 3166 code",
+		Args: map[string]*Argument{
+			"query": arg("String!", "Country name, alpha-2, alpha-3 or numeric code"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			q, err := stringArg(args, "query")
+			if err != nil {
+				return nil, err
+			}
+			return svc.Country(q)
+		},
+	})
+
+	define(query, "geoGeocode", &Field{
+		Type:        b.ref(([]*geo.GeocodeResult)(nil)),
+		Description: "Places matching a free-text address",
+		Args: map[string]*Argument{
+			"query": arg("String!", "Address or place name"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			q, err := stringArg(args, "query")
+			if err != nil {
+				return nil, err
+			}
+			return cachedLookup("geocode", geo.GeocodeCacheKey(q), func() ([]*geo.GeocodeResult, error) { return svc.Geocode(q) })
+		},
+	})
+
+	define(query, "geoReverse", &Field{
+		Type:        b.ref((*geo.ReverseGeocodeResult)(nil)),
+		Description: "Address at a point",
+		Args:        pointArgs(),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			return cachedLookup("geocode", geo.ReverseGeocodeCacheKey(lat, lon), func() (*geo.ReverseGeocodeResult, error) {
+				return svc.ReverseGeocode(lat, lon)
+			})
+		},
+	})
+
+	define(query, "geoTimezone", &Field{
+		Type:        b.ref((*geo.TimezoneResult)(nil)),
+		Description: "IANA timezone at a point",
+		Args:        pointArgs(),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			return svc.Timezone(lat, lon)
+		},
+	})
+
+	geohashArgs := pointArgs()
+	geohashArgs["precision"] = arg("Int", "Geohash length (default 9)")
+	define(query, "geoGeohash", &Field{
+		Type:        "String!",
+		Description: "Geohash of a point",
+		Args:        geohashArgs,
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			precision, err := optIntArg(args, "precision", 9)
+			if err != nil {
+				return nil, err
+			}
+			return svc.GeohashEncode(lat, lon, precision)
+		},
+	})
+
+	define(query, "geoGeohashDecode", &Field{
+		Type:        b.ref((*geo.Coordinate)(nil)),
+		Description: "Centre of a geohash cell",
+		Args: map[string]*Argument{
+			"hash": arg("String!", "Geohash"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			hash, err := stringArg(args, "hash")
+			if err != nil {
+				return nil, err
+			}
+			return svc.GeohashDecode(hash)
+		},
+	})
+
+	h3Args := pointArgs()
+	h3Args["resolution"] = arg("Int", "H3 resolution 0-15 (default 9)")
+	define(query, "geoH3", &Field{
+		Type:        b.ref((*geo.H3Result)(nil)),
+		Description: "H3 cell containing a point",
+		Args:        h3Args,
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			resolution, err := optIntArg(args, "resolution", 9)
+			if err != nil {
+				return nil, err
+			}
+			return svc.H3Encode(lat, lon, resolution)
+		},
+	})
+
+	define(query, "geoPlusCode", &Field{
+		Type:        b.ref((*geo.PlusCodeResult)(nil)),
+		Description: "Open Location Code (plus code) of a point",
+		Args:        pointArgs(),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lat, lon, err := coordinate(args, "lat", "lon")
+			if err != nil {
+				return nil, err
+			}
+			return svc.PlusCodeEncode(lat, lon)
+		},
+	})
+
+	define(query, "geoPlusCodeDecode", &Field{
+		Type:        b.ref((*geo.Coordinate)(nil)),
+		Description: "Centre of a plus code area",
+		Args: map[string]*Argument{
+			"code": arg("String!", "Full plus code"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			code, err := stringArg(args, "code")
+			if err != nil {
+				return nil, err
+			}
+			return svc.PlusCodeDecode(code)
+		},
+	})
+
+	define(query, "geoDMS", &Field{
+		Type:        "String!",
+		Description: "Decimal degrees as degrees/minutes/seconds",
+		Args: map[string]*Argument{
+			"decimal": arg("Float!", "Decimal degrees"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			decimal, err := floatArg(args, "decimal")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ToDMS(decimal), nil
+		},
+	})
+}
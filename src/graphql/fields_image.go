@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/apimgr/api/src/service/image"
+)
+
+// imageMaxPlaceholderPixels bounds placeholder dimensions (4096x4096).
+const imageMaxPlaceholderPixels = 4096 * 4096
+
+func addImageFields(b *typeBuilder, query map[string]*Field) {
+	define(query, "imageInfo", &Field{
+		Type:        b.ref((*image.ImageInfo)(nil)),
+		Description: "Dimensions and format of a base64-encoded PNG, JPEG or GIF",
+		Args: map[string]*Argument{
+			"data": arg("String!", "Base64-encoded image"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			encoded, err := stringArg(args, "data")
+			if err != nil {
+				return nil, err
+			}
+			data, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("data is not valid base64: %w", err)
+			}
+			svc := image.New()
+			if err := svc.Load(data); err != nil {
+				return nil, err
+			}
+			bounds := svc.Bounds()
+			return image.ImageInfo{
+				Width:  bounds.Dx(),
+				Height: bounds.Dy(),
+				Format: svc.Format(),
+				Size:   int64(len(data)),
+			}, nil
+		},
+	})
+
+	define(query, "imagePlaceholder", &Field{
+		Type:        "String!",
+		Description: "Base64-encoded placeholder image",
+		Args: map[string]*Argument{
+			"width":  arg("Int!", "Width in pixels"),
+			"height": arg("Int!", "Height in pixels"),
+			"format": arg("String", "png, jpeg or gif (default png)"),
+			"color":  arg("String", "Background hex color (default #cccccc)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			width, err := intArg(args, "width")
+			if err != nil {
+				return nil, err
+			}
+			height, err := intArg(args, "height")
+			if err != nil {
+				return nil, err
+			}
+			if width*height > imageMaxPlaceholderPixels {
+				return nil, fmt.Errorf("placeholder must be at most %d pixels", imageMaxPlaceholderPixels)
+			}
+			data, err := image.New().GeneratePlaceholder(width, height, optStringArg(args, "format", "png"), optStringArg(args, "color", "cccccc"))
+			if err != nil {
+				return nil, err
+			}
+			return base64.StdEncoding.EncodeToString(data), nil
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/apimgr/api/src/service/language"
+)
+
+// languageLookupTimeout bounds dictionary and thesaurus lookups.
+const languageLookupTimeout = 15 * time.Second
+
+type languageName struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func addLanguageFields(b *typeBuilder, query map[string]*Field) {
+	svc := language.New()
+
+	defineUnary(query, "languageDictionary", "Phonetics and definitions of an English word", b.ref((*language.DictionaryResult)(nil)),
+		"word", "English word",
+		func(word string) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), languageLookupTimeout)
+			defer cancel()
+			return svc.Dictionary(ctx, word)
+		})
+
+	defineUnary(query, "languageThesaurus", "Synonyms and antonyms of an English word", b.ref((*language.ThesaurusResult)(nil)),
+		"word", "English word",
+		func(word string) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), languageLookupTimeout)
+			defer cancel()
+			return svc.Thesaurus(ctx, word)
+		})
+
+	define(query, "languages", &Field{
+		Type:        b.ref(([]languageName)(nil)),
+		Description: "ISO 639-1 language codes and names",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			names := svc.ListLanguages()
+			out := make([]languageName, 0, len(names))
+			for _, code := range sortedKeys(names) {
+				out = append(out, languageName{Code: code, Name: names[code]})
+			}
+			return out, nil
+		},
+	})
+
+	defineUnary(query, "languageName", "English name of an ISO 639-1 code", b.ref((*languageName)(nil)),
+		"code", "ISO 639-1 code, e.g. fr",
+		func(code string) (interface{}, error) {
+			name, err := svc.GetLanguageName(code)
+			if err != nil {
+				return nil, err
+			}
+			return languageName{Code: code, Name: name}, nil
+		})
+
+	defineUnary(query, "languageWordCount", "Word, character, line and sentence counts", b.ref((*language.WordStats)(nil)),
+		"text", "Input text",
+		func(text string) (interface{}, error) { return svc.WordCount(text), nil })
+
+	defineUnary(query, "languageReadability", "Flesch, Flesch-Kincaid and Gunning Fog scores", b.ref((*language.ReadabilityStats)(nil)),
+		"text", "Input text",
+		func(text string) (interface{}, error) { return svc.Readability(text), nil })
+
+	defineUnary(query, "languageSentiment", "Lexicon-based sentiment score", b.ref((*language.SentimentResult)(nil)),
+		"text", "Input text",
+		func(text string) (interface{}, error) { return svc.Sentiment(text), nil })
+
+	define(query, "languageKeywords", &Field{
+		Type:        b.ref(([]language.Keyword)(nil)),
+		Description: "Most frequent non-stopword words",
+		Args: map[string]*Argument{
+			"text":  arg("String!", "Input text"),
+			"limit": arg("Int", "Maximum keywords (default 10)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			text, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			limit, err := optIntArg(args, "limit", 10)
+			if err != nil {
+				return nil, err
+			}
+			return svc.Keywords(text, limit), nil
+		},
+	})
+
+	define(query, "languageReadingTime", &Field{
+		Type:        b.ref((*language.ReadingTimeStats)(nil)),
+		Description: "Estimated reading time",
+		Args: map[string]*Argument{
+			"text": arg("String!", "Input text"),
+			"wpm":  arg("Int", "Words per minute (default 200)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			text, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			wpm, err := optIntArg(args, "wpm", 200)
+			if err != nil {
+				return nil, err
+			}
+			return svc.ReadingTime(svc.WordCount(text).Words, wpm), nil
+		},
+	})
+}
@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/apimgr/api/src/service/lorem"
+)
+
+// loremMaxCount bounds the number of words, sentences or paragraphs.
+const loremMaxCount = 1000
+
+type loremPerson struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type loremAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+	State  string `json:"state"`
+	Zip    string `json:"zip"`
+}
+
+type loremCompany struct {
+	Name     string `json:"name"`
+	Industry string `json:"industry"`
+}
+
+func addLoremFields(b *typeBuilder, query map[string]*Field) {
+	svc := lorem.New()
+
+	for name, fn := range map[string]struct {
+		description string
+		generate    func(int) (string, error)
+		def         int
+	}{
+		"loremWords":     {"Lorem ipsum words", svc.Words, 10},
+		"loremSentence":  {"Lorem ipsum sentence of count words", svc.Sentence, 10},
+		"loremParagraph": {"Lorem ipsum paragraph of count sentences", svc.Paragraph, 5},
+	} {
+		define(query, name, &Field{
+			Type:        "String!",
+			Description: fn.description,
+			Args: map[string]*Argument{
+				"count": arg("Int", fmt.Sprintf("Count (default %d)", fn.def)),
+			},
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				count, err := optIntArg(args, "count", fn.def)
+				if err != nil {
+					return nil, err
+				}
+				if count < 1 || count > loremMaxCount {
+					return nil, fmt.Errorf("count must be between 1 and %d", loremMaxCount)
+				}
+				return fn.generate(count)
+			},
+		})
+	}
+
+	define(query, "loremPerson", &Field{
+		Type:        b.ref((*loremPerson)(nil)),
+		Description: "Fake person",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return remapAs[loremPerson](svc.Person())
+		},
+	})
+
+	define(query, "loremAddress", &Field{
+		Type:        b.ref((*loremAddress)(nil)),
+		Description: "Fake US street address",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return remapAs[loremAddress](svc.Address())
+		},
+	})
+
+	define(query, "loremCompany", &Field{
+		Type:        b.ref((*loremCompany)(nil)),
+		Description: "Fake company",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return remapAs[loremCompany](svc.Company())
+		},
+	})
+}
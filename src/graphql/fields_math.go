@@ -0,0 +1,379 @@
+package graphql
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/apimgr/api/src/service/math"
+)
+
+type mathOperationResult struct {
+	Operation string  `json:"operation"`
+	Result    float64 `json:"result"`
+}
+
+// mathBigResult carries arbitrary-precision integers as decimal strings,
+// since they overflow GraphQL's Int.
+type mathBigResult struct {
+	Operation string `json:"operation"`
+	Result    string `json:"result"`
+	Remainder string `json:"remainder,omitempty"`
+}
+
+type mathStats struct {
+	Count   int     `json:"count"`
+	Sum     float64 `json:"sum"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+}
+
+type mathPrimeResult struct {
+	Number  string `json:"number"`
+	IsPrime bool   `json:"is_prime"`
+}
+
+type mathBaseResult struct {
+	Number   string `json:"number"`
+	FromBase int    `json:"from_base"`
+	ToBase   int    `json:"to_base"`
+	Result   string `json:"result"`
+}
+
+// mathMaxSequence caps list-producing math fields.
+const mathMaxSequence = 10000
+
+func addMathFields(b *typeBuilder, query map[string]*Field) {
+	svc := math.New()
+
+	binary := map[string]func(a, b float64) (float64, error){
+		"add":               func(a, b float64) (float64, error) { return svc.Add(a, b), nil },
+		"subtract":          func(a, b float64) (float64, error) { return svc.Subtract(a, b), nil },
+		"multiply":          func(a, b float64) (float64, error) { return svc.Multiply(a, b), nil },
+		"divide":            svc.Divide,
+		"power":             func(a, b float64) (float64, error) { return svc.Power(a, b), nil },
+		"percentage_of":     func(a, b float64) (float64, error) { return svc.PercentageOf(a, b), nil },
+		"percentage_change": func(a, b float64) (float64, error) { return svc.PercentageChange(a, b), nil },
+	}
+	unary := map[string]func(n float64) (float64, error){
+		"sqrt":  svc.SquareRoot,
+		"cbrt":  func(n float64) (float64, error) { return svc.CubeRoot(n), nil },
+		"abs":   func(n float64) (float64, error) { return svc.Abs(n), nil },
+		"round": func(n float64) (float64, error) { return svc.Round(n), nil },
+		"floor": func(n float64) (float64, error) { return svc.Floor(n), nil },
+		"ceil":  func(n float64) (float64, error) { return svc.Ceil(n), nil },
+		"log":   func(n float64) (float64, error) { return svc.Log(n), nil },
+		"log10": func(n float64) (float64, error) { return svc.Log10(n), nil },
+		"log2":  func(n float64) (float64, error) { return svc.Log2(n), nil },
+		"exp":   func(n float64) (float64, error) { return svc.Exp(n), nil },
+		"sin":   func(n float64) (float64, error) { return svc.Sin(n), nil },
+		"cos":   func(n float64) (float64, error) { return svc.Cos(n), nil },
+		"tan":   func(n float64) (float64, error) { return svc.Tan(n), nil },
+	}
+	operations := append(sortedKeys(binary), sortedKeys(unary)...)
+
+	define(query, "mathCalculate", &Field{
+		Type:        b.ref((*mathOperationResult)(nil)),
+		Description: "Apply an operation to a (and b): " + strings.Join(operations, ", "),
+		Args: map[string]*Argument{
+			"operation": arg("String!", "Operation name"),
+			"a":         arg("Float!", "First operand"),
+			"b":         arg("Float", "Second operand, for binary operations"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			operation, err := stringArg(args, "operation")
+			if err != nil {
+				return nil, err
+			}
+			operation = strings.ToLower(operation)
+			a, err := floatArg(args, "a")
+			if err != nil {
+				return nil, err
+			}
+
+			var result float64
+			if fn, ok := binary[operation]; ok {
+				bVal, err := floatArg(args, "b")
+				if err != nil {
+					return nil, err
+				}
+				if result, err = fn(a, bVal); err != nil {
+					return nil, err
+				}
+			} else if fn, ok := unary[operation]; ok {
+				if result, err = fn(a); err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, fmt.Errorf("unsupported operation %q", operation)
+			}
+			return mathOperationResult{Operation: operation, Result: result}, nil
+		},
+	})
+
+	define(query, "mathBigInt", &Field{
+		Type: b.ref((*mathBigResult)(nil)),
+		Description: "Arbitrary-precision integer arithmetic: add, subtract, multiply, divide, mod, " +
+			"pow, sqrt, abs, gcd, lcm, modpow, modinverse",
+		Args: map[string]*Argument{
+			"operation": arg("String!", "Operation name"),
+			"a":         arg("String!", "First operand (decimal, or 0x/0o/0b prefixed)"),
+			"b":         arg("String", "Second operand / exponent"),
+			"modulus":   arg("String", "Modulus for modpow and modinverse"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			operation, err := stringArg(args, "operation")
+			if err != nil {
+				return nil, err
+			}
+			operation = strings.ToLower(operation)
+			a, err := bigIntArg(args, "a")
+			if err != nil {
+				return nil, err
+			}
+			operand := func(name string) (*big.Int, error) { return bigIntArg(args, name) }
+
+			out := mathBigResult{Operation: operation}
+			var result *big.Int
+			switch operation {
+			case "sqrt":
+				result, err = svc.BigSquareRoot(a)
+			case "abs":
+				result = svc.BigAbs(a)
+			case "add", "subtract", "multiply", "divide", "mod", "pow", "gcd", "lcm":
+				var bVal *big.Int
+				if bVal, err = operand("b"); err != nil {
+					return nil, err
+				}
+				switch operation {
+				case "add":
+					result, err = svc.BigAdd(a, bVal)
+				case "subtract":
+					result, err = svc.BigSubtract(a, bVal)
+				case "multiply":
+					result, err = svc.BigMultiply(a, bVal)
+				case "divide":
+					var remainder *big.Int
+					result, remainder, err = svc.BigDivide(a, bVal)
+					if err == nil {
+						out.Remainder = remainder.String()
+					}
+				case "mod":
+					result, err = svc.BigModulo(a, bVal)
+				case "pow":
+					result, err = svc.BigPower(a, bVal)
+				case "gcd":
+					result = svc.BigGCD(a, bVal)
+				case "lcm":
+					result, err = svc.BigLCM(a, bVal)
+				}
+			case "modpow":
+				var exponent, modulus *big.Int
+				if exponent, err = operand("b"); err != nil {
+					return nil, err
+				}
+				if modulus, err = operand("modulus"); err != nil {
+					return nil, err
+				}
+				result, err = svc.ModPow(a, exponent, modulus)
+			case "modinverse":
+				var modulus *big.Int
+				if modulus, err = operand("modulus"); err != nil {
+					return nil, err
+				}
+				result, err = svc.ModInverse(a, modulus)
+			default:
+				return nil, fmt.Errorf("unsupported operation %q", operation)
+			}
+			if err != nil {
+				return nil, err
+			}
+			out.Result = result.String()
+			return out, nil
+		},
+	})
+
+	define(query, "mathStats", &Field{
+		Type:        b.ref((*mathStats)(nil)),
+		Description: "Count, sum, min, max, average and median of a list of numbers",
+		Args: map[string]*Argument{
+			"numbers": arg("[Float!]!", "Numbers to summarise"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			numbers, err := floatListArg(args, "numbers")
+			if err != nil {
+				return nil, err
+			}
+			if len(numbers) == 0 {
+				return nil, fmt.Errorf("numbers must not be empty")
+			}
+			return mathStats{
+				Count:   len(numbers),
+				Sum:     svc.Sum(numbers),
+				Min:     svc.Min(numbers),
+				Max:     svc.Max(numbers),
+				Average: svc.Average(numbers),
+				Median:  svc.Median(numbers),
+			}, nil
+		},
+	})
+
+	define(query, "mathIsPrime", &Field{
+		Type:        b.ref((*mathPrimeResult)(nil)),
+		Description: "Primality test (Miller-Rabin for large numbers)",
+		Args: map[string]*Argument{
+			"number": arg("String!", "Integer to test"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			n, err := bigIntArg(args, "number")
+			if err != nil {
+				return nil, err
+			}
+			prime, err := svc.IsProbablePrime(n)
+			if err != nil {
+				return nil, err
+			}
+			return mathPrimeResult{Number: n.String(), IsPrime: prime}, nil
+		},
+	})
+
+	define(query, "mathFactorial", &Field{
+		Type:        "String!",
+		Description: "n! as a decimal string",
+		Args: map[string]*Argument{
+			"n": arg("Int!", "Non-negative integer"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			n, err := intArg(args, "n")
+			if err != nil {
+				return nil, err
+			}
+			result, err := svc.BigFactorial(int64(n))
+			if err != nil {
+				return nil, err
+			}
+			return result.String(), nil
+		},
+	})
+
+	define(query, "mathFibonacci", &Field{
+		Type:        "[String!]!",
+		Description: "The first count Fibonacci numbers as decimal strings",
+		Args: map[string]*Argument{
+			"count": arg("Int!", fmt.Sprintf("How many numbers (max %d)", mathMaxSequence)),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			count, err := intArg(args, "count")
+			if err != nil {
+				return nil, err
+			}
+			if count > mathMaxSequence {
+				return nil, fmt.Errorf("count must be at most %d", mathMaxSequence)
+			}
+			values, err := svc.Fibonacci(count)
+			if err != nil {
+				return nil, err
+			}
+			sequence := make([]string, len(values))
+			for i, v := range values {
+				sequence[i] = v.String()
+			}
+			return sequence, nil
+		},
+	})
+
+	define(query, "mathSequence", &Field{
+		Type:        "[Float!]!",
+		Description: "Arithmetic or geometric sequence",
+		Args: map[string]*Argument{
+			"type":  arg("String!", "arithmetic or geometric"),
+			"start": arg("Float!", "First term"),
+			"step":  arg("Float!", "Difference or ratio"),
+			"count": arg("Int!", fmt.Sprintf("Number of terms (max %d)", mathMaxSequence)),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			seqType, err := stringArg(args, "type")
+			if err != nil {
+				return nil, err
+			}
+			start, err := floatArg(args, "start")
+			if err != nil {
+				return nil, err
+			}
+			step, err := floatArg(args, "step")
+			if err != nil {
+				return nil, err
+			}
+			count, err := intArg(args, "count")
+			if err != nil {
+				return nil, err
+			}
+			if count > mathMaxSequence {
+				return nil, fmt.Errorf("count must be at most %d", mathMaxSequence)
+			}
+			return svc.Sequence(seqType, start, step, count)
+		},
+	})
+
+	define(query, "mathBaseConvert", &Field{
+		Type:        b.ref((*mathBaseResult)(nil)),
+		Description: "Convert an integer between bases 2-36",
+		Args: map[string]*Argument{
+			"number": arg("String!", "Number in fromBase"),
+			"from":   arg("Int!", "Source base"),
+			"to":     arg("Int!", "Target base"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			number, err := stringArg(args, "number")
+			if err != nil {
+				return nil, err
+			}
+			from, err := intArg(args, "from")
+			if err != nil {
+				return nil, err
+			}
+			to, err := intArg(args, "to")
+			if err != nil {
+				return nil, err
+			}
+			result, err := svc.BigBaseConvert(number, from, to)
+			if err != nil {
+				return nil, err
+			}
+			return mathBaseResult{Number: number, FromBase: from, ToBase: to, Result: result}, nil
+		},
+	})
+
+	define(query, "mathRandom", &Field{
+		Type:        "Int!",
+		Description: "Random integer in the inclusive range [min, max]",
+		Args: map[string]*Argument{
+			"min": arg("Int!", "Lower bound"),
+			"max": arg("Int!", "Upper bound"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			lo, err := intArg(args, "min")
+			if err != nil {
+				return nil, err
+			}
+			hi, err := intArg(args, "max")
+			if err != nil {
+				return nil, err
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("max must be >= min")
+			}
+			return svc.RandomInt(int64(lo), int64(hi)), nil
+		},
+	})
+}
+
+func bigIntArg(args map[string]interface{}, name string) (*big.Int, error) {
+	raw, err := stringArg(args, name)
+	if err != nil {
+		return nil, err
+	}
+	return math.ParseBigInt(raw)
+}
@@ -0,0 +1,133 @@
+package graphql
+
+import (
+	"github.com/apimgr/api/src/service/network"
+	"github.com/apimgr/api/src/service/osint"
+)
+
+type networkMACVendor struct {
+	MAC    string `json:"mac"`
+	Vendor string `json:"vendor"`
+}
+
+func addNetworkFields(b *typeBuilder, query map[string]*Field) {
+	svc := network.New()
+
+	defineUnary(query, "networkSubnet", "Addresses, mask and host range of a CIDR block", b.ref((*network.SubnetInfo)(nil)),
+		"cidr", "IPv4 or IPv6 CIDR, e.g. 10.0.0.0/24",
+		func(cidr string) (interface{}, error) { return svc.SubnetCalculate(cidr) })
+
+	defineUnary(query, "networkMACVendor", "Registered vendor of a MAC address prefix", b.ref((*networkMACVendor)(nil)),
+		"mac", "MAC address",
+		func(mac string) (interface{}, error) {
+			vendor, err := svc.MACVendor(mac)
+			if err != nil {
+				return nil, err
+			}
+			return networkMACVendor{MAC: mac, Vendor: vendor}, nil
+		})
+
+	define(query, "networkULA", &Field{
+		Type:        "String!",
+		Description: "Random IPv6 unique local address /48 prefix (RFC 4193)",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return svc.GenerateULA()
+		},
+	})
+
+	define(query, "networkRandomPort", &Field{
+		Type:        "Int!",
+		Description: "Random unprivileged TCP port that is currently free",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return svc.RandomPort()
+		},
+	})
+
+	define(query, "networkPing", &Field{
+		Type:        b.ref((*network.PingResult)(nil)),
+		Description: "TCP connect round-trip times to a public host",
+		Args: map[string]*Argument{
+			"host":  arg("String!", "Hostname or IP, optionally host:port"),
+			"count": arg("Int", "Number of probes 1-10 (default 4)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			host, err := stringArg(args, "host")
+			if err != nil {
+				return nil, err
+			}
+			count, err := optIntArg(args, "count", 4)
+			if err != nil {
+				return nil, err
+			}
+			return svc.Ping(host, count)
+		},
+	})
+
+	defineUnary(query, "networkSSL", "TLS certificate presented by a public host", b.ref((*network.SSLCertInfo)(nil)),
+		"host", "Hostname, optionally host:port",
+		func(host string) (interface{}, error) { return svc.SSLInfo(host) })
+
+	defineUnary(query, "networkWhois", "Raw WHOIS record of a domain", "String!",
+		"domain", "Domain name",
+		func(domain string) (interface{}, error) { return svc.Whois(domain) })
+
+	markOutbound(query, "networkPing", "networkSSL", "networkWhois")
+}
+
+type osintDNSRecords struct {
+	Domain  string   `json:"domain"`
+	Type    string   `json:"type"`
+	Records []string `json:"records"`
+}
+
+func addOSINTFields(b *typeBuilder, query map[string]*Field) {
+	svc := osint.New()
+
+	defineUnary(query, "osintWhois", "Registrar, dates and name servers of a domain", b.ref((*osint.DomainInfo)(nil)),
+		"domain", "Domain name",
+		func(domain string) (interface{}, error) { return svc.WHOISLookup(domain) })
+
+	define(query, "osintDNS", &Field{
+		Type:        b.ref((*osintDNSRecords)(nil)),
+		Description: "DNS records of a domain",
+		Args: map[string]*Argument{
+			"domain": arg("String!", "Domain name"),
+			"type":   arg("String", "A, AAAA, CNAME, MX, NS or TXT (default A)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			domain, err := stringArg(args, "domain")
+			if err != nil {
+				return nil, err
+			}
+			recordType := optStringArg(args, "type", "A")
+			records, err := svc.DNSLookup(domain, recordType)
+			if err != nil {
+				return nil, err
+			}
+			return osintDNSRecords{Domain: domain, Type: recordType, Records: records}, nil
+		},
+	})
+
+	defineUnary(query, "osintIP", "Geolocation and ISP of a public IP address", b.ref((*osint.IPInfo)(nil)),
+		"ip", "IPv4 or IPv6 address",
+		func(ip string) (interface{}, error) { return svc.IPLookup(ip) })
+
+	defineUnary(query, "osintSubdomains", "Common subdomains of a domain that resolve", b.ref(([]osint.Subdomain)(nil)),
+		"domain", "Domain name",
+		func(domain string) (interface{}, error) { return svc.SubdomainEnum(domain) })
+
+	defineUnary(query, "osintTechStack", "Server software and frameworks detected on a site", b.ref((*osint.TechStackInfo)(nil)),
+		"url", "Site URL",
+		func(url string) (interface{}, error) { return svc.TechStack(url) })
+
+	markOutbound(query, "osintWhois", "osintDNS", "osintIP", "osintSubdomains", "osintTechStack")
+}
+
+// markOutbound flags fields whose resolvers contact a remote host, which
+// validation limits per operation since the rate limiter counts the
+// whole request once.
+func markOutbound(query map[string]*Field, names ...string) {
+	for _, name := range names {
+		query[name].Outbound = true
+	}
+}
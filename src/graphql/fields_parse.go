@@ -0,0 +1,165 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apimgr/api/src/service/parse"
+)
+
+func addParseFields(b *typeBuilder, query map[string]*Field) {
+	svc := parse.New()
+
+	// Formats without a fixed shape resolve to the JSON scalar.
+	documents := map[string]func(string) (interface{}, error){
+		"json":        func(s string) (interface{}, error) { return svc.ParseJSON(s) },
+		"json_array":  func(s string) (interface{}, error) { return svc.ParseJSONArray(s) },
+		"yaml":        func(s string) (interface{}, error) { return svc.ParseYAML(s) },
+		"toml":        func(s string) (interface{}, error) { return svc.ParseTOML(s) },
+		"xml":         func(s string) (interface{}, error) { return svc.ParseXML(s) },
+		"ini":         func(s string) (interface{}, error) { return svc.ParseINI(s) },
+		"env":         func(s string) (interface{}, error) { return svc.ParseEnv(s) },
+		"csv":         func(s string) (interface{}, error) { return svc.ParseCSV(s) },
+		"querystring": func(s string) (interface{}, error) { return svc.ParseQueryString(s) },
+	}
+
+	define(query, "parseDocument", &Field{
+		Type:        scalarJSON + "!",
+		Description: "Parse a document into JSON: " + strings.Join(sortedKeys(documents), ", "),
+		Args: map[string]*Argument{
+			"format": arg("String!", "Input format"),
+			"input":  arg("String!", "Document text"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			format, err := stringArg(args, "format")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			decode, ok := documents[strings.ToLower(format)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported format %q", format)
+			}
+			return decode(input)
+		},
+	})
+
+	inputArgs := func(description string) map[string]*Argument {
+		return map[string]*Argument{"input": arg("String!", description)}
+	}
+
+	define(query, "parseURL", &Field{
+		Type:        b.ref((*parse.URLParts)(nil)),
+		Description: "Split a URL into its components",
+		Args:        inputArgs("URL"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseURL(input)
+		},
+	})
+
+	define(query, "parseUserAgent", &Field{
+		Type:        b.ref((*parse.UserAgent)(nil)),
+		Description: "Browser, OS and device of a User-Agent string",
+		Args:        inputArgs("User-Agent header value"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseUserAgent(input), nil
+		},
+	})
+
+	define(query, "parseEmail", &Field{
+		Type:        b.ref((*parse.EmailParts)(nil)),
+		Description: "Split an email address into local part and domain",
+		Args:        inputArgs("Email address"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseEmail(input)
+		},
+	})
+
+	define(query, "parseHTML", &Field{
+		Type:        b.ref((*parse.HTMLSummary)(nil)),
+		Description: "Title, meta tags, headings, links and images of an HTML document",
+		Args:        inputArgs("HTML document"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseHTML(input)
+		},
+	})
+
+	define(query, "parseMarkdown", &Field{
+		Type:        b.ref((*parse.MarkdownStructure)(nil)),
+		Description: "Headings, links and code blocks of a Markdown document",
+		Args:        inputArgs("Markdown document"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseMarkdownStructure(input)
+		},
+	})
+
+	define(query, "parseSQL", &Field{
+		Type:        b.ref((*parse.SQLStructure)(nil)),
+		Description: "Statement type, tables and columns of a SQL statement",
+		Args:        inputArgs("SQL statement"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseSQLStructure(input)
+		},
+	})
+
+	define(query, "parseLog", &Field{
+		Type:        b.ref(([]parse.LogEntry)(nil)),
+		Description: "Timestamp, level and message of each log line",
+		Args:        inputArgs("Log lines"),
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseLogLines(input)
+		},
+	})
+
+	define(query, "parseDateTime", &Field{
+		Type:        "String!",
+		Description: "Parse a date/time in any common layout, a natural-language expression, or an ISO 8601 duration or interval to RFC 3339",
+		Args: map[string]*Argument{
+			"input":     arg("String!", "Date/time string"),
+			"reference": arg("String", "RFC3339 time relative expressions are resolved against (default now)"),
+			"timezone":  arg("String", "IANA timezone for times without a zone (default UTC)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "input")
+			if err != nil {
+				return nil, err
+			}
+			opts, err := parseOptionsArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return svc.ParseDateTimeAt(input, opts)
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/apimgr/api/src/service/research"
+)
+
+// researchLookupTimeout bounds arXiv and Open Library lookups.
+const researchLookupTimeout = 15 * time.Second
+
+type researchDOI struct {
+	DOI   string `json:"doi"`
+	Valid bool   `json:"valid"`
+	URL   string `json:"url"`
+}
+
+func addResearchFields(b *typeBuilder, query map[string]*Field) {
+	svc := research.New()
+
+	defineUnary(query, "researchArxiv", "arXiv paper metadata", b.ref((*research.ArxivResult)(nil)),
+		"id", "arXiv identifier, e.g. 1706.03762",
+		func(id string) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), researchLookupTimeout)
+			defer cancel()
+			return svc.ArxivLookup(ctx, id)
+		})
+
+	defineUnary(query, "researchISBN", "Book metadata from Open Library", b.ref((*research.ISBNResult)(nil)),
+		"isbn", "ISBN-10 or ISBN-13",
+		func(isbn string) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), researchLookupTimeout)
+			defer cancel()
+			return svc.ISBNLookup(ctx, isbn)
+		})
+
+	defineUnary(query, "researchDOI", "Validate a DOI and build its resolver URL", b.ref((*researchDOI)(nil)),
+		"doi", "DOI, e.g. 10.1000/182",
+		func(doi string) (interface{}, error) {
+			doi = strings.TrimSpace(doi)
+			valid := svc.ValidateDOI(doi)
+			info := researchDOI{DOI: doi, Valid: valid}
+			if valid {
+				info.URL = svc.FormatDOI(doi)
+			}
+			return info, nil
+		})
+
+	define(query, "researchCitation", &Field{
+		Type:        "String!",
+		Description: "Format a citation in APA, MLA or Chicago style",
+		Args: map[string]*Argument{
+			"style":  arg("String!", "APA, MLA or Chicago"),
+			"title":  arg("String!", "Work title"),
+			"author": arg("String!", "Author"),
+			"year":   arg("String!", "Publication year"),
+			"source": arg("String!", "Journal, publisher or URL"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			var ref research.Reference
+			var style string
+			for name, dst := range map[string]*string{
+				"style": &style, "title": &ref.Title, "author": &ref.Author, "year": &ref.Year, "source": &ref.Source,
+			} {
+				v, err := stringArg(args, name)
+				if err != nil {
+					return nil, err
+				}
+				*dst = v
+			}
+			return svc.GenerateBibliography([]research.Reference{ref}, style)[0], nil
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"github.com/apimgr/api/src/service/test"
+)
+
+type mockUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Active   bool   `json:"active"`
+	Created  string `json:"created"`
+}
+
+func addTestFields(b *typeBuilder, query map[string]*Field) {
+	svc := test.New()
+
+	define(query, "testMockUser", &Field{
+		Type:        b.ref((*mockUser)(nil)),
+		Description: "Mock user record",
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return remapAs[mockUser](svc.GenerateMockUser(), nil)
+		},
+	})
+
+	defineUnary(query, "testFixture", "Fixture by type (user, api_response)", "JSON!",
+		"type", "Fixture type",
+		func(fixtureType string) (interface{}, error) { return svc.GenerateFixture(fixtureType), nil })
+
+	define(query, "testEmail", &Field{
+		Type:        "String!",
+		Description: "Unique plus-addressed test email",
+		Args: map[string]*Argument{
+			"prefix": arg("String", "Local part (default test)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return svc.GenerateTestEmail(optStringArg(args, "prefix", "test")), nil
+		},
+	})
+
+	define(query, "testUsername", &Field{
+		Type:        "String!",
+		Description: "Unique test username",
+		Args: map[string]*Argument{
+			"prefix": arg("String", "Username prefix (default user)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			return svc.GenerateTestUsername(optStringArg(args, "prefix", "user")), nil
+		},
+	})
+}
@@ -0,0 +1,449 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apimgr/api/src/service/text"
+)
+
+type textHashResult struct {
+	Algorithm string `json:"algorithm"`
+	Input     string `json:"input"`
+	Hash      string `json:"hash"`
+}
+
+type textCodecResult struct {
+	Encoding string `json:"encoding"`
+	Input    string `json:"input"`
+	Output   string `json:"output"`
+}
+
+type textCaseResult struct {
+	Style  string `json:"style"`
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+type textStats struct {
+	Characters        int `json:"characters"`
+	CharactersNoSpace int `json:"characters_no_space"`
+	Words             int `json:"words"`
+	Lines             int `json:"lines"`
+	Bytes             int `json:"bytes"`
+}
+
+type textSimilarity struct {
+	Levenshtein int     `json:"levenshtein"`
+	Similarity  float64 `json:"similarity"`
+}
+
+type textRegexResult struct {
+	Pattern string   `json:"pattern"`
+	Matches []string `json:"matches"`
+	Count   int      `json:"count"`
+}
+
+// textEncoders and textDecoders back textEncode/textDecode; they accept
+// the same encodings as /api/v1/text/encode and /decode.
+var (
+	textEncoders = map[string]func(string) string{
+		"base64":    text.Base64Encode,
+		"base64url": text.Base64URLEncode,
+		"base32":    text.Base32Encode,
+		"hex":       text.HexEncode,
+		"base16":    text.HexEncode,
+		"url":       text.URLEncode,
+	}
+	textDecoders = map[string]func(string) (string, error){
+		"base64":    text.Base64Decode,
+		"base64url": text.Base64URLDecode,
+		"base32":    text.Base32Decode,
+		"hex":       text.HexDecode,
+		"base16":    text.HexDecode,
+		"url":       text.URLDecode,
+	}
+	textCases = map[string]func(string) string{
+		"lower":  text.ToLower,
+		"upper":  text.ToUpper,
+		"title":  text.ToTitle,
+		"camel":  text.ToCamelCase,
+		"pascal": text.ToPascalCase,
+		"snake":  text.ToSnakeCase,
+		"kebab":  text.ToKebabCase,
+		"dot":    text.ToDotCase,
+	}
+	textIDs = map[string]func() string{
+		"ulid":      text.ULID,
+		"nanoid":    text.NanoID,
+		"ksuid":     text.KSUID,
+		"xid":       text.XID,
+		"cuid":      text.CUID,
+		"snowflake": text.Snowflake,
+		"objectid":  text.ObjectID,
+	}
+	textExtractors = map[string]func(string) []string{
+		"emails": text.ExtractEmails,
+		"urls":   text.ExtractURLs,
+		"ips":    text.ExtractIPs,
+		"phones": text.ExtractPhones,
+	}
+)
+
+func addTextFields(b *typeBuilder, query map[string]*Field) {
+	define(query, "textHash", &Field{
+		Type:        b.ref((*textHashResult)(nil)),
+		Description: "Hash text with the given algorithm",
+		Args: map[string]*Argument{
+			"algorithm": arg("String!", strings.Join(text.HashAlgorithms, ", ")),
+			"text":      arg("String!", "Text to hash"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			algorithm, err := stringArg(args, "algorithm")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			hash, err := text.Hash(algorithm, input)
+			if err != nil {
+				return nil, err
+			}
+			return textHashResult{Algorithm: algorithm, Input: input, Hash: hash}, nil
+		},
+	})
+
+	define(query, "textHashAll", &Field{
+		Type:        b.ref(([]textHashResult)(nil)),
+		Description: "Hash text with every supported algorithm",
+		Args: map[string]*Argument{
+			"text": arg("String!", "Text to hash"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			hashes := text.HashAll(input)
+			results := make([]textHashResult, 0, len(hashes))
+			for _, algorithm := range sortedKeys(hashes) {
+				results = append(results, textHashResult{Algorithm: algorithm, Input: input, Hash: hashes[algorithm]})
+			}
+			return results, nil
+		},
+	})
+
+	define(query, "textEncode", &Field{
+		Type:        b.ref((*textCodecResult)(nil)),
+		Description: "Encode text: " + strings.Join(sortedKeys(textEncoders), ", "),
+		Args: map[string]*Argument{
+			"encoding": arg("String!", "Encoding name"),
+			"text":     arg("String!", "Text to encode"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			encoding, err := stringArg(args, "encoding")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			encode, ok := textEncoders[strings.ToLower(encoding)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported encoding %q", encoding)
+			}
+			return textCodecResult{Encoding: encoding, Input: input, Output: encode(input)}, nil
+		},
+	})
+
+	define(query, "textDecode", &Field{
+		Type:        b.ref((*textCodecResult)(nil)),
+		Description: "Decode text: " + strings.Join(sortedKeys(textDecoders), ", "),
+		Args: map[string]*Argument{
+			"encoding": arg("String!", "Encoding name"),
+			"text":     arg("String!", "Text to decode"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			encoding, err := stringArg(args, "encoding")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			decode, ok := textDecoders[strings.ToLower(encoding)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported encoding %q", encoding)
+			}
+			output, err := decode(input)
+			if err != nil {
+				return nil, err
+			}
+			return textCodecResult{Encoding: encoding, Input: input, Output: output}, nil
+		},
+	})
+
+	define(query, "textCase", &Field{
+		Type:        b.ref((*textCaseResult)(nil)),
+		Description: "Convert text case: " + strings.Join(sortedKeys(textCases), ", "),
+		Args: map[string]*Argument{
+			"style": arg("String!", "Case style"),
+			"text":  arg("String!", "Text to convert"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			style, err := stringArg(args, "style")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			convert, ok := textCases[strings.ToLower(style)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported style %q", style)
+			}
+			return textCaseResult{Style: style, Input: input, Output: convert(input)}, nil
+		},
+	})
+
+	define(query, "textStats", &Field{
+		Type:        b.ref((*textStats)(nil)),
+		Description: "Count characters, words, lines and bytes",
+		Args: map[string]*Argument{
+			"text": arg("String!", "Text to analyse"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			return remapAs[textStats](text.Stats(input), nil)
+		},
+	})
+
+	define(query, "textUUIDs", &Field{
+		Type:        "[String!]!",
+		Description: "Generate a batch of UUIDs",
+		Args: map[string]*Argument{
+			"version": arg("Int", "UUID version (default 4)"),
+			"count":   arg("Int", "How many to generate (default 1)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			version, err := optIntArg(args, "version", 4)
+			if err != nil {
+				return nil, err
+			}
+			count, err := optIntArg(args, "count", 1)
+			if err != nil {
+				return nil, err
+			}
+			return text.UUIDs(version, count)
+		},
+	})
+
+	define(query, "textID", &Field{
+		Type:        "String!",
+		Description: "Generate a sortable/unique ID: " + strings.Join(sortedKeys(textIDs), ", "),
+		Args: map[string]*Argument{
+			"type": arg("String!", "ID type"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			idType, err := stringArg(args, "type")
+			if err != nil {
+				return nil, err
+			}
+			generate, ok := textIDs[strings.ToLower(idType)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported ID type %q", idType)
+			}
+			return generate(), nil
+		},
+	})
+
+	define(query, "textSimilarity", &Field{
+		Type:        b.ref((*textSimilarity)(nil)),
+		Description: "Levenshtein distance and similarity ratio of two strings",
+		Args: map[string]*Argument{
+			"a": arg("String!", "First string"),
+			"b": arg("String!", "Second string"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			a, err := stringArg(args, "a")
+			if err != nil {
+				return nil, err
+			}
+			bStr, err := stringArg(args, "b")
+			if err != nil {
+				return nil, err
+			}
+			return textSimilarity{
+				Levenshtein: text.Levenshtein(a, bStr),
+				Similarity:  text.Similarity(a, bStr),
+			}, nil
+		},
+	})
+
+	define(query, "textDiff", &Field{
+		Type:        "String!",
+		Description: "Line diff of two texts",
+		Args: map[string]*Argument{
+			"a": arg("String!", "Original text"),
+			"b": arg("String!", "Changed text"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			a, err := stringArg(args, "a")
+			if err != nil {
+				return nil, err
+			}
+			bStr, err := stringArg(args, "b")
+			if err != nil {
+				return nil, err
+			}
+			return text.Diff(a, bStr), nil
+		},
+	})
+
+	define(query, "textRegex", &Field{
+		Type:        b.ref((*textRegexResult)(nil)),
+		Description: "Find all matches of a regular expression",
+		Args: map[string]*Argument{
+			"pattern": arg("String!", "RE2 pattern"),
+			"text":    arg("String!", "Text to search"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			pattern, err := stringArg(args, "pattern")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			matches, err := text.RegexMatch(pattern, input)
+			if err != nil {
+				return nil, err
+			}
+			return textRegexResult{Pattern: pattern, Matches: matches, Count: len(matches)}, nil
+		},
+	})
+
+	define(query, "textExtract", &Field{
+		Type:        "[String!]!",
+		Description: "Extract " + strings.Join(sortedKeys(textExtractors), ", ") + " from text",
+		Args: map[string]*Argument{
+			"kind": arg("String!", "What to extract"),
+			"text": arg("String!", "Text to scan"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			kind, err := stringArg(args, "kind")
+			if err != nil {
+				return nil, err
+			}
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			extract, ok := textExtractors[strings.ToLower(kind)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported kind %q", kind)
+			}
+			found := extract(input)
+			if found == nil {
+				found = []string{}
+			}
+			return found, nil
+		},
+	})
+
+	define(query, "textLines", &Field{
+		Type:        "[String!]!",
+		Description: "Split text into lines, optionally sorted and deduplicated",
+		Args: map[string]*Argument{
+			"text":   arg("String!", "Text to split"),
+			"sort":   arg("Boolean", "Sort lines"),
+			"dedupe": arg("Boolean", "Remove duplicate lines"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			lines := text.Lines(input)
+			if optBoolArg(args, "dedupe", false) {
+				lines = text.Dedupe(lines)
+			}
+			if optBoolArg(args, "sort", false) {
+				lines = text.Sort(lines)
+			}
+			return lines, nil
+		},
+	})
+
+	define(query, "textCompress", &Field{
+		Type:        "String!",
+		Description: "Compress text (gzip, zlib, deflate) and base64-encode it",
+		Args: map[string]*Argument{
+			"text":      arg("String!", "Text to compress"),
+			"algorithm": arg("String", "Compression algorithm (default gzip)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			input, err := stringArg(args, "text")
+			if err != nil {
+				return nil, err
+			}
+			return text.Compress(input, optStringArg(args, "algorithm", "gzip"))
+		},
+	})
+
+	define(query, "textDecompress", &Field{
+		Type:        "String!",
+		Description: "Decompress base64-encoded compressed text",
+		Args: map[string]*Argument{
+			"data":      arg("String!", "Base64 compressed data"),
+			"algorithm": arg("String", "Compression algorithm (default gzip)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			data, err := stringArg(args, "data")
+			if err != nil {
+				return nil, err
+			}
+			return text.Decompress(data, optStringArg(args, "algorithm", "gzip"))
+		},
+	})
+
+	for name, fn := range map[string]struct {
+		description string
+		apply       func(string) string
+	}{
+		"textROT13":          {"ROT13-encode text", text.ROT13},
+		"textROT47":          {"ROT47-encode text", text.ROT47},
+		"textMorse":          {"Translate text to Morse code", text.Morse},
+		"textBinary":         {"Write text as binary octets", text.Binary},
+		"textStripHTML":      {"Remove HTML tags", text.StripHTML},
+		"textStripMarkdown":  {"Remove Markdown formatting", text.StripMarkdown},
+		"textMarkdownToHTML": {"Render Markdown as HTML", text.MarkdownToHTML},
+		"textSoundex":        {"Soundex code of a word", text.Soundex},
+		"textMetaphone":      {"Metaphone code of a word", text.Metaphone},
+	} {
+		apply := fn.apply
+		define(query, name, &Field{
+			Type:        "String!",
+			Description: fn.description,
+			Args: map[string]*Argument{
+				"text": arg("String!", "Input text"),
+			},
+			Resolve: func(args map[string]interface{}) (interface{}, error) {
+				input, err := stringArg(args, "text")
+				if err != nil {
+					return nil, err
+				}
+				return apply(input), nil
+			},
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apimgr/api/src/service/validate"
+)
+
+type validationResult struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+	Valid bool   `json:"valid"`
+}
+
+func addValidateFields(b *typeBuilder, query map[string]*Field) {
+	svc := validate.New()
+	validators := map[string]func(string) bool{
+		"email":        svc.IsEmail,
+		"url":          svc.IsURL,
+		"ip":           svc.IsIP,
+		"ipv4":         svc.IsIPv4,
+		"ipv6":         svc.IsIPv6,
+		"domain":       svc.IsDomain,
+		"phone":        svc.IsPhone,
+		"credit_card":  svc.IsCreditCard,
+		"alpha":        svc.IsAlpha,
+		"alphanumeric": svc.IsAlphanumeric,
+		"numeric":      svc.IsNumeric,
+		"lowercase":    svc.IsLowercase,
+		"uppercase":    svc.IsUppercase,
+		"json":         svc.IsJSON,
+		"uuid":         svc.IsUUID,
+		"mac":          svc.IsMAC,
+		"iban":         svc.IsIBAN,
+		"isbn":         svc.IsISBN,
+		"vat":          svc.IsVAT,
+		"password":     func(v string) bool { return svc.PasswordStrength(v) != "weak" },
+	}
+
+	define(query, "validate", &Field{
+		Type:        b.ref((*validationResult)(nil)),
+		Description: "Validate a value as one of: " + strings.Join(sortedKeys(validators), ", "),
+		Args: map[string]*Argument{
+			"kind":  arg("String!", "What the value should be"),
+			"value": arg("String!", "Value to check"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			kind, err := stringArg(args, "kind")
+			if err != nil {
+				return nil, err
+			}
+			value, err := stringArg(args, "value")
+			if err != nil {
+				return nil, err
+			}
+			check, ok := validators[strings.ToLower(kind)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported kind %q", kind)
+			}
+			return validationResult{Kind: kind, Value: value, Valid: check(value)}, nil
+		},
+	})
+
+	define(query, "validateAll", &Field{
+		Type:        b.ref(([]validationResult)(nil)),
+		Description: "Run every validator against a value",
+		Args: map[string]*Argument{
+			"value": arg("String!", "Value to check"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			value, err := stringArg(args, "value")
+			if err != nil {
+				return nil, err
+			}
+			results := make([]validationResult, 0, len(validators))
+			for _, kind := range sortedKeys(validators) {
+				results = append(results, validationResult{Kind: kind, Value: value, Valid: validators[kind](value)})
+			}
+			return results, nil
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package graphql
+
+import (
+	"strconv"
+
+	"github.com/apimgr/api/src/service/weather"
+)
+
+// cityLookup adapts a weather service call to a byCity resolver that
+// reads through the response cache under kind.
+func cityLookup[T any](kind string, fetch func(city string) (T, error)) func(string) (interface{}, error) {
+	return func(city string) (interface{}, error) {
+		return cachedLookup("weather", weather.CacheKey(kind, city), func() (T, error) { return fetch(city) })
+	}
+}
+
+func addWeatherFields(b *typeBuilder, query map[string]*Field) {
+	svc := weather.New()
+
+	// byCity defines a field that takes only a location name.
+	byCity := func(name, description string, result interface{}, fetch func(city string) (interface{}, error)) {
+		defineUnary(query, name, description, b.ref(result), "city", "Location name, e.g. London", fetch)
+	}
+
+	byCity("weatherCurrent", "Current conditions", (*weather.CurrentWeather)(nil),
+		cityLookup("current", svc.GetCurrentWeather))
+	byCity("weatherAirQuality", "Current air quality", (*weather.AirQuality)(nil),
+		cityLookup("air-quality", svc.GetAirQuality))
+	byCity("weatherUVIndex", "Current UV index", (*weather.UVIndex)(nil),
+		cityLookup("uv", svc.GetUVIndex))
+	byCity("weatherPollen", "Current pollen concentrations (Europe only)", (*weather.Pollen)(nil),
+		cityLookup("pollen", svc.GetPollen))
+	byCity("weatherAstronomy", "Today's sunrise, sunset and daylight", (*weather.Astronomy)(nil),
+		cityLookup("astronomy", svc.GetAstronomy))
+	byCity("weatherMarine", "Current sea conditions", (*weather.MarineConditions)(nil),
+		cityLookup("marine", svc.GetMarine))
+	byCity("weatherAlerts", "Active government weather alerts", ([]*weather.Alert)(nil),
+		cityLookup("alerts", svc.GetAlerts))
+
+	define(query, "weatherForecast", &Field{
+		Type:        b.ref(([]*weather.Forecast)(nil)),
+		Description: "Daily forecast",
+		Args: map[string]*Argument{
+			"city": arg("String!", "Location name"),
+			"days": arg("Int", "Number of days 1-16 (default 7)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			city, err := stringArg(args, "city")
+			if err != nil {
+				return nil, err
+			}
+			days, err := optIntArg(args, "days", 7)
+			if err != nil {
+				return nil, err
+			}
+			return cachedLookup("weather", weather.CacheKey("forecast", city, strconv.Itoa(days)), func() ([]*weather.Forecast, error) {
+				return svc.GetForecast(city, days)
+			})
+		},
+	})
+
+	define(query, "weatherHourly", &Field{
+		Type:        b.ref(([]*weather.HourlyEntry)(nil)),
+		Description: "Hourly forecast",
+		Args: map[string]*Argument{
+			"city":  arg("String!", "Location name"),
+			"hours": arg("Int", "Number of hours 1-384 (default 24)"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			city, err := stringArg(args, "city")
+			if err != nil {
+				return nil, err
+			}
+			hours, err := optIntArg(args, "hours", 24)
+			if err != nil {
+				return nil, err
+			}
+			return cachedLookup("weather", weather.CacheKey("hourly", city, strconv.Itoa(hours)), func() ([]*weather.HourlyEntry, error) {
+				return svc.GetHourly(city, hours)
+			})
+		},
+	})
+
+	define(query, "weatherHistorical", &Field{
+		Type:        b.ref(([]*weather.HistoricalDay)(nil)),
+		Description: "Daily observations over a past date range",
+		Args: map[string]*Argument{
+			"city":  arg("String!", "Location name"),
+			"start": arg("String!", "Start date YYYY-MM-DD"),
+			"end":   arg("String!", "End date YYYY-MM-DD"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			city, err := stringArg(args, "city")
+			if err != nil {
+				return nil, err
+			}
+			start, err := stringArg(args, "start")
+			if err != nil {
+				return nil, err
+			}
+			end, err := stringArg(args, "end")
+			if err != nil {
+				return nil, err
+			}
+			return cachedLookup("weather", weather.CacheKey("historical", city, start, end), func() ([]*weather.HistoricalDay, error) {
+				return svc.GetHistorical(city, start, end)
+			})
+		},
+	})
+
+	define(query, "weatherLocations", &Field{
+		Type:        b.ref(([]*weather.Location)(nil)),
+		Description: "Places matching a name",
+		Args: map[string]*Argument{
+			"query": arg("String!", "Place name"),
+		},
+		Resolve: func(args map[string]interface{}) (interface{}, error) {
+			q, err := stringArg(args, "query")
+			if err != nil {
+				return nil, err
+			}
+			return svc.SearchLocation(q)
+		},
+	})
+}
@@ -1,15 +1,22 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"reflect"
 	"strconv"
+	"sync"
+	"sync/atomic"
 
+	"github.com/apimgr/api/src/cache"
 	"github.com/apimgr/api/src/server/handler"
 	"github.com/apimgr/api/src/service/crypto"
 	"github.com/apimgr/api/src/service/datetime"
+	"github.com/apimgr/api/src/service/math"
 	"github.com/apimgr/api/src/service/text"
 )
 
@@ -17,12 +24,17 @@ import (
 type Schema struct {
 	Query    *ObjectType
 	Mutation *ObjectType
+	// Types are the object types field results resolve to, keyed by name.
+	Types map[string]*ObjectType
 }
 
 // ObjectType represents a GraphQL object type
 type ObjectType struct {
 	Name   string
 	Fields map[string]*Field
+
+	// goType is the struct the type was derived from, if any.
+	goType reflect.Type
 }
 
 // Field represents a GraphQL field
@@ -31,12 +43,18 @@ type Field struct {
 	Description string
 	Args        map[string]*Argument
 	Resolve     ResolveFunc
+	// Outbound marks resolvers that contact a host named in the query;
+	// validation caps how many one operation may select.
+	Outbound bool
 }
 
 // Argument represents a field argument
 type Argument struct {
 	Type        string
 	Description string
+	// DefaultValue is the GraphQL literal used when the argument is
+	// omitted, or empty for no default.
+	DefaultValue string
 }
 
 // ResolveFunc is a function that resolves a field value
@@ -55,20 +73,81 @@ type Response struct {
 	Errors []Error     `json:"errors,omitempty"`
 }
 
-// Error represents a GraphQL error
+// Error represents a GraphQL error. Locations point at the part of the
+// query document the error relates to; Path is the response path of the
+// field that failed, made of response keys and list indices.
 type Error struct {
-	Message string   `json:"message"`
-	Path    []string `json:"path,omitempty"`
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// health is the Health type; the resolver returns the same keys.
+type health struct {
+	Status string `json:"status"`
+	Uptime int64  `json:"uptime"`
+}
+
+// versionInfo is the Version type.
+type versionInfo struct {
+	Version   string `json:"version"`
+	CommitID  string `json:"commit_id"`
+	BuildDate string `json:"build_date"`
+}
+
+// textResult is the TextResult type shared by the single-string
+// mutations.
+type textResult struct {
+	Result string `json:"result"`
+}
+
+// mathResult is the MathResult type.
+type mathResult struct {
+	Expression string  `json:"expression"`
+	Result     float64 `json:"result"`
+}
+
+// fieldSets add each tool category's query fields, with their result
+// types, to the schema.
+var fieldSets = []func(b *typeBuilder, query map[string]*Field){
+	addTextFields,
+	addMathFields,
+	addConvertFields,
+	addGeoFields,
+	addValidateFields,
+	addParseFields,
+	addCryptoFields,
+	addDateTimeFields,
+	addWeatherFields,
+	addImageFields,
+	addNetworkFields,
+	addOSINTFields,
+	addDevFields,
+	addDockerFields,
+	addGenerateFields,
+	addLanguageFields,
+	addLoremFields,
+	addFunFields,
+	addResearchFields,
+	addTestFields,
 }
 
+// defaultSchema is the schema queries are executed against, built once.
+var defaultSchema = sync.OnceValue(BuildSchema)
+
 // BuildSchema creates the GraphQL schema for the API
 func BuildSchema() *Schema {
-	return &Schema{
+	b := newTypeBuilder()
+	schema := &Schema{
 		Query: &ObjectType{
 			Name: "Query",
 			Fields: map[string]*Field{
 				"health": {
-					Type:        "Health",
+					Type:        b.object("Health", health{}),
 					Description: "Health check",
 					Resolve: func(args map[string]interface{}) (interface{}, error) {
 						return map[string]interface{}{
@@ -78,7 +157,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"version": {
-					Type:        "Version",
+					Type:        b.object("Version", versionInfo{}),
 					Description: "Version information",
 					Resolve: func(args map[string]interface{}) (interface{}, error) {
 						return map[string]interface{}{
@@ -89,7 +168,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textUppercase": {
-					Type:        "String",
+					Type:        "String!",
 					Description: "Convert text to uppercase",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to convert"},
@@ -103,19 +182,46 @@ func BuildSchema() *Schema {
 					},
 				},
 				"generateUUID": {
-					Type:        "String",
+					Type:        "String!",
 					Description: "Generate UUID",
 					Resolve: func(args map[string]interface{}) (interface{}, error) {
 						return text.UUID(4)
 					},
 				},
+				"mathEvaluate": {
+					Type:        b.object("MathResult", mathResult{}),
+					Description: "Evaluate an arithmetic expression",
+					Args: map[string]*Argument{
+						"expression": {Type: "String!", Description: "Expression, e.g. (2^10 + sqrt(81)) * sin(pi/4)"},
+						"variables":  {Type: "String", Description: "Variable bindings as name:value,..."},
+					},
+					Resolve: func(args map[string]interface{}) (interface{}, error) {
+						expression, ok := args["expression"].(string)
+						if !ok {
+							return nil, fmt.Errorf("argument \"expression\" is required")
+						}
+						rawVars, _ := args["variables"].(string)
+						variables, err := math.ParseExpressionVariables(rawVars)
+						if err != nil {
+							return nil, err
+						}
+						result, err := math.New().Evaluate(expression, variables)
+						if err != nil {
+							return nil, err
+						}
+						return map[string]interface{}{
+							"expression": expression,
+							"result":     result,
+						}, nil
+					},
+				},
 			},
 		},
 		Mutation: &ObjectType{
 			Name: "Mutation",
 			Fields: map[string]*Field{
 				"textUppercase": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Convert text to uppercase",
 					Args: map[string]*Argument{
 						"text": {
@@ -134,7 +240,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textLowercase": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Convert text to lowercase",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to convert"},
@@ -148,7 +254,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"bcryptHash": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Hash password with bcrypt",
 					Args: map[string]*Argument{
 						"password": {Type: "String!", Description: "Password to hash"},
@@ -166,7 +272,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textReverse": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Reverse text",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to reverse"},
@@ -180,7 +286,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textBase64Encode": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Base64-encode text",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to encode"},
@@ -194,7 +300,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textBase64Decode": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Base64-decode text",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to decode"},
@@ -212,7 +318,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textSlug": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Slugify text",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to slugify"},
@@ -226,7 +332,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"textHash": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Hash text with SHA-256",
 					Args: map[string]*Argument{
 						"text": {Type: "String!", Description: "Text to hash"},
@@ -244,7 +350,7 @@ func BuildSchema() *Schema {
 					},
 				},
 				"convertTimezone": {
-					Type:        "TextResult",
+					Type:        b.object("TextResult", textResult{}),
 					Description: "Convert a Unix timestamp between timezones",
 					Args: map[string]*Argument{
 						"timestamp": {Type: "String!", Description: "Unix timestamp (seconds)"},
@@ -278,70 +384,44 @@ func BuildSchema() *Schema {
 			},
 		},
 	}
+
+	for _, add := range fieldSets {
+		add(b, schema.Query.Fields)
+	}
+	schema.Types = b.types
+	return schema
 }
 
 // GenerateSchemaSDL generates the GraphQL SDL (Schema Definition Language)
+// from the same definitions queries are executed against.
 func GenerateSchemaSDL() string {
-	return `
-type Query {
-	# Health check
-	health: Health!
-
-	# Version information
-	version: Version!
-
-	# Text utilities
-	textUppercase(text: String!): String!
-	generateUUID: String!
-
-	# Crypto utilities (implement as needed)
-
-	# DateTime utilities (implement as needed)
-
-	# Network utilities (implement as needed)
+	return defaultSchema().SDL()
 }
 
-type Mutation {
-	# Text utilities
-	textUppercase(text: String!): TextResult!
-	textLowercase(text: String!): TextResult!
-	textReverse(text: String!): TextResult!
-	textBase64Encode(text: String!): TextResult!
-	textBase64Decode(text: String!): TextResult!
-	textSlug(text: String!): TextResult!
-	textHash(text: String!): TextResult!
-
-	# Crypto utilities
-	bcryptHash(password: String!): TextResult!
-
-	# DateTime utilities
-	convertTimezone(timestamp: String!, from: String!, to: String!): TextResult!
-}
+// responseCache is the server's outbound-provider cache, shared so that a
+// resolver and its REST counterpart serve one cached entry. Unset, lookups
+// go straight to the provider.
+var responseCache atomic.Pointer[cache.ResponseCache]
 
-type Health {
-	status: String!
-	uptime: Int!
+// SetResponseCache installs the provider cache the weather and geocode
+// resolvers read through; nil disables it.
+func SetResponseCache(c *cache.ResponseCache) {
+	responseCache.Store(c)
 }
 
-type Version {
-	version: String!
-	commit_id: String!
-	build_date: String!
-}
-
-type TextResult {
-	result: String!
-}
-
-type CryptoResult {
-	result: String!
+// cachedLookup runs fetch through the response cache for provider and key.
+func cachedLookup[T any](provider, key string, fetch func() (T, error)) (interface{}, error) {
+	value, _, err := cache.FetchJSON(context.Background(), responseCache.Load(), provider, key, func(context.Context) (T, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
 }
 
-type DateTimeResult {
-	result: String!
-}
-`
-}
+// maxRequestBytes caps a request body, matching the REST JSON endpoints.
+const maxRequestBytes = 1 << 20
 
 // HandleQuery handles GraphQL queries
 func HandleQuery(w http.ResponseWriter, r *http.Request) {
@@ -351,12 +431,18 @@ func HandleQuery(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req Request
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Invalid request", http.StatusBadRequest)
 		return
 	}
 
-	resp := executeQuery(req.Query, req.Variables)
+	resp := executeQuery(req)
 
 	body, err := json.MarshalIndent(resp, "", "  ")
 	if err != nil {
@@ -372,93 +458,6 @@ func HandleQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// executeQuery parses and resolves a GraphQL query/mutation against the
-// schema built by BuildSchema(), returning real resolver output rather than
-// pattern-matched placeholders.
-func executeQuery(query string, variables map[string]interface{}) Response {
-	op, err := newDocParser(query).parseDocument()
-	if err != nil {
-		return Response{Errors: []Error{{Message: err.Error()}}}
-	}
-
-	schema := BuildSchema()
-	root := schema.Query
-	rootName := "Query"
-	if op.Type == "mutation" {
-		root = schema.Mutation
-		rootName = "Mutation"
-	}
-
-	if variables == nil {
-		variables = map[string]interface{}{}
-	}
-
-	data := map[string]interface{}{}
-	var errs []Error
-
-	for _, sel := range op.Selections {
-		field, ok := root.Fields[sel.Name]
-		if !ok {
-			errs = append(errs, Error{
-				Message: fmt.Sprintf("Cannot query field %q on type %q.", sel.Name, rootName),
-				Path:    []string{sel.Name},
-			})
-			continue
-		}
-
-		args := map[string]interface{}{}
-		for name, val := range sel.Arguments {
-			args[name] = val.resolve(variables)
-		}
-
-		result, err := field.Resolve(args)
-		if err != nil {
-			errs = append(errs, Error{Message: err.Error(), Path: []string{sel.Name}})
-			continue
-		}
-
-		key := sel.Name
-		if sel.Alias != "" {
-			key = sel.Alias
-		}
-		data[key] = applySelection(result, sel.Selections)
-	}
-
-	resp := Response{Errors: errs}
-	if len(data) > 0 {
-		resp.Data = data
-	}
-	return resp
-}
-
-// applySelection filters a resolver's result down to only the requested
-// GraphQL sub-fields, mirroring the query shape. Scalars and results with no
-// requested sub-selection are returned as-is.
-func applySelection(result interface{}, sels []selection) interface{} {
-	if len(sels) == 0 {
-		return result
-	}
-
-	m, ok := result.(map[string]interface{})
-	if !ok {
-		return result
-	}
-
-	filtered := map[string]interface{}{}
-	for _, sel := range sels {
-		value, present := m[sel.Name]
-		if !present {
-			continue
-		}
-		key := sel.Name
-		if sel.Alias != "" {
-			key = sel.Alias
-		}
-		filtered[key] = applySelection(value, sel.Selections)
-	}
-	return filtered
-}
-
 // ServeSchema serves the GraphQL schema (introspection)
 func ServeSchema(w http.ResponseWriter, r *http.Request) {
 	schema := GenerateSchemaSDL()
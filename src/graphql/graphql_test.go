@@ -2,12 +2,19 @@ package graphql
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/apimgr/api/src/cache"
+	"github.com/apimgr/api/src/config"
+	"github.com/apimgr/api/src/service/geo"
+	"github.com/apimgr/api/src/service/weather"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -68,6 +75,21 @@ func TestBuildSchema(t *testing.T) {
 		assert.Len(t, s, 36)
 	})
 
+	t.Run("mathEvaluate resolver evaluates with variables", func(t *testing.T) {
+		field, ok := schema.Query.Fields["mathEvaluate"]
+		require.True(t, ok)
+		result, err := field.Resolve(map[string]interface{}{"expression": "x^2 + 1", "variables": "x:3"})
+		require.NoError(t, err)
+		data := result.(map[string]interface{})
+		assert.Equal(t, 10.0, data["result"])
+	})
+
+	t.Run("mathEvaluate resolver errors on invalid expression", func(t *testing.T) {
+		field := schema.Query.Fields["mathEvaluate"]
+		_, err := field.Resolve(map[string]interface{}{"expression": "1 +"})
+		assert.Error(t, err)
+	})
+
 	t.Run("mutation textUppercase", func(t *testing.T) {
 		field, ok := schema.Mutation.Fields["textUppercase"]
 		require.True(t, ok)
@@ -114,9 +136,61 @@ func TestGenerateSchemaSDL(t *testing.T) {
 		"type Query", "type Mutation", "type Health", "type Version",
 		"type TextResult", "health: Health!", "textUppercase(text: String!): String!",
 		"bcryptHash(password: String!): TextResult!",
+		"mathEvaluate(expression: String!, variables: String): MathResult!", "type MathResult",
+		"scalar JSON", "type GeoDistance {\n\tbearing: Float!\n\tkilometers: Float!\n\tmiles: Float!\n}",
+		"geoDistance(lat1: Float!, lat2: Float!, lon1: Float!, lon2: Float!): GeoDistance!",
+		"mathStats(numbers: [Float!]!): MathStats!", "weatherCurrent(city: String!): CurrentWeather!",
 	} {
 		assert.Contains(t, sdl, want)
 	}
+
+	// Every field the executor can resolve is declared in the SDL.
+	schema := BuildSchema()
+	for name := range schema.Query.Fields {
+		assert.Contains(t, sdl, "\t"+name, "query field %s missing from SDL", name)
+	}
+	for name := range schema.Types {
+		assert.Contains(t, sdl, "type "+name+" {")
+	}
+}
+
+// TestSchemaTypesDefined checks that every type a field or argument refers
+// to is either a scalar or an object type registered in the schema, for
+// every service category exposed through GraphQL.
+func TestSchemaTypesDefined(t *testing.T) {
+	schema := BuildSchema()
+	defined := func(ref string) bool {
+		name := namedType(ref)
+		_, isObject := schema.Types[name]
+		return isObject || builtinScalars[name] || name == scalarJSON
+	}
+
+	objects := []*ObjectType{schema.Query, schema.Mutation}
+	for _, name := range sortedKeys(schema.Types) {
+		objects = append(objects, schema.Types[name])
+	}
+	for _, obj := range objects {
+		for fieldName, field := range obj.Fields {
+			assert.True(t, defined(field.Type), "%s.%s has undefined type %s", obj.Name, fieldName, field.Type)
+			for argName, arg := range field.Args {
+				assert.True(t, defined(arg.Type), "%s.%s(%s) has undefined type %s", obj.Name, fieldName, argName, arg.Type)
+			}
+		}
+	}
+
+	for _, prefix := range []string{
+		"text", "math", "convert", "geo", "validate", "parse", "crypto", "datetime", "weather",
+		"image", "network", "osint", "dev", "docker", "generate", "language", "lorem", "fun", "research", "test",
+	} {
+		found := false
+		for name := range schema.Query.Fields {
+			if strings.HasPrefix(name, prefix) {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "no query fields for category %s", prefix)
+	}
 }
 
 // TestServeSchema verifies the introspection endpoint serves the SDL as
@@ -226,15 +300,398 @@ func TestHandleQuery(t *testing.T) {
 	})
 }
 
+// postQuery runs a query through HandleQuery and decodes the response.
+func postQuery(t *testing.T, query string, variables map[string]interface{}) Response {
+	t.Helper()
+	body, _ := json.Marshal(Request{Query: query, Variables: variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	HandleQuery(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+// TestTypedSelections covers sub-field selection over typed object
+// results, list results and list arguments, plus the validation errors
+// for selections and arguments that the schema does not declare.
+func TestTypedSelections(t *testing.T) {
+	t.Run("object result is narrowed to the selected fields", func(t *testing.T) {
+		resp := postQuery(t, `{ geoDistance(lat1: 51.5074, lon1: -0.1278, lat2: 48.8566, lon2: 2.3522) { kilometers } }`, nil)
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["geoDistance"].(map[string]interface{})
+		assert.Len(t, result, 1)
+		assert.InDelta(t, 343.5, result["kilometers"], 1)
+	})
+
+	t.Run("aliases rename result keys", func(t *testing.T) {
+		resp := postQuery(t, `{ a: mathIsPrime(number: "7") { is_prime } b: mathIsPrime(number: "8") { is_prime } }`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		assert.Equal(t, true, data["a"].(map[string]interface{})["is_prime"])
+		assert.Equal(t, false, data["b"].(map[string]interface{})["is_prime"])
+	})
+
+	t.Run("list argument and variables of type Float", func(t *testing.T) {
+		resp := postQuery(t, `query($n: [Float!]!) { mathStats(numbers: $n) { count median } }`,
+			map[string]interface{}{"n": []interface{}{3, 1, 2}})
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["mathStats"].(map[string]interface{})
+		assert.Equal(t, 3.0, result["count"])
+		assert.Equal(t, 2.0, result["median"])
+
+		resp = postQuery(t, `{ mathStats(numbers: [4, 5.5]) { sum } }`, nil)
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, 9.5, resp.Data.(map[string]interface{})["mathStats"].(map[string]interface{})["sum"])
+	})
+
+	t.Run("map-shaped service results are typed", func(t *testing.T) {
+		resp := postQuery(t, `{ datetimeFromUnix(timestamp: "0") { iso8601 is_leap_year } }`, nil)
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["datetimeFromUnix"].(map[string]interface{})
+		assert.Equal(t, "1970-01-01T00:00:00Z", result["iso8601"])
+		assert.Equal(t, false, result["is_leap_year"])
+	})
+
+	t.Run("JSON object argument feeds JWT sign and verify", func(t *testing.T) {
+		resp := postQuery(t, `{ cryptoJWTSign(algorithm: "HS256", key: "k", claims: {sub: "alice", iss: "me"}) }`, nil)
+		require.Empty(t, resp.Errors)
+		token := resp.Data.(map[string]interface{})["cryptoJWTSign"].(string)
+
+		resp = postQuery(t, `query($t: String!) { cryptoJWTVerify(token: $t, key: "k", issuer: "me") { valid claims } }`,
+			map[string]interface{}{"t": token})
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["cryptoJWTVerify"].(map[string]interface{})
+		assert.Equal(t, true, result["valid"])
+		assert.Equal(t, "alice", result["claims"].(map[string]interface{})["sub"])
+	})
+
+	t.Run("password hash round-trips through verify", func(t *testing.T) {
+		resp := postQuery(t, `{ cryptoPasswordHash(password: "pw", algorithm: "scrypt", params: {ln: 4}) }`, nil)
+		require.Empty(t, resp.Errors)
+		hash := resp.Data.(map[string]interface{})["cryptoPasswordHash"].(string)
+		assert.True(t, strings.HasPrefix(hash, "$scrypt$ln=4,r=8,p=1$"), hash)
+
+		resp = postQuery(t, `query($h: String!) { cryptoPasswordVerify(password: "pw", hash: $h) { valid algorithm params { ln } } }`,
+			map[string]interface{}{"h": hash})
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["cryptoPasswordVerify"].(map[string]interface{})
+		assert.Equal(t, true, result["valid"])
+		assert.Equal(t, "scrypt", result["algorithm"])
+		assert.EqualValues(t, 4, result["params"].(map[string]interface{})["ln"])
+	})
+
+	t.Run("CSR signed by a self-signed CA verifies", func(t *testing.T) {
+		resp := postQuery(t, `{ cryptoSelfSignedCertificate(common_name: "Root") { certificate private_key }
+			cryptoCSR(common_name: "leaf.example", dns_names: ["leaf.example"]) { csr info { signature_valid dns_names } } }`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		root := data["cryptoSelfSignedCertificate"].(map[string]interface{})
+		csr := data["cryptoCSR"].(map[string]interface{})
+		assert.Equal(t, true, csr["info"].(map[string]interface{})["signature_valid"])
+
+		resp = postQuery(t, `query($csr: String!, $ca: String!, $key: String!) { cryptoCertificateSign(csr: $csr, ca_certificate: $ca, ca_key: $key) }`,
+			map[string]interface{}{"csr": csr["csr"], "ca": root["certificate"], "key": root["private_key"]})
+		require.Empty(t, resp.Errors)
+		leaf := resp.Data.(map[string]interface{})["cryptoCertificateSign"]
+
+		resp = postQuery(t, `query($leaf: String!, $ca: String!) { cryptoCertificateVerify(chain: [$leaf], roots: $ca, dns_name: "leaf.example") { valid chains { subject } } }`,
+			map[string]interface{}{"leaf": leaf, "ca": root["certificate"]})
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, true, resp.Data.(map[string]interface{})["cryptoCertificateVerify"].(map[string]interface{})["valid"])
+	})
+
+	t.Run("mnemonic derives a BIP32 key", func(t *testing.T) {
+		resp := postQuery(t, `{ cryptoMnemonic(entropy: "00000000000000000000000000000000") { mnemonic words language }
+			cryptoMnemonicValidate(mnemonic: "abandon abandon abandon") { valid error } }`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		generated := data["cryptoMnemonic"].(map[string]interface{})
+		assert.EqualValues(t, 12, generated["words"])
+		assert.Equal(t, "english", generated["language"])
+		assert.Equal(t, false, data["cryptoMnemonicValidate"].(map[string]interface{})["valid"])
+
+		resp = postQuery(t, `query($m: String!) { cryptoMnemonicSeed(mnemonic: $m, passphrase: "TREZOR") { extended_private_key }
+			cryptoBIP32Derive(mnemonic: $m, passphrase: "TREZOR", path: "m/44'/0'/0'/0/0") { depth child_number public_key } }`,
+			map[string]interface{}{"m": generated["mnemonic"]})
+		require.Empty(t, resp.Errors)
+		data = resp.Data.(map[string]interface{})
+		assert.Equal(t, "xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF",
+			data["cryptoMnemonicSeed"].(map[string]interface{})["extended_private_key"])
+		derived := data["cryptoBIP32Derive"].(map[string]interface{})
+		assert.EqualValues(t, 5, derived["depth"])
+		assert.Equal(t, "0", derived["child_number"])
+		assert.Len(t, derived["public_key"], 66)
+	})
+
+	t.Run("hotp codes and otpauth URIs", func(t *testing.T) {
+		const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		resp := postQuery(t, `query($s: String!) { cryptoHOTPCode(secret: $s, counter: 1)
+			cryptoHOTPVerify(secret: $s, code: "162583", counter: 5, look_ahead: 3) { valid counter next_counter }
+			cryptoTOTPCode(secret: $s, algorithm: "SHA512", digits: 8) }`, map[string]interface{}{"s": secret})
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		assert.Equal(t, "287082", data["cryptoHOTPCode"])
+		verification := data["cryptoHOTPVerify"].(map[string]interface{})
+		assert.Equal(t, true, verification["valid"])
+		assert.EqualValues(t, 8, verification["next_counter"])
+		assert.Len(t, data["cryptoTOTPCode"], 8)
+
+		uri := "otpauth://totp/ACME:alice?secret=" + secret + "&issuer=ACME&algorithm=SHA256&period=60"
+		resp = postQuery(t, `query($u: String!) { cryptoOTPAuthParse(uri: $u) { type issuer account algorithm digits period }
+			cryptoOTPAuthQR(uri: $u, size: 128) }`, map[string]interface{}{"u": uri})
+		require.Empty(t, resp.Errors)
+		data = resp.Data.(map[string]interface{})
+		key := data["cryptoOTPAuthParse"].(map[string]interface{})
+		assert.Equal(t, "ACME", key["issuer"])
+		assert.Equal(t, "SHA256", key["algorithm"])
+		assert.EqualValues(t, 60, key["period"])
+		png, err := base64.StdEncoding.DecodeString(data["cryptoOTPAuthQR"].(string))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+	})
+
+	t.Run("explicit ciphers, key wrap and age", func(t *testing.T) {
+		zeros := strings.Repeat("00", 16)
+		resp := postQuery(t, `query($z: String!, $iv: String!) {
+			cryptoCipherEncrypt(key: $z, iv: $iv, plaintext: $z, plaintext_encoding: "hex") { ciphertext iv key }
+			cryptoCipherDecrypt(key: $z, iv: $iv, ciphertext: "0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf", plaintext_encoding: "hex")
+			cryptoKeyWrap(kek: "000102030405060708090a0b0c0d0e0f", key: "00112233445566778899aabbccddeeff")
+			cryptoKDF(passphrase: "pw", kdf: {algorithm: "pbkdf2-sha256", salt: "0001020304050607", params: {iterations: 1}}) { algorithm salt key params { iterations } }
+		}`, map[string]interface{}{"z": zeros, "iv": strings.Repeat("00", 12)})
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		encrypted := data["cryptoCipherEncrypt"].(map[string]interface{})
+		assert.Equal(t, "0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf", encrypted["ciphertext"])
+		assert.Equal(t, "", encrypted["key"])
+		assert.Equal(t, zeros, data["cryptoCipherDecrypt"])
+		assert.Equal(t, "1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5", data["cryptoKeyWrap"])
+		derived := data["cryptoKDF"].(map[string]interface{})
+		assert.Equal(t, "0001020304050607", derived["salt"])
+		assert.EqualValues(t, 1, derived["params"].(map[string]interface{})["iterations"])
+		assert.Len(t, derived["key"], 64)
+
+		resp = postQuery(t, `{ cryptoAgeEncrypt(plaintext: "hello", passphrase: "pw", work_factor: 8) }`, nil)
+		require.Empty(t, resp.Errors)
+		armored := resp.Data.(map[string]interface{})["cryptoAgeEncrypt"].(string)
+		resp = postQuery(t, `query($c: String!) { cryptoAgeDecrypt(ciphertext: $c, passphrase: "pw") }`, map[string]interface{}{"c": armored})
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, "hello", resp.Data.(map[string]interface{})["cryptoAgeDecrypt"])
+
+		resp = postQuery(t, `{ cryptoCipherEncrypt(plaintext: "x") { ciphertext } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "exactly one of key and passphrase")
+	})
+
+	t.Run("shamir split and combine", func(t *testing.T) {
+		resp := postQuery(t, `{ cryptoShamirSplit(secret: "break-glass", shares: 3, threshold: 2, encoding: "base64") { id threshold shares } }`, nil)
+		require.Empty(t, resp.Errors)
+		split := resp.Data.(map[string]interface{})["cryptoShamirSplit"].(map[string]interface{})
+		shares := split["shares"].([]interface{})
+		require.Len(t, shares, 3)
+		assert.Len(t, split["id"], 8)
+
+		resp = postQuery(t, `query($s: [String!]!) { cryptoShamirCombine(shares: $s, encoding: "base64") }`,
+			map[string]interface{}{"s": []interface{}{shares[2], shares[0]}})
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, "break-glass", resp.Data.(map[string]interface{})["cryptoShamirCombine"])
+
+		resp = postQuery(t, `query($s: [String!]!) { cryptoShamirCombine(shares: $s, encoding: "base64") }`,
+			map[string]interface{}{"s": []interface{}{shares[1]}})
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "at least 2")
+	})
+
+	t.Run("password strength with pattern detection", func(t *testing.T) {
+		resp := postQuery(t, `{ cryptoPasswordStrength(password: "jessica1990", user_inputs: ["jo@example.com"]) {
+			score strength feedback { warning }
+			crack_times { online_throttling_100_per_hour { display } }
+			sequence { pattern token dictionary year }
+		} }`, nil)
+		require.Empty(t, resp.Errors)
+		got := resp.Data.(map[string]interface{})["cryptoPasswordStrength"].(map[string]interface{})
+		assert.Equal(t, "fair", got["strength"])
+		assert.Equal(t, "Common names and surnames are easy to guess", got["feedback"].(map[string]interface{})["warning"])
+		seq := got["sequence"].([]interface{})
+		require.Len(t, seq, 2)
+		assert.Equal(t, "female_names", seq[0].(map[string]interface{})["dictionary"])
+		assert.Equal(t, "year", seq[1].(map[string]interface{})["pattern"])
+		assert.EqualValues(t, 1990, seq[1].(map[string]interface{})["year"])
+
+		resp = postQuery(t, `{ validate(kind: "password", value: "Password1") { valid } }`, nil)
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, false, resp.Data.(map[string]interface{})["validate"].(map[string]interface{})["valid"])
+	})
+
+	t.Run("cron runs in a timezone with a description", func(t *testing.T) {
+		resp := postQuery(t, `{ datetimeCron(expression: "0 0 9 * * MON-FRI", timezone: "Europe/Berlin", count: 2, start: "2026-07-17T12:00:00Z") {
+			description timezone fields { second day_of_week year } next_runs previous_runs
+		} }`, nil)
+		require.Empty(t, resp.Errors)
+		got := resp.Data.(map[string]interface{})["datetimeCron"].(map[string]interface{})
+		assert.Equal(t, "At 09:00, Monday through Friday", got["description"])
+		assert.Equal(t, "Europe/Berlin", got["timezone"])
+		assert.Equal(t, map[string]interface{}{"second": "0", "day_of_week": "MON-FRI", "year": ""}, got["fields"])
+		assert.Equal(t, []interface{}{"2026-07-20T09:00:00+02:00", "2026-07-21T09:00:00+02:00"}, got["next_runs"])
+		assert.Equal(t, []interface{}{"2026-07-17T09:00:00+02:00", "2026-07-16T09:00:00+02:00"}, got["previous_runs"])
+
+		resp = postQuery(t, `{ datetimeCron(expression: "@daily", start: "noon") { next_runs } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "RFC3339")
+	})
+
+	t.Run("business days and holidays", func(t *testing.T) {
+		resp := postQuery(t, `{
+			datetimeWorkdays(start: "2024-12-23", end: "2024-12-27", country: "US") { workdays holiday_days holidays { date name } }
+			datetimeAddBusinessDays(date: "2024-12-24", days: 1, country: "US") { result skipped_holidays { name } }
+			datetimeHolidays(country: "DE", year: 2025, region: "BY") { country_name region_name count holidays { date observed name regions } }
+			datetimeHolidayCalendars { country regions }
+		}`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		workdays := data["datetimeWorkdays"].(map[string]interface{})
+		assert.EqualValues(t, 4, workdays["workdays"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"date": "2024-12-25", "name": "Christmas Day"}}, workdays["holidays"])
+		assert.Equal(t, "2024-12-26", data["datetimeAddBusinessDays"].(map[string]interface{})["result"])
+		holidays := data["datetimeHolidays"].(map[string]interface{})
+		assert.Equal(t, "Bavaria", holidays["region_name"])
+		assert.Contains(t, holidays["holidays"], map[string]interface{}{"date": "2025-01-06", "observed": nil, "name": "Epiphany", "regions": []interface{}{"BW", "BY", "ST"}})
+		assert.Len(t, data["datetimeHolidayCalendars"], 8)
+
+		resp = postQuery(t, `{ datetimeWorkdays(start: "2024-12-23", end: "2024-12-27", weekend: "caturday") { workdays } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "invalid weekend day")
+	})
+
+	t.Run("natural-language dates relative to a reference", func(t *testing.T) {
+		resp := postQuery(t, `{
+			datetimeParse(value: "last day of next month", reference: "2026-10-16T14:30:00Z", timezone: "Asia/Tokyo") { kind interpretation iso8601 timezone }
+			interval: datetimeParse(value: "2024-01-01/P1M", timezone: "UTC") { kind end duration duration_seconds }
+			parseDateTime(input: "in 2 hours", reference: "2026-10-16T14:30:00Z")
+		}`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{
+			"kind":           "instant",
+			"interpretation": "the last day of November 2026",
+			"iso8601":        "2026-11-30T00:00:00+09:00",
+			"timezone":       "Asia/Tokyo",
+		}, data["datetimeParse"])
+		assert.Equal(t, map[string]interface{}{
+			"kind":             "interval",
+			"end":              "2024-02-01T00:00:00Z",
+			"duration":         "P1M",
+			"duration_seconds": float64(31 * 24 * 3600),
+		}, data["interval"])
+		assert.Equal(t, "2026-10-16T16:30:00Z", data["parseDateTime"])
+
+		resp = postQuery(t, `{ datetimeParse(value: "whenever") { iso8601 } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, `unrecognised "whenever"`)
+	})
+
+	t.Run("calendar conversion", func(t *testing.T) {
+		resp := postQuery(t, `{
+			datetimeCalendarConvert(value: "2023-2L-1", from: "chinese", timezone: "Asia/Shanghai") {
+				datetime { date }
+				calendars { hebrew { formatted } persian { month_name } chinese { leap_month zodiac } }
+				epochs { modified_julian_day excel_serial }
+			}
+			early: datetimeCalendarConvert(value: "0", from: "dotnet_ticks") { calendars { chinese { year } } epochs { excel_serial } }
+		}`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{
+			"datetime": map[string]interface{}{"date": "2023-03-22"},
+			"calendars": map[string]interface{}{
+				"hebrew":  map[string]interface{}{"formatted": "29 Adar 5783"},
+				"persian": map[string]interface{}{"month_name": "Farvardin"},
+				"chinese": map[string]interface{}{"leap_month": true, "zodiac": "Rabbit"},
+			},
+			"epochs": map[string]interface{}{"modified_julian_day": 60024.66666667, "excel_serial": float64(45007)},
+		}, data["datetimeCalendarConvert"])
+		assert.Equal(t, map[string]interface{}{
+			"calendars": map[string]interface{}{"chinese": nil},
+			"epochs":    map[string]interface{}{"excel_serial": nil},
+		}, data["early"])
+
+		resp = postQuery(t, `{ datetimeCalendarConvert(value: "1", from: "mayan") { from } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, `unknown calendar "mayan"`)
+	})
+
+	t.Run("recurrence rules and iCalendar parsing", func(t *testing.T) {
+		resp := postQuery(t, `query($ics: String!) {
+			datetimeRRule(rule: "FREQ=MONTHLY;BYDAY=-1FR", start: "2026-01-30T17:00", timezone: "Europe/London", exdates: ["2026-02-27"], count: 2) {
+				normalized description occurrences finite has_more
+			}
+			datetimeICSParse(ics: $ics) { timezones count events { uid start rrule_description occurrences } }
+		}`, map[string]interface{}{"ics": "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:gym\r\nDTSTART;TZID=Europe/Berlin:20260105T070000\r\nRRULE:FREQ=WEEKLY;COUNT=2\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"})
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		rrule := data["datetimeRRule"].(map[string]interface{})
+		assert.Equal(t, "FREQ=MONTHLY;BYDAY=-1FR", rrule["normalized"])
+		assert.Equal(t, "Every month on the last Friday", rrule["description"])
+		assert.Equal(t, []interface{}{"2026-01-30T17:00:00Z", "2026-03-27T17:00:00Z"}, rrule["occurrences"])
+		assert.Equal(t, false, rrule["finite"])
+		assert.Equal(t, true, rrule["has_more"])
+		ics := data["datetimeICSParse"].(map[string]interface{})
+		assert.EqualValues(t, 1, ics["count"])
+		event := ics["events"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "2026-01-05T07:00:00+01:00", event["start"])
+		assert.Equal(t, []interface{}{"2026-01-05T07:00:00+01:00", "2026-01-12T07:00:00+01:00"}, event["occurrences"])
+
+		resp = postQuery(t, `{ datetimeRRule(rule: "FREQ=HOURLY;BYWEEKNO=20", start: "2026-01-01") { count } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "not supported")
+	})
+
+	t.Run("undeclared sub-field is a validation error with a location", func(t *testing.T) {
+		resp := postQuery(t, `{ geoDistance(lat1: 0, lon1: 0, lat2: 1, lon2: 1) { furlongs } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, `Cannot query field "furlongs" on type "GeoDistance".`, resp.Errors[0].Message)
+		assert.Equal(t, []Location{{Line: 1, Column: 53}}, resp.Errors[0].Locations)
+		assert.Nil(t, resp.Data)
+	})
+
+	t.Run("selection on a scalar is an error", func(t *testing.T) {
+		resp := postQuery(t, `{ textUppercase(text: "a") { length } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "has no subfields")
+	})
+
+	t.Run("unknown argument is an error", func(t *testing.T) {
+		resp := postQuery(t, `{ textUppercase(text: "a", shout: true) }`, nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, `Unknown argument "shout"`)
+	})
+
+	t.Run("missing required argument is an error", func(t *testing.T) {
+		resp := postQuery(t, `{ geoDistance(lat1: 0, lon1: 0) { miles } }`, nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, `argument "lat2" of type "Float!" is required, but it was not provided`)
+	})
+
+	t.Run("resolver error is reported against the field", func(t *testing.T) {
+		resp := postQuery(t, `{ geoDistance(lat1: 95, lon1: 0, lat2: 0, lon2: 0) { miles } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "invalid coordinate")
+		assert.Equal(t, []interface{}{"geoDistance"}, resp.Errors[0].Path)
+		assert.Equal(t, []Location{{Line: 1, Column: 3}}, resp.Errors[0].Locations)
+	})
+}
+
 // TestParserArgumentValues exercises the argument/value grammar (strings,
 // numbers, booleans, null, and variables) directly against the hand-rolled
 // parser, independent of any particular schema field.
 func TestParserArgumentValues(t *testing.T) {
 	t.Run("string, numeric, boolean, and null literals parse", func(t *testing.T) {
-		op, err := newDocParser(`{ field(a: "x", b: 42, c: true, d: false, e: null) }`).parseDocument()
+		doc, err := newDocParser(`{ field(a: "x", b: 42, c: true, d: false, e: null) }`).parseDocument()
 		require.NoError(t, err)
-		require.Len(t, op.Selections, 1)
-		args := op.Selections[0].Arguments
+		require.Len(t, doc.Operations[0].Selections, 1)
+		args := doc.Operations[0].Selections[0].Arguments
 		assert.Equal(t, "x", args["a"].resolve(nil))
 		assert.Equal(t, 42, args["b"].resolve(nil))
 		assert.Equal(t, true, args["c"].resolve(nil))
@@ -243,24 +700,24 @@ func TestParserArgumentValues(t *testing.T) {
 	})
 
 	t.Run("negative and floating point numbers parse", func(t *testing.T) {
-		op, err := newDocParser(`{ field(a: -3, b: 1.5) }`).parseDocument()
+		doc, err := newDocParser(`{ field(a: -3, b: 1.5) }`).parseDocument()
 		require.NoError(t, err)
-		args := op.Selections[0].Arguments
+		args := doc.Operations[0].Selections[0].Arguments
 		assert.Equal(t, -3, args["a"].resolve(nil))
 		assert.Equal(t, 1.5, args["b"].resolve(nil))
 	})
 
 	t.Run("variable argument resolves from the variables map", func(t *testing.T) {
-		op, err := newDocParser(`query($v: String!) { field(a: $v) }`).parseDocument()
+		doc, err := newDocParser(`query($v: String!) { field(a: $v) }`).parseDocument()
 		require.NoError(t, err)
-		val := op.Selections[0].Arguments["a"].resolve(map[string]interface{}{"v": "hello"})
+		val := doc.Operations[0].Selections[0].Arguments["a"].resolve(map[string]interface{}{"v": "hello"})
 		assert.Equal(t, "hello", val)
 	})
 
 	t.Run("escaped string literal decodes escape sequences", func(t *testing.T) {
-		op, err := newDocParser(`{ field(a: "line1\nline2\ttab\"quote\"") }`).parseDocument()
+		doc, err := newDocParser(`{ field(a: "line1\nline2\ttab\"quote\"") }`).parseDocument()
 		require.NoError(t, err)
-		assert.Equal(t, "line1\nline2\ttab\"quote\"", op.Selections[0].Arguments["a"].resolve(nil))
+		assert.Equal(t, "line1\nline2\ttab\"quote\"", doc.Operations[0].Selections[0].Arguments["a"].resolve(nil))
 	})
 
 	t.Run("unterminated argument list is a syntax error", func(t *testing.T) {
@@ -278,3 +735,323 @@ func TestParserArgumentValues(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// TestIntrospection covers the __schema, __type and __typename meta
+// fields GraphiQL relies on.
+func TestIntrospection(t *testing.T) {
+	t.Run("__schema lists root, result, scalar and introspection types", func(t *testing.T) {
+		resp := postQuery(t, `{ __schema { queryType { name } mutationType { name } subscriptionType { name } types { name kind } directives { name locations } } }`, nil)
+		require.Empty(t, resp.Errors)
+		schema := resp.Data.(map[string]interface{})["__schema"].(map[string]interface{})
+		assert.Equal(t, "Query", schema["queryType"].(map[string]interface{})["name"])
+		assert.Equal(t, "Mutation", schema["mutationType"].(map[string]interface{})["name"])
+		assert.Nil(t, schema["subscriptionType"])
+
+		kinds := map[string]string{}
+		for _, typ := range schema["types"].([]interface{}) {
+			typ := typ.(map[string]interface{})
+			kinds[typ["name"].(string)] = typ["kind"].(string)
+		}
+		assert.Equal(t, "OBJECT", kinds["Query"])
+		assert.Equal(t, "OBJECT", kinds["GeoDistance"])
+		assert.Equal(t, "OBJECT", kinds["__Type"])
+		assert.Equal(t, "SCALAR", kinds["String"])
+		assert.Equal(t, "ENUM", kinds["__TypeKind"])
+
+		var directives []string
+		for _, d := range schema["directives"].([]interface{}) {
+			directives = append(directives, d.(map[string]interface{})["name"].(string))
+		}
+		assert.ElementsMatch(t, []string{"include", "skip", "deprecated"}, directives)
+	})
+
+	t.Run("__type unwraps non-null and list wrappers", func(t *testing.T) {
+		resp := postQuery(t, `query($name: String!) {
+			__type(name: $name) { name kind fields { name type { kind name ofType { kind name } } } }
+		}`, map[string]interface{}{"name": "GeoDistance"})
+		require.Empty(t, resp.Errors)
+		typ := resp.Data.(map[string]interface{})["__type"].(map[string]interface{})
+		assert.Equal(t, "OBJECT", typ["kind"])
+		var kilometers map[string]interface{}
+		for _, f := range typ["fields"].([]interface{}) {
+			if f := f.(map[string]interface{}); f["name"] == "kilometers" {
+				kilometers = f["type"].(map[string]interface{})
+			}
+		}
+		require.NotNil(t, kilometers)
+		assert.Equal(t, "NON_NULL", kilometers["kind"])
+		assert.Nil(t, kilometers["name"])
+		assert.Equal(t, map[string]interface{}{"kind": "SCALAR", "name": "Float"}, kilometers["ofType"])
+	})
+
+	t.Run("__type of an unknown type is null", func(t *testing.T) {
+		resp := postQuery(t, `{ __type(name: "Nope") { name } }`, nil)
+		require.Empty(t, resp.Errors)
+		assert.Nil(t, resp.Data.(map[string]interface{})["__type"])
+	})
+
+	t.Run("field arguments are described with their defaults", func(t *testing.T) {
+		resp := postQuery(t, `{ __type(name: "__Type") { fields { name args { name defaultValue type { name } } } } }`, nil)
+		require.Empty(t, resp.Errors)
+		for _, f := range resp.Data.(map[string]interface{})["__type"].(map[string]interface{})["fields"].([]interface{}) {
+			if f := f.(map[string]interface{}); f["name"] == "fields" {
+				args := f["args"].([]interface{})
+				require.Len(t, args, 1)
+				assert.Equal(t, "false", args[0].(map[string]interface{})["defaultValue"])
+			}
+		}
+	})
+
+	t.Run("__typename resolves on every object", func(t *testing.T) {
+		resp := postQuery(t, `{ __typename health { __typename status } }`, nil)
+		require.Empty(t, resp.Errors)
+		data := resp.Data.(map[string]interface{})
+		assert.Equal(t, "Query", data["__typename"])
+		assert.Equal(t, "Health", data["health"].(map[string]interface{})["__typename"])
+	})
+}
+
+// TestFragmentsAndDirectives covers named and inline fragments and the
+// @skip/@include directives.
+func TestFragmentsAndDirectives(t *testing.T) {
+	t.Run("named fragment spreads its fields", func(t *testing.T) {
+		resp := postQuery(t, `
+			query { geoDistance(lat1: 0, lon1: 0, lat2: 1, lon2: 1) { ...Distances } }
+			fragment Distances on GeoDistance { kilometers miles }`, nil)
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["geoDistance"].(map[string]interface{})
+		assert.Contains(t, result, "kilometers")
+		assert.Contains(t, result, "miles")
+	})
+
+	t.Run("inline fragments with and without a type condition", func(t *testing.T) {
+		resp := postQuery(t, `{ health { ... on Health { status } ... { uptime } } }`, nil)
+		require.Empty(t, resp.Errors)
+		result := resp.Data.(map[string]interface{})["health"].(map[string]interface{})
+		assert.Contains(t, result, "status")
+		assert.Contains(t, result, "uptime")
+	})
+
+	t.Run("skip and include follow their variables", func(t *testing.T) {
+		query := `query($withStatus: Boolean!, $skipUptime: Boolean = true) {
+			health { status @include(if: $withStatus) uptime @skip(if: $skipUptime) }
+		}`
+		resp := postQuery(t, query, map[string]interface{}{"withStatus": false})
+		require.Empty(t, resp.Errors)
+		assert.Empty(t, resp.Data.(map[string]interface{})["health"])
+
+		resp = postQuery(t, query, map[string]interface{}{"withStatus": true, "skipUptime": false})
+		require.Empty(t, resp.Errors)
+		assert.Len(t, resp.Data.(map[string]interface{})["health"], 2)
+	})
+
+	t.Run("fragment errors", func(t *testing.T) {
+		cases := map[string]string{
+			`{ health { ...Missing } }`:                                   `Unknown fragment "Missing".`,
+			`{ health { status } } fragment Unused on Health { status }`:  `Fragment "Unused" is never used.`,
+			`{ health { ...A } } fragment A on Health { ...A }`:           `Cannot spread fragment "A" within itself.`,
+			`{ health { ...V } } fragment V on Version { version }`:       `Fragment "V" cannot be spread here as objects of type "Health" can never be of type "Version".`,
+			`{ health { ... on Nope { status } } }`:                       `Unknown type "Nope".`,
+			`{ health { status @unknown } }`:                              `Unknown directive "@unknown".`,
+			`{ health { status @skip } }`:                                 `Directive "@skip" argument "if" of type "Boolean!" is required, but it was not provided.`,
+			`{ health { status @skip(if: "yes") } }`:                      `Expected value of type "Boolean!", found "yes"`,
+			`{ health { a: status a: uptime } }`:                          `Fields "a" conflict because "status" and "uptime" are different fields.`,
+			`{ health }`:                                                  `Field "health" of type "Health!" must have a selection of subfields.`,
+			`query A { health { status } } query A { health { status } }`: `There can be only one operation named "A".`,
+			`{ health { status } } query B { health { status } }`:         `This anonymous operation must be the only defined operation.`,
+			`subscription { health { status } }`:                          `Schema is not configured for subscriptions.`,
+		}
+		for query, want := range cases {
+			resp := postQuery(t, query, nil)
+			require.NotEmpty(t, resp.Errors, query)
+			assert.Contains(t, resp.Errors[0].Message, want, query)
+			assert.NotEmpty(t, resp.Errors[0].Locations, query)
+			assert.Nil(t, resp.Data, query)
+		}
+	})
+}
+
+// TestQueryLimits covers the request size, depth, field and outbound
+// field caps.
+func TestQueryLimits(t *testing.T) {
+	t.Run("oversized body is rejected", func(t *testing.T) {
+		body, _ := json.Marshal(Request{Query: "{ health { status } }" + strings.Repeat(" ", maxRequestBytes)})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		HandleQuery(rec, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		nested := func(levels int) string {
+			return `{ __type(name: "String") { ` + strings.Repeat("ofType { ", levels) + "name" + strings.Repeat(" }", levels) + " } }"
+		}
+		resp := postQuery(t, nested(maxQueryDepth-2), nil)
+		require.Empty(t, resp.Errors)
+
+		resp = postQuery(t, nested(maxQueryDepth), nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, "nested more than")
+		assert.Nil(t, resp.Data)
+	})
+
+	t.Run("aliased fields", func(t *testing.T) {
+		aliases := func(n int) string {
+			var b strings.Builder
+			b.WriteString("{ ")
+			for i := range n {
+				fmt.Fprintf(&b, "a%d: __typename ", i)
+			}
+			return b.String() + "}"
+		}
+		resp := postQuery(t, aliases(maxQueryFields), nil)
+		require.Empty(t, resp.Errors)
+
+		resp = postQuery(t, aliases(maxQueryFields+1), nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, "more than 500 fields")
+	})
+
+	t.Run("fields are counted after fragment expansion", func(t *testing.T) {
+		// Each fragment selects the next one under two aliases, so the
+		// ten-line query below resolves over two thousand fields.
+		var b strings.Builder
+		b.WriteString(`{ __type(name: "String") { ...T0 } }` + "\n")
+		for i := range 9 {
+			fmt.Fprintf(&b, "fragment T%d on __Type { a: ofType { ...T%d } b: ofType { ...T%d } }\n", i, i+1, i+1)
+		}
+		b.WriteString("fragment T9 on __Type { name kind }")
+		resp := postQuery(t, b.String(), nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, "more than 500 fields")
+		assert.Nil(t, resp.Data)
+	})
+
+	t.Run("outbound fields through a fragment", func(t *testing.T) {
+		resp := postQuery(t, `{ a: osintIP(ip: "192.0.2.1") { ip } ...W } fragment W on Query { networkWhois(domain: "example.com") }`, nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, "2 network or OSINT fields")
+	})
+
+	t.Run("outbound fields", func(t *testing.T) {
+		resp := postQuery(t, `{ a: osintIP(ip: "192.0.2.1") { ip } b: networkWhois(domain: "example.com") }`, nil)
+		require.NotEmpty(t, resp.Errors)
+		assert.Contains(t, resp.Errors[0].Message, "2 network or OSINT fields")
+		assert.Nil(t, resp.Data)
+	})
+}
+
+// TestCachedResolvers checks that the provider-backed resolvers read
+// through the response cache the REST handlers share, under the same
+// keys, so a cached entry is served without contacting the provider.
+func TestCachedResolvers(t *testing.T) {
+	cfg := config.CacheConfig{
+		Type:      "memory",
+		TTL:       "1h",
+		Responses: config.ResponseCacheConfig{Enabled: true, Stale: "1h"},
+	}
+	rc := cache.NewResponseCache(cache.New(cfg), cfg)
+	SetResponseCache(rc)
+	t.Cleanup(func() { SetResponseCache(nil) })
+
+	seed := func(provider, key string, value interface{}) {
+		_, _, err := cache.FetchJSON(context.Background(), rc, provider, key, func(context.Context) (interface{}, error) {
+			return value, nil
+		})
+		require.NoError(t, err)
+	}
+	seed("geocode", geo.ReverseGeocodeCacheKey(1.5, 2.5), geo.ReverseGeocodeResult{DisplayName: "Cached Place"})
+	seed("weather", weather.CacheKey("current", "Atlantis"), weather.CurrentWeather{Temperature: 21.5})
+
+	resp := postQuery(t, `{ geoReverse(lat: 1.5, lon: 2.5) { display_name } weatherCurrent(city: " atlantis") { temperature } }`, nil)
+	require.Empty(t, resp.Errors)
+	data := resp.Data.(map[string]interface{})
+	assert.Equal(t, "Cached Place", data["geoReverse"].(map[string]interface{})["display_name"])
+	assert.Equal(t, 21.5, data["weatherCurrent"].(map[string]interface{})["temperature"])
+}
+
+// TestVariablesAndOperations covers variable type checking, coercion and
+// operation selection by name.
+func TestVariablesAndOperations(t *testing.T) {
+	t.Run("validation errors for variable usage", func(t *testing.T) {
+		cases := map[string]string{
+			`{ textUppercase(text: $t) }`:                      `Variable "$t" is not defined.`,
+			`query Q($t: String!) { generateUUID }`:            `Variable "$t" is never used in operation "Q".`,
+			`query($t: Int!) { textUppercase(text: $t) }`:      `Variable "$t" of type "Int!" used in position expecting type "String!".`,
+			`query($t: String) { textUppercase(text: $t) }`:    `Variable "$t" of type "String" used in position expecting type "String!".`,
+			`query($t: Health) { generateUUID @skip(if: $t) }`: `Variable "$t" cannot be non-input type "Health".`,
+			`{ textUppercase(text: 5) }`:                       `Expected value of type "String!", found 5`,
+			`{ mathStats(numbers: [1, "two"]) { sum } }`:       `Expected value of type "Float!", found "two"`,
+		}
+		for query, want := range cases {
+			resp := postQuery(t, query, nil)
+			require.NotEmpty(t, resp.Errors, query)
+			assert.Contains(t, resp.Errors[0].Message, want, query)
+		}
+	})
+
+	t.Run("variable values are coerced to their declared type", func(t *testing.T) {
+		resp := postQuery(t, `query($t: String!) { textUppercase(text: $t) }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, `Variable "$t" of required type "String!" was not provided.`, resp.Errors[0].Message)
+		assert.Equal(t, []Location{{Line: 1, Column: 7}}, resp.Errors[0].Locations)
+
+		resp = postQuery(t, `query($t: String!) { textUppercase(text: $t) }`, map[string]interface{}{"t": 5})
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, `Variable "$t" got invalid value 5; String cannot represent a non string value: 5`)
+
+		resp = postQuery(t, `query($n: Float!) { mathStats(numbers: $n) { count } }`, map[string]interface{}{"n": 4})
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, `used in position expecting type "[Float!]!"`)
+
+		resp = postQuery(t, `query($n: [Float!]!) { mathStats(numbers: $n) { count } }`, map[string]interface{}{"n": 4})
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, 1.0, resp.Data.(map[string]interface{})["mathStats"].(map[string]interface{})["count"])
+	})
+
+	t.Run("operationName selects the operation to run", func(t *testing.T) {
+		query := `query Upper { textUppercase(text: "a") } query UUID { generateUUID }`
+		body, _ := json.Marshal(Request{Query: query, OperationName: "Upper"})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		HandleQuery(rec, req)
+		var resp Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, map[string]interface{}{"textUppercase": "A"}, resp.Data)
+
+		resp = postQuery(t, query, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "Must provide operation name if query contains multiple operations.", resp.Errors[0].Message)
+	})
+
+	t.Run("response keys keep selection order", func(t *testing.T) {
+		body, _ := json.Marshal(Request{Query: `{ z: generateUUID a: textUppercase(text: "x") m: __typename }`})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		HandleQuery(rec, req)
+		out := rec.Body.String()
+		assert.Less(t, strings.Index(out, `"z"`), strings.Index(out, `"a"`))
+		assert.Less(t, strings.Index(out, `"a"`), strings.Index(out, `"m"`))
+	})
+}
+
+// TestSyntaxErrors checks that syntax errors carry the location of the
+// offending token.
+func TestSyntaxErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		msg   string
+		loc   Location
+	}{
+		{"{ health { status }", `Syntax Error: Expected Name, found <EOF>.`, Location{Line: 1, Column: 20}},
+		{"{\n  health(\n) { status } }", `Syntax Error: Expected Name, found ")".`, Location{Line: 3, Column: 1}},
+		{"query { health { status } } extra", `Syntax Error: Unexpected Name "extra".`, Location{Line: 1, Column: 29}},
+	}
+	for _, tc := range cases {
+		resp := postQuery(t, tc.query, nil)
+		require.Len(t, resp.Errors, 1, tc.query)
+		assert.Equal(t, tc.msg, resp.Errors[0].Message, tc.query)
+		assert.Equal(t, []Location{tc.loc}, resp.Errors[0].Locations, tc.query)
+	}
+}
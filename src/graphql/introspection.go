@@ -0,0 +1,349 @@
+package graphql
+
+import (
+	"sort"
+	"sync"
+)
+
+// directiveDef declares a directive the executor understands.
+type directiveDef struct {
+	Name        string
+	Description string
+	Locations   []string
+	Args        map[string]*Argument
+}
+
+// directiveDefs are the directives of the schema: the executable
+// @include/@skip and the type-system @deprecated.
+var directiveDefs = map[string]*directiveDef{
+	"include": {
+		Name:        "include",
+		Description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        map[string]*Argument{"if": arg("Boolean!", "Included when true.")},
+	},
+	"skip": {
+		Name:        "skip",
+		Description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        map[string]*Argument{"if": arg("Boolean!", "Skipped when true.")},
+	},
+	"deprecated": {
+		Name:        "deprecated",
+		Description: "Marks an element of a GraphQL schema as no longer supported.",
+		Locations:   []string{"FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION", "ENUM_VALUE"},
+		Args: map[string]*Argument{
+			"reason": {Type: "String", Description: "Explains why this element was deprecated.", DefaultValue: `"No longer supported"`},
+		},
+	},
+}
+
+// introspectionEnums are the enum types of the introspection system.
+var introspectionEnums = map[string][]string{
+	"__TypeKind": {"SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT", "LIST", "NON_NULL"},
+	"__DirectiveLocation": {
+		"QUERY", "MUTATION", "SUBSCRIPTION", "FIELD", "FRAGMENT_DEFINITION", "FRAGMENT_SPREAD",
+		"INLINE_FRAGMENT", "VARIABLE_DEFINITION", "SCHEMA", "SCALAR", "OBJECT", "FIELD_DEFINITION",
+		"ARGUMENT_DEFINITION", "INTERFACE", "UNION", "ENUM", "ENUM_VALUE", "INPUT_OBJECT",
+		"INPUT_FIELD_DEFINITION",
+	},
+}
+
+// introspectionTypes are the __Schema/__Type/... object types clients
+// query to discover the schema. Their values are built by introspectType
+// and friends rather than by resolvers, and they are not part of the SDL.
+var introspectionTypes = sync.OnceValue(func() map[string]*ObjectType {
+	includeDeprecated := func() map[string]*Argument {
+		return map[string]*Argument{
+			"includeDeprecated": {Type: "Boolean", DefaultValue: "false"},
+		}
+	}
+	object := func(name string, fields map[string]*Field) *ObjectType {
+		return &ObjectType{Name: name, Fields: fields}
+	}
+	return map[string]*ObjectType{
+		"__Schema": object("__Schema", map[string]*Field{
+			"description":      {Type: "String"},
+			"types":            {Type: "[__Type!]!", Description: "All types supported by this server."},
+			"queryType":        {Type: "__Type!", Description: "The type that query operations will be rooted at."},
+			"mutationType":     {Type: "__Type", Description: "The type that mutation operations will be rooted at."},
+			"subscriptionType": {Type: "__Type", Description: "The type that subscription operations will be rooted at."},
+			"directives":       {Type: "[__Directive!]!", Description: "All directives supported by this server."},
+		}),
+		"__Type": object("__Type", map[string]*Field{
+			"kind":           {Type: "__TypeKind!"},
+			"name":           {Type: "String"},
+			"description":    {Type: "String"},
+			"specifiedByURL": {Type: "String"},
+			"fields":         {Type: "[__Field!]", Args: includeDeprecated()},
+			"interfaces":     {Type: "[__Type!]"},
+			"possibleTypes":  {Type: "[__Type!]"},
+			"enumValues":     {Type: "[__EnumValue!]", Args: includeDeprecated()},
+			"inputFields":    {Type: "[__InputValue!]", Args: includeDeprecated()},
+			"ofType":         {Type: "__Type"},
+			"isOneOf":        {Type: "Boolean"},
+		}),
+		"__Field": object("__Field", map[string]*Field{
+			"name":              {Type: "String!"},
+			"description":       {Type: "String"},
+			"args":              {Type: "[__InputValue!]!", Args: includeDeprecated()},
+			"type":              {Type: "__Type!"},
+			"isDeprecated":      {Type: "Boolean!"},
+			"deprecationReason": {Type: "String"},
+		}),
+		"__InputValue": object("__InputValue", map[string]*Field{
+			"name":              {Type: "String!"},
+			"description":       {Type: "String"},
+			"type":              {Type: "__Type!"},
+			"defaultValue":      {Type: "String", Description: "A GraphQL-formatted string representing the default value for this input value."},
+			"isDeprecated":      {Type: "Boolean!"},
+			"deprecationReason": {Type: "String"},
+		}),
+		"__EnumValue": object("__EnumValue", map[string]*Field{
+			"name":              {Type: "String!"},
+			"description":       {Type: "String"},
+			"isDeprecated":      {Type: "Boolean!"},
+			"deprecationReason": {Type: "String"},
+		}),
+		"__Directive": object("__Directive", map[string]*Field{
+			"name":         {Type: "String!"},
+			"description":  {Type: "String"},
+			"isRepeatable": {Type: "Boolean!"},
+			"locations":    {Type: "[__DirectiveLocation!]!"},
+			"args":         {Type: "[__InputValue!]!", Args: includeDeprecated()},
+		}),
+	}
+})
+
+// metaFields are the fields every Query root has in addition to its own:
+// __schema and __type. __typename is handled separately because it is
+// valid on every object type.
+var metaFields = map[string]*Field{
+	"__schema": {Type: "__Schema!", Description: "Access the current type schema of this server."},
+	"__type": {
+		Type:        "__Type",
+		Description: "Request the type information of a single type.",
+		Args:        map[string]*Argument{"name": arg("String!", "Type name")},
+	},
+}
+
+// typenameField is the __typename meta field.
+var typenameField = &Field{Type: "String!", Description: "The name of the current Object type at runtime."}
+
+// objectType looks up an object type by name: a root type, a result type
+// or an introspection type. It returns nil for scalars, enums and unknown
+// names.
+func (s *Schema) objectType(name string) *ObjectType {
+	switch {
+	case s.Query != nil && name == s.Query.Name:
+		return s.Query
+	case s.Mutation != nil && name == s.Mutation.Name:
+		return s.Mutation
+	}
+	if obj, ok := s.Types[name]; ok {
+		return obj
+	}
+	return introspectionTypes()[name]
+}
+
+// isLeafType reports whether name is a scalar or enum type.
+func (s *Schema) isLeafType(name string) bool {
+	_, isEnum := introspectionEnums[name]
+	return builtinScalars[name] || name == scalarJSON || isEnum
+}
+
+// isInputType reports whether a type reference may be used for arguments
+// and variables. The schema has no input objects, so these are the
+// scalars.
+func (s *Schema) isInputType(ref string) bool {
+	name := namedType(ref)
+	return builtinScalars[name] || name == scalarJSON
+}
+
+// fieldDef returns the definition of a field selected on obj, including
+// the meta fields, or nil when obj has no such field.
+func (s *Schema) fieldDef(obj *ObjectType, name string) *Field {
+	if name == "__typename" {
+		return typenameField
+	}
+	if obj == s.Query {
+		if meta, ok := metaFields[name]; ok {
+			return meta
+		}
+	}
+	return obj.Fields[name]
+}
+
+// typeNames lists every named type of the schema, sorted.
+func (s *Schema) typeNames() []string {
+	seen := map[string]bool{}
+	for _, obj := range []*ObjectType{s.Query, s.Mutation} {
+		if obj != nil {
+			seen[obj.Name] = true
+		}
+	}
+	for name := range s.Types {
+		seen[name] = true
+	}
+	for name := range introspectionTypes() {
+		seen[name] = true
+	}
+	for name := range introspectionEnums {
+		seen[name] = true
+	}
+	for name := range builtinScalars {
+		seen[name] = true
+	}
+	if s.usesScalar(scalarJSON) {
+		seen[scalarJSON] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Introspection values are plain maps, completed against the
+// introspection types like any other result. Fields that would recurse
+// (a type's fields, a field's type) are func() interface{} thunks that
+// the executor only evaluates when the query selects them.
+
+func (s *Schema) introspectSchema() map[string]interface{} {
+	var mutationType interface{}
+	if s.Mutation != nil {
+		mutationType = s.introspectNamed(s.Mutation.Name)
+	}
+	return map[string]interface{}{
+		"description":      nil,
+		"queryType":        s.introspectNamed(s.Query.Name),
+		"mutationType":     mutationType,
+		"subscriptionType": nil,
+		"types": func() interface{} {
+			names := s.typeNames()
+			types := make([]interface{}, len(names))
+			for i, name := range names {
+				types[i] = s.introspectNamed(name)
+			}
+			return types
+		},
+		"directives": func() interface{} {
+			directives := make([]interface{}, 0, len(directiveDefs))
+			for _, name := range sortedKeys(directiveDefs) {
+				d := directiveDefs[name]
+				locations := make([]interface{}, len(d.Locations))
+				for i, loc := range d.Locations {
+					locations[i] = loc
+				}
+				directives = append(directives, map[string]interface{}{
+					"name":         d.Name,
+					"description":  optionalString(d.Description),
+					"isRepeatable": false,
+					"locations":    locations,
+					"args":         s.introspectArgs(d.Args),
+				})
+			}
+			return directives
+		},
+	}
+}
+
+// introspectNamed describes a named type, or returns nil when the schema
+// has no type of that name.
+func (s *Schema) introspectNamed(name string) interface{} {
+	t := map[string]interface{}{
+		"name":           name,
+		"description":    nil,
+		"specifiedByURL": nil,
+		"fields":         nil,
+		"interfaces":     nil,
+		"possibleTypes":  nil,
+		"enumValues":     nil,
+		"inputFields":    nil,
+		"ofType":         nil,
+		"isOneOf":        nil,
+	}
+	if obj := s.objectType(name); obj != nil {
+		t["kind"] = "OBJECT"
+		t["interfaces"] = []interface{}{}
+		t["fields"] = func() interface{} {
+			fields := make([]interface{}, 0, len(obj.Fields))
+			for _, fieldName := range sortedKeys(obj.Fields) {
+				field := obj.Fields[fieldName]
+				fields = append(fields, map[string]interface{}{
+					"name":              fieldName,
+					"description":       optionalString(field.Description),
+					"args":              s.introspectArgs(field.Args),
+					"type":              s.introspectRef(field.Type),
+					"isDeprecated":      false,
+					"deprecationReason": nil,
+				})
+			}
+			return fields
+		}
+		return t
+	}
+	if values, ok := introspectionEnums[name]; ok {
+		t["kind"] = "ENUM"
+		enumValues := make([]interface{}, len(values))
+		for i, v := range values {
+			enumValues[i] = map[string]interface{}{
+				"name": v, "description": nil, "isDeprecated": false, "deprecationReason": nil,
+			}
+		}
+		t["enumValues"] = enumValues
+		return t
+	}
+	if s.isLeafType(name) {
+		t["kind"] = "SCALAR"
+		t["description"] = optionalString(scalarDescriptions[name])
+		return t
+	}
+	return nil
+}
+
+// introspectRef describes a type reference, unwrapping non-null and list
+// wrappers into nested ofType values.
+func (s *Schema) introspectRef(ref string) func() interface{} {
+	return func() interface{} {
+		wrapper := func(kind, inner string) map[string]interface{} {
+			return map[string]interface{}{
+				"kind": kind, "name": nil, "description": nil, "specifiedByURL": nil,
+				"fields": nil, "interfaces": nil, "possibleTypes": nil, "enumValues": nil,
+				"inputFields": nil, "isOneOf": nil, "ofType": s.introspectRef(inner),
+			}
+		}
+		if isNonNull(ref) {
+			return wrapper("NON_NULL", nullable(ref))
+		}
+		if item, ok := listItem(ref); ok {
+			return wrapper("LIST", item)
+		}
+		return s.introspectNamed(ref)
+	}
+}
+
+func (s *Schema) introspectArgs(args map[string]*Argument) []interface{} {
+	out := make([]interface{}, 0, len(args))
+	for _, name := range sortedKeys(args) {
+		a := args[name]
+		out = append(out, map[string]interface{}{
+			"name":              name,
+			"description":       optionalString(a.Description),
+			"type":              s.introspectRef(a.Type),
+			"defaultValue":      optionalString(a.DefaultValue),
+			"isDeprecated":      false,
+			"deprecationReason": nil,
+		})
+	}
+	return out
+}
+
+// optionalString maps "" to null.
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
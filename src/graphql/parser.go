@@ -2,126 +2,480 @@ package graphql
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
-// selection is one field selection inside a GraphQL operation: its name, an
-// optional alias, its literal/variable arguments, and any nested
-// selections (used to filter which sub-fields of an object result are
-// returned to the client).
+// Location is a 1-based line/column position in the query document, as
+// reported in the "locations" of a GraphQL error.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// selectionKind distinguishes the three kinds of selection a selection
+// set may contain.
+type selectionKind int
+
+const (
+	fieldSelection selectionKind = iota
+	fragmentSpread
+	inlineFragment
+)
+
+// selection is one entry of a selection set. For a field it carries the
+// field name, its response key (Alias, which equals Name when no alias was
+// written), literal/variable arguments and nested selections. For a
+// fragment spread Name is the fragment name; for an inline fragment
+// TypeCondition is the optional "on Type" and Selections its body.
 type selection struct {
-	Alias      string
-	Name       string
-	Arguments  map[string]argValue
-	Selections []selection
+	Kind          selectionKind
+	Alias         string
+	Name          string
+	TypeCondition string
+	Arguments     map[string]argValue
+	Directives    []directive
+	Selections    []selection
+	Loc           Location
+}
+
+// directive is a "@name(args)" annotation on a selection or operation.
+type directive struct {
+	Name      string
+	Arguments map[string]argValue
+	Loc       Location
 }
 
-// argValue is a parsed GraphQL argument value. It is either a literal Go
-// value (string/float64/bool/nil) or a reference to a request variable
-// (`$name`), resolved against the request's `variables` map at execution
-// time.
+// argValue is a parsed GraphQL input value. It is either a literal Go
+// value (string/int/float64/bool/nil), an enum value, a list, an input
+// object, or a reference to a request variable (`$name`), resolved against
+// the request's `variables` map at execution time.
 type argValue struct {
 	isVariable bool
 	varName    string
 	literal    interface{}
+	isEnum     bool
+	list       []argValue
+	isList     bool
+	fields     map[string]argValue
+	isObject   bool
+	loc        Location
 }
 
 // resolve returns the concrete Go value for an argument, substituting the
 // matching request variable when the argument was written as `$name`.
+// Lists resolve to []interface{} and input objects to
+// map[string]interface{}, the same shapes a JSON variable has.
 func (a argValue) resolve(variables map[string]interface{}) interface{} {
-	if a.isVariable {
+	switch {
+	case a.isVariable:
 		return variables[a.varName]
+	case a.isList:
+		items := make([]interface{}, len(a.list))
+		for i, item := range a.list {
+			items[i] = item.resolve(variables)
+		}
+		return items
+	case a.isObject:
+		fields := make(map[string]interface{}, len(a.fields))
+		for name, field := range a.fields {
+			fields[name] = field.resolve(variables)
+		}
+		return fields
 	}
 	return a.literal
 }
 
-// operation is a parsed GraphQL document: its type (query/mutation) and its
-// top-level field selections.
+// isNullLiteral reports whether the value is the literal null.
+func (a argValue) isNullLiteral() bool {
+	return !a.isVariable && !a.isList && !a.isObject && !a.isEnum && a.literal == nil
+}
+
+// String prints the value back in GraphQL syntax, for error messages.
+func (a argValue) String() string {
+	switch {
+	case a.isVariable:
+		return "$" + a.varName
+	case a.isEnum:
+		return a.literal.(string)
+	case a.isList:
+		items := make([]string, len(a.list))
+		for i, item := range a.list {
+			items[i] = item.String()
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case a.isObject:
+		fields := make([]string, 0, len(a.fields))
+		for _, name := range sortedKeys(a.fields) {
+			fields = append(fields, name+": "+a.fields[name].String())
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case a.literal == nil:
+		return "null"
+	}
+	if s, ok := a.literal.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(a.literal)
+}
+
+// variables lists the names of the variables the value refers to.
+func (a argValue) variables() []string {
+	switch {
+	case a.isVariable:
+		return []string{a.varName}
+	case a.isList:
+		var names []string
+		for _, item := range a.list {
+			names = append(names, item.variables()...)
+		}
+		return names
+	case a.isObject:
+		var names []string
+		for _, name := range sortedKeys(a.fields) {
+			names = append(names, a.fields[name].variables()...)
+		}
+		return names
+	}
+	return nil
+}
+
+// variableDef is an operation variable declaration, "$name: Type = default".
+type variableDef struct {
+	Name    string
+	Type    string
+	Default *argValue
+	Loc     Location
+}
+
+// operation is one query/mutation/subscription definition of a document.
 type operation struct {
 	Type       string
+	Name       string
+	Variables  []variableDef
+	Directives []directive
 	Selections []selection
+	Loc        Location
+}
+
+// fragment is a named fragment definition, "fragment Name on Type { ... }".
+type fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []directive
+	Selections    []selection
+	Loc           Location
+}
+
+// document is a parsed GraphQL request body: its operations in document
+// order and its fragments by name.
+type document struct {
+	Operations []*operation
+	Fragments  map[string]*fragment
+
+	// fragmentOrder keeps definition order for deterministic validation.
+	fragmentOrder []string
+	// duplicates are repeated operation/fragment names, reported by
+	// validation with the location of the repeat.
+	duplicates []Error
 }
 
-// docParser is a minimal hand-rolled parser for the subset of the GraphQL
-// language this project's schema needs: a single anonymous or named query/
-// mutation operation, aliased field selections, nested selection sets, and
-// scalar/variable arguments. It intentionally does not support fragments,
-// directives, or multiple operations per document — the schema in
-// graphql.go doesn't use them, and pulling in a full GraphQL library only
-// to skip most of it isn't worth the dependency.
+// docParser is a hand-rolled parser for the executable subset of the
+// GraphQL language: operations (with variable definitions and
+// directives), fragment definitions, fields with aliases, arguments and
+// directives, fragment spreads and inline fragments, and every input value
+// form (variables, ints, floats, strings and block strings, booleans,
+// null, enums, lists and input objects). Type-system definitions (SDL)
+// are not accepted in a query document.
 type docParser struct {
-	input []rune
-	pos   int
+	input      []rune
+	pos        int
+	lineStarts []int
 }
 
 func newDocParser(input string) *docParser {
-	return &docParser{input: []rune(input)}
+	p := &docParser{input: []rune(input), lineStarts: []int{0}}
+	for i, c := range p.input {
+		if c == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+	return p
+}
+
+// loc converts a rune offset into a line/column location.
+func (p *docParser) loc(pos int) Location {
+	line := sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > pos })
+	return Location{Line: line, Column: pos - p.lineStarts[line-1] + 1}
+}
+
+// errorf reports a syntax error at pos.
+func (p *docParser) errorf(pos int, format string, args ...interface{}) error {
+	return &Error{
+		Message:   "Syntax Error: " + fmt.Sprintf(format, args...),
+		Locations: []Location{p.loc(pos)},
+	}
 }
 
-// parseDocument parses a full GraphQL request body into a single operation.
-func (p *docParser) parseDocument() (operation, error) {
+// found describes the token at the current position for syntax errors.
+func (p *docParser) found() string {
+	if p.pos >= len(p.input) {
+		return "<EOF>"
+	}
+	return strconv.QuoteRune(p.input[p.pos])
+}
+
+// parseDocument parses a full GraphQL request body.
+func (p *docParser) parseDocument() (*document, error) {
+	doc := &document{Fragments: map[string]*fragment{}}
+	operationNames := map[string]bool{}
+
 	p.skipIgnored()
 	if p.pos >= len(p.input) {
-		return operation{}, fmt.Errorf("syntax error: empty query")
+		return nil, p.errorf(p.pos, "Unexpected <EOF>.")
+	}
+	for p.pos < len(p.input) {
+		start := p.pos
+		if p.consumeKeyword("fragment") {
+			frag, err := p.parseFragment(start)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[frag.Name]; exists {
+				doc.duplicates = append(doc.duplicates, Error{
+					Message:   fmt.Sprintf("There can be only one fragment named %q.", frag.Name),
+					Locations: []Location{frag.Loc},
+				})
+			} else {
+				doc.Fragments[frag.Name] = frag
+				doc.fragmentOrder = append(doc.fragmentOrder, frag.Name)
+			}
+		} else {
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			if op.Name != "" {
+				if operationNames[op.Name] {
+					doc.duplicates = append(doc.duplicates, Error{
+						Message:   fmt.Sprintf("There can be only one operation named %q.", op.Name),
+						Locations: []Location{op.Loc},
+					})
+				}
+				operationNames[op.Name] = true
+			}
+			doc.Operations = append(doc.Operations, op)
+		}
+		p.skipIgnored()
+	}
+	if len(doc.Operations) == 0 {
+		return nil, p.errorf(0, "Document contains no operations.")
 	}
+	return doc, nil
+}
 
-	opType := "query"
-	if p.consumeKeyword("mutation") {
-		opType = "mutation"
-	} else {
-		p.consumeKeyword("query")
+// parseOperation parses a query/mutation/subscription definition or the
+// anonymous "{ ... }" query shorthand.
+func (p *docParser) parseOperation() (*operation, error) {
+	op := &operation{Type: "query", Loc: p.loc(p.pos)}
+	if p.pos < len(p.input) && p.input[p.pos] == '{' {
+		sels, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		op.Selections = sels
+		return op, nil
+	}
+
+	start := p.pos
+	switch opType := p.parseName(); opType {
+	case "query", "mutation", "subscription":
+		op.Type = opType
+	default:
+		return nil, p.errorf(start, "Unexpected %s.", describeToken(opType, p.input, start))
 	}
 	p.skipIgnored()
 
-	// Optional operation name (anonymous shorthand omits it).
-	if p.pos < len(p.input) && p.input[p.pos] != '{' && p.input[p.pos] != '(' {
-		p.parseName()
+	// Optional operation name (anonymous operations omit it).
+	if name := p.parseName(); name != "" {
+		op.Name = name
 		p.skipIgnored()
 	}
 
 	// Optional variable definitions, e.g. "mutation($t: String!) { ... }".
-	if err := p.parseVariableDefinitions(); err != nil {
-		return operation{}, err
+	vars, err := p.parseVariableDefinitions()
+	if err != nil {
+		return nil, err
 	}
+	op.Variables = vars
 	p.skipIgnored()
 
-	sels, err := p.parseSelectionSet()
-	if err != nil {
-		return operation{}, err
+	if op.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if op.Selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// describeToken names an unexpected token for a syntax error.
+func describeToken(name string, input []rune, pos int) string {
+	if name != "" {
+		return "Name " + strconv.Quote(name)
+	}
+	if pos >= len(input) {
+		return "<EOF>"
+	}
+	return strconv.QuoteRune(input[pos])
+}
+
+// parseFragment parses the remainder of "fragment Name on Type { ... }"
+// after the "fragment" keyword that started at start.
+func (p *docParser) parseFragment(start int) (*fragment, error) {
+	frag := &fragment{Loc: p.loc(start)}
+	p.skipIgnored()
+	nameStart := p.pos
+	frag.Name = p.parseName()
+	if frag.Name == "" {
+		return nil, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+	}
+	if frag.Name == "on" {
+		return nil, p.errorf(nameStart, "Unexpected Name \"on\".")
+	}
+	p.skipIgnored()
+	if !p.consumeKeyword("on") {
+		return nil, p.errorf(p.pos, "Expected \"on\", found %s.", p.found())
+	}
+	p.skipIgnored()
+	if frag.TypeCondition = p.parseName(); frag.TypeCondition == "" {
+		return nil, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+	}
+	p.skipIgnored()
+
+	var err error
+	if frag.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if frag.Selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
 	}
-	return operation{Type: opType, Selections: sels}, nil
+	return frag, nil
 }
 
 // parseVariableDefinitions consumes an optional operation variable
-// declaration list, e.g. "($id: ID!, $limit: Int = 10)". Declared types and
-// defaults are not tracked — arguments resolve variables directly from the
-// request's "variables" map — so this only needs to skip the list,
-// respecting nested brackets and string literals along the way.
-func (p *docParser) parseVariableDefinitions() error {
+// declaration list, e.g. "($id: ID!, $limit: Int = 10)".
+func (p *docParser) parseVariableDefinitions() ([]variableDef, error) {
 	if p.pos >= len(p.input) || p.input[p.pos] != '(' {
-		return nil
+		return nil, nil
 	}
-	depth := 0
-	for p.pos < len(p.input) {
-		c := p.input[p.pos]
-		if c == '"' {
-			if _, err := p.parseString(); err != nil {
-				return err
+	p.pos++
+	var defs []variableDef
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) {
+			return nil, p.errorf(p.pos, "Expected \"$\", found <EOF>.")
+		}
+		if p.input[p.pos] == ')' {
+			p.pos++
+			if len(defs) == 0 {
+				return nil, p.errorf(p.pos-1, "Expected \"$\", found \")\".")
 			}
-			continue
+			return defs, nil
+		}
+
+		def := variableDef{Loc: p.loc(p.pos)}
+		if err := p.expect('$'); err != nil {
+			return nil, err
+		}
+		if def.Name = p.parseName(); def.Name == "" {
+			return nil, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+		}
+		p.skipIgnored()
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		p.skipIgnored()
+		typ, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		def.Type = typ
+		p.skipIgnored()
+		if p.pos < len(p.input) && p.input[p.pos] == '=' {
+			p.pos++
+			p.skipIgnored()
+			valueStart := p.pos
+			val, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if names := val.variables(); len(names) > 0 {
+				return nil, p.errorf(valueStart, "Unexpected variable \"$%s\" in constant value.", names[0])
+			}
+			def.Default = &val
+		}
+		p.skipIgnored()
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+}
+
+// parseTypeRef parses a type reference such as "Int", "[String!]" or
+// "[Float!]!" and returns it in the same notation.
+func (p *docParser) parseTypeRef() (string, error) {
+	var typ string
+	if p.pos < len(p.input) && p.input[p.pos] == '[' {
+		p.pos++
+		p.skipIgnored()
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return "", err
+		}
+		p.skipIgnored()
+		if err := p.expect(']'); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		if typ = p.parseName(); typ == "" {
+			return "", p.errorf(p.pos, "Expected Name, found %s.", p.found())
 		}
-		if c == '(' {
-			depth++
-		} else if c == ')' {
-			depth--
+	}
+	p.skipIgnored()
+	if p.pos < len(p.input) && p.input[p.pos] == '!' {
+		p.pos++
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *docParser) parseDirectives() ([]directive, error) {
+	var dirs []directive
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) || p.input[p.pos] != '@' {
+			return dirs, nil
 		}
+		dir := directive{Loc: p.loc(p.pos)}
 		p.pos++
-		if depth == 0 {
-			return nil
+		if dir.Name = p.parseName(); dir.Name == "" {
+			return nil, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+		}
+		p.skipIgnored()
+		if p.pos < len(p.input) && p.input[p.pos] == '(' {
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+			dir.Arguments = args
 		}
+		dirs = append(dirs, dir)
 	}
-	return fmt.Errorf("syntax error: unterminated variable definition list")
 }
 
 func (p *docParser) parseSelectionSet() ([]selection, error) {
@@ -132,9 +486,12 @@ func (p *docParser) parseSelectionSet() ([]selection, error) {
 	for {
 		p.skipIgnored()
 		if p.pos >= len(p.input) {
-			return nil, fmt.Errorf("syntax error: unterminated selection set")
+			return nil, p.errorf(p.pos, "Expected Name, found <EOF>.")
 		}
 		if p.input[p.pos] == '}' {
+			if len(sels) == 0 {
+				return nil, p.errorf(p.pos, "Expected Name, found \"}\".")
+			}
 			p.pos++
 			return sels, nil
 		}
@@ -147,43 +504,76 @@ func (p *docParser) parseSelectionSet() ([]selection, error) {
 }
 
 func (p *docParser) parseSelection() (selection, error) {
+	start := p.pos
+	if strings.HasPrefix(string(p.input[p.pos:min(p.pos+3, len(p.input))]), "...") {
+		return p.parseFragmentSelection(start)
+	}
+
 	first := p.parseName()
 	if first == "" {
-		return selection{}, fmt.Errorf("syntax error: expected field name at position %d", p.pos)
+		return selection{}, p.errorf(p.pos, "Expected Name, found %s.", p.found())
 	}
 	p.skipIgnored()
 
-	name := first
-	alias := first
+	sel := selection{Kind: fieldSelection, Alias: first, Name: first, Loc: p.loc(start)}
 	if p.pos < len(p.input) && p.input[p.pos] == ':' {
 		p.pos++
 		p.skipIgnored()
-		name = p.parseName()
-		if name == "" {
-			return selection{}, fmt.Errorf("syntax error: expected field name after alias %q", alias)
+		if sel.Name = p.parseName(); sel.Name == "" {
+			return selection{}, p.errorf(p.pos, "Expected Name, found %s.", p.found())
 		}
 		p.skipIgnored()
 	}
 
-	sel := selection{Alias: alias, Name: name}
-
 	if p.pos < len(p.input) && p.input[p.pos] == '(' {
 		args, err := p.parseArguments()
 		if err != nil {
 			return selection{}, err
 		}
 		sel.Arguments = args
-		p.skipIgnored()
+	}
+
+	var err error
+	if sel.Directives, err = p.parseDirectives(); err != nil {
+		return selection{}, err
 	}
 
 	if p.pos < len(p.input) && p.input[p.pos] == '{' {
-		sub, err := p.parseSelectionSet()
-		if err != nil {
+		if sel.Selections, err = p.parseSelectionSet(); err != nil {
 			return selection{}, err
 		}
-		sel.Selections = sub
+	}
+	return sel, nil
+}
+
+// parseFragmentSelection parses "...Name @dirs" or "... on Type @dirs { }"
+// (the type condition of an inline fragment is optional).
+func (p *docParser) parseFragmentSelection(start int) (selection, error) {
+	p.pos += 3
+	p.skipIgnored()
+	sel := selection{Kind: inlineFragment, Loc: p.loc(start)}
+
+	switch name := p.parseName(); name {
+	case "on":
+		p.skipIgnored()
+		if sel.TypeCondition = p.parseName(); sel.TypeCondition == "" {
+			return selection{}, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+		}
+	case "":
+	default:
+		sel.Kind = fragmentSpread
+		sel.Name = name
 	}
 
+	var err error
+	if sel.Directives, err = p.parseDirectives(); err != nil {
+		return selection{}, err
+	}
+	if sel.Kind == inlineFragment {
+		if sel.Selections, err = p.parseSelectionSet(); err != nil {
+			return selection{}, err
+		}
+	}
 	return sel, nil
 }
 
@@ -195,15 +585,22 @@ func (p *docParser) parseArguments() (map[string]argValue, error) {
 	for {
 		p.skipIgnored()
 		if p.pos >= len(p.input) {
-			return nil, fmt.Errorf("syntax error: unterminated argument list")
+			return nil, p.errorf(p.pos, "Expected Name, found <EOF>.")
 		}
 		if p.input[p.pos] == ')' {
+			if len(args) == 0 {
+				return nil, p.errorf(p.pos, "Expected Name, found \")\".")
+			}
 			p.pos++
 			return args, nil
 		}
+		start := p.pos
 		name := p.parseName()
 		if name == "" {
-			return nil, fmt.Errorf("syntax error: expected argument name at position %d", p.pos)
+			return nil, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+		}
+		if _, dup := args[name]; dup {
+			return nil, p.errorf(start, "There can be only one argument named %q.", name)
 		}
 		p.skipIgnored()
 		if err := p.expect(':'); err != nil {
@@ -214,54 +611,145 @@ func (p *docParser) parseArguments() (map[string]argValue, error) {
 		if err != nil {
 			return nil, err
 		}
+		val.loc = p.loc(start)
 		args[name] = val
-		p.skipIgnored()
 	}
 }
 
 func (p *docParser) parseValue() (argValue, error) {
 	if p.pos >= len(p.input) {
-		return argValue{}, fmt.Errorf("syntax error: expected value at position %d", p.pos)
+		return argValue{}, p.errorf(p.pos, "Unexpected <EOF>.")
 	}
 
+	start := p.pos
+	var val argValue
+	var err error
 	switch c := p.input[p.pos]; {
 	case c == '$':
 		p.pos++
 		name := p.parseName()
 		if name == "" {
-			return argValue{}, fmt.Errorf("syntax error: expected variable name at position %d", p.pos)
+			return argValue{}, p.errorf(p.pos, "Expected Name, found %s.", p.found())
 		}
-		return argValue{isVariable: true, varName: name}, nil
+		val = argValue{isVariable: true, varName: name}
+	case c == '[':
+		val, err = p.parseList()
+	case c == '{':
+		val, err = p.parseObject()
 	case c == '"':
-		s, err := p.parseString()
-		if err != nil {
-			return argValue{}, err
+		var s string
+		if strings.HasPrefix(string(p.input[p.pos:min(p.pos+3, len(p.input))]), `"""`) {
+			s, err = p.parseBlockString()
+		} else {
+			s, err = p.parseString()
 		}
-		return argValue{literal: s}, nil
+		val = argValue{literal: s}
 	case c == '-' || (c >= '0' && c <= '9'):
-		return p.parseNumber()
-	case strings.HasPrefix(string(p.input[p.pos:]), "true"):
-		p.pos += 4
-		return argValue{literal: true}, nil
-	case strings.HasPrefix(string(p.input[p.pos:]), "false"):
-		p.pos += 5
-		return argValue{literal: false}, nil
-	case strings.HasPrefix(string(p.input[p.pos:]), "null"):
-		p.pos += 4
-		return argValue{literal: nil}, nil
+		val, err = p.parseNumber()
+	case isNameStart(c):
+		switch name := p.parseName(); name {
+		case "true":
+			val = argValue{literal: true}
+		case "false":
+			val = argValue{literal: false}
+		case "null":
+			val = argValue{literal: nil}
+		default:
+			val = argValue{literal: name, isEnum: true}
+		}
 	default:
-		return argValue{}, fmt.Errorf("syntax error: unexpected character %q at position %d", c, p.pos)
+		return argValue{}, p.errorf(p.pos, "Unexpected %s.", p.found())
+	}
+	if err != nil {
+		return argValue{}, err
+	}
+	val.loc = p.loc(start)
+	return val, nil
+}
+
+// parseLiteral parses a standalone constant value, such as the default
+// value of a schema argument.
+func parseLiteral(s string) (argValue, error) {
+	p := newDocParser(s)
+	p.skipIgnored()
+	val, err := p.parseValue()
+	if err != nil {
+		return argValue{}, err
+	}
+	if p.skipIgnored(); p.pos < len(p.input) {
+		return argValue{}, p.errorf(p.pos, "Unexpected %s.", p.found())
+	}
+	return val, nil
+}
+
+// parseList parses a list value such as ["a", "b"] or [1, $n].
+func (p *docParser) parseList() (argValue, error) {
+	if err := p.expect('['); err != nil {
+		return argValue{}, err
+	}
+	list := argValue{isList: true}
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) {
+			return argValue{}, p.errorf(p.pos, "Expected \"]\", found <EOF>.")
+		}
+		if p.input[p.pos] == ']' {
+			p.pos++
+			return list, nil
+		}
+		item, err := p.parseValue()
+		if err != nil {
+			return argValue{}, err
+		}
+		list.list = append(list.list, item)
+	}
+}
+
+// parseObject parses an input object value such as {lat: 1, lon: $lon}.
+func (p *docParser) parseObject() (argValue, error) {
+	if err := p.expect('{'); err != nil {
+		return argValue{}, err
+	}
+	obj := argValue{isObject: true, fields: map[string]argValue{}}
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) {
+			return argValue{}, p.errorf(p.pos, "Expected Name, found <EOF>.")
+		}
+		if p.input[p.pos] == '}' {
+			p.pos++
+			return obj, nil
+		}
+		start := p.pos
+		name := p.parseName()
+		if name == "" {
+			return argValue{}, p.errorf(p.pos, "Expected Name, found %s.", p.found())
+		}
+		if _, dup := obj.fields[name]; dup {
+			return argValue{}, p.errorf(start, "There can be only one input field named %q.", name)
+		}
+		p.skipIgnored()
+		if err := p.expect(':'); err != nil {
+			return argValue{}, err
+		}
+		p.skipIgnored()
+		val, err := p.parseValue()
+		if err != nil {
+			return argValue{}, err
+		}
+		obj.fields[name] = val
 	}
 }
 
 func (p *docParser) parseString() (string, error) {
+	start := p.pos
 	if err := p.expect('"'); err != nil {
 		return "", err
 	}
 	var b strings.Builder
 	for {
-		if p.pos >= len(p.input) {
-			return "", fmt.Errorf("syntax error: unterminated string literal")
+		if p.pos >= len(p.input) || p.input[p.pos] == '\n' || p.input[p.pos] == '\r' {
+			return "", p.errorf(start, "Unterminated string.")
 		}
 		c := p.input[p.pos]
 		if c == '"' {
@@ -270,17 +758,31 @@ func (p *docParser) parseString() (string, error) {
 		}
 		if c == '\\' && p.pos+1 < len(p.input) {
 			p.pos++
-			switch p.input[p.pos] {
-			case '"':
-				b.WriteRune('"')
-			case '\\':
-				b.WriteRune('\\')
+			switch esc := p.input[p.pos]; esc {
+			case '"', '\\', '/':
+				b.WriteRune(esc)
+			case 'b':
+				b.WriteRune('\b')
+			case 'f':
+				b.WriteRune('\f')
 			case 'n':
 				b.WriteRune('\n')
+			case 'r':
+				b.WriteRune('\r')
 			case 't':
 				b.WriteRune('\t')
+			case 'u':
+				if p.pos+4 >= len(p.input) {
+					return "", p.errorf(p.pos-1, "Invalid Unicode escape sequence.")
+				}
+				code, err := strconv.ParseUint(string(p.input[p.pos+1:p.pos+5]), 16, 32)
+				if err != nil || !utf8.ValidRune(rune(code)) {
+					return "", p.errorf(p.pos-1, "Invalid Unicode escape sequence.")
+				}
+				b.WriteRune(rune(code))
+				p.pos += 4
 			default:
-				b.WriteRune(p.input[p.pos])
+				return "", p.errorf(p.pos-1, "Invalid character escape sequence: \"\\%c\".", esc)
 			}
 			p.pos++
 			continue
@@ -290,36 +792,107 @@ func (p *docParser) parseString() (string, error) {
 	}
 }
 
+// parseBlockString parses a """block string""", removing the common
+// indentation and leading/trailing blank lines as the spec requires.
+func (p *docParser) parseBlockString() (string, error) {
+	start := p.pos
+	p.pos += 3
+	var raw strings.Builder
+	for {
+		if p.pos >= len(p.input) {
+			return "", p.errorf(start, "Unterminated string.")
+		}
+		rest := string(p.input[p.pos:min(p.pos+4, len(p.input))])
+		if strings.HasPrefix(rest, `\"""`) {
+			raw.WriteString(`"""`)
+			p.pos += 4
+			continue
+		}
+		if strings.HasPrefix(rest, `"""`) {
+			p.pos += 3
+			return blockStringValue(raw.String()), nil
+		}
+		raw.WriteRune(p.input[p.pos])
+		p.pos++
+	}
+}
+
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(raw, "\r\n", "\n"), "\r", "\n"), "\n")
+	common := -1
+	for _, line := range lines[1:] {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < len(line) && (common < 0 || indent < common) {
+			common = indent
+		}
+	}
+	if common > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= common {
+				lines[i] = lines[i][common:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (p *docParser) parseNumber() (argValue, error) {
 	start := p.pos
 	if p.input[p.pos] == '-' {
 		p.pos++
 	}
-	isFloat := false
-	for p.pos < len(p.input) {
-		c := p.input[p.pos]
-		if c >= '0' && c <= '9' {
+	digits := func() int {
+		n := 0
+		for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
 			p.pos++
-			continue
+			n++
+		}
+		return n
+	}
+	isFloat := false
+	if digits() == 0 {
+		return argValue{}, p.errorf(p.pos, "Invalid number, expected digit but got: %s.", p.found())
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '.' {
+		isFloat = true
+		p.pos++
+		if digits() == 0 {
+			return argValue{}, p.errorf(p.pos, "Invalid number, expected digit but got: %s.", p.found())
 		}
-		if c == '.' && !isFloat {
-			isFloat = true
+	}
+	if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		isFloat = true
+		p.pos++
+		if p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
 			p.pos++
-			continue
 		}
-		break
+		if digits() == 0 {
+			return argValue{}, p.errorf(p.pos, "Invalid number, expected digit but got: %s.", p.found())
+		}
 	}
+	if p.pos < len(p.input) && (isNameStart(p.input[p.pos]) || p.input[p.pos] == '.') {
+		return argValue{}, p.errorf(p.pos, "Invalid number, expected digit but got: %s.", p.found())
+	}
+
 	raw := string(p.input[start:p.pos])
 	if isFloat {
 		f, err := strconv.ParseFloat(raw, 64)
 		if err != nil {
-			return argValue{}, fmt.Errorf("syntax error: invalid number %q", raw)
+			return argValue{}, p.errorf(start, "Invalid number %q.", raw)
 		}
 		return argValue{literal: f}, nil
 	}
 	n, err := strconv.Atoi(raw)
 	if err != nil {
-		return argValue{}, fmt.Errorf("syntax error: invalid number %q", raw)
+		return argValue{}, p.errorf(start, "Invalid number %q.", raw)
 	}
 	return argValue{literal: n}, nil
 }
@@ -363,19 +936,20 @@ func (p *docParser) consumeKeyword(kw string) bool {
 
 func (p *docParser) expect(c rune) error {
 	if p.pos >= len(p.input) || p.input[p.pos] != c {
-		return fmt.Errorf("syntax error: expected %q at position %d", c, p.pos)
+		return p.errorf(p.pos, "Expected %s, found %s.", strconv.QuoteRune(c), p.found())
 	}
 	p.pos++
 	return nil
 }
 
-// skipIgnored advances past whitespace, commas, and `#`-prefixed comments —
-// all of which the GraphQL spec treats as insignificant between tokens.
+// skipIgnored advances past whitespace, commas, the byte order mark and
+// `#`-prefixed comments — all of which the GraphQL spec treats as
+// insignificant between tokens.
 func (p *docParser) skipIgnored() {
 	for p.pos < len(p.input) {
 		c := p.input[p.pos]
 		switch {
-		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' || c == '\uFEFF':
 			p.pos++
 		case c == '#':
 			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
@@ -0,0 +1,341 @@
+package graphql
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// scalarJSON is the custom scalar for free-form values (maps, interface{}
+// and json.Marshaler types) that have no fixed shape.
+const scalarJSON = "JSON"
+
+// builtinScalars are the scalar types every GraphQL schema has.
+var builtinScalars = map[string]bool{
+	"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true,
+}
+
+// scalarDescriptions describe each scalar in the SDL and introspection.
+var scalarDescriptions = map[string]string{
+	"String":   "UTF-8 character sequence",
+	"Int":      "Signed 32-bit integer",
+	"Float":    "Double-precision floating-point value",
+	"Boolean":  "true or false",
+	"ID":       "Unique identifier, serialized as a String",
+	scalarJSON: "Arbitrary JSON value",
+}
+
+// typeBuilder derives GraphQL object types from the Go values resolvers
+// return. A field's declared type and its resolved value therefore come
+// from the same struct, and the SDL is rendered from the resulting types.
+type typeBuilder struct {
+	types map[string]*ObjectType
+	names map[reflect.Type]string
+}
+
+func newTypeBuilder() *typeBuilder {
+	return &typeBuilder{
+		types: make(map[string]*ObjectType),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// object registers v's struct type under an explicit GraphQL name and
+// returns its non-null type reference, e.g. "Health!".
+func (b *typeBuilder) object(name string, v interface{}) string {
+	t := derefType(reflect.TypeOf(v))
+	if existing, ok := b.names[t]; ok && existing != name {
+		panic(fmt.Sprintf("graphql: %s is already registered as %s", t, existing))
+	}
+	b.names[t] = name
+	b.buildObject(t, name)
+	return name + "!"
+}
+
+// ref returns the non-null GraphQL type reference for v's type, such as
+// "GeoCoordinate!" or "[String!]!". v is usually a typed nil pointer.
+func (b *typeBuilder) ref(v interface{}) string {
+	return b.typeRef(derefType(reflect.TypeOf(v)), true, "")
+}
+
+// typeRef maps a Go type to a GraphQL type reference. owner names the
+// enclosing field, for anonymous struct types.
+func (b *typeBuilder) typeRef(t reflect.Type, nonNull bool, owner string) string {
+	bang := ""
+	if nonNull {
+		bang = "!"
+	}
+	if t.Kind() == reflect.Ptr {
+		return b.typeRef(t.Elem(), false, owner) + bang
+	}
+
+	switch {
+	case t == timeType:
+		return "String" + bang
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return scalarJSON + bang
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return "String" + bang
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "Boolean" + bang
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int" + bang
+	case reflect.Float32, reflect.Float64:
+		return "Float" + bang
+	case reflect.String:
+		return "String" + bang
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes []byte as base64
+			return "String" + bang
+		}
+		return "[" + b.typeRef(t.Elem(), t.Elem().Kind() != reflect.Ptr, owner) + "]" + bang
+	case reflect.Struct:
+		return b.structName(t, owner) + bang
+	default:
+		// maps, interface{} and anything else without a fixed shape
+		return scalarJSON + bang
+	}
+}
+
+// structName returns the GraphQL name of a struct type, building its
+// object type on first use.
+func (b *typeBuilder) structName(t reflect.Type, owner string) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	name := typeName(t, owner)
+	b.names[t] = name
+	b.buildObject(t, name)
+	return name
+}
+
+// buildObject adds the object type for struct t, with one field per
+// property encoding/json would write.
+func (b *typeBuilder) buildObject(t reflect.Type, name string) {
+	if existing, ok := b.types[name]; ok {
+		if existing.goType != t {
+			panic(fmt.Sprintf("graphql: type name %s used by both %s and %s", name, existing.goType, t))
+		}
+		return
+	}
+	obj := &ObjectType{Name: name, Fields: make(map[string]*Field), goType: t}
+	b.types[name] = obj
+	b.addStructFields(obj, t)
+}
+
+func (b *typeBuilder) addStructFields(obj *ObjectType, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+
+		if sf.Anonymous && tag == "" {
+			if ft := derefType(sf.Type); ft.Kind() == reflect.Struct {
+				b.addStructFields(obj, ft)
+				continue
+			}
+		}
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		if !validName(name) {
+			panic(fmt.Sprintf("graphql: %s.%s has no valid GraphQL field name (%q)", t, sf.Name, name))
+		}
+
+		kind := sf.Type.Kind()
+		nonNull := !strings.Contains(opts, "omitempty") &&
+			kind != reflect.Ptr && kind != reflect.Slice && kind != reflect.Map && kind != reflect.Interface
+		obj.Fields[name] = &Field{Type: b.typeRef(sf.Type, nonNull, obj.Name+upperFirst(name))}
+	}
+}
+
+// typeName derives a GraphQL type name from a Go struct type. Types from
+// the service packages are prefixed with their package unless the name
+// already says it (geo.Coordinate -> GeoCoordinate, weather.CurrentWeather
+// stays CurrentWeather). Anonymous structs are named after their field.
+func typeName(t reflect.Type, owner string) string {
+	if t.Name() == "" {
+		return owner
+	}
+	name := upperFirst(t.Name())
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if pkg == "graphql" || strings.Contains(strings.ToLower(name), pkg) {
+		return name
+	}
+	return upperFirst(pkg) + name
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func validName(s string) bool {
+	if s == "" || !isNameStart(rune(s[0])) {
+		return false
+	}
+	for _, c := range s {
+		if !isNameChar(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// namedType strips list and non-null wrappers from a type reference:
+// "[GeoCoordinate!]!" -> "GeoCoordinate".
+func namedType(ref string) string {
+	return strings.Trim(ref, "[]!")
+}
+
+// isNonNull reports whether a type reference is a non-null type, "T!".
+func isNonNull(ref string) bool {
+	return strings.HasSuffix(ref, "!")
+}
+
+// nullable strips one non-null wrapper: "[Int!]!" -> "[Int!]".
+func nullable(ref string) string {
+	return strings.TrimSuffix(ref, "!")
+}
+
+// listItem returns the item type of a (nullable) list type reference:
+// "[Int!]" -> "Int!".
+func listItem(ref string) (string, bool) {
+	if strings.HasPrefix(ref, "[") && strings.HasSuffix(ref, "]") {
+		return ref[1 : len(ref)-1], true
+	}
+	return "", false
+}
+
+// SDL renders the schema in the GraphQL Schema Definition Language.
+func (s *Schema) SDL() string {
+	var b strings.Builder
+
+	if s.usesScalar(scalarJSON) {
+		fmt.Fprintf(&b, "%s\nscalar %s\n\n", sdlString(scalarDescriptions[scalarJSON]), scalarJSON)
+	}
+
+	writeObjectSDL(&b, s.Query)
+	if s.Mutation != nil {
+		writeObjectSDL(&b, s.Mutation)
+	}
+
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeObjectSDL(&b, s.Types[name])
+	}
+	return b.String()
+}
+
+func writeObjectSDL(b *strings.Builder, obj *ObjectType) {
+	fmt.Fprintf(b, "type %s {\n", obj.Name)
+	for _, name := range sortedKeys(obj.Fields) {
+		field := obj.Fields[name]
+		if field.Description != "" {
+			fmt.Fprintf(b, "\t%s\n", sdlString(field.Description))
+		}
+		b.WriteString("\t" + name)
+		if len(field.Args) > 0 {
+			var args []string
+			for _, argName := range sortedKeys(field.Args) {
+				arg := field.Args[argName]
+				if arg.DefaultValue != "" {
+					args = append(args, argName+": "+arg.Type+" = "+arg.DefaultValue)
+				} else {
+					args = append(args, argName+": "+arg.Type)
+				}
+			}
+			b.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+		b.WriteString(": " + field.Type + "\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+// usesScalar reports whether any field or argument references scalar.
+func (s *Schema) usesScalar(scalar string) bool {
+	objects := []*ObjectType{s.Query, s.Mutation}
+	for _, obj := range s.Types {
+		objects = append(objects, obj)
+	}
+	for _, obj := range objects {
+		if obj == nil {
+			continue
+		}
+		for _, field := range obj.Fields {
+			if namedType(field.Type) == scalar {
+				return true
+			}
+			for _, arg := range field.Args {
+				if namedType(arg.Type) == scalar {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// sdlString quotes a description as a GraphQL string literal.
+func sdlString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// define adds a root field, refusing to silently replace another
+// category's field of the same name.
+func define(fields map[string]*Field, name string, f *Field) {
+	if _, dup := fields[name]; dup {
+		panic(fmt.Sprintf("graphql: field %s is defined twice", name))
+	}
+	fields[name] = f
+}
@@ -0,0 +1,544 @@
+package graphql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Per-operation limits, so that one request cannot fan out into
+// unbounded resolver work.
+const (
+	maxQueryDepth     = 15
+	maxQueryFields    = 500
+	maxOutboundFields = 1
+)
+
+// validator checks a parsed document against the schema before any of
+// it is executed, following the validation rules of the GraphQL spec.
+type validator struct {
+	schema *Schema
+	doc    *document
+	errs   []Error
+}
+
+// definitionInfo records what one operation or fragment body refers to,
+// so variable and fragment rules can follow spreads transitively.
+type definitionInfo struct {
+	spreads   []fragmentRef
+	variables []variableUsage
+}
+
+type fragmentRef struct {
+	name string
+	loc  Location
+}
+
+// variableUsage is a variable written where a value of type Type is
+// expected.
+type variableUsage struct {
+	name string
+	typ  string
+	loc  Location
+	// hasDefault is set when the position itself has a default value,
+	// which lets a nullable variable fill a non-null position.
+	hasDefault bool
+}
+
+// validate returns the validation errors of doc, or nil when it may be
+// executed.
+func (s *Schema) validate(doc *document) []Error {
+	v := &validator{schema: s, doc: doc}
+	v.errs = append(v.errs, doc.duplicates...)
+
+	if len(doc.Operations) > 1 {
+		for _, op := range doc.Operations {
+			if op.Name == "" {
+				v.errorf([]Location{op.Loc}, "This anonymous operation must be the only defined operation.")
+			}
+		}
+	}
+
+	operations := make([]*definitionInfo, len(doc.Operations))
+	for i, op := range doc.Operations {
+		operations[i] = v.operation(op)
+	}
+	fragments := map[string]*definitionInfo{}
+	for _, name := range doc.fragmentOrder {
+		fragments[name] = v.fragment(doc.Fragments[name])
+	}
+
+	v.fragmentCycles(fragments)
+
+	used := map[string]bool{}
+	for i, op := range doc.Operations {
+		info := v.reachable(operations[i], fragments, used)
+		v.operationVariables(op, info)
+	}
+	for _, name := range doc.fragmentOrder {
+		if !used[name] {
+			v.errorf([]Location{doc.Fragments[name].Loc}, "Fragment %q is never used.", name)
+		}
+	}
+	if len(v.errs) == 0 {
+		for _, op := range doc.Operations {
+			v.limits(op)
+		}
+	}
+	return v.errs
+}
+
+func (v *validator) errorf(locs []Location, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{Message: fmt.Sprintf(format, args...), Locations: locs})
+}
+
+func (v *validator) operation(op *operation) *definitionInfo {
+	info := &definitionInfo{}
+	v.directives(op.Directives, strings.ToUpper(op.Type), info)
+
+	seen := map[string]bool{}
+	for _, def := range op.Variables {
+		if seen[def.Name] {
+			v.errorf([]Location{def.Loc}, "There can be only one variable named \"$%s\".", def.Name)
+		}
+		seen[def.Name] = true
+		if !v.typeExists(namedType(def.Type)) {
+			v.errorf([]Location{def.Loc}, "Unknown type %q.", namedType(def.Type))
+			continue
+		}
+		if !v.schema.isInputType(def.Type) {
+			v.errorf([]Location{def.Loc}, "Variable \"$%s\" cannot be non-input type %q.", def.Name, def.Type)
+			continue
+		}
+		if def.Default != nil {
+			v.value(def.Type, *def.Default, &definitionInfo{})
+		}
+	}
+
+	var root *ObjectType
+	switch op.Type {
+	case "query":
+		root = v.schema.Query
+	case "mutation":
+		root = v.schema.Mutation
+	}
+	if root == nil {
+		v.errorf([]Location{op.Loc}, "Schema is not configured for %ss.", op.Type)
+		return info
+	}
+	v.selections(root, op.Selections, info)
+	return info
+}
+
+func (v *validator) fragment(frag *fragment) *definitionInfo {
+	info := &definitionInfo{}
+	v.directives(frag.Directives, "FRAGMENT_DEFINITION", info)
+	if obj := v.conditionType(frag.TypeCondition, frag.Loc); obj != nil {
+		v.selections(obj, frag.Selections, info)
+	}
+	return info
+}
+
+// conditionType resolves a fragment's type condition, reporting unknown
+// and non-object types.
+func (v *validator) conditionType(name string, loc Location) *ObjectType {
+	if !v.typeExists(name) {
+		v.errorf([]Location{loc}, "Unknown type %q.", name)
+		return nil
+	}
+	obj := v.schema.objectType(name)
+	if obj == nil {
+		v.errorf([]Location{loc}, "Fragment cannot condition on non composite type %q.", name)
+	}
+	return obj
+}
+
+func (v *validator) typeExists(name string) bool {
+	return v.schema.objectType(name) != nil || v.schema.isLeafType(name)
+}
+
+// selections validates a selection set on parent.
+func (v *validator) selections(parent *ObjectType, sels []selection, info *definitionInfo) {
+	for _, sel := range sels {
+		switch sel.Kind {
+		case fieldSelection:
+			v.directives(sel.Directives, "FIELD", info)
+			v.field(parent, sel, info)
+		case fragmentSpread:
+			v.directives(sel.Directives, "FRAGMENT_SPREAD", info)
+			info.spreads = append(info.spreads, fragmentRef{name: sel.Name, loc: sel.Loc})
+			frag, ok := v.doc.Fragments[sel.Name]
+			if !ok {
+				v.errorf([]Location{sel.Loc}, "Unknown fragment %q.", sel.Name)
+				continue
+			}
+			if v.schema.objectType(frag.TypeCondition) != nil && frag.TypeCondition != parent.Name {
+				v.errorf([]Location{sel.Loc}, "Fragment %q cannot be spread here as objects of type %q can never be of type %q.",
+					sel.Name, parent.Name, frag.TypeCondition)
+			}
+		case inlineFragment:
+			v.directives(sel.Directives, "INLINE_FRAGMENT", info)
+			target := parent
+			if sel.TypeCondition != "" {
+				if target = v.conditionType(sel.TypeCondition, sel.Loc); target == nil {
+					continue
+				}
+				if target != parent {
+					v.errorf([]Location{sel.Loc}, "Fragment cannot be spread here as objects of type %q can never be of type %q.",
+						parent.Name, target.Name)
+				}
+			}
+			v.selections(target, sel.Selections, info)
+		}
+	}
+	v.overlaps(sels)
+}
+
+func (v *validator) field(parent *ObjectType, sel selection, info *definitionInfo) {
+	def := v.schema.fieldDef(parent, sel.Name)
+	if def == nil {
+		v.errorf([]Location{sel.Loc}, "Cannot query field %q on type %q.", sel.Name, parent.Name)
+		return
+	}
+	v.arguments(def.Args, sel.Arguments, sel.Loc, info,
+		func(name string) string {
+			return fmt.Sprintf("Unknown argument %q on field \"%s.%s\".", name, parent.Name, sel.Name)
+		},
+		func(name, typ string) string {
+			return fmt.Sprintf("Field %q argument %q of type %q is required, but it was not provided.", sel.Name, name, typ)
+		})
+
+	named := namedType(def.Type)
+	if v.schema.isLeafType(named) {
+		if len(sel.Selections) > 0 {
+			v.errorf([]Location{sel.Loc}, "Field %q must not have a selection since type %q has no subfields.", sel.Name, def.Type)
+		}
+		return
+	}
+	if len(sel.Selections) == 0 {
+		v.errorf([]Location{sel.Loc}, "Field %q of type %q must have a selection of subfields. Did you mean \"%s { ... }\"?",
+			sel.Name, def.Type, sel.Name)
+		return
+	}
+	v.selections(v.schema.objectType(named), sel.Selections, info)
+}
+
+// queryCost tallies the fields an operation selects.
+type queryCost struct {
+	fields, outbound, depth int
+}
+
+// limits reports an operation nested deeper than maxQueryDepth, selecting
+// more than maxQueryFields fields or more than maxOutboundFields outbound
+// ones. Aliases count as separate fields since each one is resolved, and
+// fragments are expanded at every spread, so nesting them cannot multiply
+// the work past the caps. It runs only on a valid document, whose
+// fragments are known and acyclic.
+func (v *validator) limits(op *operation) {
+	root := v.schema.Query
+	if op.Type == "mutation" {
+		root = v.schema.Mutation
+	}
+	cost := &queryCost{}
+	v.measure(root, op.Selections, 1, cost)
+	switch {
+	case cost.depth > maxQueryDepth:
+		v.errorf([]Location{op.Loc}, "Operation is nested more than %d levels deep.", maxQueryDepth)
+	case cost.fields > maxQueryFields:
+		v.errorf([]Location{op.Loc}, "Operation selects more than %d fields.", maxQueryFields)
+	case cost.outbound > maxOutboundFields:
+		v.errorf([]Location{op.Loc}, "Operation selects %d network or OSINT fields; at most %d may be queried per request.",
+			cost.outbound, maxOutboundFields)
+	}
+}
+
+// measure adds the fields of sels, selected on parent at depth, to cost.
+// It stops descending once a limit is exceeded, which also bounds the
+// walk through fragments that spread each other many times over.
+func (v *validator) measure(parent *ObjectType, sels []selection, depth int, cost *queryCost) {
+	if depth > maxQueryDepth {
+		cost.depth = depth
+		return
+	}
+	for _, sel := range sels {
+		if cost.fields > maxQueryFields {
+			return
+		}
+		switch sel.Kind {
+		case fieldSelection:
+			cost.fields++
+			cost.depth = max(cost.depth, depth)
+			def := v.schema.fieldDef(parent, sel.Name)
+			if def.Outbound {
+				cost.outbound++
+			}
+			if obj := v.schema.objectType(namedType(def.Type)); obj != nil {
+				v.measure(obj, sel.Selections, depth+1, cost)
+			}
+		case fragmentSpread:
+			frag := v.doc.Fragments[sel.Name]
+			v.measure(v.schema.objectType(frag.TypeCondition), frag.Selections, depth, cost)
+		case inlineFragment:
+			target := parent
+			if sel.TypeCondition != "" {
+				target = v.schema.objectType(sel.TypeCondition)
+			}
+			v.measure(target, sel.Selections, depth, cost)
+		}
+	}
+}
+
+// arguments checks the arguments given to a field or directive against
+// its definitions: no unknown arguments, every required argument present
+// and every literal a valid value of its type.
+func (v *validator) arguments(defs map[string]*Argument, given map[string]argValue, loc Location, info *definitionInfo,
+	unknown func(name string) string, missing func(name, typ string) string) {
+	for _, name := range sortedKeys(given) {
+		def, ok := defs[name]
+		if !ok {
+			v.errs = append(v.errs, Error{Message: unknown(name), Locations: []Location{given[name].loc}})
+			continue
+		}
+		val := given[name]
+		if val.isVariable {
+			info.variables = append(info.variables, variableUsage{
+				name: val.varName, typ: def.Type, loc: val.loc, hasDefault: def.DefaultValue != "",
+			})
+			continue
+		}
+		v.value(def.Type, val, info)
+	}
+	for _, name := range sortedKeys(defs) {
+		def := defs[name]
+		if _, ok := given[name]; !ok && isNonNull(def.Type) && def.DefaultValue == "" {
+			v.errs = append(v.errs, Error{Message: missing(name, def.Type), Locations: []Location{loc}})
+		}
+	}
+}
+
+// value checks a literal input value against type ref, recording the
+// variables nested inside it.
+func (v *validator) value(ref string, val argValue, info *definitionInfo) {
+	if val.isVariable {
+		info.variables = append(info.variables, variableUsage{name: val.varName, typ: ref, loc: val.loc})
+		return
+	}
+	if val.isNullLiteral() {
+		if isNonNull(ref) {
+			v.errorf([]Location{val.loc}, "Expected value of type %q, found %s.", ref, val)
+		}
+		return
+	}
+	inner := nullable(ref)
+	if item, ok := listItem(inner); ok {
+		if !val.isList {
+			v.value(item, val, info)
+			return
+		}
+		for _, elem := range val.list {
+			v.value(item, elem, info)
+		}
+		return
+	}
+	if inner == scalarJSON {
+		// Any literal is a JSON value; only its variables need checking.
+		for _, name := range val.variables() {
+			info.variables = append(info.variables, variableUsage{name: name, typ: scalarJSON, loc: val.loc})
+		}
+		return
+	}
+	if val.isList || val.isObject || val.isEnum {
+		v.errorf([]Location{val.loc}, "Expected value of type %q, found %s.", ref, val)
+		return
+	}
+	if _, err := coerceInput(inner, val.literal); err != nil {
+		v.errorf([]Location{val.loc}, "Expected value of type %q, found %s; %s", ref, val, err)
+	}
+}
+
+// directives checks that each directive is known, allowed at location,
+// not repeated, and given valid arguments.
+func (v *validator) directives(directives []directive, location string, info *definitionInfo) {
+	seen := map[string]bool{}
+	for _, d := range directives {
+		def, ok := directiveDefs[d.Name]
+		if !ok {
+			v.errorf([]Location{d.Loc}, "Unknown directive \"@%s\".", d.Name)
+			continue
+		}
+		if !slices.Contains(def.Locations, location) {
+			v.errorf([]Location{d.Loc}, "Directive \"@%s\" may not be used on %s.", d.Name, location)
+		}
+		if seen[d.Name] {
+			v.errorf([]Location{d.Loc}, "The directive \"@%s\" can only be used once at this location.", d.Name)
+		}
+		seen[d.Name] = true
+		v.arguments(def.Args, d.Arguments, d.Loc, info,
+			func(name string) string {
+				return fmt.Sprintf("Unknown argument %q on directive \"@%s\".", name, d.Name)
+			},
+			func(name, typ string) string {
+				return fmt.Sprintf("Directive \"@%s\" argument %q of type %q is required, but it was not provided.", d.Name, name, typ)
+			})
+	}
+}
+
+// overlaps reports fields of one selection set that share a response key
+// but could not be merged: different fields, or different arguments.
+// Fields reached through fragments are checked where the fragment is
+// defined.
+func (v *validator) overlaps(sels []selection) {
+	first := map[string]selection{}
+	for _, sel := range sels {
+		if sel.Kind != fieldSelection {
+			continue
+		}
+		prev, ok := first[sel.Alias]
+		if !ok {
+			first[sel.Alias] = sel
+			continue
+		}
+		locs := []Location{prev.Loc, sel.Loc}
+		switch {
+		case prev.Name != sel.Name:
+			v.errorf(locs, "Fields %q conflict because %q and %q are different fields. Use different aliases on the fields to fetch both if this was intentional.",
+				sel.Alias, prev.Name, sel.Name)
+		case argumentsString(prev.Arguments) != argumentsString(sel.Arguments):
+			v.errorf(locs, "Fields %q conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.",
+				sel.Alias)
+		}
+	}
+}
+
+// argumentsString prints an argument list canonically, for comparison.
+func argumentsString(args map[string]argValue) string {
+	parts := make([]string, 0, len(args))
+	for _, name := range sortedKeys(args) {
+		parts = append(parts, name+": "+args[name].String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fragmentCycles reports fragments that spread themselves, directly or
+// through other fragments.
+func (v *validator) fragmentCycles(fragments map[string]*definitionInfo) {
+	reported := map[string]bool{}
+	var visit func(name string, stack []string)
+	visit = func(name string, stack []string) {
+		info, ok := fragments[name]
+		if !ok {
+			return
+		}
+		for _, spread := range info.spreads {
+			if i := slices.Index(stack, spread.name); i >= 0 {
+				if !reported[spread.name] {
+					reported[spread.name] = true
+					msg := fmt.Sprintf("Cannot spread fragment %q within itself", spread.name)
+					if via := stack[i+1:]; len(via) > 0 {
+						msg += " via " + strings.Join(via, ", ")
+					}
+					v.errorf([]Location{spread.loc}, "%s.", msg)
+				}
+				continue
+			}
+			if reported[spread.name] {
+				continue
+			}
+			visit(spread.name, append(stack, spread.name))
+		}
+	}
+	for _, name := range v.doc.fragmentOrder {
+		visit(name, []string{name})
+	}
+}
+
+// reachable merges an operation's info with that of every fragment it
+// spreads, directly or indirectly, and marks those fragments used.
+func (v *validator) reachable(op *definitionInfo, fragments map[string]*definitionInfo, used map[string]bool) *definitionInfo {
+	merged := &definitionInfo{variables: slices.Clone(op.variables)}
+	visited := map[string]bool{}
+	queue := slices.Clone(op.spreads)
+	for len(queue) > 0 {
+		spread := queue[0]
+		queue = queue[1:]
+		if visited[spread.name] {
+			continue
+		}
+		visited[spread.name] = true
+		used[spread.name] = true
+		if info, ok := fragments[spread.name]; ok {
+			merged.variables = append(merged.variables, info.variables...)
+			queue = append(queue, info.spreads...)
+		}
+	}
+	return merged
+}
+
+// operationVariables checks that every variable an operation uses is
+// defined with a compatible type, and that every defined variable is
+// used.
+func (v *validator) operationVariables(op *operation, info *definitionInfo) {
+	defs := map[string]variableDef{}
+	for _, def := range op.Variables {
+		if _, ok := defs[def.Name]; !ok {
+			defs[def.Name] = def
+		}
+	}
+	used := map[string]bool{}
+	for _, usage := range info.variables {
+		used[usage.name] = true
+		def, ok := defs[usage.name]
+		if !ok {
+			if op.Name != "" {
+				v.errorf([]Location{usage.loc, op.Loc}, "Variable \"$%s\" is not defined by operation %q.", usage.name, op.Name)
+			} else {
+				v.errorf([]Location{usage.loc, op.Loc}, "Variable \"$%s\" is not defined.", usage.name)
+			}
+			continue
+		}
+		if !v.schema.isInputType(def.Type) {
+			continue
+		}
+		varType := def.Type
+		locType := usage.typ
+		hasDefault := def.Default != nil && !def.Default.isNullLiteral()
+		if isNonNull(locType) && !isNonNull(varType) && (hasDefault || usage.hasDefault) {
+			locType = nullable(locType)
+		}
+		if !typeAllowed(varType, locType) {
+			v.errorf([]Location{def.Loc, usage.loc}, "Variable \"$%s\" of type %q used in position expecting type %q.",
+				usage.name, def.Type, usage.typ)
+		}
+	}
+	for _, def := range op.Variables {
+		if used[def.Name] {
+			continue
+		}
+		if op.Name != "" {
+			v.errorf([]Location{def.Loc}, "Variable \"$%s\" is never used in operation %q.", def.Name, op.Name)
+		} else {
+			v.errorf([]Location{def.Loc}, "Variable \"$%s\" is never used.", def.Name)
+		}
+	}
+}
+
+// typeAllowed reports whether a variable of type varType may be used
+// where locType is expected: the same type, or a stricter one. The JSON
+// scalar accepts a variable of any type.
+func typeAllowed(varType, locType string) bool {
+	if isNonNull(locType) {
+		return isNonNull(varType) && typeAllowed(nullable(varType), nullable(locType))
+	}
+	if isNonNull(varType) {
+		return typeAllowed(nullable(varType), locType)
+	}
+	if locType == scalarJSON {
+		return true
+	}
+	locItem, locIsList := listItem(locType)
+	varItem, varIsList := listItem(varType)
+	if locIsList || varIsList {
+		return locIsList && varIsList && typeAllowed(varItem, locItem)
+	}
+	return varType == locType
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/apimgr/api/src/ssl"
 	"github.com/apimgr/api/src/sysservice"
 	"github.com/apimgr/api/src/tor"
+	"github.com/apimgr/api/src/tzboundary"
 )
 
 var (
@@ -230,6 +231,19 @@ func main() {
 		log.Printf("Warning: Failed to load GeoIP database: %v (will auto-download on first request)", err)
 	}
 
+	// Load the timezone boundary dataset, fetching it in the background when
+	// missing (lookups use the online provider until it arrives)
+	if err := tzboundary.Get().Load(paths.DataDir()); err != nil {
+		log.Printf("Warning: Failed to load timezone boundary dataset: %v", err)
+	}
+	if !tzboundary.Get().Loaded() {
+		go func() {
+			if err := tzboundary.Download(context.Background(), paths.DataDir()); err != nil {
+				log.Printf("Warning: Failed to download timezone boundary dataset: %v", err)
+			}
+		}()
+	}
+
 	// Override config with CLI flags (flags have highest priority)
 	if resolvedAddress != "" {
 		cfg.Server.Address = resolvedAddress
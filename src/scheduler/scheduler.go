@@ -49,10 +49,10 @@ func New() *Scheduler {
 }
 
 // AddTask adds a new task to the scheduler.
-// schedule: 5-field cron expression, @hourly, @daily, @weekly, @monthly, or
-// @every X. Persisted state (next_run, enabled, last_run) is restored from
-// the database if a row already exists for this task, so schedules survive
-// restarts per AI.md PART 18.
+// schedule: cron expression (see package cron), @hourly, @daily, @weekly,
+// @monthly, or @every X. Persisted state (next_run, enabled, last_run) is
+// restored from the database if a row already exists for this task, so
+// schedules survive restarts per AI.md PART 18.
 func (s *Scheduler) AddTask(name string, sched string, fn func() error, enabledDefault bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
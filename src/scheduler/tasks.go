@@ -15,6 +15,7 @@ import (
 	"github.com/apimgr/api/src/paths"
 	"github.com/apimgr/api/src/ssl"
 	"github.com/apimgr/api/src/tor"
+	"github.com/apimgr/api/src/tzboundary"
 )
 
 // RegisterDefaultTasks registers all built-in scheduled tasks
@@ -28,6 +29,9 @@ func (s *Scheduler) RegisterDefaultTasks() {
 	// GeoIP database update at 03:00 Sunday
 	s.AddTask("geoip_update", "0 3 * * 0", geoipUpdateTask, true)
 
+	// Timezone boundary dataset update at 04:00 Sunday
+	s.AddTask("timezone_update", "0 4 * * 0", timezoneUpdateTask, true)
+
 	// Token cleanup every 15 minutes
 	s.AddTask("token_cleanup", "@every 15m", tokenCleanupTask, true)
 
@@ -147,6 +151,20 @@ func geoipUpdateTask() error {
 	return nil
 }
 
+// timezoneUpdateTask downloads the latest timezone-boundary dataset used
+// for offline coordinate-to-timezone lookups
+func timezoneUpdateTask() error {
+	log.Println("Scheduler: Updating timezone boundary dataset...")
+
+	if err := tzboundary.Download(context.Background(), paths.DataDir()); err != nil {
+		log.Printf("Scheduler: Timezone boundary update failed: %v", err)
+		return err
+	}
+
+	log.Println("Scheduler: Timezone boundary update completed successfully")
+	return nil
+}
+
 // tokenCleanupTask removes expired ephemeral state.
 // This project has no user accounts, sessions, or API tokens (IDEA.md
 // non-goals) — the closest real expiring state to PART 18's spec purpose
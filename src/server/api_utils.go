@@ -1,15 +1,20 @@
 package server
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apimgr/api/src/config"
 	"github.com/apimgr/api/src/service/convert"
@@ -396,7 +401,9 @@ func apiDockerSizeOptimizerHandler(w http.ResponseWriter, r *http.Request) {
 // path parameter.
 func apiWeatherCurrentHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	weatherData, err := weatherService.GetCurrentWeather(location)
+	weatherData, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("current", location), func(context.Context) (*weather.CurrentWeather, error) {
+		return weatherService.GetCurrentWeather(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -427,7 +434,9 @@ func apiWeatherForecastHandler(w http.ResponseWriter, r *http.Request) {
 	if !validateStruct(w, weatherForecastParams{Days: days}) {
 		return
 	}
-	forecast, err := weatherService.GetForecast(location, days)
+	forecast, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("forecast", location, strconv.Itoa(days)), func(context.Context) ([]*weather.Forecast, error) {
+		return weatherService.GetForecast(location, days)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -443,7 +452,9 @@ func apiWeatherForecastHandler(w http.ResponseWriter, r *http.Request) {
 // pollutant concentrations) for the {location} path parameter.
 func apiWeatherAirQualityHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	data, err := weatherService.GetAirQuality(location)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("air-quality", location), func(context.Context) (*weather.AirQuality, error) {
+		return weatherService.GetAirQuality(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -456,7 +467,9 @@ func apiWeatherAirQualityHandler(w http.ResponseWriter, r *http.Request) {
 // Canada (CA), and MeteoAlarm (Europe), normalized into a uniform shape.
 func apiWeatherAlertsHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	alerts, err := weatherService.GetAlerts(location)
+	alerts, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("alerts", location), func(context.Context) ([]*weather.Alert, error) {
+		return weatherService.GetAlerts(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -471,7 +484,9 @@ func apiWeatherAlertsHandler(w http.ResponseWriter, r *http.Request) {
 // the {location} path parameter.
 func apiWeatherAstronomyHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	data, err := weatherService.GetAstronomy(location)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("astronomy", location), func(context.Context) (*weather.Astronomy, error) {
+		return weatherService.GetAstronomy(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -497,7 +512,9 @@ func apiWeatherHistoricalHandler(w http.ResponseWriter, r *http.Request) {
 	if !validateStruct(w, weatherHistoricalParams{Start: start, End: end}) {
 		return
 	}
-	data, err := weatherService.GetHistorical(location, start, end)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("historical", location, start, end), func(context.Context) ([]*weather.HistoricalDay, error) {
+		return weatherService.GetHistorical(location, start, end)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -533,7 +550,9 @@ func apiWeatherHourlyHandler(w http.ResponseWriter, r *http.Request) {
 	if !validateStruct(w, weatherHourlyParams{Hours: hours}) {
 		return
 	}
-	data, err := weatherService.GetHourly(location, hours)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("hourly", location, strconv.Itoa(hours)), func(context.Context) ([]*weather.HourlyEntry, error) {
+		return weatherService.GetHourly(location, hours)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -556,7 +575,9 @@ func apiWeatherMapsHandler(w http.ResponseWriter, r *http.Request) {
 // not an error.
 func apiWeatherMarineHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	data, err := weatherService.GetMarine(location)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("marine", location), func(context.Context) (*weather.MarineConditions, error) {
+		return weatherService.GetMarine(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -569,7 +590,9 @@ func apiWeatherMarineHandler(w http.ResponseWriter, r *http.Request) {
 // upstream provider; other regions return an explanatory coverage note.
 func apiWeatherPollenHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	data, err := weatherService.GetPollen(location)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("pollen", location), func(context.Context) (*weather.Pollen, error) {
+		return weatherService.GetPollen(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -587,7 +610,9 @@ func apiWeatherRadarHandler(w http.ResponseWriter, r *http.Request) {
 // path parameter.
 func apiWeatherUVHandler(w http.ResponseWriter, r *http.Request) {
 	location := chi.URLParam(r, "location")
-	data, err := weatherService.GetUVIndex(location)
+	data, err := cachedLookup(r.Context(), w, "weather", weather.CacheKey("uv", location), func(context.Context) (*weather.UVIndex, error) {
+		return weatherService.GetUVIndex(location)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "WEATHER_LOOKUP_FAILED", err.Error(), nil)
 		return
@@ -743,7 +768,9 @@ func apiGeoGeocodeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results, err := geoService.Geocode(query)
+	results, err := cachedLookup(r.Context(), w, "geocode", geo.GeocodeCacheKey(query), func(context.Context) ([]*geo.GeocodeResult, error) {
+		return geoService.Geocode(query)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "UPSTREAM_ERROR", err.Error(), nil)
 		return
@@ -763,7 +790,9 @@ func apiGeoReverseHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := geoService.ReverseGeocode(lat, lon)
+	result, err := cachedLookup(r.Context(), w, "geocode", geo.ReverseGeocodeCacheKey(lat, lon), func(context.Context) (*geo.ReverseGeocodeResult, error) {
+		return geoService.ReverseGeocode(lat, lon)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "UPSTREAM_ERROR", err.Error(), nil)
 		return
@@ -772,8 +801,9 @@ func apiGeoReverseHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // apiGeoTimezoneHandler resolves the IANA timezone name for a coordinate
-// using the free, keyless Open-Meteo forecast API's timezone=auto
-// resolution.
+// from the offline timezone-boundary dataset, falling back to the free,
+// keyless Open-Meteo forecast API's timezone=auto resolution when no
+// dataset is loaded.
 func apiGeoTimezoneHandler(w http.ResponseWriter, r *http.Request) {
 	lat, lon, err := parseGeoSingleCoordinateParams(r.URL.Query())
 	if err != nil {
@@ -980,19 +1010,103 @@ func apiGeoBBoxHandler(w http.ResponseWriter, r *http.Request) {
 // mathCalculateParams validates the ?operation= query parameter for
 // apiMathCalculateHandler.
 type mathCalculateParams struct {
-	Operation string `validate:"required,oneof=add subtract multiply divide power percentage_of percentage_change modulo gcd lcm sqrt cbrt abs round floor ceil log log10 log2 exp sin cos tan factorial"`
+	Operation string `validate:"required,oneof=add subtract multiply divide power percentage_of percentage_change modulo gcd lcm sqrt cbrt abs round floor ceil log log10 log2 exp sin cos tan factorial expression"`
+}
+
+// mathModeParams validates the optional ?mode= and ?precision= query
+// parameters shared by the /math/* handlers that support arbitrary
+// precision.
+type mathModeParams struct {
+	Mode      string `validate:"omitempty,oneof=float bigint bigfloat"`
+	Precision uint   `validate:"omitempty,min=2,max=16384"`
+}
+
+// parseMathMode resolves the numeric mode for a /math/* request: "float"
+// (the default float64/int64 methods), "bigint" (math/big.Int, operands as
+// decimal or 0x/0o/0b strings), or "bigfloat" (math/big.Float with a
+// ?precision= mantissa in bits, default math.DefaultBigFloatPrecision).
+// Supplying ?precision= alone implies bigfloat. On failure it writes the
+// error envelope and returns ok=false.
+func parseMathMode(w http.ResponseWriter, q url.Values) (mode string, precision uint, ok bool) {
+	mode = strings.ToLower(q.Get("mode"))
+	precision = math.DefaultBigFloatPrecision
+	if raw := q.Get("precision"); raw != "" {
+		p, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "precision must be a positive integer (bits)", nil)
+			return "", 0, false
+		}
+		precision = uint(p)
+		if mode == "" {
+			mode = "bigfloat"
+		}
+	}
+	if !validateStruct(w, mathModeParams{Mode: mode, Precision: precision}) {
+		return "", 0, false
+	}
+	if mode == "" {
+		mode = "float"
+	}
+	return mode, precision, true
+}
+
+// parseIntegerMathMode is parseMathMode for the integer-only /math/*
+// handlers: it rejects bigfloat mode and ?precision=, which they could not
+// honour, naming the operation as what.
+func parseIntegerMathMode(w http.ResponseWriter, q url.Values, what string) (mode string, ok bool) {
+	mode, _, ok = parseMathMode(w, q)
+	if !ok {
+		return "", false
+	}
+	if mode == "bigfloat" || q.Get("precision") != "" {
+		writeEnvelopeError(w, http.StatusBadRequest, "UNSUPPORTED_OPERATION", what+" supports float and bigint modes only, without precision", nil)
+		return "", false
+	}
+	return mode, true
+}
+
+// writeBigMathError maps an arbitrary-precision math.Service error to its
+// envelope error code.
+func writeBigMathError(w http.ResponseWriter, err error) {
+	code := "INVALID_VALUE"
+	switch {
+	case errors.Is(err, math.ErrDivisionByZero):
+		code = "DIVISION_BY_ZERO"
+	case errors.Is(err, math.ErrNegativeSquareRoot):
+		code = "NEGATIVE_SQUARE_ROOT"
+	case errors.Is(err, math.ErrBigIntTooLarge), errors.Is(err, math.ErrBigFloatTooLarge), errors.Is(err, math.ErrModularTooLarge):
+		code = "VALUE_TOO_LARGE"
+	case errors.Is(err, math.ErrNoModularInverse):
+		code = "NO_MODULAR_INVERSE"
+	}
+	writeEnvelopeError(w, http.StatusBadRequest, code, err.Error(), nil)
 }
 
 // apiMathCalculateHandler dispatches to a math.Service operation selected
-// by ?operation=, composing the existing named methods rather than
-// evaluating a generic expression (no expression evaluator exists or may
-// be invented).
+// by ?operation=, composing the existing named methods. The "expression"
+// operation instead evaluates ?expression= with math.Service.Evaluate, a
+// sandboxed parser over the same methods with variables bound from
+// ?variables=name:value,... (never eval'd or executed). ?mode=bigint and
+// ?mode=bigfloat (or ?precision=) route to mathBigIntCalculate and
+// mathBigFloatCalculate instead.
 func apiMathCalculateHandler(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	operation := strings.ToLower(q.Get("operation"))
 	if !validateStruct(w, mathCalculateParams{Operation: operation}) {
 		return
 	}
+	mode, precision, ok := parseMathMode(w, q)
+	if !ok {
+		return
+	}
+	switch mode {
+	case "bigint":
+		mathBigIntCalculate(w, q, operation)
+		return
+	case "bigfloat":
+		mathBigFloatCalculate(w, q, operation, precision)
+		return
+	}
 
 	parseFloatParam := func(name string) (float64, bool) {
 		raw := q.Get(name)
@@ -1106,22 +1220,312 @@ func apiMathCalculateHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		writeEnvelopeOK(w, http.StatusOK, map[string]string{"result": mathService.Factorial(n).String()})
+	case "expression":
+		expression := q.Get("expression")
+		if strings.TrimSpace(expression) == "" {
+			writeEnvelopeError(w, http.StatusBadRequest, "MISSING_EXPRESSION", "expression query parameter is required", nil)
+			return
+		}
+		variables, err := math.ParseExpressionVariables(q.Get("variables"))
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VARIABLES", err.Error(), nil)
+			return
+		}
+		result, err := mathService.Evaluate(expression, variables)
+		if err != nil {
+			code := "INVALID_EXPRESSION"
+			switch {
+			case errors.Is(err, math.ErrDivisionByZero):
+				code = "DIVISION_BY_ZERO"
+			case errors.Is(err, math.ErrExpressionTooLong), errors.Is(err, math.ErrExpressionTooDeep), errors.Is(err, math.ErrTooManyVariables):
+				code = "EXPRESSION_TOO_COMPLEX"
+			}
+			writeEnvelopeError(w, http.StatusBadRequest, code, err.Error(), nil)
+			return
+		}
+		writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+			"expression": expression,
+			"variables":  variables,
+			"result":     result,
+		})
+	}
+}
+
+// mathBigIntCalculate is apiMathCalculateHandler's ?mode=bigint branch:
+// operands ?a=/?b= (or ?n=) are parsed with math.ParseBigInt and the
+// operation runs on the math.Service Big* methods, returning the result as
+// a decimal string.
+func mathBigIntCalculate(w http.ResponseWriter, q url.Values, operation string) {
+	parseOperand := func(name string) (*big.Int, bool) {
+		raw := q.Get(name)
+		if raw == "" {
+			writeEnvelopeError(w, http.StatusBadRequest, "MISSING_OPERANDS", fmt.Sprintf("%s query parameter is required", name), nil)
+			return nil, false
+		}
+		v, err := math.ParseBigInt(raw)
+		if err != nil {
+			writeBigMathError(w, err)
+			return nil, false
+		}
+		return v, true
+	}
+
+	var result, remainder *big.Int
+	var err error
+	switch operation {
+	case "add", "subtract", "multiply", "divide", "power", "modulo", "gcd", "lcm":
+		a, ok := parseOperand("a")
+		if !ok {
+			return
+		}
+		b, ok := parseOperand("b")
+		if !ok {
+			return
+		}
+		switch operation {
+		case "add":
+			result, err = mathService.BigAdd(a, b)
+		case "subtract":
+			result, err = mathService.BigSubtract(a, b)
+		case "multiply":
+			result, err = mathService.BigMultiply(a, b)
+		case "divide":
+			result, remainder, err = mathService.BigDivide(a, b)
+		case "power":
+			result, err = mathService.BigPower(a, b)
+		case "modulo":
+			result, err = mathService.BigModulo(a, b)
+		case "gcd":
+			result = mathService.BigGCD(a, b)
+		case "lcm":
+			result, err = mathService.BigLCM(a, b)
+		}
+	case "sqrt", "abs", "factorial":
+		n, ok := parseOperand("n")
+		if !ok {
+			return
+		}
+		switch operation {
+		case "sqrt":
+			result, err = mathService.BigSquareRoot(n)
+		case "abs":
+			result = mathService.BigAbs(n)
+		case "factorial":
+			if !n.IsInt64() {
+				err = fmt.Errorf("factorial operand must be between 0 and %d", math.MaxBigFactorial)
+				break
+			}
+			result, err = mathService.BigFactorial(n.Int64())
+		}
+	default:
+		writeEnvelopeError(w, http.StatusBadRequest, "UNSUPPORTED_OPERATION", fmt.Sprintf("operation %q is not available in bigint mode", operation), nil)
+		return
+	}
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"mode":   "bigint",
+		"result": result.String(),
+	}
+	if remainder != nil {
+		data["remainder"] = remainder.String()
+	}
+	writeEnvelopeOK(w, http.StatusOK, data)
+}
+
+// mathBigFloatCalculate is apiMathCalculateHandler's ?mode=bigfloat
+// branch: operands are parsed with math.ParseBigFloat at the requested
+// precision and the operation runs on the math.Service BigFloat* methods.
+func mathBigFloatCalculate(w http.ResponseWriter, q url.Values, operation string, precision uint) {
+	parseOperand := func(name string) (*big.Float, bool) {
+		raw := q.Get(name)
+		if raw == "" {
+			writeEnvelopeError(w, http.StatusBadRequest, "MISSING_OPERANDS", fmt.Sprintf("%s query parameter is required", name), nil)
+			return nil, false
+		}
+		v, err := math.ParseBigFloat(raw, precision)
+		if err != nil {
+			writeBigMathError(w, err)
+			return nil, false
+		}
+		return v, true
+	}
+
+	var result *big.Float
+	var err error
+	switch operation {
+	case "add", "subtract", "multiply", "divide", "power":
+		a, ok := parseOperand("a")
+		if !ok {
+			return
+		}
+		b, ok := parseOperand("b")
+		if !ok {
+			return
+		}
+		switch operation {
+		case "add":
+			result, err = mathService.BigFloatAdd(a, b, precision)
+		case "subtract":
+			result, err = mathService.BigFloatSubtract(a, b, precision)
+		case "multiply":
+			result, err = mathService.BigFloatMultiply(a, b, precision)
+		case "divide":
+			result, err = mathService.BigFloatDivide(a, b, precision)
+		case "power":
+			result, err = mathService.BigFloatPower(a, b, precision)
+		}
+	case "sqrt", "abs":
+		n, ok := parseOperand("n")
+		if !ok {
+			return
+		}
+		if operation == "sqrt" {
+			result, err = mathService.BigFloatSquareRoot(n, precision)
+		} else {
+			result = mathService.BigFloatAbs(n, precision)
+		}
+	default:
+		writeEnvelopeError(w, http.StatusBadRequest, "UNSUPPORTED_OPERATION", fmt.Sprintf("operation %q is not available in bigfloat mode", operation), nil)
+		return
 	}
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"mode":      "bigfloat",
+		"precision": precision,
+		"result":    math.FormatBigFloat(result),
+	})
 }
 
 // apiMathPrimeHandler reports whether the {n} path parameter is a prime
-// number, using math.Service.IsPrime.
+// number, using math.Service.IsPrime. Values beyond int64, or any value
+// with ?mode=bigint, are tested with math.Service.IsProbablePrime
+// (Miller-Rabin) instead. Bigfloat mode and ?precision= are rejected.
 func apiMathPrimeHandler(w http.ResponseWriter, r *http.Request) {
 	nParam := chi.URLParam(r, "n")
+	mode, ok := parseIntegerMathMode(w, r.URL.Query(), "primality testing")
+	if !ok {
+		return
+	}
 	n, err := strconv.ParseInt(nParam, 10, 64)
+	if err == nil && mode != "bigint" {
+		writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+			"n":        n,
+			"is_prime": mathService.IsPrime(n),
+		})
+		return
+	}
+
+	bigN, err := math.ParseBigInt(nParam)
 	if err != nil {
+		if errors.Is(err, math.ErrBigIntTooLarge) {
+			writeBigMathError(w, err)
+			return
+		}
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "n must be an integer", nil)
 		return
 	}
+	isPrime, err := mathService.IsProbablePrime(bigN)
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"n":        bigN.String(),
+		"is_prime": isPrime,
+		"mode":     "bigint",
+		"method":   "miller-rabin",
+		"rounds":   math.PrimalityRounds,
+	})
+}
+
+// mathModPowParams validates the ?base=, ?exponent=, and ?modulus= query
+// parameters for apiMathModPowHandler.
+type mathModPowParams struct {
+	Base     string `validate:"required"`
+	Exponent string `validate:"required"`
+	Modulus  string `validate:"required"`
+}
+
+// apiMathModPowHandler computes ?base=^?exponent= mod ?modulus= with
+// math.Service.ModPow; a negative exponent uses the modular inverse.
+func apiMathModPowHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := mathModPowParams{Base: q.Get("base"), Exponent: q.Get("exponent"), Modulus: q.Get("modulus")}
+	if !validateStruct(w, params) {
+		return
+	}
+
+	operands := make([]*big.Int, 3)
+	for i, raw := range []string{params.Base, params.Exponent, params.Modulus} {
+		v, err := math.ParseBigInt(raw)
+		if err != nil {
+			writeBigMathError(w, err)
+			return
+		}
+		operands[i] = v
+	}
+
+	result, err := mathService.ModPow(operands[0], operands[1], operands[2])
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"base":     operands[0].String(),
+		"exponent": operands[1].String(),
+		"modulus":  operands[2].String(),
+		"result":   result.String(),
+	})
+}
+
+// mathModInverseParams validates the ?a= and ?modulus= query parameters
+// for apiMathModInverseHandler.
+type mathModInverseParams struct {
+	A       string `validate:"required"`
+	Modulus string `validate:"required"`
+}
+
+// apiMathModInverseHandler finds x with ?a= * x ≡ 1 (mod ?modulus=) using
+// math.Service.ModInverse, reporting NO_MODULAR_INVERSE when a and the
+// modulus are not coprime.
+func apiMathModInverseHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := mathModInverseParams{A: q.Get("a"), Modulus: q.Get("modulus")}
+	if !validateStruct(w, params) {
+		return
+	}
+
+	a, err := math.ParseBigInt(params.A)
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
+	modulus, err := math.ParseBigInt(params.Modulus)
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
+
+	result, err := mathService.ModInverse(a, modulus)
+	if err != nil {
+		writeBigMathError(w, err)
+		return
+	}
 
 	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
-		"n":        n,
-		"is_prime": mathService.IsPrime(n),
+		"a":       a.String(),
+		"modulus": modulus.String(),
+		"result":  result.String(),
 	})
 }
 
@@ -1252,7 +1656,8 @@ type mathBaseParams struct {
 }
 
 // apiMathBaseHandler converts ?number= from ?from_base= to ?to_base= using
-// math.Service.BaseConvert; both bases must be between 2 and 36.
+// math.Service.BaseConvert (or BigBaseConvert with ?mode=bigint, for
+// numbers wider than int64); both bases must be between 2 and 36.
 func apiMathBaseHandler(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	number := q.Get("number")
@@ -1273,7 +1678,15 @@ func apiMathBaseHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := mathService.BaseConvert(number, fromBase, toBase)
+	mode, ok := parseIntegerMathMode(w, q, "base conversion")
+	if !ok {
+		return
+	}
+	convertBase := mathService.BaseConvert
+	if mode == "bigint" {
+		convertBase = mathService.BigBaseConvert
+	}
+	result, err := convertBase(number, fromBase, toBase)
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
 		return
@@ -1937,11 +2350,17 @@ func apiConvertCurrencyHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result, err := convertService.ConvertCurrency(amount, from, to)
+	// The rate is cached per currency pair (converting 1 unit) and scaled
+	// here, so every amount shares one cached upstream lookup.
+	result, err := cachedLookup(r.Context(), w, "currency", strings.ToUpper(strings.TrimSpace(from))+":"+strings.ToUpper(strings.TrimSpace(to)), func(context.Context) (convert.CurrencyResult, error) {
+		return convertService.ConvertCurrency(1, from, to)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadGateway, "CURRENCY_LOOKUP_FAILED", err.Error(), nil)
 		return
 	}
+	result.Amount = amount
+	result.Result = amount * result.Rate
 
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
@@ -1969,11 +2388,26 @@ func apiDatetimeFormatHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // apiDatetimeParseHandler parses a free-form date/time string against a
-// list of common layouts via datetime.ParseDateString.
+// list of common layouts via datetime.ParseDateString, falling back to
+// natural-language expressions ("next tuesday 3pm", "2h45m ago") and
+// ISO 8601 durations and intervals. The string comes from the path or
+// ?value= (needed for intervals, which contain a slash); ?reference=
+// (RFC3339, default now) and ?timezone= (IANA, default UTC) anchor
+// relative expressions.
 func apiDatetimeParseHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
 	value := chi.URLParam(r, "value")
+	if value == "" {
+		value = q.Get("value")
+	}
+	opts := datetime.ParseOptions{Timezone: q.Get("timezone")}
+	var err error
+	if opts.Reference, err = parseOptionalRFC3339(q.Get("reference")); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_TIMESTAMP", "reference must be an RFC3339 timestamp", nil)
+		return
+	}
 
-	result, err := datetime.ParseDateString(value)
+	result, err := datetime.ParseDateString(value, opts)
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "UNPARSEABLE_DATE", err.Error(), nil)
 		return
@@ -1982,12 +2416,19 @@ func apiDatetimeParseHandler(w http.ResponseWriter, r *http.Request) {
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
-// apiDatetimeCronHandler parses a standard 5-field cron expression and
-// returns a breakdown plus next scheduled run times via datetime.ParseCron.
+// apiDatetimeCronHandler parses a cron expression and returns a
+// breakdown, a plain-English description and its next and previous run
+// times via datetime.ParseCron. ?timezone= (IANA, default UTC), ?count=
+// (1-100, default 5), and ?start= / ?end= (RFC3339) pick which runs are
+// listed.
 func apiDatetimeCronHandler(w http.ResponseWriter, r *http.Request) {
 	expression := r.URL.Query().Get("expression")
+	opts, ok := cronOptionsFromQuery(w, r.URL.Query())
+	if !ok {
+		return
+	}
 
-	result, err := datetime.ParseCron(expression)
+	result, err := datetime.ParseCron(expression, opts)
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_CRON", err.Error(), nil)
 		return
@@ -1996,11 +2437,50 @@ func apiDatetimeCronHandler(w http.ResponseWriter, r *http.Request) {
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
+// cronOptionsFromQuery reads the ?timezone=, ?count=, ?start= and ?end=
+// parameters shared by the cron handlers, writing a 400 and returning
+// false when one is malformed.
+func cronOptionsFromQuery(w http.ResponseWriter, q url.Values) (datetime.CronOptions, bool) {
+	opts := datetime.CronOptions{Timezone: q.Get("timezone")}
+	if v := q.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > datetime.CronMaxRuns {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_COUNT", fmt.Sprintf("count must be an integer between 1 and %d", datetime.CronMaxRuns), nil)
+			return opts, false
+		}
+		opts.Count = n
+	}
+	var err error
+	if opts.From, err = parseOptionalRFC3339(q.Get("start")); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_TIMESTAMP", "start must be an RFC3339 timestamp", nil)
+		return opts, false
+	}
+	if opts.Until, err = parseOptionalRFC3339(q.Get("end")); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_TIMESTAMP", "end must be an RFC3339 timestamp", nil)
+		return opts, false
+	}
+	return opts, true
+}
+
+// parseOptionalRFC3339 parses an RFC3339 timestamp, returning the zero
+// time for an empty string.
+func parseOptionalRFC3339(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
 // apiDatetimeCalendarHandler builds a week-grid calendar for a given
-// year/month via datetime.GenerateCalendar.
+// year/month via datetime.GenerateCalendar, listing the month's
+// holidays and business days for ?country=, ?region= and ?weekend=.
 func apiDatetimeCalendarHandler(w http.ResponseWriter, r *http.Request) {
 	yearParam := chi.URLParam(r, "year")
 	monthParam := chi.URLParam(r, "month")
+	opts, ok := businessOptionsFromQuery(w, r.URL.Query())
+	if !ok {
+		return
+	}
 
 	year, err := strconv.Atoi(yearParam)
 	if err != nil {
@@ -2014,7 +2494,7 @@ func apiDatetimeCalendarHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := datetime.GenerateCalendar(year, month)
+	result, err := datetime.GenerateCalendar(year, month, opts)
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_MONTH", err.Error(), nil)
 		return
@@ -2023,13 +2503,19 @@ func apiDatetimeCalendarHandler(w http.ResponseWriter, r *http.Request) {
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
-// apiDatetimeWorkdaysHandler counts weekdays (Mon-Fri) between two
-// YYYY-MM-DD dates inclusive via datetime.WorkdaysBetween.
+// apiDatetimeWorkdaysHandler counts business days between two
+// YYYY-MM-DD dates inclusive via datetime.WorkdaysBetween, skipping
+// weekends (?weekend=, default Saturday and Sunday) and the public
+// holidays of ?country= and ?region=.
 func apiDatetimeWorkdaysHandler(w http.ResponseWriter, r *http.Request) {
 	start := chi.URLParam(r, "start")
 	end := chi.URLParam(r, "end")
+	opts, ok := businessOptionsFromQuery(w, r.URL.Query())
+	if !ok {
+		return
+	}
 
-	result, err := datetime.WorkdaysBetween(start, end)
+	result, err := datetime.WorkdaysBetween(start, end, opts)
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_DATE", err.Error(), nil)
 		return
@@ -2038,66 +2524,290 @@ func apiDatetimeWorkdaysHandler(w http.ResponseWriter, r *http.Request) {
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
-// apiDatetimeSunriseHandler computes sunrise/sunset UTC times for a given
-// latitude, longitude, and optional YYYY-MM-DD date via
-// datetime.SunriseSunset (Almanac for Computers, 1990 algorithm).
-func apiDatetimeSunriseHandler(w http.ResponseWriter, r *http.Request) {
-	latParam := chi.URLParam(r, "lat")
-	lonParam := chi.URLParam(r, "lon")
+// apiDatetimeAddWorkdaysHandler adds a (possibly negative) number of
+// business days to a YYYY-MM-DD date via datetime.AddBusinessDays, using
+// the same ?country=, ?region= and ?weekend= calendar as the workdays
+// endpoint.
+func apiDatetimeAddWorkdaysHandler(w http.ResponseWriter, r *http.Request) {
 	date := chi.URLParam(r, "date")
-
-	lat, err := strconv.ParseFloat(latParam, 64)
-	if err != nil {
-		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_LATITUDE", "lat must be numeric", nil)
+	daysParam := chi.URLParam(r, "days")
+	opts, ok := businessOptionsFromQuery(w, r.URL.Query())
+	if !ok {
 		return
 	}
 
-	lon, err := strconv.ParseFloat(lonParam, 64)
-	if err != nil {
-		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_LONGITUDE", "lon must be numeric", nil)
+	days, err := strconv.Atoi(daysParam)
+	if err != nil || days < -datetime.MaxBusinessDays || days > datetime.MaxBusinessDays {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_DAYS", fmt.Sprintf("days must be an integer between -%d and %d", datetime.MaxBusinessDays, datetime.MaxBusinessDays), nil)
 		return
 	}
 
-	result, err := datetime.SunriseSunset(lat, lon, date)
+	result, err := datetime.AddBusinessDays(date, days, opts)
 	if err != nil {
-		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_DATE", err.Error(), nil)
 		return
 	}
 
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
-// apiDatetimeMoonHandler computes the current lunar phase for an optional
-// YYYY-MM-DD date via datetime.MoonPhase (synodic-month method).
-func apiDatetimeMoonHandler(w http.ResponseWriter, r *http.Request) {
-	date := chi.URLParam(r, "date")
+// apiDatetimeHolidayCalendarsHandler lists the supported holiday
+// calendars and their regions via datetime.HolidayCalendars.
+func apiDatetimeHolidayCalendarsHandler(w http.ResponseWriter, r *http.Request) {
+	calendars := datetime.HolidayCalendars()
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"calendars": calendars,
+		"count":     len(calendars),
+	})
+}
 
-	result, err := datetime.MoonPhase(date)
+// apiDatetimeHolidaysHandler lists a country's public holidays for a
+// year via datetime.Holidays. ?region= adds that region's holidays.
+func apiDatetimeHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	country := chi.URLParam(r, "country")
+	yearParam := chi.URLParam(r, "year")
+	region := r.URL.Query().Get("region")
+
+	year, err := strconv.Atoi(yearParam)
+	if err != nil || year < 1 || year > 9999 {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_YEAR", "year must be an integer between 1 and 9999", nil)
+		return
+	}
+
+	result, err := datetime.Holidays(country, region, year)
 	if err != nil {
-		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_DATE", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_CALENDAR", err.Error(), nil)
 		return
 	}
 
 	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
-// apiGenerateQRHandler renders a QR code PNG for the given data, or for a
-// Wi-Fi join payload when ssid is supplied instead of data.
-func apiGenerateQRHandler(w http.ResponseWriter, r *http.Request) {
-	handleQRRequest(w, r)
-}
-
-// validateEmailParams validates the required email field for
-// apiValidateEmailHandler.
-type validateEmailParams struct {
-	Email string `validate:"required"`
+// businessOptionsFromQuery reads the ?country=, ?region= and ?weekend=
+// parameters shared by the business-day handlers, writing a 400 and
+// returning false when they do not name a valid calendar.
+func businessOptionsFromQuery(w http.ResponseWriter, q url.Values) (datetime.BusinessOptions, bool) {
+	opts := datetime.BusinessOptions{
+		Country: q.Get("country"),
+		Region:  q.Get("region"),
+		Weekend: q.Get("weekend"),
+	}
+	if _, err := datetime.NewBusinessCalendar(opts); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_CALENDAR", err.Error(), nil)
+		return opts, false
+	}
+	return opts, true
 }
 
-// apiValidateEmailHandler validates the email address supplied in the
-// JSON body ({"email":"..."}) or as an ?email= query parameter.
-func apiValidateEmailHandler(w http.ResponseWriter, r *http.Request) {
-	email := r.URL.Query().Get("email")
-	if email == "" {
+// apiDatetimeRRuleHandler expands an RFC 5545 recurrence rule from
+// ?start= in ?timezone= (IANA, default UTC) via datetime.ExpandRRule.
+// ?exdate= takes a comma-separated list of excluded times or dates,
+// ?count= (1-1000, default 10) caps the occurrences listed, and ?from= /
+// ?to= (RFC3339) restrict them to a window.
+func apiDatetimeRRuleHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rule := q.Get("rule")
+	opts := datetime.RRuleOptions{Start: q.Get("start"), Timezone: q.Get("timezone")}
+	if v := q.Get("exdate"); v != "" {
+		opts.Exdates = strings.Split(v, ",")
+	}
+	if v := q.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > datetime.RRuleMaxOccurrences {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_COUNT", fmt.Sprintf("count must be an integer between 1 and %d", datetime.RRuleMaxOccurrences), nil)
+			return
+		}
+		opts.Count = n
+	}
+	var err error
+	if opts.From, err = parseOptionalRFC3339(q.Get("from")); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_TIMESTAMP", "from must be an RFC3339 timestamp", nil)
+		return
+	}
+	if opts.Until, err = parseOptionalRFC3339(q.Get("to")); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_TIMESTAMP", "to must be an RFC3339 timestamp", nil)
+		return
+	}
+
+	result, err := datetime.ExpandRRule(rule, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_RRULE", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, result)
+}
+
+// apiDatetimeICSHandler writes an iCalendar file for the events and
+// to-dos in the JSON request body via datetime.GenerateICS.
+func apiDatetimeICSHandler(w http.ResponseWriter, r *http.Request) {
+	var cal datetime.ICSCalendar
+	if err := decodeJSONBody(r, &cal); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", "request body must be JSON with events and/or todos", nil)
+		return
+	}
+
+	ics, err := datetime.GenerateICS(cal)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "ICS_GENERATION_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"ics":    ics,
+		"events": len(cal.Events),
+		"todos":  len(cal.Todos),
+	})
+}
+
+// datetimeICSParseParams validates the trimmed request body for
+// apiDatetimeICSParseHandler.
+type datetimeICSParseParams struct {
+	Body string `validate:"required"`
+}
+
+// apiDatetimeICSParseHandler reads the iCalendar text in the request
+// body via datetime.ParseICS, listing up to ?count= (1-100, default 5)
+// occurrences of each recurring event or to-do.
+func apiDatetimeICSParseHandler(w http.ResponseWriter, r *http.Request) {
+	count := 0
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > datetime.ICSMaxOccurrences {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_COUNT", fmt.Sprintf("count must be an integer between 1 and %d", datetime.ICSMaxOccurrences), nil)
+			return
+		}
+		count = n
+	}
+	raw, err := readRequestBody(r)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body := strings.TrimSpace(string(raw))
+	if !validateStruct(w, datetimeICSParseParams{Body: body}) {
+		return
+	}
+
+	result, err := datetime.ParseICS(body, count)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PARSE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, result)
+}
+
+// apiDatetimeCalendarConvertHandler converts ?value= from the calendar or
+// time scale named by ?from= (default gregorian) to all the others via
+// datetime.ConvertCalendar. ?timezone= (IANA, default UTC) sets the zone
+// calendar dates are read and reported in, and ?format= adds a
+// FormatDatetime rendering.
+func apiDatetimeCalendarConvertHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := datetime.CalendarOptions{From: q.Get("from"), Timezone: q.Get("timezone"), Format: q.Get("format")}
+
+	result, err := datetime.ConvertCalendar(q.Get("value"), opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_DATE", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, result)
+}
+
+// apiDatetimeSunriseHandler computes sunrise/sunset UTC times for a given
+// latitude, longitude, and optional YYYY-MM-DD date via
+// datetime.SunriseSunset (Almanac for Computers, 1990 algorithm).
+func apiDatetimeSunriseHandler(w http.ResponseWriter, r *http.Request) {
+	latParam := chi.URLParam(r, "lat")
+	lonParam := chi.URLParam(r, "lon")
+	date := chi.URLParam(r, "date")
+
+	lat, err := strconv.ParseFloat(latParam, 64)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_LATITUDE", "lat must be numeric", nil)
+		return
+	}
+
+	lon, err := strconv.ParseFloat(lonParam, 64)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_LONGITUDE", "lon must be numeric", nil)
+		return
+	}
+
+	result, err := datetime.SunriseSunset(lat, lon, date)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), nil)
+		return
+	}
+
+	addSunLocalTimes(result, lat, lon)
+	writeEnvelopeOK(w, http.StatusOK, result)
+}
+
+// addSunLocalTimes adds the coordinate's timezone and local sunrise/sunset
+// times when the offline timezone dataset resolves it. It never calls the
+// online provider, so a missing dataset just leaves the UTC-only result.
+func addSunLocalTimes(result map[string]interface{}, lat, lon float64) {
+	rise, riseOK := result["sunrise_iso8601"].(string)
+	set, setOK := result["sunset_iso8601"].(string)
+	if !riseOK || !setOK {
+		return
+	}
+	riseTime, err := time.Parse(time.RFC3339, rise)
+	if err != nil {
+		return
+	}
+	setTime, err := time.Parse(time.RFC3339, set)
+	if err != nil {
+		return
+	}
+
+	tz, ok := geo.LocalTimezone(lat, lon, riseTime)
+	if !ok {
+		return
+	}
+	loc, err := time.LoadLocation(tz.Timezone)
+	if err != nil {
+		return
+	}
+	result["timezone"] = tz.Timezone
+	result["sunrise_local"] = riseTime.In(loc).Format(time.RFC3339)
+	result["sunset_local"] = setTime.In(loc).Format(time.RFC3339)
+}
+
+// apiDatetimeMoonHandler computes the current lunar phase for an optional
+// YYYY-MM-DD date via datetime.MoonPhase (synodic-month method).
+func apiDatetimeMoonHandler(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	result, err := datetime.MoonPhase(date)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_DATE", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, result)
+}
+
+// apiGenerateQRHandler renders a QR code PNG for the given data, or for a
+// Wi-Fi join payload when ssid is supplied instead of data.
+func apiGenerateQRHandler(w http.ResponseWriter, r *http.Request) {
+	handleQRRequest(w, r)
+}
+
+// validateEmailParams validates the required email field for
+// apiValidateEmailHandler.
+type validateEmailParams struct {
+	Email string `validate:"required"`
+}
+
+// apiValidateEmailHandler validates the email address supplied in the
+// JSON body ({"email":"..."}) or as an ?email= query parameter.
+func apiValidateEmailHandler(w http.ResponseWriter, r *http.Request) {
+	email := r.URL.Query().Get("email")
+	if email == "" {
 		var body struct {
 			Email string `json:"email"`
 		}
@@ -2320,6 +3030,47 @@ func apiValidateVATHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// validatePasswordRequest is the JSON body of apiValidatePasswordHandler.
+type validatePasswordRequest struct {
+	Password    string   `json:"password"`
+	UserInputs  []string `json:"user_inputs"`
+	MinStrength string   `json:"min_strength"`
+}
+
+// validatePasswordParams validates apiValidatePasswordHandler's body.
+type validatePasswordParams struct {
+	Password    string `validate:"required"`
+	MinStrength string `validate:"omitempty,oneof=weak medium strong"`
+}
+
+// validatePasswordLevels orders validate.PasswordStrength's ratings.
+var validatePasswordLevels = map[string]int{"weak": 0, "medium": 1, "strong": 2}
+
+// apiValidatePasswordHandler rates the password in the JSON body weak,
+// medium or strong and reports it valid when it reaches min_strength
+// (default medium). Optional user_inputs, such as the account's name and
+// email, are words the password is checked against. The password is not
+// echoed back.
+func apiValidatePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req validatePasswordRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, validatePasswordParams{Password: req.Password, MinStrength: req.MinStrength}) {
+		return
+	}
+	if req.MinStrength == "" {
+		req.MinStrength = "medium"
+	}
+	strength := validateService.PasswordStrength(req.Password, req.UserInputs...)
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"strength":     strength,
+		"min_strength": req.MinStrength,
+		"valid":        validatePasswordLevels[strength] >= validatePasswordLevels[req.MinStrength],
+	})
+}
+
 // apiParseJSONHandler parses the raw JSON document supplied in the
 // request body into a generic map.
 func apiParseJSONHandler(w http.ResponseWriter, r *http.Request) {
@@ -2642,7 +3393,9 @@ func apiLanguageDictionaryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := languageService.Dictionary(r.Context(), word)
+	result, err := cachedLookup(r.Context(), w, "dictionary", strings.ToLower(strings.TrimSpace(word)), func(ctx context.Context) (*language.DictionaryResult, error) {
+		return languageService.Dictionary(ctx, word)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
 		return
@@ -2665,7 +3418,9 @@ func apiLanguageThesaurusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := languageService.Thesaurus(r.Context(), word)
+	result, err := cachedLookup(r.Context(), w, "thesaurus", strings.ToLower(strings.TrimSpace(word)), func(ctx context.Context) (*language.ThesaurusResult, error) {
+		return languageService.Thesaurus(ctx, word)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
 		return
@@ -3463,7 +4218,8 @@ type osintCertParams struct {
 
 // apiOsintCertHandler connects to the {domain} path parameter (host:443 by
 // default, or host:port if a port is present) and reports the peer TLS
-// certificate's details via osint.SSLInfo.
+// certificate's details via osint.SSLInfo. Its chain field can be posted
+// to /crypto/certificate/verify as-is.
 func apiOsintCertHandler(w http.ResponseWriter, r *http.Request) {
 	domain := chi.URLParam(r, "domain")
 	if !validateStruct(w, osintCertParams{Domain: domain}) {
@@ -3676,7 +4432,9 @@ func apiResearchArxivHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := researchService.ArxivLookup(r.Context(), id)
+	result, err := cachedLookup(r.Context(), w, "arxiv", strings.TrimSpace(id), func(ctx context.Context) (*research.ArxivResult, error) {
+		return researchService.ArxivLookup(ctx, id)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
 		return
@@ -3713,7 +4471,9 @@ func apiResearchIsbnHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := researchService.ISBNLookup(r.Context(), isbn)
+	result, err := cachedLookup(r.Context(), w, "isbn", strings.TrimSpace(isbn), func(ctx context.Context) (*research.ISBNResult, error) {
+		return researchService.ISBNLookup(ctx, isbn)
+	})
 	if err != nil {
 		writeEnvelopeError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
 		return
@@ -3916,7 +4676,7 @@ func apiFunMemeHandler(w http.ResponseWriter, r *http.Request) {
 func apiLoremPersonHandler(w http.ResponseWriter, r *http.Request) {
 	person, err := loremService.Person()
 	if err != nil {
-		writeEnvelopeError(w, http.StatusInternalServerError, "GENERATION_FAILED", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusInternalServerError, "MNEMONIC_GENERATION_FAILED", err.Error(), nil)
 		return
 	}
 	writeEnvelopeOK(w, http.StatusOK, person)
@@ -3926,7 +4686,7 @@ func apiLoremPersonHandler(w http.ResponseWriter, r *http.Request) {
 func apiLoremAddressHandler(w http.ResponseWriter, r *http.Request) {
 	address, err := loremService.Address()
 	if err != nil {
-		writeEnvelopeError(w, http.StatusInternalServerError, "GENERATION_FAILED", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusInternalServerError, "MNEMONIC_GENERATION_FAILED", err.Error(), nil)
 		return
 	}
 	writeEnvelopeOK(w, http.StatusOK, address)
@@ -3936,7 +4696,7 @@ func apiLoremAddressHandler(w http.ResponseWriter, r *http.Request) {
 func apiLoremCompanyHandler(w http.ResponseWriter, r *http.Request) {
 	company, err := loremService.Company()
 	if err != nil {
-		writeEnvelopeError(w, http.StatusInternalServerError, "GENERATION_FAILED", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusInternalServerError, "MNEMONIC_GENERATION_FAILED", err.Error(), nil)
 		return
 	}
 	writeEnvelopeOK(w, http.StatusOK, company)
@@ -4043,11 +4803,16 @@ func apiDevFormatJSONHandler(w http.ResponseWriter, r *http.Request) {
 	writeEnvelopeOK(w, http.StatusOK, map[string]string{"formatted": formatted})
 }
 
-// apiDevCronHandler parses a 5-field cron expression, reusing the same
-// datetime.ParseCron helper as apiDatetimeCronHandler.
+// apiDevCronHandler parses a cron expression, reusing the same
+// datetime.ParseCron helper and query parameters as
+// apiDatetimeCronHandler.
 func apiDevCronHandler(w http.ResponseWriter, r *http.Request) {
 	expression := r.URL.Query().Get("expression")
-	result, err := datetime.ParseCron(expression)
+	opts, ok := cronOptionsFromQuery(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	result, err := datetime.ParseCron(expression, opts)
 	if err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_CRON", err.Error(), nil)
 		return
@@ -4803,96 +5568,1906 @@ func apiCryptoJWTDecodeHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// apiNetworkDNSHandler queries DNS records for a domain via the system
-// resolver, composing the existing free/keyless osint.DNSLookup function.
-// Defaults to an A-record lookup when no record type is given.
-func apiNetworkDNSHandler(w http.ResponseWriter, r *http.Request) {
-	domain := chi.URLParam(r, "domain")
-	recordType := chi.URLParam(r, "type")
-	if recordType == "" {
-		recordType = "A"
+// cryptoJWTSignRequest is the JSON body shape accepted by
+// apiCryptoJWTSignHandler.
+type cryptoJWTSignRequest struct {
+	Algorithm string                 `json:"algorithm"`
+	Key       string                 `json:"key"`
+	Claims    map[string]interface{} `json:"claims"`
+	Header    map[string]interface{} `json:"header"`
+	ExpiresIn int64                  `json:"expires_in"`
+}
+
+// cryptoJWTSignParams validates the fields accepted by
+// apiCryptoJWTSignHandler.
+type cryptoJWTSignParams struct {
+	Algorithm string `validate:"required,oneof=HS256 HS384 HS512 RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512 EdDSA"`
+	ExpiresIn int64  `validate:"gte=0"`
+}
+
+// apiCryptoJWTSignHandler signs Claims as a JWT, composing crypto.JWTSign.
+// Key is the shared secret for HS* or a PEM/JWK private key otherwise;
+// when it is omitted a fresh secret or keypair is generated with the
+// crypto package's generators and returned alongside the token. A
+// positive ExpiresIn (seconds) sets exp, and iat is filled in when absent.
+func apiCryptoJWTSignHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoJWTSignRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoJWTSignParams{Algorithm: body.Algorithm, ExpiresIn: body.ExpiresIn}) {
+		return
 	}
 
-	records, err := osintService.DNSLookup(domain, recordType)
+	var generated map[string]interface{}
+	if body.Key == "" {
+		private, public, err := crypto.JWTGenerateKey(body.Algorithm)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusInternalServerError, "KEY_GENERATION_FAILED", err.Error(), nil)
+			return
+		}
+		body.Key = private
+		if public == "" {
+			generated = map[string]interface{}{"secret": private}
+		} else {
+			generated = map[string]interface{}{"private_key": private, "public_key": public}
+		}
+	}
+
+	claims := make(map[string]interface{}, len(body.Claims)+2)
+	for k, v := range body.Claims {
+		claims[k] = v
+	}
+	now := time.Now()
+	if _, ok := claims["iat"]; !ok {
+		claims["iat"] = now.Unix()
+	}
+	if body.ExpiresIn > 0 {
+		claims["exp"] = now.Unix() + body.ExpiresIn
+	}
+
+	token, err := crypto.JWTSign(body.Algorithm, claims, body.Header, body.Key)
 	if err != nil {
-		writeEnvelopeError(w, http.StatusBadRequest, "DNS_LOOKUP_FAILED", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_KEY", err.Error(), nil)
 		return
 	}
 
-	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
-		"domain":  domain,
-		"type":    strings.ToUpper(recordType),
-		"records": records,
-	})
+	data := map[string]interface{}{
+		"algorithm": body.Algorithm,
+		"token":     token,
+		"claims":    claims,
+	}
+	if generated != nil {
+		data["generated_key"] = generated
+	}
+	writeEnvelopeOK(w, http.StatusOK, data)
 }
 
-// textCompressRequest is the JSON body shape accepted by
-// apiTextCompressHandler: the input data, the algorithm (gzip, zlib, or
-// flate/deflate), and the direction (compress or decompress).
-type textCompressRequest struct {
-	Data      string `json:"data"`
-	Algorithm string `json:"algorithm"`
-	Mode      string `json:"mode"`
+// cryptoJWTVerifyRequest is the JSON body shape accepted by
+// apiCryptoJWTVerifyHandler.
+type cryptoJWTVerifyRequest struct {
+	Token      string   `json:"token"`
+	Key        string   `json:"key"`
+	Algorithms []string `json:"algorithms"`
+	Audience   string   `json:"audience"`
+	Issuer     string   `json:"issuer"`
+	ClockSkew  int64    `json:"clock_skew"`
 }
 
-// textCompressParams validates the data/mode fields accepted by
-// apiTextCompressHandler, after Mode's default has been applied.
-type textCompressParams struct {
-	Data string `validate:"required"`
-	Mode string `validate:"oneof=compress decompress"`
+// cryptoJWTVerifyParams validates the fields accepted by
+// apiCryptoJWTVerifyHandler.
+type cryptoJWTVerifyParams struct {
+	Token     string `validate:"required"`
+	Key       string `validate:"required"`
+	ClockSkew int64  `validate:"gte=0,lte=86400"`
 }
 
-// apiTextCompressHandler compresses or decompresses data using
-// text.Compress/text.Decompress, both base64-encoded on the wire so the
-// result is always safe JSON. Mode defaults to "compress"; Algorithm
-// defaults to "gzip" (zlib and flate/deflate are also supported by the
-// underlying service — brotli/zstd are not implemented).
-func apiTextCompressHandler(w http.ResponseWriter, r *http.Request) {
-	var body textCompressRequest
+// apiCryptoJWTVerifyHandler verifies a JWT's signature with Key and checks
+// its exp/nbf/iat (allowing ClockSkew seconds of leeway), aud and iss
+// claims, composing crypto.JWTVerify. Unlike apiCryptoJWTDecodeHandler it
+// answers whether the token can be trusted: a bad signature or failed
+// claim is a 200 with valid=false and the reasons in errors, while a
+// token or key that cannot be evaluated at all is a 400.
+func apiCryptoJWTVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoJWTVerifyRequest
 	if err := decodeJSONBody(r, &body); err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
 		return
 	}
-	if body.Algorithm == "" {
-		body.Algorithm = "gzip"
-	}
-	if body.Mode == "" {
-		body.Mode = "compress"
-	}
-	if !validateStruct(w, textCompressParams{Data: body.Data, Mode: body.Mode}) {
+	if !validateStruct(w, cryptoJWTVerifyParams{Token: body.Token, Key: body.Key, ClockSkew: body.ClockSkew}) {
 		return
 	}
 
-	var result string
-	var err error
-	switch strings.ToLower(body.Mode) {
-	case "compress":
-		result, err = text.Compress(body.Data, body.Algorithm)
-	case "decompress":
-		result, err = text.Decompress(body.Data, body.Algorithm)
-	}
+	result, err := crypto.JWTVerify(body.Token, body.Key, crypto.JWTVerifyOptions{
+		Algorithms: body.Algorithms,
+		Audience:   body.Audience,
+		Issuer:     body.Issuer,
+		ClockSkew:  time.Duration(body.ClockSkew) * time.Second,
+	})
 	if err != nil {
-		writeEnvelopeError(w, http.StatusBadRequest, "COMPRESS_FAILED", err.Error(), nil)
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_JWT", err.Error(), nil)
 		return
 	}
 
-	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
-		"mode":      body.Mode,
-		"algorithm": body.Algorithm,
-		"result":    result,
-	})
+	writeEnvelopeOK(w, http.StatusOK, result)
 }
 
-// textDiffRequest is the JSON body shape accepted by apiTextDiffHandler.
-type textDiffRequest struct {
-	Text1 string `json:"text1"`
-	Text2 string `json:"text2"`
+// cryptoKeyRequest is the JSON body shape accepted by
+// apiCryptoKeyInspectHandler and apiCryptoKeyConvertHandler.
+type cryptoKeyRequest struct {
+	Key        string `json:"key"`
+	Format     string `json:"format"`
+	PublicOnly bool   `json:"public_only"`
 }
 
-// apiTextDiffHandler returns a unified line diff between two texts using
-// text.Diff.
-func apiTextDiffHandler(w http.ResponseWriter, r *http.Request) {
-	var body textDiffRequest
+// cryptoKeyInspectParams validates the key field accepted by
+// apiCryptoKeyInspectHandler.
+type cryptoKeyInspectParams struct {
+	Key string `validate:"required"`
+}
+
+// apiCryptoKeyInspectHandler reports the type, size, curve and
+// fingerprints (RFC 7638 JWK thumbprint, OpenSSH SHA256/MD5 and SPKI
+// hashes) of a key in any format crypto.ParseKey accepts, composing
+// crypto.InspectKey.
+func apiCryptoKeyInspectHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoKeyRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoKeyInspectParams{Key: body.Key}) {
+		return
+	}
+
+	info, err := crypto.InspectKey(body.Key)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_KEY", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, info)
+}
+
+// cryptoKeyConvertParams validates the fields accepted by
+// apiCryptoKeyConvertHandler.
+type cryptoKeyConvertParams struct {
+	Key    string `validate:"required"`
+	Format string `validate:"required,oneof=pem pkcs8 pkcs1 sec1 spki der jwk openssh"`
+}
+
+// apiCryptoKeyConvertHandler re-encodes a key from any format
+// crypto.ParseKey accepts (PEM, base64 DER, JWK or OpenSSH) into Format,
+// composing crypto.ConvertKey. PublicOnly outputs just the public half of
+// a private key. The converted key's InspectKey details come back too.
+func apiCryptoKeyConvertHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoKeyRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.Format = strings.ToLower(body.Format)
+	if !validateStruct(w, cryptoKeyConvertParams{Key: body.Key, Format: body.Format}) {
+		return
+	}
+
+	converted, err := crypto.ConvertKey(body.Key, body.Format, body.PublicOnly)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "CONVERT_FAILED", err.Error(), nil)
+		return
+	}
+	info, err := crypto.InspectKey(converted)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusInternalServerError, "CONVERT_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"format": body.Format,
+		"key":    converted,
+		"info":   info,
+	})
+}
+
+// cryptoJWKSRequest is the JSON body shape accepted by
+// apiCryptoJWKSHandler.
+type cryptoJWKSRequest struct {
+	Keys []string `json:"keys"`
+	Use  string   `json:"use"`
+}
+
+// cryptoJWKSParams validates the fields accepted by apiCryptoJWKSHandler.
+type cryptoJWKSParams struct {
+	Keys []string `validate:"required,min=1,max=100,dive,required"`
+	Use  string   `validate:"oneof=sig enc"`
+}
+
+// apiCryptoJWKSHandler builds a JSON Web Key Set from the public halves of
+// Keys, composing crypto.BuildJWKS. Each entry's kid is its RFC 7638
+// thumbprint and Use (default sig) is set on every entry. The data is the
+// JWKS document itself, ready to publish as a jwks_uri.
+func apiCryptoJWKSHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoJWKSRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if body.Use == "" {
+		body.Use = "sig"
+	}
+	if !validateStruct(w, cryptoJWKSParams{Keys: body.Keys, Use: body.Use}) {
+		return
+	}
+
+	jwks, err := crypto.BuildJWKS(body.Keys, body.Use)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_KEY", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, jwks)
+}
+
+// cryptoPasswordHashRequest is the JSON body shape accepted by
+// apiCryptoPasswordHashHandler.
+type cryptoPasswordHashRequest struct {
+	Algorithm string                    `json:"algorithm"`
+	Password  string                    `json:"password"`
+	Params    crypto.PasswordHashParams `json:"params"`
+}
+
+// cryptoPasswordHashParams validates the fields accepted by
+// apiCryptoPasswordHashHandler; the work-factor bounds are enforced by
+// crypto.PasswordHash.
+type cryptoPasswordHashParams struct {
+	Algorithm string `validate:"required,oneof=argon2id argon2i scrypt pbkdf2-sha256 pbkdf2-sha512 sha256-crypt sha512-crypt apr1 bcrypt"`
+	Password  string `validate:"required,max=1024"`
+}
+
+// apiCryptoPasswordHashHandler hashes Password with Algorithm, composing
+// crypto.PasswordHash. Params tunes the work factors, salt and key length;
+// omitted fields take the algorithm's defaults (Argon2 m=65536,t=3,p=4;
+// scrypt ln=15,r=8,p=1; PBKDF2 600000/210000 iterations; sha-crypt 5000
+// rounds; bcrypt cost 12). Parameters over the server's limits are a 400.
+func apiCryptoPasswordHashHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoPasswordHashRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if body.Algorithm == "" {
+		body.Algorithm = "argon2id"
+	}
+	body.Algorithm = strings.ToLower(body.Algorithm)
+	if !validateStruct(w, cryptoPasswordHashParams{Algorithm: body.Algorithm, Password: body.Password}) {
+		return
+	}
+
+	hash, err := crypto.PasswordHash(body.Algorithm, body.Password, body.Params)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_PARAMS", err.Error(), nil)
+		return
+	}
+	info, err := crypto.IdentifyPasswordHash(hash)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusInternalServerError, "HASH_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"algorithm": info.Algorithm,
+		"params":    info.Params,
+		"hash":      hash,
+	})
+}
+
+// cryptoPasswordVerifyRequest is the JSON body shape accepted by
+// apiCryptoPasswordVerifyHandler.
+type cryptoPasswordVerifyRequest struct {
+	Password string `json:"password"`
+	Hash     string `json:"hash"`
+}
+
+// cryptoPasswordVerifyParams validates the fields accepted by
+// apiCryptoPasswordVerifyHandler.
+type cryptoPasswordVerifyParams struct {
+	Password string `validate:"max=1024"`
+	Hash     string `validate:"required"`
+}
+
+// apiCryptoPasswordVerifyHandler checks Password against Hash, detecting
+// the algorithm and parameters from the hash string, composing
+// crypto.PasswordVerify. Besides every algorithm the hash endpoint
+// produces, $1$ md5-crypt and Django pbkdf2_sha256 hashes are accepted for
+// migrations. An unrecognised hash, or one whose work factors exceed the
+// server's limits, is a 400; a wrong password is valid=false.
+func apiCryptoPasswordVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoPasswordVerifyRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoPasswordVerifyParams{Password: body.Password, Hash: body.Hash}) {
+		return
+	}
+
+	info, valid, err := crypto.PasswordVerify(body.Password, body.Hash)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_HASH", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"valid":     valid,
+		"algorithm": info.Algorithm,
+		"params":    info.Params,
+	})
+}
+
+// cryptoCertificateDecodeRequest is the JSON body shape accepted by
+// apiCryptoCertificateDecodeHandler.
+type cryptoCertificateDecodeRequest struct {
+	Certificate string `json:"certificate"`
+}
+
+// cryptoCertificateDecodeParams validates the fields accepted by
+// apiCryptoCertificateDecodeHandler.
+type cryptoCertificateDecodeParams struct {
+	Certificate string `validate:"required"`
+}
+
+// apiCryptoCertificateDecodeHandler decodes every certificate in a PEM
+// bundle, composing crypto.DecodeCertificates. Unlike mode=parse on
+// /crypto/certificate it accepts whole chains, as servers send them.
+func apiCryptoCertificateDecodeHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCertificateDecodeRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoCertificateDecodeParams{Certificate: body.Certificate}) {
+		return
+	}
+
+	certs, err := crypto.DecodeCertificates(body.Certificate)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PARSE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"count":        len(certs),
+		"certificates": certs,
+	})
+}
+
+// cryptoCSRRequest is the JSON body shape accepted by apiCryptoCSRHandler.
+type cryptoCSRRequest struct {
+	Subject        crypto.CertificateSubject `json:"subject"`
+	DNSNames       []string                  `json:"dns_names"`
+	IPAddresses    []string                  `json:"ip_addresses"`
+	EmailAddresses []string                  `json:"email_addresses"`
+	URIs           []string                  `json:"uris"`
+	KeyUsage       []string                  `json:"key_usage"`
+	ExtKeyUsage    []string                  `json:"ext_key_usage"`
+	KeyType        string                    `json:"key_type"`
+	Key            string                    `json:"key"`
+}
+
+// cryptoCSRParams validates the fields accepted by apiCryptoCSRHandler.
+// Key usage names are checked by crypto.GenerateCSR, which also accepts
+// dotted OIDs for extended usages.
+type cryptoCSRParams struct {
+	CommonName     string   `validate:"max=64"`
+	DNSNames       []string `validate:"max=100,dive,required,max=253"`
+	IPAddresses    []string `validate:"max=100,dive,ip"`
+	EmailAddresses []string `validate:"max=100,dive,email"`
+	URIs           []string `validate:"max=100,dive,uri"`
+	KeyType        string   `validate:"omitempty,oneof=ecdsa-p256 ecdsa-p384 rsa-2048 rsa-3072 rsa-4096 ed25519"`
+}
+
+// apiCryptoCSRHandler generates a PEM certificate signing request with
+// subject alternative names and requested key usages, composing
+// crypto.GenerateCSR. A new key of KeyType (default ecdsa-p256) is created
+// and returned as private_key unless an existing Key is supplied. The
+// decoded request is returned as info so callers can check it before
+// submitting it to a CA.
+func apiCryptoCSRHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCSRRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoCSRParams{
+		CommonName:     body.Subject.CommonName,
+		DNSNames:       body.DNSNames,
+		IPAddresses:    body.IPAddresses,
+		EmailAddresses: body.EmailAddresses,
+		URIs:           body.URIs,
+		KeyType:        body.KeyType,
+	}) {
+		return
+	}
+
+	csrPEM, keyPEM, err := crypto.GenerateCSR(crypto.CSROptions{
+		Subject:        body.Subject,
+		DNSNames:       body.DNSNames,
+		IPAddresses:    body.IPAddresses,
+		EmailAddresses: body.EmailAddresses,
+		URIs:           body.URIs,
+		KeyUsage:       body.KeyUsage,
+		ExtKeyUsage:    body.ExtKeyUsage,
+		Key:            body.Key,
+		KeyType:        body.KeyType,
+	})
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "CSR_FAILED", err.Error(), nil)
+		return
+	}
+	info, err := crypto.ParseCSR(csrPEM)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusInternalServerError, "CSR_FAILED", err.Error(), nil)
+		return
+	}
+
+	data := map[string]interface{}{
+		"csr":  csrPEM,
+		"info": info,
+	}
+	if keyPEM != "" {
+		data["private_key"] = keyPEM
+	}
+	writeEnvelopeOK(w, http.StatusOK, data)
+}
+
+// cryptoCSRDecodeRequest is the JSON body shape accepted by
+// apiCryptoCSRDecodeHandler.
+type cryptoCSRDecodeRequest struct {
+	CSR string `json:"csr"`
+}
+
+// cryptoCSRDecodeParams validates the fields accepted by
+// apiCryptoCSRDecodeHandler.
+type cryptoCSRDecodeParams struct {
+	CSR string `validate:"required"`
+}
+
+// apiCryptoCSRDecodeHandler decodes a PEM certificate signing request and
+// checks its self-signature, composing crypto.ParseCSR.
+func apiCryptoCSRDecodeHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCSRDecodeRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoCSRDecodeParams{CSR: body.CSR}) {
+		return
+	}
+
+	info, err := crypto.ParseCSR(body.CSR)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PARSE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, info)
+}
+
+// cryptoCertificateSignRequest is the JSON body shape accepted by
+// apiCryptoCertificateSignHandler.
+type cryptoCertificateSignRequest struct {
+	CSR           string   `json:"csr"`
+	CACertificate string   `json:"ca_certificate"`
+	CAKey         string   `json:"ca_key"`
+	ValidDays     int      `json:"valid_days"`
+	IsCA          bool     `json:"is_ca"`
+	PathLen       *int     `json:"path_len"`
+	KeyUsage      []string `json:"key_usage"`
+	ExtKeyUsage   []string `json:"ext_key_usage"`
+}
+
+// cryptoCertificateSignParams validates the fields accepted by
+// apiCryptoCertificateSignHandler.
+type cryptoCertificateSignParams struct {
+	CSR           string `validate:"required"`
+	CACertificate string `validate:"required"`
+	CAKey         string `validate:"required"`
+	ValidDays     int    `validate:"min=0,max=3650"`
+	PathLen       *int   `validate:"omitempty,min=0,max=16"`
+}
+
+// apiCryptoCertificateSignHandler issues a certificate for a CSR signed by
+// a caller-supplied CA certificate and key, composing crypto.SignCSR. The
+// subject and SANs come from the CSR; key usages come from the request
+// body, else from the CSR, else from leaf or CA defaults. The CA key is
+// used only for this signature and is never stored.
+func apiCryptoCertificateSignHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCertificateSignRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoCertificateSignParams{
+		CSR:           body.CSR,
+		CACertificate: body.CACertificate,
+		CAKey:         body.CAKey,
+		ValidDays:     body.ValidDays,
+		PathLen:       body.PathLen,
+	}) {
+		return
+	}
+
+	certPEM, err := crypto.SignCSR(body.CSR, body.CACertificate, body.CAKey, crypto.SignCSROptions{
+		ValidDays:   body.ValidDays,
+		IsCA:        body.IsCA,
+		PathLen:     body.PathLen,
+		KeyUsage:    body.KeyUsage,
+		ExtKeyUsage: body.ExtKeyUsage,
+	})
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "SIGN_FAILED", err.Error(), nil)
+		return
+	}
+	info, err := crypto.ParseCertificate(certPEM)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusInternalServerError, "SIGN_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"certificate": certPEM,
+		"info":        info,
+	})
+}
+
+// cryptoCertificateVerifyRequest is the JSON body shape accepted by
+// apiCryptoCertificateVerifyHandler.
+type cryptoCertificateVerifyRequest struct {
+	Chain       []string `json:"chain"`
+	Roots       string   `json:"roots"`
+	DNSName     string   `json:"dns_name"`
+	At          string   `json:"at"`
+	ExtKeyUsage []string `json:"ext_key_usage"`
+}
+
+// cryptoCertificateVerifyParams validates the fields accepted by
+// apiCryptoCertificateVerifyHandler.
+type cryptoCertificateVerifyParams struct {
+	Chain []string `validate:"required,min=1,max=20,dive,required"`
+	At    string   `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// apiCryptoCertificateVerifyHandler builds and validates the chain from
+// the leaf (the first certificate of Chain) through any intermediates to
+// Roots, composing crypto.VerifyCertificateChain. Without Roots the
+// system trust store is used. The chain field of GET /osint/cert/{domain}
+// can be posted as Chain unchanged. A chain that fails validation is a
+// 200 with valid=false and the reason in error.
+func apiCryptoCertificateVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCertificateVerifyRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoCertificateVerifyParams{Chain: body.Chain, At: body.At}) {
+		return
+	}
+
+	opts := crypto.ChainOptions{Roots: body.Roots, DNSName: body.DNSName, ExtKeyUsage: body.ExtKeyUsage}
+	if body.At != "" {
+		opts.At, _ = time.Parse(time.RFC3339, body.At)
+	}
+	result, err := crypto.VerifyCertificateChain(body.Chain, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PARSE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, result)
+}
+
+// cryptoPKCS12Request is the JSON body shape accepted by
+// apiCryptoPKCS12Handler.
+type cryptoPKCS12Request struct {
+	PrivateKey  string `json:"private_key"`
+	Certificate string `json:"certificate"`
+	Password    string `json:"password"`
+	Legacy      bool   `json:"legacy"`
+}
+
+// cryptoPKCS12Params validates the fields accepted by
+// apiCryptoPKCS12Handler.
+type cryptoPKCS12Params struct {
+	PrivateKey  string `validate:"required"`
+	Certificate string `validate:"required"`
+	Password    string `validate:"max=1024"`
+}
+
+// apiCryptoPKCS12Handler bundles a private key, its certificate and any
+// CA certificates following it in Certificate into a base64 PKCS#12
+// (.p12/.pfx) file, composing crypto.PKCS12Encode. Legacy selects 3DES
+// encryption for consumers that cannot read AES-encrypted bundles.
+func apiCryptoPKCS12Handler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoPKCS12Request
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoPKCS12Params{
+		PrivateKey:  body.PrivateKey,
+		Certificate: body.Certificate,
+		Password:    body.Password,
+	}) {
+		return
+	}
+
+	pfx, err := crypto.PKCS12Encode(body.PrivateKey, body.Certificate, body.Password, body.Legacy)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PKCS12_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"pkcs12": pfx,
+		"legacy": body.Legacy,
+	})
+}
+
+// cryptoPKCS12DecodeRequest is the JSON body shape accepted by
+// apiCryptoPKCS12DecodeHandler.
+type cryptoPKCS12DecodeRequest struct {
+	PKCS12   string `json:"pkcs12"`
+	Password string `json:"password"`
+}
+
+// cryptoPKCS12DecodeParams validates the fields accepted by
+// apiCryptoPKCS12DecodeHandler.
+type cryptoPKCS12DecodeParams struct {
+	PKCS12   string `validate:"required,base64"`
+	Password string `validate:"max=1024"`
+}
+
+// apiCryptoPKCS12DecodeHandler opens a base64 PKCS#12 bundle, composing
+// crypto.PKCS12Decode, and returns its key as PKCS#8 PEM and its
+// certificates as PEM. Trust stores without a key return only
+// ca_certificates.
+func apiCryptoPKCS12DecodeHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoPKCS12DecodeRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoPKCS12DecodeParams{PKCS12: body.PKCS12, Password: body.Password}) {
+		return
+	}
+
+	contents, err := crypto.PKCS12Decode(body.PKCS12, body.Password)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PKCS12_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, contents)
+}
+
+// cryptoCRLDecodeRequest is the JSON body shape accepted by
+// apiCryptoCRLDecodeHandler.
+type cryptoCRLDecodeRequest struct {
+	CRL    string `json:"crl"`
+	Issuer string `json:"issuer"`
+}
+
+// cryptoCRLDecodeParams validates the fields accepted by
+// apiCryptoCRLDecodeHandler.
+type cryptoCRLDecodeParams struct {
+	CRL string `validate:"required"`
+}
+
+// apiCryptoCRLDecodeHandler decodes a PEM or base64 DER certificate
+// revocation list, composing crypto.ParseCRL. When the issuer certificate
+// is supplied the CRL's signature is checked and reported as
+// signature_valid.
+func apiCryptoCRLDecodeHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCRLDecodeRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoCRLDecodeParams{CRL: body.CRL}) {
+		return
+	}
+
+	info, err := crypto.ParseCRL(body.CRL, body.Issuer)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "PARSE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, info)
+}
+
+// cryptoMnemonicRequest is the JSON body shape accepted by the
+// /crypto/mnemonic handlers. Each handler reads the fields it needs.
+type cryptoMnemonicRequest struct {
+	Words       int    `json:"words"`
+	Language    string `json:"language"`
+	Entropy     string `json:"entropy"`
+	Mnemonic    string `json:"mnemonic"`
+	Passphrase  string `json:"passphrase"`
+	Seed        string `json:"seed"`
+	ExtendedKey string `json:"extended_key"`
+	Network     string `json:"network"`
+	Path        string `json:"path"`
+}
+
+// cryptoMnemonicGenerateParams validates the fields accepted by
+// apiCryptoMnemonicGenerateHandler, after Words' default has been
+// applied.
+type cryptoMnemonicGenerateParams struct {
+	Words    int    `validate:"oneof=12 15 18 21 24"`
+	Language string `validate:"omitempty,oneof=english japanese korean spanish chinese_simplified chinese_traditional french italian czech"`
+	Entropy  string `validate:"omitempty,hexadecimal"`
+}
+
+// apiCryptoMnemonicGenerateHandler creates a BIP39 mnemonic of Words
+// words (default 12) from Language's wordlist (default english), composing
+// crypto.GenerateMnemonic. Entropy (hex, 16-32 bytes) encodes chosen
+// entropy instead of random bytes, and then decides the word count.
+func apiCryptoMnemonicGenerateHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoMnemonicRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if body.Words == 0 {
+		body.Words = 12
+	}
+	body.Language = strings.ToLower(body.Language)
+	if !validateStruct(w, cryptoMnemonicGenerateParams{Words: body.Words, Language: body.Language, Entropy: body.Entropy}) {
+		return
+	}
+
+	var mnemonic string
+	if body.Entropy != "" {
+		entropy, err := hex.DecodeString(body.Entropy)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "entropy must be hex: "+err.Error(), nil)
+			return
+		}
+		if mnemonic, err = crypto.MnemonicFromEntropy(entropy, body.Language); err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+			return
+		}
+	} else {
+		var err error
+		if mnemonic, err = crypto.GenerateMnemonic(body.Words, body.Language); err != nil {
+			writeEnvelopeError(w, http.StatusInternalServerError, "MNEMONIC_GENERATION_FAILED", err.Error(), nil)
+			return
+		}
+	}
+
+	info := crypto.ValidateMnemonic(mnemonic, body.Language)
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"mnemonic": mnemonic,
+		"words":    info.Words,
+		"language": info.Language,
+		"entropy":  info.Entropy,
+	})
+}
+
+// cryptoMnemonicValidateParams validates the fields accepted by
+// apiCryptoMnemonicValidateHandler and apiCryptoMnemonicSeedHandler.
+type cryptoMnemonicValidateParams struct {
+	Mnemonic string `validate:"required,max=1024"`
+	Language string `validate:"omitempty,oneof=english japanese korean spanish chinese_simplified chinese_traditional french italian czech"`
+}
+
+// apiCryptoMnemonicValidateHandler checks a BIP39 mnemonic's words and
+// checksum, composing crypto.ValidateMnemonic. Without a language the
+// wordlist is detected. An invalid mnemonic is a 200 with valid=false and
+// the reason in error.
+func apiCryptoMnemonicValidateHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoMnemonicRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.Language = strings.ToLower(body.Language)
+	if !validateStruct(w, cryptoMnemonicValidateParams{Mnemonic: body.Mnemonic, Language: body.Language}) {
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, crypto.ValidateMnemonic(body.Mnemonic, body.Language))
+}
+
+// apiCryptoMnemonicSeedHandler derives the 64-byte BIP39 seed for a
+// mnemonic and optional passphrase, composing crypto.MnemonicToSeed, and
+// returns it in hex with the BIP32 master key it produces.
+func apiCryptoMnemonicSeedHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoMnemonicRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.Language = strings.ToLower(body.Language)
+	if !validateStruct(w, cryptoMnemonicValidateParams{Mnemonic: body.Mnemonic, Language: body.Language}) {
+		return
+	}
+
+	seed, err := crypto.MnemonicToSeed(body.Mnemonic, body.Passphrase, body.Language)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_MNEMONIC", err.Error(), nil)
+		return
+	}
+	master, err := crypto.NewMasterKey(seed, "")
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "DERIVE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"seed":                 hex.EncodeToString(seed),
+		"extended_private_key": master.String(),
+		"extended_public_key":  master.Neuter().String(),
+	})
+}
+
+// cryptoMnemonicDeriveParams validates the fields accepted by
+// apiCryptoMnemonicDeriveHandler: exactly one of Mnemonic, Seed and
+// ExtendedKey is the starting key.
+type cryptoMnemonicDeriveParams struct {
+	Mnemonic    string `validate:"required_without_all=Seed ExtendedKey,max=1024"`
+	Seed        string `validate:"excluded_with=Mnemonic,omitempty,hexadecimal"`
+	ExtendedKey string `validate:"excluded_with=Mnemonic Seed"`
+	Language    string `validate:"omitempty,oneof=english japanese korean spanish chinese_simplified chinese_traditional french italian czech"`
+	Network     string `validate:"omitempty,oneof=mainnet testnet"`
+	Path        string `validate:"required,max=2048"`
+}
+
+// apiCryptoMnemonicDeriveHandler derives the BIP32 key at Path, composing
+// crypto.DeriveKeyInfo. The root is a mnemonic (plus optional
+// passphrase), a hex seed, or an existing xprv/xpub/tprv/tpub whose own
+// position is "m" in Path. Network picks xprv/xpub or tprv/tpub
+// serialization for mnemonic and seed roots; an extended key keeps its
+// own. Extended public keys can only derive non-hardened children.
+func apiCryptoMnemonicDeriveHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoMnemonicRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.Language = strings.ToLower(body.Language)
+	body.Network = strings.ToLower(body.Network)
+	if !validateStruct(w, cryptoMnemonicDeriveParams{
+		Mnemonic:    body.Mnemonic,
+		Seed:        body.Seed,
+		ExtendedKey: body.ExtendedKey,
+		Language:    body.Language,
+		Network:     body.Network,
+		Path:        body.Path,
+	}) {
+		return
+	}
+
+	var root *crypto.ExtendedKey
+	switch {
+	case body.ExtendedKey != "":
+		key, err := crypto.ParseExtendedKey(body.ExtendedKey)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_KEY", err.Error(), nil)
+			return
+		}
+		root = key
+	default:
+		var seed []byte
+		if body.Mnemonic != "" {
+			var err error
+			if seed, err = crypto.MnemonicToSeed(body.Mnemonic, body.Passphrase, body.Language); err != nil {
+				writeEnvelopeError(w, http.StatusBadRequest, "INVALID_MNEMONIC", err.Error(), nil)
+				return
+			}
+		} else {
+			var err error
+			if seed, err = hex.DecodeString(body.Seed); err != nil {
+				writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "seed must be hex: "+err.Error(), nil)
+				return
+			}
+		}
+		key, err := crypto.NewMasterKey(seed, body.Network)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "DERIVE_FAILED", err.Error(), nil)
+			return
+		}
+		root = key
+	}
+
+	info, err := crypto.DeriveKeyInfo(root, body.Path)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "DERIVE_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, info)
+}
+
+// cryptoOTPRequest is the JSON body shape accepted by the HOTP, TOTP and
+// otpauth handlers. Each handler reads the fields it needs.
+type cryptoOTPRequest struct {
+	Secret    string `json:"secret"`
+	Code      string `json:"code"`
+	Counter   uint64 `json:"counter"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int64  `json:"period"`
+	Timestamp int64  `json:"timestamp"`
+	Window    *int   `json:"window"`
+	LookAhead int    `json:"look_ahead"`
+	URI       string `json:"uri"`
+	Type      string `json:"type"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Size      int    `json:"size"`
+}
+
+// options normalizes the algorithm spelling ("sha-256" -> "SHA256") and
+// returns the OTP parameters; crypto.OTPOptions applies the defaults.
+func (b *cryptoOTPRequest) options() crypto.OTPOptions {
+	b.Algorithm = strings.ToUpper(strings.ReplaceAll(b.Algorithm, "-", ""))
+	return crypto.OTPOptions{Algorithm: b.Algorithm, Digits: b.Digits, Period: b.Period}
+}
+
+// at returns the request's Timestamp, or now when none was given.
+func (b *cryptoOTPRequest) at() time.Time {
+	if b.Timestamp != 0 {
+		return time.Unix(b.Timestamp, 0)
+	}
+	return time.Now()
+}
+
+// cryptoOTPParams validates the fields shared by the HOTP and TOTP
+// handlers. Mode is "generate" or "verify"; Code is only required to
+// verify.
+type cryptoOTPParams struct {
+	Mode      string
+	Secret    string `validate:"required,max=256"`
+	Code      string `validate:"required_if=Mode verify,omitempty,numeric,max=8"`
+	Algorithm string `validate:"omitempty,oneof=SHA1 SHA256 SHA512"`
+	Digits    int    `validate:"omitempty,min=6,max=8"`
+	Period    int64  `validate:"omitempty,min=1,max=3600"`
+	Window    int    `validate:"min=0,max=10"`
+	LookAhead int    `validate:"min=0,max=100"`
+}
+
+// otpParams builds the shared validation struct for body.
+func otpParams(mode string, body cryptoOTPRequest) cryptoOTPParams {
+	window := 1
+	if body.Window != nil {
+		window = *body.Window
+	}
+	return cryptoOTPParams{
+		Mode: mode, Secret: body.Secret, Code: body.Code, Algorithm: body.Algorithm,
+		Digits: body.Digits, Period: body.Period, Window: window, LookAhead: body.LookAhead,
+	}
+}
+
+// apiCryptoHOTPGenerateHandler returns the RFC 4226 counter-based code for
+// Counter, composing crypto.GenerateHOTP. Algorithm is SHA1 (default),
+// SHA256 or SHA512 and Digits 6 (default) to 8.
+func apiCryptoHOTPGenerateHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoOTPRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	opts := body.options()
+	if !validateStruct(w, otpParams("generate", body)) {
+		return
+	}
+
+	code, err := crypto.GenerateHOTP(body.Secret, body.Counter, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_SECRET", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"code":      code,
+		"counter":   body.Counter,
+		"algorithm": opts.Algorithm,
+		"digits":    len(code),
+	})
+}
+
+// apiCryptoHOTPVerifyHandler checks an HOTP code against Counter and the
+// LookAhead counters after it (default 0, at most 100), composing
+// crypto.VerifyHOTP. On a match the response carries the matched counter
+// and next_counter, the value the caller should store for the next
+// verification.
+func apiCryptoHOTPVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoOTPRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	opts := body.options()
+	params := otpParams("verify", body)
+	if !validateStruct(w, params) {
+		return
+	}
+
+	valid, matched, err := crypto.VerifyHOTP(body.Secret, body.Code, body.Counter, body.LookAhead, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_SECRET", err.Error(), nil)
+		return
+	}
+
+	data := map[string]interface{}{"valid": valid}
+	if valid {
+		data["counter"] = matched
+		data["next_counter"] = matched + 1
+	}
+	writeEnvelopeOK(w, http.StatusOK, data)
+}
+
+// apiCryptoTOTPCodeHandler returns the RFC 6238 time-based code for
+// Timestamp (Unix seconds, default now), composing crypto.TOTPAt. Unlike
+// GET /totp/code/{secret} it accepts SHA256/SHA512, 6-8 digits and a
+// custom period.
+func apiCryptoTOTPCodeHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoOTPRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	opts := body.options()
+	if !validateStruct(w, otpParams("generate", body)) {
+		return
+	}
+
+	at := body.at()
+	code, err := crypto.TOTPAt(body.Secret, at, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_SECRET", err.Error(), nil)
+		return
+	}
+
+	period := body.Period
+	if period == 0 {
+		period = 30
+	}
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"code":              code,
+		"timestamp":         at.Unix(),
+		"remaining_seconds": period - at.Unix()%period,
+		"period":            period,
+		"algorithm":         opts.Algorithm,
+		"digits":            len(code),
+	})
+}
+
+// apiCryptoTOTPVerifyHandler checks a TOTP code against the step at
+// Timestamp (default now) and Window steps either side (default 1, at
+// most 10), composing crypto.VerifyTOTPAt.
+func apiCryptoTOTPVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoOTPRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	opts := body.options()
+	params := otpParams("verify", body)
+	if !validateStruct(w, params) {
+		return
+	}
+
+	valid, err := crypto.VerifyTOTPAt(body.Secret, body.Code, body.at(), params.Window, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_SECRET", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{"valid": valid})
+}
+
+// cryptoOTPAuthParseParams validates the field accepted by
+// apiCryptoOTPAuthParseHandler.
+type cryptoOTPAuthParseParams struct {
+	URI string `validate:"required,max=4096"`
+}
+
+// apiCryptoOTPAuthParseHandler parses an otpauth:// key URI into its
+// parameters, composing crypto.ParseOTPAuthURI, and adds the code the key
+// produces now (totp) or at its counter (hotp).
+func apiCryptoOTPAuthParseHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoOTPRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoOTPAuthParseParams{URI: body.URI}) {
+		return
+	}
+
+	key, err := crypto.ParseOTPAuthURI(body.URI)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_URI", err.Error(), nil)
+		return
+	}
+	code, err := otpAuthCode(key)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_URI", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, struct {
+		*crypto.OTPAuthKey
+		Code string `json:"code"`
+	}{key, code})
+}
+
+// otpAuthCode returns the current code for a parsed otpauth key.
+func otpAuthCode(key *crypto.OTPAuthKey) (string, error) {
+	opts := crypto.OTPOptions{Algorithm: key.Algorithm, Digits: key.Digits, Period: key.Period}
+	if key.Type == "hotp" {
+		return crypto.GenerateHOTP(key.Secret, key.Counter, opts)
+	}
+	return crypto.TOTPAt(key.Secret, time.Now(), opts)
+}
+
+// cryptoOTPAuthQRParams validates the fields accepted by
+// apiCryptoOTPAuthQRHandler: either a complete URI, or a secret and the
+// parameters to build one from.
+type cryptoOTPAuthQRParams struct {
+	URI       string `validate:"required_without=Secret,max=4096"`
+	Secret    string `validate:"required_without=URI,max=256"`
+	Type      string `validate:"omitempty,oneof=totp hotp"`
+	Algorithm string `validate:"omitempty,oneof=SHA1 SHA256 SHA512"`
+	Digits    int    `validate:"omitempty,min=6,max=8"`
+	Period    int64  `validate:"omitempty,min=1,max=3600"`
+	Size      int    `validate:"omitempty,min=64,max=2048"`
+}
+
+// apiCryptoOTPAuthQRHandler renders a provisioning QR code as a PNG in one
+// call: URI is encoded as given, or one is built from Secret, Type (totp
+// by default), Issuer, Account and the OTP parameters. Either way the URI
+// is validated with crypto.ParseOTPAuthURI first, and the image comes
+// from the same generateService.QR path as /generate/qr. The encoded URI
+// is echoed in the X-OTPAuth-URI header.
+func apiCryptoOTPAuthQRHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoOTPRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	opts := body.options()
+	body.Type = strings.ToLower(body.Type)
+	if !validateStruct(w, cryptoOTPAuthQRParams{
+		URI: body.URI, Secret: body.Secret, Type: body.Type, Algorithm: body.Algorithm,
+		Digits: body.Digits, Period: body.Period, Size: body.Size,
+	}) {
+		return
+	}
+
+	uri := body.URI
+	if uri == "" {
+		key := crypto.OTPAuthKey{
+			Type: body.Type, Issuer: body.Issuer, Account: body.Account, Secret: body.Secret,
+			Algorithm: "SHA1", Digits: 6, Counter: body.Counter,
+		}
+		if opts.Algorithm != "" {
+			key.Algorithm = opts.Algorithm
+		}
+		if opts.Digits != 0 {
+			key.Digits = opts.Digits
+		}
+		if body.Type != "hotp" {
+			key.Period = 30
+			if opts.Period != 0 {
+				key.Period = opts.Period
+			}
+		}
+		uri = key.URI()
+	}
+	if _, err := crypto.ParseOTPAuthURI(uri); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_URI", err.Error(), nil)
+		return
+	}
+
+	size := body.Size
+	if size == 0 {
+		size = 300
+	}
+	png, err := generateService.QR(uri, size, size)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "QR_GENERATION_FAILED", err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("X-OTPAuth-URI", uri)
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// cryptoCipherRequest is the JSON body shape accepted by
+// apiCryptoCipherEncryptHandler and apiCryptoCipherDecryptHandler. Key,
+// IV, AAD, Tag and KDF.Salt are hex; Encoding is the ciphertext encoding
+// and PlaintextEncoding the plaintext's.
+type cryptoCipherRequest struct {
+	Algorithm         string            `json:"algorithm"`
+	Plaintext         string            `json:"plaintext"`
+	PlaintextEncoding string            `json:"plaintext_encoding"`
+	Ciphertext        string            `json:"ciphertext"`
+	Encoding          string            `json:"encoding"`
+	Key               string            `json:"key"`
+	Passphrase        string            `json:"passphrase"`
+	KDF               crypto.KDFOptions `json:"kdf"`
+	IV                string            `json:"iv"`
+	AAD               string            `json:"aad"`
+	Tag               string            `json:"tag"`
+	Padding           string            `json:"padding"`
+}
+
+// cryptoCipherParams validates the fields accepted by the /crypto/cipher
+// handlers, after defaults have been applied. Exactly one of Key and
+// Passphrase supplies the key.
+type cryptoCipherParams struct {
+	Mode              string
+	Algorithm         string `validate:"oneof=aes-cbc aes-ctr aes-gcm chacha20-poly1305 xchacha20-poly1305"`
+	Plaintext         string `validate:"required_if=Mode encrypt"`
+	Ciphertext        string `validate:"required_if=Mode decrypt"`
+	Key               string `validate:"required_without=Passphrase,excluded_with=Passphrase"`
+	Passphrase        string `validate:"max=1024"`
+	IV                string `validate:"required_if=Mode decrypt"`
+	Encoding          string `validate:"oneof=hex base64"`
+	PlaintextEncoding string `validate:"oneof=utf8 hex base64"`
+	Padding           string `validate:"omitempty,oneof=pkcs7 none"`
+}
+
+// cipherParams applies the /crypto/cipher defaults to body (aes-gcm, hex
+// ciphertext, UTF-8 plaintext) and returns the fields to validate for
+// mode.
+func cipherParams(mode string, body *cryptoCipherRequest) cryptoCipherParams {
+	body.Algorithm = strings.ToLower(body.Algorithm)
+	if body.Algorithm == "" {
+		body.Algorithm = "aes-gcm"
+	}
+	body.Encoding = strings.ToLower(body.Encoding)
+	if body.Encoding == "" {
+		body.Encoding = "hex"
+	}
+	body.PlaintextEncoding = strings.ToLower(body.PlaintextEncoding)
+	if body.PlaintextEncoding == "" {
+		body.PlaintextEncoding = "utf8"
+	}
+	return cryptoCipherParams{
+		Mode:              mode,
+		Algorithm:         body.Algorithm,
+		Plaintext:         body.Plaintext,
+		Ciphertext:        body.Ciphertext,
+		Key:               body.Key,
+		Passphrase:        body.Passphrase,
+		IV:                body.IV,
+		Encoding:          body.Encoding,
+		PlaintextEncoding: body.PlaintextEncoding,
+		Padding:           strings.ToLower(body.Padding),
+	}
+}
+
+// cipherOptions decodes the key material in body into crypto.CipherOptions.
+// A passphrase is run through body.KDF (default argon2id, 32-byte key);
+// the options actually used are returned so the key can be re-derived.
+func cipherOptions(mode string, body cryptoCipherRequest) (crypto.CipherOptions, *crypto.KDFOptions, error) {
+	opts := crypto.CipherOptions{Algorithm: body.Algorithm, Padding: body.Padding}
+	var err error
+	if opts.IV, err = crypto.DecodeBytes("iv", body.IV, "hex"); err != nil {
+		return opts, nil, err
+	}
+	if opts.AAD, err = crypto.DecodeBytes("aad", body.AAD, "hex"); err != nil {
+		return opts, nil, err
+	}
+	if body.Passphrase == "" {
+		opts.Key, err = crypto.DecodeBytes("key", body.Key, "hex")
+		return opts, nil, err
+	}
+
+	if mode == "decrypt" && body.KDF.Salt == "" {
+		return opts, nil, fmt.Errorf("kdf.salt is required to decrypt with a passphrase")
+	}
+	if body.KDF.Params.KeyLength == 0 {
+		body.KDF.Params.KeyLength = 32
+	}
+	key, used, err := crypto.DeriveKey(body.Passphrase, body.KDF)
+	if err != nil {
+		return opts, nil, err
+	}
+	opts.Key = key
+	return opts, &used, nil
+}
+
+// apiCryptoCipherEncryptHandler encrypts Plaintext with an explicit
+// cipher, key and IV, composing crypto.CipherEncrypt, so the result can be
+// reproduced by OpenSSL, WebCrypto, libsodium and the like. Algorithm is
+// aes-cbc, aes-ctr, aes-gcm (default), chacha20-poly1305 or
+// xchacha20-poly1305. The key is raw hex, or derived from Passphrase with
+// KDF (argon2id, argon2i, scrypt or pbkdf2-sha1/256/512, with the same
+// params as /crypto/password/hash). A random IV is generated when none is
+// given. AEAD ciphertext ends with the tag, which is also returned
+// separately.
+func apiCryptoCipherEncryptHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCipherRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cipherParams("encrypt", &body)) {
+		return
+	}
+
+	plaintext, err := crypto.DecodeBytes("plaintext", body.Plaintext, body.PlaintextEncoding)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	opts, kdf, err := cipherOptions("encrypt", body)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	ciphertext, iv, err := crypto.CipherEncrypt(plaintext, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "ENCRYPT_FAILED", err.Error(), nil)
+		return
+	}
+
+	encoded, _ := crypto.EncodeBytes(ciphertext, body.Encoding)
+	data := map[string]interface{}{
+		"algorithm":  body.Algorithm,
+		"ciphertext": encoded,
+		"encoding":   body.Encoding,
+		"iv":         hex.EncodeToString(iv),
+	}
+	if body.Algorithm != "aes-cbc" && body.Algorithm != "aes-ctr" {
+		data["tag"] = hex.EncodeToString(ciphertext[len(ciphertext)-16:])
+	}
+	if kdf != nil {
+		data["key"] = hex.EncodeToString(opts.Key)
+		data["kdf"] = kdf
+	}
+	writeEnvelopeOK(w, http.StatusOK, data)
+}
+
+// apiCryptoCipherDecryptHandler reverses apiCryptoCipherEncryptHandler,
+// composing crypto.CipherDecrypt. IV is required, and so is kdf.salt when
+// the key comes from a passphrase. For AEAD ciphers a Tag given
+// separately (as OpenSSL and many HSMs emit it) is appended to the
+// ciphertext. The plaintext falls back to hex when it is not valid UTF-8.
+func apiCryptoCipherDecryptHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoCipherRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cipherParams("decrypt", &body)) {
+		return
+	}
+
+	ciphertext, err := crypto.DecodeBytes("ciphertext", body.Ciphertext, body.Encoding)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	tag, err := crypto.DecodeBytes("tag", body.Tag, "hex")
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	ciphertext = append(ciphertext, tag...)
+	opts, _, err := cipherOptions("decrypt", body)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	plaintext, err := crypto.CipherDecrypt(ciphertext, opts)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "DECRYPT_FAILED", err.Error(), nil)
+		return
+	}
+
+	encoded, encoding := crypto.EncodeBytes(plaintext, body.PlaintextEncoding)
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"algorithm":          body.Algorithm,
+		"plaintext":          encoded,
+		"plaintext_encoding": encoding,
+	})
+}
+
+// cryptoKDFRequest is the JSON body shape accepted by apiCryptoKDFHandler.
+type cryptoKDFRequest struct {
+	Passphrase string `json:"passphrase"`
+	crypto.KDFOptions
+}
+
+// cryptoKDFParams validates the fields accepted by apiCryptoKDFHandler;
+// the work-factor bounds are enforced by crypto.DeriveKey.
+type cryptoKDFParams struct {
+	Passphrase string `validate:"required,max=1024"`
+	Algorithm  string `validate:"omitempty,oneof=argon2id argon2i scrypt pbkdf2-sha256 pbkdf2-sha512 pbkdf2-sha1"`
+}
+
+// apiCryptoKDFHandler derives a raw key from Passphrase, composing
+// crypto.DeriveKey. Algorithm defaults to argon2id and Params to the
+// /crypto/password/hash defaults; Salt (hex) is random when omitted. The
+// response echoes every parameter used alongside the hex key.
+func apiCryptoKDFHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoKDFRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.Algorithm = strings.ToLower(body.Algorithm)
+	if !validateStruct(w, cryptoKDFParams{Passphrase: body.Passphrase, Algorithm: body.Algorithm}) {
+		return
+	}
+
+	key, used, err := crypto.DeriveKey(body.Passphrase, body.KDFOptions)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"algorithm": used.Algorithm,
+		"salt":      used.Salt,
+		"params":    used.Params,
+		"key":       hex.EncodeToString(key),
+	})
+}
+
+// cryptoKeyWrapRequest is the JSON body shape accepted by
+// apiCryptoKeyWrapHandler and apiCryptoKeyUnwrapHandler. All values are
+// hex.
+type cryptoKeyWrapRequest struct {
+	KEK     string `json:"kek"`
+	Key     string `json:"key"`
+	Wrapped string `json:"wrapped"`
+	Padding bool   `json:"padding"`
+}
+
+// cryptoKeyWrapParams validates the fields accepted by the /crypto/keywrap
+// handlers.
+type cryptoKeyWrapParams struct {
+	Mode    string
+	KEK     string `validate:"required,hexadecimal"`
+	Key     string `validate:"required_if=Mode wrap,omitempty,hexadecimal"`
+	Wrapped string `validate:"required_if=Mode unwrap,omitempty,hexadecimal"`
+}
+
+// keyWrapAlgorithm names the key wrap variant in responses.
+func keyWrapAlgorithm(padding bool) string {
+	if padding {
+		return "AES-KWP"
+	}
+	return "AES-KW"
+}
+
+// apiCryptoKeyWrapHandler wraps Key under the AES key-encryption key KEK,
+// composing crypto.AESKeyWrap: RFC 3394 (AES-KW), or RFC 5649 (AES-KWP,
+// any key length) when Padding is true.
+func apiCryptoKeyWrapHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoKeyWrapRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoKeyWrapParams{Mode: "wrap", KEK: body.KEK, Key: body.Key}) {
+		return
+	}
+
+	kek, err1 := hex.DecodeString(body.KEK)
+	key, err2 := hex.DecodeString(body.Key)
+	if err1 != nil || err2 != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "kek and key must be hex", nil)
+		return
+	}
+	wrapped, err := crypto.AESKeyWrap(kek, key, body.Padding)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_KEY", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"algorithm": keyWrapAlgorithm(body.Padding),
+		"wrapped":   hex.EncodeToString(wrapped),
+	})
+}
+
+// apiCryptoKeyUnwrapHandler reverses apiCryptoKeyWrapHandler, composing
+// crypto.AESKeyUnwrap. A wrong KEK or padding mode fails the integrity
+// check.
+func apiCryptoKeyUnwrapHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoKeyWrapRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if !validateStruct(w, cryptoKeyWrapParams{Mode: "unwrap", KEK: body.KEK, Wrapped: body.Wrapped}) {
+		return
+	}
+
+	kek, err1 := hex.DecodeString(body.KEK)
+	wrapped, err2 := hex.DecodeString(body.Wrapped)
+	if err1 != nil || err2 != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "kek and wrapped must be hex", nil)
+		return
+	}
+	key, err := crypto.AESKeyUnwrap(kek, wrapped, body.Padding)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "UNWRAP_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"algorithm": keyWrapAlgorithm(body.Padding),
+		"key":       hex.EncodeToString(key),
+	})
+}
+
+// cryptoAgeRequest is the JSON body shape accepted by
+// apiCryptoAgeEncryptHandler and apiCryptoAgeDecryptHandler.
+type cryptoAgeRequest struct {
+	Plaintext         string   `json:"plaintext"`
+	PlaintextEncoding string   `json:"plaintext_encoding"`
+	Ciphertext        string   `json:"ciphertext"`
+	Recipients        []string `json:"recipients"`
+	Identities        []string `json:"identities"`
+	Passphrase        string   `json:"passphrase"`
+	WorkFactor        int      `json:"work_factor"`
+	Armor             *bool    `json:"armor"`
+}
+
+// cryptoAgeEncryptParams validates the fields accepted by
+// apiCryptoAgeEncryptHandler: exactly one of Recipients and Passphrase.
+type cryptoAgeEncryptParams struct {
+	Plaintext         string   `validate:"required"`
+	PlaintextEncoding string   `validate:"oneof=utf8 hex base64"`
+	Recipients        []string `validate:"required_without=Passphrase,excluded_with=Passphrase,max=50"`
+	Passphrase        string   `validate:"max=1024"`
+	WorkFactor        int      `validate:"min=0,max=18"`
+}
+
+// cryptoAgeDecryptParams validates the fields accepted by
+// apiCryptoAgeDecryptHandler.
+type cryptoAgeDecryptParams struct {
+	Ciphertext        string   `validate:"required"`
+	PlaintextEncoding string   `validate:"oneof=utf8 hex base64"`
+	Identities        []string `validate:"required_without=Passphrase,max=50"`
+	Passphrase        string   `validate:"max=1024"`
+}
+
+// apiCryptoAgeKeygenHandler generates an age X25519 identity and its
+// recipient, composing crypto.GenerateAgeKeyPair.
+func apiCryptoAgeKeygenHandler(w http.ResponseWriter, r *http.Request) {
+	pair, err := crypto.GenerateAgeKeyPair()
+	if err != nil {
+		writeEnvelopeError(w, http.StatusInternalServerError, "GENERATE_FAILED", err.Error(), nil)
+		return
+	}
+	writeEnvelopeOK(w, http.StatusOK, pair)
+}
+
+// apiCryptoAgeEncryptHandler encrypts Plaintext in the age v1 format,
+// composing crypto.AgeEncrypt, to the X25519 Recipients ("age1...") or
+// to Passphrase with scrypt work factor WorkFactor (log2 N, default and
+// maximum 18). Output is ASCII-armored unless Armor is false, in which
+// case it is base64 of the binary file. Either form decrypts with the
+// age CLI.
+func apiCryptoAgeEncryptHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoAgeRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.PlaintextEncoding = strings.ToLower(body.PlaintextEncoding)
+	if body.PlaintextEncoding == "" {
+		body.PlaintextEncoding = "utf8"
+	}
+	if !validateStruct(w, cryptoAgeEncryptParams{
+		Plaintext:         body.Plaintext,
+		PlaintextEncoding: body.PlaintextEncoding,
+		Recipients:        body.Recipients,
+		Passphrase:        body.Passphrase,
+		WorkFactor:        body.WorkFactor,
+	}) {
+		return
+	}
+
+	plaintext, err := crypto.DecodeBytes("plaintext", body.Plaintext, body.PlaintextEncoding)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	armored := body.Armor == nil || *body.Armor
+	ciphertext, err := crypto.AgeEncrypt(plaintext, body.Recipients, body.Passphrase, body.WorkFactor, armored)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "ENCRYPT_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"ciphertext": ciphertext,
+		"armored":    armored,
+	})
+}
+
+// apiCryptoAgeDecryptHandler decrypts an armored or base64 age file with
+// the X25519 Identities ("AGE-SECRET-KEY-1...") or Passphrase, composing
+// crypto.AgeDecrypt. The plaintext falls back to hex when it is not valid
+// UTF-8.
+func apiCryptoAgeDecryptHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoAgeRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.PlaintextEncoding = strings.ToLower(body.PlaintextEncoding)
+	if body.PlaintextEncoding == "" {
+		body.PlaintextEncoding = "utf8"
+	}
+	if !validateStruct(w, cryptoAgeDecryptParams{
+		Ciphertext:        body.Ciphertext,
+		PlaintextEncoding: body.PlaintextEncoding,
+		Identities:        body.Identities,
+		Passphrase:        body.Passphrase,
+	}) {
+		return
+	}
+
+	plaintext, err := crypto.AgeDecrypt(body.Ciphertext, body.Identities, body.Passphrase)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "DECRYPT_FAILED", err.Error(), nil)
+		return
+	}
+
+	encoded, encoding := crypto.EncodeBytes(plaintext, body.PlaintextEncoding)
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"plaintext":          encoded,
+		"plaintext_encoding": encoding,
+	})
+}
+
+// cryptoShamirSplitRequest is the JSON body shape accepted by
+// apiCryptoShamirSplitHandler.
+type cryptoShamirSplitRequest struct {
+	Secret         string `json:"secret"`
+	SecretEncoding string `json:"secret_encoding"`
+	Shares         int    `json:"shares"`
+	Threshold      int    `json:"threshold"`
+	Encoding       string `json:"encoding"`
+}
+
+// cryptoShamirSplitParams validates the fields accepted by
+// apiCryptoShamirSplitHandler, after the encodings' defaults have been
+// applied.
+type cryptoShamirSplitParams struct {
+	Secret         string `validate:"required,max=8192"`
+	SecretEncoding string `validate:"oneof=utf8 hex base64"`
+	Shares         int    `validate:"required,min=2,max=255"`
+	Threshold      int    `validate:"required,min=2,ltefield=Shares"`
+	Encoding       string `validate:"oneof=hex base64 mnemonic"`
+}
+
+// apiCryptoShamirSplitHandler splits Secret into Shares shares, any
+// Threshold of which recover it, composing crypto.ShamirSplit. Shares are
+// encoded as hex (default), base64 or mnemonic words from the English
+// BIP39 wordlist; each carries the split id, threshold and a checksum.
+func apiCryptoShamirSplitHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoShamirSplitRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.SecretEncoding = strings.ToLower(body.SecretEncoding)
+	if body.SecretEncoding == "" {
+		body.SecretEncoding = "utf8"
+	}
+	body.Encoding = strings.ToLower(body.Encoding)
+	if body.Encoding == "" {
+		body.Encoding = "hex"
+	}
+	if !validateStruct(w, cryptoShamirSplitParams{
+		Secret:         body.Secret,
+		SecretEncoding: body.SecretEncoding,
+		Shares:         body.Shares,
+		Threshold:      body.Threshold,
+		Encoding:       body.Encoding,
+	}) {
+		return
+	}
+
+	secret, err := crypto.DecodeBytes("secret", body.Secret, body.SecretEncoding)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+	shares, err := crypto.ShamirSplit(secret, body.Shares, body.Threshold)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", err.Error(), nil)
+		return
+	}
+
+	encoded := make([]string, len(shares))
+	for i, share := range shares {
+		if encoded[i], err = crypto.EncodeShamirShare(share, body.Encoding); err != nil {
+			writeEnvelopeError(w, http.StatusInternalServerError, "SPLIT_FAILED", err.Error(), nil)
+			return
+		}
+	}
+	info, _ := crypto.InspectShamirShare(shares[0])
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"id":        info.ID,
+		"threshold": body.Threshold,
+		"encoding":  body.Encoding,
+		"shares":    encoded,
+	})
+}
+
+// cryptoShamirCombineRequest is the JSON body shape accepted by
+// apiCryptoShamirCombineHandler.
+type cryptoShamirCombineRequest struct {
+	Shares         []string `json:"shares"`
+	Encoding       string   `json:"encoding"`
+	SecretEncoding string   `json:"secret_encoding"`
+}
+
+// cryptoShamirCombineParams validates the fields accepted by
+// apiCryptoShamirCombineHandler, after the encodings' defaults have been
+// applied.
+type cryptoShamirCombineParams struct {
+	Shares         []string `validate:"required,min=2,max=255,dive,required"`
+	Encoding       string   `validate:"oneof=hex base64 mnemonic"`
+	SecretEncoding string   `validate:"oneof=utf8 hex base64"`
+}
+
+// apiCryptoShamirCombineHandler recovers a secret from shares made by
+// apiCryptoShamirSplitHandler, composing crypto.ShamirCombine. Each
+// share's checksum is verified, all must come from one split, and the
+// recovered secret is checked against the digest embedded when it was
+// split, so a mistyped, altered or foreign share is a 400 naming it
+// rather than a wrong secret. The secret falls back to hex when it is not
+// valid UTF-8.
+func apiCryptoShamirCombineHandler(w http.ResponseWriter, r *http.Request) {
+	var body cryptoShamirCombineRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	body.Encoding = strings.ToLower(body.Encoding)
+	if body.Encoding == "" {
+		body.Encoding = "hex"
+	}
+	body.SecretEncoding = strings.ToLower(body.SecretEncoding)
+	if body.SecretEncoding == "" {
+		body.SecretEncoding = "utf8"
+	}
+	if !validateStruct(w, cryptoShamirCombineParams{Shares: body.Shares, Encoding: body.Encoding, SecretEncoding: body.SecretEncoding}) {
+		return
+	}
+
+	shares := make([][]byte, len(body.Shares))
+	for i, s := range body.Shares {
+		share, err := crypto.DecodeShamirShare(s, body.Encoding)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_SHARE", fmt.Sprintf("share %d: %v", i+1, err), nil)
+			return
+		}
+		shares[i] = share
+	}
+	secret, info, err := crypto.ShamirCombine(shares)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_SHARE", err.Error(), nil)
+		return
+	}
+
+	encoded, encoding := crypto.EncodeBytes(secret, body.SecretEncoding)
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"secret":          encoded,
+		"secret_encoding": encoding,
+		"id":              info.ID,
+		"threshold":       info.Threshold,
+	})
+}
+
+// apiNetworkDNSHandler queries DNS records for a domain via the system
+// resolver, composing the existing free/keyless osint.DNSLookup function.
+// Defaults to an A-record lookup when no record type is given.
+func apiNetworkDNSHandler(w http.ResponseWriter, r *http.Request) {
+	domain := chi.URLParam(r, "domain")
+	recordType := chi.URLParam(r, "type")
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	records, err := osintService.DNSLookup(domain, recordType)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "DNS_LOOKUP_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"domain":  domain,
+		"type":    strings.ToUpper(recordType),
+		"records": records,
+	})
+}
+
+// textCompressRequest is the JSON body shape accepted by
+// apiTextCompressHandler: the input data, the algorithm (gzip, zlib, or
+// flate/deflate), and the direction (compress or decompress).
+type textCompressRequest struct {
+	Data      string `json:"data"`
+	Algorithm string `json:"algorithm"`
+	Mode      string `json:"mode"`
+}
+
+// textCompressParams validates the data/mode fields accepted by
+// apiTextCompressHandler, after Mode's default has been applied.
+type textCompressParams struct {
+	Data string `validate:"required"`
+	Mode string `validate:"oneof=compress decompress"`
+}
+
+// apiTextCompressHandler compresses or decompresses data using
+// text.Compress/text.Decompress, both base64-encoded on the wire so the
+// result is always safe JSON. Mode defaults to "compress"; Algorithm
+// defaults to "gzip" (zlib and flate/deflate are also supported by the
+// underlying service — brotli/zstd are not implemented).
+func apiTextCompressHandler(w http.ResponseWriter, r *http.Request) {
+	var body textCompressRequest
+	if err := decodeJSONBody(r, &body); err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	if body.Algorithm == "" {
+		body.Algorithm = "gzip"
+	}
+	if body.Mode == "" {
+		body.Mode = "compress"
+	}
+	if !validateStruct(w, textCompressParams{Data: body.Data, Mode: body.Mode}) {
+		return
+	}
+
+	var result string
+	var err error
+	switch strings.ToLower(body.Mode) {
+	case "compress":
+		result, err = text.Compress(body.Data, body.Algorithm)
+	case "decompress":
+		result, err = text.Decompress(body.Data, body.Algorithm)
+	}
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, "COMPRESS_FAILED", err.Error(), nil)
+		return
+	}
+
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"mode":      body.Mode,
+		"algorithm": body.Algorithm,
+		"result":    result,
+	})
+}
+
+// textDiffRequest is the JSON body shape accepted by apiTextDiffHandler.
+type textDiffRequest struct {
+	Text1 string `json:"text1"`
+	Text2 string `json:"text2"`
+}
+
+// apiTextDiffHandler returns a unified line diff between two texts using
+// text.Diff.
+func apiTextDiffHandler(w http.ResponseWriter, r *http.Request) {
+	var body textDiffRequest
 	if err := decodeJSONBody(r, &body); err != nil {
 		writeEnvelopeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
 		return
@@ -5192,8 +7767,9 @@ type cryptoHMACParams struct {
 }
 
 // apiCryptoHMACHandler computes an HMAC of Message using Key, composing
-// crypto.HMACGenerate. Algorithm defaults to sha256; sha1 is also
-// supported.
+// crypto.HMACGenerate. Algorithm defaults to sha256; sha1, sha384, sha512
+// and sha3-224/256/384/512 are also HMACs, while blake2b-256/512,
+// blake2s-256 and blake3 (32-byte key) use the hash's own keyed mode.
 func apiCryptoHMACHandler(w http.ResponseWriter, r *http.Request) {
 	var body cryptoHMACRequest
 	if err := decodeJSONBody(r, &body); err != nil {
@@ -5360,30 +7936,50 @@ func apiCryptoEd25519Handler(w http.ResponseWriter, r *http.Request) {
 
 // cryptoPGPRequest is the JSON body shape accepted by apiCryptoPGPHandler.
 type cryptoPGPRequest struct {
-	Mode       string `json:"mode"`
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	Plaintext  string `json:"plaintext"`
-	Ciphertext string `json:"ciphertext"`
-	PublicKey  string `json:"public_key"`
-	PrivateKey string `json:"private_key"`
+	Mode       string   `json:"mode"`
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	Plaintext  string   `json:"plaintext"`
+	Ciphertext string   `json:"ciphertext"`
+	Message    string   `json:"message"`
+	Signature  string   `json:"signature"`
+	Encoding   string   `json:"encoding"`
+	PublicKey  string   `json:"public_key"`
+	PublicKeys []string `json:"public_keys"`
+	PrivateKey string   `json:"private_key"`
+	Passphrase string   `json:"passphrase"`
 }
 
 // cryptoPGPParams validates the mode-dependent fields accepted by
-// apiCryptoPGPHandler, after Mode's default has been applied.
+// apiCryptoPGPHandler, after Mode's default has been applied and
+// PublicKey has been merged into PublicKeys.
 type cryptoPGPParams struct {
-	Mode       string `validate:"oneof=generate encrypt decrypt"`
-	Name       string `validate:"required_if=Mode generate"`
-	Email      string `validate:"required_if=Mode generate,omitempty,email"`
-	Plaintext  string `validate:"required_if=Mode encrypt"`
-	PublicKey  string `validate:"required_if=Mode encrypt"`
-	Ciphertext string `validate:"required_if=Mode decrypt"`
-	PrivateKey string `validate:"required_if=Mode decrypt"`
-}
-
-// apiCryptoPGPHandler handles PGP keypair generation, encryption, and
-// decryption in one endpoint, selected by Mode. Composes
-// crypto.GeneratePGPKeys, crypto.PGPEncrypt, and crypto.PGPDecrypt.
+	Mode       string   `validate:"oneof=generate encrypt decrypt sign clearsign verify"`
+	Name       string   `validate:"required_if=Mode generate"`
+	Email      string   `validate:"required_if=Mode generate,omitempty,email"`
+	Plaintext  string   `validate:"required_if=Mode encrypt"`
+	PublicKeys []string `validate:"required_if=Mode encrypt,required_if=Mode verify,max=50,dive,required"`
+	Ciphertext string   `validate:"required_if=Mode decrypt"`
+	PrivateKey string   `validate:"required_if=Mode decrypt,required_if=Mode sign,required_if=Mode clearsign"`
+	Message    string   `validate:"required_if=Mode sign,required_if=Mode clearsign,required_if=Mode verify"`
+	Encoding   string   `validate:"omitempty,oneof=text base64"`
+	Passphrase string   `validate:"max=1024"`
+}
+
+// apiCryptoPGPHandler handles PGP keypair generation, encryption,
+// decryption, signing, and signature verification in one endpoint,
+// selected by Mode. Composes crypto.GeneratePGPKeysWithPassphrase,
+// crypto.PGPEncryptTo, crypto.PGPDecryptWithPassphrase, crypto.PGPSign,
+// crypto.PGPClearsign, and crypto.PGPVerify.
+//
+// Passphrase protects the generated private key, and unlocks a protected
+// one for decrypt, sign and clearsign. Encrypt and verify take keys from
+// PublicKey and PublicKeys together, so a message can be encrypted to
+// several recipients or checked against a keyring. sign makes a detached
+// armored signature of Message; verify checks Message against Signature,
+// or checks Message as a clearsigned message when Signature is empty.
+// With Encoding base64, Message is decoded first, so binary artifacts can
+// be signed and verified.
 func apiCryptoPGPHandler(w http.ResponseWriter, r *http.Request) {
 	var body cryptoPGPRequest
 	if err := decodeJSONBody(r, &body); err != nil {
@@ -5393,21 +7989,38 @@ func apiCryptoPGPHandler(w http.ResponseWriter, r *http.Request) {
 	if body.Mode == "" {
 		body.Mode = "generate"
 	}
+	publicKeys := body.PublicKeys
+	if body.PublicKey != "" {
+		publicKeys = append([]string{body.PublicKey}, publicKeys...)
+	}
 	if !validateStruct(w, cryptoPGPParams{
 		Mode:       body.Mode,
 		Name:       body.Name,
 		Email:      body.Email,
 		Plaintext:  body.Plaintext,
-		PublicKey:  body.PublicKey,
+		PublicKeys: publicKeys,
 		Ciphertext: body.Ciphertext,
 		PrivateKey: body.PrivateKey,
+		Message:    body.Message,
+		Encoding:   body.Encoding,
+		Passphrase: body.Passphrase,
 	}) {
 		return
 	}
 
+	message := []byte(body.Message)
+	if body.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(body.Message)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_VALUE", "message is not valid base64", nil)
+			return
+		}
+		message = decoded
+	}
+
 	switch strings.ToLower(body.Mode) {
 	case "generate":
-		publicKey, privateKey, err := crypto.GeneratePGPKeys(body.Name, body.Email)
+		publicKey, privateKey, err := crypto.GeneratePGPKeysWithPassphrase(body.Name, body.Email, body.Passphrase)
 		if err != nil {
 			writeEnvelopeError(w, http.StatusBadRequest, "GENERATE_FAILED", err.Error(), nil)
 			return
@@ -5416,9 +8029,10 @@ func apiCryptoPGPHandler(w http.ResponseWriter, r *http.Request) {
 			"mode":        body.Mode,
 			"public_key":  publicKey,
 			"private_key": privateKey,
+			"protected":   body.Passphrase != "",
 		})
 	case "encrypt":
-		ciphertext, err := crypto.PGPEncrypt(body.Plaintext, body.PublicKey)
+		ciphertext, err := crypto.PGPEncryptTo(body.Plaintext, publicKeys)
 		if err != nil {
 			writeEnvelopeError(w, http.StatusBadRequest, "ENCRYPT_FAILED", err.Error(), nil)
 			return
@@ -5428,7 +8042,7 @@ func apiCryptoPGPHandler(w http.ResponseWriter, r *http.Request) {
 			"ciphertext": ciphertext,
 		})
 	case "decrypt":
-		plaintext, err := crypto.PGPDecrypt(body.Ciphertext, body.PrivateKey)
+		plaintext, err := crypto.PGPDecryptWithPassphrase(body.Ciphertext, body.PrivateKey, body.Passphrase)
 		if err != nil {
 			writeEnvelopeError(w, http.StatusBadRequest, "DECRYPT_FAILED", err.Error(), nil)
 			return
@@ -5437,6 +8051,48 @@ func apiCryptoPGPHandler(w http.ResponseWriter, r *http.Request) {
 			"mode":      body.Mode,
 			"plaintext": plaintext,
 		})
+	case "sign":
+		signature, err := crypto.PGPSign(message, body.PrivateKey, body.Passphrase)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "SIGN_FAILED", err.Error(), nil)
+			return
+		}
+		writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+			"mode":      body.Mode,
+			"signature": signature,
+		})
+	case "clearsign":
+		signed, err := crypto.PGPClearsign(body.Message, body.PrivateKey, body.Passphrase)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "SIGN_FAILED", err.Error(), nil)
+			return
+		}
+		writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+			"mode":    body.Mode,
+			"message": signed,
+		})
+	case "verify":
+		result, err := crypto.PGPVerify(message, body.Signature, publicKeys)
+		if err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "VERIFY_FAILED", err.Error(), nil)
+			return
+		}
+		data := map[string]interface{}{
+			"mode":           body.Mode,
+			"valid":          result.Valid,
+			"key_id":         result.KeyID,
+			"fingerprint":    result.Fingerprint,
+			"signer":         result.Signer,
+			"created_at":     result.CreatedAt,
+			"hash_algorithm": result.HashAlgorithm,
+		}
+		if body.Signature == "" {
+			data["message"] = result.Message
+		}
+		if result.Error != "" {
+			data["error"] = result.Error
+		}
+		writeEnvelopeOK(w, http.StatusOK, data)
 	}
 }
 
@@ -5623,18 +8279,44 @@ func apiGenerateSQLHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// generateSSHKeyParams validates the optional ?format= query parameter of
+// apiGenerateSSHKeyHandler.
+type generateSSHKeyParams struct {
+	Format string `validate:"omitempty,oneof=openssh pem jwk"`
+}
+
 // apiGenerateSSHKeyHandler generates a stateless Ed25519 SSH key pair and
 // returns both keys in the JSON envelope; nothing is persisted, so the
 // private key is returned in full (this is the only time it is available).
+// ?format=pem or ?format=jwk converts both keys with crypto.ConvertKey
+// instead of the default OpenSSH formats; the fingerprints are the same
+// whichever format is chosen.
 func apiGenerateSSHKeyHandler(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if !validateStruct(w, generateSSHKeyParams{Format: format}) {
+		return
+	}
+
 	keyPair, err := generateService.SSHKey()
 	if err != nil {
 		writeEnvelopeError(w, http.StatusInternalServerError, "SSH_KEY_GENERATION_FAILED", err.Error(), nil)
 		return
 	}
-	writeEnvelopeOK(w, http.StatusOK, map[string]string{
-		"private_key": keyPair.PrivateKey,
-		"public_key":  keyPair.PublicKey,
+	if format != "" && format != "openssh" {
+		private, err := crypto.ConvertKey(keyPair.PrivateKey, format, false)
+		if err == nil {
+			keyPair.PublicKey, err = crypto.ConvertKey(keyPair.PrivateKey, format, true)
+		}
+		if err != nil {
+			writeEnvelopeError(w, http.StatusInternalServerError, "SSH_KEY_GENERATION_FAILED", err.Error(), nil)
+			return
+		}
+		keyPair.PrivateKey = private
+	}
+	writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+		"private_key":  keyPair.PrivateKey,
+		"public_key":   keyPair.PublicKey,
+		"fingerprints": keyPair.Fingerprints,
 	})
 }
 
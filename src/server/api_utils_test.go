@@ -182,6 +182,258 @@ func TestAPIMathCalculateHandler(t *testing.T) {
 		env := decodeEnvelope(t, w.Body.Bytes())
 		assert.Equal(t, "VALIDATION_FAILED", env["error"])
 	})
+
+	t.Run("expression with variables", func(t *testing.T) {
+		q := url.Values{"operation": {"expression"}, "expression": {"(2^10 + x) * 2"}, "variables": {"x:6"}}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, float64(2060), data["result"])
+	})
+
+	t.Run("missing expression", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=expression", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "MISSING_EXPRESSION", env["error"])
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		q := url.Values{"operation": {"expression"}, "expression": {"1 + "}}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "INVALID_EXPRESSION", env["error"])
+	})
+
+	t.Run("expression divide by zero", func(t *testing.T) {
+		q := url.Values{"operation": {"expression"}, "expression": {"1/0"}}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "DIVISION_BY_ZERO", env["error"])
+	})
+}
+
+// ?mode=bigint and ?mode=bigfloat (or ?precision=) must route calculate
+// through math/big, and unsupported operations or modes must be rejected.
+func TestAPIMathCalculateHandlerBigModes(t *testing.T) {
+	t.Run("bigint multiply beyond int64", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=multiply&mode=bigint&a=9223372036854775807&b=10", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "bigint", data["mode"])
+		assert.Equal(t, "92233720368547758070", data["result"])
+	})
+
+	t.Run("bigint divide returns remainder", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=divide&mode=bigint&a=100000000000000000001&b=10", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "10000000000000000000", data["result"])
+		assert.Equal(t, "1", data["remainder"])
+	})
+
+	t.Run("bigint factorial", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=factorial&mode=bigint&n=25", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "15511210043330985984000000", data["result"])
+	})
+
+	t.Run("bigint power too large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=power&mode=bigint&a=10&b=1000000", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "VALUE_TOO_LARGE", env["error"])
+	})
+
+	t.Run("bigint unsupported operation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=sin&mode=bigint&n=1", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "UNSUPPORTED_OPERATION", env["error"])
+	})
+
+	t.Run("precision implies bigfloat", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=divide&a=1&b=3&precision=128", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "bigfloat", data["mode"])
+		assert.Equal(t, float64(128), data["precision"])
+		assert.True(t, strings.HasPrefix(data["result"].(string), "0.3333333333333333333333333333333333333"))
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/calculate?operation=add&a=1&b=2&mode=decimal", nil)
+		w := httptest.NewRecorder()
+
+		apiMathCalculateHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+}
+
+// apiMathPrimeHandler keeps int64 inputs on IsPrime and switches to
+// Miller-Rabin for wider values.
+func TestAPIMathPrimeHandler(t *testing.T) {
+	t.Run("int64 input", func(t *testing.T) {
+		req := reqWithParams(http.MethodGet, "/api/v1/math/prime/17", map[string]string{"n": "17"}, nil)
+		w := httptest.NewRecorder()
+
+		apiMathPrimeHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, true, data["is_prime"])
+		assert.Nil(t, data["method"])
+	})
+
+	t.Run("input beyond int64", func(t *testing.T) {
+		m127 := "170141183460469231731687303715884105727"
+		req := reqWithParams(http.MethodGet, "/api/v1/math/prime/"+m127, map[string]string{"n": m127}, nil)
+		w := httptest.NewRecorder()
+
+		apiMathPrimeHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, m127, data["n"])
+		assert.Equal(t, true, data["is_prime"])
+		assert.Equal(t, "miller-rabin", data["method"])
+	})
+
+	t.Run("not an integer", func(t *testing.T) {
+		req := reqWithParams(http.MethodGet, "/api/v1/math/prime/abc", map[string]string{"n": "abc"}, nil)
+		w := httptest.NewRecorder()
+
+		apiMathPrimeHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "INVALID_VALUE", env["error"])
+	})
+
+	t.Run("bigfloat mode and precision rejected", func(t *testing.T) {
+		for _, query := range []string{"mode=bigfloat", "precision=128", "mode=bigint&precision=64"} {
+			req := reqWithParams(http.MethodGet, "/api/v1/math/prime/17?"+query, map[string]string{"n": "17"}, nil)
+			w := httptest.NewRecorder()
+
+			apiMathPrimeHandler(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			env := decodeEnvelope(t, w.Body.Bytes())
+			assert.Equal(t, "UNSUPPORTED_OPERATION", env["error"], query)
+		}
+	})
+}
+
+// apiMathModPowHandler and apiMathModInverseHandler require their operands
+// and report non-invertible inputs.
+func TestAPIMathModularHandlers(t *testing.T) {
+	t.Run("mod-pow", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/mod-pow?base=4&exponent=13&modulus=497", nil)
+		w := httptest.NewRecorder()
+
+		apiMathModPowHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "445", data["result"])
+	})
+
+	t.Run("mod-pow missing modulus", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/mod-pow?base=4&exponent=13", nil)
+		w := httptest.NewRecorder()
+
+		apiMathModPowHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+
+	t.Run("mod-inverse", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/mod-inverse?a=3&modulus=11", nil)
+		w := httptest.NewRecorder()
+
+		apiMathModInverseHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "4", data["result"])
+	})
+
+	t.Run("mod-inverse not coprime", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/mod-inverse?a=6&modulus=9", nil)
+		w := httptest.NewRecorder()
+
+		apiMathModInverseHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "NO_MODULAR_INVERSE", env["error"])
+	})
 }
 
 func TestAPIMathFibonacciHandler(t *testing.T) {
@@ -249,6 +501,30 @@ func TestAPIMathBaseHandler(t *testing.T) {
 		assert.Equal(t, "ff", data["result"])
 	})
 
+	t.Run("bigint mode beyond int64", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/base?number=ffffffffffffffffffff&from_base=16&to_base=10&mode=bigint", nil)
+		w := httptest.NewRecorder()
+
+		apiMathBaseHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "1208925819614629174706175", data["result"])
+	})
+
+	t.Run("bigfloat mode rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/base?number=255&from_base=10&to_base=16&mode=bigfloat", nil)
+		w := httptest.NewRecorder()
+
+		apiMathBaseHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "UNSUPPORTED_OPERATION", env["error"])
+	})
+
 	t.Run("invalid number for base", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/math/base?number=zz&from_base=10&to_base=16", nil)
 		w := httptest.NewRecorder()
@@ -697,6 +973,7 @@ func TestAPIDatetimeFormatHandler(t *testing.T) {
 
 func TestAPIDatetimeParseHandler(t *testing.T) {
 	r := chi.NewRouter()
+	r.Get("/datetime/parse", apiDatetimeParseHandler)
 	r.Get("/datetime/parse/{value}", apiDatetimeParseHandler)
 
 	t.Run("valid date string", func(t *testing.T) {
@@ -720,6 +997,37 @@ func TestAPIDatetimeParseHandler(t *testing.T) {
 		env := decodeEnvelope(t, w.Body.Bytes())
 		assert.Equal(t, "UNPARSEABLE_DATE", env["error"])
 	})
+
+	t.Run("natural language relative to a reference", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/parse/"+url.PathEscape("next tuesday 3pm")+"?reference=2026-10-16T14:30:00Z&timezone=America/Chicago", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "2026-10-20T15:00:00-05:00", data["iso8601"])
+		assert.Equal(t, "the first Tuesday after the reference date, at 15:00", data["interpretation"])
+	})
+
+	t.Run("interval via query parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/parse?value="+url.QueryEscape("2024-01-01/P1M"), nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "interval", data["kind"])
+		assert.Equal(t, "2024-02-01T00:00:00Z", data["end"])
+	})
+
+	t.Run("bad reference", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/parse/tomorrow?reference=yesterday", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_TIMESTAMP", decodeEnvelope(t, w.Body.Bytes())["error"])
+	})
 }
 
 func TestAPIDatetimeCronHandler(t *testing.T) {
@@ -747,6 +1055,43 @@ func TestAPIDatetimeCronHandler(t *testing.T) {
 		env := decodeEnvelope(t, w.Body.Bytes())
 		assert.Equal(t, "INVALID_CRON", env["error"])
 	})
+
+	t.Run("timezone, count and window", func(t *testing.T) {
+		q := url.Values{
+			"expression": {"0 30 2 * * *"},
+			"timezone":   {"America/New_York"},
+			"count":      {"10"},
+			"start":      {"2026-03-07T00:00:00Z"},
+			"end":        {"2026-03-09T12:00:00Z"},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/datetime/cron?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "America/New_York", data["timezone"])
+		assert.Equal(t, "At 02:30", data["description"])
+		assert.Equal(t, []interface{}{
+			"2026-03-07T02:30:00-05:00", "2026-03-08T03:00:00-04:00", "2026-03-09T02:30:00-04:00",
+		}, data["next_runs"])
+	})
+
+	for _, tc := range []struct{ query, code string }{
+		{"count=0", "INVALID_COUNT"},
+		{"count=abc", "INVALID_COUNT"},
+		{"start=yesterday", "INVALID_TIMESTAMP"},
+		{"timezone=Mars/Olympus", "INVALID_CRON"},
+	} {
+		t.Run("bad "+tc.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/datetime/cron?expression=@daily&"+tc.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tc.code, decodeEnvelope(t, w.Body.Bytes())["error"])
+		})
+	}
 }
 
 func TestAPIDatetimeCalendarHandler(t *testing.T) {
@@ -774,6 +1119,26 @@ func TestAPIDatetimeCalendarHandler(t *testing.T) {
 		env := decodeEnvelope(t, w.Body.Bytes())
 		assert.Equal(t, "INVALID_MONTH", env["error"])
 	})
+
+	t.Run("country holidays", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/calendar/2024/12?country=US", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, []interface{}{map[string]interface{}{"date": "2024-12-25", "name": "Christmas Day"}}, data["holidays"])
+		assert.EqualValues(t, 21, data["business_days"])
+	})
+
+	t.Run("unknown country", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/calendar/2024/12?country=XX", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_CALENDAR", decodeEnvelope(t, w.Body.Bytes())["error"])
+	})
 }
 
 func TestAPIDatetimeWorkdaysHandler(t *testing.T) {
@@ -785,22 +1150,269 @@ func TestAPIDatetimeWorkdaysHandler(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-		env := decodeEnvelope(t, w.Body.Bytes())
-		data, ok := env["data"].(map[string]interface{})
-		require.True(t, ok)
-		assert.NotNil(t, data["workdays"])
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data, ok := env["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.NotNil(t, data["workdays"])
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/workdays/not-a-date/2024-01-31", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		assert.Equal(t, "INVALID_DATE", env["error"])
+	})
+
+	t.Run("holidays and custom weekend", func(t *testing.T) {
+		// Christmas week 2024 in England: Wednesday 25th and Thursday
+		// 26th are holidays, and a Friday/Saturday weekend leaves
+		// Sunday 22nd through Tuesday 24th.
+		req := httptest.NewRequest(http.MethodGet, "/datetime/workdays/2024-12-22/2024-12-28?country=GB&region=ENG&weekend=fri,sat", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.EqualValues(t, 3, data["workdays"])
+		assert.EqualValues(t, 2, data["holiday_days"])
+		assert.Equal(t, []interface{}{"Friday", "Saturday"}, data["weekend"])
+	})
+
+	t.Run("invalid calendar", func(t *testing.T) {
+		for _, query := range []string{"country=ZZ", "country=US&region=XX", "region=CA", "weekend=someday"} {
+			req := httptest.NewRequest(http.MethodGet, "/datetime/workdays/2024-01-01/2024-01-31?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Equal(t, "INVALID_CALENDAR", decodeEnvelope(t, w.Body.Bytes())["error"], query)
+		}
+	})
+}
+
+func TestAPIDatetimeAddWorkdaysHandler(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/datetime/workdays/add/{date}/{days}", apiDatetimeAddWorkdaysHandler)
+
+	t.Run("skips holidays", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/workdays/add/2024-12-24/1?country=US", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "2024-12-26", data["result"])
+		assert.EqualValues(t, 2, data["calendar_days"])
+	})
+
+	t.Run("negative days", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/workdays/add/2024-12-30/-3", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2024-12-25", decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})["result"])
+	})
+
+	tests := []struct {
+		name, path, code string
+	}{
+		{"non-numeric days", "/datetime/workdays/add/2024-12-24/five", "INVALID_DAYS"},
+		{"too many days", "/datetime/workdays/add/2024-12-24/10001", "INVALID_DAYS"},
+		{"invalid date", "/datetime/workdays/add/24-12-2024/1", "INVALID_DATE"},
+		{"invalid calendar", "/datetime/workdays/add/2024-12-24/1?country=ZZ", "INVALID_CALENDAR"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tc.code, decodeEnvelope(t, w.Body.Bytes())["error"])
+		})
+	}
+}
+
+func TestAPIDatetimeHolidaysHandler(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/datetime/holidays", apiDatetimeHolidayCalendarsHandler)
+	r.Get("/datetime/holidays/{country}/{year}", apiDatetimeHolidaysHandler)
+
+	t.Run("calendars", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/holidays", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.EqualValues(t, 8, data["count"])
+	})
+
+	t.Run("country and region", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/datetime/holidays/au/2025?region=vic", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "AU", data["country"])
+		assert.Equal(t, "Victoria", data["region_name"])
+		holidays := data["holidays"].([]interface{})
+		assert.EqualValues(t, len(holidays), data["count"])
+		assert.Equal(t, map[string]interface{}{"date": "2025-01-01", "weekday": "Wednesday", "name": "New Year's Day"}, holidays[0])
+	})
+
+	tests := []struct {
+		name, path, code string
+	}{
+		{"bad year", "/datetime/holidays/US/twenty", "INVALID_YEAR"},
+		{"year out of range", "/datetime/holidays/US/0", "INVALID_YEAR"},
+		{"unknown country", "/datetime/holidays/ZZ/2025", "INVALID_CALENDAR"},
+		{"unknown region", "/datetime/holidays/US/2025?region=ZZ", "INVALID_CALENDAR"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tc.code, decodeEnvelope(t, w.Body.Bytes())["error"])
+		})
+	}
+}
+
+func TestAPIDatetimeRRuleHandler(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/datetime/rrule", apiDatetimeRRuleHandler)
+
+	t.Run("occurrences in a timezone", func(t *testing.T) {
+		q := url.Values{
+			"rule":     {"FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4"},
+			"start":    {"2026-10-20T09:00"},
+			"timezone": {"Europe/Paris"},
+			"exdate":   {"2026-10-22"},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/datetime/rrule?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "Every week on Tuesday and Thursday, 4 times", data["description"])
+		assert.Equal(t, []interface{}{"2026-10-20T09:00:00+02:00", "2026-10-27T09:00:00+01:00", "2026-10-29T09:00:00+01:00"}, data["occurrences"])
+		assert.Equal(t, true, data["finite"])
+	})
+
+	tests := []struct {
+		name, query, code string
+	}{
+		{"bad rule", "rule=FREQ%3DSOMETIMES&start=2026-01-01", "INVALID_RRULE"},
+		{"missing start", "rule=FREQ%3DDAILY", "INVALID_RRULE"},
+		{"bad timezone", "rule=FREQ%3DDAILY&start=2026-01-01&timezone=Mars/Olympus", "INVALID_RRULE"},
+		{"bad count", "rule=FREQ%3DDAILY&start=2026-01-01&count=0", "INVALID_COUNT"},
+		{"bad window", "rule=FREQ%3DDAILY&start=2026-01-01&from=yesterday", "INVALID_TIMESTAMP"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/datetime/rrule?"+tc.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tc.code, decodeEnvelope(t, w.Body.Bytes())["error"])
+		})
+	}
+}
+
+func TestAPIDatetimeICSHandlers(t *testing.T) {
+	r := chi.NewRouter()
+	r.Post("/datetime/ics", apiDatetimeICSHandler)
+	r.Post("/datetime/ics/parse", apiDatetimeICSParseHandler)
+
+	req := httptest.NewRequest(http.MethodPost, "/datetime/ics", strings.NewReader(`{"timezone":"Asia/Tokyo","events":[{"uid":"launch","summary":"Launch","start":"2026-11-02T10:00","rrule":"FREQ=DAILY;COUNT=3"}]}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+	assert.EqualValues(t, 1, data["events"])
+	ics := data["ics"].(string)
+	assert.Contains(t, ics, "DTSTART;TZID=Asia/Tokyo:20261102T100000\r\n")
+
+	req = httptest.NewRequest(http.MethodPost, "/datetime/ics/parse?count=2", strings.NewReader(ics))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	data = decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+	event := data["events"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Launch", event["summary"])
+	assert.Equal(t, []interface{}{"2026-11-02T10:00:00+09:00", "2026-11-03T10:00:00+09:00"}, event["occurrences"])
+
+	tests := []struct {
+		name, path, body, code string
+	}{
+		{"generate bad json", "/datetime/ics", `{`, "INVALID_BODY"},
+		{"generate no components", "/datetime/ics", `{"name":"empty"}`, "ICS_GENERATION_FAILED"},
+		{"parse empty body", "/datetime/ics/parse", ``, "VALIDATION_FAILED"},
+		{"parse bad count", "/datetime/ics/parse?count=500", ics, "INVALID_COUNT"},
+		{"parse not ics", "/datetime/ics/parse", "hello", "PARSE_FAILED"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tc.code, decodeEnvelope(t, w.Body.Bytes())["error"])
+		})
+	}
+}
+
+func TestAPIDatetimeCalendarConvertHandler(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/datetime/calendar-convert", apiDatetimeCalendarConvertHandler)
+
+	t.Run("hebrew to every calendar", func(t *testing.T) {
+		q := url.Values{"value": {"5787-Tishrei-1"}, "from": {"hebrew"}, "timezone": {"Asia/Jerusalem"}, "format": {"date"}}
+		req := httptest.NewRequest(http.MethodGet, "/datetime/calendar-convert?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+		assert.Equal(t, "2026-09-12T00:00:00+03:00", data["datetime"].(map[string]interface{})["iso8601"])
+		assert.Equal(t, "2026-09-12", data["formatted"].(map[string]interface{})["result"])
+		cals := data["calendars"].(map[string]interface{})
+		assert.Equal(t, "1448-03-29", cals["islamic"].(map[string]interface{})["date"])
+		assert.Equal(t, "21 Shahrivar 1405", cals["persian"].(map[string]interface{})["formatted"])
+		assert.Equal(t, "2026-8-2", cals["chinese"].(map[string]interface{})["date"])
+		assert.Equal(t, 2461295.375, data["epochs"].(map[string]interface{})["julian_day"])
 	})
 
-	t.Run("invalid date", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/datetime/workdays/not-a-date/2024-01-31", nil)
-		w := httptest.NewRecorder()
-		r.ServeHTTP(w, req)
+	tests := []struct {
+		name, query string
+	}{
+		{"missing value", "from=unix"},
+		{"unknown calendar", "value=1&from=mayan"},
+		{"invalid date", "value=1404-12-30&from=persian"},
+		{"bad timezone", "value=0&from=unix&timezone=Mars/Olympus"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/datetime/calendar-convert?"+tc.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		env := decodeEnvelope(t, w.Body.Bytes())
-		assert.Equal(t, "INVALID_DATE", env["error"])
-	})
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "INVALID_DATE", decodeEnvelope(t, w.Body.Bytes())["error"])
+		})
+	}
 }
 
 func TestAPIDatetimeSunriseHandler(t *testing.T) {
@@ -1253,6 +1865,51 @@ func TestAPIValidateVATHandler(t *testing.T) {
 	})
 }
 
+func TestAPIValidatePasswordHandler(t *testing.T) {
+	validate := func(body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/validate/password", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		apiValidatePasswordHandler(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	t.Run("missing password", func(t *testing.T) {
+		code, env := validate(`{}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+
+	t.Run("bad min_strength", func(t *testing.T) {
+		code, env := validate(`{"password":"x","min_strength":"excellent"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+
+	t.Run("common password", func(t *testing.T) {
+		code, env := validate(`{"password":"Password1"}`)
+		assert.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "weak", data["strength"])
+		assert.Equal(t, "medium", data["min_strength"])
+		assert.Equal(t, false, data["valid"])
+		assert.NotContains(t, data, "password")
+	})
+
+	t.Run("strong password", func(t *testing.T) {
+		code, env := validate(`{"password":"correcthorsebatterystaple","min_strength":"strong"}`)
+		assert.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "strong", data["strength"])
+		assert.Equal(t, true, data["valid"])
+	})
+
+	t.Run("user inputs", func(t *testing.T) {
+		code, env := validate(`{"password":"Zb8ronwq4kX","user_inputs":["zb8ronwq4kx"]}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, env["data"].(map[string]interface{})["valid"])
+	})
+}
+
 // apiParseJSONHandler must 400 INVALID_JSON for malformed input and 200
 // for a valid document.
 func TestAPIParseJSONHandler(t *testing.T) {
@@ -3703,6 +4360,63 @@ func TestAPICryptoEncryptDecryptHandlers(t *testing.T) {
 		require.True(t, ok)
 		assert.Equal(t, "Hello World", decData["plaintext"])
 	})
+
+	t.Run("protected key signs, clearsigns and verifies", func(t *testing.T) {
+		post := func(body map[string]interface{}) (int, map[string]interface{}) {
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/crypto/pgp", bytes.NewReader(raw))
+			w := httptest.NewRecorder()
+			apiCryptoPGPHandler(w, req)
+			env := decodeEnvelope(t, w.Body.Bytes())
+			data, _ := env["data"].(map[string]interface{})
+			if data == nil {
+				data = env
+			}
+			return w.Code, data
+		}
+
+		code, key := post(map[string]interface{}{"name": "Release Bot", "email": "release@example.com", "passphrase": "s3cret"})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, key["protected"])
+
+		code, data := post(map[string]interface{}{"mode": "sign", "message": "AAEC/w==", "encoding": "base64", "private_key": key["private_key"]})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "SIGN_FAILED", data["error"])
+
+		code, data = post(map[string]interface{}{
+			"mode": "sign", "message": "AAEC/w==", "encoding": "base64", "private_key": key["private_key"], "passphrase": "s3cret",
+		})
+		require.Equal(t, http.StatusOK, code)
+		signature := data["signature"]
+
+		code, data = post(map[string]interface{}{
+			"mode": "verify", "message": "AAEC/w==", "encoding": "base64", "signature": signature, "public_keys": []interface{}{key["public_key"]},
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, "Release Bot <release@example.com>", data["signer"])
+		assert.Len(t, data["key_id"], 16)
+		assert.NotEmpty(t, data["created_at"])
+
+		code, data = post(map[string]interface{}{
+			"mode": "verify", "message": "AAEC/g==", "encoding": "base64", "signature": signature, "public_key": key["public_key"],
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, data["valid"])
+		assert.NotEmpty(t, data["error"])
+
+		code, data = post(map[string]interface{}{"mode": "clearsign", "message": "v1.2.3", "private_key": key["private_key"], "passphrase": "s3cret"})
+		require.Equal(t, http.StatusOK, code)
+		code, data = post(map[string]interface{}{"mode": "verify", "message": data["message"], "public_key": key["public_key"]})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, "v1.2.3", data["message"])
+
+		code, data = post(map[string]interface{}{"mode": "verify", "message": "x"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", data["error"])
+	})
 }
 
 func TestAPICryptoRSAHandler(t *testing.T) {
@@ -3810,6 +4524,575 @@ func TestAPICryptoRSAHandler(t *testing.T) {
 	})
 }
 
+func TestAPICryptoJWTSignVerifyHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	t.Run("HS256 sign then verify", func(t *testing.T) {
+		code, env := post(apiCryptoJWTSignHandler, `{"algorithm":"HS256","key":"secret","claims":{"sub":"alice","aud":"api"},"expires_in":60}`)
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		token := data["token"].(string)
+		assert.NotNil(t, data["claims"].(map[string]interface{})["exp"])
+		assert.Nil(t, data["generated_key"])
+
+		code, env = post(apiCryptoJWTVerifyHandler, `{"token":"`+token+`","key":"secret","audience":"api","algorithms":["HS256"]}`)
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, true, data["signature_valid"])
+
+		code, env = post(apiCryptoJWTVerifyHandler, `{"token":"`+token+`","key":"wrong","audience":"web"}`)
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		assert.Equal(t, false, data["valid"])
+		assert.Len(t, data["errors"], 2)
+	})
+
+	t.Run("generated ES256 keypair", func(t *testing.T) {
+		code, env := post(apiCryptoJWTSignHandler, `{"algorithm":"ES256","claims":{"sub":"bob"}}`)
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		keys := data["generated_key"].(map[string]interface{})
+		pub, err := json.Marshal(keys["public_key"])
+		require.NoError(t, err)
+
+		code, env = post(apiCryptoJWTVerifyHandler, `{"token":"`+data["token"].(string)+`","key":`+string(pub)+`}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, env["data"].(map[string]interface{})["valid"])
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		code, env := post(apiCryptoJWTSignHandler, `{"algorithm":"none","key":"k"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoJWTSignHandler, `{"algorithm":"RS256","key":"not a key"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_KEY", env["error"])
+
+		code, env = post(apiCryptoJWTVerifyHandler, `{"token":"a.b","key":"k"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_JWT", env["error"])
+	})
+}
+
+func TestAPICryptoKeyHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+	const edJWK = `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+
+	t.Run("inspect reports the RFC 7638 thumbprint", func(t *testing.T) {
+		code, env := post(apiCryptoKeyInspectHandler, map[string]string{"key": edJWK})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "Ed25519", data["type"])
+		assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", data["fingerprints"].(map[string]interface{})["jwk_thumbprint"])
+	})
+
+	t.Run("convert JWK to OpenSSH", func(t *testing.T) {
+		code, env := post(apiCryptoKeyConvertHandler, map[string]string{"key": edJWK, "format": "openssh"})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.True(t, strings.HasPrefix(data["key"].(string), "ssh-ed25519 "))
+		assert.Equal(t, false, data["info"].(map[string]interface{})["private"])
+
+		code, env = post(apiCryptoKeyConvertHandler, map[string]string{"key": edJWK, "format": "pkcs1"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "CONVERT_FAILED", env["error"])
+
+		code, env = post(apiCryptoKeyConvertHandler, map[string]string{"key": edJWK, "format": "xml"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+
+	t.Run("JWKS from several keys", func(t *testing.T) {
+		code, env := post(apiCryptoJWKSHandler, map[string]interface{}{"keys": []string{edJWK}})
+		require.Equal(t, http.StatusOK, code)
+		keys := env["data"].(map[string]interface{})["keys"].([]interface{})
+		require.Len(t, keys, 1)
+		assert.Equal(t, "sig", keys[0].(map[string]interface{})["use"])
+
+		code, env = post(apiCryptoJWKSHandler, map[string]interface{}{"keys": []string{}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoJWKSHandler, map[string]interface{}{"keys": []string{"garbage!"}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_KEY", env["error"])
+	})
+}
+
+func TestAPICryptoPasswordHashHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	t.Run("argon2id hash verifies", func(t *testing.T) {
+		code, env := post(apiCryptoPasswordHashHandler, map[string]interface{}{
+			"password": "hunter2",
+			"params":   map[string]int{"memory": 1024, "time": 1, "threads": 1},
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "argon2id", data["algorithm"])
+		hash := data["hash"].(string)
+		assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), hash)
+
+		code, env = post(apiCryptoPasswordVerifyHandler, map[string]string{"password": "hunter2", "hash": hash})
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, "argon2id", data["algorithm"])
+
+		code, env = post(apiCryptoPasswordVerifyHandler, map[string]string{"password": "hunter3", "hash": hash})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, env["data"].(map[string]interface{})["valid"])
+	})
+
+	t.Run("verify detects sha512-crypt", func(t *testing.T) {
+		code, env := post(apiCryptoPasswordVerifyHandler, map[string]string{
+			"password": "Hello world!",
+			"hash":     "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, "sha512-crypt", data["algorithm"])
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		code, env := post(apiCryptoPasswordHashHandler, map[string]interface{}{"password": "pw", "algorithm": "md5"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoPasswordHashHandler, map[string]interface{}{
+			"password": "pw", "algorithm": "bcrypt", "params": map[string]int{"cost": 31},
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_PARAMS", env["error"])
+
+		code, env = post(apiCryptoPasswordVerifyHandler, map[string]string{"password": "pw", "hash": "$9$nope"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_HASH", env["error"])
+	})
+}
+
+func TestAPICryptoMnemonicHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	const abandon = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	t.Run("generate defaults to 12 english words", func(t *testing.T) {
+		code, env := post(apiCryptoMnemonicGenerateHandler, map[string]interface{}{})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, float64(12), data["words"])
+		assert.Equal(t, "english", data["language"])
+		assert.Len(t, strings.Fields(data["mnemonic"].(string)), 12)
+		assert.Len(t, data["entropy"], 32)
+
+		code, env = post(apiCryptoMnemonicGenerateHandler, map[string]interface{}{"words": 24, "language": "spanish"})
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		assert.Equal(t, float64(24), data["words"])
+		assert.Equal(t, "spanish", data["language"])
+	})
+
+	t.Run("generate from entropy", func(t *testing.T) {
+		code, env := post(apiCryptoMnemonicGenerateHandler, map[string]string{"entropy": "00000000000000000000000000000000"})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, abandon, env["data"].(map[string]interface{})["mnemonic"])
+	})
+
+	t.Run("validate detects language and checksum", func(t *testing.T) {
+		code, env := post(apiCryptoMnemonicValidateHandler, map[string]string{"mnemonic": abandon})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, "english", data["language"])
+
+		code, env = post(apiCryptoMnemonicValidateHandler, map[string]string{"mnemonic": strings.Replace(abandon, "about", "abandon", 1)})
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		assert.Equal(t, false, data["valid"])
+		assert.Equal(t, "invalid checksum", data["error"])
+	})
+
+	t.Run("seed and derive", func(t *testing.T) {
+		code, env := post(apiCryptoMnemonicSeedHandler, map[string]string{"mnemonic": abandon, "passphrase": "TREZOR"})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		seed := data["seed"].(string)
+		assert.True(t, strings.HasPrefix(seed, "c55257c360c07c72"), seed)
+		assert.Equal(t, "xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF", data["extended_private_key"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{
+			"mnemonic": abandon, "passphrase": "TREZOR", "path": "m/44'/0'/0'",
+		})
+		require.Equal(t, http.StatusOK, code)
+		fromMnemonic := env["data"].(map[string]interface{})
+		assert.Equal(t, float64(3), fromMnemonic["depth"])
+		assert.Equal(t, "0'", fromMnemonic["child_number"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{"seed": seed, "path": "m/44'/0'/0'"})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, fromMnemonic["extended_private_key"], env["data"].(map[string]interface{})["extended_private_key"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{
+			"extended_key": fromMnemonic["extended_public_key"].(string), "path": "m/0/5",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		assert.Nil(t, data["private_key"])
+		assert.Equal(t, float64(5), data["depth"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{"seed": seed, "path": "m/0", "network": "testnet"})
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, strings.HasPrefix(env["data"].(map[string]interface{})["extended_private_key"].(string), "tprv"))
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		code, env := post(apiCryptoMnemonicGenerateHandler, map[string]interface{}{"words": 13})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoMnemonicSeedHandler, map[string]string{"mnemonic": "zoo zoo zoo"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_MNEMONIC", env["error"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{"mnemonic": abandon, "seed": "00", "path": "m"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{"mnemonic": abandon, "path": "44'/0'"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "DERIVE_FAILED", env["error"])
+
+		code, env = post(apiCryptoMnemonicDeriveHandler, map[string]string{"extended_key": "xpub-nope", "path": "m/0"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_KEY", env["error"])
+	})
+}
+
+func TestAPICryptoOTPHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	// RFC 4226/6238 test secret "12345678901234567890" in base32.
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	t.Run("hotp generate and verify", func(t *testing.T) {
+		code, env := post(apiCryptoHOTPGenerateHandler, map[string]interface{}{"secret": secret, "counter": 1})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "287082", env["data"].(map[string]interface{})["code"])
+
+		code, env = post(apiCryptoHOTPVerifyHandler, map[string]interface{}{"secret": secret, "code": "162583", "counter": 5, "look_ahead": 3})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["valid"])
+		assert.Equal(t, float64(7), data["counter"])
+		assert.Equal(t, float64(8), data["next_counter"])
+
+		code, env = post(apiCryptoHOTPVerifyHandler, map[string]interface{}{"secret": secret, "code": "162583", "counter": 5})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, env["data"].(map[string]interface{})["valid"])
+	})
+
+	t.Run("totp with sha256 at a timestamp", func(t *testing.T) {
+		secret256 := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA"
+		code, env := post(apiCryptoTOTPCodeHandler, map[string]interface{}{
+			"secret": secret256, "algorithm": "sha-256", "digits": 8, "timestamp": 1111111109,
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "68084774", data["code"])
+		assert.Equal(t, "SHA256", data["algorithm"])
+
+		code, env = post(apiCryptoTOTPVerifyHandler, map[string]interface{}{
+			"secret": secret256, "algorithm": "SHA256", "digits": 8, "code": "68084774", "timestamp": 1111111139,
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, env["data"].(map[string]interface{})["valid"])
+
+		code, env = post(apiCryptoTOTPVerifyHandler, map[string]interface{}{
+			"secret": secret256, "algorithm": "SHA256", "digits": 8, "code": "68084774", "timestamp": 1111111139, "window": 0,
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, env["data"].(map[string]interface{})["valid"])
+	})
+
+	t.Run("otpauth parse", func(t *testing.T) {
+		code, env := post(apiCryptoOTPAuthParseHandler, map[string]string{
+			"uri": "otpauth://hotp/ACME:alice?secret=" + secret + "&issuer=ACME&counter=1&algorithm=SHA1",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "hotp", data["type"])
+		assert.Equal(t, "ACME", data["issuer"])
+		assert.Equal(t, "alice", data["account"])
+		assert.Equal(t, float64(6), data["digits"])
+		assert.Equal(t, "287082", data["code"])
+	})
+
+	t.Run("otpauth qr renders a png", func(t *testing.T) {
+		raw, _ := json.Marshal(map[string]interface{}{"secret": secret, "issuer": "ACME", "account": "alice", "algorithm": "SHA512", "size": 128})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		apiCryptoOTPAuthQRHandler(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")))
+		assert.Equal(t, "otpauth://totp/ACME:alice?secret="+secret+"&issuer=ACME&algorithm=SHA512&digits=6&period=30", w.Header().Get("X-OTPAuth-URI"))
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		code, env := post(apiCryptoHOTPVerifyHandler, map[string]interface{}{"secret": secret})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoTOTPCodeHandler, map[string]interface{}{"secret": secret, "algorithm": "md5"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoHOTPGenerateHandler, map[string]interface{}{"secret": "not base32!"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_SECRET", env["error"])
+
+		code, env = post(apiCryptoOTPAuthParseHandler, map[string]string{"uri": "otpauth://totp/alice"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_URI", env["error"])
+
+		code, env = post(apiCryptoOTPAuthQRHandler, map[string]string{"issuer": "ACME"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+}
+
+func TestAPICryptoCipherHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	t.Run("aes-gcm with a raw key and separate tag", func(t *testing.T) {
+		// GCM specification test case 2.
+		zeros := strings.Repeat("00", 16)
+		code, env := post(apiCryptoCipherEncryptHandler, map[string]interface{}{
+			"key": zeros, "iv": strings.Repeat("00", 12), "plaintext": zeros, "plaintext_encoding": "hex",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "aes-gcm", data["algorithm"])
+		assert.Equal(t, "0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf", data["ciphertext"])
+		assert.Equal(t, "ab6e47d42cec13bdf53a67b21257bddf", data["tag"])
+
+		code, env = post(apiCryptoCipherDecryptHandler, map[string]interface{}{
+			"key": zeros, "iv": strings.Repeat("00", 12), "plaintext_encoding": "hex",
+			"ciphertext": "0388dace60b6a392f328c2b971b2fe78", "tag": "ab6e47d42cec13bdf53a67b21257bddf",
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, zeros, env["data"].(map[string]interface{})["plaintext"])
+	})
+
+	t.Run("passphrase with explicit kdf round-trips", func(t *testing.T) {
+		kdf := map[string]interface{}{"algorithm": "pbkdf2-sha256", "params": map[string]interface{}{"iterations": 1000, "key_length": 16}}
+		code, env := post(apiCryptoCipherEncryptHandler, map[string]interface{}{
+			"algorithm": "AES-CBC", "passphrase": "hunter2", "kdf": kdf, "plaintext": "hello", "encoding": "base64",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		used := data["kdf"].(map[string]interface{})
+		assert.Equal(t, "pbkdf2-sha256", used["algorithm"])
+		assert.Len(t, used["salt"], 32)
+		assert.Len(t, data["key"], 32)
+		assert.Nil(t, data["tag"])
+
+		kdf["salt"] = used["salt"]
+		code, env = post(apiCryptoCipherDecryptHandler, map[string]interface{}{
+			"algorithm": "aes-cbc", "passphrase": "hunter2", "kdf": kdf, "encoding": "base64",
+			"ciphertext": data["ciphertext"], "iv": data["iv"],
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "hello", env["data"].(map[string]interface{})["plaintext"])
+
+		delete(kdf, "salt")
+		code, env = post(apiCryptoCipherDecryptHandler, map[string]interface{}{
+			"algorithm": "aes-cbc", "passphrase": "hunter2", "kdf": kdf, "encoding": "base64",
+			"ciphertext": data["ciphertext"], "iv": data["iv"],
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_VALUE", env["error"])
+	})
+
+	t.Run("cipher errors", func(t *testing.T) {
+		key := strings.Repeat("00", 32)
+		code, env := post(apiCryptoCipherEncryptHandler, map[string]interface{}{"key": key, "passphrase": "pw", "plaintext": "x"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, _ = post(apiCryptoCipherEncryptHandler, map[string]interface{}{"algorithm": "rc4", "key": key, "plaintext": "x"})
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, env = post(apiCryptoCipherDecryptHandler, map[string]interface{}{
+			"algorithm": "chacha20-poly1305", "key": key, "iv": strings.Repeat("00", 12), "ciphertext": strings.Repeat("00", 20),
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "DECRYPT_FAILED", env["error"])
+	})
+
+	t.Run("kdf", func(t *testing.T) {
+		code, env := post(apiCryptoKDFHandler, map[string]interface{}{
+			"passphrase": "pw", "algorithm": "scrypt", "salt": "0001020304050607", "params": map[string]interface{}{"ln": 4},
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "0001020304050607", data["salt"])
+		assert.Equal(t, float64(4), data["params"].(map[string]interface{})["ln"])
+		assert.Len(t, data["key"], 64)
+	})
+
+	t.Run("key wrap", func(t *testing.T) {
+		// RFC 3394 section 4.1.
+		kek := "000102030405060708090a0b0c0d0e0f"
+		code, env := post(apiCryptoKeyWrapHandler, map[string]interface{}{"kek": kek, "key": "00112233445566778899aabbccddeeff"})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "AES-KW", data["algorithm"])
+		assert.Equal(t, "1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5", data["wrapped"])
+
+		code, env = post(apiCryptoKeyUnwrapHandler, map[string]interface{}{"kek": kek, "wrapped": data["wrapped"]})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "00112233445566778899aabbccddeeff", env["data"].(map[string]interface{})["key"])
+
+		code, env = post(apiCryptoKeyUnwrapHandler, map[string]interface{}{"kek": kek, "wrapped": data["wrapped"], "padding": true})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "UNWRAP_FAILED", env["error"])
+	})
+
+	t.Run("age keygen, encrypt and decrypt", func(t *testing.T) {
+		code, env := post(apiCryptoAgeKeygenHandler, nil)
+		require.Equal(t, http.StatusOK, code)
+		pair := env["data"].(map[string]interface{})
+
+		code, env = post(apiCryptoAgeEncryptHandler, map[string]interface{}{"plaintext": "hello", "recipients": []interface{}{pair["recipient"]}})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["armored"])
+		assert.True(t, strings.HasPrefix(data["ciphertext"].(string), "-----BEGIN AGE ENCRYPTED FILE-----"))
+
+		code, env = post(apiCryptoAgeDecryptHandler, map[string]interface{}{"ciphertext": data["ciphertext"], "identities": []interface{}{pair["identity"]}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "hello", env["data"].(map[string]interface{})["plaintext"])
+
+		code, env = post(apiCryptoAgeEncryptHandler, map[string]interface{}{"plaintext": "hello", "passphrase": "pw", "work_factor": 8, "armor": false})
+		require.Equal(t, http.StatusOK, code)
+		data = env["data"].(map[string]interface{})
+		code, env = post(apiCryptoAgeDecryptHandler, map[string]interface{}{"ciphertext": data["ciphertext"], "passphrase": "pw"})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "hello", env["data"].(map[string]interface{})["plaintext"])
+
+		code, env = post(apiCryptoAgeEncryptHandler, map[string]interface{}{"plaintext": "hello"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+}
+
+func TestAPICryptoShamirHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+
+	t.Run("split and combine with mnemonic shares", func(t *testing.T) {
+		code, env := post(apiCryptoShamirSplitHandler, map[string]interface{}{
+			"secret": "root-password", "shares": 5, "threshold": 3, "encoding": "mnemonic",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		shares := data["shares"].([]interface{})
+		require.Len(t, shares, 5)
+		assert.Equal(t, float64(3), data["threshold"])
+		assert.Contains(t, shares[0], " ")
+
+		code, env = post(apiCryptoShamirCombineHandler, map[string]interface{}{
+			"shares": []interface{}{shares[4], shares[0], shares[2]}, "encoding": "mnemonic",
+		})
+		require.Equal(t, http.StatusOK, code)
+		combined := env["data"].(map[string]interface{})
+		assert.Equal(t, "root-password", combined["secret"])
+		assert.Equal(t, data["id"], combined["id"])
+
+		code, env = post(apiCryptoShamirCombineHandler, map[string]interface{}{
+			"shares": []interface{}{shares[0], shares[1]}, "encoding": "mnemonic",
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_SHARE", env["error"])
+	})
+
+	t.Run("hex secret and a corrupted share", func(t *testing.T) {
+		code, env := post(apiCryptoShamirSplitHandler, map[string]interface{}{
+			"secret": "00ff", "secret_encoding": "hex", "shares": 3, "threshold": 2,
+		})
+		require.Equal(t, http.StatusOK, code)
+		shares := env["data"].(map[string]interface{})["shares"].([]interface{})
+
+		code, env = post(apiCryptoShamirCombineHandler, map[string]interface{}{"shares": []interface{}{shares[0], shares[2]}})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "00ff", data["secret"])
+		assert.Equal(t, "hex", data["secret_encoding"])
+
+		bad := []byte(shares[1].(string))
+		bad[20] ^= 1
+		code, env = post(apiCryptoShamirCombineHandler, map[string]interface{}{"shares": []interface{}{shares[0], string(bad)}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_SHARE", env["error"])
+	})
+
+	t.Run("threshold above share count fails validation", func(t *testing.T) {
+		code, env := post(apiCryptoShamirSplitHandler, map[string]interface{}{"secret": "x", "shares": 2, "threshold": 3})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+}
+
 func TestAPICryptoHMACHandler(t *testing.T) {
 	t.Run("missing key", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/crypto/hmac", strings.NewReader(`{"message":"hi"}`))
@@ -3844,6 +5127,16 @@ func TestAPICryptoHMACHandler(t *testing.T) {
 		assert.NotEmpty(t, data["hmac"])
 	})
 
+	t.Run("keyed blake2b", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/crypto/hmac", strings.NewReader(`{"algorithm":"blake2b-256","key":"key","message":"hello world"}`))
+		w := httptest.NewRecorder()
+		apiCryptoHMACHandler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		env := decodeEnvelope(t, w.Body.Bytes())
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, "70e4d5080ea1c43926e3bab251812dec5fa0d53687e9f7d59c6be6474913928a", data["hmac"])
+	})
+
 	t.Run("invalid algorithm", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/crypto/hmac", strings.NewReader(`{"algorithm":"md5","key":"secret","message":"hi"}`))
 		w := httptest.NewRecorder()
@@ -3898,6 +5191,10 @@ func TestAPICryptoCertificateHandler(t *testing.T) {
 		parseData, ok := parseEnv["data"].(map[string]interface{})
 		require.True(t, ok)
 		assert.Contains(t, parseData["subject"], "example.com")
+		assert.Len(t, parseData["fingerprint_sha256"], 64)
+		keyInfo := parseData["public_key"].(map[string]interface{})
+		assert.Equal(t, "RSA", keyInfo["type"])
+		assert.Equal(t, 2048.0, keyInfo["bits"])
 	})
 
 	t.Run("parse invalid pem", func(t *testing.T) {
@@ -3910,6 +5207,98 @@ func TestAPICryptoCertificateHandler(t *testing.T) {
 	})
 }
 
+func TestAPICryptoCertificateToolkitHandlers(t *testing.T) {
+	post := func(h http.HandlerFunc, body interface{}) (int, map[string]interface{}) {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code, decodeEnvelope(t, w.Body.Bytes())
+	}
+	code, env := post(apiCryptoCertificateHandler, map[string]interface{}{"common_name": "Toolkit Root", "valid_days": 30})
+	require.Equal(t, http.StatusOK, code)
+	root := env["data"].(map[string]interface{})
+	caPEM, caKey := root["certificate"].(string), root["private_key"].(string)
+
+	code, env = post(apiCryptoCSRHandler, map[string]interface{}{
+		"subject":       map[string]string{"common_name": "api.example.com", "organization": "Example"},
+		"dns_names":     []string{"api.example.com"},
+		"ext_key_usage": []string{"server_auth"},
+	})
+	require.Equal(t, http.StatusOK, code)
+	csr := env["data"].(map[string]interface{})
+	require.NotEmpty(t, csr["private_key"])
+	info := csr["info"].(map[string]interface{})
+	assert.Equal(t, true, info["signature_valid"])
+	assert.Equal(t, "CN=api.example.com,O=Example", info["subject"])
+
+	code, env = post(apiCryptoCertificateSignHandler, map[string]interface{}{
+		"csr": csr["csr"], "ca_certificate": caPEM, "ca_key": caKey, "valid_days": 10,
+	})
+	require.Equal(t, http.StatusOK, code)
+	signed := env["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"server_auth"}, signed["info"].(map[string]interface{})["ext_key_usage"])
+
+	t.Run("verify chain", func(t *testing.T) {
+		code, env := post(apiCryptoCertificateVerifyHandler, map[string]interface{}{
+			"chain": []string{signed["certificate"].(string)}, "roots": caPEM, "dns_name": "api.example.com",
+		})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, true, data["valid"])
+		assert.Len(t, data["chains"].([]interface{})[0], 2)
+
+		code, env = post(apiCryptoCertificateVerifyHandler, map[string]interface{}{
+			"chain": []string{signed["certificate"].(string)}, "roots": caPEM, "at": "2000-01-01T00:00:00Z",
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, env["data"].(map[string]interface{})["valid"])
+
+		code, env = post(apiCryptoCertificateVerifyHandler, map[string]interface{}{"chain": []string{}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+	})
+
+	t.Run("pkcs12 round trip", func(t *testing.T) {
+		code, env := post(apiCryptoPKCS12Handler, map[string]interface{}{
+			"private_key": csr["private_key"], "certificate": signed["certificate"].(string) + caPEM, "password": "pw",
+		})
+		require.Equal(t, http.StatusOK, code)
+		pfx := env["data"].(map[string]interface{})["pkcs12"]
+
+		code, env = post(apiCryptoPKCS12DecodeHandler, map[string]interface{}{"pkcs12": pfx, "password": "pw"})
+		require.Equal(t, http.StatusOK, code)
+		data := env["data"].(map[string]interface{})
+		assert.Equal(t, signed["certificate"], data["certificate"])
+		assert.Len(t, data["ca_certificates"], 1)
+
+		code, env = post(apiCryptoPKCS12DecodeHandler, map[string]interface{}{"pkcs12": pfx, "password": "nope"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "PKCS12_FAILED", env["error"])
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		code, env := post(apiCryptoCertificateSignHandler, map[string]interface{}{
+			"csr": csr["csr"], "ca_certificate": signed["certificate"], "ca_key": csr["private_key"],
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "SIGN_FAILED", env["error"])
+
+		code, env = post(apiCryptoCSRHandler, map[string]interface{}{"subject": map[string]string{"common_name": "x"}, "key_type": "dsa"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "VALIDATION_FAILED", env["error"])
+
+		code, env = post(apiCryptoCRLDecodeHandler, map[string]string{"crl": "not a crl"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "PARSE_FAILED", env["error"])
+	})
+
+	code, env = post(apiCryptoCertificateDecodeHandler, map[string]string{"certificate": signed["certificate"].(string) + caPEM})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2.0, env["data"].(map[string]interface{})["count"])
+}
+
 func TestAPICryptoEd25519Handler(t *testing.T) {
 	t.Run("generate", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/crypto/ed25519", strings.NewReader(`{"mode":"generate"}`))
@@ -4469,6 +5858,21 @@ func TestAPIGenerateSSHKeyHandler(t *testing.T) {
 	require.True(t, ok)
 	assert.Contains(t, data["private_key"], "BEGIN OPENSSH PRIVATE KEY")
 	assert.Contains(t, data["public_key"], "ssh-ed25519")
+	assert.Contains(t, data["fingerprints"].(map[string]interface{})["ssh_sha256"], "SHA256:")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/generate/ssh-key?format=jwk", nil)
+	w = httptest.NewRecorder()
+	apiGenerateSSHKeyHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	data = decodeEnvelope(t, w.Body.Bytes())["data"].(map[string]interface{})
+	assert.Contains(t, data["private_key"], `"d":`)
+	assert.Contains(t, data["public_key"], `"kty":"OKP"`)
+	assert.NotContains(t, data["public_key"], `"d":`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/generate/ssh-key?format=ppk", nil)
+	w = httptest.NewRecorder()
+	apiGenerateSSHKeyHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestAPIGenerateAPIDocsHandler(t *testing.T) {
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/apimgr/api/src/config"
+	"github.com/go-chi/chi/v5"
+)
+
+// batchPath is the route of apiBatchHandler. RateLimitMiddleware counts a
+// batch differently from other requests, see batchRateLimit.
+const batchPath = "/api/v1/batch"
+
+// batchSubRequestKey marks a request dispatched by apiBatchHandler.
+const batchSubRequestKey contextKey = "batchSubRequest"
+
+// isBatchSubRequest reports whether r is one entry of a batch rather than
+// a request made by a client directly.
+func isBatchSubRequest(ctx context.Context) bool {
+	sub, _ := ctx.Value(batchSubRequestKey).(bool)
+	return sub
+}
+
+// batchRequest is one entry of a POST /api/v1/batch body. Method defaults
+// to GET. Path must be an /api/v1 route and may carry its own query
+// string, which Query entries are added to (a value may be a string,
+// number, boolean or a list of them). Body is sent as-is when it is a
+// JSON string and as application/json otherwise.
+type batchRequest struct {
+	Method string                     `json:"method"`
+	Path   string                     `json:"path"`
+	Query  map[string]json.RawMessage `json:"query,omitempty"`
+	Body   json.RawMessage            `json:"body,omitempty"`
+}
+
+// batchResponse is the result of one batchRequest, at the same index as
+// the request. Body is the sub-request's JSON response embedded as-is, or
+// its text for any other content type.
+type batchResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body"`
+}
+
+// batchResponseHeaders are the sub-response headers a batch passes on;
+// the rest (security headers, CORS, request IDs) describe the batch as a
+// whole rather than one entry.
+var batchResponseHeaders = []string{"Content-Type", "Retry-After", "X-Cache", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// apiBatchHandler runs many API calls in one request: it dispatches each
+// entry of a JSON array of {method, path, query, body} sub-requests
+// through the router in-process, server.batch.concurrency at a time, and
+// returns their status, headers and body in request order. A batch holds
+// at most server.batch.max_requests entries and may not contain another
+// batch. The batch itself counts once against the global_burst ceiling;
+// each entry counts against its own read/write rate limit class.
+func apiBatchHandler(cfg *config.Config, router chi.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var reqs []batchRequest
+		if err := decodeJSONBody(r, &reqs); err != nil {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_JSON", "Request body must be a JSON array of {method, path, query, body} objects", nil)
+			return
+		}
+		if len(reqs) == 0 {
+			writeEnvelopeError(w, http.StatusBadRequest, "INVALID_INPUT", "Batch must contain at least one request", nil)
+			return
+		}
+		if limit := cfg.Server.Batch.MaxRequests; len(reqs) > limit {
+			writeEnvelopeError(w, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE",
+				fmt.Sprintf("Batch contains %d requests; the maximum is %d", len(reqs), limit),
+				map[string]interface{}{"max_requests": limit})
+			return
+		}
+
+		concurrency := max(cfg.Server.Batch.Concurrency, 1)
+		results := make([]batchResponse, len(reqs))
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, req := range reqs {
+			sub, err := newBatchSubRequest(r, i, req)
+			if err != nil {
+				results[i] = batchErrorResponse(http.StatusBadRequest, "INVALID_INPUT", err.Error())
+				continue
+			}
+			wg.Go(func() {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				results[i] = dispatchBatchRequest(router, sub)
+			})
+		}
+		wg.Wait()
+
+		writeEnvelopeOK(w, http.StatusOK, results)
+	}
+}
+
+// newBatchSubRequest builds the in-process request for entry i of the
+// batch r, checking that it targets an API route other than the batch
+// endpoint itself.
+func newBatchSubRequest(r *http.Request, i int, req batchRequest) (*http.Request, error) {
+	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("unsupported method %q", req.Method)
+	}
+
+	target, err := url.Parse(req.Path)
+	if err != nil || target.IsAbs() || target.Host != "" {
+		return nil, fmt.Errorf("path must be an /api/v1/ route, got %q", req.Path)
+	}
+	if !strings.HasPrefix(target.Path, "/api/v1/") {
+		return nil, fmt.Errorf("path must be an /api/v1/ route, got %q", req.Path)
+	}
+	if target.Path == batchPath {
+		return nil, fmt.Errorf("a batch cannot contain another batch")
+	}
+	query := target.Query()
+	for name, raw := range req.Query {
+		values, err := batchQueryValues(raw)
+		if err != nil {
+			return nil, fmt.Errorf("query parameter %q: %v", name, err)
+		}
+		for _, v := range values {
+			query.Add(name, v)
+		}
+	}
+	target.RawQuery = query.Encode()
+
+	var body []byte
+	contentType := ""
+	if len(req.Body) > 0 && string(req.Body) != "null" {
+		var text string
+		if err := json.Unmarshal(req.Body, &text); err == nil {
+			body, contentType = []byte(text), "text/plain; charset=utf-8"
+		} else {
+			body, contentType = req.Body, "application/json"
+		}
+	}
+
+	// Keep the batch's context for cancellation and request-scoped values,
+	// but drop its chi routing state so the router matches the entry's
+	// own path.
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, nil)
+	ctx = context.WithValue(ctx, batchSubRequestKey, true)
+	sub, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	// The entry runs as the batch's client: same resolved IP (so it is
+	// rate limited as that client), language and user agent, with a
+	// request ID that ties its log lines to the batch.
+	sub.RemoteAddr = r.RemoteAddr
+	for _, name := range []string{"Accept", "Accept-Language", "User-Agent", "DNT", "Sec-GPC"} {
+		if v := r.Header.Get(name); v != "" {
+			sub.Header.Set(name, v)
+		}
+	}
+	if id := RequestIDFromContext(r.Context()); id != "" {
+		sub.Header.Set("X-Request-ID", fmt.Sprintf("%s-%d", id, i))
+	}
+	if contentType != "" {
+		sub.Header.Set("Content-Type", contentType)
+	}
+	return sub, nil
+}
+
+// batchQueryValues converts one "query" entry to its string values.
+func batchQueryValues(raw json.RawMessage) ([]string, error) {
+	var list []interface{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var single interface{}
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, err
+		}
+		list = []interface{}{single}
+	}
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		switch v := v.(type) {
+		case string:
+			values = append(values, v)
+		case float64, bool:
+			values = append(values, fmt.Sprint(v))
+		default:
+			return nil, fmt.Errorf("must be a string, number, boolean or a list of them")
+		}
+	}
+	return values, nil
+}
+
+// dispatchBatchRequest serves sub through the router and captures its
+// response.
+func dispatchBatchRequest(router http.Handler, sub *http.Request) batchResponse {
+	rec := newBatchRecorder()
+	router.ServeHTTP(rec, sub)
+
+	resp := batchResponse{Status: rec.status, Headers: map[string]string{}}
+	for _, name := range batchResponseHeaders {
+		if v := rec.header.Get(name); v != "" {
+			resp.Headers[name] = v
+		}
+	}
+	body := rec.body.Bytes()
+	if strings.HasPrefix(rec.header.Get("Content-Type"), "application/json") && json.Valid(body) {
+		resp.Body = json.RawMessage(bytes.TrimSpace(body))
+	} else {
+		resp.Body = string(body)
+	}
+	return resp
+}
+
+// batchErrorResponse is the entry for a sub-request that could not be
+// dispatched, shaped like the error envelope the API would have written.
+func batchErrorResponse(status int, code, message string) batchResponse {
+	return batchResponse{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body: map[string]interface{}{
+			"ok":      false,
+			"error":   code,
+			"message": message,
+		},
+	}
+}
+
+// batchRecorder is the http.ResponseWriter a sub-request is served into.
+type batchRecorder struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: http.Header{}, status: http.StatusOK}
+}
+
+func (b *batchRecorder) Header() http.Header {
+	return b.header
+}
+
+func (b *batchRecorder) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+func (b *batchRecorder) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
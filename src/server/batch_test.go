@@ -0,0 +1,190 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchResult is one decoded entry of a batch response.
+type batchResult struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+// postBatch sends body to /api/v1/batch on ts and decodes the envelope.
+func postBatch(t *testing.T, ts *httptest.Server, body string) (int, []batchResult, map[string]interface{}) {
+	t.Helper()
+	resp, err := http.Post(ts.URL+batchPath, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var envelope struct {
+		OK      bool                   `json:"ok"`
+		Data    []batchResult          `json:"data"`
+		Error   string                 `json:"error"`
+		Details map[string]interface{} `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+	if !envelope.OK {
+		return resp.StatusCode, nil, map[string]interface{}{"error": envelope.Error, "details": envelope.Details}
+	}
+	return resp.StatusCode, envelope.Data, nil
+}
+
+// TestAPIBatchHandler drives POST /api/v1/batch through the real router:
+// entries run in order with their own status and body, invalid entries
+// fail on their own, and the batch size is capped.
+func TestAPIBatchHandler(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Server.RateLimit.Read.Requests = 1000
+	cfg.Server.RateLimit.Write.Requests = 1000
+	ts := httptest.NewServer(newTestServer(t, cfg).Handler)
+	defer ts.Close()
+
+	t.Run("entries are dispatched and returned in order", func(t *testing.T) {
+		status, results, _ := postBatch(t, ts, `[
+			{"path": "/api/v1/text/hash/sha256/abc"},
+			{"method": "get", "path": "/api/v1/text/uuid", "query": {"unused": [1, true]}},
+			{"method": "POST", "path": "/api/v1/text/stats", "body": {"text": "two words"}},
+			{"path": "/api/v1/text/uuid.txt"},
+			{"path": "/api/v1/does/not/exist"}
+		]`)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, results, 5)
+
+		assert.Equal(t, http.StatusOK, results[0].Status)
+		assert.Contains(t, string(results[0].Body), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")
+		assert.Equal(t, http.StatusOK, results[1].Status)
+		assert.Equal(t, http.StatusOK, results[2].Status)
+		assert.Contains(t, string(results[2].Body), `"words"`)
+		assert.Equal(t, http.StatusOK, results[3].Status)
+		assert.True(t, strings.HasPrefix(results[3].Headers["Content-Type"], "text/plain"))
+		var uuid string
+		require.NoError(t, json.Unmarshal(results[3].Body, &uuid))
+		assert.Len(t, strings.TrimSpace(uuid), 36)
+		assert.Equal(t, http.StatusNotFound, results[4].Status)
+	})
+
+	t.Run("invalid entries fail without failing the batch", func(t *testing.T) {
+		status, results, _ := postBatch(t, ts, `[
+			{"path": "/server/about"},
+			{"path": "https://example.com/api/v1/text/uuid"},
+			{"method": "POST", "path": "/api/v1/batch", "body": []},
+			{"method": "TRACE", "path": "/api/v1/text/uuid"},
+			{"path": "/api/v1/text/uuid", "query": {"x": {"nested": true}}},
+			{"path": "/api/v1/text/uuid"}
+		]`)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, results, 6)
+		for _, r := range results[:5] {
+			assert.Equal(t, http.StatusBadRequest, r.Status, string(r.Body))
+			assert.Contains(t, string(r.Body), `"INVALID_INPUT"`)
+		}
+		assert.Equal(t, http.StatusOK, results[5].Status)
+	})
+
+	t.Run("body must be a non-empty array within max_requests", func(t *testing.T) {
+		status, _, errBody := postBatch(t, ts, `{"path": "/api/v1/text/uuid"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "INVALID_JSON", errBody["error"])
+
+		status, _, errBody = postBatch(t, ts, `[]`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "INVALID_INPUT", errBody["error"])
+
+		entries := make([]string, cfg.Server.Batch.MaxRequests+1)
+		for i := range entries {
+			entries[i] = `{"path": "/api/v1/text/uuid"}`
+		}
+		status, _, errBody = postBatch(t, ts, "["+strings.Join(entries, ",")+"]")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.Equal(t, "BATCH_TOO_LARGE", errBody["error"])
+		assert.Equal(t, float64(cfg.Server.Batch.MaxRequests), errBody["details"].(map[string]interface{})["max_requests"])
+	})
+}
+
+// TestAPIBatchHandler_RateLimit checks that a batch costs one global_burst
+// request and no write quota, while each entry is charged to its own
+// class.
+func TestAPIBatchHandler_RateLimit(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Server.RateLimit.Read.Requests = 2
+	cfg.Server.RateLimit.Write.Requests = 1
+	cfg.Server.RateLimit.GlobalBurst = 3
+	ts := httptest.NewServer(newTestServer(t, cfg).Handler)
+	defer ts.Close()
+
+	body := `[{"path": "/api/v1/text/uuid"}, {"path": "/api/v1/text/uuid"}, {"path": "/api/v1/text/uuid"}]`
+	status, results, _ := postBatch(t, ts, body)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, results, 3)
+
+	limited := 0
+	for _, r := range results {
+		if r.Status == http.StatusTooManyRequests {
+			limited++
+			assert.NotEmpty(t, r.Headers["Retry-After"])
+		}
+	}
+	assert.Equal(t, 1, limited, "only the third read exceeds the read quota of 2")
+
+	// Neither batch was charged to the single-request write class, and
+	// three entries did not use up the global ceiling of 3.
+	status, results, _ = postBatch(t, ts, `[{"method": "POST", "path": "/api/v1/text/stats", "body": {"text": "a"}}]`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, http.StatusOK, results[0].Status)
+}
+
+// TestBatchRateLimit covers which limits RateLimitMiddleware applies to a
+// batch, its entries and ordinary requests.
+func TestBatchRateLimit(t *testing.T) {
+	plain := httptest.NewRequest(http.MethodPost, "/api/v1/text/stats", nil)
+	global, class := batchRateLimit(plain)
+	assert.True(t, global)
+	assert.True(t, class)
+
+	batch := httptest.NewRequest(http.MethodPost, batchPath, nil)
+	global, class = batchRateLimit(batch)
+	assert.True(t, global)
+	assert.False(t, class)
+
+	sub, err := newBatchSubRequest(batch, 0, batchRequest{Path: "/api/v1/text/uuid"})
+	require.NoError(t, err)
+	global, class = batchRateLimit(sub)
+	assert.False(t, global)
+	assert.True(t, class)
+}
+
+// newBatchSubRequest must carry the batch's client address, query
+// parameters and body over to the entry.
+func TestNewBatchSubRequest(t *testing.T) {
+	batch := httptest.NewRequest(http.MethodPost, batchPath, bytes.NewReader(nil))
+	batch.RemoteAddr = "203.0.113.7:4000"
+	batch.Header.Set("Accept-Language", "de")
+
+	sub, err := newBatchSubRequest(batch, 3, batchRequest{
+		Method: "post",
+		Path:   "/api/v1/text/stats?a=1",
+		Query:  map[string]json.RawMessage{"a": json.RawMessage(`2`), "b": json.RawMessage(`["x", "y"]`)},
+		Body:   json.RawMessage(`"raw text"`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, sub.Method)
+	assert.Equal(t, "203.0.113.7:4000", sub.RemoteAddr)
+	assert.Equal(t, "de", sub.Header.Get("Accept-Language"))
+	assert.Equal(t, []string{"1", "2"}, sub.URL.Query()["a"])
+	assert.Equal(t, []string{"x", "y"}, sub.URL.Query()["b"])
+	assert.True(t, strings.HasPrefix(sub.Header.Get("Content-Type"), "text/plain"))
+	raw := new(bytes.Buffer)
+	_, err = raw.ReadFrom(sub.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "raw text", raw.String())
+}
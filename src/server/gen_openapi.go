@@ -0,0 +1,783 @@
+//go:build ignore
+
+// gen_openapi.go writes openapi_docs.go: the swagger.HandlerDoc for every
+// handler in this package, derived from the handler source so the OpenAPI
+// spec cannot drift from it. It type-checks the package and records, per
+// handler (following calls into package helpers that receive the
+// ResponseWriter or Request):
+//
+//   - the doc comment, as summary and description
+//   - validateStruct param structs, decodeJSONBody body types, raw body
+//     reads, query parameters and multipart form fields
+//   - the success writer (writeEnvelopeOK, jsonResponse, textResponse or a
+//     fixed Content-Type) with its payload type and status
+//   - every constant error status it can write
+//
+// Run via `go generate ./src/server`.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const outputFile = "openapi_docs.go"
+
+// listedPackage is the subset of `go list -json` output used here.
+type listedPackage struct {
+	Dir        string
+	ImportPath string
+	Name       string
+	GoFiles    []string
+	Export     string
+}
+
+// facts are what one function contributes to its handler's documentation.
+type facts struct {
+	params      []types.Type
+	query       []string
+	body        types.Type
+	rawBody     bool
+	formFields  []string
+	formFiles   []string
+	format      string
+	contentType string
+	response    types.Type
+	success     int64
+	errors      map[int64]bool
+	legacy      bool
+	page        bool
+	callees     []*types.Func
+}
+
+func main() {
+	pkgs, err := goList()
+	if err != nil {
+		log.Fatal(err)
+	}
+	self := pkgs[len(pkgs)-1]
+	exports := make(map[string]string, len(pkgs))
+	for _, p := range pkgs {
+		exports[p.ImportPath] = p.Export
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range self.GoFiles {
+		if name == outputFile {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(self.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files = append(files, f)
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+			export, ok := exports[path]
+			if !ok || export == "" {
+				return nil, fmt.Errorf("no export data for %s", path)
+			}
+			return os.Open(export)
+		}),
+		// openapi_docs.go is excluded, so its handlerDocs is undeclared
+		Error: func(err error) {
+			if !strings.Contains(err.Error(), "handlerDocs") {
+				log.Fatal(err)
+			}
+		},
+	}
+	pkg, _ := conf.Check(self.ImportPath, fset, files, info)
+
+	g := &generator{
+		pkg:     pkg,
+		info:    info,
+		funcs:   make(map[*types.Func]*facts),
+		aliases: importAliases(files),
+		imports: make(map[string]string),
+	}
+
+	var handlers []*ast.FuncDecl
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || fd.Body == nil {
+				continue
+			}
+			obj := info.Defs[fd.Name].(*types.Func)
+			g.funcs[obj] = g.collect(fd)
+			if isHandler(obj) {
+				handlers = append(handlers, fd)
+			}
+		}
+	}
+	sort.Slice(handlers, func(i, j int) bool { return handlers[i].Name.Name < handlers[j].Name.Name })
+
+	var entries bytes.Buffer
+	for _, fd := range handlers {
+		obj := info.Defs[fd.Name].(*types.Func)
+		merged := g.merge(obj, make(map[*types.Func]bool))
+		if merged.page {
+			// HTML pages are not part of the API spec
+			continue
+		}
+		g.writeEntry(&entries, fd, merged)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by gen_openapi.go; DO NOT EDIT.\n\npackage server\n\nimport (\n")
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&out, "\t%s %q\n", g.imports[path], path)
+	}
+	out.WriteString("\t\"github.com/apimgr/api/src/swagger\"\n)\n\n")
+	out.WriteString("// handlerDocs documents every handler in this package for the OpenAPI\n")
+	out.WriteString("// spec, keyed by function name. Regenerate with `go generate ./src/server`.\n")
+	out.WriteString("var handlerDocs = map[string]swagger.HandlerDoc{\n")
+	out.Write(entries.Bytes())
+	out.WriteString("}\n")
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("formatting output: %v\n%s", err, out.Bytes())
+	}
+	if err := os.WriteFile(filepath.Join(self.Dir, outputFile), src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// goList returns this package's dependencies with export data, ending with
+// the package itself.
+func goList() ([]listedPackage, error) {
+	cmd := exec.Command("go", "list", "-e", "-export", "-deps", "-json", ".")
+	cmd.Stderr = os.Stderr
+	raw, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+	var pkgs []listedPackage
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	for dec.More() {
+		var p listedPackage
+		if err := dec.Decode(&p); err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, p)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("go list returned no packages")
+	}
+	return pkgs, nil
+}
+
+// importAliases records the names this package's files import packages
+// under, so the output uses the same ones (e.g. svcvalidate).
+func importAliases(files []*ast.File) map[string]string {
+	aliases := make(map[string]string)
+	for _, f := range files {
+		for _, spec := range f.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if spec.Name != nil && spec.Name.Name != "_" && spec.Name.Name != "." {
+				aliases[path] = spec.Name.Name
+			}
+		}
+	}
+	return aliases
+}
+
+// isHandler reports whether fn is an http handler function or a factory
+// returning one (not middleware).
+func isHandler(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() == 2 &&
+		isNamed(sig.Params().At(0).Type(), "net/http", "ResponseWriter") &&
+		isPointerTo(sig.Params().At(1).Type(), "net/http", "Request") {
+		return true
+	}
+	if sig.Results().Len() != 1 {
+		return false
+	}
+	// Middleware also returns a Handler, but wraps one
+	for i := 0; i < sig.Params().Len(); i++ {
+		if isNamed(sig.Params().At(i).Type(), "net/http", "Handler") {
+			return false
+		}
+	}
+	t := sig.Results().At(0).Type()
+	return isNamed(t, "net/http", "HandlerFunc") || isNamed(t, "net/http", "Handler")
+}
+
+func isNamed(t types.Type, pkg, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == pkg && n.Obj().Name() == name
+}
+
+func isPointerTo(t types.Type, pkg, name string) bool {
+	p, ok := t.(*types.Pointer)
+	return ok && isNamed(p.Elem(), pkg, name)
+}
+
+type generator struct {
+	pkg     *types.Package
+	info    *types.Info
+	funcs   map[*types.Func]*facts
+	aliases map[string]string
+	imports map[string]string
+}
+
+// collect records the facts of one function body, including closures.
+func (g *generator) collect(fd *ast.FuncDecl) *facts {
+	f := &facts{errors: make(map[int64]bool)}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := g.callee(call)
+		if fn == nil {
+			return true
+		}
+
+		switch {
+		case fn.Pkg() == g.pkg:
+			g.collectLocalCall(f, fn, call)
+		case fn.Pkg().Path() == "net/url" && fn.Name() == "Get" && isRecv(fn, "net/url", "Values"):
+			f.query = appendConst(f.query, g.constString(call, 0))
+		case fn.Pkg().Path() == "net/http" && isRecv(fn, "net/http", "Request"):
+			switch fn.Name() {
+			case "FormValue", "PostFormValue":
+				f.formFields = appendConst(f.formFields, g.constString(call, 0))
+			case "FormFile":
+				f.formFiles = appendConst(f.formFiles, g.constString(call, 0))
+			}
+		case fn.Pkg().Path() == "net/http" && fn.Name() == "Set" && isRecv(fn, "net/http", "Header"):
+			if g.constString(call, 0) == "Content-Type" && f.contentType == "" {
+				f.contentType = g.contentType(call)
+			}
+		case fn.Pkg().Path() == "net/http" && fn.Name() == "WriteHeader":
+			if code, ok := g.constInt(call, 0); ok && code >= 400 {
+				f.errors[code] = true
+			}
+		case fn.Pkg().Path() == "net/http" && fn.Name() == "Error":
+			if code, ok := g.constInt(call, 2); ok {
+				f.errors[code] = true
+			}
+		case fn.Pkg().Path() == "io" && fn.Name() == "ReadAll":
+			if len(call.Args) == 1 && g.readsRequestBody(call.Args[0]) {
+				f.rawBody = true
+			}
+		}
+		return true
+	})
+	return f
+}
+
+// collectLocalCall handles a call into this package: the response and
+// validation helpers, or any other function receiving the handler's
+// ResponseWriter or Request, whose facts are merged in later.
+func (g *generator) collectLocalCall(f *facts, fn *types.Func, call *ast.CallExpr) {
+	switch fn.Name() {
+	case "validateStruct":
+		if t := g.argType(call, 1); t != nil {
+			f.params = append(f.params, t)
+		}
+		f.errors[400] = true
+	case "decodeJSONBody":
+		if t := g.argType(call, 1); t != nil && f.body == nil {
+			if p, ok := t.(*types.Pointer); ok {
+				f.body = p.Elem()
+			}
+		}
+	case "readRequestBody":
+		f.rawBody = true
+	case "streamRequestBody":
+		f.rawBody = true
+		f.callees = append(f.callees, fn)
+	case "renderPage":
+		f.page = true
+	case "writeEnvelopeOK":
+		g.setSuccess(f, "envelope", call, 1, 2)
+	case "jsonResponse":
+		g.setSuccess(f, "json", call, -1, 1)
+	case "textResponse":
+		if f.format == "" {
+			f.format = "text"
+		}
+	case "writeEnvelopeError":
+		if code, ok := g.constInt(call, 1); ok {
+			f.errors[code] = true
+		}
+	case "errorResponse":
+		if code, ok := g.constInt(call, 2); ok {
+			f.errors[code] = true
+		}
+		f.legacy = true
+	default:
+		for _, arg := range call.Args {
+			t := g.info.TypeOf(arg)
+			if t != nil && (isNamed(t, "net/http", "ResponseWriter") || isPointerTo(t, "net/http", "Request")) {
+				f.callees = append(f.callees, fn)
+				break
+			}
+		}
+	}
+}
+
+// setSuccess records the first success writer seen.
+func (g *generator) setSuccess(f *facts, format string, call *ast.CallExpr, statusArg, dataArg int) {
+	if f.format != "" && f.format != "text" {
+		return
+	}
+	f.format = format
+	f.success = 200
+	if statusArg >= 0 {
+		if code, ok := g.constInt(call, statusArg); ok {
+			f.success = code
+		}
+	}
+	if t := g.argType(call, dataArg); t != nil {
+		if _, isInterface := t.Underlying().(*types.Interface); !isInterface {
+			f.response = t
+		}
+	}
+}
+
+// merge folds fn's callees into its facts: statuses, params and inputs
+// accumulate, and the success writer comes from the first callee that has
+// one when fn writes none itself.
+func (g *generator) merge(fn *types.Func, visiting map[*types.Func]bool) *facts {
+	own := g.funcs[fn]
+	if own == nil || visiting[fn] {
+		return &facts{errors: map[int64]bool{}}
+	}
+	visiting[fn] = true
+	defer delete(visiting, fn)
+
+	m := *own
+	m.errors = make(map[int64]bool, len(own.errors))
+	for code := range own.errors {
+		m.errors[code] = true
+	}
+	for _, callee := range own.callees {
+		c := g.merge(callee, visiting)
+		for code := range c.errors {
+			m.errors[code] = true
+		}
+		m.params = append(m.params, c.params...)
+		m.query = append(m.query, c.query...)
+		m.formFields = append(m.formFields, c.formFields...)
+		m.formFiles = append(m.formFiles, c.formFiles...)
+		m.rawBody = m.rawBody || c.rawBody
+		m.legacy = m.legacy || c.legacy
+		m.page = m.page || c.page
+		if m.body == nil {
+			m.body = c.body
+		}
+		if m.format == "" || (m.format == "text" && c.format != "" && c.format != "text") {
+			if c.format != "" {
+				m.format, m.response, m.success = c.format, c.response, c.success
+			}
+		}
+		if m.contentType == "" {
+			m.contentType = c.contentType
+		}
+	}
+	return &m
+}
+
+// callee resolves the function or method a call invokes.
+func (g *generator) callee(call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := g.info.Uses[id].(*types.Func)
+	if fn == nil || fn.Pkg() == nil {
+		return nil
+	}
+	return fn
+}
+
+// isRecv reports whether method fn has receiver type pkg.name (or a
+// pointer to it).
+func isRecv(fn *types.Func, pkg, name string) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	return isNamed(t, pkg, name)
+}
+
+// readsRequestBody reports whether expr is r.Body (possibly wrapped, as in
+// io.LimitReader(r.Body, n)).
+func (g *generator) readsRequestBody(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if ok && sel.Sel.Name == "Body" && isPointerTo(g.info.TypeOf(sel.X), "net/http", "Request") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func (g *generator) argType(call *ast.CallExpr, i int) types.Type {
+	if i >= len(call.Args) {
+		return nil
+	}
+	return g.info.TypeOf(call.Args[i])
+}
+
+func (g *generator) constString(call *ast.CallExpr, i int) string {
+	if i >= len(call.Args) {
+		return ""
+	}
+	tv := g.info.Types[call.Args[i]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+func (g *generator) constInt(call *ast.CallExpr, i int) (int64, bool) {
+	if i >= len(call.Args) {
+		return 0, false
+	}
+	tv := g.info.Types[call.Args[i]]
+	if tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(tv.Value)
+}
+
+// contentType returns the media type a Header().Set("Content-Type", v)
+// call sets: v itself when constant, "image/*" when computed by an image
+// helper (the image tools answer in the requested format), and
+// "application/octet-stream" otherwise.
+func (g *generator) contentType(call *ast.CallExpr) string {
+	if v := g.constString(call, 1); v != "" {
+		return v
+	}
+	if inner, ok := call.Args[1].(*ast.CallExpr); ok {
+		if fn := g.callee(inner); fn != nil && strings.Contains(strings.ToLower(fn.Name()), "image") {
+			return "image/*"
+		}
+	}
+	return "application/octet-stream"
+}
+
+// appendConst appends a non-empty name once.
+func appendConst(list []string, name string) []string {
+	if name == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == name {
+			return list
+		}
+	}
+	return append(list, name)
+}
+
+// typeExpr renders t as a typed nil pointer expression, (*T)(nil), or ""
+// when t cannot be named from this package.
+func (g *generator) typeExpr(t types.Type) string {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if !g.expressible(t, make(map[types.Type]bool)) {
+		return ""
+	}
+	return "(*" + types.TypeString(t, g.qualifier) + ")(nil)"
+}
+
+// expressible reports whether every named type in t can be referenced
+// from generated code in this package.
+func (g *generator) expressible(t types.Type, seen map[types.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Kind() != types.Invalid && t.Info()&types.IsUntyped == 0
+	case *types.Named:
+		obj := t.Obj()
+		if t.TypeArgs().Len() > 0 || obj.Pkg() == nil {
+			return obj.Pkg() == nil && obj.Name() == "error"
+		}
+		if obj.Pkg() == g.pkg {
+			return obj.Parent() == g.pkg.Scope()
+		}
+		return obj.Exported()
+	case *types.Alias:
+		return g.expressible(types.Unalias(t), seen)
+	case *types.Pointer:
+		return g.expressible(t.Elem(), seen)
+	case *types.Slice:
+		return g.expressible(t.Elem(), seen)
+	case *types.Array:
+		return g.expressible(t.Elem(), seen)
+	case *types.Map:
+		return g.expressible(t.Key(), seen) && g.expressible(t.Elem(), seen)
+	case *types.Interface:
+		return t.Empty()
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if !t.Field(i).Exported() || !g.expressible(t.Field(i).Type(), seen) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// qualifier names packages in type strings, registering each import.
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg {
+		return ""
+	}
+	if name, ok := g.imports[p.Path()]; ok {
+		return name
+	}
+	name := p.Name()
+	if alias, ok := g.aliases[p.Path()]; ok {
+		name = alias
+	}
+	for g.nameTaken(name) {
+		name += "pkg"
+	}
+	g.imports[p.Path()] = name
+	return name
+}
+
+// nameTaken reports whether an import name would clash with another
+// import or a package-level identifier.
+func (g *generator) nameTaken(name string) bool {
+	if g.pkg.Scope().Lookup(name) != nil {
+		return true
+	}
+	for _, existing := range g.imports {
+		if existing == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEntry writes one handlerDocs map entry.
+func (g *generator) writeEntry(w *bytes.Buffer, fd *ast.FuncDecl, f *facts) {
+	summary, description := docText(fd)
+
+	fmt.Fprintf(w, "\t%q: {\n", fd.Name.Name)
+	if summary != "" {
+		fmt.Fprintf(w, "\t\tSummary: %q,\n", summary)
+	}
+	if description != "" {
+		fmt.Fprintf(w, "\t\tDescription: %q,\n", description)
+	}
+
+	var params []string
+	seen := make(map[string]bool)
+	for _, t := range f.params {
+		if expr := g.typeExpr(t); expr != "" && !seen[expr] {
+			seen[expr] = true
+			params = append(params, expr)
+		}
+	}
+	if len(params) > 0 {
+		fmt.Fprintf(w, "\t\tParams: []interface{}{%s},\n", strings.Join(params, ", "))
+	}
+	writeStrings(w, "QueryParams", dedupe(f.query))
+	if f.body != nil {
+		if expr := g.typeExpr(f.body); expr != "" {
+			fmt.Fprintf(w, "\t\tBody: %s,\n", expr)
+		}
+	}
+	multipart := len(f.formFiles) > 0
+	if f.rawBody && f.body == nil && !multipart {
+		w.WriteString("\t\tRawBody: true,\n")
+	}
+	if multipart {
+		writeStrings(w, "FormFields", dedupe(f.formFields))
+		writeStrings(w, "FormFiles", dedupe(f.formFiles))
+	} else {
+		// Without a multipart body, FormValue reads the query string
+		if extra := dedupe(f.formFields); len(extra) > 0 && len(f.query) == 0 {
+			writeStrings(w, "QueryParams", extra)
+		}
+	}
+
+	switch f.format {
+	case "envelope":
+		w.WriteString("\t\tFormat: swagger.FormatEnvelope,\n")
+	case "json":
+		w.WriteString("\t\tFormat: swagger.FormatJSON,\n")
+	case "text":
+		w.WriteString("\t\tFormat: swagger.FormatText,\n")
+	default:
+		if f.contentType != "" {
+			mediaType, _, _ := strings.Cut(f.contentType, ";")
+			fmt.Fprintf(w, "\t\tFormat: swagger.FormatRaw,\n\t\tContentType: %q,\n", strings.TrimSpace(mediaType))
+		}
+	}
+	if f.response != nil {
+		if expr := g.typeExpr(f.response); expr != "" {
+			fmt.Fprintf(w, "\t\tResponse: %s,\n", expr)
+		}
+	}
+	if f.success != 0 && f.success != 200 {
+		fmt.Fprintf(w, "\t\tSuccessStatus: %d,\n", f.success)
+	}
+	if len(f.errors) > 0 {
+		codes := make([]int, 0, len(f.errors))
+		for code := range f.errors {
+			codes = append(codes, int(code))
+		}
+		sort.Ints(codes)
+		parts := make([]string, len(codes))
+		for i, code := range codes {
+			parts[i] = strconv.Itoa(code)
+		}
+		fmt.Fprintf(w, "\t\tErrorStatuses: []int{%s},\n", strings.Join(parts, ", "))
+	}
+	if f.legacy {
+		w.WriteString("\t\tLegacyErrors: true,\n")
+	}
+	w.WriteString("\t},\n")
+}
+
+func writeStrings(w *bytes.Buffer, field string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	fmt.Fprintf(w, "\t\t%s: []string{%s},\n", field, strings.Join(quoted, ", "))
+}
+
+func dedupe(values []string) []string {
+	var out []string
+	for _, v := range values {
+		out = appendConst(out, v)
+	}
+	return out
+}
+
+// docText splits a handler's doc comment into a one-sentence summary and
+// the full description, dropping the leading function name ("apiFoo
+// returns X" becomes "Returns X"; "apiFoo is X" becomes "Foo is X").
+// Section-marker comments that happen to sit above a handler ("Text API
+// handlers") are ignored.
+func docText(fd *ast.FuncDecl) (summary, description string) {
+	if fd.Doc == nil {
+		return "", ""
+	}
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(fd.Doc.Text()), "\n\n") {
+		paragraphs = append(paragraphs, strings.Join(strings.Fields(p), " "))
+	}
+	text := strings.Join(paragraphs, "\n\n")
+
+	if rest, ok := strings.CutPrefix(text, fd.Name.Name+" "); ok {
+		if strings.HasPrefix(rest, "is ") || strings.HasPrefix(rest, "are ") {
+			text = humanize(fd.Name.Name) + " " + rest
+		} else {
+			text = upperFirst(rest)
+		}
+	} else if !strings.Contains(text, ".") && len(strings.Fields(text)) < 6 {
+		return "", ""
+	}
+
+	summary, _, _ = strings.Cut(text, "\n\n")
+	if i := sentenceEnd(summary); i > 0 {
+		summary = summary[:i]
+	}
+	summary = strings.TrimSuffix(summary, ".")
+	if text == summary || text == summary+"." {
+		text = ""
+	}
+	return summary, text
+}
+
+// sentenceEnd returns the index just past the first sentence's period, or
+// -1. Periods inside words (file.txt) or after abbreviations (e.g.) do not
+// end a sentence.
+func sentenceEnd(s string) int {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] != '.' || s[i+1] != ' ' {
+			continue
+		}
+		word := s[strings.LastIndex(s[:i], " ")+1 : i]
+		switch word {
+		case "e.g", "i.e", "vs", "etc", "approx":
+			continue
+		}
+		return i + 1
+	}
+	return -1
+}
+
+// humanize turns a handler name into words: apiWeatherRadarHandler
+// becomes "Weather radar".
+func humanize(name string) string {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "api"), "Handler")
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
@@ -81,7 +81,7 @@ func TextUUIDsHandler(w http.ResponseWriter, r *http.Request) {
 // @Summary Compute hash
 // @Tags Text
 // @Produce json
-// @Param algorithm query string true "Hash algorithm (md5, sha1, sha256, sha384, sha512)"
+// @Param algorithm query string true "Hash algorithm (md5, sha1, sha2, sha3, blake2, blake3, crc, adler32, xxhash64, murmur3 variants)"
 // @Param input query string true "Input text"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/text/hash [get]
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"unicode"
+
+	"github.com/apimgr/api/src/swagger"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlerDocs (openapi_docs.go) is generated from the handler source: doc
+// comments, validateStruct param structs, request bodies, response
+// payloads and error statuses.
+//
+//go:generate go run gen_openapi.go
+
+// registerAPIEndpoints walks the router and registers every /api route
+// with the swagger package, so /api/v1/server/swagger describes exactly
+// the routes being served.
+func registerAPIEndpoints(r chi.Routes) {
+	swagger.SetEndpoints(apiEndpoints(r))
+}
+
+// apiEndpoints lists the /api routes in r with their handler docs.
+// Handlers without generated docs (those outside this package) get a
+// summary derived from their name.
+func apiEndpoints(r chi.Routes) []swagger.Endpoint {
+	var eps []swagger.Endpoint
+	chi.Walk(r, func(method, route string, h http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, "/api/") {
+			return nil
+		}
+		name := handlerName(h)
+		doc, ok := handlerDocs[name]
+		if !ok || doc.Summary == "" {
+			doc.Summary = humanizeHandlerName(name)
+		}
+		eps = append(eps, swagger.Endpoint{
+			Method:  method,
+			Pattern: route,
+			Tag:     routeTag(route),
+			Doc:     doc,
+		})
+		return nil
+	})
+	return eps
+}
+
+// handlerName returns the function name behind h: "apiUUIDHandler" for
+// this package's handlers (closures report their enclosing factory) and
+// "pkg.Func" for other packages'.
+func handlerName(h http.Handler) string {
+	v := reflect.ValueOf(h)
+	if v.Kind() != reflect.Func {
+		return ""
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return ""
+	}
+	full := fn.Name()
+	if i := strings.LastIndex(full, "/"); i >= 0 {
+		full = full[i+1:]
+	}
+	parts := strings.Split(full, ".")
+	if len(parts) < 2 {
+		return full
+	}
+	if parts[0] == "server" {
+		return parts[1]
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// humanizeHandlerName turns a handler name into a summary:
+// "apiUUIDBatchHandler" becomes "UUID batch", "handler.ServerHealthz"
+// becomes "Server healthz".
+func humanizeHandlerName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimPrefix(name, "api")
+	name = strings.TrimSuffix(name, "Handler")
+
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes) ||
+			(unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))))
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	for i, w := range words {
+		// Keep initialisms (UUID, JSON) as they are
+		if i > 0 && strings.ToUpper(w) != w {
+			words[i] = strings.ToLower(w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// routeTag groups a route under its tool category: /api/v1/text/... is
+// "Text". Top-level and unversioned routes are "Server".
+func routeTag(route string) string {
+	rest, ok := strings.CutPrefix(route, "/api/v1/")
+	if !ok {
+		return "Server"
+	}
+	category, _, nested := strings.Cut(rest, "/")
+	if !nested || category == "" {
+		return "Server"
+	}
+	return strings.ToUpper(category[:1]) + category[1:]
+}
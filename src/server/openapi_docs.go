@@ -0,0 +1,2287 @@
+// Code generated by gen_openapi.go; DO NOT EDIT.
+
+package server
+
+import (
+	convert "github.com/apimgr/api/src/service/convert"
+	crypto "github.com/apimgr/api/src/service/crypto"
+	datetime "github.com/apimgr/api/src/service/datetime"
+	docker "github.com/apimgr/api/src/service/docker"
+	fun "github.com/apimgr/api/src/service/fun"
+	generate "github.com/apimgr/api/src/service/generate"
+	geo "github.com/apimgr/api/src/service/geo"
+	image "github.com/apimgr/api/src/service/image"
+	language "github.com/apimgr/api/src/service/language"
+	network "github.com/apimgr/api/src/service/network"
+	osint "github.com/apimgr/api/src/service/osint"
+	parse "github.com/apimgr/api/src/service/parse"
+	research "github.com/apimgr/api/src/service/research"
+	weather "github.com/apimgr/api/src/service/weather"
+	"github.com/apimgr/api/src/swagger"
+)
+
+// handlerDocs documents every handler in this package for the OpenAPI
+// spec, keyed by function name. Regenerate with `go generate ./src/server`.
+var handlerDocs = map[string]swagger.HandlerDoc{
+	"HandleThemeSwitch": {
+		Summary:       "Handles theme toggle requests POST /api/v1/theme Body: {\"theme\": \"dark|light|auto\"}",
+		QueryParams:   []string{"theme"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "application/json",
+		ErrorStatuses: []int{400, 405},
+	},
+	"apiAddDurationHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiBatchHandler": {
+		Summary:       "Runs many API calls in one request: it dispatches each entry of a JSON array of {method, path, query, body} sub-requests through the router in-process, server.batch.concurrency at a time, and returns their status, headers and body in request order",
+		Description:   "Runs many API calls in one request: it dispatches each entry of a JSON array of {method, path, query, body} sub-requests through the router in-process, server.batch.concurrency at a time, and returns their status, headers and body in request order. A batch holds at most server.batch.max_requests entries and may not contain another batch. The batch itself counts once against the global_burst ceiling; each entry counts against its own read/write rate limit class.",
+		Body:          (*[]batchRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*[]batchResponse)(nil),
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiBcryptHandler": {
+		QueryParams:   []string{"cost"},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiBcryptVerifyGetHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiBcryptVerifyHandler": {
+		Body: (*struct {
+			Password string "json:\"password\""
+			Hash     string "json:\"hash\""
+		})(nil),
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiCaseHandler": {
+		Params:        []interface{}{(*caseParams)(nil)},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCaseTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiConvertAreaHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional area-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertColorHandler": {
+		Summary:       "Converts a color value between hex, RGB (\"r,g,b\"), and HSL (\"h,s,l\") representations using ?value=&from=&to=",
+		Params:        []interface{}{(*convertColorParams)(nil)},
+		QueryParams:   []string{"value", "from", "to"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertCurrencyHandler": {
+		Summary:       "Converts ?amount= from ?from= to ?to= using live ECB reference rates from the free, keyless Frankfurter API",
+		Params:        []interface{}{(*convertCurrencyParams)(nil)},
+		QueryParams:   []string{"amount", "from", "to"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*convert.CurrencyResult)(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiConvertDataHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional data-size-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertEnergyHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional energy-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertLengthHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional length-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertPressureHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional pressure-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertSpeedHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional speed-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertTemperatureHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional temperature-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertTimeHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional time-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertTimestampHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiConvertTimezoneHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiConvertVolumeHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional volume-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiConvertWeightHandler": {
+		Summary:       "Converts {value} from {from} to {to} units using the existing bidirectional weight-conversion pairs exported by convert.Service",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoAgeDecryptHandler": {
+		Summary:       "Decrypts an armored or base64 age file with the X25519 Identities (\"AGE-SECRET-KEY-1...\") or Passphrase, composing crypto.AgeDecrypt",
+		Description:   "Decrypts an armored or base64 age file with the X25519 Identities (\"AGE-SECRET-KEY-1...\") or Passphrase, composing crypto.AgeDecrypt. The plaintext falls back to hex when it is not valid UTF-8.",
+		Params:        []interface{}{(*cryptoAgeDecryptParams)(nil)},
+		Body:          (*cryptoAgeRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoAgeEncryptHandler": {
+		Summary:       "Encrypts Plaintext in the age v1 format, composing crypto.AgeEncrypt, to the X25519 Recipients (\"age1...\") or to Passphrase with scrypt work factor WorkFactor (log2 N, default and maximum 18)",
+		Description:   "Encrypts Plaintext in the age v1 format, composing crypto.AgeEncrypt, to the X25519 Recipients (\"age1...\") or to Passphrase with scrypt work factor WorkFactor (log2 N, default and maximum 18). Output is ASCII-armored unless Armor is false, in which case it is base64 of the binary file. Either form decrypts with the age CLI.",
+		Params:        []interface{}{(*cryptoAgeEncryptParams)(nil)},
+		Body:          (*cryptoAgeRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoAgeKeygenHandler": {
+		Summary:       "Generates an age X25519 identity and its recipient, composing crypto.GenerateAgeKeyPair",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.AgeKeyPair)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiCryptoCRLDecodeHandler": {
+		Summary:       "Decodes a PEM or base64 DER certificate revocation list, composing crypto.ParseCRL",
+		Description:   "Decodes a PEM or base64 DER certificate revocation list, composing crypto.ParseCRL. When the issuer certificate is supplied the CRL's signature is checked and reported as signature_valid.",
+		Params:        []interface{}{(*cryptoCRLDecodeParams)(nil)},
+		Body:          (*cryptoCRLDecodeRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.CRLInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoCSRDecodeHandler": {
+		Summary:       "Decodes a PEM certificate signing request and checks its self-signature, composing crypto.ParseCSR",
+		Params:        []interface{}{(*cryptoCSRDecodeParams)(nil)},
+		Body:          (*cryptoCSRDecodeRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.CSRInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoCSRHandler": {
+		Summary:       "Generates a PEM certificate signing request with subject alternative names and requested key usages, composing crypto.GenerateCSR",
+		Description:   "Generates a PEM certificate signing request with subject alternative names and requested key usages, composing crypto.GenerateCSR. A new key of KeyType (default ecdsa-p256) is created and returned as private_key unless an existing Key is supplied. The decoded request is returned as info so callers can check it before submitting it to a CA.",
+		Params:        []interface{}{(*cryptoCSRParams)(nil)},
+		Body:          (*cryptoCSRRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoCertificateDecodeHandler": {
+		Summary:       "Decodes every certificate in a PEM bundle, composing crypto.DecodeCertificates",
+		Description:   "Decodes every certificate in a PEM bundle, composing crypto.DecodeCertificates. Unlike mode=parse on /crypto/certificate it accepts whole chains, as servers send them.",
+		Params:        []interface{}{(*cryptoCertificateDecodeParams)(nil)},
+		Body:          (*cryptoCertificateDecodeRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoCertificateHandler": {
+		Summary:       "Handles self-signed X.509 certificate generation and PEM certificate parsing in one endpoint, selected by Mode",
+		Description:   "Handles self-signed X.509 certificate generation and PEM certificate parsing in one endpoint, selected by Mode. Composes crypto.GenerateCertificate and crypto.ParseCertificate.",
+		Params:        []interface{}{(*cryptoCertificateParams)(nil)},
+		Body:          (*cryptoCertificateRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoCertificateSignHandler": {
+		Summary:       "Issues a certificate for a CSR signed by a caller-supplied CA certificate and key, composing crypto.SignCSR",
+		Description:   "Issues a certificate for a CSR signed by a caller-supplied CA certificate and key, composing crypto.SignCSR. The subject and SANs come from the CSR; key usages come from the request body, else from the CSR, else from leaf or CA defaults. The CA key is used only for this signature and is never stored.",
+		Params:        []interface{}{(*cryptoCertificateSignParams)(nil)},
+		Body:          (*cryptoCertificateSignRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoCertificateVerifyHandler": {
+		Summary:       "Builds and validates the chain from the leaf (the first certificate of Chain) through any intermediates to Roots, composing crypto.VerifyCertificateChain",
+		Description:   "Builds and validates the chain from the leaf (the first certificate of Chain) through any intermediates to Roots, composing crypto.VerifyCertificateChain. Without Roots the system trust store is used. The chain field of GET /osint/cert/{domain} can be posted as Chain unchanged. A chain that fails validation is a 200 with valid=false and the reason in error.",
+		Params:        []interface{}{(*cryptoCertificateVerifyParams)(nil)},
+		Body:          (*cryptoCertificateVerifyRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.ChainVerification)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoCipherDecryptHandler": {
+		Summary:       "Reverses apiCryptoCipherEncryptHandler, composing crypto.CipherDecrypt",
+		Description:   "Reverses apiCryptoCipherEncryptHandler, composing crypto.CipherDecrypt. IV is required, and so is kdf.salt when the key comes from a passphrase. For AEAD ciphers a Tag given separately (as OpenSSL and many HSMs emit it) is appended to the ciphertext. The plaintext falls back to hex when it is not valid UTF-8.",
+		Params:        []interface{}{(*cryptoCipherParams)(nil)},
+		Body:          (*cryptoCipherRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoCipherEncryptHandler": {
+		Summary:       "Encrypts Plaintext with an explicit cipher, key and IV, composing crypto.CipherEncrypt, so the result can be reproduced by OpenSSL, WebCrypto, libsodium and the like",
+		Description:   "Encrypts Plaintext with an explicit cipher, key and IV, composing crypto.CipherEncrypt, so the result can be reproduced by OpenSSL, WebCrypto, libsodium and the like. Algorithm is aes-cbc, aes-ctr, aes-gcm (default), chacha20-poly1305 or xchacha20-poly1305. The key is raw hex, or derived from Passphrase with KDF (argon2id, argon2i, scrypt or pbkdf2-sha1/256/512, with the same params as /crypto/password/hash). A random IV is generated when none is given. AEAD ciphertext ends with the tag, which is also returned separately.",
+		Params:        []interface{}{(*cryptoCipherParams)(nil)},
+		Body:          (*cryptoCipherRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoDecryptHandler": {
+		Summary:       "Decrypts a base64-encoded AES-256-GCM payload produced by apiCryptoEncryptHandler, composing crypto.AESDecrypt",
+		Params:        []interface{}{(*cryptoDecryptParams)(nil)},
+		Body:          (*cryptoEncryptRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoEd25519Handler": {
+		Summary:       "Handles Ed25519 keypair generation, signing, and signature verification in one endpoint, selected by Mode",
+		Description:   "Handles Ed25519 keypair generation, signing, and signature verification in one endpoint, selected by Mode. Composes crypto.GenerateEd25519Keys, crypto.Ed25519Sign, and crypto.Ed25519Verify.",
+		Params:        []interface{}{(*cryptoEd25519Params)(nil)},
+		Body:          (*cryptoEd25519Request)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoEncryptHandler": {
+		Summary:       "Encrypts plaintext with AES-256-GCM using a key derived from the supplied passphrase via Argon2id, composing crypto.AESEncrypt",
+		Params:        []interface{}{(*cryptoEncryptParams)(nil)},
+		Body:          (*cryptoEncryptRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoHMACHandler": {
+		Summary:       "Computes an HMAC of Message using Key, composing crypto.HMACGenerate",
+		Description:   "Computes an HMAC of Message using Key, composing crypto.HMACGenerate. Algorithm defaults to sha256; sha1, sha384, sha512 and sha3-224/256/384/512 are also HMACs, while blake2b-256/512, blake2s-256 and blake3 (32-byte key) use the hash's own keyed mode.",
+		Params:        []interface{}{(*cryptoHMACParams)(nil)},
+		Body:          (*cryptoHMACRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoHOTPGenerateHandler": {
+		Summary:       "Returns the RFC 4226 counter-based code for Counter, composing crypto.GenerateHOTP",
+		Description:   "Returns the RFC 4226 counter-based code for Counter, composing crypto.GenerateHOTP. Algorithm is SHA1 (default), SHA256 or SHA512 and Digits 6 (default) to 8.",
+		Params:        []interface{}{(*cryptoOTPParams)(nil)},
+		Body:          (*cryptoOTPRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoHOTPVerifyHandler": {
+		Summary:       "Checks an HOTP code against Counter and the LookAhead counters after it (default 0, at most 100), composing crypto.VerifyHOTP",
+		Description:   "Checks an HOTP code against Counter and the LookAhead counters after it (default 0, at most 100), composing crypto.VerifyHOTP. On a match the response carries the matched counter and next_counter, the value the caller should store for the next verification.",
+		Params:        []interface{}{(*cryptoOTPParams)(nil)},
+		Body:          (*cryptoOTPRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoJWKSHandler": {
+		Summary:       "Builds a JSON Web Key Set from the public halves of Keys, composing crypto.BuildJWKS",
+		Description:   "Builds a JSON Web Key Set from the public halves of Keys, composing crypto.BuildJWKS. Each entry's kid is its RFC 7638 thumbprint and Use (default sig) is set on every entry. The data is the JWKS document itself, ready to publish as a jwks_uri.",
+		Params:        []interface{}{(*cryptoJWKSParams)(nil)},
+		Body:          (*cryptoJWKSRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.JWKS)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoJWTDecodeHandler": {
+		Summary:       "Decodes (never verifies) the header and payload of a JSON Web Token",
+		Description:   "Decodes (never verifies) the header and payload of a JSON Web Token. No signature verification is performed — this is a read-only inspection tool, matching the JWT decoder's stated purpose of viewing header/payload/signature details, not validating trust.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoJWTSignHandler": {
+		Summary:       "Signs Claims as a JWT, composing crypto.JWTSign",
+		Description:   "Signs Claims as a JWT, composing crypto.JWTSign. Key is the shared secret for HS* or a PEM/JWK private key otherwise; when it is omitted a fresh secret or keypair is generated with the crypto package's generators and returned alongside the token. A positive ExpiresIn (seconds) sets exp, and iat is filled in when absent.",
+		Params:        []interface{}{(*cryptoJWTSignParams)(nil)},
+		Body:          (*cryptoJWTSignRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoJWTVerifyHandler": {
+		Summary:       "Verifies a JWT's signature with Key and checks its exp/nbf/iat (allowing ClockSkew seconds of leeway), aud and iss claims, composing crypto.JWTVerify",
+		Description:   "Verifies a JWT's signature with Key and checks its exp/nbf/iat (allowing ClockSkew seconds of leeway), aud and iss claims, composing crypto.JWTVerify. Unlike apiCryptoJWTDecodeHandler it answers whether the token can be trusted: a bad signature or failed claim is a 200 with valid=false and the reasons in errors, while a token or key that cannot be evaluated at all is a 400.",
+		Params:        []interface{}{(*cryptoJWTVerifyParams)(nil)},
+		Body:          (*cryptoJWTVerifyRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.JWTVerification)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoKDFHandler": {
+		Summary:       "Derives a raw key from Passphrase, composing crypto.DeriveKey",
+		Description:   "Derives a raw key from Passphrase, composing crypto.DeriveKey. Algorithm defaults to argon2id and Params to the /crypto/password/hash defaults; Salt (hex) is random when omitted. The response echoes every parameter used alongside the hex key.",
+		Params:        []interface{}{(*cryptoKDFParams)(nil)},
+		Body:          (*cryptoKDFRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoKeyConvertHandler": {
+		Summary:       "Re-encodes a key from any format crypto.ParseKey accepts (PEM, base64 DER, JWK or OpenSSH) into Format, composing crypto.ConvertKey",
+		Description:   "Re-encodes a key from any format crypto.ParseKey accepts (PEM, base64 DER, JWK or OpenSSH) into Format, composing crypto.ConvertKey. PublicOnly outputs just the public half of a private key. The converted key's InspectKey details come back too.",
+		Params:        []interface{}{(*cryptoKeyConvertParams)(nil)},
+		Body:          (*cryptoKeyRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoKeyInspectHandler": {
+		Summary:       "Reports the type, size, curve and fingerprints (RFC 7638 JWK thumbprint, OpenSSH SHA256/MD5 and SPKI hashes) of a key in any format crypto.ParseKey accepts, composing crypto.InspectKey",
+		Params:        []interface{}{(*cryptoKeyInspectParams)(nil)},
+		Body:          (*cryptoKeyRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.KeyInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoKeyUnwrapHandler": {
+		Summary:       "Reverses apiCryptoKeyWrapHandler, composing crypto.AESKeyUnwrap",
+		Description:   "Reverses apiCryptoKeyWrapHandler, composing crypto.AESKeyUnwrap. A wrong KEK or padding mode fails the integrity check.",
+		Params:        []interface{}{(*cryptoKeyWrapParams)(nil)},
+		Body:          (*cryptoKeyWrapRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoKeyWrapHandler": {
+		Summary:       "Wraps Key under the AES key-encryption key KEK, composing crypto.AESKeyWrap: RFC 3394 (AES-KW), or RFC 5649 (AES-KWP, any key length) when Padding is true",
+		Params:        []interface{}{(*cryptoKeyWrapParams)(nil)},
+		Body:          (*cryptoKeyWrapRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoMnemonicDeriveHandler": {
+		Summary:       "Derives the BIP32 key at Path, composing crypto.DeriveKeyInfo",
+		Description:   "Derives the BIP32 key at Path, composing crypto.DeriveKeyInfo. The root is a mnemonic (plus optional passphrase), a hex seed, or an existing xprv/xpub/tprv/tpub whose own position is \"m\" in Path. Network picks xprv/xpub or tprv/tpub serialization for mnemonic and seed roots; an extended key keeps its own. Extended public keys can only derive non-hardened children.",
+		Params:        []interface{}{(*cryptoMnemonicDeriveParams)(nil)},
+		Body:          (*cryptoMnemonicRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.DerivedKey)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoMnemonicGenerateHandler": {
+		Summary:       "Creates a BIP39 mnemonic of Words words (default 12) from Language's wordlist (default english), composing crypto.GenerateMnemonic",
+		Description:   "Creates a BIP39 mnemonic of Words words (default 12) from Language's wordlist (default english), composing crypto.GenerateMnemonic. Entropy (hex, 16-32 bytes) encodes chosen entropy instead of random bytes, and then decides the word count.",
+		Params:        []interface{}{(*cryptoMnemonicGenerateParams)(nil)},
+		Body:          (*cryptoMnemonicRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoMnemonicSeedHandler": {
+		Summary:       "Derives the 64-byte BIP39 seed for a mnemonic and optional passphrase, composing crypto.MnemonicToSeed, and returns it in hex with the BIP32 master key it produces",
+		Params:        []interface{}{(*cryptoMnemonicValidateParams)(nil)},
+		Body:          (*cryptoMnemonicRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoMnemonicValidateHandler": {
+		Summary:       "Checks a BIP39 mnemonic's words and checksum, composing crypto.ValidateMnemonic",
+		Description:   "Checks a BIP39 mnemonic's words and checksum, composing crypto.ValidateMnemonic. Without a language the wordlist is detected. An invalid mnemonic is a 200 with valid=false and the reason in error.",
+		Params:        []interface{}{(*cryptoMnemonicValidateParams)(nil)},
+		Body:          (*cryptoMnemonicRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.MnemonicInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoOTPAuthParseHandler": {
+		Summary: "Parses an otpauth:// key URI into its parameters, composing crypto.ParseOTPAuthURI, and adds the code the key produces now (totp) or at its counter (hotp)",
+		Params:  []interface{}{(*cryptoOTPAuthParseParams)(nil)},
+		Body:    (*cryptoOTPRequest)(nil),
+		Format:  swagger.FormatEnvelope,
+		Response: (*struct {
+			*crypto.OTPAuthKey
+			Code string "json:\"code\""
+		})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoOTPAuthQRHandler": {
+		Summary:       "Renders a provisioning QR code as a PNG in one call: URI is encoded as given, or one is built from Secret, Type (totp by default), Issuer, Account and the OTP parameters",
+		Description:   "Renders a provisioning QR code as a PNG in one call: URI is encoded as given, or one is built from Secret, Type (totp by default), Issuer, Account and the OTP parameters. Either way the URI is validated with crypto.ParseOTPAuthURI first, and the image comes from the same generateService.QR path as /generate/qr. The encoded URI is echoed in the X-OTPAuth-URI header.",
+		Params:        []interface{}{(*cryptoOTPAuthQRParams)(nil)},
+		Body:          (*cryptoOTPRequest)(nil),
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoPGPHandler": {
+		Summary:       "Handles PGP keypair generation, encryption, decryption, signing, and signature verification in one endpoint, selected by Mode",
+		Description:   "Handles PGP keypair generation, encryption, decryption, signing, and signature verification in one endpoint, selected by Mode. Composes crypto.GeneratePGPKeysWithPassphrase, crypto.PGPEncryptTo, crypto.PGPDecryptWithPassphrase, crypto.PGPSign, crypto.PGPClearsign, and crypto.PGPVerify.\n\nPassphrase protects the generated private key, and unlocks a protected one for decrypt, sign and clearsign. Encrypt and verify take keys from PublicKey and PublicKeys together, so a message can be encrypted to several recipients or checked against a keyring. sign makes a detached armored signature of Message; verify checks Message against Signature, or checks Message as a clearsigned message when Signature is empty. With Encoding base64, Message is decoded first, so binary artifacts can be signed and verified.",
+		Params:        []interface{}{(*cryptoPGPParams)(nil)},
+		Body:          (*cryptoPGPRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoPKCS12DecodeHandler": {
+		Summary:       "Opens a base64 PKCS#12 bundle, composing crypto.PKCS12Decode, and returns its key as PKCS#8 PEM and its certificates as PEM",
+		Description:   "Opens a base64 PKCS#12 bundle, composing crypto.PKCS12Decode, and returns its key as PKCS#8 PEM and its certificates as PEM. Trust stores without a key return only ca_certificates.",
+		Params:        []interface{}{(*cryptoPKCS12DecodeParams)(nil)},
+		Body:          (*cryptoPKCS12DecodeRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*crypto.PKCS12Contents)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoPKCS12Handler": {
+		Summary:       "Bundles a private key, its certificate and any CA certificates following it in Certificate into a base64 PKCS#12 (.p12/.pfx) file, composing crypto.PKCS12Encode",
+		Description:   "Bundles a private key, its certificate and any CA certificates following it in Certificate into a base64 PKCS#12 (.p12/.pfx) file, composing crypto.PKCS12Encode. Legacy selects 3DES encryption for consumers that cannot read AES-encrypted bundles.",
+		Params:        []interface{}{(*cryptoPKCS12Params)(nil)},
+		Body:          (*cryptoPKCS12Request)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoPasswordHashHandler": {
+		Summary:       "Hashes Password with Algorithm, composing crypto.PasswordHash",
+		Description:   "Hashes Password with Algorithm, composing crypto.PasswordHash. Params tunes the work factors, salt and key length; omitted fields take the algorithm's defaults (Argon2 m=65536,t=3,p=4; scrypt ln=15,r=8,p=1; PBKDF2 600000/210000 iterations; sha-crypt 5000 rounds; bcrypt cost 12). Parameters over the server's limits are a 400.",
+		Params:        []interface{}{(*cryptoPasswordHashParams)(nil)},
+		Body:          (*cryptoPasswordHashRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoPasswordVerifyHandler": {
+		Summary:       "Checks Password against Hash, detecting the algorithm and parameters from the hash string, composing crypto.PasswordVerify",
+		Description:   "Checks Password against Hash, detecting the algorithm and parameters from the hash string, composing crypto.PasswordVerify. Besides every algorithm the hash endpoint produces, $1$ md5-crypt and Django pbkdf2_sha256 hashes are accepted for migrations. An unrecognised hash, or one whose work factors exceed the server's limits, is a 400; a wrong password is valid=false.",
+		Params:        []interface{}{(*cryptoPasswordVerifyParams)(nil)},
+		Body:          (*cryptoPasswordVerifyRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoRSAHandler": {
+		Summary:       "Handles RSA keypair generation, RSA-OAEP encryption, and RSA-OAEP decryption in one endpoint, selected by Mode",
+		Description:   "Handles RSA keypair generation, RSA-OAEP encryption, and RSA-OAEP decryption in one endpoint, selected by Mode. Composes crypto.GenerateRSAKeys, crypto.RSAEncrypt, and crypto.RSADecrypt.",
+		Params:        []interface{}{(*cryptoRSAParams)(nil)},
+		Body:          (*cryptoRSARequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoShamirCombineHandler": {
+		Summary:       "Recovers a secret from shares made by apiCryptoShamirSplitHandler, composing crypto.ShamirCombine",
+		Description:   "Recovers a secret from shares made by apiCryptoShamirSplitHandler, composing crypto.ShamirCombine. Each share's checksum is verified, all must come from one split, and the recovered secret is checked against the digest embedded when it was split, so a mistyped, altered or foreign share is a 400 naming it rather than a wrong secret. The secret falls back to hex when it is not valid UTF-8.",
+		Params:        []interface{}{(*cryptoShamirCombineParams)(nil)},
+		Body:          (*cryptoShamirCombineRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoShamirSplitHandler": {
+		Summary:       "Splits Secret into Shares shares, any Threshold of which recover it, composing crypto.ShamirSplit",
+		Description:   "Splits Secret into Shares shares, any Threshold of which recover it, composing crypto.ShamirSplit. Shares are encoded as hex (default), base64 or mnemonic words from the English BIP39 wordlist; each carries the split id, threshold and a checksum.",
+		Params:        []interface{}{(*cryptoShamirSplitParams)(nil)},
+		Body:          (*cryptoShamirSplitRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiCryptoTOTPCodeHandler": {
+		Summary:       "Returns the RFC 6238 time-based code for Timestamp (Unix seconds, default now), composing crypto.TOTPAt",
+		Description:   "Returns the RFC 6238 time-based code for Timestamp (Unix seconds, default now), composing crypto.TOTPAt. Unlike GET /totp/code/{secret} it accepts SHA256/SHA512, 6-8 digits and a custom period.",
+		Params:        []interface{}{(*cryptoOTPParams)(nil)},
+		Body:          (*cryptoOTPRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiCryptoTOTPVerifyHandler": {
+		Summary:       "Checks a TOTP code against the step at Timestamp (default now) and Window steps either side (default 1, at most 10), composing crypto.VerifyTOTPAt",
+		Params:        []interface{}{(*cryptoOTPParams)(nil)},
+		Body:          (*cryptoOTPRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDateTimeNowHandler": {
+		QueryParams:   []string{"timezone"},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiDateTimeNowTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiDatetimeAddWorkdaysHandler": {
+		Summary:       "Adds a (possibly negative) number of business days to a YYYY-MM-DD date via datetime.AddBusinessDays, using the same ?country=, ?region= and ?weekend= calendar as the workdays endpoint",
+		QueryParams:   []string{"country", "region", "weekend"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeCalendarConvertHandler": {
+		Summary:       "Converts ?value= from the calendar or time scale named by ?from= (default gregorian) to all the others via datetime.ConvertCalendar",
+		Description:   "Converts ?value= from the calendar or time scale named by ?from= (default gregorian) to all the others via datetime.ConvertCalendar. ?timezone= (IANA, default UTC) sets the zone calendar dates are read and reported in, and ?format= adds a FormatDatetime rendering.",
+		QueryParams:   []string{"from", "timezone", "format", "value"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeCalendarHandler": {
+		Summary:       "Builds a week-grid calendar for a given year/month via datetime.GenerateCalendar, listing the month's holidays and business days for ?country=, ?region= and ?weekend=",
+		QueryParams:   []string{"country", "region", "weekend"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeCronHandler": {
+		Summary:       "Parses a cron expression and returns a breakdown, a plain-English description and its next and previous run times via datetime.ParseCron",
+		Description:   "Parses a cron expression and returns a breakdown, a plain-English description and its next and previous run times via datetime.ParseCron. ?timezone= (IANA, default UTC), ?count= (1-100, default 5), and ?start= / ?end= (RFC3339) pick which runs are listed.",
+		QueryParams:   []string{"expression", "timezone", "count", "start", "end"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeFormatHandler": {
+		Summary:       "Formats a Unix timestamp using a named format (iso8601, rfc3339, rfc1123, rfc822, kitchen, date, time, datetime) or a literal Go reference-time layout, via datetime.FormatDatetime",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeHolidayCalendarsHandler": {
+		Summary:  "Lists the supported holiday calendars and their regions via datetime.HolidayCalendars",
+		Format:   swagger.FormatEnvelope,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiDatetimeHolidaysHandler": {
+		Summary:       "Lists a country's public holidays for a year via datetime.Holidays",
+		Description:   "Lists a country's public holidays for a year via datetime.Holidays. ?region= adds that region's holidays.",
+		QueryParams:   []string{"region"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeICSHandler": {
+		Summary:       "Writes an iCalendar file for the events and to-dos in the JSON request body via datetime.GenerateICS",
+		Body:          (*datetime.ICSCalendar)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeICSParseHandler": {
+		Summary:       "Reads the iCalendar text in the request body via datetime.ParseICS, listing up to ?count= (1-100, default 5) occurrences of each recurring event or to-do",
+		Params:        []interface{}{(*datetimeICSParseParams)(nil)},
+		QueryParams:   []string{"count"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeMoonHandler": {
+		Summary:       "Computes the current lunar phase for an optional YYYY-MM-DD date via datetime.MoonPhase (synodic-month method)",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeParseHandler": {
+		Summary:       "Parses a free-form date/time string against a list of common layouts via datetime.ParseDateString, falling back to natural-language expressions (\"next tuesday 3pm\", \"2h45m ago\") and ISO 8601 durations and intervals",
+		Description:   "Parses a free-form date/time string against a list of common layouts via datetime.ParseDateString, falling back to natural-language expressions (\"next tuesday 3pm\", \"2h45m ago\") and ISO 8601 durations and intervals. The string comes from the path or ?value= (needed for intervals, which contain a slash); ?reference= (RFC3339, default now) and ?timezone= (IANA, default UTC) anchor relative expressions.",
+		QueryParams:   []string{"value", "timezone", "reference"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeRRuleHandler": {
+		Summary:       "Expands an RFC 5545 recurrence rule from ?start= in ?timezone= (IANA, default UTC) via datetime.ExpandRRule",
+		Description:   "Expands an RFC 5545 recurrence rule from ?start= in ?timezone= (IANA, default UTC) via datetime.ExpandRRule. ?exdate= takes a comma-separated list of excluded times or dates, ?count= (1-1000, default 10) caps the occurrences listed, and ?from= / ?to= (RFC3339) restrict them to a window.",
+		QueryParams:   []string{"rule", "start", "timezone", "exdate", "count", "from", "to"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeSunriseHandler": {
+		Summary:       "Computes sunrise/sunset UTC times for a given latitude, longitude, and optional YYYY-MM-DD date via datetime.SunriseSunset (Almanac for Computers, 1990 algorithm)",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDatetimeWorkdaysHandler": {
+		Summary:       "Counts business days between two YYYY-MM-DD dates inclusive via datetime.WorkdaysBetween, skipping weekends (?weekend=, default Saturday and Sunday) and the public holidays of ?country= and ?region=",
+		QueryParams:   []string{"country", "region", "weekend"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDecodeHandler": {
+		Params:        []interface{}{(*decodeParams)(nil)},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiDecodeTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiDevBase64Handler": {
+		Summary:       "Encodes or decodes the raw request body as base64 (standard or URL-safe, per ?urlsafe=) depending on the ?action= query parameter (encode, the default, or decode)",
+		Params:        []interface{}{(*devBase64Params)(nil)},
+		QueryParams:   []string{"action", "urlsafe"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevCronHandler": {
+		Summary:       "Parses a cron expression, reusing the same datetime.ParseCron helper and query parameters as apiDatetimeCronHandler",
+		QueryParams:   []string{"expression", "timezone", "count", "start", "end"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevEchoHandler": {
+		Summary:       "Reflects the caller's own request details back as JSON: method, path, query parameters, headers, remote address, and raw body",
+		Description:   "Reflects the caller's own request details back as JSON: method, path, query parameters, headers, remote address, and raw body. This is a genuinely new debug tool with no service dependency.",
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevFormatCSSHandler": {
+		Summary:       "Formats (or, with ?minify=true, minifies) the raw CSS document supplied in the request body",
+		QueryParams:   []string{"minify"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevFormatHTMLHandler": {
+		Summary:       "Formats (or, with ?minify=true, minifies) the raw HTML document supplied in the request body",
+		QueryParams:   []string{"minify"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevFormatJSHandler": {
+		Summary:       "Formats (or, with ?minify=true, minifies) the raw JavaScript source supplied in the request body",
+		QueryParams:   []string{"minify"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevFormatJSONHandler": {
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevFormatSQLHandler": {
+		Summary:       "Formats the raw SQL query supplied in the request body by breaking it onto multiple lines by clause keyword",
+		Description:   "Formats the raw SQL query supplied in the request body by breaking it onto multiple lines by clause keyword. There is no minify variant — a formatted SQL query is the tool's only mode.",
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevFormatXMLHandler": {
+		Summary:       "Formats (or, with ?minify=true, minifies) the raw XML document supplied in the request body",
+		QueryParams:   []string{"minify"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevJWTHandler": {
+		Summary:       "Decodes (never verifies) the header and payload of a JSON Web Token, reusing the same decodeJWTSegment helper as apiParseJWTHandler and apiCryptoJWTDecodeHandler",
+		Description:   "Decodes (never verifies) the header and payload of a JSON Web Token, reusing the same decodeJWTSegment helper as apiParseJWTHandler and apiCryptoJWTDecodeHandler. No signature verification is performed — this is a read-only debug/inspection tool.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDevURLEncodeHandler": {
+		Summary:       "URL-encodes or URL-decodes the raw request body depending on the ?action= query parameter (encode, the default, or decode)",
+		Params:        []interface{}{(*devURLEncodeParams)(nil)},
+		QueryParams:   []string{"action"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDiffHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiDockerBestPracticesHandler": {
+		Summary:  "Returns the static curated Docker best practices guide, using docker.Service.BestPracticesGuide",
+		Format:   swagger.FormatEnvelope,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiDockerComposeToRunHandler": {
+		Summary:       "Converts the docker-compose YAML text supplied in the request body into an equivalent docker run command for the service named by ?service= (or the file's only service when omitted), using docker.Service.ComposeToRunCommand",
+		Params:        []interface{}{(*dockerComposeToRunParams)(nil)},
+		QueryParams:   []string{"service"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerComposeValidateHandler": {
+		Summary:       "Validates the docker-compose YAML text supplied in the request body, using docker.Service.ValidateCompose",
+		Params:        []interface{}{(*dockerComposeValidateParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.ComposeValidationResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerEnvParserHandler": {
+		Summary:       "Parses the .env file text supplied in the request body into structured key/value entries, using docker.Service.ParseEnvFile",
+		Params:        []interface{}{(*dockerEnvParserParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.EnvParseResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerLintHandler": {
+		Summary:       "Lints the Dockerfile text supplied in the request body for common anti-patterns, using docker.Service.LintDockerfile",
+		Params:        []interface{}{(*dockerLintParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.DockerfileLintResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerNetworkHelperHandler": {
+		Summary:       "Generates a docker network create command and a matching compose networks: block from query-string parameters, using docker.Service.GenerateNetworkConfig",
+		QueryParams:   []string{"name", "driver", "subnet", "gateway", "internal"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.NetworkHelperResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerPortMappingHandler": {
+		Summary:       "Formats a host/container/protocol triple into a \"host:container/protocol\" string (?action=format, the default) or parses an existing mapping string back into its parts (?action=parse&mapping=), using docker.Service's Format/ParsePortMapping",
+		Params:        []interface{}{(*dockerPortMappingParams)(nil), (*dockerPortMappingMappingParams)(nil)},
+		QueryParams:   []string{"action", "host", "container", "protocol", "mapping"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerRunToComposeHandler": {
+		Summary:       "Converts the docker run command line supplied in the request body into an equivalent docker-compose service block, using docker.Service.RunCommandToCompose",
+		Params:        []interface{}{(*dockerRunToComposeParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerSecurityScanHandler": {
+		Summary:       "Statically scans the Dockerfile or compose text supplied in the request body for common security anti-patterns, using docker.Service.ScanSecurity",
+		Params:        []interface{}{(*dockerSecurityScanParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.SecurityScanResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerSizeOptimizerHandler": {
+		Summary:       "Statically analyzes the Dockerfile text supplied in the request body and suggests image-size reduction changes, using docker.Service.OptimizeSize",
+		Params:        []interface{}{(*dockerSizeOptimizerParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.SizeOptimizationResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerVersionHandler": {
+		Summary:       "Parses a docker image reference passed as ?image= and reports its registry/namespace/repository/tag breakdown",
+		Description:   "Parses a docker image reference passed as ?image= and reports its registry/namespace/repository/tag breakdown. docker.Service has no daemon-version concept; the closest available \"version\" is the parsed image tag.",
+		Params:        []interface{}{(*dockerVersionParams)(nil)},
+		QueryParams:   []string{"image"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*docker.ImageInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerVolumeHandler": {
+		Summary:       "Formats a host/container path pair into a \"host:container[:ro]\" volume mount string, using docker.Service.FormatVolumeMount",
+		Params:        []interface{}{(*dockerVolumeParams)(nil)},
+		QueryParams:   []string{"host", "container", "readonly"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiDockerfileGenerateHandler": {
+		Summary:       "Decodes a JSON docker.DockerfileConfig from the request body and returns the generated Dockerfile text, using docker.Service.GenerateDockerfile",
+		Params:        []interface{}{(*dockerfileGenerateParams)(nil)},
+		Body:          (*docker.DockerfileConfig)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiEncodeHandler": {
+		Params:        []interface{}{(*encodeParams)(nil)},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiEncodeTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiFunComplimentHandler": {
+		Summary:       "Returns a single random compliment from the curated built-in list in funService.Compliment",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunDadJokeHandler": {
+		Summary:       "Returns a single random dad joke from the curated built-in list in funService.DadJoke",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunFactHandler": {
+		Summary:       "Returns a single random fact from the curated built-in list in funService.Fact",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunFortuneHandler": {
+		Summary:       "Returns a single random fortune-cookie style saying from funService.Fortune, independent of the joke-type composite endpoint",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunInsultHandler": {
+		Summary:       "Returns a single random playful mock-insult from the curated built-in list in funService.Insult",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunJokeHandler": {
+		Summary:       "Composes the existing joke-category selector with a fortune-cookie string, since no dedicated joke-text corpus exists anywhere in src/service/fun (RandomJokeType only returns a category label such as \"dad joke\", never joke text)",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunMemeHandler": {
+		Summary:       "Returns a single random text-only meme caption from the curated built-in list in funService.Meme (no image generation)",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunMotivationalHandler": {
+		Summary:       "Returns a single random motivational quote from the curated built-in list in funService.Motivational",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunProgrammingJokeHandler": {
+		Summary:       "Returns a single random programming joke from the curated built-in list in funService.ProgrammingJoke",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunQuoteHandler": {
+		Summary:       "Returns a single random inspirational quote from the curated built-in list in funService.Quote",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunRiddleHandler": {
+		Summary:       "Returns a single random riddle question/answer pair from the curated built-in list in funService.Riddle",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*fun.QAPair)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiFunTriviaHandler": {
+		Summary:       "Returns a single random trivia question/answer pair from the curated built-in list in funService.Trivia",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*fun.QAPair)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiGenerateAPIDocsHandler": {
+		Summary:       "Renders API documentation for ?format= (markdown, default, or json — which duplicates /api/swagger's structure) and ?version=, using generateService.APIDocs against the shared swagger.GenerateSpec output",
+		QueryParams:   []string{"format", "version"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateAvatarHandler": {
+		Params:        []interface{}{(*generateAvatarParams)(nil)},
+		QueryParams:   []string{"initials", "size"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateBarcodeHandler": {
+		Params:        []interface{}{(*generateBarcodeParams)(nil)},
+		QueryParams:   []string{"format", "data", "width", "height"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateConfigHandler": {
+		Summary:       "Renders a configuration-file scaffold in the requested format (yaml, json, env, toml) from the request's query parameters (excluding \"format\") as key=value pairs, using generateService.Config",
+		QueryParams:   []string{"format"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateDockerfileHandler": {
+		Summary:       "Renders a minimal idiomatic multi-stage Dockerfile for the requested language using generateService.Dockerfile",
+		QueryParams:   []string{"lang"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateGitignoreHandler": {
+		Summary:       "Renders a union of curated .gitignore boilerplate for the comma-separated languages/tools in ?lang= using generateService.Gitignore",
+		QueryParams:   []string{"lang"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateIdenticonHandler": {
+		Params:        []interface{}{(*generateIdenticonParams)(nil)},
+		QueryParams:   []string{"seed", "size"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateLicenseHandler": {
+		Summary:       "Renders canonical license text for ?type= (mit, apache-2.0, gpl-3.0, bsd-3-clause, isc), optionally substituting ?author=/?year=, using generateService.License",
+		QueryParams:   []string{"type", "author", "year"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeneratePlaceholderHandler": {
+		Params:        []interface{}{(*generatePlaceholderParams)(nil)},
+		QueryParams:   []string{"format", "bg"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateQRHandler": {
+		Summary:       "Renders a QR code PNG for the given data, or for a Wi-Fi join payload when ssid is supplied instead of data",
+		Params:        []interface{}{(*qrRequestParams)(nil)},
+		QueryParams:   []string{"data", "ssid", "password", "security", "hidden", "width", "height"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateSQLHandler": {
+		Summary:     "Decodes a JSON {\"table\": \"...\", \"columns\": [...]} body and returns the generated CREATE TABLE statement, using generateService.SQL",
+		Description: "Decodes a JSON {\"table\": \"...\", \"columns\": [...]} body and returns the generated CREATE TABLE statement, using generateService.SQL. Scope is limited to CREATE TABLE generation only.",
+		Body: (*struct {
+			Table   string               "json:\"table\""
+			Columns []generate.SQLColumn "json:\"columns\""
+		})(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGenerateSSHKeyHandler": {
+		Summary:       "Generates a stateless Ed25519 SSH key pair and returns both keys in the JSON envelope; nothing is persisted, so the private key is returned in full (this is the only time it is available)",
+		Description:   "Generates a stateless Ed25519 SSH key pair and returns both keys in the JSON envelope; nothing is persisted, so the private key is returned in full (this is the only time it is available). ?format=pem or ?format=jwk converts both keys with crypto.ConvertKey instead of the default OpenSSH formats; the fingerprints are the same whichever format is chosen.",
+		Params:        []interface{}{(*generateSSHKeyParams)(nil)},
+		QueryParams:   []string{"format"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 500},
+	},
+	"apiGeoBBoxHandler": {
+		Summary:       "Computes a bounding box either from a center coordinate plus radius in kilometers (?lat/?lon/?radius) or from a list of coordinates (?coords=lat1,lon1|lat2,lon2|...)",
+		Params:        []interface{}{(*geoBBoxParams)(nil)},
+		QueryParams:   []string{"coords", "radius"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*geo.BoundingBox)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoBearingHandler": {
+		Summary:       "Computes the initial compass bearing (in degrees) from one coordinate to another",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]float64)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoCountryHandler": {
+		Summary:       "Resolves country reference data (name, alpha-2/ alpha-3/numeric codes, capital, currency, calling code, TLD, region) from a country name, alpha-2 code, or alpha-3 code",
+		Params:        []interface{}{(*geoGeocodeParams)(nil)},
+		QueryParams:   []string{"q"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*geo.CountryInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoDistanceHandler": {
+		Summary:       "Computes the great-circle distance between two coordinates, in kilometers (default) or miles via ?unit=mi",
+		Params:        []interface{}{(*geoDistanceParams)(nil)},
+		QueryParams:   []string{"unit"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoGeocodeHandler": {
+		Summary:       "Converts an address or place name to coordinates using the free, keyless Nominatim (OpenStreetMap) search API",
+		Params:        []interface{}{(*geoGeocodeParams)(nil)},
+		QueryParams:   []string{"q"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiGeoGeohashHandler": {
+		Summary:       "Encodes a coordinate to a base32 geohash (?lat/?lon, optional ?precision, default 9), or decodes a geohash back to a coordinate (?hash)",
+		QueryParams:   []string{"hash", "precision"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoH3Handler": {
+		Summary:       "Encodes a coordinate to an Uber H3 hexagonal cell index (?lat/?lon, optional ?resolution, default 9)",
+		QueryParams:   []string{"resolution"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*geo.H3Result)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoIPHandler": {
+		Summary:       "Resolves geolocation for the {ip} path parameter",
+		Description:   "Resolves geolocation for the {ip} path parameter. The geo service package only implements coordinate math (distance, bearing, midpoint) with no IP capability, so this deliberately reuses osint.IPLookup, which already implements the required MaxMind-backed lookup plus private/loopback/link-local rejection.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*osint.IPInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoMidpointHandler": {
+		Summary:       "Computes the geographic midpoint between two coordinates",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*geo.Coordinate)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoPlusCodeHandler": {
+		Summary:       "Encodes a coordinate to a Google Open Location Code (?lat/?lon), or decodes a plus code back to a coordinate (?code)",
+		QueryParams:   []string{"code"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiGeoReverseHandler": {
+		Summary:       "Converts coordinates to a human-readable address using the free, keyless Nominatim (OpenStreetMap) reverse geocoding API",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*geo.ReverseGeocodeResult)(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiGeoTimezoneHandler": {
+		Summary:       "Resolves the IANA timezone name for a coordinate from the offline timezone-boundary dataset, falling back to the free, keyless Open-Meteo forecast API's timezone=auto resolution when no dataset is loaded",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*geo.TimezoneResult)(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiHashHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiHashMultiHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiHashTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiImageAvatarHandler": {
+		Params:        []interface{}{(*imageAvatarParams)(nil)},
+		QueryParams:   []string{"initials", "size"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageBarcodeHandler": {
+		Params:        []interface{}{(*imageBarcodeParams)(nil)},
+		QueryParams:   []string{"format", "data", "width", "height"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageConvertHandler": {
+		Params:        []interface{}{(*imageConvertParams)(nil)},
+		FormFields:    []string{"format"},
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageCropHandler": {
+		Params:        []interface{}{(*imageCropParams)(nil)},
+		FormFields:    []string{"x", "y", "width", "height", "format"},
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageFilterHandler": {
+		Params:        []interface{}{(*imageFilterParams)(nil)},
+		FormFields:    []string{"name", "amount", "format"},
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageIdenticonHandler": {
+		Params:        []interface{}{(*imageIdenticonParams)(nil)},
+		QueryParams:   []string{"seed", "size"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageMetadataHandler": {
+		Summary:       "Decodes an uploaded image and reports its dimensions, format, and byte size as JSON",
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*image.ImageInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiImageOptimizeHandler": {
+		Summary:       "Decodes an uploaded image and re-encodes it in the requested format, reporting the original and optimized byte sizes via response headers",
+		Description:   "Decodes an uploaded image and re-encodes it in the requested format, reporting the original and optimized byte sizes via response headers. quality only affects JPEG output — Go's standard library has no lossy quality knob for PNG or GIF, so those always use the encoder's fixed lossless compression; this endpoint does not invent a fake compression ratio for them.",
+		FormFields:    []string{"quality", "format"},
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiImagePlaceholderHandler": {
+		Summary:       "Generates a placeholder image of {width}x{height} and writes it as raw binary content",
+		Description:   "Generates a placeholder image of {width}x{height} and writes it as raw binary content. PART 14's JSON envelope is scoped to application/json bodies; a binary image payload is served directly with the matching Content-Type instead.",
+		Params:        []interface{}{(*imagePlaceholderParams)(nil)},
+		QueryParams:   []string{"format", "bg"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "application/octet-stream",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageQRHandler": {
+		Summary:       "Renders a QR code PNG for the given data, or for a Wi-Fi join payload when ssid is supplied instead of data",
+		Description:   "Renders a QR code PNG for the given data, or for a Wi-Fi join payload when ssid is supplied instead of data. This mirrors apiGenerateQRHandler exactly; it exists only so the /api/v1/image/qr path works the same as /api/v1/generate/qr.",
+		Params:        []interface{}{(*qrRequestParams)(nil)},
+		QueryParams:   []string{"data", "ssid", "password", "security", "hidden", "width", "height"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageResizeHandler": {
+		Params:        []interface{}{(*imageResizeParams)(nil)},
+		FormFields:    []string{"width", "height", "format"},
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiImageWatermarkHandler": {
+		Params:        []interface{}{(*imageWatermarkParams)(nil)},
+		FormFields:    []string{"text", "opacity", "format"},
+		FormFiles:     []string{"image"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/*",
+		ErrorStatuses: []int{400},
+	},
+	"apiLanguageDetectHandler": {
+		Summary:       "Reports that language auto-detection is not supported",
+		Description:   "Reports that language auto-detection is not supported. IDEA.md explicitly lists \"language auto-detection\" as a non-goal; src/service/language only offers code<->name lookup/listing, which is not detection and would misrepresent the response if reused.",
+		ErrorStatuses: []int{501},
+	},
+	"apiLanguageDictionaryHandler": {
+		Summary:       "Looks up a word's definitions using the free, keyless Free Dictionary API (dictionaryapi.dev)",
+		Params:        []interface{}{(*languageDictionaryParams)(nil)},
+		QueryParams:   []string{"word"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*language.DictionaryResult)(nil),
+		ErrorStatuses: []int{400, 404},
+	},
+	"apiLanguageGrammarHandler": {
+		Summary:       "Reports that grammar checking is not supported",
+		Description:   "Reports that grammar checking is not supported. Not named in IDEA.md's declared Language scope of code/name lookup, listing, dictionary, and thesaurus.",
+		ErrorStatuses: []int{501},
+	},
+	"apiLanguageKeywordsHandler": {
+		Summary:       "Returns the most frequent non-stopword words in text supplied via ?text= or the raw request body, optionally limited via ?limit=",
+		Params:        []interface{}{(*languageKeywordsParams)(nil), (*languageKeywordsLimitParams)(nil)},
+		QueryParams:   []string{"text", "limit"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiLanguagePhoneticHandler": {
+		Summary:       "Returns the Soundex and Metaphone phonetic codes for a word supplied via ?word=",
+		Params:        []interface{}{(*languagePhoneticParams)(nil)},
+		QueryParams:   []string{"word"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiLanguageReadabilityHandler": {
+		Summary:       "Returns Flesch Reading Ease, Flesch-Kincaid Grade Level, and Gunning Fog Index scores for text supplied via ?text= or the raw request body",
+		Params:        []interface{}{(*languageReadabilityParams)(nil)},
+		QueryParams:   []string{"text"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*language.ReadabilityStats)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiLanguageReadingTimeHandler": {
+		Summary:       "Estimates reading time for text supplied via ?text= or the raw request body, at an optional ?wpm= words-per-minute rate (default 200)",
+		Params:        []interface{}{(*languageReadingTimeParams)(nil), (*languageReadingTimeWpmParams)(nil)},
+		QueryParams:   []string{"text", "wpm"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*language.ReadingTimeStats)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiLanguageSentimentHandler": {
+		Summary:       "Scores text supplied via ?text= or the raw request body using a small lexicon-based positive/negative heuristic",
+		Params:        []interface{}{(*languageSentimentParams)(nil)},
+		QueryParams:   []string{"text"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*language.SentimentResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiLanguageSpellCheckHandler": {
+		Summary:       "Reports that spell-checking is not supported",
+		Description:   "Reports that spell-checking is not supported. Not named in IDEA.md's declared Language scope of code/name lookup, listing, dictionary, and thesaurus.",
+		ErrorStatuses: []int{501},
+	},
+	"apiLanguageThesaurusHandler": {
+		Summary:       "Looks up a word's synonyms and antonyms using the free, keyless Datamuse API",
+		Params:        []interface{}{(*languageThesaurusParams)(nil)},
+		QueryParams:   []string{"word"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*language.ThesaurusResult)(nil),
+		ErrorStatuses: []int{400, 404},
+	},
+	"apiLanguageTranslateHandler": {
+		Summary:       "Reports that machine translation is not supported",
+		Description:   "Reports that machine translation is not supported. IDEA.md explicitly excludes \"machine translation\" as a non-goal and forbids commercial translation among outbound integrations.",
+		ErrorStatuses: []int{501},
+	},
+	"apiLanguageWordCountHandler": {
+		Summary:       "Returns word/character/line/sentence counts for text supplied via ?text= or the raw request body",
+		Params:        []interface{}{(*languageWordCountParams)(nil)},
+		QueryParams:   []string{"text"},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*language.WordStats)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiLoremAddressHandler": {
+		Summary:       "Generates a fake street address",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiLoremCompanyHandler": {
+		Summary:       "Generates a fake company name and catchphrase",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiLoremHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiLoremPersonHandler": {
+		Summary:       "Generates a fake person (name/email/phone)",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiLoremTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiMathBaseHandler": {
+		Summary:       "Converts ?number= from ?from_base= to ?to_base= using math.Service.BaseConvert (or BigBaseConvert with ?mode=bigint, for numbers wider than int64); both bases must be between 2 and 36",
+		Params:        []interface{}{(*mathBaseParams)(nil), (*mathModeParams)(nil)},
+		QueryParams:   []string{"number", "from_base", "to_base", "precision", "mode"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathCalculateHandler": {
+		Summary:       "Dispatches to a math.Service operation selected by ?operation=, composing the existing named methods",
+		Description:   "Dispatches to a math.Service operation selected by ?operation=, composing the existing named methods. The \"expression\" operation instead evaluates ?expression= with math.Service.Evaluate, a sandboxed parser over the same methods with variables bound from ?variables=name:value,... (never eval'd or executed). ?mode=bigint and ?mode=bigfloat (or ?precision=) route to mathBigIntCalculate and mathBigFloatCalculate instead.",
+		Params:        []interface{}{(*mathCalculateParams)(nil), (*mathModeParams)(nil)},
+		QueryParams:   []string{"operation", "expression", "variables", "mode", "precision"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]float64)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathFibonacciHandler": {
+		Summary:       "Returns the first {count} Fibonacci numbers using math.Service.Fibonacci; ?count= is required and must be a non-negative integer",
+		Params:        []interface{}{(*mathFibonacciParams)(nil)},
+		QueryParams:   []string{"count"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathMatrixHandler": {
+		Summary:       "Performs add, multiply, or determinant on the matrices supplied in the JSON request body, using the corresponding math.Service Matrix* method",
+		Params:        []interface{}{(*matrixRequest)(nil)},
+		Body:          (*matrixRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathModInverseHandler": {
+		Summary:       "Finds x with ?a= * x ≡ 1 (mod ?modulus=) using math.Service.ModInverse, reporting NO_MODULAR_INVERSE when a and the modulus are not coprime",
+		Params:        []interface{}{(*mathModInverseParams)(nil)},
+		QueryParams:   []string{"a", "modulus"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathModPowHandler": {
+		Summary:       "Computes ?base=^?exponent= mod ?modulus= with math.Service.ModPow; a negative exponent uses the modular inverse",
+		Params:        []interface{}{(*mathModPowParams)(nil)},
+		QueryParams:   []string{"base", "exponent", "modulus"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathPrimeHandler": {
+		Summary:       "Reports whether the {n} path parameter is a prime number, using math.Service.IsPrime",
+		Description:   "Reports whether the {n} path parameter is a prime number, using math.Service.IsPrime. Values beyond int64, or any value with ?mode=bigint, are tested with math.Service.IsProbablePrime (Miller-Rabin) instead. Bigfloat mode and ?precision= are rejected.",
+		Params:        []interface{}{(*mathModeParams)(nil)},
+		QueryParams:   []string{"precision", "mode"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathRandomHandler": {
+		Summary:       "Returns a random integer in the inclusive range [{min}, {max}] using math.Service.RandomInt",
+		Params:        []interface{}{(*mathRandomParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathSequenceHandler": {
+		Summary:       "Generates ?count= numbers of ?type= (arithmetic or geometric) starting at ?start= and stepping by ?step=, using math.Service.Sequence",
+		Params:        []interface{}{(*mathSequenceParams)(nil), (*mathFibonacciParams)(nil)},
+		QueryParams:   []string{"type", "start", "step", "count"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiMathStatsHandler": {
+		Summary:       "Computes min/max/sum/average/median over the comma-separated ?numbers= query parameter, using the corresponding math.Service methods",
+		Params:        []interface{}{(*mathStatsParams)(nil)},
+		QueryParams:   []string{"numbers"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkCallerHandler": {
+		Summary:  "Returns the caller's resolved IP/port and the caller-identifying request headers",
+		Format:   swagger.FormatEnvelope,
+		Response: (*network.CallerInfo)(nil),
+	},
+	"apiNetworkDNSHandler": {
+		Summary:       "Queries DNS records for a domain via the system resolver, composing the existing free/keyless osint.DNSLookup function",
+		Description:   "Queries DNS records for a domain via the system resolver, composing the existing free/keyless osint.DNSLookup function. Defaults to an A-record lookup when no record type is given.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkMACVendorHandler": {
+		Summary:       "Looks up the vendor for a MAC address's OUI prefix",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkPingHandler": {
+		Summary:       "Measures TCP connect round-trip latency to ?host= (optionally ?count=, default 4, max 20) using network.Service.Ping",
+		Params:        []interface{}{(*networkPingParams)(nil)},
+		QueryParams:   []string{"host", "count"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*network.PingResult)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkPortHandler": {
+		Summary:       "Suggests a random unprivileged port",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]int)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiNetworkSSLHandler": {
+		Summary:       "Reports the leaf TLS certificate details for ?host= using network.Service.SSLInfo",
+		Params:        []interface{}{(*networkSSLParams)(nil)},
+		QueryParams:   []string{"host"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*network.SSLCertInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkSubnetHandler": {
+		Summary:       "Computes network/broadcast/host details for a CIDR block passed as ?cidr=",
+		Params:        []interface{}{(*networkSubnetParams)(nil)},
+		QueryParams:   []string{"cidr"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*network.SubnetInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkTracerouteHandler": {
+		Summary:       "Honestly reports 501 NOT_SUPPORTED: a real traceroute requires sending TTL-limited probes and receiving ICMP time-exceeded replies, which needs a raw ICMP socket (CAP_NET_RAW or root)",
+		Description:   "Honestly reports 501 NOT_SUPPORTED: a real traceroute requires sending TTL-limited probes and receiving ICMP time-exceeded replies, which needs a raw ICMP socket (CAP_NET_RAW or root). This project ships as an unprivileged, non-root, self-contained binary with no guarantee of that capability on the host it runs on, so this honestly reports unsupported rather than shipping a fake TCP-connect approximation mislabeled as \"traceroute\". See TODO.AI.md \"Known permanent API gaps\" for the same pattern applied to generate/qr, language/detect, and research/extract.",
+		ErrorStatuses: []int{501},
+	},
+	"apiNetworkULAHandler": {
+		Summary:       "Generates an RFC 4193 IPv6 unique-local-address prefix",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{500},
+	},
+	"apiNetworkURLHandler": {
+		Summary:       "Parses ?url= into its component parts using network.Service.ParseURL",
+		Params:        []interface{}{(*networkURLParams)(nil)},
+		QueryParams:   []string{"url"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*network.URLInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiNetworkUserAgentHandler": {
+		Summary:     "Parses the caller's User-Agent header (or an explicit ?ua= override) into browser/OS/device components",
+		QueryParams: []string{"ua"},
+		Format:      swagger.FormatEnvelope,
+		Response:    (*parse.UserAgent)(nil),
+	},
+	"apiNetworkWhoisHandler": {
+		Summary:       "Looks up WHOIS information for ?domain= using network.Service.Whois",
+		Params:        []interface{}{(*networkWhoisParams)(nil)},
+		QueryParams:   []string{"domain"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiOsintBreachHandler": {
+		Summary:       "Reports that breach-database checking is not supported",
+		Description:   "Reports that breach-database checking is not supported. Free breach-check services (e.g. HaveIBeenPwned) require an API key for domain/bulk search, which is outside IDEA.md's declared free-and-keyless OSINT trust boundary (IDEA.md line 34: OSINT is scoped to IP geolocation, WHOIS, DNS, and TLS certificate inspection only).",
+		ErrorStatuses: []int{501},
+	},
+	"apiOsintCertHandler": {
+		Summary:       "Connects to the {domain} path parameter (host:443 by default, or host:port if a port is present) and reports the peer TLS certificate's details via osint.SSLInfo",
+		Description:   "Connects to the {domain} path parameter (host:443 by default, or host:port if a port is present) and reports the peer TLS certificate's details via osint.SSLInfo. Its chain field can be posted to /crypto/certificate/verify as-is.",
+		Params:        []interface{}{(*osintCertParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiOsintCompanyHandler": {
+		Summary:       "Reports that company-data lookup is not supported",
+		Description:   "Reports that company-data lookup is not supported. Company enrichment (e.g. Clearbit-style lookups) is a commercial, keyed service — outside IDEA.md's declared free-and-keyless OSINT scope (IP geolocation, WHOIS, DNS, TLS cert only) and forbidden by the non-goals list's ban on paid/keyed third-party APIs.",
+		ErrorStatuses: []int{501},
+	},
+	"apiOsintDomainHandler": {
+		Summary:       "Performs a free, keyless WHOIS lookup for the {domain} path parameter via osint.WHOISLookup",
+		Params:        []interface{}{(*osintDomainParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*osint.DomainInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiOsintEmailHandler": {
+		Summary:       "Validates the {email} path parameter's format and checks whether its domain has mail-exchange (MX) records, composing validate.IsEmail, parse.ParseEmail, and osint.DNSLookup — all free, keyless, and already exported",
+		Description:   "Validates the {email} path parameter's format and checks whether its domain has mail-exchange (MX) records, composing validate.IsEmail, parse.ParseEmail, and osint.DNSLookup — all free, keyless, and already exported. No dedicated email-OSINT function exists in src/service/osint.",
+		Params:        []interface{}{(*osintEmailParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiOsintIPHandler": {
+		Summary:       "Resolves geolocation/ISP intelligence for the {ip} path parameter via the shared osintService.IPLookup (same underlying implementation as apiGeoIPHandler)",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*osint.IPInfo)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiOsintMetadataHandler": {
+		Summary:       "Reports that generic file-metadata extraction is not supported as an OSINT tool",
+		Description:   "Reports that generic file-metadata extraction is not supported as an OSINT tool. IDEA.md scopes OSINT to IP geolocation, WHOIS, DNS, and TLS certificate inspection only — file-metadata extraction is not one of those mechanisms, and image/document metadata extraction already exists as its own tool (apiImageMetadataHandler); duplicating it here would invent behavior IDEA.md does not scope to OSINT.",
+		ErrorStatuses: []int{501},
+	},
+	"apiOsintPhoneHandler": {
+		Summary:       "Reports that phone-number intelligence lookup is not supported",
+		Description:   "Reports that phone-number intelligence lookup is not supported. Carrier/line-type/reputation lookup services are commercial and keyed — outside IDEA.md's declared free-and-keyless OSINT scope; basic phone-number format validation is already covered by validate/phone.",
+		ErrorStatuses: []int{501},
+	},
+	"apiOsintSocialHandler": {
+		Summary:       "Reports that social-media profile discovery is not supported",
+		Description:   "Reports that social-media profile discovery is not supported. Finding a person's social profiles means firing off requests to dozens of third-party platforms — a much larger and fundamentally different outbound surface than IDEA.md's four narrowly-scoped OSINT mechanisms (IP geolocation, WHOIS, DNS, TLS cert), and not user-directed to a single target the caller names.",
+		ErrorStatuses: []int{501},
+	},
+	"apiOsintSubdomainHandler": {
+		Summary:       "Discovers subdomains of the {domain} path parameter by resolving a small fixed wordlist of common subdomain labels via the system DNS resolver (osint.SubdomainEnum) — the same trust boundary already used by osint.DNSLookup",
+		Params:        []interface{}{(*osintSubdomainParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiOsintTechStackHandler": {
+		Summary:       "Performs a single direct HTTP GET to the ?url= query parameter and reports technology signatures observed in the response headers/cookies/HTML via osint.TechStack — analogous in shape to apiOsintCertHandler's direct TLS handshake, one direct user-directed connection only",
+		Params:        []interface{}{(*osintTechStackParams)(nil)},
+		QueryParams:   []string{"url"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*osint.TechStackInfo)(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiOsintUsernameHandler": {
+		Summary:       "Reports that cross-platform username enumeration is not supported, for the same reason as apiOsintSocialHandler: checking a username against dozens of third-party sites is a much larger and different outbound surface than IDEA.md's four declared OSINT mechanisms",
+		ErrorStatuses: []int{501},
+	},
+	"apiPINHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiPINTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiParseCSVHandler": {
+		Summary:       "Parses the raw CSV document supplied in the request body (first row treated as headers) via parseService.ParseCSV",
+		Params:        []interface{}{(*parseCSVParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*[]map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseEnvHandler": {
+		Summary:       "Parses the raw .env-style document supplied in the request body via parseService.ParseEnv",
+		Params:        []interface{}{(*parseEnvParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseHTMLHandler": {
+		Summary:       "Parses the raw HTML document supplied in the request body into a structural summary via parseService.ParseHTML",
+		Params:        []interface{}{(*parseHTMLParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*parse.HTMLSummary)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseINIHandler": {
+		Summary:       "Parses the raw INI document supplied in the request body via parseService.ParseINI",
+		Params:        []interface{}{(*parseINIParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]map[string]string)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseJSONHandler": {
+		Summary:       "Parses the raw JSON document supplied in the request body into a generic map",
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseJWTHandler": {
+		Summary:       "Decodes (never verifies) the header and payload of a JSON Web Token supplied via the {token} path parameter",
+		Description:   "Decodes (never verifies) the header and payload of a JSON Web Token supplied via the {token} path parameter. This reuses the exact same decodeJWTSegment helper as apiCryptoJWTDecodeHandler in the crypto category — parse and crypto both expose a JWT decode tool over the same underlying logic, matching the established cross-category reuse pattern (e.g. apiOsintIPHandler/apiGeoIPHandler).",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseLogHandler": {
+		Summary:       "Parses the raw log document supplied in the request body, one best-effort entry per line, via parseService.ParseLogLines",
+		Params:        []interface{}{(*parseLogParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*[]parse.LogEntry)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseMarkdownHandler": {
+		Summary:       "Parses the raw Markdown document supplied in the request body into a structure summary via parseService.ParseMarkdownStructure",
+		Params:        []interface{}{(*parseMarkdownParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*parse.MarkdownStructure)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseSQLHandler": {
+		Summary:       "Parses the raw SQL statement supplied in the request body into a best-effort structure summary via parseService.ParseSQLStructure",
+		Params:        []interface{}{(*parseSQLParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*parse.SQLStructure)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseTOMLHandler": {
+		Summary:       "Parses the raw TOML document supplied in the request body via parseService.ParseTOML",
+		Params:        []interface{}{(*parseTOMLParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseXMLHandler": {
+		Summary:       "Parses the raw XML document supplied in the request body into a generic map, reusing the existing parseService.ParseXML",
+		Params:        []interface{}{(*parseXMLParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiParseYAMLHandler": {
+		Summary:       "Parses the raw YAML document supplied in the request body via parseService.ParseYAML",
+		Params:        []interface{}{(*parseYAMLParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiPasswordHandler": {
+		QueryParams:   []string{"uppercase", "lowercase", "numbers", "symbols", "exclude_similar"},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiPasswordStrengthHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiPasswordStrengthPostHandler": {
+		Body: (*struct {
+			Password   string   "json:\"password\""
+			UserInputs []string "json:\"user_inputs\""
+		})(nil),
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiPasswordTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiROT13Handler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiROT13TextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiRandomBytesHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiRandomHexHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiResearchArxivHandler": {
+		Summary:       "Looks up an arXiv paper by ID (JSON body {\"id\":\"...\"} or ?id= query parameter) using the free, keyless arXiv query API",
+		Params:        []interface{}{(*researchArxivParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*research.ArxivResult)(nil),
+		ErrorStatuses: []int{400, 404},
+	},
+	"apiResearchBibtexHandler": {
+		Summary:       "Reports that BibTeX parsing/formatting is not supported",
+		Description:   "Reports that BibTeX parsing/formatting is not supported. IDEA.md's declared Research scope covers only citation formatting (APA/MLA/Chicago), bibliography generation, and DOI formatting/validation — BibTeX is a distinct format not named in scope.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchCitationHandler": {
+		Summary:       "Formats a single caller-supplied reference into a citation string",
+		Description:   "Formats a single caller-supplied reference into a citation string. It reuses research.Service.GenerateBibliography with a one-element slice rather than re-implementing the APA/MLA/Chicago style switch, so the single-citation and bibliography code paths can never drift apart.",
+		Params:        []interface{}{(*researchCitationParams)(nil)},
+		Body:          (*researchCitationRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiResearchDOIHandler": {
+		Summary:       "Validates the wildcard path suffix as a DOI and returns its canonical resolver URL, using research.Service.ValidateDOI/FormatDOI",
+		Description:   "Validates the wildcard path suffix as a DOI and returns its canonical resolver URL, using research.Service.ValidateDOI/FormatDOI. A wildcard route (rather than a {doi} chi.URLParam) is required because DOIs always contain at least one \"/\" (prefix/suffix, e.g. \"10.1000/182\"), which a single path segment parameter cannot capture.",
+		Params:        []interface{}{(*researchDoiParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiResearchExtractHandler": {
+		Summary:       "Reports that citation extraction from unstructured text is not supported",
+		Description:   "Reports that citation extraction from unstructured text is not supported. research.go's own source comment documents this as unimplemented (\"Full research service could include: citation extraction from text\") — only pre-structured citation formatting (APA/MLA/Chicago) and DOI validation exist.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchFootnotesHandler": {
+		Summary:       "Reports that footnote/endnote formatting is not supported",
+		Description:   "Reports that footnote/endnote formatting is not supported. Not named in IDEA.md's declared Research scope.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchIsbnHandler": {
+		Summary:       "Looks up a book's metadata by ISBN (JSON body {\"isbn\":\"...\"} or ?isbn= query parameter) using the free, keyless Open Library Books API",
+		Params:        []interface{}{(*researchIsbnParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*research.ISBNResult)(nil),
+		ErrorStatuses: []int{400, 404},
+	},
+	"apiResearchMetadataHandler": {
+		Summary:       "Reports that web-page metadata extraction is not supported",
+		Description:   "Reports that web-page metadata extraction is not supported. Not named in IDEA.md's declared Research scope, and fetching an arbitrary caller-supplied URL (rather than querying a single fixed, trusted provider) is a broader SSRF surface than this project takes on.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchOutlineHandler": {
+		Summary:       "Reports that document outline generation is not supported",
+		Description:   "Reports that document outline generation is not supported. Not named in IDEA.md's declared Research scope; heading extraction from Markdown is already covered by parse/markdown.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchPdfExtractHandler": {
+		Summary:       "Reports that PDF text extraction is not supported",
+		Description:   "Reports that PDF text extraction is not supported. It would require a new third-party PDF-parsing dependency, and is not named in IDEA.md's declared Research scope.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchReadabilityHandler": {
+		Summary:       "Reports that reader-mode article extraction is not supported",
+		Description:   "Reports that reader-mode article extraction is not supported. Not named in IDEA.md's declared Research scope, and fetching an arbitrary caller-supplied URL (rather than querying a single fixed, trusted provider) is a broader SSRF surface than this project takes on.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchScraperHandler": {
+		Summary:       "Reports that general web scraping is not supported",
+		Description:   "Reports that general web scraping is not supported. It would require fetching arbitrary caller-supplied URLs, a broader SSRF surface than the narrow, mitigated outbound-call mechanisms (fixed, trusted providers) this project uses elsewhere.",
+		ErrorStatuses: []int{501},
+	},
+	"apiResearchSummarizeHandler": {
+		Summary:       "Reports that text summarization is not supported",
+		Description:   "Reports that text summarization is not supported. A genuine summarizer needs an external/keyed NLP or LLM service, which IDEA.md excludes (\"every outbound integration must be free and keyless\"); it is not named in IDEA.md's declared Research scope.",
+		ErrorStatuses: []int{501},
+	},
+	"apiReverseHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiReverseTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiSwaggerSpecHandler": {
+		Summary:     "Serves the OpenAPI JSON spec",
+		Description: "Serves the OpenAPI JSON spec. Mounted at both /api/swagger (unversioned alias) and /api/v1/server/swagger (versioned canonical) — same handler, no redirect, per PART 14. JSON only, no YAML.",
+	},
+	"apiTOTPCodeHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiTOTPCodeTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiTOTPGenerateHandler": {
+		QueryParams:   []string{"issuer", "account"},
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiTOTPVerifyHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiTestAPIClientHandler": {
+		Summary:       "Renders a caller-described HTTP request as generated client code snippets (curl, JavaScript fetch, Python requests, and Go net/http) so a developer can copy working request code for their language of choice",
+		Description:   "Renders a caller-described HTTP request as generated client code snippets (curl, JavaScript fetch, Python requests, and Go net/http) so a developer can copy working request code for their language of choice. Pure string templating of the caller's own input, no outbound call.",
+		Body:          (*testRequestSpec)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestAssertHandler": {
+		Summary:       "Runs one of the five test.Service assertion helpers (equal/not_equal/contains/true/false) against caller-supplied values and returns the resulting pass/fail TestResult",
+		Description:   "Runs one of the five test.Service assertion helpers (equal/not_equal/contains/true/false) against caller-supplied values and returns the resulting pass/fail TestResult. It dispatches to the existing Assert* methods rather than re-implementing the comparison logic, so the CLI/API behavior always matches whatever the underlying assertion helpers do.",
+		Params:        []interface{}{(*testAssertParams)(nil)},
+		Body:          (*testAssertRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestCurlGeneratorHandler": {
+		Summary:       "Renders a caller-described HTTP request as a single curl command string",
+		Description:   "Renders a caller-described HTTP request as a single curl command string. Pure string formatting of the caller's own input, no outbound call.",
+		Body:          (*testRequestSpec)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestFakeDataHandler": {
+		Summary:       "Generates fake test data (email, username, or a full mock user) via the existing test.Service generators, selected by the ?type= query parameter (default \"user\")",
+		Params:        []interface{}{(*testFakeDataParams)(nil)},
+		QueryParams:   []string{"type", "prefix"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestFixtureHandler": {
+		Summary:       "Returns a named test fixture, dispatching to test.Service.GenerateFixture rather than re-implementing per-type fixture shapes here",
+		Params:        []interface{}{(*testFixtureParams)(nil)},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestHTTPHandler": {
+		Summary:     "Exercises the mock HTTP response fixture generator under a measured execution window",
+		Description: "Exercises the mock HTTP response fixture generator under a measured execution window. IDEA.md restricts outbound network calls to only the OSINT and weather tool families, so this cannot make a live request to an arbitrary target; it composes the existing mock/fixture and timing utilities instead.",
+		Format:      swagger.FormatEnvelope,
+		Response:    (*map[string]interface{})(nil),
+	},
+	"apiTestLoadTestHandler": {
+		Summary:       "Test load test is a permanent gap: a real load-test tool must fire real, potentially high-volume outbound HTTP traffic at a caller-supplied target URL",
+		Description:   "Test load test is a permanent gap: a real load-test tool must fire real, potentially high-volume outbound HTTP traffic at a caller-supplied target URL. IDEA.md restricts outbound calls to only the OSINT and weather tool families (a bounded, intentional SSRF surface) — deliberately generating load against an arbitrary target is outside that boundary and would turn this server into an abuse/DoS proxy.",
+		ErrorStatuses: []int{501},
+	},
+	"apiTestMockServerHandler": {
+		Summary:       "Test mock server is a permanent gap: a configurable mock HTTP server requires either a second runtime-managed listening socket (no dynamic-listener lifecycle exists in this codebase, and config-rules.md forbids a runtime API for listener/port changes) or persisting caller-defined response rules across requests (forbidden by IDEA.md's no-persistent-storage-of-user-submitted-data non-goal)",
+		Description:   "Test mock server is a permanent gap: a configurable mock HTTP server requires either a second runtime-managed listening socket (no dynamic-listener lifecycle exists in this codebase, and config-rules.md forbids a runtime API for listener/port changes) or persisting caller-defined response rules across requests (forbidden by IDEA.md's no-persistent-storage-of-user-submitted-data non-goal). Both readings hit a declared non-goal.",
+		ErrorStatuses: []int{501},
+	},
+	"apiTestPostmanHandler": {
+		Summary:       "Renders a caller-described HTTP request as a minimal Postman Collection v2.1 JSON document containing that single request",
+		Description:   "Renders a caller-described HTTP request as a minimal Postman Collection v2.1 JSON document containing that single request. Pure JSON templating of the caller's own input, no outbound call, no persistence.",
+		Body:          (*testRequestSpec)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*postmanCollection)(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestRequestInspectorHandler": {
+		Summary:     "Echoes back the caller's own request: method, path, query parameters, headers, and raw body",
+		Description: "Echoes back the caller's own request: method, path, query parameters, headers, and raw body. Directly analogous to the already-shipped network.Service.CallerInfo pattern (IDEA.md's declared caller/header-inspection scope) — no storage, no outbound call.",
+		RawBody:     true,
+		Format:      swagger.FormatEnvelope,
+		Response:    (*map[string]interface{})(nil),
+	},
+	"apiTestResponseGeneratorHandler": {
+		Summary:     "Generates a mock API response fixture",
+		Description: "Generates a mock API response fixture. IDEA.md's declared Testing scope names only one mock-API-response generator, which already exists as test.Service.GenerateMockAPIResponse — this dispatches to it directly rather than inventing a broader parameterized (arbitrary status/header/body) response builder that IDEA.md does not declare.",
+		Format:      swagger.FormatEnvelope,
+		Response:    (*map[string]interface{})(nil),
+	},
+	"apiTestStatusCodesHandler": {
+		Summary:       "Returns a single HTTP status code's canonical reason phrase and description when a {code} path parameter is given, or the full reference table when it is omitted",
+		Description:   "Returns a single HTTP status code's canonical reason phrase and description when a {code} path parameter is given, or the full reference table when it is omitted. Static lookup data only, no outbound call, no persistence.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTestWebhookHandler": {
+		Summary:     "Accepts a caller-submitted webhook POST and echoes back a structured inspection of it (headers, parsed/raw body) in the same response cycle",
+		Description: "Accepts a caller-submitted webhook POST and echoes back a structured inspection of it (headers, parsed/raw body) in the same response cycle. This is a stateless same-request echo, not a receive-then-inspect-later store: IDEA.md's non-goals forbid persistent storage of user-submitted data, so no payload is retained past this request.",
+		RawBody:     true,
+		Format:      swagger.FormatEnvelope,
+		Response:    (*map[string]interface{})(nil),
+	},
+	"apiTextCompressHandler": {
+		Summary:       "Compresses or decompresses data using text.Compress/text.Decompress, both base64-encoded on the wire so the result is always safe JSON",
+		Description:   "Compresses or decompresses data using text.Compress/text.Decompress, both base64-encoded on the wire so the result is always safe JSON. Mode defaults to \"compress\"; Algorithm defaults to \"gzip\" (zlib and flate/deflate are also supported by the underlying service — brotli/zstd are not implemented).",
+		Params:        []interface{}{(*textCompressParams)(nil)},
+		Body:          (*textCompressRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTextCompressStreamHandler": {
+		Summary:       "Compresses the raw request body with {algorithm} and streams the compressed bytes back",
+		Description:   "Compresses the raw request body with {algorithm} and streams the compressed bytes back. The body may be up to server.stream.compress_max_mb megabytes.",
+		Params:        []interface{}{(*textCompressStreamParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatRaw,
+		ContentType:   "application/octet-stream",
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiTextDecodeStreamHandler": {
+		Summary:       "Decodes the {encoding} text in the raw request body and streams the decoded bytes back as they are produced",
+		Description:   "Decodes the {encoding} text in the raw request body and streams the decoded bytes back as they are produced. The body may be up to server.stream.encode_max_mb megabytes. Input that turns out to be invalid part-way through ends the output early with an X-Stream-Error trailer.",
+		Params:        []interface{}{(*decodeParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatRaw,
+		ContentType:   "application/octet-stream",
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiTextDecompressStreamHandler": {
+		Summary:       "Decompresses the {algorithm} data in the raw request body and streams the original bytes back",
+		Description:   "Decompresses the {algorithm} data in the raw request body and streams the original bytes back. Both the body and the decompressed output are capped at server.stream.compress_max_mb megabytes, so a small compression bomb cannot produce unbounded output.",
+		Params:        []interface{}{(*textCompressStreamParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatRaw,
+		ContentType:   "application/octet-stream",
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiTextDiffHandler": {
+		Summary:       "Returns a unified line diff between two texts using text.Diff",
+		Body:          (*textDiffRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTextEncodeStreamHandler": {
+		Summary:       "Encodes the raw request body with {encoding} and streams the encoded text back as it is produced",
+		Description:   "Encodes the raw request body with {encoding} and streams the encoded text back as it is produced. The body may be up to server.stream.encode_max_mb megabytes.",
+		Params:        []interface{}{(*encodeParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatRaw,
+		ContentType:   "text/plain",
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiTextExtractHandler": {
+		Summary:       "Pulls emails, URLs, IPs, or phone numbers out of free-form text using the matching text.Extract* function",
+		Params:        []interface{}{(*textExtractParams)(nil)},
+		Body:          (*textExtractRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTextHashMultiStreamHandler": {
+		Summary:       "Hashes the raw request body with every supported algorithm in a single pass",
+		Description:   "Hashes the raw request body with every supported algorithm in a single pass. The body may be up to server.stream.hash_max_mb megabytes.",
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiTextHashStreamHandler": {
+		Summary:       "Hashes the raw request body with {algorithm} and returns the hex digest and the number of bytes hashed",
+		Description:   "Hashes the raw request body with {algorithm} and returns the hex digest and the number of bytes hashed. The body may be up to server.stream.hash_max_mb megabytes.",
+		Params:        []interface{}{(*textHashStreamParams)(nil)},
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 413},
+	},
+	"apiTextNanoIDHandler": {
+		Summary:  "Returns a newly generated NanoID via text.NanoID",
+		Format:   swagger.FormatEnvelope,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiTextRegexHandler": {
+		Summary:       "Tests a regular expression against input text",
+		Description:   "Tests a regular expression against input text. Mode \"match\" (the default) returns every match via text.RegexMatch; mode \"replace\" substitutes Replacement via text.RegexReplace; mode \"explain\" returns a human-readable breakdown via text.RegexExplain.",
+		Params:        []interface{}{(*textRegexParams)(nil)},
+		Body:          (*textRegexRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiTextStatsHandler": {
+		Body: (*struct {
+			Text string "json:\"text\""
+		})(nil),
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiTextULIDHandler": {
+		Summary:  "Returns a newly generated ULID via text.ULID",
+		Format:   swagger.FormatEnvelope,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiTimestampHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiTimestampTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiTimezoneInfoHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiTimezonesHandler": {
+		Format:   swagger.FormatJSON,
+		Response: (*map[string]interface{})(nil),
+	},
+	"apiToUnixHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+		LegacyErrors:  true,
+	},
+	"apiUUIDBatchHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiUUIDHandler": {
+		Format:        swagger.FormatJSON,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{500},
+		LegacyErrors:  true,
+	},
+	"apiUUIDTextHandler": {
+		Format: swagger.FormatText,
+	},
+	"apiValidateCreditCardHandler": {
+		Summary:       "Validates a credit card number (Luhn check) supplied as ?number= or a JSON {\"number\":\"...\"} body",
+		Params:        []interface{}{(*validateCreditCardParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateDomainHandler": {
+		Summary:       "Validates a domain name supplied as ?domain= or a JSON {\"domain\":\"...\"} body",
+		Params:        []interface{}{(*validateDomainParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateEmailHandler": {
+		Summary:     "Validates the email address supplied in the JSON body ({\"email\":\"...\"}) or as an ?email= query parameter",
+		Params:      []interface{}{(*validateEmailParams)(nil)},
+		QueryParams: []string{"email"},
+		Body: (*struct {
+			Email string "json:\"email\""
+		})(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateIBANHandler": {
+		Summary:       "Validates an IBAN supplied as ?iban= or a JSON {\"iban\":\"...\"} body against the ISO 13616 mod-97 checksum",
+		Params:        []interface{}{(*validateIBANParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateIPHandler": {
+		Summary:       "Validates an IPv4 or IPv6 address supplied as ?ip= or a JSON {\"ip\":\"...\"} body",
+		Params:        []interface{}{(*validateIPParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateISBNHandler": {
+		Summary:       "Validates an ISBN-10 or ISBN-13 supplied as ?isbn= or a JSON {\"isbn\":\"...\"} body",
+		Params:        []interface{}{(*validateISBNParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateJSONHandler": {
+		Summary:       "Validates that the raw request body is well-formed JSON",
+		RawBody:       true,
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateMACHandler": {
+		Summary:       "Validates a MAC address supplied as ?mac= or a JSON {\"mac\":\"...\"} body",
+		Params:        []interface{}{(*validateMACParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidatePasswordHandler": {
+		Summary:       "Rates the password in the JSON body weak, medium or strong and reports it valid when it reaches min_strength (default medium)",
+		Description:   "Rates the password in the JSON body weak, medium or strong and reports it valid when it reaches min_strength (default medium). Optional user_inputs, such as the account's name and email, are words the password is checked against. The password is not echoed back.",
+		Params:        []interface{}{(*validatePasswordParams)(nil)},
+		Body:          (*validatePasswordRequest)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidatePhoneHandler": {
+		Summary:       "Validates a phone number supplied as ?phone= or a JSON {\"phone\":\"...\"} body",
+		Params:        []interface{}{(*validatePhoneParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateURLHandler": {
+		Summary:       "Validates a URL supplied as ?url= or a JSON {\"url\":\"...\"} body",
+		Params:        []interface{}{(*validateURLParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateUUIDHandler": {
+		Summary:       "Validates a UUID supplied as ?uuid= or a JSON {\"uuid\":\"...\"} body",
+		Params:        []interface{}{(*validateUUIDParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiValidateVATHandler": {
+		Summary:       "Validates the structural format of an EU/UK/CH/NO VAT registration number supplied as ?vat= or a JSON {\"vat\":\"...\"} body",
+		Params:        []interface{}{(*validateVATParams)(nil)},
+		Body:          (*map[string]string)(nil),
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400},
+	},
+	"apiWeatherAirQualityHandler": {
+		Summary:       "Returns current air quality (AQI and pollutant concentrations) for the {location} path parameter",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*weather.AirQuality)(nil),
+		ErrorStatuses: []int{502},
+	},
+	"apiWeatherAlertsHandler": {
+		Summary:       "Returns active government weather alerts for the {location} path parameter, aggregated across NWS (US), Environment Canada (CA), and MeteoAlarm (Europe), normalized into a uniform shape",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{502},
+	},
+	"apiWeatherAstronomyHandler": {
+		Summary:       "Returns sunrise/sunset and daylight data for the {location} path parameter",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*weather.Astronomy)(nil),
+		ErrorStatuses: []int{502},
+	},
+	"apiWeatherCurrentHandler": {
+		Summary:       "Returns current weather for the {location} path parameter",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*weather.CurrentWeather)(nil),
+		ErrorStatuses: []int{502},
+	},
+	"apiWeatherForecastHandler": {
+		Summary:       "Returns a daily weather forecast for the {location} path parameter",
+		Description:   "Returns a daily weather forecast for the {location} path parameter. The number of days (1-16) is read from the ?days= query parameter, defaulting to 5.",
+		Params:        []interface{}{(*weatherForecastParams)(nil)},
+		QueryParams:   []string{"days"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiWeatherHistoricalHandler": {
+		Summary:       "Returns historical daily weather for the {location} path parameter between the required ?start= and ?end= query parameters (each YYYY-MM-DD)",
+		Params:        []interface{}{(*weatherHistoricalParams)(nil)},
+		QueryParams:   []string{"start", "end"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiWeatherHourlyHandler": {
+		Summary:       "Returns an hourly weather forecast for the {location} path parameter",
+		Description:   "Returns an hourly weather forecast for the {location} path parameter. The number of hours (1-48) is read from the ?hours= query parameter, defaulting to 24.",
+		Params:        []interface{}{(*weatherHourlyParams)(nil)},
+		QueryParams:   []string{"hours"},
+		Format:        swagger.FormatEnvelope,
+		Response:      (*map[string]interface{})(nil),
+		ErrorStatuses: []int{400, 502},
+	},
+	"apiWeatherMapsHandler": {
+		Summary:       "Weather maps is a permanent gap: keyless weather tile/map imagery has no free provider within IDEA.md's outbound-call boundary",
+		ErrorStatuses: []int{501},
+	},
+	"apiWeatherMarineHandler": {
+		Summary:       "Returns current marine/ocean conditions for the {location} path parameter",
+		Description:   "Returns current marine/ocean conditions for the {location} path parameter. Inland locations return zero-value fields, not an error.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*weather.MarineConditions)(nil),
+		ErrorStatuses: []int{502},
+	},
+	"apiWeatherPollenHandler": {
+		Summary:       "Returns current pollen counts for the {location} path parameter",
+		Description:   "Returns current pollen counts for the {location} path parameter. Coverage is currently limited to Europe by the upstream provider; other regions return an explanatory coverage note.",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*weather.Pollen)(nil),
+		ErrorStatuses: []int{502},
+	},
+	"apiWeatherRadarHandler": {
+		Summary:       "Weather radar is a permanent gap: keyless weather radar imagery has no free provider within IDEA.md's outbound-call boundary",
+		ErrorStatuses: []int{501},
+	},
+	"apiWeatherUVHandler": {
+		Summary:       "Returns the current UV index for the {location} path parameter",
+		Format:        swagger.FormatEnvelope,
+		Response:      (*weather.UVIndex)(nil),
+		ErrorStatuses: []int{502},
+	},
+	"graphqlUIHandler": {
+		Summary: "Serves the GraphiQL UI at the PART 14 canonical /server/docs/graphql path; it POSTs to /api/graphql",
+	},
+	"handleQRRequest": {
+		Summary:       "Implements the shared body of apiGenerateQRHandler and apiImageQRHandler: either ?data=..",
+		Description:   "Implements the shared body of apiGenerateQRHandler and apiImageQRHandler: either ?data=... is encoded directly, or ?ssid=... (with optional password/security/hidden) is built into a standard Wi-Fi QR join payload per IDEA.md's Wi-Fi QR code requirement.",
+		Params:        []interface{}{(*qrRequestParams)(nil)},
+		QueryParams:   []string{"data", "ssid", "password", "security", "hidden", "width", "height"},
+		Format:        swagger.FormatRaw,
+		ContentType:   "image/png",
+		ErrorStatuses: []int{400},
+	},
+	"manifestHandler": {
+		Format:      swagger.FormatRaw,
+		ContentType: "application/manifest+json",
+	},
+	"metricsPrometheusHandler": {
+		Summary:       "Serves metrics in Prometheus format",
+		Description:   "Serves metrics in Prometheus format. When server.metrics.token is set, requests must present a matching \"Authorization: Bearer <token>\" header (PART 20 optional bearer-token auth) - compared in constant time, never with ==. An empty token means no auth check: the endpoint relies on firewall/proxy/NetworkPolicy restriction alone (PART 20 Access Control).",
+		ErrorStatuses: []int{401},
+	},
+	"offlinePageHandler": {
+		Summary:     "Serves the embedded /offline.html fallback page that the service worker returns when a navigation request fails offline and no cached copy of the requested page exists",
+		Format:      swagger.FormatRaw,
+		ContentType: "text/html",
+	},
+	"reportsHandler": {
+		Summary: "Receives browser-emitted reports (CSP violations, NEL network errors, deprecation/intervention/crash reports, and the generic \"default\" group) POSTed to /api/v1/server/reports/{name}, per AI.md PART 11 \"Reporting API (Modern + Legacy)\": \"All report endpoints share the same public reports rules — same rate limits [applied globally by RateLimitMiddleware's write class], same Output Sanitization Pipeline, same Tier 2 visibility (no PII echoed back).\" One handler serves every report name (default, csp, nel, deprecation, intervention, crash, ...) since they share the same scope and shape, rather than one-off handlers per report type",
+		RawBody: true,
+	},
+	"robotsHandler": {
+		Format:      swagger.FormatRaw,
+		ContentType: "text/plain",
+	},
+	"securityHandler": {
+		Format:      swagger.FormatRaw,
+		ContentType: "text/plain",
+	},
+	"serviceWorkerHandler": {
+		Summary:     "Serves the embedded /sw.js at the required root scope with no-cache so browsers always see a new service worker promptly after a build changes it",
+		Format:      swagger.FormatRaw,
+		ContentType: "application/javascript",
+	},
+	"swaggerUIHandler": {
+		Summary: "Serves the Swagger UI at the PART 14 canonical /server/docs/swagger path; it fetches its spec from /api/swagger",
+	},
+}
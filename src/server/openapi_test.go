@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apimgr/api/src/swagger"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fetchSpec builds the real router and returns the spec it serves at
+// /api/v1/server/swagger.
+func fetchSpec(t *testing.T) (*http.Server, swagger.Spec) {
+	t.Helper()
+	cfg := newTestConfig(t)
+	srv := newTestServer(t, cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/server/swagger", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec swagger.Spec
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&spec))
+	return srv, spec
+}
+
+// Every /api route the router serves, across all categories, must appear
+// in the spec with a unique operation ID.
+func TestOpenAPISpecCoversEveryRoute(t *testing.T) {
+	srv, spec := fetchSpec(t)
+
+	routes := 0
+	err := chi.Walk(srv.Handler.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, "/api/") {
+			return nil
+		}
+		routes++
+		path := route
+		if strings.HasSuffix(path, "/*") {
+			path = strings.TrimSuffix(path, "*") + "{path}"
+		}
+		item, ok := spec.Paths[path]
+		if !assert.True(t, ok, "missing path %s", path) {
+			return nil
+		}
+		var op *swagger.Operation
+		switch method {
+		case http.MethodGet:
+			op = item.Get
+		case http.MethodPost:
+			op = item.Post
+		case http.MethodPut:
+			op = item.Put
+		case http.MethodDelete:
+			op = item.Delete
+		case http.MethodPatch:
+			op = item.Patch
+		default:
+			return nil
+		}
+		if assert.NotNil(t, op, "missing %s %s", method, path) {
+			assert.NotEmpty(t, op.Summary, "%s %s has no summary", method, path)
+			assert.NotEmpty(t, op.Tags, "%s %s has no tag", method, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, routes, 250)
+
+	seen := make(map[string]string)
+	for path, item := range spec.Paths {
+		for _, op := range []*swagger.Operation{item.Get, item.Post, item.Put, item.Delete, item.Patch} {
+			if op == nil {
+				continue
+			}
+			if other, dup := seen[op.OperationID]; dup {
+				t.Errorf("operation ID %s used by %s and %s", op.OperationID, other, path)
+			}
+			seen[op.OperationID] = path
+		}
+	}
+}
+
+// Param structs must type and constrain parameters, bodies must carry
+// their schemas, .txt variants must be text/plain, and 501 stubs must
+// document only their error envelope.
+func TestOpenAPISpecOperations(t *testing.T) {
+	_, spec := fetchSpec(t)
+
+	// Query parameter typed by dockerVersionParams, envelope response
+	docker := spec.Paths["/api/v1/docker/version"].Get
+	require.NotNil(t, docker)
+	require.Len(t, docker.Parameters, 1)
+	assert.Equal(t, "image", docker.Parameters[0].Name)
+	assert.Equal(t, "query", docker.Parameters[0].In)
+	assert.True(t, docker.Parameters[0].Required)
+	require.Contains(t, docker.Responses, "200")
+	assert.Contains(t, docker.Responses, "400")
+	data := docker.Responses["200"].Content["application/json"].Schema
+	require.Len(t, data.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/Envelope", data.AllOf[0].Ref)
+
+	// Path parameter constrained by generatePlaceholderParams (gt=0)
+	placeholder := spec.Paths["/api/v1/generate/placeholder/{width}/{height}"].Get
+	require.NotNil(t, placeholder)
+	require.NotEmpty(t, placeholder.Parameters)
+	width := placeholder.Parameters[0]
+	assert.Equal(t, "width", width.Name)
+	assert.Equal(t, "path", width.In)
+	assert.Equal(t, "integer", width.Schema.Type)
+	require.NotNil(t, width.Schema.Minimum)
+	assert.True(t, width.Schema.ExclusiveMinimum)
+
+	// .txt variant
+	uuidText := spec.Paths["/api/v1/text/uuid.txt"].Get
+	require.NotNil(t, uuidText)
+	assert.Contains(t, uuidText.Responses["200"].Content, "text/plain")
+
+	// JSON request body
+	assert.NotNil(t, spec.Paths["/api/v1/test/assert"].Post.RequestBody)
+	assert.Contains(t, spec.Paths["/api/v1/test/assert"].Post.RequestBody.Content, "application/json")
+
+	// Multipart upload
+	resize := spec.Paths["/api/v1/image/resize"].Post
+	require.NotNil(t, resize.RequestBody)
+	form := resize.RequestBody.Content["multipart/form-data"].Schema
+	assert.Equal(t, "binary", form.Properties["image"].Format)
+
+	// 501 stub
+	radar := spec.Paths["/api/v1/weather/radar/{location}"].Get
+	require.NotNil(t, radar)
+	assert.NotContains(t, radar.Responses, "200")
+	require.Contains(t, radar.Responses, "501")
+	assert.Equal(t, "#/components/schemas/ErrorEnvelope", radar.Responses["501"].Content["application/json"].Schema.Ref)
+}
+
+func TestHumanizeHandlerName(t *testing.T) {
+	assert.Equal(t, "UUID batch", humanizeHandlerName("apiUUIDBatchHandler"))
+	assert.Equal(t, "Server healthz", humanizeHandlerName("handler.ServerHealthz"))
+	assert.Equal(t, "Math mod pow", humanizeHandlerName("apiMathModPowHandler"))
+}
+
+func TestRouteTag(t *testing.T) {
+	assert.Equal(t, "Text", routeTag("/api/v1/text/uuid"))
+	assert.Equal(t, "Server", routeTag("/api/v1/version"))
+	assert.Equal(t, "Server", routeTag("/api/healthz"))
+}
@@ -72,9 +72,7 @@ const globalBurstWindowSeconds = 60
 // NewRateLimiter creates a new rate limiter from server.rate_limit config,
 // backed by the server.cache.* store (in-process memory by default, or a
 // shared valkey/redis instance when configured).
-func NewRateLimiter(cfg *config.Config) *RateLimiter {
-	store := cache.New(cfg.Server.Cache)
-
+func NewRateLimiter(cfg *config.Config, store cache.Store) *RateLimiter {
 	return &RateLimiter{
 		enabled: cfg.Server.RateLimit.Enabled,
 		read:    newClassLimiter(store, "read", cfg.Server.RateLimit.Read.Requests, cfg.Server.RateLimit.Read.Window),
@@ -84,9 +82,9 @@ func NewRateLimiter(cfg *config.Config) *RateLimiter {
 	}
 }
 
-// RateLimitMiddleware creates a rate limiting middleware
-func RateLimitMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
-	limiter := NewRateLimiter(cfg)
+// RateLimitMiddleware creates a rate limiting middleware counting in store
+func RateLimitMiddleware(cfg *config.Config, store cache.Store) func(http.Handler) http.Handler {
+	limiter := NewRateLimiter(cfg, store)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -97,10 +95,17 @@ func RateLimitMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
 			}
 
 			clientIP := getClientIP(r)
+			checkGlobal, checkClass := batchRateLimit(r)
 
 			// Absolute ceiling across all endpoint types, checked first
-			if allowed, _, limit, resetTime := limiter.global.allow(clientIP); !allowed {
-				writeRateLimitExceeded(w, limit, resetTime)
+			if checkGlobal {
+				if allowed, _, limit, resetTime := limiter.global.allow(clientIP); !allowed {
+					writeRateLimitExceeded(w, limit, resetTime)
+					return
+				}
+			}
+			if !checkClass {
+				next.ServeHTTP(w, r)
 				return
 			}
 
@@ -148,6 +153,22 @@ func writeRateLimitExceeded(w http.ResponseWriter, limit int, resetTime time.Tim
 	})
 }
 
+// batchRateLimit reports which limits apply to r. global_burst bounds
+// HTTP requests and the read/write classes bound API calls, so a POST
+// /api/v1/batch counts once against the global ceiling and not at all
+// against the write class, while each of its sub-requests counts against
+// its own class but not again against the global ceiling. Batching thus
+// saves connections without raising any per-class quota.
+func batchRateLimit(r *http.Request) (global, class bool) {
+	switch {
+	case isBatchSubRequest(r.Context()):
+		return false, true
+	case r.URL.Path == batchPath:
+		return true, false
+	}
+	return true, true
+}
+
 // shouldSkipRateLimit returns true for paths that should bypass rate limiting
 // entirely (static/well-known files, not endpoints)
 func shouldSkipRateLimit(path string) bool {
@@ -209,7 +209,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 
 	t.Run("disabled: never limits", func(t *testing.T) {
 		cfg := testRateLimitConfig(false, 1, 1, 1, 100)
-		mw := RateLimitMiddleware(cfg)(okHandler)
+		mw := RateLimitMiddleware(cfg, cache.New(cfg.Server.Cache))(okHandler)
 
 		for i := 0; i < 5; i++ {
 			req := httptest.NewRequest(http.MethodGet, "/x", nil)
@@ -222,7 +222,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 
 	t.Run("static path bypasses rate limit entirely", func(t *testing.T) {
 		cfg := testRateLimitConfig(true, 1, 1, 1, 100)
-		mw := RateLimitMiddleware(cfg)(okHandler)
+		mw := RateLimitMiddleware(cfg, cache.New(cfg.Server.Cache))(okHandler)
 
 		for i := 0; i < 5; i++ {
 			req := httptest.NewRequest(http.MethodGet, "/static/app.css", nil)
@@ -236,7 +236,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 
 	t.Run("read class limited after N GET requests, headers set", func(t *testing.T) {
 		cfg := testRateLimitConfig(true, 1, 1, 1, 100)
-		mw := RateLimitMiddleware(cfg)(okHandler)
+		mw := RateLimitMiddleware(cfg, cache.New(cfg.Server.Cache))(okHandler)
 
 		req1 := httptest.NewRequest(http.MethodGet, "/api/v1/thing", nil)
 		req1.RemoteAddr = "9.9.9.1:1"
@@ -259,7 +259,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 
 	t.Run("write class tracked independently from read class", func(t *testing.T) {
 		cfg := testRateLimitConfig(true, 1, 1, 1, 100)
-		mw := RateLimitMiddleware(cfg)(okHandler)
+		mw := RateLimitMiddleware(cfg, cache.New(cfg.Server.Cache))(okHandler)
 
 		getReq := httptest.NewRequest(http.MethodGet, "/api/v1/thing", nil)
 		getReq.RemoteAddr = "9.9.9.2:1"
@@ -275,7 +275,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 
 	t.Run("health path uses its own class", func(t *testing.T) {
 		cfg := testRateLimitConfig(true, 0, 0, 1, 100)
-		mw := RateLimitMiddleware(cfg)(okHandler)
+		mw := RateLimitMiddleware(cfg, cache.New(cfg.Server.Cache))(okHandler)
 
 		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
 		req.RemoteAddr = "9.9.9.3:1"
@@ -287,7 +287,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 
 	t.Run("global burst ceiling blocks even when class quota remains", func(t *testing.T) {
 		cfg := testRateLimitConfig(true, 100, 100, 100, 1)
-		mw := RateLimitMiddleware(cfg)(okHandler)
+		mw := RateLimitMiddleware(cfg, cache.New(cfg.Server.Cache))(okHandler)
 
 		req1 := httptest.NewRequest(http.MethodGet, "/api/v1/a", nil)
 		req1.RemoteAddr = "9.9.9.4:1"
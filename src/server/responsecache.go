@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/apimgr/api/src/cache"
+	"github.com/apimgr/api/src/config"
+	"github.com/apimgr/api/src/graphql"
+)
+
+// responseCache fronts the outbound-provider lookups (weather, geocode,
+// dictionary, thesaurus, arxiv, isbn, currency) per server.cache.responses.
+// It stays nil - caching bypassed - until initResponseCache runs, so
+// handlers exercised directly in tests always hit their service.
+var responseCache *cache.ResponseCache
+
+// initResponseCache builds responseCache over store, the server.cache.*
+// store the rate limiter also uses.
+func initResponseCache(cfg *config.Config, store cache.Store) {
+	if !cfg.Server.Cache.Responses.Enabled {
+		responseCache = nil
+		graphql.SetResponseCache(nil)
+		return
+	}
+	responseCache = cache.NewResponseCache(store, cfg.Server.Cache)
+	graphql.SetResponseCache(responseCache)
+}
+
+// cachedLookup runs fetch through responseCache for provider and key and
+// reports the outcome in the X-Cache response header (HIT, MISS, or
+// STALE).
+func cachedLookup[T any](ctx context.Context, w http.ResponseWriter, provider, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if !responseCache.Enabled() {
+		return fetch(ctx)
+	}
+
+	result, status, err := cache.FetchJSON(ctx, responseCache, provider, key, fetch)
+	if err != nil {
+		return result, err
+	}
+	w.Header().Set("X-Cache", string(status))
+	return result, nil
+}
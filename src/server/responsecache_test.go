@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apimgr/api/src/cache"
+	"github.com/apimgr/api/src/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cachedLookupResult stands in for a provider result type.
+type cachedLookupResult struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// withResponseCache installs a memory-backed responseCache for the test and
+// restores the previous one afterwards.
+func withResponseCache(t *testing.T) {
+	t.Helper()
+	cfg := config.CacheConfig{
+		Type:      "memory",
+		TTL:       "1h",
+		Responses: config.ResponseCacheConfig{Enabled: true, Stale: "1h"},
+	}
+	previous := responseCache
+	responseCache = cache.NewResponseCache(cache.New(cfg), cfg)
+	t.Cleanup(func() { responseCache = previous })
+}
+
+// cachedLookup must call through without an X-Cache header when caching
+// is disabled, and round-trip typed results through the cache with
+// MISS/HIT reported in X-Cache once enabled.
+func TestCachedLookup(t *testing.T) {
+	calls := 0
+	fetch := func(context.Context) (*cachedLookupResult, error) {
+		calls++
+		return &cachedLookupResult{Name: "paris", Value: 21.5}, nil
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		previous := responseCache
+		responseCache = nil
+		t.Cleanup(func() { responseCache = previous })
+
+		w := httptest.NewRecorder()
+		result, err := cachedLookup(context.Background(), w, "weather", "paris", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "paris", result.Name)
+		assert.Empty(t, w.Header().Get("X-Cache"))
+	})
+
+	t.Run("miss then hit", func(t *testing.T) {
+		withResponseCache(t)
+		calls = 0
+
+		w := httptest.NewRecorder()
+		result, err := cachedLookup(context.Background(), w, "weather", "paris", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		assert.Equal(t, 21.5, result.Value)
+
+		w = httptest.NewRecorder()
+		result, err = cachedLookup(context.Background(), w, "weather", "paris", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+		assert.Equal(t, &cachedLookupResult{Name: "paris", Value: 21.5}, result)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		withResponseCache(t)
+
+		w := httptest.NewRecorder()
+		_, err := cachedLookup(context.Background(), w, "weather", "nowhere", func(context.Context) (*cachedLookupResult, error) {
+			return nil, errors.New("upstream down")
+		})
+		assert.Error(t, err)
+		assert.Empty(t, w.Header().Get("X-Cache"))
+	})
+}
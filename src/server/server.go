@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/apimgr/api/src/cache"
 	"github.com/apimgr/api/src/common/theme"
 	"github.com/apimgr/api/src/config"
 	"github.com/apimgr/api/src/graphql"
@@ -46,6 +47,11 @@ func New(cfg *config.Config) *http.Server {
 		panic(fmt.Sprintf("Failed to parse templates: %v", err))
 	}
 
+	// One server.cache.* store (and so one valkey/redis client) backs
+	// both the response cache and the rate limiter.
+	store := cache.New(cfg.Server.Cache)
+	initResponseCache(cfg, store)
+
 	r := chi.NewRouter()
 
 	// Core middleware
@@ -57,7 +63,7 @@ func New(cfg *config.Config) *http.Server {
 	r.Use(middleware.Compress(5))
 	r.Use(securityHeadersMiddleware(cfg))
 	r.Use(secFetchValidationMiddleware(cfg))
-	r.Use(RateLimitMiddleware(cfg))
+	r.Use(RateLimitMiddleware(cfg, store))
 	r.Use(corsMiddleware(cfg))
 
 	// Static files
@@ -174,12 +180,16 @@ func New(cfg *config.Config) *http.Server {
 			r.Get("/hash/{algorithm}/{input}", apiHashHandler)
 			r.Get("/hash/{algorithm}/{input}.txt", apiHashTextHandler)
 			r.Get("/hash/multi/{input}", apiHashMultiHandler)
+			r.Post("/hash/{algorithm}", apiTextHashStreamHandler(cfg))
+			r.Post("/hash/multi", apiTextHashMultiStreamHandler(cfg))
 
 			// Encode/Decode
 			r.Get("/encode/{encoding}/{input}", apiEncodeHandler)
 			r.Get("/encode/{encoding}/{input}.txt", apiEncodeTextHandler)
 			r.Get("/decode/{encoding}/{input}", apiDecodeHandler)
 			r.Get("/decode/{encoding}/{input}.txt", apiDecodeTextHandler)
+			r.Post("/encode/{encoding}", apiTextEncodeStreamHandler(cfg))
+			r.Post("/decode/{encoding}", apiTextDecodeStreamHandler(cfg))
 
 			// Case conversion
 			r.Get("/case/{style}/{input}", apiCaseHandler)
@@ -204,6 +214,8 @@ func New(cfg *config.Config) *http.Server {
 
 			// Compress/decompress
 			r.Post("/compress", apiTextCompressHandler)
+			r.Post("/compress/{algorithm}", apiTextCompressStreamHandler(cfg))
+			r.Post("/decompress/{algorithm}", apiTextDecompressStreamHandler(cfg))
 
 			// Diff
 			r.Post("/diff", apiTextDiffHandler)
@@ -230,6 +242,11 @@ func New(cfg *config.Config) *http.Server {
 			r.Post("/bcrypt/verify", apiBcryptVerifyHandler)
 			r.Get("/bcrypt/verify/{password}/{hash}", apiBcryptVerifyGetHandler)
 
+			// Password hashing with auto-detecting verify (Argon2, scrypt,
+			// PBKDF2, sha-crypt, apr1, bcrypt)
+			r.Post("/password/hash", apiCryptoPasswordHashHandler)
+			r.Post("/password/verify", apiCryptoPasswordVerifyHandler)
+
 			// Password generation
 			r.Get("/password", apiPasswordHandler)
 			r.Get("/password/{length}", apiPasswordHandler)
@@ -248,6 +265,16 @@ func New(cfg *config.Config) *http.Server {
 			r.Get("/totp/code/{secret}", apiTOTPCodeHandler)
 			r.Get("/totp/code/{secret}.txt", apiTOTPCodeTextHandler)
 			r.Get("/totp/verify/{secret}/{code}", apiTOTPVerifyHandler)
+			r.Post("/totp/code", apiCryptoTOTPCodeHandler)
+			r.Post("/totp/verify", apiCryptoTOTPVerifyHandler)
+
+			// HOTP (RFC 4226 counter-based codes)
+			r.Post("/hotp/generate", apiCryptoHOTPGenerateHandler)
+			r.Post("/hotp/verify", apiCryptoHOTPVerifyHandler)
+
+			// otpauth:// key URIs: parse, or render as a provisioning QR code
+			r.Post("/otpauth/parse", apiCryptoOTPAuthParseHandler)
+			r.Post("/otpauth/qr", apiCryptoOTPAuthQRHandler)
 
 			// Random bytes
 			r.Get("/random/bytes/{count}", apiRandomBytesHandler)
@@ -260,14 +287,43 @@ func New(cfg *config.Config) *http.Server {
 			// Hash (alias of /text/hash for the crypto tool page)
 			r.Get("/hash/{algorithm}/{input}", apiHashHandler)
 			r.Get("/hash/{algorithm}/{input}.txt", apiHashTextHandler)
+			r.Post("/hash/{algorithm}", apiTextHashStreamHandler(cfg))
 
 			// JWT decode (header/payload only, no signature verification)
 			r.Get("/jwt/{token}", apiCryptoJWTDecodeHandler)
 
+			// JWT sign / verify (signature and exp/nbf/iat/aud/iss checks)
+			r.Post("/jwt/sign", apiCryptoJWTSignHandler)
+			r.Post("/jwt/verify", apiCryptoJWTVerifyHandler)
+
+			// Key inspection, format conversion and JWKS documents
+			r.Post("/key/inspect", apiCryptoKeyInspectHandler)
+			r.Post("/key/convert", apiCryptoKeyConvertHandler)
+			r.Post("/jwks", apiCryptoJWKSHandler)
+
 			// AES-256-GCM encrypt/decrypt
 			r.Post("/encrypt", apiCryptoEncryptHandler)
 			r.Post("/decrypt", apiCryptoDecryptHandler)
 
+			// Explicit-parameter ciphers (AES-CBC/CTR/GCM, ChaCha20-Poly1305,
+			// XChaCha20-Poly1305) and raw key derivation
+			r.Post("/cipher/encrypt", apiCryptoCipherEncryptHandler)
+			r.Post("/cipher/decrypt", apiCryptoCipherDecryptHandler)
+			r.Post("/kdf", apiCryptoKDFHandler)
+
+			// AES key wrap (RFC 3394 / RFC 5649)
+			r.Post("/keywrap/wrap", apiCryptoKeyWrapHandler)
+			r.Post("/keywrap/unwrap", apiCryptoKeyUnwrapHandler)
+
+			// age file encryption (X25519 recipients or passphrase)
+			r.Post("/age/keygen", apiCryptoAgeKeygenHandler)
+			r.Post("/age/encrypt", apiCryptoAgeEncryptHandler)
+			r.Post("/age/decrypt", apiCryptoAgeDecryptHandler)
+
+			// Shamir secret sharing
+			r.Post("/shamir/split", apiCryptoShamirSplitHandler)
+			r.Post("/shamir/combine", apiCryptoShamirCombineHandler)
+
 			// RSA keypair generation / RSA-OAEP encrypt / decrypt
 			r.Post("/rsa", apiCryptoRSAHandler)
 
@@ -277,11 +333,28 @@ func New(cfg *config.Config) *http.Server {
 			// X.509 certificate generation (self-signed) / parse
 			r.Post("/certificate", apiCryptoCertificateHandler)
 
+			// X.509 toolkit: bundle decoding, CSRs, CA signing, chain
+			// validation, PKCS#12 and CRLs
+			r.Post("/certificate/decode", apiCryptoCertificateDecodeHandler)
+			r.Post("/certificate/csr", apiCryptoCSRHandler)
+			r.Post("/certificate/csr/decode", apiCryptoCSRDecodeHandler)
+			r.Post("/certificate/sign", apiCryptoCertificateSignHandler)
+			r.Post("/certificate/verify", apiCryptoCertificateVerifyHandler)
+			r.Post("/certificate/pkcs12", apiCryptoPKCS12Handler)
+			r.Post("/certificate/pkcs12/decode", apiCryptoPKCS12DecodeHandler)
+			r.Post("/certificate/crl/decode", apiCryptoCRLDecodeHandler)
+
 			// Ed25519 keypair generation / sign / verify
 			r.Post("/ed25519", apiCryptoEd25519Handler)
 
-			// PGP keypair generation / encrypt / decrypt
+			// PGP keypair generation / encrypt / decrypt / sign / verify
 			r.Post("/pgp", apiCryptoPGPHandler)
+
+			// BIP39 mnemonics and BIP32 key derivation
+			r.Post("/mnemonic/generate", apiCryptoMnemonicGenerateHandler)
+			r.Post("/mnemonic/validate", apiCryptoMnemonicValidateHandler)
+			r.Post("/mnemonic/seed", apiCryptoMnemonicSeedHandler)
+			r.Post("/mnemonic/derive", apiCryptoMnemonicDeriveHandler)
 		})
 
 		// DateTime utilities
@@ -311,6 +384,7 @@ func New(cfg *config.Config) *http.Server {
 
 			// Format/Parse
 			r.Get("/format/{timestamp}/{format}", apiDatetimeFormatHandler)
+			r.Get("/parse", apiDatetimeParseHandler)
 			r.Get("/parse/{value}", apiDatetimeParseHandler)
 
 			// Cron
@@ -319,8 +393,19 @@ func New(cfg *config.Config) *http.Server {
 			// Calendar
 			r.Get("/calendar/{year}/{month}", apiDatetimeCalendarHandler)
 
-			// Workdays
+			// Workdays/Holidays
 			r.Get("/workdays/{start}/{end}", apiDatetimeWorkdaysHandler)
+			r.Get("/workdays/add/{date}/{days}", apiDatetimeAddWorkdaysHandler)
+			r.Get("/holidays", apiDatetimeHolidayCalendarsHandler)
+			r.Get("/holidays/{country}/{year}", apiDatetimeHolidaysHandler)
+
+			// Recurrence/iCalendar
+			r.Get("/rrule", apiDatetimeRRuleHandler)
+			r.Post("/ics", apiDatetimeICSHandler)
+			r.Post("/ics/parse", apiDatetimeICSParseHandler)
+
+			// Calendar systems
+			r.Get("/calendar-convert", apiDatetimeCalendarConvertHandler)
 
 			// Sun/Moon
 			r.Get("/sunrise/{lat}/{lon}", apiDatetimeSunriseHandler)
@@ -406,6 +491,8 @@ func New(cfg *config.Config) *http.Server {
 			r.Get("/base", apiMathBaseHandler)
 			r.Post("/matrix", apiMathMatrixHandler)
 			r.Get("/sequence", apiMathSequenceHandler)
+			r.Get("/mod-pow", apiMathModPowHandler)
+			r.Get("/mod-inverse", apiMathModInverseHandler)
 		})
 
 		// Unit Conversion
@@ -454,6 +541,7 @@ func New(cfg *config.Config) *http.Server {
 			r.Post("/iban", apiValidateIBANHandler)
 			r.Post("/isbn", apiValidateISBNHandler)
 			r.Post("/vat", apiValidateVATHandler)
+			r.Post("/password", apiValidatePasswordHandler)
 		})
 
 		// Parsers
@@ -600,6 +688,12 @@ func New(cfg *config.Config) *http.Server {
 		})
 	})
 
+	// Batch: runs its sub-requests back through this router, so it is
+	// mounted once every other route exists.
+	r.Post(batchPath, apiBatchHandler(cfg, r))
+
+	registerAPIEndpoints(r)
+
 	return &http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Address, cfg.Server.Port),
 		Handler:      r,
@@ -744,27 +838,30 @@ type toolPage struct {
 // wiring work.
 func toolPages() []toolPage {
 	return []toolPage{
-		{category: "crypto", tool: "hash", title: "Hash Generator", description: "Generate cryptographic hashes using various algorithms (MD5, SHA-1, SHA-256, SHA-512, BLAKE3)"},
-		{category: "crypto", tool: "jwt", title: "JWT Decoder", description: "Decode and inspect JSON Web Tokens (JWT). View header, payload, and signature details"},
-		{category: "crypto", tool: "totp", title: "TOTP Generator", description: "Generate a time-based one-time password secret, provisioning URI, and current code"},
+		{category: "crypto", tool: "hash", title: "Hash Generator", description: "Generate hashes and checksums using MD5, SHA-1, SHA-2, SHA-3, BLAKE2, BLAKE3, CRC, Adler-32, xxHash and Murmur3"},
+		{category: "crypto", tool: "jwt", title: "JWT Decoder", description: "Decode, sign and verify JSON Web Tokens (JWT). View header, payload, and signature details"},
+		{category: "crypto", tool: "totp", title: "TOTP Generator", description: "Generate TOTP secrets and codes, generate and verify HOTP codes, parse otpauth URIs and render provisioning QR codes"},
 		{category: "crypto", tool: "random", title: "Random Bytes", description: "Generate cryptographically secure random bytes as raw values and hex encoding"},
 		{category: "crypto", tool: "password", title: "Password Generator", description: "Generate secure random passwords with customizable length and character sets"},
 		{category: "crypto", tool: "encrypt", title: "AES Encrypt", description: "Encrypt text with AES-256-GCM using a passphrase-derived key"},
 		{category: "crypto", tool: "decrypt", title: "AES Decrypt", description: "Decrypt AES-256-GCM ciphertext using the original passphrase"},
+		{category: "crypto", tool: "cipher", title: "Symmetric Ciphers", description: "Encrypt and decrypt with AES-CBC/CTR/GCM or (X)ChaCha20-Poly1305 using explicit keys, IVs and KDF parameters, wrap keys with AES-KW, and encrypt age files"},
 		{category: "crypto", tool: "rsa", title: "RSA Encrypt/Decrypt", description: "Generate an RSA keypair, or encrypt/decrypt text with RSA-OAEP (SHA-256)"},
-		{category: "crypto", tool: "hmac", title: "HMAC Generator", description: "Compute an HMAC (SHA-1 or SHA-256) of a message using a secret key"},
-		{category: "crypto", tool: "certificate", title: "X.509 Certificate", description: "Generate a self-signed X.509 certificate, or parse an existing PEM certificate's details"},
+		{category: "crypto", tool: "hmac", title: "HMAC Generator", description: "Compute an HMAC (SHA-1, SHA-2, SHA-3) or keyed BLAKE2/BLAKE3 MAC of a message using a secret key"},
+		{category: "crypto", tool: "certificate", title: "X.509 Certificate", description: "Generate self-signed certificates and CSRs, sign CSRs with your own CA, validate chains, and decode certificates, PKCS#12 bundles and CRLs"},
 		{category: "crypto", tool: "ed25519", title: "Ed25519 Sign/Verify", description: "Generate an Ed25519 keypair, sign a message, or verify a signature"},
-		{category: "crypto", tool: "pgp", title: "PGP Encrypt/Decrypt", description: "Generate a PGP keypair, or encrypt/decrypt a message with PGP"},
+		{category: "crypto", tool: "pgp", title: "PGP Encrypt/Decrypt", description: "Generate PGP keypairs, encrypt/decrypt messages, and create or verify PGP signatures"},
+		{category: "crypto", tool: "shamir", title: "Shamir Secret Sharing", description: "Split a secret into shares so any threshold of them recovers it, with hex, base64 or word-list shares and integrity checks"},
+		{category: "crypto", tool: "mnemonic", title: "BIP39 Mnemonic", description: "Generate and validate BIP39 seed phrases, derive their seed, and derive BIP32 extended keys along a path"},
 		{category: "datetime", tool: "now", title: "Current Time", description: "Get the current timestamp in multiple formats including Unix, ISO 8601, and human-readable"},
 		{category: "network", tool: "ip", title: "IP Address Lookup", description: "Get detailed information about any IP address including location, ISP, and network details"},
 		{category: "network", tool: "headers", title: "Request Headers", description: "Inspect the caller-identifying headers sent with the request"},
 		{category: "network", tool: "dns", title: "DNS Lookup", description: "Query DNS records for any domain. Supports A, AAAA, CNAME, MX, TXT, and NS"},
 		{category: "text", tool: "uuid", title: "UUID Generator", description: "Generate UUIDs (v1, v3, v4, v5, v6, v7) for use in applications and databases"},
-		{category: "text", tool: "hash", title: "Hash Generator", description: "Generate cryptographic hashes of arbitrary text (MD5, SHA-1, SHA-256, SHA-512, BLAKE3)"},
+		{category: "text", tool: "hash", title: "Hash Generator", description: "Hash or checksum arbitrary text (MD5, SHA-1, SHA-2, SHA-3, BLAKE2, BLAKE3, CRC, Adler-32, xxHash, Murmur3)"},
 		{category: "crypto", tool: "bcrypt", title: "Bcrypt Hash", description: "Hash a password using bcrypt with a configurable cost factor"},
 		{category: "crypto", tool: "pin", title: "PIN Generator", description: "Generate a random numeric PIN of a given length"},
-		{category: "crypto", tool: "password-strength", title: "Password Strength Checker", description: "Check the estimated strength of a password"},
+		{category: "crypto", tool: "password-strength", title: "Password Strength Checker", description: "Estimate password guesses, crack times and weaknesses"},
 		{category: "datetime", tool: "timestamp", title: "Unix Timestamp", description: "Get the current Unix timestamp"},
 		{category: "network", tool: "user-agent", title: "User-Agent Lookup", description: "Inspect the User-Agent header sent with the request"},
 		{category: "network", tool: "mac", title: "MAC Vendor Lookup", description: "Look up the hardware vendor for a MAC address"},
@@ -828,6 +925,7 @@ func toolPages() []toolPage {
 		{category: "math", tool: "logarithm", title: "Logarithm Calculator", description: "Calculate natural, base-10, or base-2 logarithms"},
 		{category: "math", tool: "trigonometry", title: "Trigonometry Calculator", description: "Calculate sine, cosine, and tangent of an angle in radians"},
 		{category: "math", tool: "prime", title: "Prime Checker", description: "Check whether a number is prime"},
+		{category: "math", tool: "modular", title: "Modular Arithmetic", description: "Compute modular exponentiation and modular inverses on big integers"},
 		{category: "math", tool: "random", title: "Random Number Generator", description: "Generate a random integer within a range"},
 		{category: "math", tool: "stats", title: "Statistics Calculator", description: "Calculate min, max, sum, average, and median of a list of numbers"},
 		{category: "math", tool: "fibonacci", title: "Fibonacci Generator", description: "Generate the first N numbers of the Fibonacci sequence"},
@@ -897,6 +995,7 @@ func toolPages() []toolPage {
 		{category: "validate", tool: "iban", title: "Validate IBAN", description: "Check an IBAN against the ISO 13616 mod-97 checksum"},
 		{category: "validate", tool: "isbn", title: "Validate ISBN", description: "Check an ISBN-10 or ISBN-13 book number's check digit"},
 		{category: "validate", tool: "vat", title: "Validate VAT Number", description: "Check an EU/UK/CH/NO VAT number's structural format"},
+		{category: "validate", tool: "password", title: "Validate Password", description: "Check that a password reaches a minimum estimated strength"},
 		{category: "image", tool: "placeholder", title: "Placeholder Image", description: "Generate a placeholder image of any size, format, and background color"},
 		{category: "image", tool: "resize", title: "Resize Image", description: "Upload an image and resize it to a new width and height"},
 		{category: "image", tool: "crop", title: "Crop Image", description: "Upload an image and crop a region out of it"},
@@ -926,10 +1025,14 @@ func toolPages() []toolPage {
 		{category: "datetime", tool: "add", title: "Add Duration", description: "Add a duration to a Unix timestamp"},
 		{category: "datetime", tool: "diff", title: "Timestamp Diff", description: "Compute the difference between two Unix timestamps"},
 		{category: "datetime", tool: "format", title: "Date Formatter", description: "Format dates in various styles"},
-		{category: "datetime", tool: "parse", title: "Date Parser", description: "Parse date strings to timestamps"},
-		{category: "datetime", tool: "cron", title: "Cron Parser", description: "Parse and explain cron expressions"},
+		{category: "datetime", tool: "parse", title: "Date Parser", description: "Parse timestamps, natural-language dates and ISO 8601 durations/intervals"},
+		{category: "datetime", tool: "cron", title: "Cron Parser", description: "Parse and explain cron expressions with seconds, macros and timezones"},
 		{category: "datetime", tool: "calendar", title: "Calendar", description: "View calendar for any month/year"},
-		{category: "datetime", tool: "workdays", title: "Business Days", description: "Calculate working days between dates"},
+		{category: "datetime", tool: "workdays", title: "Business Days", description: "Count or add business days, skipping weekends and public holidays"},
+		{category: "datetime", tool: "holidays", title: "Public Holidays", description: "List public holidays by country, region and year"},
+		{category: "datetime", tool: "rrule", title: "Recurrence Rules", description: "Expand RFC 5545 RRULE recurrences in any timezone, with excluded dates"},
+		{category: "datetime", tool: "ics", title: "iCalendar Parser", description: "Read .ics files: events, to-dos, timezones and recurrences"},
+		{category: "datetime", tool: "calendar-convert", title: "Calendar Converter", description: "Convert dates between Gregorian, Hebrew, Islamic, Persian, Chinese and ISO calendars and time scales like Julian Day, GPS and Excel"},
 		{category: "datetime", tool: "sunrise", title: "Sunrise/Sunset", description: "Calculate sunrise and sunset times"},
 		{category: "datetime", tool: "moon", title: "Moon Phase", description: "Calculate current moon phase"},
 		{category: "text", tool: "compress", title: "Compress/Decompress", description: "Compress or decompress text using gzip, zlib, or flate/deflate"},
@@ -1970,14 +2073,15 @@ func apiPasswordStrengthHandler(w http.ResponseWriter, r *http.Request) {
 
 func apiPasswordStrengthPostHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Password string `json:"password"`
+		Password   string   `json:"password"`
+		UserInputs []string `json:"user_inputs"`
 	}
 	if err := decodeJSONBody(r, &input); err != nil {
 		errorResponse(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	jsonResponse(w, crypto.PasswordStrength(input.Password))
+	jsonResponse(w, crypto.PasswordStrength(input.Password, input.UserInputs...))
 }
 
 // DateTime API handlers
@@ -229,6 +229,7 @@ func TestNew_RouterServesCoreEndpoints(t *testing.T) {
 		{"math logarithm tool page", http.MethodGet, "/math/logarithm", http.StatusOK},
 		{"math trigonometry tool page", http.MethodGet, "/math/trigonometry", http.StatusOK},
 		{"math prime tool page", http.MethodGet, "/math/prime", http.StatusOK},
+		{"math modular tool page", http.MethodGet, "/math/modular", http.StatusOK},
 		{"math random tool page", http.MethodGet, "/math/random", http.StatusOK},
 		{"math stats tool page", http.MethodGet, "/math/stats", http.StatusOK},
 		{"math fibonacci tool page", http.MethodGet, "/math/fibonacci", http.StatusOK},
@@ -1113,12 +1114,30 @@ func TestApiPasswordStrengthHandler_And_Post(t *testing.T) {
 	apiPasswordStrengthHandler(weakRec, weakReq)
 	assert.Equal(t, http.StatusOK, weakRec.Code)
 
+	var weak map[string]interface{}
+	require.NoError(t, json.Unmarshal(weakRec.Body.Bytes(), &weak))
+	assert.Equal(t, "weak", weak["strength"])
+	assert.Equal(t, "This is a top-10 common password", weak["feedback"].(map[string]interface{})["warning"])
+
 	body := bytes.NewBufferString(`{"password":"Tr0ub4dor&3xtraLong!"}`)
 	strongReq := reqWithParams(http.MethodPost, "/api/v1/crypto/password/strength", nil, body)
 	strongRec := httptest.NewRecorder()
 	apiPasswordStrengthPostHandler(strongRec, strongReq)
 	assert.Equal(t, http.StatusOK, strongRec.Code)
 
+	t.Run("user inputs", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"password":"Zb8ronwq4kX","user_inputs":["zb8ronwq4kx"]}`)
+		req := reqWithParams(http.MethodPost, "/api/v1/crypto/password/strength", nil, body)
+		rec := httptest.NewRecorder()
+		apiPasswordStrengthPostHandler(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		seq := got["sequence"].([]interface{})
+		require.Len(t, seq, 1)
+		assert.Equal(t, "user_inputs", seq[0].(map[string]interface{})["dictionary"])
+	})
+
 	t.Run("invalid body", func(t *testing.T) {
 		badBody := bytes.NewBufferString(`not json`)
 		req := reqWithParams(http.MethodPost, "/api/v1/crypto/password/strength", nil, badBody)
@@ -0,0 +1,307 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apimgr/api/src/config"
+	"github.com/apimgr/api/src/service/text"
+	"github.com/go-chi/chi/v5"
+)
+
+// The POST variants of the hash, encode/decode and compress tools read
+// the raw request body as a stream instead of a path segment or JSON
+// field, so input of any size up to the tool class's server.stream.*
+// cap is processed with a fixed amount of memory. Hashes come back as a
+// JSON envelope once the body is consumed; the other tools stream their
+// output back as it is produced.
+
+// streamErrorTrailer carries the error of a streaming tool that failed
+// after its output had started, when the status can no longer change.
+const streamErrorTrailer = "X-Stream-Error"
+
+// defaultStreamTimeout applies when server.stream.timeout is unset or
+// invalid.
+const defaultStreamTimeout = 10 * time.Minute
+
+// streamRequestBody caps r's body at maxMB megabytes and gives the
+// request server.stream.timeout to complete, in place of the server-wide
+// read/write timeouts. A Content-Length already over the cap is answered
+// with 413 and ok is false.
+func streamRequestBody(w http.ResponseWriter, r *http.Request, cfg *config.Config, maxMB int) (body io.Reader, ok bool) {
+	limit := int64(maxMB) << 20
+	if r.ContentLength > limit {
+		writeBodyTooLarge(w, limit)
+		return nil, false
+	}
+
+	timeout, err := time.ParseDuration(cfg.Server.Stream.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = defaultStreamTimeout
+	}
+	// Output is written while the body is still being read, which an
+	// HTTP/1 connection only allows in full-duplex mode. In-process
+	// writers (batch entries, tests) have no connection to configure, so
+	// ErrNotSupported is fine here.
+	rc := http.NewResponseController(w)
+	_ = rc.EnableFullDuplex()
+	deadline := time.Now().Add(timeout)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	return r.Body, true
+}
+
+// writeBodyTooLarge writes the 413 for a body over limit bytes.
+func writeBodyTooLarge(w http.ResponseWriter, limit int64) {
+	writeEnvelopeError(w, http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE",
+		fmt.Sprintf("Request body exceeds the %d byte limit", limit),
+		map[string]interface{}{"max_bytes": limit})
+}
+
+// writeStreamError reports err from reading or transforming a stream:
+// 413 when the body hit its cap, 400 with code otherwise.
+func writeStreamError(w http.ResponseWriter, code string, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeBodyTooLarge(w, tooLarge.Limit)
+		return
+	}
+	writeEnvelopeError(w, http.StatusBadRequest, code, err.Error(), nil)
+}
+
+// streamWriter passes a tool's output through to the client and records
+// whether any of it has been sent yet.
+type streamWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// startStream declares the error trailer; the caller has set the output's
+// Content-Type.
+func startStream(w http.ResponseWriter) *streamWriter {
+	w.Header().Set("Trailer", streamErrorTrailer)
+	return &streamWriter{w: w}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		s.started = true
+	}
+	return s.w.Write(p)
+}
+
+// finish reports err, if any. Before the first output byte it becomes an
+// ordinary error response; after it, the 200 is already on the wire, so
+// the output stops short and the error goes in the X-Stream-Error
+// trailer.
+func (s *streamWriter) finish(code string, err error) {
+	if err == nil {
+		return
+	}
+	if !s.started {
+		s.w.Header().Del("Trailer")
+		s.w.Header().Del("Content-Type")
+		writeStreamError(s.w, code, err)
+		return
+	}
+	s.w.Header().Set(streamErrorTrailer, err.Error())
+}
+
+// textHashStreamParams validates the {algorithm} path parameter of
+// apiTextHashStreamHandler: text.HashAlgorithms and their aliases.
+type textHashStreamParams struct {
+	Algorithm string `validate:"required,oneof=md5 sha1 sha256 sha384 sha512 sha3-224 sha3-256 sha3-384 sha3-512 blake2b-256 blake2b-512 blake2s-256 blake3 crc32 crc32c crc64 crc64-iso adler32 xxhash64 murmur3-32 murmur3-128 blake2b blake2s crc64-ecma xxhash xxh64 murmur3"`
+}
+
+// apiTextHashStreamHandler hashes the raw request body with {algorithm}
+// and returns the hex digest and the number of bytes hashed. The body may
+// be up to server.stream.hash_max_mb megabytes.
+func apiTextHashStreamHandler(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		algorithm := strings.ToLower(chi.URLParam(r, "algorithm"))
+		if !validateStruct(w, textHashStreamParams{Algorithm: algorithm}) {
+			return
+		}
+		body, ok := streamRequestBody(w, r, cfg, cfg.Server.Stream.HashMaxMB)
+		if !ok {
+			return
+		}
+
+		digest, n, err := text.HashReader(algorithm, body)
+		if err != nil {
+			writeStreamError(w, "INVALID_BODY", err)
+			return
+		}
+
+		writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+			"algorithm": algorithm,
+			"hash":      digest,
+			"bytes":     n,
+		})
+	}
+}
+
+// apiTextHashMultiStreamHandler hashes the raw request body with every
+// supported algorithm in a single pass. The body may be up to
+// server.stream.hash_max_mb megabytes.
+func apiTextHashMultiStreamHandler(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := streamRequestBody(w, r, cfg, cfg.Server.Stream.HashMaxMB)
+		if !ok {
+			return
+		}
+
+		hashes, n, err := text.HashAllReader(body)
+		if err != nil {
+			writeStreamError(w, "INVALID_BODY", err)
+			return
+		}
+
+		writeEnvelopeOK(w, http.StatusOK, map[string]interface{}{
+			"hashes": hashes,
+			"bytes":  n,
+		})
+	}
+}
+
+// apiTextEncodeStreamHandler encodes the raw request body with {encoding}
+// and streams the encoded text back as it is produced. The body may be
+// up to server.stream.encode_max_mb megabytes.
+func apiTextEncodeStreamHandler(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(chi.URLParam(r, "encoding"))
+		if !validateStruct(w, encodeParams{Encoding: encoding}) {
+			return
+		}
+		body, ok := streamRequestBody(w, r, cfg, cfg.Server.Stream.EncodeMaxMB)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		out := startStream(w)
+		enc, err := text.NewEncoder(encoding, out)
+		if err == nil {
+			_, err = io.Copy(enc, body)
+			if closeErr := enc.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		out.finish("INVALID_BODY", err)
+	}
+}
+
+// apiTextDecodeStreamHandler decodes the {encoding} text in the raw
+// request body and streams the decoded bytes back as they are produced.
+// The body may be up to server.stream.encode_max_mb megabytes. Input that
+// turns out to be invalid part-way through ends the output early with an
+// X-Stream-Error trailer.
+func apiTextDecodeStreamHandler(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(chi.URLParam(r, "encoding"))
+		if !validateStruct(w, decodeParams{Encoding: encoding}) {
+			return
+		}
+		body, ok := streamRequestBody(w, r, cfg, cfg.Server.Stream.EncodeMaxMB)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		out := startStream(w)
+		dec, err := text.NewDecoder(encoding, body)
+		if err == nil {
+			_, err = io.Copy(out, dec)
+		}
+		out.finish("DECODE_FAILED", err)
+	}
+}
+
+// textCompressStreamParams validates the {algorithm} path parameter of
+// the streaming compress/decompress handlers.
+type textCompressStreamParams struct {
+	Algorithm string `validate:"required,oneof=gzip zlib flate deflate"`
+}
+
+// compressedContentTypes maps a compression algorithm to the media type
+// of its output.
+var compressedContentTypes = map[string]string{
+	"gzip": "application/gzip",
+	"zlib": "application/zlib",
+}
+
+// apiTextCompressStreamHandler compresses the raw request body with
+// {algorithm} and streams the compressed bytes back. The body may be up
+// to server.stream.compress_max_mb megabytes.
+func apiTextCompressStreamHandler(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		algorithm := strings.ToLower(chi.URLParam(r, "algorithm"))
+		if !validateStruct(w, textCompressStreamParams{Algorithm: algorithm}) {
+			return
+		}
+		body, ok := streamRequestBody(w, r, cfg, cfg.Server.Stream.CompressMaxMB)
+		if !ok {
+			return
+		}
+
+		contentType := compressedContentTypes[algorithm]
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		out := startStream(w)
+		comp, err := text.NewCompressor(algorithm, out)
+		if err == nil {
+			_, err = io.Copy(comp, body)
+			if closeErr := comp.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		out.finish("COMPRESS_FAILED", err)
+	}
+}
+
+// apiTextDecompressStreamHandler decompresses the {algorithm} data in the
+// raw request body and streams the original bytes back. Both the body and
+// the decompressed output are capped at server.stream.compress_max_mb
+// megabytes, so a small compression bomb cannot produce unbounded output.
+func apiTextDecompressStreamHandler(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		algorithm := strings.ToLower(chi.URLParam(r, "algorithm"))
+		if !validateStruct(w, textCompressStreamParams{Algorithm: algorithm}) {
+			return
+		}
+		body, ok := streamRequestBody(w, r, cfg, cfg.Server.Stream.CompressMaxMB)
+		if !ok {
+			return
+		}
+
+		limit := int64(cfg.Server.Stream.CompressMaxMB) << 20
+		w.Header().Set("Content-Type", "application/octet-stream")
+		out := startStream(w)
+		dec, err := text.NewDecompressor(algorithm, body)
+		if err == nil {
+			defer dec.Close()
+			_, err = io.CopyN(out, dec, limit)
+			switch {
+			case err == io.EOF:
+				err = nil
+			case err == nil:
+				// The output reached the cap; any further byte is over it.
+				var probe [1]byte
+				if _, err = io.ReadFull(dec, probe[:]); err == nil {
+					err = fmt.Errorf("decompressed output exceeds the %d byte limit", limit)
+				} else if err == io.EOF {
+					err = nil
+				}
+			}
+		}
+		out.finish("COMPRESS_FAILED", err)
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apimgr/api/src/service/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postStream sends body to path on ts and returns the response with its
+// body fully read (so trailers are populated).
+func postStream(t *testing.T, ts *httptest.Server, path string, body io.Reader) (*http.Response, []byte) {
+	t.Helper()
+	resp, err := http.Post(ts.URL+path, "application/octet-stream", body)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, out
+}
+
+// chunked hides the length of r, so the request is sent without a
+// Content-Length and the cap can only be enforced while reading.
+func chunked(r io.Reader) io.Reader {
+	return struct{ io.Reader }{r}
+}
+
+// TestStreamHandlers drives the POST hash/encode/decode/compress routes
+// through the real router with bodies larger than one read buffer.
+func TestStreamHandlers(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Server.RateLimit.Write.Requests = 1000
+	cfg.Server.Stream.HashMaxMB = 1
+	cfg.Server.Stream.EncodeMaxMB = 1
+	cfg.Server.Stream.CompressMaxMB = 1
+	ts := httptest.NewServer(newTestServer(t, cfg).Handler)
+	defer ts.Close()
+
+	input := strings.Repeat("streamed ", 70000) // 630,000 bytes, 840,000 as base64
+
+	t.Run("hash returns the digest and byte count", func(t *testing.T) {
+		resp, out := postStream(t, ts, "/api/v1/text/hash/sha256", chunked(strings.NewReader("abc")))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(out))
+		var envelope struct {
+			Data struct {
+				Hash  string `json:"hash"`
+				Bytes int64  `json:"bytes"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(out, &envelope))
+		assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", envelope.Data.Hash)
+		assert.Equal(t, int64(3), envelope.Data.Bytes)
+
+		resp, out = postStream(t, ts, "/api/v1/text/hash/multi", strings.NewReader(input))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(out), `"sha512"`)
+		assert.Contains(t, string(out), `"bytes": 630000`)
+
+		resp, _ = postStream(t, ts, "/api/v1/crypto/hash/md5", strings.NewReader("abc"))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, _ = postStream(t, ts, "/api/v1/text/hash/whirlpool", strings.NewReader("abc"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("encode and decode stream the output", func(t *testing.T) {
+		resp, encoded := postStream(t, ts, "/api/v1/text/encode/base64", strings.NewReader(input))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"))
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(input)), string(encoded))
+
+		resp, decoded := postStream(t, ts, "/api/v1/text/decode/base64", bytes.NewReader(encoded))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, input, string(decoded))
+		assert.Empty(t, resp.Trailer.Get(streamErrorTrailer))
+	})
+
+	t.Run("compress and decompress round-trip", func(t *testing.T) {
+		resp, compressed := postStream(t, ts, "/api/v1/text/compress/gzip", strings.NewReader(input))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/gzip", resp.Header.Get("Content-Type"))
+		assert.Less(t, len(compressed), len(input))
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		plain, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, input, string(plain))
+
+		resp, plain = postStream(t, ts, "/api/v1/text/decompress/gzip", bytes.NewReader(compressed))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, input, string(plain))
+
+		resp, out := postStream(t, ts, "/api/v1/text/decompress/gzip", strings.NewReader("not gzip"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, string(out), "COMPRESS_FAILED")
+	})
+
+	t.Run("bodies over the class cap are rejected", func(t *testing.T) {
+		large := strings.Repeat("x", 1<<20+1)
+
+		resp, out := postStream(t, ts, "/api/v1/text/hash/sha256", strings.NewReader(large))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Contains(t, string(out), `"max_bytes": 1048576`)
+
+		resp, out = postStream(t, ts, "/api/v1/text/hash/sha256", chunked(strings.NewReader(large)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Contains(t, string(out), "BODY_TOO_LARGE")
+
+		// Encoding output has already started when the cap is hit.
+		resp, _ = postStream(t, ts, "/api/v1/text/encode/hex", chunked(strings.NewReader(large)))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Trailer.Get(streamErrorTrailer), "too large")
+	})
+
+	t.Run("decompressed output is capped", func(t *testing.T) {
+		var bomb bytes.Buffer
+		zw := gzip.NewWriter(&bomb)
+		zw.Write(make([]byte, 2<<20))
+		require.NoError(t, zw.Close())
+
+		resp, out := postStream(t, ts, "/api/v1/text/decompress/gzip", &bomb)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, out, 1<<20)
+		assert.Contains(t, resp.Trailer.Get(streamErrorTrailer), "exceeds")
+	})
+
+	t.Run("invalid input mid-stream is reported in the trailer", func(t *testing.T) {
+		body := base64.StdEncoding.EncodeToString([]byte(input)) + "!!!!"
+		resp, out := postStream(t, ts, "/api/v1/text/decode/base64", strings.NewReader(body))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(t, out)
+		assert.Contains(t, resp.Trailer.Get(streamErrorTrailer), "illegal base64")
+
+		resp, out = postStream(t, ts, "/api/v1/text/decode/base64", strings.NewReader("!!!!"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, string(out), "DECODE_FAILED")
+	})
+}
+
+// textHashStreamParams must accept every algorithm text.Hash supports.
+func TestTextHashStreamParams(t *testing.T) {
+	for _, alg := range text.HashAlgorithms {
+		assert.NoError(t, validate.Struct(textHashStreamParams{Algorithm: alg}), alg)
+	}
+	assert.Error(t, validate.Struct(textHashStreamParams{Algorithm: "whirlpool"}))
+}
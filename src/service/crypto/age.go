@@ -0,0 +1,147 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// AgeMaxWorkFactor caps the scrypt work factor (log2 N) of age passphrase
+// encryption and decryption. 18 is age's own default, so files from the
+// age CLI always decrypt, while a crafted header cannot demand more.
+const AgeMaxWorkFactor = 18
+
+// AgeKeyPair is an age X25519 identity ("AGE-SECRET-KEY-1...") and its
+// recipient ("age1...").
+type AgeKeyPair struct {
+	Identity  string `json:"identity"`
+	Recipient string `json:"recipient"`
+}
+
+// GenerateAgeKeyPair creates a new age X25519 identity.
+func GenerateAgeKeyPair() (*AgeKeyPair, error) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	return &AgeKeyPair{Identity: id.String(), Recipient: id.Recipient().String()}, nil
+}
+
+// AgeEncrypt encrypts plaintext in the age v1 format (age-encryption.org)
+// to either the X25519 recipients or a passphrase; age does not allow both
+// in one file. workFactor is the scrypt log2 N for passphrases, 1 to
+// AgeMaxWorkFactor (0 means 18). The result is ASCII-armored when armored
+// is true and base64 of the binary file otherwise.
+func AgeEncrypt(plaintext []byte, recipients []string, passphrase string, workFactor int, armored bool) (string, error) {
+	var rcpts []age.Recipient
+	switch {
+	case len(recipients) > 0 && passphrase != "":
+		return "", fmt.Errorf("use either recipients or a passphrase, not both")
+	case passphrase != "":
+		if workFactor == 0 {
+			workFactor = AgeMaxWorkFactor
+		}
+		if workFactor < 1 || workFactor > AgeMaxWorkFactor {
+			return "", fmt.Errorf("work factor must be between 1 and %d", AgeMaxWorkFactor)
+		}
+		r, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return "", err
+		}
+		r.SetWorkFactor(workFactor)
+		rcpts = append(rcpts, r)
+	case len(recipients) > 0:
+		for _, s := range recipients {
+			r, err := age.ParseX25519Recipient(strings.TrimSpace(s))
+			if err != nil {
+				return "", fmt.Errorf("invalid recipient %q: %w", s, err)
+			}
+			rcpts = append(rcpts, r)
+		}
+	default:
+		return "", fmt.Errorf("at least one recipient or a passphrase is required")
+	}
+
+	var buf bytes.Buffer
+	var dst io.WriteCloser = nopWriteCloser{&buf}
+	if armored {
+		dst = armor.NewWriter(&buf)
+	}
+	w, err := age.Encrypt(dst, rcpts...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		return "", err
+	}
+	if armored {
+		return buf.String(), nil
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// AgeDecrypt decrypts an age file with the X25519 identities or the
+// passphrase. ciphertext may be ASCII-armored, the raw binary file, or
+// base64 of it.
+func AgeDecrypt(ciphertext string, identities []string, passphrase string) ([]byte, error) {
+	var ids []age.Identity
+	for _, s := range identities {
+		id, err := age.ParseX25519Identity(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid identity: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if passphrase != "" {
+		id, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		id.SetMaxWorkFactor(AgeMaxWorkFactor)
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one identity or a passphrase is required")
+	}
+
+	var src io.Reader
+	trimmed := strings.TrimSpace(ciphertext)
+	switch {
+	case strings.HasPrefix(trimmed, armor.Header):
+		src = armor.NewReader(strings.NewReader(trimmed + "\n"))
+	case strings.HasPrefix(ciphertext, "age-encryption.org/"):
+		src = strings.NewReader(ciphertext)
+	default:
+		raw, err := base64.StdEncoding.DecodeString(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("ciphertext must be an armored or base64-encoded age file")
+		}
+		src = bytes.NewReader(raw)
+	}
+
+	r, err := age.Decrypt(src, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+// nopWriteCloser adds a no-op Close to an io.Writer.
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,404 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// HardenedKeyStart is the first hardened BIP32 child index (2^31).
+const HardenedKeyStart = 0x80000000
+
+// maxDerivationDepth is the deepest key BIP32 can serialize: depth is a
+// single byte.
+const maxDerivationDepth = 255
+
+// extendedKeyVersions are the serialization prefixes for mainnet
+// (xprv/xpub) and testnet (tprv/tpub) keys.
+var extendedKeyVersions = map[string]struct{ private, public [4]byte }{
+	"mainnet": {[4]byte{0x04, 0x88, 0xad, 0xe4}, [4]byte{0x04, 0x88, 0xb2, 0x1e}},
+	"testnet": {[4]byte{0x04, 0x35, 0x83, 0x94}, [4]byte{0x04, 0x35, 0x87, 0xcf}},
+}
+
+// ExtendedKey is a BIP32 extended private or public key on secp256k1.
+// Key holds the 32-byte private scalar or the 33-byte compressed point.
+type ExtendedKey struct {
+	Network           string
+	Depth             uint8
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+	ChainCode         []byte
+	Key               []byte
+	Private           bool
+}
+
+// NewMasterKey derives the BIP32 master key for a 16 to 64 byte seed, such
+// as the one MnemonicToSeed returns. network is "mainnet" (the default) or
+// "testnet" and only affects serialization.
+func NewMasterKey(seed []byte, network string) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be 16 to 64 bytes, got %d", len(seed))
+	}
+	network, err := extendedKeyNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	var k secp256k1.ModNScalar
+	if overflow := k.SetByteSlice(sum[:32]); overflow || k.IsZero() {
+		return nil, fmt.Errorf("seed produces an invalid master key")
+	}
+	return &ExtendedKey{
+		Network:   network,
+		ChainCode: sum[32:],
+		Key:       sum[:32],
+		Private:   true,
+	}, nil
+}
+
+// extendedKeyNetwork normalizes network, defaulting to mainnet.
+func extendedKeyNetwork(network string) (string, error) {
+	network = strings.ToLower(strings.TrimSpace(network))
+	if network == "" {
+		return "mainnet", nil
+	}
+	if _, ok := extendedKeyVersions[network]; !ok {
+		return "", fmt.Errorf("network must be mainnet or testnet")
+	}
+	return network, nil
+}
+
+// ParseExtendedKey decodes a base58check xprv, xpub, tprv or tpub string.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	data, err := base58CheckDecode(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 78 {
+		return nil, fmt.Errorf("extended key is %d bytes, want 78", len(data))
+	}
+
+	key := &ExtendedKey{
+		Depth:       data[4],
+		ChildNumber: binary.BigEndian.Uint32(data[9:13]),
+		ChainCode:   append([]byte{}, data[13:45]...),
+	}
+	copy(key.ParentFingerprint[:], data[5:9])
+
+	var version [4]byte
+	copy(version[:], data[:4])
+	for network, v := range extendedKeyVersions {
+		switch version {
+		case v.private:
+			key.Network, key.Private = network, true
+		case v.public:
+			key.Network = network
+		}
+	}
+	if key.Network == "" {
+		return nil, fmt.Errorf("unknown extended key version %x", version)
+	}
+	if key.Depth == 0 && (key.ChildNumber != 0 || key.ParentFingerprint != [4]byte{}) {
+		return nil, fmt.Errorf("master key has a parent fingerprint or child number")
+	}
+
+	if key.Private {
+		if data[45] != 0 {
+			return nil, fmt.Errorf("private key is not prefixed with 0x00")
+		}
+		var k secp256k1.ModNScalar
+		if overflow := k.SetByteSlice(data[46:]); overflow || k.IsZero() {
+			return nil, fmt.Errorf("private key is out of range")
+		}
+		key.Key = append([]byte{}, data[46:]...)
+	} else {
+		if _, err := secp256k1.ParsePubKey(data[45:]); err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		key.Key = append([]byte{}, data[45:]...)
+	}
+	return key, nil
+}
+
+// String serializes the key in base58check form (xprv/xpub/tprv/tpub).
+func (k *ExtendedKey) String() string {
+	versions := extendedKeyVersions[k.Network]
+	version := versions.public
+	if k.Private {
+		version = versions.private
+	}
+
+	buf := make([]byte, 0, 78)
+	buf = append(buf, version[:]...)
+	buf = append(buf, k.Depth)
+	buf = append(buf, k.ParentFingerprint[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, k.ChildNumber)
+	buf = append(buf, k.ChainCode...)
+	if k.Private {
+		buf = append(buf, 0)
+	}
+	buf = append(buf, k.Key...)
+	return base58CheckEncode(buf)
+}
+
+// PublicKey returns the 33-byte compressed public key.
+func (k *ExtendedKey) PublicKey() []byte {
+	if !k.Private {
+		return k.Key
+	}
+	return secp256k1.PrivKeyFromBytes(k.Key).PubKey().SerializeCompressed()
+}
+
+// Fingerprint returns the first four bytes of HASH160 of the public key,
+// which children record as their parent fingerprint.
+func (k *ExtendedKey) Fingerprint() [4]byte {
+	sha := sha256.Sum256(k.PublicKey())
+	h := ripemd160.New()
+	h.Write(sha[:])
+	var fp [4]byte
+	copy(fp[:], h.Sum(nil))
+	return fp
+}
+
+// Neuter returns the extended public key for k.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	pub := *k
+	pub.Key = k.PublicKey()
+	pub.Private = false
+	return &pub
+}
+
+// Child derives child index i (CKDpriv, or CKDpub for a public key).
+// Public keys cannot derive hardened children. BIP32 asks callers to skip
+// the rare index whose derivation is invalid; Child reports it as an
+// error instead.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	if k.Depth == maxDerivationDepth {
+		return nil, fmt.Errorf("cannot derive beyond depth %d", maxDerivationDepth)
+	}
+	if i >= HardenedKeyStart && !k.Private {
+		return nil, fmt.Errorf("cannot derive hardened child %s from a public key", formatChildIndex(i))
+	}
+
+	data := make([]byte, 0, 37)
+	if i >= HardenedKeyStart {
+		data = append(append(data, 0), k.Key...)
+	} else {
+		data = append(data, k.PublicKey()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+
+	mac := hmac.New(sha512.New, k.ChainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	var il secp256k1.ModNScalar
+	if overflow := il.SetByteSlice(sum[:32]); overflow {
+		return nil, fmt.Errorf("child %s is invalid, use the next index", formatChildIndex(i))
+	}
+
+	child := &ExtendedKey{
+		Network:           k.Network,
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.Fingerprint(),
+		ChildNumber:       i,
+		ChainCode:         sum[32:],
+		Private:           k.Private,
+	}
+
+	if k.Private {
+		var parent secp256k1.ModNScalar
+		parent.SetByteSlice(k.Key)
+		il.Add(&parent)
+		if il.IsZero() {
+			return nil, fmt.Errorf("child %s is invalid, use the next index", formatChildIndex(i))
+		}
+		b := il.Bytes()
+		child.Key = b[:]
+		return child, nil
+	}
+
+	parent, err := secp256k1.ParsePubKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	var point, parentPoint, sumPoint secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&il, &point)
+	parent.AsJacobian(&parentPoint)
+	secp256k1.AddNonConst(&point, &parentPoint, &sumPoint)
+	if sumPoint.Z.IsZero() {
+		return nil, fmt.Errorf("child %s is invalid, use the next index", formatChildIndex(i))
+	}
+	sumPoint.ToAffine()
+	child.Key = secp256k1.NewPublicKey(&sumPoint.X, &sumPoint.Y).SerializeCompressed()
+	return child, nil
+}
+
+// Derive walks path from k. Paths look like "m/44'/0'/0'/0/0": "m" (or
+// "M") is k itself and a trailing ', h or H marks a hardened index.
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, i := range indexes {
+		if key, err = key.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ParseDerivationPath parses a BIP32 path into child indexes, with
+// hardened indexes offset by HardenedKeyStart.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" && parts[0] != "M" {
+		return nil, fmt.Errorf("derivation path must start with m")
+	}
+	parts = parts[1:]
+	if len(parts) > maxDerivationDepth {
+		return nil, fmt.Errorf("derivation path is deeper than %d", maxDerivationDepth)
+	}
+
+	indexes := make([]uint32, len(parts))
+	for n, part := range parts {
+		hardened := false
+		if s, ok := strings.CutSuffix(part, "'"); ok {
+			part, hardened = s, true
+		} else if s, ok := strings.CutSuffix(strings.ToLower(part), "h"); ok {
+			part, hardened = s, true
+		}
+		i, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || i >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid path component %q", parts[n])
+		}
+		indexes[n] = uint32(i)
+		if hardened {
+			indexes[n] += HardenedKeyStart
+		}
+	}
+	return indexes, nil
+}
+
+// formatChildIndex renders a child index the way paths write it.
+func formatChildIndex(i uint32) string {
+	if i >= HardenedKeyStart {
+		return strconv.FormatUint(uint64(i-HardenedKeyStart), 10) + "'"
+	}
+	return strconv.FormatUint(uint64(i), 10)
+}
+
+// DerivedKey is the JSON description of an extended key returned by
+// DeriveKeyInfo.
+type DerivedKey struct {
+	Path               string `json:"path"`
+	Network            string `json:"network"`
+	Depth              int    `json:"depth"`
+	ChildNumber        string `json:"child_number"`
+	Fingerprint        string `json:"fingerprint"`
+	ParentFingerprint  string `json:"parent_fingerprint"`
+	ChainCode          string `json:"chain_code"`
+	ExtendedPrivateKey string `json:"extended_private_key,omitempty"`
+	ExtendedPublicKey  string `json:"extended_public_key"`
+	PrivateKey         string `json:"private_key,omitempty"`
+	PublicKey          string `json:"public_key"`
+}
+
+// DeriveKeyInfo derives path from k and describes the result: the
+// serialized extended keys plus the raw keys, chain code and fingerprints
+// in hex. Private fields are omitted when k is a public key.
+func DeriveKeyInfo(k *ExtendedKey, path string) (*DerivedKey, error) {
+	key, err := k.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	fp := key.Fingerprint()
+	info := &DerivedKey{
+		Path:              strings.TrimSpace(path),
+		Network:           key.Network,
+		Depth:             int(key.Depth),
+		ChildNumber:       formatChildIndex(key.ChildNumber),
+		Fingerprint:       hex.EncodeToString(fp[:]),
+		ParentFingerprint: hex.EncodeToString(key.ParentFingerprint[:]),
+		ChainCode:         hex.EncodeToString(key.ChainCode),
+		ExtendedPublicKey: key.Neuter().String(),
+		PublicKey:         hex.EncodeToString(key.PublicKey()),
+	}
+	if key.Private {
+		info.ExtendedPrivateKey = key.String()
+		info.PrivateKey = hex.EncodeToString(key.Key)
+	}
+	return info, nil
+}
+
+// base58Alphabet is the Bitcoin base58 alphabet.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58CheckEncode appends a double-SHA-256 checksum and base58-encodes.
+func base58CheckEncode(payload []byte) string {
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	data := append(append([]byte{}, payload...), second[:4]...)
+
+	n := new(big.Int).SetBytes(data)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// base58CheckDecode reverses base58CheckEncode, verifying the checksum.
+func base58CheckDecode(s string) ([]byte, error) {
+	n, radix := new(big.Int), big.NewInt(58)
+	zeros := 0
+	for i, c := range s {
+		d := strings.IndexRune(base58Alphabet, c)
+		if d < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		if d == 0 && i == zeros {
+			zeros++
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(d)))
+	}
+	data := append(make([]byte, zeros), n.Bytes()...)
+	if len(data) < 4 {
+		return nil, fmt.Errorf("base58check string is too short")
+	}
+
+	payload, checksum := data[:len(data)-4], data[len(data)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(checksum, second[:4]) {
+		return nil, fmt.Errorf("invalid base58check checksum")
+	}
+	return payload, nil
+}
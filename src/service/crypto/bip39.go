@@ -0,0 +1,235 @@
+package crypto
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MnemonicLanguages lists the BIP39 wordlists embedded in the binary, in
+// the order ValidateMnemonic tries them when no language is given.
+var MnemonicLanguages = []string{
+	"english", "japanese", "korean", "spanish", "chinese_simplified",
+	"chinese_traditional", "french", "italian", "czech",
+}
+
+// MnemonicWordCounts lists the mnemonic lengths BIP39 defines, for 128 to
+// 256 bits of entropy in 32-bit steps.
+var MnemonicWordCounts = []int{12, 15, 18, 21, 24}
+
+// wordlistFiles holds the official BIP39 wordlists
+// (github.com/bitcoin/bips/tree/master/bip-0039), one word per line.
+//
+//go:embed wordlists/*.txt
+var wordlistFiles embed.FS
+
+// mnemonicWordlist is one parsed wordlist: words in index order, NFKD
+// normalized so lookups match regardless of how accents were typed.
+type mnemonicWordlist struct {
+	words []string
+	index map[string]int
+}
+
+// loadWordlists parses every embedded wordlist once, on first use.
+var loadWordlists = sync.OnceValue(func() map[string]*mnemonicWordlist {
+	lists := make(map[string]*mnemonicWordlist, len(MnemonicLanguages))
+	for _, lang := range MnemonicLanguages {
+		data, err := wordlistFiles.ReadFile("wordlists/" + lang + ".txt")
+		if err != nil {
+			panic("crypto: missing BIP39 wordlist " + lang)
+		}
+		words := strings.Fields(norm.NFKD.String(string(data)))
+		if len(words) != 2048 {
+			panic(fmt.Sprintf("crypto: BIP39 wordlist %s has %d words", lang, len(words)))
+		}
+		list := &mnemonicWordlist{words: words, index: make(map[string]int, len(words))}
+		for i, w := range words {
+			list.index[w] = i
+		}
+		lists[lang] = list
+	}
+	return lists
+})
+
+// mnemonicWordlistFor returns the wordlist for language, defaulting to
+// English when language is empty.
+func mnemonicWordlistFor(language string) (*mnemonicWordlist, string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" {
+		language = "english"
+	}
+	list, ok := loadWordlists()[language]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported language %q (supported: %s)", language, strings.Join(MnemonicLanguages, ", "))
+	}
+	return list, language, nil
+}
+
+// GenerateMnemonic creates a random BIP39 mnemonic of words words (12, 15,
+// 18, 21 or 24) from language's wordlist.
+func GenerateMnemonic(words int, language string) (string, error) {
+	if words%3 != 0 || words < 12 || words > 24 {
+		return "", fmt.Errorf("word count must be one of 12, 15, 18, 21 or 24")
+	}
+	entropy := make([]byte, words/3*4)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy, language)
+}
+
+// MnemonicFromEntropy encodes 16 to 32 bytes of entropy (a multiple of 4)
+// as a BIP39 mnemonic, appending the SHA-256 checksum bits. Japanese
+// mnemonics are joined with ideographic spaces, as the specification
+// recommends; every other language uses ASCII spaces.
+func MnemonicFromEntropy(entropy []byte, language string) (string, error) {
+	if len(entropy)%4 != 0 || len(entropy) < 16 || len(entropy) > 32 {
+		return "", fmt.Errorf("entropy must be 16, 20, 24, 28 or 32 bytes, got %d", len(entropy))
+	}
+	list, language, err := mnemonicWordlistFor(language)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := sha256.Sum256(entropy)
+	bits := append(append([]byte{}, entropy...), checksum[0])
+	words := make([]string, len(entropy)*3/4)
+	for i := range words {
+		idx := 0
+		for b := i * 11; b < i*11+11; b++ {
+			idx = idx<<1 | int(bits[b/8]>>(7-b%8)&1)
+		}
+		words[i] = norm.NFC.String(list.words[idx])
+	}
+
+	sep := " "
+	if language == "japanese" {
+		sep = "\u3000"
+	}
+	return strings.Join(words, sep), nil
+}
+
+// MnemonicInfo describes a mnemonic checked by ValidateMnemonic.
+type MnemonicInfo struct {
+	Valid    bool   `json:"valid"`
+	Language string `json:"language,omitempty"`
+	Words    int    `json:"words"`
+	Entropy  string `json:"entropy,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ValidateMnemonic checks that every word of mnemonic is in the wordlist
+// for language and that the checksum matches. With no language, each
+// wordlist in MnemonicLanguages is tried in turn and the first one that
+// contains every word is used. Entropy is hex and only set when Valid.
+func ValidateMnemonic(mnemonic, language string) MnemonicInfo {
+	words := mnemonicWords(mnemonic)
+	info := MnemonicInfo{Words: len(words)}
+
+	entropy, lang, err := decodeMnemonic(words, language)
+	info.Language = lang
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Valid = true
+	info.Entropy = hex.EncodeToString(entropy)
+	return info
+}
+
+// MnemonicToSeed validates mnemonic and derives its 64-byte BIP39 seed:
+// PBKDF2-HMAC-SHA512 over the NFKD-normalized sentence with salt
+// "mnemonic"+passphrase and 2048 iterations. The words are re-joined with
+// single spaces first, so extra whitespace or ideographic spaces do not
+// change the seed.
+func MnemonicToSeed(mnemonic, passphrase, language string) ([]byte, error) {
+	words := mnemonicWords(mnemonic)
+	if _, _, err := decodeMnemonic(words, language); err != nil {
+		return nil, err
+	}
+	sentence := strings.Join(words, " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key(sha512.New, sentence, []byte(salt), 2048, 64)
+}
+
+// mnemonicWords splits mnemonic into lower-cased, NFKD-normalized words.
+// strings.Fields also splits on the ideographic space Japanese uses.
+func mnemonicWords(mnemonic string) []string {
+	return strings.Fields(strings.ToLower(norm.NFKD.String(mnemonic)))
+}
+
+// decodeMnemonic maps words back to their entropy, verifying the word
+// count and checksum. It returns the language the words were found in.
+func decodeMnemonic(words []string, language string) ([]byte, string, error) {
+	var list *mnemonicWordlist
+	if strings.TrimSpace(language) == "" {
+		language, list = detectMnemonicLanguage(words)
+		if list == nil {
+			return nil, "", fmt.Errorf("mnemonic words do not match any supported wordlist")
+		}
+	} else {
+		var err error
+		if list, language, err = mnemonicWordlistFor(language); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, language, fmt.Errorf("mnemonic has %d words, want 12, 15, 18, 21 or 24", len(words))
+	}
+
+	bits := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		idx, ok := list.index[w]
+		if !ok {
+			return nil, language, fmt.Errorf("word %d (%q) is not in the %s wordlist", i+1, norm.NFC.String(w), language)
+		}
+		for b := 0; b < 11; b++ {
+			if idx>>(10-b)&1 == 1 {
+				pos := i*11 + b
+				bits[pos/8] |= 1 << (7 - pos%8)
+			}
+		}
+	}
+
+	entropyLen := len(words) / 3 * 4
+	entropy := bits[:entropyLen]
+	checksumBits := len(words) / 3
+	checksum := sha256.Sum256(entropy)
+	want := checksum[0] >> (8 - checksumBits)
+	got := bits[entropyLen] >> (8 - checksumBits)
+	if want != got {
+		return nil, language, fmt.Errorf("invalid checksum")
+	}
+	return entropy, language, nil
+}
+
+// detectMnemonicLanguage returns the first wordlist containing every word.
+func detectMnemonicLanguage(words []string) (string, *mnemonicWordlist) {
+	if len(words) == 0 {
+		return "", nil
+	}
+	lists := loadWordlists()
+	for _, lang := range MnemonicLanguages {
+		list := lists[lang]
+		found := true
+		for _, w := range words {
+			if _, ok := list.index[w]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return lang, list
+		}
+	}
+	return "", nil
+}
@@ -3,8 +3,11 @@ package crypto
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
 	"math/big"
@@ -63,8 +66,10 @@ func GenerateCertificate(commonName string, validDays int) (string, string, erro
 }
 
 // ParseCertificate parses a PEM-encoded X.509 certificate and returns its
-// subject, issuer, validity window, serial number, DNS SANs, CA flag, and
-// signature algorithm as a JSON-friendly map.
+// subject, issuer, validity window, serial number, subject alternative
+// names, CA flag and path length, key usages, key identifiers, signature
+// algorithm, certificate fingerprints, and the DescribeKey details of its
+// public key (when supported) as a JSON-friendly map.
 func ParseCertificate(certPEM string) (map[string]interface{}, error) {
 	block, _ := pem.Decode([]byte(certPEM))
 	if block == nil || block.Type != "CERTIFICATE" {
@@ -75,17 +80,43 @@ func ParseCertificate(certPEM string) (map[string]interface{}, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse certificate: %w", err)
 	}
+	return describeCertificate(cert), nil
+}
+
+// describeCertificate builds the ParseCertificate map for cert.
+func describeCertificate(cert *x509.Certificate) map[string]interface{} {
+	sha1Sum := sha1.Sum(cert.Raw)
+	sha256Sum := sha256.Sum256(cert.Raw)
 
-	return map[string]interface{}{
+	details := map[string]interface{}{
 		"subject":              cert.Subject.String(),
 		"issuer":               cert.Issuer.String(),
 		"serial_number":        cert.SerialNumber.String(),
 		"not_before":           cert.NotBefore.UTC().Format(time.RFC3339),
 		"not_after":            cert.NotAfter.UTC().Format(time.RFC3339),
 		"dns_names":            cert.DNSNames,
+		"ip_addresses":         ipStrings(cert.IPAddresses),
+		"email_addresses":      nonNil(cert.EmailAddresses),
+		"uris":                 uriStrings(cert.URIs),
 		"is_ca":                cert.IsCA,
+		"key_usage":            keyUsageNames(cert.KeyUsage),
+		"ext_key_usage":        extKeyUsageNames(cert),
+		"subject_key_id":       hex.EncodeToString(cert.SubjectKeyId),
+		"authority_key_id":     hex.EncodeToString(cert.AuthorityKeyId),
 		"signature_algorithm":  cert.SignatureAlgorithm.String(),
 		"public_key_algorithm": cert.PublicKeyAlgorithm.String(),
 		"version":              cert.Version,
-	}, nil
+		"fingerprint_sha1":     hex.EncodeToString(sha1Sum[:]),
+		"fingerprint_sha256":   hex.EncodeToString(sha256Sum[:]),
+	}
+	// -1 (or 0 without MaxPathLenZero) means the CA sets no limit.
+	if cert.IsCA && (cert.MaxPathLen > 0 || cert.MaxPathLenZero) {
+		details["max_path_len"] = cert.MaxPathLen
+	}
+	// Key types DescribeKey does not know (such as DSA) are left out
+	// rather than failing the whole parse.
+	if keyInfo, err := DescribeKey(cert.PublicKey); err == nil {
+		details["public_key"] = keyInfo
+	}
+	return details
 }
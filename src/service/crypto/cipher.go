@@ -0,0 +1,437 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// CipherAlgorithms lists the ciphers CipherEncrypt and CipherDecrypt
+// support. Unlike AESEncrypt, every input is explicit, so output can be
+// compared byte for byte with other implementations.
+var CipherAlgorithms = []string{"aes-cbc", "aes-ctr", "aes-gcm", "chacha20-poly1305", "xchacha20-poly1305"}
+
+// KDFAlgorithms lists the key-derivation functions DeriveKey supports.
+var KDFAlgorithms = []string{"argon2id", "argon2i", "scrypt", "pbkdf2-sha256", "pbkdf2-sha512", "pbkdf2-sha1"}
+
+// CipherOptions are the explicit inputs to CipherEncrypt and
+// CipherDecrypt. IV is the nonce for the AEAD ciphers; CipherEncrypt
+// generates a random one when it is empty.
+type CipherOptions struct {
+	Algorithm string
+	Key       []byte
+	IV        []byte
+	// AAD is additional authenticated data (aes-gcm and the ChaCha20
+	// ciphers only).
+	AAD []byte
+	// Padding is "pkcs7" (default) or "none", for aes-cbc only.
+	Padding string
+}
+
+// cipherIVSize is the IV or nonce size each algorithm generates by
+// default. aes-gcm also accepts other nonce sizes.
+var cipherIVSize = map[string]int{
+	"aes-cbc":            aes.BlockSize,
+	"aes-ctr":            aes.BlockSize,
+	"aes-gcm":            12,
+	"chacha20-poly1305":  chacha20poly1305.NonceSize,
+	"xchacha20-poly1305": chacha20poly1305.NonceSizeX,
+}
+
+// normalize lower-cases the algorithm and padding and checks the key and
+// IV sizes. An empty IV is allowed; CipherEncrypt fills it in.
+func (o *CipherOptions) normalize() error {
+	o.Algorithm = strings.ToLower(o.Algorithm)
+	ivSize, ok := cipherIVSize[o.Algorithm]
+	if !ok {
+		return fmt.Errorf("algorithm must be one of %s", strings.Join(CipherAlgorithms, ", "))
+	}
+	if strings.HasPrefix(o.Algorithm, "aes-") {
+		if n := len(o.Key); n != 16 && n != 24 && n != 32 {
+			return fmt.Errorf("AES key must be 16, 24 or 32 bytes, got %d", n)
+		}
+	} else if len(o.Key) != chacha20poly1305.KeySize {
+		return fmt.Errorf("ChaCha20 key must be %d bytes, got %d", chacha20poly1305.KeySize, len(o.Key))
+	}
+
+	o.Padding = strings.ToLower(o.Padding)
+	if o.Padding == "" {
+		o.Padding = "pkcs7"
+	}
+	if o.Padding != "pkcs7" && o.Padding != "none" {
+		return fmt.Errorf("padding must be pkcs7 or none")
+	}
+	if len(o.AAD) > 0 && (o.Algorithm == "aes-cbc" || o.Algorithm == "aes-ctr") {
+		return fmt.Errorf("%s does not take additional authenticated data", o.Algorithm)
+	}
+
+	if len(o.IV) == 0 {
+		return nil
+	}
+	if o.Algorithm == "aes-gcm" {
+		if len(o.IV) < 8 || len(o.IV) > 64 {
+			return fmt.Errorf("aes-gcm nonce must be 8-64 bytes, got %d", len(o.IV))
+		}
+	} else if len(o.IV) != ivSize {
+		return fmt.Errorf("%s IV must be %d bytes, got %d", o.Algorithm, ivSize, len(o.IV))
+	}
+	return nil
+}
+
+// aead returns the AEAD for the aes-gcm and ChaCha20 algorithms.
+func (o *CipherOptions) aead() (cipher.AEAD, error) {
+	switch o.Algorithm {
+	case "chacha20-poly1305":
+		return chacha20poly1305.New(o.Key)
+	case "xchacha20-poly1305":
+		return chacha20poly1305.NewX(o.Key)
+	}
+	block, err := aes.NewCipher(o.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.IV) == 12 {
+		return cipher.NewGCM(block)
+	}
+	return cipher.NewGCMWithNonceSize(block, len(o.IV))
+}
+
+// CipherEncrypt encrypts plaintext with opts, returning the ciphertext and
+// the IV that was used. AEAD output is the ciphertext with the 16-byte tag
+// appended, the layout Go, WebCrypto and libsodium use.
+func CipherEncrypt(plaintext []byte, opts CipherOptions) (ciphertext, iv []byte, err error) {
+	if err := opts.normalize(); err != nil {
+		return nil, nil, err
+	}
+	if len(opts.IV) == 0 {
+		if opts.IV, err = RandomBytes(cipherIVSize[opts.Algorithm]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch opts.Algorithm {
+	case "aes-cbc":
+		if opts.Padding == "pkcs7" {
+			plaintext = pkcs7Pad(plaintext, aes.BlockSize)
+		} else if len(plaintext)%aes.BlockSize != 0 {
+			return nil, nil, fmt.Errorf("plaintext must be a multiple of %d bytes without padding", aes.BlockSize)
+		}
+		block, err := aes.NewCipher(opts.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, opts.IV).CryptBlocks(out, plaintext)
+		return out, opts.IV, nil
+	case "aes-ctr":
+		block, err := aes.NewCipher(opts.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := make([]byte, len(plaintext))
+		cipher.NewCTR(block, opts.IV).XORKeyStream(out, plaintext)
+		return out, opts.IV, nil
+	}
+
+	aead, err := opts.aead()
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead.Seal(nil, opts.IV, plaintext, opts.AAD), opts.IV, nil
+}
+
+// CipherDecrypt reverses CipherEncrypt. opts.IV is required. For the AEAD
+// algorithms ciphertext must end with the authentication tag.
+func CipherDecrypt(ciphertext []byte, opts CipherOptions) ([]byte, error) {
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+	if len(opts.IV) == 0 {
+		return nil, fmt.Errorf("IV is required to decrypt")
+	}
+
+	switch opts.Algorithm {
+	case "aes-cbc":
+		if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("ciphertext must be a non-empty multiple of %d bytes", aes.BlockSize)
+		}
+		block, err := aes.NewCipher(opts.Key)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, opts.IV).CryptBlocks(out, ciphertext)
+		if opts.Padding == "none" {
+			return out, nil
+		}
+		return pkcs7Unpad(out, aes.BlockSize)
+	case "aes-ctr":
+		block, err := aes.NewCipher(opts.Key)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, len(ciphertext))
+		cipher.NewCTR(block, opts.IV).XORKeyStream(out, ciphertext)
+		return out, nil
+	}
+
+	aead, err := opts.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.Overhead() {
+		return nil, fmt.Errorf("ciphertext is shorter than the %d-byte tag", aead.Overhead())
+	}
+	plaintext, err := aead.Open(nil, opts.IV, ciphertext, opts.AAD)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: message authentication failed")
+	}
+	return plaintext, nil
+}
+
+// pkcs7Pad appends PKCS#7 padding, always adding at least one byte.
+func pkcs7Pad(b []byte, blockSize int) []byte {
+	n := blockSize - len(b)%blockSize
+	return append(append([]byte{}, b...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// pkcs7Unpad strips and checks PKCS#7 padding.
+func pkcs7Unpad(b []byte, blockSize int) ([]byte, error) {
+	n := int(b[len(b)-1])
+	if n == 0 || n > blockSize || n > len(b) {
+		return nil, fmt.Errorf("invalid PKCS#7 padding (wrong key or IV?)")
+	}
+	for _, p := range b[len(b)-n:] {
+		if int(p) != n {
+			return nil, fmt.Errorf("invalid PKCS#7 padding (wrong key or IV?)")
+		}
+	}
+	return b[:len(b)-n], nil
+}
+
+// DecodeBytes decodes s as "utf8" (taken as is), "hex" or "base64",
+// naming field in the error.
+func DecodeBytes(field, s, encoding string) ([]byte, error) {
+	var b []byte
+	var err error
+	switch encoding {
+	case "hex":
+		b, err = hex.DecodeString(strings.TrimSpace(s))
+	case "base64":
+		b, err = base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	default:
+		return []byte(s), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s must be %s: %w", field, encoding, err)
+	}
+	return b, nil
+}
+
+// EncodeBytes is the inverse of DecodeBytes. "utf8" output that is not
+// valid UTF-8 falls back to hex; the encoding actually used is returned.
+func EncodeBytes(b []byte, encoding string) (string, string) {
+	switch {
+	case encoding == "base64":
+		return base64.StdEncoding.EncodeToString(b), encoding
+	case encoding == "utf8" && utf8.Valid(b):
+		return string(b), encoding
+	}
+	return hex.EncodeToString(b), "hex"
+}
+
+// KDFOptions selects a key-derivation function and its inputs. Salt is
+// hex; DeriveKey generates Params.SaltLength random bytes when it is
+// empty. Params uses the same fields and defaults as PasswordHash.
+type KDFOptions struct {
+	Algorithm string             `json:"algorithm"`
+	Salt      string             `json:"salt"`
+	Params    PasswordHashParams `json:"params"`
+}
+
+// DeriveKey runs passphrase through opts' KDF and returns the key along
+// with the options actually used, defaults and salt filled in, so the
+// same key can be derived again elsewhere. pbkdf2-sha1 (default 10000
+// iterations) is accepted for compatibility with older tools.
+func DeriveKey(passphrase string, opts KDFOptions) ([]byte, KDFOptions, error) {
+	opts.Algorithm = strings.ToLower(opts.Algorithm)
+	if opts.Algorithm == "" {
+		opts.Algorithm = "argon2id"
+	}
+	if !slices.Contains(KDFAlgorithms, opts.Algorithm) {
+		return nil, opts, fmt.Errorf("kdf algorithm must be one of %s", strings.Join(KDFAlgorithms, ", "))
+	}
+	def := passwordHashDefaults[opts.Algorithm]
+	if opts.Algorithm == "pbkdf2-sha1" {
+		def = PasswordHashParams{Iterations: 10_000, SaltLength: 16, KeyLength: 32}
+	}
+
+	var salt []byte
+	var err error
+	if opts.Salt != "" {
+		if salt, err = hex.DecodeString(opts.Salt); err != nil {
+			return nil, opts, fmt.Errorf("salt must be hex: %w", err)
+		}
+		opts.Params.SaltLength = len(salt)
+	}
+	p := opts.Params.withDefaults(def)
+	if err := checkPasswordHashParams(opts.Algorithm, p); err != nil {
+		return nil, opts, err
+	}
+	if salt == nil {
+		if salt, err = RandomBytes(p.SaltLength); err != nil {
+			return nil, opts, err
+		}
+		opts.Salt = hex.EncodeToString(salt)
+	}
+
+	// Report only the parameters this algorithm uses.
+	var key []byte
+	switch opts.Algorithm {
+	case "argon2id", "argon2i":
+		key = argon2Key(opts.Algorithm, passphrase, salt, p)
+		opts.Params = PasswordHashParams{Memory: p.Memory, Time: p.Time, Threads: p.Threads}
+	case "scrypt":
+		key, err = scrypt.Key([]byte(passphrase), salt, 1<<p.LogN, p.BlockSize, p.Parallelism, p.KeyLength)
+		opts.Params = PasswordHashParams{LogN: p.LogN, BlockSize: p.BlockSize, Parallelism: p.Parallelism}
+	default:
+		key, err = pbkdf2.Key(pbkdf2Hash(opts.Algorithm), passphrase, salt, p.Iterations, p.KeyLength)
+		opts.Params = PasswordHashParams{Iterations: p.Iterations}
+	}
+	if err != nil {
+		return nil, opts, err
+	}
+	opts.Params.SaltLength, opts.Params.KeyLength = p.SaltLength, p.KeyLength
+	return key, opts, nil
+}
+
+// keyWrapIV and keyWrapPadIV are the RFC 3394 default IV and the RFC 5649
+// alternative IV prefix.
+var (
+	keyWrapIV    = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+	keyWrapPadIV = []byte{0xA6, 0x59, 0x59, 0xA6}
+)
+
+// AESKeyWrap wraps key under the AES key-encryption key kek. Without
+// padding this is RFC 3394, which needs a key of at least 16 bytes in
+// 8-byte multiples; with padding it is RFC 5649 and takes any length.
+func AESKeyWrap(kek, key []byte, padded bool) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key-encryption key: %w", err)
+	}
+	if !padded {
+		if len(key) < 16 || len(key)%8 != 0 {
+			return nil, fmt.Errorf("key must be at least 16 bytes and a multiple of 8 (use padding for other lengths)")
+		}
+		return keyWrap(block, keyWrapIV, key), nil
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+	iv := binary.BigEndian.AppendUint32(append([]byte{}, keyWrapPadIV...), uint32(len(key)))
+	p := append(append([]byte{}, key...), make([]byte, (8-len(key)%8)%8)...)
+	if len(p) == 8 {
+		out := make([]byte, 16)
+		block.Encrypt(out, append(iv, p...))
+		return out, nil
+	}
+	return keyWrap(block, iv, p), nil
+}
+
+// AESKeyUnwrap reverses AESKeyWrap, checking the integrity value.
+func AESKeyUnwrap(kek, wrapped []byte, padded bool) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key-encryption key: %w", err)
+	}
+	if len(wrapped) < 16 || len(wrapped)%8 != 0 {
+		return nil, fmt.Errorf("wrapped key must be at least 16 bytes and a multiple of 8")
+	}
+	fail := fmt.Errorf("integrity check failed (wrong key-encryption key or padding mode?)")
+
+	if !padded {
+		if len(wrapped) < 24 {
+			return nil, fail
+		}
+		iv, key := keyUnwrap(block, wrapped)
+		if subtle.ConstantTimeCompare(iv, keyWrapIV) != 1 {
+			return nil, fail
+		}
+		return key, nil
+	}
+
+	var iv, p []byte
+	if len(wrapped) == 16 {
+		b := make([]byte, 16)
+		block.Decrypt(b, wrapped)
+		iv, p = b[:8], b[8:]
+	} else {
+		iv, p = keyUnwrap(block, wrapped)
+	}
+	if subtle.ConstantTimeCompare(iv[:4], keyWrapPadIV) != 1 {
+		return nil, fail
+	}
+	n := int(binary.BigEndian.Uint32(iv[4:]))
+	if n <= len(p)-8 || n > len(p) {
+		return nil, fail
+	}
+	for _, b := range p[n:] {
+		if b != 0 {
+			return nil, fail
+		}
+	}
+	return p[:n], nil
+}
+
+// keyWrap is the RFC 3394 wrapping process W over the 8-byte blocks of p.
+func keyWrap(block cipher.Block, iv, p []byte) []byte {
+	n := len(p) / 8
+	out := make([]byte, 8+len(p))
+	copy(out, iv)
+	copy(out[8:], p)
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(b, out[:8])
+			copy(b[8:], out[i*8:i*8+8])
+			block.Encrypt(b, b)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(out[:8], binary.BigEndian.Uint64(b[:8])^t)
+			copy(out[i*8:], b[8:])
+		}
+	}
+	return out
+}
+
+// keyUnwrap is the RFC 3394 unwrapping process W⁻¹, returning the
+// recovered integrity value and key data.
+func keyUnwrap(block cipher.Block, c []byte) (iv, p []byte) {
+	n := len(c)/8 - 1
+	out := append([]byte{}, c...)
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:8], binary.BigEndian.Uint64(out[:8])^t)
+			copy(b[8:], out[i*8:i*8+8])
+			block.Decrypt(b, b)
+			copy(out[:8], b[:8])
+			copy(out[i*8:], b[8:])
+		}
+	}
+	return out[:8], out[8:]
+}
@@ -5,16 +5,22 @@ import (
 	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
 	"crypto/subtle"
 	"encoding/base32"
 	"encoding/binary"
 	"fmt"
 	"hash"
+	"math"
 	"math/big"
 	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"lukechampine.com/blake3"
 )
 
 // BcryptHash creates a bcrypt hash
@@ -201,7 +207,7 @@ func GenerateTOTP(secret string, digits int, period int64) (string, error) {
 	counter := uint64(time.Now().Unix() / period)
 
 	// Generate HOTP
-	return generateHOTP(key, counter, digits), nil
+	return generateHOTP(key, counter, digits, sha1.New), nil
 }
 
 // VerifyTOTP verifies a TOTP code
@@ -243,16 +249,18 @@ func generateTOTPAtOffset(secret string, digits int, period int64, offset int64)
 	}
 
 	counter := uint64((time.Now().Unix() / period) + offset)
-	return generateHOTP(key, counter, digits), nil
+	return generateHOTP(key, counter, digits, sha1.New), nil
 }
 
-func generateHOTP(key []byte, counter uint64, digits int) string {
+// generateHOTP computes the RFC 4226 code for counter. newHash is SHA-1
+// for standard HOTP/TOTP; RFC 6238 also allows SHA-256 and SHA-512.
+func generateHOTP(key []byte, counter uint64, digits int, newHash func() hash.Hash) string {
 	// Convert counter to bytes
 	buf := make([]byte, 8)
 	binary.BigEndian.PutUint64(buf, counter)
 
-	// Generate HMAC-SHA1
-	mac := hmac.New(sha1.New, key)
+	// Generate HMAC
+	mac := hmac.New(newHash, key)
 	mac.Write(buf)
 	hash := mac.Sum(nil)
 
@@ -272,23 +280,29 @@ func generateHOTP(key []byte, counter uint64, digits int) string {
 
 // GenerateTOTPURI generates an otpauth URI
 func GenerateTOTPURI(secret, issuer, account string) string {
-	return fmt.Sprintf("otpauth://totp/%s:%s?secret=%s&issuer=%s&algorithm=SHA1&digits=6&period=30",
-		issuer, account, secret, issuer)
+	return OTPAuthKey{
+		Type: "totp", Issuer: issuer, Account: account, Secret: secret,
+		Algorithm: "SHA1", Digits: 6, Period: 30,
+	}.URI()
 }
 
 // HMAC
 
-// HMACGenerate generates an HMAC
-func HMACGenerate(algorithm, key, message string) (string, error) {
-	var mac hash.Hash
+// HMACAlgorithms lists the algorithms HMACGenerate accepts. SHA-1, SHA-2
+// and SHA-3 are used in an HMAC; BLAKE2 and BLAKE3 have a keyed mode of
+// their own that is used instead. The non-cryptographic checksums that
+// text.Hash offers have no keyed variant.
+var HMACAlgorithms = []string{
+	"sha1", "sha256", "sha384", "sha512",
+	"sha3-224", "sha3-256", "sha3-384", "sha3-512",
+	"blake2b-256", "blake2b-512", "blake2s-256", "blake3",
+}
 
-	switch strings.ToLower(algorithm) {
-	case "sha1":
-		mac = hmac.New(sha1.New, []byte(key))
-	case "sha256":
-		mac = hmac.New(sha256.New, []byte(key))
-	default:
-		return "", fmt.Errorf("unsupported algorithm: %s", algorithm)
+// HMACGenerate generates an HMAC, or a keyed BLAKE2/BLAKE3 MAC, of message
+func HMACGenerate(algorithm, key, message string) (string, error) {
+	mac, err := newMAC(strings.ToLower(algorithm), []byte(key))
+	if err != nil {
+		return "", err
 	}
 
 	mac.Write([]byte(message))
@@ -296,8 +310,45 @@ func HMACGenerate(algorithm, key, message string) (string, error) {
 	return fmt.Sprintf("%x", mac.Sum(nil)), nil
 }
 
-// PasswordStrength analyzes password strength
-func PasswordStrength(password string) map[string]interface{} {
+// newMAC returns the keyed hash for algorithm. BLAKE2b takes keys of up
+// to 64 bytes and BLAKE2s up to 32; BLAKE3's keyed mode needs exactly 32.
+func newMAC(algorithm string, key []byte) (hash.Hash, error) {
+	switch algorithm {
+	case "sha1":
+		return hmac.New(sha1.New, key), nil
+	case "sha256":
+		return hmac.New(sha256.New, key), nil
+	case "sha384":
+		return hmac.New(sha512.New384, key), nil
+	case "sha512":
+		return hmac.New(sha512.New, key), nil
+	case "sha3-224":
+		return hmac.New(func() hash.Hash { return sha3.New224() }, key), nil
+	case "sha3-256":
+		return hmac.New(func() hash.Hash { return sha3.New256() }, key), nil
+	case "sha3-384":
+		return hmac.New(func() hash.Hash { return sha3.New384() }, key), nil
+	case "sha3-512":
+		return hmac.New(func() hash.Hash { return sha3.New512() }, key), nil
+	case "blake2b-256":
+		return blake2b.New256(key)
+	case "blake2b-512":
+		return blake2b.New512(key)
+	case "blake2s-256":
+		return blake2s.New256(key)
+	case "blake3":
+		if len(key) != 32 {
+			return nil, fmt.Errorf("blake3 keyed mode needs a 32-byte key, got %d bytes", len(key))
+		}
+		return blake3.New(32, key), nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+}
+
+// PasswordStrength analyzes password strength with EstimatePassword,
+// adding the password's length and character classes. entropy_bits is
+// log2 of the estimated guesses, not of the character set size.
+func PasswordStrength(password string, userInputs ...string) map[string]interface{} {
 	length := len(password)
 
 	hasUpper := strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
@@ -319,38 +370,22 @@ func PasswordStrength(password string) map[string]interface{} {
 		charsetSize += 32
 	}
 
-	entropy := float64(length) * (float64(charsetSize) / 4.0) // Simplified entropy calculation
-
-	var strength string
-	var score int
-
-	switch {
-	case entropy >= 100:
-		strength = "very_strong"
-		score = 5
-	case entropy >= 80:
-		strength = "strong"
-		score = 4
-	case entropy >= 60:
-		strength = "good"
-		score = 3
-	case entropy >= 40:
-		strength = "fair"
-		score = 2
-	default:
-		strength = "weak"
-		score = 1
-	}
+	est := EstimatePassword(password, userInputs...)
 
 	return map[string]interface{}{
-		"score":         score,
-		"strength":      strength,
+		"score":         est.Score,
+		"strength":      est.Strength,
 		"length":        length,
-		"entropy_bits":  entropy,
+		"entropy_bits":  math.Log2(est.Guesses),
 		"has_uppercase": hasUpper,
 		"has_lowercase": hasLower,
 		"has_numbers":   hasDigit,
 		"has_symbols":   hasSymbol,
 		"charset_size":  charsetSize,
+		"guesses":       est.Guesses,
+		"guesses_log10": est.GuessesLog10,
+		"crack_times":   est.CrackTimes,
+		"feedback":      est.Feedback,
+		"sequence":      est.Sequence,
 	}
 }
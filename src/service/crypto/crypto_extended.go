@@ -59,7 +59,25 @@ func GenerateRSAKeys(bits int) (string, string, error) {
 // GenerateECDSAKeys generates an ECDSA (P-256) keypair and returns the
 // PEM-encoded EC private key and PKIX public key
 func GenerateECDSAKeys() (string, string, error) {
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return GenerateECDSAKeysCurve("P-256")
+}
+
+// GenerateECDSAKeysCurve is GenerateECDSAKeys on the named curve: P-256,
+// P-384 or P-521.
+func GenerateECDSAKeysCurve(curveName string) (string, string, error) {
+	var curve elliptic.Curve
+	switch curveName {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return "", "", fmt.Errorf("unsupported curve: %s", curveName)
+	}
+
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate ECDSA key: %w", err)
 	}
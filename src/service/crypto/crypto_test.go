@@ -1,8 +1,18 @@
 package crypto
 
 import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"math"
+	"math/big"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBcryptHashVerify(t *testing.T) {
@@ -280,6 +290,46 @@ func TestHMACGenerate(t *testing.T) {
 	if _, err := HMACGenerate("md5", "key", "message"); err == nil {
 		t.Errorf("HMACGenerate(md5) should be unsupported and error")
 	}
+	if _, err := HMACGenerate("crc32", "key", "message"); err == nil {
+		t.Errorf("HMACGenerate(crc32) should be unsupported and error")
+	}
+}
+
+func TestHMACGenerateKeyedFamilies(t *testing.T) {
+	cases := []struct {
+		algo, key, message, want string
+	}{
+		{"sha3-256", "key", "The quick brown fox jumps over the lazy dog", "8c6e0683409427f8931711b10ca92a506eb1fafa48fadd66d76126f47ac2c333"},
+		{"blake2b-256", "key", "hello world", "70e4d5080ea1c43926e3bab251812dec5fa0d53687e9f7d59c6be6474913928a"},
+	}
+	for _, c := range cases {
+		got, err := HMACGenerate(c.algo, c.key, c.message)
+		if err != nil {
+			t.Fatalf("HMACGenerate(%s) error: %v", c.algo, err)
+		}
+		if got != c.want {
+			t.Errorf("HMACGenerate(%s) = %q, want %q", c.algo, got, c.want)
+		}
+	}
+
+	for _, algo := range HMACAlgorithms {
+		key := "key"
+		if algo == "blake3" {
+			key = strings.Repeat("k", 32)
+		}
+		if _, err := HMACGenerate(algo, key, "message"); err != nil {
+			t.Errorf("HMACGenerate(%s) error: %v", algo, err)
+		}
+	}
+	if _, err := HMACGenerate("blake3", "short", "message"); err == nil {
+		t.Errorf("HMACGenerate(blake3) should require a 32-byte key")
+	}
+	// Only the names HMACAlgorithms lists are accepted.
+	for _, algo := range []string{"blake2b", "blake2s"} {
+		if _, err := HMACGenerate(algo, "key", "message"); err == nil {
+			t.Errorf("HMACGenerate(%s) should name a digest size", algo)
+		}
+	}
 }
 
 func TestPasswordStrength(t *testing.T) {
@@ -436,3 +486,1219 @@ func TestRSAEncryptDecryptErrors(t *testing.T) {
 		t.Errorf("RSADecrypt() with mismatched private key should error")
 	}
 }
+
+func TestJWTSignVerifyRoundTrip(t *testing.T) {
+	claims := map[string]interface{}{"sub": "alice", "iss": "apimgr", "aud": []string{"api", "web"}}
+	for _, alg := range JWTAlgorithms {
+		priv, pub, err := JWTGenerateKey(alg)
+		if err != nil {
+			t.Fatalf("JWTGenerateKey(%s) error: %v", alg, err)
+		}
+		if pub == "" {
+			pub = priv
+		}
+		token, err := JWTSign(alg, claims, map[string]interface{}{"kid": "k1", "alg": "none"}, priv)
+		if err != nil {
+			t.Fatalf("JWTSign(%s) error: %v", alg, err)
+		}
+		res, err := JWTVerify(token, pub, JWTVerifyOptions{Audience: "web", Issuer: "apimgr"})
+		if err != nil {
+			t.Fatalf("JWTVerify(%s) error: %v", alg, err)
+		}
+		if !res.Valid || res.Algorithm != alg || res.Header["kid"] != "k1" {
+			t.Errorf("JWTVerify(%s) = %+v, want valid with kid k1", alg, res)
+		}
+
+		tampered := token[:len(token)-4] + "AAAA"
+		if res, err := JWTVerify(tampered, pub, JWTVerifyOptions{}); err == nil && res.SignatureValid {
+			t.Errorf("JWTVerify(%s) accepted a tampered signature", alg)
+		}
+	}
+}
+
+func TestJWTVerifyRFC7515Example(t *testing.T) {
+	token := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	key := `{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`
+
+	res, err := JWTVerify(token, key, JWTVerifyOptions{Issuer: "joe", Now: time.Unix(1300819000, 0)})
+	if err != nil {
+		t.Fatalf("JWTVerify error: %v", err)
+	}
+	if !res.Valid {
+		t.Errorf("JWTVerify() errors = %v, want valid", res.Errors)
+	}
+
+	res, _ = JWTVerify(token, key, JWTVerifyOptions{Now: time.Unix(1300819400, 0)})
+	if res.Valid || !res.SignatureValid {
+		t.Errorf("JWTVerify() after exp = %+v, want good signature but invalid", res)
+	}
+	res, _ = JWTVerify(token, key, JWTVerifyOptions{Now: time.Unix(1300819400, 0), ClockSkew: time.Minute})
+	if !res.Valid {
+		t.Errorf("JWTVerify() within clock skew errors = %v, want valid", res.Errors)
+	}
+	res, _ = JWTVerify(token, key, JWTVerifyOptions{Audience: "api", Issuer: "bob", Now: time.Unix(1300819000, 0)})
+	if res.Valid || len(res.Errors) != 2 {
+		t.Errorf("JWTVerify() aud/iss errors = %v, want 2", res.Errors)
+	}
+}
+
+func TestJWTTimeClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	claims := map[string]interface{}{"nbf": now.Add(time.Hour).Unix(), "iat": now.Add(time.Hour).Unix()}
+	token, err := JWTSign("HS256", claims, nil, "secret")
+	if err != nil {
+		t.Fatalf("JWTSign error: %v", err)
+	}
+	res, _ := JWTVerify(token, "secret", JWTVerifyOptions{Now: now})
+	if res.Valid || len(res.Errors) != 2 {
+		t.Errorf("JWTVerify() errors = %v, want nbf and iat failures", res.Errors)
+	}
+	res, _ = JWTVerify(token, "secret", JWTVerifyOptions{Now: now, ClockSkew: 2 * time.Hour})
+	if !res.Valid {
+		t.Errorf("JWTVerify() with skew errors = %v, want valid", res.Errors)
+	}
+}
+
+func TestJWTRejectsUnsafeInput(t *testing.T) {
+	_, rsaPub, err := GenerateRSAKeys(2048)
+	if err != nil {
+		t.Fatalf("GenerateRSAKeys error: %v", err)
+	}
+
+	// An RS256 public key must never be usable as an HS256 secret.
+	if _, err := JWTSign("HS256", nil, nil, rsaPub); err == nil {
+		t.Errorf("JWTSign(HS256) with a PEM key should fail")
+	}
+	forged, _ := JWTSign("HS256", map[string]interface{}{"sub": "x"}, nil, "k")
+	if _, err := JWTVerify(forged, rsaPub, JWTVerifyOptions{}); err == nil {
+		t.Errorf("JWTVerify(HS256) with a PEM key should fail")
+	}
+
+	unsigned := "eyJhbGciOiJub25lIn0.eyJzdWIiOiJ4In0."
+	if _, err := JWTVerify(unsigned, "k", JWTVerifyOptions{}); err == nil {
+		t.Errorf("JWTVerify() should reject alg none")
+	}
+	if _, err := JWTSign("RS256", nil, nil, rsaPub); err == nil {
+		t.Errorf("JWTSign() with a public key should fail")
+	}
+	ecPriv, _, _ := GenerateECDSAKeys()
+	if _, err := JWTSign("ES384", nil, nil, ecPriv); err == nil {
+		t.Errorf("JWTSign(ES384) with a P-256 key should fail")
+	}
+
+	res, err := JWTVerify(forged, "k", JWTVerifyOptions{Algorithms: []string{"RS256"}})
+	if err != nil || res.SignatureValid {
+		t.Errorf("JWTVerify() with disallowed algorithm = %+v, %v", res, err)
+	}
+}
+
+func TestParseKeyJWK(t *testing.T) {
+	keys := []string{
+		`{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`,
+		`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+	}
+	for _, k := range keys {
+		if _, err := ParseKey(k); err != nil {
+			t.Errorf("ParseKey(%s) error: %v", k, err)
+		}
+	}
+	bad := []string{"not a key", `{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}`, `{"kty":"XYZ"}`}
+	for _, k := range bad {
+		if _, err := ParseKey(k); err == nil {
+			t.Errorf("ParseKey(%s) should fail", k)
+		}
+	}
+}
+
+func TestJWKThumbprintRFC8037(t *testing.T) {
+	key, err := ParseKey(`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`)
+	if err != nil {
+		t.Fatalf("ParseKey error: %v", err)
+	}
+	got, err := JWKThumbprint(key)
+	if err != nil {
+		t.Fatalf("JWKThumbprint error: %v", err)
+	}
+	if want := "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"; got != want {
+		t.Errorf("JWKThumbprint() = %q, want %q", got, want)
+	}
+}
+
+func TestConvertKeyRoundTrip(t *testing.T) {
+	rsaPriv, _, _ := GenerateRSAKeys(2048)
+	ecPriv, _, _ := GenerateECDSAKeysCurve("P-384")
+	edPriv, _, _ := GenerateEd25519Keys()
+
+	for _, priv := range []string{rsaPriv, ecPriv, edPriv} {
+		want, err := InspectKey(priv)
+		if err != nil {
+			t.Fatalf("InspectKey error: %v", err)
+		}
+		if !want.Private {
+			t.Errorf("InspectKey() private = false for a private key")
+		}
+		for _, format := range KeyFormats {
+			for _, publicOnly := range []bool{false, true} {
+				out, err := ConvertKey(priv, format, publicOnly)
+				if err != nil {
+					// pkcs1/sec1 are type-specific and pkcs8 is private-only.
+					continue
+				}
+				got, err := InspectKey(out)
+				if err != nil {
+					t.Errorf("%s key as %s (public %v) does not parse back: %v", want.Type, format, publicOnly, err)
+					continue
+				}
+				wantPrivate := !publicOnly && format != "spki"
+				if got.Fingerprints != want.Fingerprints || got.Private != wantPrivate {
+					t.Errorf("%s key as %s (public %v) = %+v, want fingerprints %+v", want.Type, format, publicOnly, got, want.Fingerprints)
+				}
+			}
+		}
+	}
+
+	if _, err := ConvertKey(edPriv, "pkcs1", false); err == nil {
+		t.Errorf("ConvertKey(Ed25519, pkcs1) should fail")
+	}
+	if _, err := ConvertKey(rsaPriv, "pkcs8", true); err == nil {
+		t.Errorf("ConvertKey(public, pkcs8) should fail")
+	}
+}
+
+func TestInspectKeyDetails(t *testing.T) {
+	_, ecPub, _ := GenerateECDSAKeys()
+	info, err := InspectKey(ecPub)
+	if err != nil {
+		t.Fatalf("InspectKey error: %v", err)
+	}
+	if info.Type != "EC" || info.Curve != "P-256" || info.Bits != 256 || info.Private {
+		t.Errorf("InspectKey() = %+v, want public EC P-256", info)
+	}
+	if !strings.HasPrefix(info.Fingerprints.SSHSHA256, "SHA256:") || strings.Count(info.Fingerprints.SSHMD5, ":") != 15 {
+		t.Errorf("InspectKey() fingerprints = %+v", info.Fingerprints)
+	}
+
+	info, err = InspectKey(`{"kty":"oct","k":"c2VjcmV0"}`)
+	if err != nil || info.Type != "oct" || info.Bits != 48 || info.Fingerprints.SSHSHA256 != "" {
+		t.Errorf("InspectKey(oct) = %+v, %v", info, err)
+	}
+}
+
+func TestBuildJWKS(t *testing.T) {
+	_, rsaPub, _ := GenerateRSAKeys(2048)
+	edPriv, _, _ := GenerateEd25519Keys()
+
+	set, err := BuildJWKS([]string{rsaPub, edPriv}, "sig")
+	if err != nil {
+		t.Fatalf("BuildJWKS error: %v", err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("BuildJWKS() has %d keys, want 2", len(set.Keys))
+	}
+	ed := set.Keys[1]
+	if ed.D != "" || ed.Alg != "EdDSA" || ed.Use != "sig" || ed.Kid == "" {
+		t.Errorf("BuildJWKS() Ed25519 entry = %+v, want public key with alg, use and kid", ed)
+	}
+	if set.Keys[0].Alg != "" {
+		t.Errorf("BuildJWKS() RSA alg = %q, want none", set.Keys[0].Alg)
+	}
+
+	enc, err := BuildJWKS([]string{edPriv}, "enc")
+	if err != nil || enc.Keys[0].Alg != "" || enc.Keys[0].Use != "enc" {
+		t.Errorf("BuildJWKS(enc) = %+v, %v; want no alg on an encryption key", enc, err)
+	}
+
+	if _, err := BuildJWKS([]string{rsaPub, rsaPub}, "sig"); err == nil {
+		t.Errorf("BuildJWKS() should reject duplicate keys")
+	}
+	if _, err := BuildJWKS([]string{`{"kty":"oct","k":"c2VjcmV0"}`}, "sig"); err == nil {
+		t.Errorf("BuildJWKS() should reject symmetric keys")
+	}
+}
+
+func TestPasswordVerifyKnownHashes(t *testing.T) {
+	tests := []struct {
+		password, hash, algorithm string
+	}{
+		{"Hello world!", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", "sha256-crypt"},
+		{"Hello world!", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", "sha512-crypt"},
+		{"Hello world!", "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA", "sha256-crypt"},
+		{"Hello world!", "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.", "sha512-crypt"},
+		{"password", "$apr1$abcdefgh$FBwExRW4dCc8aL.OvjpIE1", "apr1"},
+		{"password", "$1$abcdefgh$G//4keteveJp0qb8z2DxG/", "md5-crypt"},
+		{"password", "$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG", "argon2i"},
+		{"password", "$pbkdf2-sha256$1000$c2FsdHNhbHQ$E196ZhRPzw.wA84EjzHwJO1cv/MFJdO6C/sxmUeTYqY", "pbkdf2-sha256"},
+		{"password", "$pbkdf2-sha512$1000$c2FsdHNhbHQ$Q6v4xwJ8a9nWPp2BeEoAYYhHSo2xRmPWART17vTpSxt2q6iNp7BOozW557qqa95eNjUO4gKs0CyvJbYGGku1tA", "pbkdf2-sha512"},
+		{"password", "pbkdf2_sha256$1000$saltsalt$E196ZhRPzw+wA84EjzHwJO1cv/MFJdO6C/sxmUeTYqY=", "pbkdf2_sha256"},
+		{"password", "$scrypt$ln=10,r=8,p=1$c2FsdHNhbHQ$AOLXEESCcPmf2DxU3D47ZJxp5ZTcHC0S2Mb2eFXc4tI", "scrypt"},
+	}
+	for _, tt := range tests {
+		info, valid, err := PasswordVerify(tt.password, tt.hash)
+		if err != nil {
+			t.Errorf("PasswordVerify(%s) error: %v", tt.algorithm, err)
+			continue
+		}
+		if !valid || info.Algorithm != tt.algorithm {
+			t.Errorf("PasswordVerify(%s) = %v, %v; want valid", tt.algorithm, info.Algorithm, valid)
+		}
+		if _, valid, _ := PasswordVerify("wrong", tt.hash); valid {
+			t.Errorf("PasswordVerify(%s) accepted the wrong password", tt.algorithm)
+		}
+		if _, valid, err := PasswordVerify(tt.password, " "+tt.hash+"\n"); err != nil || !valid {
+			t.Errorf("PasswordVerify(%s) = %v, %v with surrounding whitespace", tt.algorithm, valid, err)
+		}
+	}
+}
+
+func TestPasswordHashRoundTrip(t *testing.T) {
+	cheap := PasswordHashParams{Memory: 1024, Time: 1, Threads: 1, LogN: 10, Iterations: 1000, Cost: 4}
+	for _, alg := range PasswordHashAlgorithms {
+		hash, err := PasswordHash(alg, "correct horse", cheap)
+		if err != nil {
+			t.Fatalf("PasswordHash(%s) error: %v", alg, err)
+		}
+		info, valid, err := PasswordVerify("correct horse", hash)
+		if err != nil || !valid || info.Algorithm != alg {
+			t.Errorf("PasswordVerify(%s) = %+v, %v, %v for %q", alg, info, valid, err, hash)
+		}
+	}
+
+	hash, _ := PasswordHash("argon2id", "pw", PasswordHashParams{Memory: 2048, Time: 2, Threads: 1})
+	info, err := IdentifyPasswordHash(hash)
+	if err != nil || info.Params.Memory != 2048 || info.Params.Time != 2 || info.Params.KeyLength != 32 {
+		t.Errorf("IdentifyPasswordHash() = %+v, %v", info, err)
+	}
+}
+
+func TestPasswordHashLimits(t *testing.T) {
+	if _, err := PasswordHash("argon2id", "pw", PasswordHashParams{Memory: 1 << 30}); err == nil {
+		t.Errorf("PasswordHash() should reject excessive argon2 memory")
+	}
+	if _, err := PasswordHash("argon2id", "pw", PasswordHashParams{Memory: 64*1024 + 8, Time: 1, Threads: 1}); err == nil {
+		t.Errorf("PasswordHash() should reject argon2 memory over 64 MiB")
+	}
+	if _, err := PasswordHash("scrypt", "pw", PasswordHashParams{LogN: 17, BlockSize: 8, Parallelism: 1}); err == nil {
+		t.Errorf("PasswordHash() should reject scrypt memory over 64 MiB")
+	}
+	if _, err := PasswordHash("whirlpool", "pw", PasswordHashParams{}); err == nil {
+		t.Errorf("PasswordHash() should reject unknown algorithms")
+	}
+	long := strings.Repeat("a", maxPasswordLen+1)
+	if _, err := PasswordHash("sha512-crypt", long, PasswordHashParams{}); err == nil {
+		t.Errorf("PasswordHash() should reject an over-long password")
+	}
+	if _, _, err := PasswordVerify(long, "$6$salt$x"); err == nil {
+		t.Errorf("PasswordVerify() should reject an over-long password")
+	}
+	bad := []string{
+		"$argon2id$v=19$m=4194304,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"$scrypt$ln=30,r=8,p=1$c2FsdHNhbHQ$AOLXEESCcPmf2DxU3D47ZJxp5ZTcHC0S2Mb2eFXc4tI",
+		"$pbkdf2-sha256$999999999$c2FsdHNhbHQ$E196ZhRPzw.wA84EjzHwJO1cv/MFJdO6C/sxmUeTYqY",
+		"$argon2id$v=16$m=65536,t=2,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"plaintext",
+	}
+	for _, hash := range bad {
+		if _, _, err := PasswordVerify("pw", hash); err == nil {
+			t.Errorf("PasswordVerify(%q) should fail", hash)
+		}
+	}
+}
+
+func TestCertificateAuthorityChain(t *testing.T) {
+	rootPEM, rootKey, err := GenerateCertificate("Test Root", 30)
+	if err != nil {
+		t.Fatalf("GenerateCertificate error: %v", err)
+	}
+
+	interCSR, interKey, err := GenerateCSR(CSROptions{Subject: CertificateSubject{CommonName: "Test Intermediate", Organization: "Example"}})
+	if err != nil {
+		t.Fatalf("GenerateCSR (intermediate) error: %v", err)
+	}
+	pathLen := 0
+	interPEM, err := SignCSR(interCSR, rootPEM, rootKey, SignCSROptions{IsCA: true, PathLen: &pathLen})
+	if err != nil {
+		t.Fatalf("SignCSR (intermediate) error: %v", err)
+	}
+
+	leafCSR, leafKey, err := GenerateCSR(CSROptions{
+		Subject:     CertificateSubject{CommonName: "www.example.com"},
+		DNSNames:    []string{"www.example.com", "example.com"},
+		IPAddresses: []string{"192.0.2.1"},
+		KeyUsage:    []string{"digital_signature"},
+		ExtKeyUsage: []string{"server_auth"},
+		KeyType:     "ed25519",
+	})
+	if err != nil {
+		t.Fatalf("GenerateCSR (leaf) error: %v", err)
+	}
+	csr, err := ParseCSR(leafCSR)
+	if err != nil {
+		t.Fatalf("ParseCSR error: %v", err)
+	}
+	if !csr.SignatureValid || csr.PublicKey.Type != "Ed25519" {
+		t.Errorf("ParseCSR() = %+v, want a valid Ed25519 request", csr)
+	}
+	if strings.Join(csr.KeyUsage, ",") != "digital_signature" || strings.Join(csr.ExtKeyUsage, ",") != "server_auth" {
+		t.Errorf("ParseCSR() usages = %v / %v", csr.KeyUsage, csr.ExtKeyUsage)
+	}
+	if strings.Join(csr.IPAddresses, ",") != "192.0.2.1" {
+		t.Errorf("ParseCSR() ip_addresses = %v", csr.IPAddresses)
+	}
+
+	leafPEM, err := SignCSR(leafCSR, interPEM, interKey, SignCSROptions{ValidDays: 7})
+	if err != nil {
+		t.Fatalf("SignCSR (leaf) error: %v", err)
+	}
+	leaf, err := ParseCertificate(leafPEM)
+	if err != nil {
+		t.Fatalf("ParseCertificate error: %v", err)
+	}
+	if leaf["is_ca"] != false || strings.Join(leaf["ext_key_usage"].([]string), ",") != "server_auth" {
+		t.Errorf("leaf = %v, want a server_auth end-entity certificate", leaf)
+	}
+	if leaf["authority_key_id"] == "" {
+		t.Errorf("leaf has no authority key identifier")
+	}
+
+	if _, err := SignCSR(leafCSR, leafPEM, leafKey, SignCSROptions{}); err == nil {
+		t.Errorf("SignCSR() with a non-CA issuer should fail")
+	}
+	if _, err := SignCSR(leafCSR, interPEM, rootKey, SignCSROptions{}); err == nil {
+		t.Errorf("SignCSR() with a mismatched CA key should fail")
+	}
+
+	// An expired CA must not issue a certificate that ends before it starts.
+	roots, _ := parseCertificateBundle(rootPEM)
+	signer, _ := ParseKey(rootKey)
+	expired := *roots[0]
+	expired.NotBefore, expired.NotAfter = time.Now().AddDate(0, 0, -30), time.Now().AddDate(0, 0, -1)
+	der, err := x509.CreateCertificate(rand.Reader, &expired, &expired, roots[0].PublicKey, signer)
+	if err != nil {
+		t.Fatalf("CreateCertificate error: %v", err)
+	}
+	expiredPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	if _, err := SignCSR(leafCSR, expiredPEM, rootKey, SignCSROptions{}); err == nil {
+		t.Errorf("SignCSR() with an expired CA should fail")
+	}
+
+	result, err := VerifyCertificateChain([]string{leafPEM, interPEM}, ChainOptions{Roots: rootPEM, DNSName: "example.com"})
+	if err != nil {
+		t.Fatalf("VerifyCertificateChain error: %v", err)
+	}
+	if !result.Valid || len(result.Chains) != 1 || len(result.Chains[0]) != 3 {
+		t.Fatalf("VerifyCertificateChain() = %+v, want one three-certificate chain", result)
+	}
+	if result.Chains[0][2].Subject != "CN=Test Root" {
+		t.Errorf("chain root = %q", result.Chains[0][2].Subject)
+	}
+
+	for name, opts := range map[string]ChainOptions{
+		"wrong name":  {Roots: rootPEM, DNSName: "other.example"},
+		"wrong usage": {Roots: rootPEM, ExtKeyUsage: []string{"code_signing"}},
+		"expired":     {Roots: rootPEM, At: time.Now().AddDate(0, 0, 10)},
+	} {
+		result, err := VerifyCertificateChain([]string{leafPEM, interPEM}, opts)
+		if err != nil || result.Valid || result.Error == "" {
+			t.Errorf("%s: VerifyCertificateChain() = %+v, %v, want invalid", name, result, err)
+		}
+	}
+	if result, err := VerifyCertificateChain([]string{leafPEM}, ChainOptions{Roots: rootPEM}); err != nil || result.Valid {
+		t.Errorf("VerifyCertificateChain() without the intermediate = %+v, %v, want invalid", result, err)
+	}
+	if result, err := VerifyCertificateChain([]string{leafPEM}, ChainOptions{Roots: interPEM}); err != nil || !result.Valid {
+		t.Errorf("VerifyCertificateChain() trusting the intermediate = %+v, %v, want valid", result, err)
+	}
+
+	bundle, err := DecodeCertificates(leafPEM + interPEM)
+	if err != nil || len(bundle) != 2 || bundle[1]["max_path_len"] != 0 {
+		t.Errorf("DecodeCertificates() = %v, %v", bundle, err)
+	}
+}
+
+func TestPKCS12RoundTrip(t *testing.T) {
+	certPEM, keyPEM, err := GenerateCertificate("pkcs12.example", 1)
+	if err != nil {
+		t.Fatalf("GenerateCertificate error: %v", err)
+	}
+	for _, legacy := range []bool{false, true} {
+		pfx, err := PKCS12Encode(keyPEM, certPEM, "changeit", legacy)
+		if err != nil {
+			t.Fatalf("PKCS12Encode(legacy=%v) error: %v", legacy, err)
+		}
+		contents, err := PKCS12Decode(pfx, "changeit")
+		if err != nil {
+			t.Fatalf("PKCS12Decode(legacy=%v) error: %v", legacy, err)
+		}
+		if contents.Certificate != certPEM {
+			t.Errorf("PKCS12Decode(legacy=%v) certificate does not round-trip", legacy)
+		}
+		want, _ := ConvertKey(keyPEM, "pkcs8", false)
+		if contents.PrivateKey != want {
+			t.Errorf("PKCS12Decode(legacy=%v) key does not round-trip", legacy)
+		}
+		if _, err := PKCS12Decode(pfx, "wrong"); err == nil {
+			t.Errorf("PKCS12Decode(legacy=%v) with the wrong password should fail", legacy)
+		}
+	}
+
+	otherCert, _, err := GenerateCertificate("other.example", 1)
+	if err != nil {
+		t.Fatalf("GenerateCertificate error: %v", err)
+	}
+	if _, err := PKCS12Encode(keyPEM, otherCert, "pw", false); err == nil {
+		t.Errorf("PKCS12Encode() with a mismatched certificate should fail")
+	}
+}
+
+func TestParseCRL(t *testing.T) {
+	rootPEM, rootKey, err := GenerateCertificate("Test Root", 30)
+	if err != nil {
+		t.Fatalf("GenerateCertificate error: %v", err)
+	}
+	csrPEM, caKeyPEM, err := GenerateCSR(CSROptions{Subject: CertificateSubject{CommonName: "CRL Issuer"}})
+	if err != nil {
+		t.Fatalf("GenerateCSR error: %v", err)
+	}
+	caPEM, err := SignCSR(csrPEM, rootPEM, rootKey, SignCSROptions{IsCA: true})
+	if err != nil {
+		t.Fatalf("SignCSR error: %v", err)
+	}
+	caCerts, err := parseCertificateBundle(caPEM)
+	if err != nil {
+		t.Fatalf("parseCertificateBundle error: %v", err)
+	}
+	caKey, err := ParseKey(caKeyPEM)
+	if err != nil {
+		t.Fatalf("ParseKey error: %v", err)
+	}
+	revokedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(42),
+		ThisUpdate: revokedAt,
+		NextUpdate: revokedAt.AddDate(0, 0, 7),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: big.NewInt(1001), RevocationTime: revokedAt, ReasonCode: 1},
+			{SerialNumber: big.NewInt(1002), RevocationTime: revokedAt},
+		},
+	}, caCerts[0], caKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("CreateRevocationList error: %v", err)
+	}
+	crlPEM := string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+
+	info, err := ParseCRL(crlPEM, caPEM)
+	if err != nil {
+		t.Fatalf("ParseCRL error: %v", err)
+	}
+	if info.Number != "42" || info.RevokedCount != 2 || info.NextUpdate != "2024-05-08T12:00:00Z" {
+		t.Errorf("ParseCRL() = %+v", info)
+	}
+	if info.Revoked[0].SerialNumber != "1001" || info.Revoked[0].Reason != "key_compromise" {
+		t.Errorf("ParseCRL() first entry = %+v", info.Revoked[0])
+	}
+	if info.SignatureValid == nil || !*info.SignatureValid {
+		t.Errorf("ParseCRL() signature should verify against its issuer")
+	}
+
+	info, err = ParseCRL(base64.StdEncoding.EncodeToString(der), "")
+	if err != nil || info.SignatureValid != nil {
+		t.Errorf("ParseCRL(base64 DER) = %+v, %v, want no signature check", info, err)
+	}
+	if info, err := ParseCRL(crlPEM, rootPEM); err != nil || *info.SignatureValid {
+		t.Errorf("ParseCRL() against the wrong issuer = %+v, %v, want signature_valid=false", info, err)
+	}
+}
+
+func TestPGPSignVerify(t *testing.T) {
+	pub, priv, err := GeneratePGPKeysWithPassphrase("Release Bot", "release@example.com", "hunter2")
+	if err != nil {
+		t.Fatalf("GeneratePGPKeysWithPassphrase error: %v", err)
+	}
+	otherPub, _, err := GeneratePGPKeys("Someone Else", "else@example.com")
+	if err != nil {
+		t.Fatalf("GeneratePGPKeys error: %v", err)
+	}
+	artifact := []byte{0x00, 0xff, 'b', 'i', 'n', '\n'}
+
+	if _, err := PGPSign(artifact, priv, ""); err == nil {
+		t.Errorf("PGPSign() without the passphrase should fail")
+	}
+	if _, err := PGPSign(artifact, priv, "wrong"); err == nil {
+		t.Errorf("PGPSign() with the wrong passphrase should fail")
+	}
+	sig, err := PGPSign(artifact, priv, "hunter2")
+	if err != nil {
+		t.Fatalf("PGPSign error: %v", err)
+	}
+
+	result, err := PGPVerify(artifact, sig, []string{otherPub, pub})
+	if err != nil {
+		t.Fatalf("PGPVerify error: %v", err)
+	}
+	if !result.Valid || result.Signer != "Release Bot <release@example.com>" || len(result.KeyID) != 16 || result.Fingerprint == "" {
+		t.Errorf("PGPVerify() = %+v, want a valid signature by Release Bot", result)
+	}
+	if created, err := time.Parse(time.RFC3339, result.CreatedAt); err != nil || time.Since(created) > time.Minute {
+		t.Errorf("PGPVerify() created_at = %q", result.CreatedAt)
+	}
+
+	if result, err := PGPVerify(append(artifact, '!'), sig, []string{pub}); err != nil || result.Valid {
+		t.Errorf("PGPVerify() of tampered data = %+v, %v, want invalid", result, err)
+	}
+	result, err = PGPVerify(artifact, sig, []string{otherPub})
+	if err != nil || result.Valid || !strings.Contains(result.Error, result.KeyID) {
+		t.Errorf("PGPVerify() with an unrelated key = %+v, %v, want invalid naming the key ID", result, err)
+	}
+
+	signed, err := PGPClearsign("ship it\n", priv, "hunter2")
+	if err != nil {
+		t.Fatalf("PGPClearsign error: %v", err)
+	}
+	if !strings.HasPrefix(signed, "-----BEGIN PGP SIGNED MESSAGE-----") {
+		t.Errorf("PGPClearsign() = %q", signed)
+	}
+	result, err = PGPVerify([]byte(signed), "", []string{pub})
+	if err != nil || !result.Valid || result.Message != "ship it\n" {
+		t.Errorf("PGPVerify(clearsigned) = %+v, %v", result, err)
+	}
+	tampered := strings.Replace(signed, "ship it", "ship that", 1)
+	if result, err := PGPVerify([]byte(tampered), "", []string{pub}); err != nil || result.Valid {
+		t.Errorf("PGPVerify(tampered clearsigned) = %+v, %v, want invalid", result, err)
+	}
+	if _, err := PGPVerify([]byte("plain text"), "", []string{pub}); err == nil {
+		t.Errorf("PGPVerify() of an unsigned message without a signature should fail")
+	}
+}
+
+func TestPGPEncryptToMultipleRecipients(t *testing.T) {
+	pubA, privA, err := GeneratePGPKeysWithPassphrase("Alice", "alice@example.com", "a-pass")
+	if err != nil {
+		t.Fatalf("GeneratePGPKeysWithPassphrase error: %v", err)
+	}
+	pubB, privB, err := GeneratePGPKeys("Bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("GeneratePGPKeys error: %v", err)
+	}
+
+	ciphertext, err := PGPEncryptTo("for both of you", []string{pubA, pubB})
+	if err != nil {
+		t.Fatalf("PGPEncryptTo error: %v", err)
+	}
+	if got, err := PGPDecryptWithPassphrase(ciphertext, privA, "a-pass"); err != nil || got != "for both of you" {
+		t.Errorf("Alice decrypt = %q, %v", got, err)
+	}
+	if got, err := PGPDecrypt(ciphertext, privB); err != nil || got != "for both of you" {
+		t.Errorf("Bob decrypt = %q, %v", got, err)
+	}
+	if _, err := PGPDecrypt(ciphertext, privA); err == nil {
+		t.Errorf("PGPDecrypt() with a protected key and no passphrase should fail")
+	}
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	// Trezor reference vectors (github.com/trezor/python-mnemonic), all
+	// with passphrase "TREZOR".
+	tests := []struct {
+		entropy, mnemonic, seed, xprv string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			"xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+			"xprv9s21ZrQH143K2WFF16X85T2QCpndrGwx6GueB72Zf3AHwHJaknRXNF37ZmDrtHrrLSHvbuRejXcnYxoZKvRquTPyp2JiNG3XcjQyzSEgqCB",
+		},
+	}
+	for _, tc := range tests {
+		entropy, _ := hex.DecodeString(tc.entropy)
+		mnemonic, err := MnemonicFromEntropy(entropy, "english")
+		if err != nil || mnemonic != tc.mnemonic {
+			t.Errorf("MnemonicFromEntropy(%s) = %q, %v", tc.entropy, mnemonic, err)
+		}
+
+		info := ValidateMnemonic(strings.ToUpper(tc.mnemonic), "")
+		if !info.Valid || info.Language != "english" || info.Entropy != tc.entropy {
+			t.Errorf("ValidateMnemonic() = %+v", info)
+		}
+
+		seed, err := MnemonicToSeed(tc.mnemonic, "TREZOR", "")
+		if err != nil || hex.EncodeToString(seed) != tc.seed {
+			t.Errorf("MnemonicToSeed() = %x, %v", seed, err)
+		}
+		master, err := NewMasterKey(seed, "")
+		if err != nil || master.String() != tc.xprv {
+			t.Errorf("NewMasterKey() = %v, %v", master, err)
+		}
+	}
+
+	bad := ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "english")
+	if bad.Valid || bad.Error != "invalid checksum" {
+		t.Errorf("ValidateMnemonic() with a bad checksum = %+v", bad)
+	}
+	if info := ValidateMnemonic("abandon abandon notaword", ""); info.Valid {
+		t.Errorf("ValidateMnemonic() with an unknown word should be invalid")
+	}
+	if _, err := MnemonicToSeed("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo", "", ""); err == nil {
+		t.Errorf("MnemonicToSeed() of an invalid mnemonic should fail")
+	}
+}
+
+func TestGenerateMnemonicLanguages(t *testing.T) {
+	for _, lang := range MnemonicLanguages {
+		for _, words := range []int{12, 24} {
+			mnemonic, err := GenerateMnemonic(words, lang)
+			if err != nil {
+				t.Fatalf("GenerateMnemonic(%d, %s) error: %v", words, lang, err)
+			}
+			info := ValidateMnemonic(mnemonic, lang)
+			if !info.Valid || info.Words != words {
+				t.Errorf("%s mnemonic %q did not validate: %+v", lang, mnemonic, info)
+			}
+		}
+	}
+	if _, err := GenerateMnemonic(13, ""); err == nil {
+		t.Errorf("GenerateMnemonic(13) should fail")
+	}
+	if _, err := GenerateMnemonic(12, "klingon"); err == nil {
+		t.Errorf("GenerateMnemonic() with an unknown language should fail")
+	}
+}
+
+func TestBIP32Derivation(t *testing.T) {
+	// BIP32 test vector 1.
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed, "mainnet")
+	if err != nil {
+		t.Fatalf("NewMasterKey error: %v", err)
+	}
+	if got := master.Neuter().String(); got != "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8" {
+		t.Errorf("master xpub = %s", got)
+	}
+
+	info, err := DeriveKeyInfo(master, "m/0'/1/2h/2/1000000000")
+	if err != nil {
+		t.Fatalf("DeriveKeyInfo error: %v", err)
+	}
+	if info.ExtendedPrivateKey != "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76" {
+		t.Errorf("xprv = %s", info.ExtendedPrivateKey)
+	}
+	if info.ExtendedPublicKey != "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy" {
+		t.Errorf("xpub = %s", info.ExtendedPublicKey)
+	}
+	if info.Depth != 5 || info.ChildNumber != "1000000000" {
+		t.Errorf("depth/child = %d/%s", info.Depth, info.ChildNumber)
+	}
+
+	// Public derivation from an xpub matches private derivation.
+	hardened, err := master.Derive("m/0'")
+	if err != nil {
+		t.Fatalf("Derive error: %v", err)
+	}
+	xpub, err := ParseExtendedKey(hardened.Neuter().String())
+	if err != nil {
+		t.Fatalf("ParseExtendedKey error: %v", err)
+	}
+	pubInfo, err := DeriveKeyInfo(xpub, "m/1")
+	if err != nil {
+		t.Fatalf("public DeriveKeyInfo error: %v", err)
+	}
+	if pubInfo.ExtendedPublicKey != "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ" || pubInfo.PrivateKey != "" {
+		t.Errorf("public derivation = %+v", pubInfo)
+	}
+	if _, err := xpub.Derive("m/0'"); err == nil {
+		t.Errorf("hardened derivation from an xpub should fail")
+	}
+
+	for _, path := range []string{"", "0/1", "m/x", "m/2147483648", "m//1"} {
+		if _, err := ParseDerivationPath(path); err == nil {
+			t.Errorf("ParseDerivationPath(%q) should fail", path)
+		}
+	}
+	if _, err := ParseExtendedKey(info.ExtendedPrivateKey[:len(info.ExtendedPrivateKey)-1] + "1"); err == nil {
+		t.Errorf("ParseExtendedKey() with a bad checksum should fail")
+	}
+}
+
+func TestHOTPVectors(t *testing.T) {
+	// RFC 4226 appendix D.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		got, err := GenerateHOTP(secret, uint64(counter), OTPOptions{})
+		if err != nil || got != code {
+			t.Errorf("GenerateHOTP(counter=%d) = %q, %v, want %q", counter, got, err, code)
+		}
+	}
+
+	ok, matched, err := VerifyHOTP(secret, "162583", 3, 5, OTPOptions{})
+	if err != nil || !ok || matched != 7 {
+		t.Errorf("VerifyHOTP() in the look-ahead window = %v, %d, %v", ok, matched, err)
+	}
+	if ok, _, _ := VerifyHOTP(secret, "162583", 3, 2, OTPOptions{}); ok {
+		t.Errorf("VerifyHOTP() accepted a code beyond the look-ahead window")
+	}
+	if _, err := GenerateHOTP(secret, 0, OTPOptions{Algorithm: "MD5"}); err == nil {
+		t.Errorf("GenerateHOTP() with MD5 should fail")
+	}
+}
+
+func TestTOTPAlgorithmVectors(t *testing.T) {
+	// RFC 6238 appendix B.
+	secrets := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		unix int64
+		want map[string]string
+	}{
+		{59, map[string]string{"SHA1": "94287082", "SHA256": "46119246", "SHA512": "90693936"}},
+		{1111111109, map[string]string{"SHA1": "07081804", "SHA256": "68084774", "SHA512": "25091201"}},
+		{20000000000, map[string]string{"SHA1": "65353130", "SHA256": "77737706", "SHA512": "47863826"}},
+	}
+	for _, tc := range tests {
+		for alg, want := range tc.want {
+			secret := base32.StdEncoding.EncodeToString([]byte(secrets[alg]))
+			opts := OTPOptions{Algorithm: alg, Digits: 8}
+			got, err := TOTPAt(secret, time.Unix(tc.unix, 0), opts)
+			if err != nil || got != want {
+				t.Errorf("TOTPAt(%s, %d) = %q, %v, want %q", alg, tc.unix, got, err, want)
+			}
+			ok, err := VerifyTOTPAt(secret, want, time.Unix(tc.unix+30, 0), 1, opts)
+			if err != nil || !ok {
+				t.Errorf("VerifyTOTPAt(%s) one step later = %v, %v", alg, ok, err)
+			}
+		}
+	}
+}
+
+func TestOTPAuthURI(t *testing.T) {
+	key, err := ParseOTPAuthURI("otpauth://totp/ACME%20Co:john.doe@email.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60&image=https://example.com/logo.png")
+	if err != nil {
+		t.Fatalf("ParseOTPAuthURI error: %v", err)
+	}
+	if key.Type != "totp" || key.Issuer != "ACME Co" || key.Account != "john.doe@email.com" ||
+		key.Algorithm != "SHA256" || key.Digits != 8 || key.Period != 60 || key.Params["image"] != "https://example.com/logo.png" {
+		t.Errorf("ParseOTPAuthURI() = %+v", key)
+	}
+
+	again, err := ParseOTPAuthURI(key.URI())
+	if err != nil || again.Issuer != key.Issuer || again.Account != key.Account || again.Period != 60 || again.Params["image"] != key.Params["image"] {
+		t.Errorf("URI() did not round-trip: %q -> %+v, %v", key.URI(), again, err)
+	}
+
+	// Query and label metacharacters survive the round trip.
+	special := OTPAuthKey{Type: "totp", Issuer: "AT&T+Co = 100%", Account: "a:b c/d?e#f@example.com", Secret: "JBSWY3DPEHPK3PXP"}
+	uri := special.URI()
+	again, err = ParseOTPAuthURI(uri)
+	if err != nil || again.Issuer != special.Issuer || again.Account != special.Account {
+		t.Errorf("URI() did not round-trip special characters: %q -> %+v, %v", uri, again, err)
+	}
+	if !strings.Contains(uri, "issuer=AT%26T%2BCo%20%3D%20100%25") {
+		t.Errorf("URI() issuer not query-escaped: %q", uri)
+	}
+
+	escaped, err := ParseOTPAuthURI("otpauth://totp/ACME%3Aalice?secret=JBSWY3DPEHPK3PXP")
+	if err != nil || escaped.Issuer != "ACME" || escaped.Account != "alice" {
+		t.Errorf("ParseOTPAuthURI(escaped separator) = %+v, %v", escaped, err)
+	}
+
+	hotp, err := ParseOTPAuthURI("otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=42")
+	if err != nil || hotp.Counter != 42 || hotp.Algorithm != "SHA1" || hotp.Digits != 6 || hotp.Period != 0 {
+		t.Errorf("ParseOTPAuthURI(hotp) = %+v, %v", hotp, err)
+	}
+
+	for _, bad := range []string{
+		"https://totp/alice?secret=JBSWY3DPEHPK3PXP",
+		"otpauth://motp/alice?secret=JBSWY3DPEHPK3PXP",
+		"otpauth://totp/alice",
+		"otpauth://totp/alice?secret=not-base32!",
+		"otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP",
+		"otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&digits=4",
+	} {
+		if _, err := ParseOTPAuthURI(bad); err == nil {
+			t.Errorf("ParseOTPAuthURI(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCipherVectors(t *testing.T) {
+	unhex := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	nistKey := unhex("2b7e151628aed2a6abf7158809cf4f3c")
+	nistBlock := unhex("6bc1bee22e409f96e93d7e117393172a")
+	// NIST SP 800-38A F.2.1 and F.5.1, GCM spec test case 2, and
+	// RFC 8439 section 2.8.2 (ciphertext prefix and tag).
+	tests := []struct {
+		name      string
+		opts      CipherOptions
+		plaintext []byte
+		prefix    string
+		suffix    string
+	}{
+		{"aes-cbc", CipherOptions{Algorithm: "aes-cbc", Key: nistKey, IV: unhex("000102030405060708090a0b0c0d0e0f"), Padding: "none"},
+			nistBlock, "7649abac8119b246cee98e9b12e9197d", ""},
+		{"aes-ctr", CipherOptions{Algorithm: "AES-CTR", Key: nistKey, IV: unhex("f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")},
+			nistBlock, "874d6191b620e3261bef6864990db6ce", ""},
+		{"aes-gcm", CipherOptions{Algorithm: "aes-gcm", Key: make([]byte, 16), IV: make([]byte, 12)},
+			make([]byte, 16), "0388dace60b6a392f328c2b971b2fe78", "ab6e47d42cec13bdf53a67b21257bddf"},
+		{"chacha20-poly1305", CipherOptions{Algorithm: "chacha20-poly1305",
+			Key: unhex("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f"),
+			IV:  unhex("070000004041424344454647"), AAD: unhex("50515253c0c1c2c3c4c5c6c7")},
+			[]byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it."),
+			"d31a8d34648e60db7b86afbc53ef7ec2", "1ae10b594f09e26a7e902ecbd0600691"},
+	}
+	for _, tt := range tests {
+		ct, _, err := CipherEncrypt(tt.plaintext, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: CipherEncrypt error: %v", tt.name, err)
+		}
+		got := hex.EncodeToString(ct)
+		if !strings.HasPrefix(got, tt.prefix) || !strings.HasSuffix(got, tt.suffix) {
+			t.Errorf("%s: ciphertext = %s", tt.name, got)
+		}
+		pt, err := CipherDecrypt(ct, tt.opts)
+		if err != nil || string(pt) != string(tt.plaintext) {
+			t.Errorf("%s: CipherDecrypt = %x, %v", tt.name, pt, err)
+		}
+	}
+}
+
+func TestCipherRoundTripAndErrors(t *testing.T) {
+	key := make([]byte, 32)
+	for _, alg := range CipherAlgorithms {
+		ct, iv, err := CipherEncrypt([]byte("hello"), CipherOptions{Algorithm: alg, Key: key})
+		if err != nil {
+			t.Fatalf("%s: CipherEncrypt error: %v", alg, err)
+		}
+		if len(iv) != cipherIVSize[alg] {
+			t.Errorf("%s: generated IV is %d bytes", alg, len(iv))
+		}
+		pt, err := CipherDecrypt(ct, CipherOptions{Algorithm: alg, Key: key, IV: iv})
+		if err != nil || string(pt) != "hello" {
+			t.Errorf("%s: round trip = %q, %v", alg, pt, err)
+		}
+	}
+
+	ct, iv, _ := CipherEncrypt([]byte("hello"), CipherOptions{Algorithm: "aes-gcm", Key: key, AAD: []byte("ctx")})
+	if _, err := CipherDecrypt(ct, CipherOptions{Algorithm: "aes-gcm", Key: key, IV: iv, AAD: []byte("other")}); err == nil {
+		t.Error("aes-gcm with the wrong AAD should fail")
+	}
+	if _, _, err := CipherEncrypt([]byte("x"), CipherOptions{Algorithm: "aes-cbc", Key: key[:10]}); err == nil {
+		t.Error("a 10-byte AES key should be rejected")
+	}
+	if _, _, err := CipherEncrypt([]byte("x"), CipherOptions{Algorithm: "aes-cbc", Key: key, Padding: "none"}); err == nil {
+		t.Error("aes-cbc without padding should reject a partial block")
+	}
+	if _, err := CipherDecrypt(ct, CipherOptions{Algorithm: "aes-gcm", Key: key}); err == nil {
+		t.Error("CipherDecrypt without an IV should fail")
+	}
+	if _, _, err := CipherEncrypt([]byte("x"), CipherOptions{Algorithm: "des", Key: key}); err == nil {
+		t.Error("an unknown algorithm should be rejected")
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	// RFC 6070 PBKDF2-HMAC-SHA1, 4096 iterations, 25-byte key.
+	salt := hex.EncodeToString([]byte("saltSALTsaltSALTsaltSALTsaltSALTsalt"))
+	key, used, err := DeriveKey("passwordPASSWORDpassword", KDFOptions{
+		Algorithm: "pbkdf2-sha1", Salt: salt, Params: PasswordHashParams{Iterations: 4096, KeyLength: 25},
+	})
+	if err != nil {
+		t.Fatalf("DeriveKey error: %v", err)
+	}
+	if got := hex.EncodeToString(key); got != "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038" {
+		t.Errorf("pbkdf2-sha1 key = %s", got)
+	}
+	if used.Params.Iterations != 4096 || used.Params.SaltLength != 36 || used.Params.Memory != 0 {
+		t.Errorf("used params = %+v", used.Params)
+	}
+
+	key, used, err = DeriveKey("pw", KDFOptions{Params: PasswordHashParams{Memory: 1024, Time: 1, Threads: 1}})
+	if err != nil {
+		t.Fatalf("DeriveKey(argon2id) error: %v", err)
+	}
+	if used.Algorithm != "argon2id" || len(used.Salt) != 32 || len(key) != 32 {
+		t.Errorf("DeriveKey(argon2id) = %x, %+v", key, used)
+	}
+	again, _, err := DeriveKey("pw", used)
+	if err != nil || hex.EncodeToString(again) != hex.EncodeToString(key) {
+		t.Errorf("re-deriving with the reported options = %x, %v", again, err)
+	}
+
+	for _, opts := range []KDFOptions{
+		{Algorithm: "md5"},
+		{Algorithm: "scrypt", Params: PasswordHashParams{LogN: 40}},
+		{Algorithm: "pbkdf2-sha256", Salt: "zz"},
+		{Algorithm: "pbkdf2-sha256", Salt: "0102"},
+	} {
+		if _, _, err := DeriveKey("pw", opts); err == nil {
+			t.Errorf("DeriveKey(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestAESKeyWrapVectors(t *testing.T) {
+	tests := []struct {
+		kek, key, wrapped string
+		padded            bool
+	}{
+		// RFC 3394 section 4.1 and 4.6.
+		{"000102030405060708090a0b0c0d0e0f", "00112233445566778899aabbccddeeff",
+			"1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5", false},
+		{"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			"00112233445566778899aabbccddeeff000102030405060708090a0b0c0d0e0f",
+			"28c9f404c4b810f4cbccb35cfb87f8263f5786e2d80ed326cbc7f0e71a99f43bfb988b9b7a02dd21", false},
+		// RFC 5649 section 6.
+		{"5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8", "c37b7e6492584340bed12207808941155068f738",
+			"138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a", true},
+		{"5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8", "466f7250617369",
+			"afbeb0f07dfbf5419200f2ccb50bb24f", true},
+	}
+	for _, tt := range tests {
+		kek, _ := hex.DecodeString(tt.kek)
+		key, _ := hex.DecodeString(tt.key)
+		wrapped, err := AESKeyWrap(kek, key, tt.padded)
+		if err != nil || hex.EncodeToString(wrapped) != tt.wrapped {
+			t.Errorf("AESKeyWrap(%s) = %x, %v, want %s", tt.key, wrapped, err, tt.wrapped)
+		}
+		unwrapped, err := AESKeyUnwrap(kek, wrapped, tt.padded)
+		if err != nil || hex.EncodeToString(unwrapped) != tt.key {
+			t.Errorf("AESKeyUnwrap(%s) = %x, %v", tt.wrapped, unwrapped, err)
+		}
+		wrapped[len(wrapped)-1] ^= 1
+		if _, err := AESKeyUnwrap(kek, wrapped, tt.padded); err == nil {
+			t.Errorf("AESKeyUnwrap should reject a tampered %s", tt.wrapped)
+		}
+	}
+	if _, err := AESKeyWrap(make([]byte, 16), make([]byte, 12), false); err == nil {
+		t.Error("RFC 3394 wrap of a 12-byte key should fail")
+	}
+}
+
+func TestAgeRoundTrip(t *testing.T) {
+	alice, err := GenerateAgeKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateAgeKeyPair error: %v", err)
+	}
+	bob, _ := GenerateAgeKeyPair()
+	if !strings.HasPrefix(alice.Recipient, "age1") || !strings.HasPrefix(alice.Identity, "AGE-SECRET-KEY-1") {
+		t.Errorf("key pair = %+v", alice)
+	}
+
+	armored, err := AgeEncrypt([]byte("hello age"), []string{alice.Recipient, bob.Recipient}, "", 0, true)
+	if err != nil || !strings.HasPrefix(armored, "-----BEGIN AGE ENCRYPTED FILE-----") {
+		t.Fatalf("AgeEncrypt(armored) = %q, %v", armored, err)
+	}
+	for _, id := range []string{alice.Identity, bob.Identity} {
+		if pt, err := AgeDecrypt(armored, []string{id}, ""); err != nil || string(pt) != "hello age" {
+			t.Errorf("AgeDecrypt = %q, %v", pt, err)
+		}
+	}
+	carol, _ := GenerateAgeKeyPair()
+	if _, err := AgeDecrypt(armored, []string{carol.Identity}, ""); err == nil {
+		t.Error("AgeDecrypt with an unrelated identity should fail")
+	}
+
+	b64, err := AgeEncrypt([]byte("secret"), nil, "hunter2", 10, false)
+	if err != nil {
+		t.Fatalf("AgeEncrypt(passphrase) error: %v", err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(b64)
+	if !strings.HasPrefix(string(raw), "age-encryption.org/v1\n-> scrypt ") {
+		t.Errorf("binary age file header = %q", raw[:40])
+	}
+	if pt, err := AgeDecrypt(b64, nil, "hunter2"); err != nil || string(pt) != "secret" {
+		t.Errorf("AgeDecrypt(passphrase) = %q, %v", pt, err)
+	}
+	if _, err := AgeDecrypt(b64, nil, "wrong"); err == nil {
+		t.Error("AgeDecrypt with the wrong passphrase should fail")
+	}
+	if _, err := AgeEncrypt([]byte("x"), []string{alice.Recipient}, "pw", 0, true); err == nil {
+		t.Error("AgeEncrypt with both recipients and a passphrase should fail")
+	}
+	if _, err := AgeEncrypt([]byte("x"), nil, "pw", 20, true); err == nil {
+		t.Error("AgeEncrypt above the maximum work factor should fail")
+	}
+}
+
+func TestShamirSplitCombine(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	shares, err := ShamirSplit(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("ShamirSplit error: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("got %d shares", len(shares))
+	}
+
+	// Every 3- and 4-share subset recovers the secret.
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3}} {
+		var picked [][]byte
+		for _, i := range subset {
+			picked = append(picked, shares[i])
+		}
+		got, info, err := ShamirCombine(picked)
+		if err != nil || string(got) != string(secret) {
+			t.Errorf("ShamirCombine(%v) = %q, %v", subset, got, err)
+		} else if info.Threshold != 3 || len(info.ID) != 8 {
+			t.Errorf("ShamirCombine(%v) info = %+v", subset, info)
+		}
+	}
+
+	if _, _, err := ShamirCombine(shares[:2]); err == nil || !strings.Contains(err.Error(), "at least 3") {
+		t.Errorf("two shares should be too few, got %v", err)
+	}
+	if _, _, err := ShamirCombine([][]byte{shares[0], shares[0], shares[1]}); err == nil {
+		t.Error("duplicate shares should be rejected")
+	}
+
+	tampered := append([]byte{}, shares[1]...)
+	tampered[10] ^= 0xff
+	if _, _, err := ShamirCombine([][]byte{shares[0], tampered, shares[2]}); err == nil || !strings.Contains(err.Error(), "share 2") {
+		t.Errorf("a corrupted share should be reported, got %v", err)
+	}
+
+	other, _ := ShamirSplit(secret, 5, 3)
+	if _, _, err := ShamirCombine([][]byte{shares[0], shares[1], other[2]}); err == nil || !strings.Contains(err.Error(), "different split") {
+		t.Errorf("shares from another split should be rejected, got %v", err)
+	}
+
+	for _, tt := range []struct{ shares, threshold int }{{1, 1}, {5, 1}, {3, 4}, {256, 2}} {
+		if _, err := ShamirSplit(secret, tt.shares, tt.threshold); err == nil {
+			t.Errorf("ShamirSplit(%d of %d) should fail", tt.threshold, tt.shares)
+		}
+	}
+}
+
+func TestShamirShareEncodings(t *testing.T) {
+	shares, err := ShamirSplit([]byte{0x00, 0xff, 0x10}, 3, 2)
+	if err != nil {
+		t.Fatalf("ShamirSplit error: %v", err)
+	}
+	for _, encoding := range ShamirEncodings {
+		var decoded [][]byte
+		for _, share := range shares[1:] {
+			s, err := EncodeShamirShare(share, encoding)
+			if err != nil {
+				t.Fatalf("EncodeShamirShare(%s) error: %v", encoding, err)
+			}
+			d, err := DecodeShamirShare(s, encoding)
+			if err != nil || hex.EncodeToString(d) != hex.EncodeToString(share) {
+				t.Errorf("%s round trip of %x = %x, %v", encoding, share, d, err)
+			}
+			decoded = append(decoded, d)
+		}
+		if got, _, err := ShamirCombine(decoded); err != nil || hex.EncodeToString(got) != "00ff10" {
+			t.Errorf("%s: ShamirCombine = %x, %v", encoding, got, err)
+		}
+	}
+
+	// Every length packs and unpacks, whatever the 11-bit padding.
+	for n := 1; n <= 40; n++ {
+		split, _ := ShamirSplit(make([]byte, n), 2, 2)
+		words, _ := EncodeShamirShare(split[0], "mnemonic")
+		if d, err := DecodeShamirShare(words, "mnemonic"); err != nil || len(d) != len(split[0]) {
+			t.Errorf("mnemonic round trip of a %d-byte secret's share = %d bytes, %v", n, len(d), err)
+		}
+	}
+
+	words, _ := EncodeShamirShare(shares[0], "mnemonic")
+	if _, err := DecodeShamirShare(words+" notaword", "mnemonic"); err == nil {
+		t.Error("an unknown word should be rejected")
+	}
+	if _, err := EncodeShamirShare(shares[0], "base58"); err == nil {
+		t.Error("an unknown encoding should be rejected")
+	}
+}
+
+func TestEstimatePassword(t *testing.T) {
+	// Each password is recognised as the single pattern listed.
+	patterns := []struct {
+		password, pattern, detail string
+	}{
+		{"password", "dictionary", "passwords"},
+		{"drowssap", "dictionary", "reversed"},
+		{"p@ssw0rd", "dictionary", "l33t"},
+		{"sdfghjkl", "spatial", "qwerty"},
+		{"zzzzzzzz", "repeat", "z"},
+		{"mnopqrst", "sequence", "lower"},
+		{"1987", "year", ""},
+		{"13.09.1987", "date", "."},
+	}
+	for _, c := range patterns {
+		est := EstimatePassword(c.password)
+		if len(est.Sequence) != 1 || est.Sequence[0].Pattern != c.pattern {
+			t.Errorf("EstimatePassword(%q) sequence = %+v, want one %s match", c.password, est.Sequence, c.pattern)
+			continue
+		}
+		m := est.Sequence[0]
+		var detail string
+		switch {
+		case m.Reversed:
+			detail = "reversed"
+		case m.L33t:
+			detail = "l33t"
+		case c.pattern == "dictionary":
+			detail = m.Dictionary
+		default:
+			detail = m.Graph + m.BaseToken + m.SequenceName + m.Separator
+		}
+		if detail != c.detail {
+			t.Errorf("EstimatePassword(%q) match detail = %q, want %q", c.password, detail, c.detail)
+		}
+		if est.Score > 2 || est.Feedback.Warning == "" {
+			t.Errorf("EstimatePassword(%q) = score %d, warning %q; want a weak score with a warning", c.password, est.Score, est.Feedback.Warning)
+		}
+	}
+
+	if d := EstimatePassword("13.09.1987").Sequence[0]; d.Day != 13 || d.Month != 9 || d.Year != 1987 {
+		t.Errorf("date match = %d-%d-%d, want 1987-9-13", d.Year, d.Month, d.Day)
+	}
+
+	strong := EstimatePassword("correcthorsebatterystaple")
+	if strong.Score != 5 || strong.Strength != "very_strong" || len(strong.Sequence) != 4 {
+		t.Errorf("EstimatePassword(correcthorsebatterystaple) = %+v", strong)
+	}
+	if strong.Feedback.Warning != "" || len(strong.Feedback.Suggestions) != 0 {
+		t.Errorf("a strong password should get no feedback: %+v", strong.Feedback)
+	}
+	ct := strong.CrackTimes
+	if ct.OnlineThrottling.Display != "centuries" || ct.OfflineFastHashing.Seconds >= ct.OfflineSlowHashing.Seconds {
+		t.Errorf("crack times = %+v", ct)
+	}
+
+	// A top-10 password is cracked online in seconds.
+	weak := EstimatePassword("qwerty")
+	if weak.Feedback.Warning != "This is a top-10 common password" || weak.CrackTimes.OnlineNoThrottling.Display != "less than a second" {
+		t.Errorf("EstimatePassword(qwerty) = %+v", weak)
+	}
+
+	// User inputs are matched like any other dictionary.
+	if EstimatePassword("Zb8ronwq4kX").Score < 4 {
+		t.Error("a random 11-character password should be strong")
+	}
+	if est := EstimatePassword("Zb8ronwq4kX", "zb8ronwq4kx"); est.Score > 2 || est.Sequence[0].Dictionary != "user_inputs" {
+		t.Errorf("a password equal to a user input = %+v", est)
+	}
+
+	// Only the first StrengthMaxLength characters are analysed, and the
+	// estimate stays finite.
+	long := EstimatePassword(strings.Repeat("x9!Q", 1000))
+	if long.Sequence[len(long.Sequence)-1].End != StrengthMaxLength-1 || math.IsInf(long.Guesses, 0) {
+		t.Errorf("long password estimate = %v guesses, last match %+v", long.Guesses, long.Sequence[len(long.Sequence)-1])
+	}
+}
@@ -0,0 +1,318 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// JWK is the wire form of a JSON Web Key (RFC 7517/7518/8037), limited to
+// the members of the key types ParseKey understands.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	D   string `json:"d,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	P   string `json:"p,omitempty"`
+	Q   string `json:"q,omitempty"`
+	DP  string `json:"dp,omitempty"`
+	DQ  string `json:"dq,omitempty"`
+	QI  string `json:"qi,omitempty"`
+	K   string `json:"k,omitempty"`
+}
+
+// parseJWK decodes a single JSON Web Key.
+func parseJWK(data []byte) (interface{}, error) {
+	var k JWK
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+
+	switch k.Kty {
+	case "oct":
+		secret, err := jwkBytes("k", k.K)
+		if err != nil {
+			return nil, err
+		}
+		return secret, nil
+
+	case "RSA":
+		n, err := jwkInt("n", k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := jwkInt("e", k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid JWK: unsupported RSA exponent")
+		}
+		pub := rsa.PublicKey{N: n, E: int(e.Int64())}
+		if k.D == "" {
+			return &pub, nil
+		}
+		d, err := jwkInt("d", k.D)
+		if err != nil {
+			return nil, err
+		}
+		p, err := jwkInt("p", k.P)
+		if err != nil {
+			return nil, err
+		}
+		q, err := jwkInt("q", k.Q)
+		if err != nil {
+			return nil, err
+		}
+		priv := &rsa.PrivateKey{PublicKey: pub, D: d, Primes: []*big.Int{p, q}}
+		if err := priv.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid JWK: %w", err)
+		}
+		priv.Precompute()
+		return priv, nil
+
+	case "EC":
+		curve, err := jwkCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if k.D != "" {
+			d, err := jwkFixedBytes("d", k.D, size)
+			if err != nil {
+				return nil, err
+			}
+			priv, err := ecdsa.ParseRawPrivateKey(curve, d)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JWK: %w", err)
+			}
+			return priv, nil
+		}
+		x, err := jwkFixedBytes("x", k.X, size)
+		if err != nil {
+			return nil, err
+		}
+		y, err := jwkFixedBytes("y", k.Y, size)
+		if err != nil {
+			return nil, err
+		}
+		point := append(append([]byte{4}, x...), y...)
+		pub, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK: %w", err)
+		}
+		return pub, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("invalid JWK: unsupported OKP curve %q", k.Crv)
+		}
+		if k.D != "" {
+			seed, err := jwkFixedBytes("d", k.D, ed25519.SeedSize)
+			if err != nil {
+				return nil, err
+			}
+			return ed25519.NewKeyFromSeed(seed), nil
+		}
+		x, err := jwkFixedBytes("x", k.X, ed25519.PublicKeySize)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("invalid JWK: unsupported kty %q", k.Kty)
+}
+
+// jwkCurve maps a JWK "crv" to its elliptic curve.
+func jwkCurve(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("invalid JWK: unsupported EC curve %q", crv)
+}
+
+// jwkBytes decodes the base64url member name.
+func jwkBytes(name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("invalid JWK: missing %q", name)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWK: %q is not base64url", name)
+	}
+	return b, nil
+}
+
+// jwkFixedBytes decodes member name, which must be exactly size bytes.
+func jwkFixedBytes(name, value string, size int) ([]byte, error) {
+	b, err := jwkBytes(name, value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("invalid JWK: %q must be %d bytes, got %d", name, size, len(b))
+	}
+	return b, nil
+}
+
+// jwkInt decodes member name as a big-endian unsigned integer.
+func jwkInt(name, value string) (*big.Int, error) {
+	b, err := jwkBytes(name, value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// MarshalJWK returns key, as returned by ParseKey, as a JWK. Private keys
+// keep their private members; pass PublicKey(key) for the public JWK.
+func MarshalJWK(key interface{}) (*JWK, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch k := key.(type) {
+	case []byte:
+		return &JWK{Kty: "oct", K: b64(k)}, nil
+	case *rsa.PublicKey:
+		return &JWK{Kty: "RSA", N: b64(k.N.Bytes()), E: b64(big.NewInt(int64(k.E)).Bytes())}, nil
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, fmt.Errorf("multi-prime RSA keys cannot be written as a JWK")
+		}
+		k.Precompute()
+		j, _ := MarshalJWK(&k.PublicKey)
+		j.D = b64(k.D.Bytes())
+		j.P = b64(k.Primes[0].Bytes())
+		j.Q = b64(k.Primes[1].Bytes())
+		j.DP = b64(k.Precomputed.Dp.Bytes())
+		j.DQ = b64(k.Precomputed.Dq.Bytes())
+		j.QI = b64(k.Precomputed.Qinv.Bytes())
+		return j, nil
+	case *ecdsa.PublicKey:
+		point, err := k.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		size := (len(point) - 1) / 2
+		return &JWK{Kty: "EC", Crv: k.Curve.Params().Name, X: b64(point[1 : 1+size]), Y: b64(point[1+size:])}, nil
+	case *ecdsa.PrivateKey:
+		j, err := MarshalJWK(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		d, err := k.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		j.D = b64(d)
+		return j, nil
+	case ed25519.PublicKey:
+		return &JWK{Kty: "OKP", Crv: "Ed25519", X: b64(k)}, nil
+	case ed25519.PrivateKey:
+		j, _ := MarshalJWK(k.Public())
+		j.D = b64(k.Seed())
+		return j, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// JWKThumbprint returns the RFC 7638 SHA-256 thumbprint of key, base64url
+// encoded. It hashes only the required public members, in lexicographic
+// order, so a private key and its public half share a thumbprint.
+func JWKThumbprint(key interface{}) (string, error) {
+	j, err := MarshalJWK(PublicKey(key))
+	if err != nil {
+		return "", err
+	}
+	var canonical string
+	switch j.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, j.E, j.N)
+	case "EC":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, j.Crv, j.X, j.Y)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, j.Crv, j.X)
+	default:
+		canonical = fmt.Sprintf(`{"k":%q,"kty":"oct"}`, j.K)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// JWKS is a JSON Web Key Set document.
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// BuildJWKS parses each of keys (any format ParseKey accepts) and returns
+// their public halves as a JWKS. Every entry gets use (when non-empty), an
+// alg for key types with a single JWS algorithm, and its RFC 7638
+// thumbprint as kid. Symmetric keys are refused, since a JWKS is meant to
+// be published. Keys for "enc" get no alg, which would otherwise name a
+// signing algorithm rather than their key-management one.
+func BuildJWKS(keys []string, use string) (*JWKS, error) {
+	set := &JWKS{Keys: make([]*JWK, 0, len(keys))}
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		parsed, err := ParseKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if _, ok := parsed.([]byte); ok {
+			return nil, fmt.Errorf("key %d: symmetric keys cannot be published in a JWKS", i)
+		}
+		pub := PublicKey(parsed)
+		j, err := MarshalJWK(pub)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if j.Kid, err = JWKThumbprint(pub); err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if seen[j.Kid] {
+			return nil, fmt.Errorf("key %d: duplicate of an earlier key", i)
+		}
+		seen[j.Kid] = true
+		j.Use = use
+		if use != "enc" {
+			j.Alg = defaultJWSAlgorithm(pub)
+		}
+		set.Keys = append(set.Keys, j)
+	}
+	return set, nil
+}
+
+// defaultJWSAlgorithm returns the only JWS algorithm pub can sign with, or
+// "" for RSA, which has several.
+func defaultJWSAlgorithm(pub interface{}) string {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().Name {
+		case "P-256":
+			return "ES256"
+		case "P-384":
+			return "ES384"
+		case "P-521":
+			return "ES512"
+		}
+	case ed25519.PublicKey:
+		return "EdDSA"
+	}
+	return ""
+}
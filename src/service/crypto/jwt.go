@@ -0,0 +1,438 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JWTAlgorithms lists the JWS algorithms JWTSign and JWTVerify support.
+// "none" is deliberately absent: an unsigned token never verifies.
+var JWTAlgorithms = []string{
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// jwtAlg describes how one JWS algorithm signs: its family (HS, RS, PS,
+// ES or EdDSA), digest, and for ES the curve the key must be on.
+type jwtAlg struct {
+	family string
+	hash   stdcrypto.Hash
+	curve  string
+}
+
+var jwtAlgs = map[string]jwtAlg{
+	"HS256": {"HS", stdcrypto.SHA256, ""},
+	"HS384": {"HS", stdcrypto.SHA384, ""},
+	"HS512": {"HS", stdcrypto.SHA512, ""},
+	"RS256": {"RS", stdcrypto.SHA256, ""},
+	"RS384": {"RS", stdcrypto.SHA384, ""},
+	"RS512": {"RS", stdcrypto.SHA512, ""},
+	"PS256": {"PS", stdcrypto.SHA256, ""},
+	"PS384": {"PS", stdcrypto.SHA384, ""},
+	"PS512": {"PS", stdcrypto.SHA512, ""},
+	"ES256": {"ES", stdcrypto.SHA256, "P-256"},
+	"ES384": {"ES", stdcrypto.SHA384, "P-384"},
+	"ES512": {"ES", stdcrypto.SHA512, "P-521"},
+	"EdDSA": {"EdDSA", 0, ""},
+}
+
+func lookupJWTAlg(algorithm string) (jwtAlg, error) {
+	alg, ok := jwtAlgs[algorithm]
+	if !ok {
+		return jwtAlg{}, fmt.Errorf("unsupported algorithm: %q", algorithm)
+	}
+	return alg, nil
+}
+
+// JWTGenerateKey creates a key suitable for algorithm with the existing
+// generators: a random hex secret for HS* (returned as private, with an
+// empty public), or a PEM keypair otherwise.
+func JWTGenerateKey(algorithm string) (private, public string, err error) {
+	alg, err := lookupJWTAlg(algorithm)
+	if err != nil {
+		return "", "", err
+	}
+	switch alg.family {
+	case "HS":
+		secret, err := RandomBytes(alg.hash.Size())
+		if err != nil {
+			return "", "", err
+		}
+		return hex.EncodeToString(secret), "", nil
+	case "RS", "PS":
+		return GenerateRSAKeys(2048)
+	case "ES":
+		return GenerateECDSAKeysCurve(alg.curve)
+	default:
+		return GenerateEd25519Keys()
+	}
+}
+
+// JWTSign returns claims as a compact JWS signed with algorithm. For HS*
+// key is the shared secret (or an "oct" JWK); otherwise it is a PEM or JWK
+// private key of the matching type. Members of header, such as "kid", are
+// added to the protected header, but "alg" and "typ" are always set here.
+func JWTSign(algorithm string, claims, header map[string]interface{}, key string) (string, error) {
+	alg, err := lookupJWTAlg(algorithm)
+	if err != nil {
+		return "", err
+	}
+	signer, err := jwtSigningKey(alg, key)
+	if err != nil {
+		return "", err
+	}
+
+	hdr := make(map[string]interface{}, len(header)+2)
+	for k, v := range header {
+		hdr[k] = v
+	}
+	hdr["alg"] = algorithm
+	if _, ok := hdr["typ"]; !ok {
+		hdr["typ"] = "JWT"
+	}
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+
+	headerJSON, err := json.Marshal(hdr)
+	if err != nil {
+		return "", fmt.Errorf("invalid header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("invalid claims: %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	sig, err := jwtSign(alg, signer, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// JWTVerifyOptions controls JWTVerify's checks. Empty fields skip the
+// corresponding check.
+type JWTVerifyOptions struct {
+	// Algorithms the token may use; empty allows any of JWTAlgorithms.
+	Algorithms []string
+	// Audience must appear in the "aud" claim.
+	Audience string
+	// Issuer must equal the "iss" claim.
+	Issuer string
+	// ClockSkew is the leeway applied to exp, nbf and iat.
+	ClockSkew time.Duration
+	// Now is the time claims are checked against; zero means time.Now.
+	Now time.Time
+}
+
+// JWTVerification is the outcome of JWTVerify. Valid is true only when
+// the signature checks out and no claim check failed; Errors says why not.
+type JWTVerification struct {
+	Header         map[string]interface{} `json:"header"`
+	Claims         map[string]interface{} `json:"claims"`
+	Algorithm      string                 `json:"algorithm"`
+	SignatureValid bool                   `json:"signature_valid"`
+	Valid          bool                   `json:"valid"`
+	Errors         []string               `json:"errors"`
+}
+
+// JWTVerify checks token's signature with key and validates its exp, nbf,
+// iat, aud and iss claims per opts. key is the HS* shared secret, or a PEM
+// or JWK public key (a private key also works; its public half is used).
+// An error is returned only when the token or key cannot be evaluated at
+// all; a bad signature or failed claim check is reported in the result.
+func JWTVerify(token, key string, opts JWTVerifyOptions) (*JWTVerification, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	var header map[string]interface{}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid JWT header: %w", err)
+	}
+	var claims map[string]interface{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT claims: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT signature: not base64url")
+	}
+
+	algorithm, _ := header["alg"].(string)
+	alg, err := lookupJWTAlg(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := header["crit"]; ok {
+		return nil, fmt.Errorf("unsupported critical header parameters")
+	}
+	verifier, err := jwtVerifyingKey(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JWTVerification{Header: header, Claims: claims, Algorithm: algorithm, Errors: []string{}}
+	if len(opts.Algorithms) > 0 && !slices.Contains(opts.Algorithms, algorithm) {
+		result.Errors = append(result.Errors, fmt.Sprintf("algorithm %s is not allowed", algorithm))
+	} else if jwtVerify(alg, verifier, []byte(parts[0]+"."+parts[1]), sig) {
+		result.SignatureValid = true
+	} else {
+		result.Errors = append(result.Errors, "signature is invalid")
+	}
+
+	result.Errors = append(result.Errors, checkJWTClaims(claims, opts)...)
+	result.Valid = result.SignatureValid && len(result.Errors) == 0
+	return result, nil
+}
+
+// checkJWTClaims validates the registered time, audience and issuer claims
+// and returns one message per failed check.
+func checkJWTClaims(claims map[string]interface{}, opts JWTVerifyOptions) []string {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	skew := opts.ClockSkew
+	var errs []string
+
+	if exp, ok, err := jwtTimeClaim(claims, "exp"); err != nil {
+		errs = append(errs, err.Error())
+	} else if ok && !now.Before(exp.Add(skew)) {
+		errs = append(errs, "token expired at "+exp.UTC().Format(time.RFC3339))
+	}
+	if nbf, ok, err := jwtTimeClaim(claims, "nbf"); err != nil {
+		errs = append(errs, err.Error())
+	} else if ok && now.Add(skew).Before(nbf) {
+		errs = append(errs, "token is not valid before "+nbf.UTC().Format(time.RFC3339))
+	}
+	if iat, ok, err := jwtTimeClaim(claims, "iat"); err != nil {
+		errs = append(errs, err.Error())
+	} else if ok && now.Add(skew).Before(iat) {
+		errs = append(errs, "token was issued in the future, at "+iat.UTC().Format(time.RFC3339))
+	}
+
+	if opts.Audience != "" {
+		var audiences []string
+		switch aud := claims["aud"].(type) {
+		case string:
+			audiences = []string{aud}
+		case []interface{}:
+			for _, a := range aud {
+				if s, ok := a.(string); ok {
+					audiences = append(audiences, s)
+				}
+			}
+		}
+		if !slices.Contains(audiences, opts.Audience) {
+			errs = append(errs, fmt.Sprintf("audience %q is not in the aud claim", opts.Audience))
+		}
+	}
+	if opts.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != opts.Issuer {
+			errs = append(errs, fmt.Sprintf("issuer %q does not match expected %q", iss, opts.Issuer))
+		}
+	}
+	return errs
+}
+
+// jwtTimeClaim reads the NumericDate claim name. ok is false when the
+// claim is absent.
+func jwtTimeClaim(claims map[string]interface{}, name string) (t time.Time, ok bool, err error) {
+	v, present := claims[name]
+	if !present {
+		return time.Time{}, false, nil
+	}
+	n, isNumber := v.(json.Number)
+	if !isNumber {
+		return time.Time{}, false, fmt.Errorf("%s claim must be a number", name)
+	}
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) || math.Abs(f) > 1<<53 {
+		return time.Time{}, false, fmt.Errorf("%s claim must be a number", name)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)), true, nil
+}
+
+// decodeJWTPart decodes a base64url JSON object segment, keeping numbers
+// as json.Number so large NumericDates survive.
+func decodeJWTPart(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("not base64url")
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("not a JSON object")
+	}
+	return nil
+}
+
+// jwtSecret returns the HMAC secret in key. Asymmetric keys are refused so
+// a public key can never be used as an HMAC secret (algorithm confusion).
+func jwtSecret(key string) ([]byte, error) {
+	if key == "" {
+		return nil, fmt.Errorf("a secret is required for HMAC algorithms")
+	}
+	trimmed := strings.TrimSpace(key)
+	if strings.HasPrefix(trimmed, "-----BEGIN") {
+		return nil, fmt.Errorf("HMAC algorithms take a shared secret, not a PEM key")
+	}
+	if strings.HasPrefix(trimmed, "{") {
+		parsed, err := ParseKey(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		secret, ok := parsed.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("HMAC algorithms take a shared secret or an oct JWK")
+		}
+		return secret, nil
+	}
+	return []byte(key), nil
+}
+
+// jwtSigningKey parses key and checks it is a private key for alg.
+func jwtSigningKey(alg jwtAlg, key string) (interface{}, error) {
+	if alg.family == "HS" {
+		return jwtSecret(key)
+	}
+	parsed, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	switch parsed.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return nil, fmt.Errorf("signing requires a private key")
+	}
+	if err := checkJWTKeyType(alg, PublicKey(parsed)); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// jwtVerifyingKey parses key, reducing a private key to its public half,
+// and checks it suits alg.
+func jwtVerifyingKey(alg jwtAlg, key string) (interface{}, error) {
+	if alg.family == "HS" {
+		return jwtSecret(key)
+	}
+	parsed, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	pub := PublicKey(parsed)
+	if err := checkJWTKeyType(alg, pub); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// checkJWTKeyType reports whether pub is the key type alg signs with.
+func checkJWTKeyType(alg jwtAlg, pub interface{}) error {
+	switch alg.family {
+	case "RS", "PS":
+		if _, ok := pub.(*rsa.PublicKey); ok {
+			return nil
+		}
+		return fmt.Errorf("%s algorithms require an RSA key", alg.family)
+	case "ES":
+		if k, ok := pub.(*ecdsa.PublicKey); ok {
+			if k.Curve.Params().Name == alg.curve {
+				return nil
+			}
+			return fmt.Errorf("this algorithm requires a %s key, got %s", alg.curve, k.Curve.Params().Name)
+		}
+		return fmt.Errorf("ES algorithms require an EC key")
+	case "EdDSA":
+		if _, ok := pub.(ed25519.PublicKey); ok {
+			return nil
+		}
+		return fmt.Errorf("EdDSA requires an Ed25519 key")
+	}
+	return fmt.Errorf("unsupported key type")
+}
+
+// jwtDigest hashes signingInput with alg's hash.
+func jwtDigest(alg jwtAlg, signingInput []byte) []byte {
+	h := alg.hash.New()
+	h.Write(signingInput)
+	return h.Sum(nil)
+}
+
+// jwtSign signs signingInput with key, already checked to suit alg. ES
+// signatures use the fixed-width R||S form JWS requires, not ASN.1.
+func jwtSign(alg jwtAlg, key interface{}, signingInput []byte) ([]byte, error) {
+	switch alg.family {
+	case "HS":
+		mac := hmac.New(alg.hash.New, key.([]byte))
+		mac.Write(signingInput)
+		return mac.Sum(nil), nil
+	case "RS":
+		return rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), alg.hash, jwtDigest(alg, signingInput))
+	case "PS":
+		return rsa.SignPSS(rand.Reader, key.(*rsa.PrivateKey), alg.hash, jwtDigest(alg, signingInput),
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	case "ES":
+		priv := key.(*ecdsa.PrivateKey)
+		r, s, err := ecdsa.Sign(rand.Reader, priv, jwtDigest(alg, signingInput))
+		if err != nil {
+			return nil, err
+		}
+		size := (priv.Curve.Params().BitSize + 7) / 8
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig, nil
+	}
+	return ed25519.Sign(key.(ed25519.PrivateKey), signingInput), nil
+}
+
+// jwtVerify checks sig over signingInput with key, already checked to
+// suit alg.
+func jwtVerify(alg jwtAlg, key interface{}, signingInput, sig []byte) bool {
+	switch alg.family {
+	case "HS":
+		mac := hmac.New(alg.hash.New, key.([]byte))
+		mac.Write(signingInput)
+		return subtle.ConstantTimeCompare(mac.Sum(nil), sig) == 1
+	case "RS":
+		return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), alg.hash, jwtDigest(alg, signingInput), sig) == nil
+	case "PS":
+		return rsa.VerifyPSS(key.(*rsa.PublicKey), alg.hash, jwtDigest(alg, signingInput), sig,
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case "ES":
+		pub := key.(*ecdsa.PublicKey)
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(pub, jwtDigest(alg, signingInput), r, s)
+	}
+	return ed25519.Verify(key.(ed25519.PublicKey), signingInput, sig)
+}
@@ -0,0 +1,335 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// KeyFormats lists the output formats ConvertKey supports.
+var KeyFormats = []string{"pem", "pkcs8", "pkcs1", "sec1", "spki", "der", "jwk", "openssh"}
+
+// ParseKey parses a key in any supported format into the Go key it holds:
+// *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey, *ecdsa.PublicKey,
+// ed25519.PrivateKey, ed25519.PublicKey, or []byte for a symmetric "oct"
+// JWK. Accepted formats are a JWK; PEM holding a PKCS#8, PKCS#1, SEC 1 or
+// OpenSSH private key, a PKIX or PKCS#1 public key, or a certificate (its
+// public key is returned); the same DER structures base64-encoded; and an
+// OpenSSH authorized_keys line. PEM labels are not relied on.
+func ParseKey(key string) (interface{}, error) {
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+		return nil, fmt.Errorf("key is empty")
+	case strings.HasPrefix(key, "{"):
+		return parseJWK([]byte(key))
+	case strings.HasPrefix(key, "-----BEGIN"):
+		return parsePEMKey(key)
+	case strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "ecdsa-sha2-"):
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OpenSSH public key: %w", err)
+		}
+		return sshCryptoKey(pub)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key), ""))
+	if err != nil {
+		return nil, fmt.Errorf("key must be PEM, base64 DER, a JWK or an OpenSSH public key")
+	}
+	return parseDERKey(der)
+}
+
+// parsePEMKey decodes the first PEM block of key.
+func parsePEMKey(key string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM key")
+	}
+	if block.Type == "OPENSSH PRIVATE KEY" {
+		priv, err := ssh.ParseRawPrivateKey([]byte(key))
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("encrypted OpenSSH keys are not supported")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid OpenSSH private key: %w", err)
+		}
+		return normalizeKey(priv)
+	}
+	if strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") || block.Type == "ENCRYPTED PRIVATE KEY" {
+		return nil, fmt.Errorf("encrypted private keys are not supported")
+	}
+	return parseDERKey(block.Bytes)
+}
+
+// parseDERKey tries each DER key structure ParseKey accepts in turn.
+func parseDERKey(der []byte) (interface{}, error) {
+	if k, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return normalizeKey(k)
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(der); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return normalizeKey(k)
+	}
+	if k, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return k, nil
+	}
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		return normalizeKey(cert.PublicKey)
+	}
+	return nil, fmt.Errorf("unsupported or malformed key")
+}
+
+// normalizeKey rejects key types other than RSA, ECDSA and Ed25519 and
+// turns *ed25519.PrivateKey, as the ssh package returns, into a value.
+func normalizeKey(key interface{}) (interface{}, error) {
+	switch k := key.(type) {
+	case *ed25519.PrivateKey:
+		return *k, nil
+	case *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey, *ecdsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// sshCryptoKey unwraps an ssh.PublicKey into its crypto key.
+func sshCryptoKey(pub ssh.PublicKey) (interface{}, error) {
+	ck, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported OpenSSH key type %s", pub.Type())
+	}
+	return normalizeKey(ck.CryptoPublicKey())
+}
+
+// PublicKey returns the public half of a private key as ParseKey returns
+// it, or key itself if it is already public (or symmetric).
+func PublicKey(key interface{}) interface{} {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	case ed25519.PrivateKey:
+		return k.Public()
+	}
+	return key
+}
+
+// isPrivateKey reports whether key is an asymmetric private key.
+func isPrivateKey(key interface{}) bool {
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return true
+	}
+	return false
+}
+
+// KeyInfo describes a parsed key.
+type KeyInfo struct {
+	// Type is RSA, EC, Ed25519 or oct.
+	Type    string `json:"type"`
+	Private bool   `json:"private"`
+	// Bits is the modulus size for RSA, the curve size for EC and
+	// Ed25519, and the secret length for oct.
+	Bits         int             `json:"bits"`
+	Curve        string          `json:"curve,omitempty"`
+	Fingerprints KeyFingerprints `json:"fingerprints"`
+}
+
+// KeyFingerprints are the common fingerprints of a key's public half.
+// Only the JWK thumbprint applies to symmetric keys.
+type KeyFingerprints struct {
+	// JWKThumbprint is the RFC 7638 SHA-256 thumbprint, base64url.
+	JWKThumbprint string `json:"jwk_thumbprint"`
+	// SSHSHA256 is what ssh-keygen -l prints: "SHA256:" and base64.
+	SSHSHA256 string `json:"ssh_sha256,omitempty"`
+	// SSHMD5 is the legacy colon-separated hex OpenSSH fingerprint.
+	SSHMD5 string `json:"ssh_md5,omitempty"`
+	// SPKISHA256 is the base64 SHA-256 of the DER SubjectPublicKeyInfo,
+	// the form used for public key pinning.
+	SPKISHA256 string `json:"spki_sha256,omitempty"`
+	// SPKIMD5 is the hex MD5 of the DER SubjectPublicKeyInfo.
+	SPKIMD5 string `json:"spki_md5,omitempty"`
+}
+
+// InspectKey parses key (any format ParseKey accepts) and reports its
+// type, size, curve and fingerprints.
+func InspectKey(key string) (*KeyInfo, error) {
+	parsed, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return DescribeKey(parsed)
+}
+
+// DescribeKey is InspectKey for an already parsed key.
+func DescribeKey(key interface{}) (*KeyInfo, error) {
+	info := &KeyInfo{}
+	pub := PublicKey(key)
+	switch k := pub.(type) {
+	case []byte:
+		info.Type, info.Private, info.Bits = "oct", true, len(k)*8
+	case *rsa.PublicKey:
+		info.Type, info.Bits = "RSA", k.N.BitLen()
+	case *ecdsa.PublicKey:
+		info.Type, info.Bits, info.Curve = "EC", k.Curve.Params().BitSize, k.Curve.Params().Name
+	case ed25519.PublicKey:
+		info.Type, info.Bits, info.Curve = "Ed25519", 256, "Ed25519"
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if isPrivateKey(key) {
+		info.Private = true
+	}
+
+	thumb, err := JWKThumbprint(pub)
+	if err != nil {
+		return nil, err
+	}
+	info.Fingerprints.JWKThumbprint = thumb
+	if info.Type == "oct" {
+		return info, nil
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	info.Fingerprints.SSHSHA256 = ssh.FingerprintSHA256(sshPub)
+	info.Fingerprints.SSHMD5 = ssh.FingerprintLegacyMD5(sshPub)
+
+	spki, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(spki)
+	info.Fingerprints.SPKISHA256 = base64.StdEncoding.EncodeToString(sum[:])
+	info.Fingerprints.SPKIMD5 = fmt.Sprintf("%x", md5.Sum(spki))
+	return info, nil
+}
+
+// ConvertKey parses key (any format ParseKey accepts) and writes it in
+// format, one of KeyFormats. With publicOnly, a private key is reduced to
+// its public half first. The formats are:
+//
+//   - pem: PKCS#8 for private keys, SPKI for public keys
+//   - pkcs8: PKCS#8 PEM (private keys only)
+//   - pkcs1: PKCS#1 PEM (RSA only, private or public)
+//   - sec1: SEC 1 "EC PRIVATE KEY" PEM (EC private keys only)
+//   - spki: SPKI "PUBLIC KEY" PEM of the public half
+//   - der: base64 of the DER the pem format would hold
+//   - jwk: a JSON Web Key
+//   - openssh: an OpenSSH private key, or an authorized_keys line
+func ConvertKey(key, format string, publicOnly bool) (string, error) {
+	parsed, err := ParseKey(key)
+	if err != nil {
+		return "", err
+	}
+	if publicOnly {
+		parsed = PublicKey(parsed)
+	}
+	format = strings.ToLower(format)
+	if _, ok := parsed.([]byte); ok && format != "jwk" {
+		return "", fmt.Errorf("symmetric keys can only be written as a JWK")
+	}
+	private := isPrivateKey(parsed)
+
+	switch format {
+	case "pem", "der":
+		var block *pem.Block
+		if private {
+			der, err := x509.MarshalPKCS8PrivateKey(parsed)
+			if err != nil {
+				return "", err
+			}
+			block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+		} else {
+			der, err := x509.MarshalPKIXPublicKey(parsed)
+			if err != nil {
+				return "", err
+			}
+			block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+		}
+		if format == "der" {
+			return base64.StdEncoding.EncodeToString(block.Bytes), nil
+		}
+		return string(pem.EncodeToMemory(block)), nil
+
+	case "pkcs8":
+		if !private {
+			return "", fmt.Errorf("pkcs8 holds private keys; use spki for a public key")
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(parsed)
+		if err != nil {
+			return "", err
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+
+	case "pkcs1":
+		switch k := parsed.(type) {
+		case *rsa.PrivateKey:
+			return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})), nil
+		case *rsa.PublicKey:
+			return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(k)})), nil
+		}
+		return "", fmt.Errorf("pkcs1 holds RSA keys only")
+
+	case "sec1":
+		k, ok := parsed.(*ecdsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("sec1 holds EC private keys only")
+		}
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return "", err
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+
+	case "spki":
+		der, err := x509.MarshalPKIXPublicKey(PublicKey(parsed))
+		if err != nil {
+			return "", err
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+
+	case "jwk":
+		j, err := MarshalJWK(parsed)
+		if err != nil {
+			return "", err
+		}
+		out, err := json.Marshal(j)
+		return string(out), err
+
+	case "openssh":
+		if private {
+			block, err := ssh.MarshalPrivateKey(parsed, "")
+			if err != nil {
+				return "", err
+			}
+			return string(pem.EncodeToMemory(block)), nil
+		}
+		sshPub, err := ssh.NewPublicKey(parsed)
+		if err != nil {
+			return "", err
+		}
+		return string(ssh.MarshalAuthorizedKey(sshPub)), nil
+	}
+	return "", fmt.Errorf("unsupported format %q (want one of %s)", format, strings.Join(KeyFormats, ", "))
+}
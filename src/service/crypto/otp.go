@@ -0,0 +1,307 @@
+package crypto
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTPAlgorithms lists the HMAC algorithms HOTP and TOTP codes can use.
+// SHA1 is what almost every authenticator app expects.
+var OTPAlgorithms = []string{"SHA1", "SHA256", "SHA512"}
+
+// otpHashes maps OTPAlgorithms to their hash constructors.
+var otpHashes = map[string]func() hash.Hash{
+	"SHA1":   sha1.New,
+	"SHA256": sha256.New,
+	"SHA512": sha512.New,
+}
+
+// OTPOptions are the parameters shared by HOTP and TOTP codes. Zero
+// values mean the defaults: SHA1, 6 digits and a 30 second period.
+type OTPOptions struct {
+	Algorithm string
+	Digits    int
+	Period    int64
+}
+
+// normalize applies defaults and rejects unsupported parameters, returning
+// the hash constructor for the algorithm.
+func (o *OTPOptions) normalize() (func() hash.Hash, error) {
+	o.Algorithm = strings.ToUpper(strings.ReplaceAll(o.Algorithm, "-", ""))
+	if o.Algorithm == "" {
+		o.Algorithm = "SHA1"
+	}
+	newHash, ok := otpHashes[o.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("algorithm must be one of %s", strings.Join(OTPAlgorithms, ", "))
+	}
+	if o.Digits == 0 {
+		o.Digits = 6
+	}
+	if o.Digits < 6 || o.Digits > 8 {
+		return nil, fmt.Errorf("digits must be between 6 and 8")
+	}
+	if o.Period == 0 {
+		o.Period = 30
+	}
+	if o.Period < 1 || o.Period > 3600 {
+		return nil, fmt.Errorf("period must be between 1 and 3600 seconds")
+	}
+	return newHash, nil
+}
+
+// decodeOTPSecret decodes a base32 secret, ignoring case, spaces and
+// padding as authenticator apps do.
+func decodeOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("invalid secret")
+	}
+	return key, nil
+}
+
+// GenerateHOTP returns the RFC 4226 code for counter.
+func GenerateHOTP(secret string, counter uint64, opts OTPOptions) (string, error) {
+	newHash, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generateHOTP(key, counter, opts.Digits, newHash), nil
+}
+
+// VerifyHOTP checks code against counter and the lookAhead counters after
+// it, the RFC 4226 resynchronization window. It returns the counter that
+// matched, so the caller can store matched+1 as the next expected counter.
+// Every candidate is compared in constant time and all are evaluated, as
+// VerifyTOTP does.
+func VerifyHOTP(secret, code string, counter uint64, lookAhead int, opts OTPOptions) (bool, uint64, error) {
+	newHash, err := opts.normalize()
+	if err != nil {
+		return false, 0, err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return false, 0, err
+	}
+	if lookAhead < 0 || lookAhead > 100 {
+		return false, 0, fmt.Errorf("look-ahead must be between 0 and 100")
+	}
+
+	matched, at := false, uint64(0)
+	for i := 0; i <= lookAhead; i++ {
+		expected := generateHOTP(key, counter+uint64(i), opts.Digits, newHash)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 && !matched {
+			matched, at = true, counter+uint64(i)
+		}
+	}
+	return matched, at, nil
+}
+
+// TOTPAt returns the RFC 6238 code for the time step containing at.
+func TOTPAt(secret string, at time.Time, opts OTPOptions) (string, error) {
+	newHash, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generateHOTP(key, uint64(at.Unix()/opts.Period), opts.Digits, newHash), nil
+}
+
+// VerifyTOTPAt checks code against the time step containing at and window
+// steps either side of it, in constant time like VerifyTOTP.
+func VerifyTOTPAt(secret, code string, at time.Time, window int, opts OTPOptions) (bool, error) {
+	newHash, err := opts.normalize()
+	if err != nil {
+		return false, err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return false, err
+	}
+	if window < 0 || window > 10 {
+		return false, fmt.Errorf("window must be between 0 and 10")
+	}
+
+	step := at.Unix() / opts.Period
+	matched := false
+	for i := -window; i <= window; i++ {
+		expected := generateHOTP(key, uint64(step+int64(i)), opts.Digits, newHash)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			matched = true
+		}
+	}
+	return matched, nil
+}
+
+// OTPAuthKey is the content of an otpauth:// key URI, the format
+// authenticator apps scan from QR codes
+// (github.com/google/google-authenticator/wiki/Key-Uri-Format).
+// Period only applies to totp keys and Counter to hotp keys. Params keeps
+// any other query parameters, such as image.
+type OTPAuthKey struct {
+	Type      string            `json:"type"`
+	Issuer    string            `json:"issuer"`
+	Account   string            `json:"account"`
+	Secret    string            `json:"secret"`
+	Algorithm string            `json:"algorithm"`
+	Digits    int               `json:"digits"`
+	Period    int64             `json:"period,omitempty"`
+	Counter   uint64            `json:"counter"`
+	Params    map[string]string `json:"params,omitempty"`
+}
+
+// ParseOTPAuthURI parses an otpauth:// URI, applying the format's
+// defaults (SHA1, 6 digits, 30 second period) and validating the secret.
+// The issuer parameter takes precedence over an "Issuer:" label prefix.
+func ParseOTPAuthURI(raw string) (*OTPAuthKey, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI: %w", err)
+	}
+	if u.Scheme != "otpauth" {
+		return nil, fmt.Errorf("scheme must be otpauth, got %q", u.Scheme)
+	}
+	key := &OTPAuthKey{Type: strings.ToLower(u.Host)}
+	if key.Type != "totp" && key.Type != "hotp" {
+		return nil, fmt.Errorf("type must be totp or hotp, got %q", u.Host)
+	}
+
+	// A literal colon separates the issuer from the account, so either may
+	// hold an escaped one; failing that, the separator itself may be
+	// escaped.
+	label := strings.TrimPrefix(u.EscapedPath(), "/")
+	rawIssuer, rawAccount, ok := strings.Cut(label, ":")
+	if !ok {
+		if i := strings.Index(strings.ToUpper(label), "%3A"); i >= 0 {
+			rawIssuer, rawAccount = label[:i], label[i+3:]
+		} else {
+			rawIssuer, rawAccount = "", label
+		}
+	}
+	issuer, err := url.PathUnescape(rawIssuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label: %w", err)
+	}
+	account, err := url.PathUnescape(rawAccount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label: %w", err)
+	}
+	key.Issuer, key.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+
+	q := u.Query()
+	key.Secret = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(q.Get("secret"), " ", ""), "="))
+	if key.Secret == "" {
+		return nil, fmt.Errorf("secret parameter is required")
+	}
+	if _, err := decodeOTPSecret(key.Secret); err != nil {
+		return nil, err
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+
+	opts := OTPOptions{Algorithm: q.Get("algorithm")}
+	if d := q.Get("digits"); d != "" {
+		if opts.Digits, err = strconv.Atoi(d); err != nil {
+			return nil, fmt.Errorf("digits must be a number, got %q", d)
+		}
+	}
+	if key.Type == "totp" {
+		if p := q.Get("period"); p != "" {
+			if opts.Period, err = strconv.ParseInt(p, 10, 64); err != nil {
+				return nil, fmt.Errorf("period must be a number, got %q", p)
+			}
+		}
+	} else {
+		c := q.Get("counter")
+		if c == "" {
+			return nil, fmt.Errorf("counter parameter is required for hotp")
+		}
+		if key.Counter, err = strconv.ParseUint(c, 10, 64); err != nil {
+			return nil, fmt.Errorf("counter must be a number, got %q", c)
+		}
+	}
+	if _, err := opts.normalize(); err != nil {
+		return nil, err
+	}
+	key.Algorithm, key.Digits = opts.Algorithm, opts.Digits
+	if key.Type == "totp" {
+		key.Period = opts.Period
+	}
+
+	for name, values := range q {
+		switch name {
+		case "secret", "issuer", "algorithm", "digits", "period", "counter":
+			continue
+		}
+		if key.Params == nil {
+			key.Params = map[string]string{}
+		}
+		key.Params[name] = values[0]
+	}
+	return key, nil
+}
+
+// URI renders k as an otpauth:// URI. The label is "Issuer:Account" with
+// each part percent-encoded, and the issuer is repeated as a parameter as
+// the format recommends.
+func (k OTPAuthKey) URI() string {
+	typ := strings.ToLower(k.Type)
+	if typ == "" {
+		typ = "totp"
+	}
+	label := otpLabelEscape(k.Account)
+	if k.Issuer != "" {
+		label = otpLabelEscape(k.Issuer) + ":" + label
+	}
+
+	params := []string{"secret=" + url.QueryEscape(k.Secret)}
+	if k.Issuer != "" {
+		// Spaces as %20 rather than "+", which some authenticator apps
+		// show literally.
+		params = append(params, "issuer="+strings.ReplaceAll(url.QueryEscape(k.Issuer), "+", "%20"))
+	}
+	if k.Algorithm != "" {
+		params = append(params, "algorithm="+url.QueryEscape(strings.ToUpper(k.Algorithm)))
+	}
+	if k.Digits != 0 {
+		params = append(params, "digits="+strconv.Itoa(k.Digits))
+	}
+	if typ == "hotp" {
+		params = append(params, "counter="+strconv.FormatUint(k.Counter, 10))
+	} else if k.Period != 0 {
+		params = append(params, "period="+strconv.FormatInt(k.Period, 10))
+	}
+	extra := url.Values{}
+	for name, value := range k.Params {
+		extra.Set(name, value)
+	}
+	if len(extra) > 0 {
+		params = append(params, extra.Encode())
+	}
+	return "otpauth://" + typ + "/" + label + "?" + strings.Join(params, "&")
+}
+
+// otpLabelEscape percent-encodes one part of an otpauth label, including
+// any colon so it cannot be read back as the issuer separator.
+func otpLabelEscape(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), ":", "%3A")
+}
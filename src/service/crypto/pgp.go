@@ -5,9 +5,13 @@ import (
 	stdcrypto "crypto"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
 	"github.com/ProtonMail/go-crypto/openpgp/packet"
 )
 
@@ -21,6 +25,13 @@ func pgpConfig() *packet.Config {
 // GeneratePGPKeys generates an OpenPGP keypair for the given name and email
 // and returns the ASCII-armored public and private keys.
 func GeneratePGPKeys(name, email string) (string, string, error) {
+	return GeneratePGPKeysWithPassphrase(name, email, "")
+}
+
+// GeneratePGPKeysWithPassphrase is GeneratePGPKeys with the private key
+// (and its subkeys) encrypted under passphrase. An empty passphrase leaves
+// the key unprotected.
+func GeneratePGPKeysWithPassphrase(name, email, passphrase string) (string, string, error) {
 	if name == "" || email == "" {
 		return "", "", fmt.Errorf("name and email are required")
 	}
@@ -42,12 +53,24 @@ func GeneratePGPKeys(name, email string) (string, string, error) {
 		return "", "", fmt.Errorf("failed to close public key armor: %w", err)
 	}
 
+	if passphrase != "" {
+		if err := entity.EncryptPrivateKeys([]byte(passphrase), pgpConfig()); err != nil {
+			return "", "", fmt.Errorf("failed to protect private key: %w", err)
+		}
+	}
+
 	var privBuf bytes.Buffer
 	privWriter, err := armor.Encode(&privBuf, openpgp.PrivateKeyType, nil)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to armor private key: %w", err)
 	}
-	if err := entity.SerializePrivate(privWriter, nil); err != nil {
+	// SerializePrivate re-signs the identities, which an encrypted key
+	// cannot do; the self-signatures NewEntity made are kept instead.
+	serialize := entity.SerializePrivate
+	if passphrase != "" {
+		serialize = entity.SerializePrivateWithoutSigning
+	}
+	if err := serialize(privWriter, nil); err != nil {
 		return "", "", fmt.Errorf("failed to serialize private key: %w", err)
 	}
 	if err := privWriter.Close(); err != nil {
@@ -60,12 +83,16 @@ func GeneratePGPKeys(name, email string) (string, string, error) {
 // PGPEncrypt encrypts plaintext to an ASCII-armored PGP message for the
 // holder of the given ASCII-armored public key.
 func PGPEncrypt(plaintext, armoredPublicKey string) (string, error) {
-	entityList, err := openpgp.ReadArmoredKeyRing(bytes.NewReader([]byte(armoredPublicKey)))
+	return PGPEncryptTo(plaintext, []string{armoredPublicKey})
+}
+
+// PGPEncryptTo encrypts plaintext to an ASCII-armored PGP message that
+// any one of the holders of armoredPublicKeys can decrypt. Each entry may
+// itself hold several keys.
+func PGPEncryptTo(plaintext string, armoredPublicKeys []string) (string, error) {
+	entityList, err := readPGPPublicKeys(armoredPublicKeys)
 	if err != nil {
-		return "", fmt.Errorf("failed to read public key: %w", err)
-	}
-	if len(entityList) == 0 {
-		return "", fmt.Errorf("no public key found in input")
+		return "", err
 	}
 
 	var outBuf bytes.Buffer
@@ -94,13 +121,17 @@ func PGPEncrypt(plaintext, armoredPublicKey string) (string, error) {
 // PGPDecrypt decrypts an ASCII-armored PGP message using the given
 // ASCII-armored private key.
 func PGPDecrypt(armoredCiphertext, armoredPrivateKey string) (string, error) {
-	entityList, err := openpgp.ReadArmoredKeyRing(bytes.NewReader([]byte(armoredPrivateKey)))
+	return PGPDecryptWithPassphrase(armoredCiphertext, armoredPrivateKey, "")
+}
+
+// PGPDecryptWithPassphrase is PGPDecrypt for a private key that may be
+// protected by passphrase.
+func PGPDecryptWithPassphrase(armoredCiphertext, armoredPrivateKey, passphrase string) (string, error) {
+	entity, err := readPGPPrivateKey(armoredPrivateKey, passphrase)
 	if err != nil {
-		return "", fmt.Errorf("failed to read private key: %w", err)
-	}
-	if len(entityList) == 0 {
-		return "", fmt.Errorf("no private key found in input")
+		return "", err
 	}
+	entityList := openpgp.EntityList{entity}
 
 	block, err := armor.Decode(bytes.NewReader([]byte(armoredCiphertext)))
 	if err != nil {
@@ -119,3 +150,181 @@ func PGPDecrypt(armoredCiphertext, armoredPrivateKey string) (string, error) {
 
 	return string(plaintext), nil
 }
+
+// PGPSign returns an ASCII-armored detached signature of message made with
+// the given ASCII-armored private key, unlocked with passphrase if it is
+// protected. The signature covers message byte for byte, so it suits
+// binary artifacts as well as text.
+func PGPSign(message []byte, armoredPrivateKey, passphrase string) (string, error) {
+	entity, err := readPGPPrivateKey(armoredPrivateKey, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	var sigBuf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sigBuf, entity, bytes.NewReader(message), pgpConfig()); err != nil {
+		return "", fmt.Errorf("failed to sign message: %w", err)
+	}
+	return sigBuf.String(), nil
+}
+
+// PGPClearsign returns message as a cleartext-signed PGP message ("BEGIN
+// PGP SIGNED MESSAGE"), readable without PGP and verifiable by PGPVerify.
+func PGPClearsign(message, armoredPrivateKey, passphrase string) (string, error) {
+	entity, err := readPGPPrivateKey(armoredPrivateKey, passphrase)
+	if err != nil {
+		return "", err
+	}
+	key, ok := entity.SigningKey(time.Now())
+	if !ok || key.PrivateKey == nil {
+		return "", fmt.Errorf("private key has no usable signing key")
+	}
+
+	var outBuf bytes.Buffer
+	plaintextWriter, err := clearsign.Encode(&outBuf, key.PrivateKey, pgpConfig())
+	if err != nil {
+		return "", fmt.Errorf("failed to clearsign message: %w", err)
+	}
+	if _, err := plaintextWriter.Write([]byte(message)); err != nil {
+		return "", fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := plaintextWriter.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize signature: %w", err)
+	}
+	return outBuf.String(), nil
+}
+
+// PGPVerification is the result of PGPVerify. KeyID and CreatedAt come
+// from the signature itself, so they are reported even when it does not
+// verify; Signer and Fingerprint are only known once a given key matches.
+type PGPVerification struct {
+	Valid         bool   `json:"valid"`
+	KeyID         string `json:"key_id"`
+	Fingerprint   string `json:"fingerprint,omitempty"`
+	Signer        string `json:"signer,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	HashAlgorithm string `json:"hash_algorithm"`
+	// Message is the signed text of a clearsigned message.
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PGPVerify checks a PGP signature against armoredPublicKeys. With a
+// detached armoredSignature, message is the signed data; with an empty
+// armoredSignature, message must be a clearsigned message. Malformed input
+// is an error; a signature that does not verify, or was made by a key not
+// among armoredPublicKeys, is Valid=false with the reason in Error.
+func PGPVerify(message []byte, armoredSignature string, armoredPublicKeys []string) (*PGPVerification, error) {
+	keyring, err := readPGPPublicKeys(armoredPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if armoredSignature == "" {
+		block, _ := clearsign.Decode(message)
+		if block == nil {
+			return nil, fmt.Errorf("message is not clearsigned; a detached signature is required")
+		}
+		result, err := verifyPGPSignature(keyring, block.Bytes, block.ArmoredSignature.Body)
+		if err != nil {
+			return nil, err
+		}
+		result.Message = string(block.Plaintext)
+		return result, nil
+	}
+
+	block, err := armor.Decode(strings.NewReader(armoredSignature))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode armored signature: %w", err)
+	}
+	if block.Type != openpgp.SignatureType {
+		return nil, fmt.Errorf("expected a %s block, got %s", openpgp.SignatureType, block.Type)
+	}
+	return verifyPGPSignature(keyring, message, block.Body)
+}
+
+// verifyPGPSignature checks the signature packet read from sigBody over
+// signed.
+func verifyPGPSignature(keyring openpgp.EntityList, signed []byte, sigBody io.Reader) (*PGPVerification, error) {
+	sigData, err := io.ReadAll(sigBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+	p, err := packet.Read(bytes.NewReader(sigData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signature: %w", err)
+	}
+	sig, ok := p.(*packet.Signature)
+	if !ok || sig.IssuerKeyId == nil {
+		return nil, fmt.Errorf("input is not a PGP signature with an issuer")
+	}
+
+	result := &PGPVerification{
+		KeyID:         fmt.Sprintf("%016X", *sig.IssuerKeyId),
+		CreatedAt:     sig.CreationTime.UTC().Format(time.RFC3339),
+		HashAlgorithm: sig.Hash.String(),
+	}
+	_, signer, err := openpgp.VerifyDetachedSignature(keyring, bytes.NewReader(signed), bytes.NewReader(sigData), pgpConfig())
+	if signer != nil {
+		if id := signer.PrimaryIdentity(); id != nil {
+			result.Signer = id.Name
+		}
+		for _, key := range keyring.KeysById(*sig.IssuerKeyId) {
+			result.Fingerprint = fmt.Sprintf("%X", key.PublicKey.Fingerprint)
+			break
+		}
+	}
+	switch {
+	case err == pgperrors.ErrUnknownIssuer:
+		result.Error = fmt.Sprintf("signed by key %s, which is not among the given public keys", result.KeyID)
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		result.Valid = true
+	}
+	return result, nil
+}
+
+// readPGPPublicKeys reads every key from each of armoredKeys into one
+// keyring. Private keys are accepted for their public halves.
+func readPGPPublicKeys(armoredKeys []string) (openpgp.EntityList, error) {
+	var keyring openpgp.EntityList
+	for i, armored := range armoredKeys {
+		entityList, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key %d: %w", i, err)
+		}
+		keyring = append(keyring, entityList...)
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("no public key found in input")
+	}
+	return keyring, nil
+}
+
+// readPGPPrivateKey reads the first key of an ASCII-armored private key
+// block, unlocking it with passphrase if it is protected.
+func readPGPPrivateKey(armoredPrivateKey, passphrase string) (*openpgp.Entity, error) {
+	entityList, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredPrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	if len(entityList) == 0 || entityList[0].PrivateKey == nil {
+		return nil, fmt.Errorf("no private key found in input")
+	}
+	entity := entityList[0]
+
+	encrypted := entity.PrivateKey.Encrypted
+	for _, sub := range entity.Subkeys {
+		encrypted = encrypted || (sub.PrivateKey != nil && sub.PrivateKey.Encrypted)
+	}
+	if encrypted {
+		if passphrase == "" {
+			return nil, fmt.Errorf("private key is passphrase-protected; a passphrase is required")
+		}
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("failed to unlock private key: incorrect passphrase")
+		}
+	}
+	return entity, nil
+}
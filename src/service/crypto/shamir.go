@@ -0,0 +1,281 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ShamirEncodings lists the share encodings EncodeShamirShare supports.
+// "mnemonic" spells a share with words from the English BIP39 wordlist,
+// which is easier to write down and read back than hex.
+var ShamirEncodings = []string{"hex", "base64", "mnemonic"}
+
+// Limits on ShamirSplit. Share x-coordinates are single bytes, so there
+// can be at most 255 shares.
+const (
+	ShamirMaxShares     = 255
+	ShamirMaxSecretSize = 4096
+)
+
+// Shamir share layout, all integers big-endian:
+//
+//	version(1) | id(4) | threshold(1) | x(1) | y(len(secret)+4) | checksum(4)
+//
+// id is random per split, so shares from different splits are told apart
+// before interpolation. y interpolates to the secret followed by the first
+// four bytes of its SHA-256, which ShamirCombine checks. checksum is the
+// first four bytes of the SHA-256 of everything before it and catches a
+// mistyped share.
+const (
+	shamirVersion    = 1
+	shamirHeaderSize = 7
+	shamirCheckSize  = 4
+)
+
+// ShamirShareInfo describes a decoded share, or with no Index the split
+// ShamirCombine recovered.
+type ShamirShareInfo struct {
+	ID        string `json:"id"`
+	Threshold int    `json:"threshold"`
+	Index     int    `json:"index,omitempty"`
+}
+
+// gf256Exp and gf256Log are exponent and logarithm tables for GF(2^8)
+// with the AES polynomial x^8+x^4+x^3+x+1 and generator 3.
+var gf256Exp, gf256Log = func() (exp [510]byte, log [256]byte) {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = x, x
+		log[x] = byte(i)
+		// Multiply by 3: x*2 (with reduction) xor x.
+		hi := x & 0x80
+		x2 := x << 1
+		if hi != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	return exp, log
+}()
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+// ShamirSplit splits secret into shares shares, any threshold of which
+// recover it with ShamirCombine. Every coefficient is uniformly random,
+// zero included, which is what makes fewer shares reveal nothing: a
+// nonzero leading one would let threshold-1 shares rule out a value per
+// byte. Shares are raw bytes, see EncodeShamirShare.
+func ShamirSplit(secret []byte, shares, threshold int) ([][]byte, error) {
+	if len(secret) == 0 || len(secret) > ShamirMaxSecretSize {
+		return nil, fmt.Errorf("secret must be 1-%d bytes", ShamirMaxSecretSize)
+	}
+	if shares < 2 || shares > ShamirMaxShares {
+		return nil, fmt.Errorf("shares must be between 2 and %d", ShamirMaxShares)
+	}
+	if threshold < 2 || threshold > shares {
+		return nil, fmt.Errorf("threshold must be between 2 and the number of shares")
+	}
+
+	sum := sha256.Sum256(secret)
+	payload := append(append([]byte{}, secret...), sum[:shamirCheckSize]...)
+	id, err := RandomBytes(4)
+	if err != nil {
+		return nil, err
+	}
+	coeffs, err := RandomBytes(len(payload) * (threshold - 1))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, shares)
+	for i := range out {
+		x := byte(i + 1)
+		share := make([]byte, 0, shamirHeaderSize+len(payload)+shamirCheckSize)
+		share = append(share, shamirVersion)
+		share = append(share, id...)
+		share = append(share, byte(threshold), x)
+		for b, c0 := range payload {
+			// Horner's rule over c0 + c1*x + ... + c(t-1)*x^(t-1).
+			row := coeffs[b*(threshold-1) : (b+1)*(threshold-1)]
+			y := byte(0)
+			for k := len(row) - 1; k >= 0; k-- {
+				y = gf256Mul(y, x) ^ row[k]
+			}
+			share = append(share, gf256Mul(y, x)^c0)
+		}
+		check := sha256.Sum256(share)
+		out[i] = append(share, check[:shamirCheckSize]...)
+	}
+	return out, nil
+}
+
+// InspectShamirShare checks a share's version and checksum and reports
+// its split id, threshold and index.
+func InspectShamirShare(share []byte) (*ShamirShareInfo, error) {
+	// At least one secret byte plus the embedded digest.
+	if len(share) < shamirHeaderSize+1+2*shamirCheckSize {
+		return nil, fmt.Errorf("too short")
+	}
+	if share[0] != shamirVersion {
+		return nil, fmt.Errorf("unsupported version %d", share[0])
+	}
+	body := share[:len(share)-shamirCheckSize]
+	check := sha256.Sum256(body)
+	if !bytes.Equal(check[:shamirCheckSize], share[len(body):]) {
+		return nil, fmt.Errorf("checksum mismatch (mistyped or corrupted)")
+	}
+	if share[6] == 0 || share[5] < 2 {
+		return nil, fmt.Errorf("malformed header")
+	}
+	return &ShamirShareInfo{ID: hex.EncodeToString(share[1:5]), Threshold: int(share[5]), Index: int(share[6])}, nil
+}
+
+// ShamirCombine recovers the secret from at least threshold shares of
+// one split. Every share's checksum is verified, shares must agree on
+// the split id, threshold and length, and the recovered secret must match
+// the digest embedded at split time, so a wrong or tampered share is an
+// error rather than a silently wrong secret.
+func ShamirCombine(shares [][]byte) ([]byte, *ShamirShareInfo, error) {
+	if len(shares) == 0 {
+		return nil, nil, fmt.Errorf("no shares given")
+	}
+	var first *ShamirShareInfo
+	seen := map[int]bool{}
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		info, err := InspectShamirShare(share)
+		if err != nil {
+			return nil, nil, fmt.Errorf("share %d: %w", i+1, err)
+		}
+		if first == nil {
+			first = info
+		} else if info.ID != first.ID || info.Threshold != first.Threshold || len(share) != len(shares[0]) {
+			return nil, nil, fmt.Errorf("share %d belongs to a different split", i+1)
+		}
+		if seen[info.Index] {
+			return nil, nil, fmt.Errorf("share %d duplicates share index %d", i+1, info.Index)
+		}
+		seen[info.Index] = true
+		xs[i] = byte(info.Index)
+	}
+	if len(shares) < first.Threshold {
+		return nil, nil, fmt.Errorf("need at least %d shares, got %d", first.Threshold, len(shares))
+	}
+
+	// Lagrange interpolation at x = 0; subtraction in GF(2^8) is xor.
+	weights := make([]byte, len(xs))
+	for i, xi := range xs {
+		w := byte(1)
+		for j, xj := range xs {
+			if i != j {
+				w = gf256Mul(w, gf256Div(xj, xj^xi))
+			}
+		}
+		weights[i] = w
+	}
+	n := len(shares[0]) - shamirHeaderSize - shamirCheckSize
+	payload := make([]byte, n)
+	for b := range payload {
+		var v byte
+		for i, share := range shares {
+			v ^= gf256Mul(share[shamirHeaderSize+b], weights[i])
+		}
+		payload[b] = v
+	}
+
+	secret, digest := payload[:n-shamirCheckSize], payload[n-shamirCheckSize:]
+	sum := sha256.Sum256(secret)
+	if !bytes.Equal(sum[:shamirCheckSize], digest) {
+		return nil, nil, fmt.Errorf("recovered secret failed its integrity check (a share was altered)")
+	}
+	first.Index = 0
+	return secret, first, nil
+}
+
+// EncodeShamirShare renders a share as hex, base64 or mnemonic words. A
+// mnemonic packs a 2-byte length and the share into 11-bit groups, one
+// English BIP39 word each; unlike a BIP39 seed phrase it has no
+// checksum word of its own, since the share carries one.
+func EncodeShamirShare(share []byte, encoding string) (string, error) {
+	switch encoding {
+	case "hex":
+		return hex.EncodeToString(share), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(share), nil
+	case "mnemonic":
+		data := binary.BigEndian.AppendUint16(nil, uint16(len(share)))
+		data = append(data, share...)
+		list := loadWordlists()["english"]
+		words := make([]string, (len(data)*8+10)/11)
+		for i := range words {
+			idx := 0
+			for b := i * 11; b < i*11+11; b++ {
+				bit := 0
+				if b < len(data)*8 {
+					bit = int(data[b/8] >> (7 - b%8) & 1)
+				}
+				idx = idx<<1 | bit
+			}
+			words[i] = list.words[idx]
+		}
+		return strings.Join(words, " "), nil
+	}
+	return "", fmt.Errorf("encoding must be one of %s", strings.Join(ShamirEncodings, ", "))
+}
+
+// DecodeShamirShare parses a share encoded by EncodeShamirShare.
+func DecodeShamirShare(s, encoding string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	switch encoding {
+	case "hex":
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("not valid hex")
+		}
+		return b, nil
+	case "base64":
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("not valid base64")
+		}
+		return b, nil
+	case "mnemonic":
+		list := loadWordlists()["english"]
+		words := strings.Fields(strings.ToLower(s))
+		data := make([]byte, len(words)*11/8)
+		for i, w := range words {
+			idx, ok := list.index[w]
+			if !ok {
+				return nil, fmt.Errorf("word %d (%q) is not in the english wordlist", i+1, w)
+			}
+			for b := 0; b < 11; b++ {
+				pos := i*11 + b
+				if idx>>(10-b)&1 == 1 && pos/8 < len(data) {
+					data[pos/8] |= 1 << (7 - pos%8)
+				}
+			}
+		}
+		if len(data) < 2 || int(binary.BigEndian.Uint16(data))+2 > len(data) {
+			return nil, fmt.Errorf("mnemonic is too short")
+		}
+		return data[2 : 2+int(binary.BigEndian.Uint16(data))], nil
+	}
+	return nil, fmt.Errorf("encoding must be one of %s", strings.Join(ShamirEncodings, ", "))
+}
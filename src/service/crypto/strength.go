@@ -0,0 +1,1177 @@
+package crypto
+
+import (
+	"embed"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// StrengthMaxLength is how many characters of a password EstimatePassword
+// analyses. Matching is quadratic in the length, and anything longer is
+// already far beyond every attacker model.
+const StrengthMaxLength = 100
+
+// PasswordDictionaries lists the embedded frequency lists EstimatePassword
+// matches against, most common entry first in each. They are the lists
+// from zxcvbn (github.com/dropbox/zxcvbn, MIT licensed), one word per
+// line; english and surnames are cut to their 30000 and 10000 most
+// frequent entries as zxcvbn does. Caller-supplied user inputs are
+// matched as a sixth dictionary, "user_inputs".
+var PasswordDictionaries = []string{"passwords", "english", "female_names", "male_names", "surnames"}
+
+//go:embed dictionaries/*.txt
+var dictionaryFiles embed.FS
+
+// loadDictionaries parses every embedded dictionary into a word -> rank
+// map once, on first use.
+var loadDictionaries = sync.OnceValue(func() map[string]map[string]int {
+	dicts := make(map[string]map[string]int, len(PasswordDictionaries))
+	for _, name := range PasswordDictionaries {
+		data, err := dictionaryFiles.ReadFile("dictionaries/" + name + ".txt")
+		if err != nil {
+			panic("crypto: missing password dictionary " + name)
+		}
+		dicts[name] = rankedDictionary(strings.Fields(string(data)))
+	}
+	return dicts
+})
+
+func rankedDictionary(words []string) map[string]int {
+	ranked := make(map[string]int, len(words))
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if _, ok := ranked[w]; !ok && w != "" {
+			ranked[w] = i + 1
+		}
+	}
+	return ranked
+}
+
+// PasswordMatch is one piece of a password EstimatePassword recognised:
+// a dictionary word, keyboard walk, repeat, sequence, year, date, or a
+// bruteforce run between them. Start and End are inclusive character
+// offsets; only the fields of the match's pattern are set.
+type PasswordMatch struct {
+	Pattern      string  `json:"pattern"`
+	Start        int     `json:"start"`
+	End          int     `json:"end"`
+	Token        string  `json:"token"`
+	Guesses      float64 `json:"guesses"`
+	GuessesLog10 float64 `json:"guesses_log10"`
+
+	// dictionary
+	Dictionary  string `json:"dictionary,omitempty"`
+	MatchedWord string `json:"matched_word,omitempty"`
+	Rank        int    `json:"rank,omitempty"`
+	Reversed    bool   `json:"reversed,omitempty"`
+	L33t        bool   `json:"l33t,omitempty"`
+	L33tSubs    string `json:"l33t_subs,omitempty"`
+
+	// spatial
+	Graph        string `json:"graph,omitempty"`
+	Turns        int    `json:"turns,omitempty"`
+	ShiftedCount int    `json:"shifted_count,omitempty"`
+
+	// repeat
+	BaseToken   string `json:"base_token,omitempty"`
+	RepeatCount int    `json:"repeat_count,omitempty"`
+
+	// sequence
+	SequenceName string `json:"sequence_name,omitempty"`
+	Direction    string `json:"direction,omitempty"`
+
+	// date and year
+	Year      int    `json:"year,omitempty"`
+	Month     int    `json:"month,omitempty"`
+	Day       int    `json:"day,omitempty"`
+	Separator string `json:"separator,omitempty"`
+
+	sub         map[rune]rune
+	baseGuesses float64
+	ascending   bool
+}
+
+// PasswordCrackTime is how long an attacker needs, on average, to reach
+// a password at one guessing rate.
+type PasswordCrackTime struct {
+	Seconds float64 `json:"seconds"`
+	Display string  `json:"display"`
+}
+
+// PasswordCrackTimes estimates crack time under four attacker models: an
+// online attack against a rate-limited or an unthrottled service, and an
+// offline attack on a slow (bcrypt, scrypt, Argon2) or a fast (unsalted
+// SHA-1, MD5) hash.
+type PasswordCrackTimes struct {
+	OnlineThrottling   PasswordCrackTime `json:"online_throttling_100_per_hour"`
+	OnlineNoThrottling PasswordCrackTime `json:"online_no_throttling_10_per_second"`
+	OfflineSlowHashing PasswordCrackTime `json:"offline_slow_hashing_1e4_per_second"`
+	OfflineFastHashing PasswordCrackTime `json:"offline_fast_hashing_1e10_per_second"`
+}
+
+// PasswordFeedback explains a weak password: what makes it guessable and
+// how to improve it. Both are empty for passwords scoring 4 or 5.
+type PasswordFeedback struct {
+	Warning     string   `json:"warning"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// PasswordEstimate is EstimatePassword's result.
+type PasswordEstimate struct {
+	Score        int                `json:"score"`
+	Strength     string             `json:"strength"`
+	Guesses      float64            `json:"guesses"`
+	GuessesLog10 float64            `json:"guesses_log10"`
+	CrackTimes   PasswordCrackTimes `json:"crack_times"`
+	Feedback     PasswordFeedback   `json:"feedback"`
+	Sequence     []PasswordMatch    `json:"sequence"`
+}
+
+// passwordStrengths names scores 1-5.
+var passwordStrengths = []string{"weak", "fair", "good", "strong", "very_strong"}
+
+// EstimatePassword estimates how many guesses an attacker needs for
+// password, following zxcvbn: it finds every dictionary word (also
+// reversed or with l33t substitutions), keyboard walk, repeat, sequence,
+// year and date in the password, then picks the combination of them and
+// bruteforce runs that is cheapest to guess. userInputs are words the
+// attacker would try first, such as the account's name or email address.
+//
+// Score is 1-5 for fewer than 10^3, 10^6, 10^8, 10^10 guesses and more.
+func EstimatePassword(password string, userInputs ...string) *PasswordEstimate {
+	pw := []rune(password)
+	if len(pw) > StrengthMaxLength {
+		pw = pw[:StrengthMaxLength]
+	}
+	m := newStrengthMatcher(userInputs)
+	guesses, seq := m.mostGuessable(pw, m.omnimatch(pw), false)
+	guesses = min(guesses, math.MaxFloat64)
+
+	score := passwordScore(guesses)
+	est := &PasswordEstimate{
+		Score:        score,
+		Strength:     passwordStrengths[score-1],
+		Guesses:      guesses,
+		GuessesLog10: math.Log10(guesses),
+		CrackTimes: PasswordCrackTimes{
+			OnlineThrottling:   crackTime(guesses / (100.0 / 3600)),
+			OnlineNoThrottling: crackTime(guesses / 10),
+			OfflineSlowHashing: crackTime(guesses / 1e4),
+			OfflineFastHashing: crackTime(guesses / 1e10),
+		},
+		Feedback: passwordFeedback(score, seq),
+		Sequence: make([]PasswordMatch, len(seq)),
+	}
+	for i, match := range seq {
+		est.Sequence[i] = *match
+	}
+	return est
+}
+
+func passwordScore(guesses float64) int {
+	// The small delta keeps an exact power of ten from scoring a band up
+	// because of rounding in the product of match guesses.
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 1
+	case guesses < 1e6+delta:
+		return 2
+	case guesses < 1e8+delta:
+		return 3
+	case guesses < 1e10+delta:
+		return 4
+	}
+	return 5
+}
+
+func crackTime(seconds float64) PasswordCrackTime {
+	const (
+		minute  = 60
+		hour    = minute * 60
+		day     = hour * 24
+		month   = day * 31
+		year    = month * 12
+		century = year * 100
+	)
+	units := []struct {
+		size float64
+		name string
+	}{{1, "second"}, {minute, "minute"}, {hour, "hour"}, {day, "day"}, {month, "month"}, {year, "year"}}
+
+	display := "centuries"
+	switch {
+	case seconds < 1:
+		display = "less than a second"
+	case seconds < century:
+		for i := len(units) - 1; i >= 0; i-- {
+			if seconds >= units[i].size {
+				n := math.Round(seconds / units[i].size)
+				display = fmt.Sprintf("%.0f %s", n, units[i].name)
+				if n != 1 {
+					display += "s"
+				}
+				break
+			}
+		}
+	}
+	return PasswordCrackTime{Seconds: seconds, Display: display}
+}
+
+// strengthMatcher holds the dictionaries one estimate matches against:
+// the embedded lists plus the caller's user inputs.
+type strengthMatcher struct {
+	dicts     map[string]map[string]int
+	names     []string
+	refYear   int
+	graphs    map[string]adjacencyGraph
+	graphList []string
+}
+
+func newStrengthMatcher(userInputs []string) *strengthMatcher {
+	m := &strengthMatcher{
+		dicts:     loadDictionaries(),
+		names:     PasswordDictionaries,
+		refYear:   time.Now().Year(),
+		graphs:    loadAdjacencyGraphs(),
+		graphList: []string{"qwerty", "dvorak", "keypad"},
+	}
+	if len(userInputs) > 0 {
+		dicts := make(map[string]map[string]int, len(m.dicts)+1)
+		for name, d := range m.dicts {
+			dicts[name] = d
+		}
+		dicts["user_inputs"] = rankedDictionary(userInputs)
+		m.dicts = dicts
+		m.names = append(slices.Clone(m.names), "user_inputs")
+	}
+	return m
+}
+
+// omnimatch returns every match of every pattern in pw, ordered by
+// position.
+func (m *strengthMatcher) omnimatch(pw []rune) []*PasswordMatch {
+	var matches []*PasswordMatch
+	matches = append(matches, m.dictionaryMatch(pw)...)
+	matches = append(matches, m.reverseDictionaryMatch(pw)...)
+	matches = append(matches, m.l33tMatch(pw)...)
+	matches = append(matches, m.spatialMatch(pw)...)
+	matches = append(matches, m.repeatMatch(pw)...)
+	matches = append(matches, sequenceMatch(pw)...)
+	matches = append(matches, m.yearMatch(pw)...)
+	matches = append(matches, m.dateMatch(pw)...)
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Start != matches[b].Start {
+			return matches[a].Start < matches[b].Start
+		}
+		return matches[a].End < matches[b].End
+	})
+	return matches
+}
+
+func lowerRunes(pw []rune) []rune {
+	lower := make([]rune, len(pw))
+	for i, r := range pw {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// dictionaryMatch finds every substring of pw that is a dictionary word,
+// ignoring case.
+func (m *strengthMatcher) dictionaryMatch(pw []rune) []*PasswordMatch {
+	var matches []*PasswordMatch
+	lower := lowerRunes(pw)
+	for _, name := range m.names {
+		dict := m.dicts[name]
+		for i := range lower {
+			for j := i; j < len(lower); j++ {
+				word := string(lower[i : j+1])
+				if rank, ok := dict[word]; ok {
+					matches = append(matches, &PasswordMatch{
+						Pattern: "dictionary", Start: i, End: j, Token: string(pw[i : j+1]),
+						Dictionary: name, MatchedWord: word, Rank: rank,
+					})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// reverseDictionaryMatch finds dictionary words spelled backwards.
+func (m *strengthMatcher) reverseDictionaryMatch(pw []rune) []*PasswordMatch {
+	reversed := slices.Clone(pw)
+	slices.Reverse(reversed)
+	matches := m.dictionaryMatch(reversed)
+	for _, match := range matches {
+		token := []rune(match.Token)
+		slices.Reverse(token)
+		match.Token = string(token)
+		match.Reversed = true
+		match.Start, match.End = len(pw)-1-match.End, len(pw)-1-match.Start
+	}
+	return matches
+}
+
+// l33tTable lists the characters commonly substituted for each letter.
+var l33tTable = map[rune]string{
+	'a': "4@", 'b': "8", 'c': "({[<", 'e': "3", 'g': "69", 'i': "1!|",
+	'l': "1|7", 'o': "0", 's': "$5", 't': "+7", 'x': "%", 'z': "2",
+}
+
+// l33tMatch finds dictionary words with l33t substitutions, trying every
+// reading of characters that stand in for more than one letter.
+func (m *strengthMatcher) l33tMatch(pw []rune) []*PasswordMatch {
+	// The letters each substitution character in pw could stand for.
+	candidates := map[rune][]rune{}
+	for letter, subs := range l33tTable {
+		for _, c := range subs {
+			if slices.Contains(pw, c) {
+				candidates[c] = append(candidates[c], letter)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	chars := make([]rune, 0, len(candidates))
+	for c := range candidates {
+		chars = append(chars, c)
+		slices.Sort(candidates[c])
+	}
+	slices.Sort(chars)
+
+	subs := []map[rune]rune{{}}
+	for _, c := range chars {
+		var next []map[rune]rune
+		for _, sub := range subs {
+			for _, letter := range candidates[c] {
+				s := make(map[rune]rune, len(sub)+1)
+				for k, v := range sub {
+					s[k] = v
+				}
+				s[c] = letter
+				next = append(next, s)
+			}
+		}
+		subs = next
+	}
+
+	var matches []*PasswordMatch
+	seen := map[string]bool{}
+	for _, sub := range subs {
+		translated := make([]rune, len(pw))
+		for i, r := range pw {
+			if letter, ok := sub[r]; ok {
+				r = letter
+			}
+			translated[i] = r
+		}
+		for _, match := range m.dictionaryMatch(translated) {
+			token := pw[match.Start : match.End+1]
+			if len(token) <= 1 || string(lowerRunes(token)) == match.MatchedWord {
+				continue
+			}
+			used := map[rune]rune{}
+			var display []string
+			for _, c := range chars {
+				if slices.Contains(token, c) {
+					used[c] = sub[c]
+					display = append(display, string(c)+" -> "+string(sub[c]))
+				}
+			}
+			key := fmt.Sprint(match.Start, match.End, match.Dictionary, match.MatchedWord, display)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			match.Token = string(token)
+			match.L33t = true
+			match.sub = used
+			match.L33tSubs = strings.Join(display, ", ")
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// adjacencyGraph maps each key to the keys around it, one string per
+// direction ("" where there is none) holding that key's unshifted and
+// shifted characters.
+type adjacencyGraph map[rune][]string
+
+// keyboardLayouts are drawn one row per string, each key's characters
+// unshifted then shifted. Keyboard rows are slanted: each row sits half a
+// key right of the one above, and offset is the row's first key column.
+var keyboardLayouts = []struct {
+	name    string
+	slanted bool
+	offsets []int
+	rows    []string
+}{
+	{"qwerty", true, []int{0, 1, 1, 1}, []string{
+		"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+",
+		"qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|",
+		"aA sS dD fF gG hH jJ kK lL ;: '\"",
+		"zZ xX cC vV bB nN mM ,< .> /?",
+	}},
+	{"dvorak", true, []int{0, 1, 1, 1}, []string{
+		"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) [{ ]}",
+		"'\" ,< .> pP yY fF gG cC rR lL /? =+ \\|",
+		"aA oO eE uU iI dD hH tT nN sS -_",
+		";: qQ jJ kK xX bB mM wW vV zZ",
+	}},
+	{"keypad", false, []int{1, 0, 0, 0, 1}, []string{
+		"/ * -",
+		"7 8 9 +",
+		"4 5 6",
+		"1 2 3",
+		"0 .",
+	}},
+}
+
+// loadAdjacencyGraphs builds every keyboard layout's graph once.
+var loadAdjacencyGraphs = sync.OnceValue(func() map[string]adjacencyGraph {
+	graphs := make(map[string]adjacencyGraph, len(keyboardLayouts))
+	for _, layout := range keyboardLayouts {
+		type pos struct{ x, y int }
+		keys := map[pos]string{}
+		for y, row := range layout.rows {
+			for x, key := range strings.Fields(row) {
+				keys[pos{x + layout.offsets[y], y}] = key
+			}
+		}
+		// Neighbours clockwise from the left.
+		dirs := []pos{{-1, 0}, {-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}}
+		if layout.slanted {
+			dirs = []pos{{-1, 0}, {0, -1}, {1, -1}, {1, 0}, {0, 1}, {-1, 1}}
+		}
+		graph := adjacencyGraph{}
+		for p, key := range keys {
+			adjacent := make([]string, len(dirs))
+			for i, d := range dirs {
+				adjacent[i] = keys[pos{p.x + d.x, p.y + d.y}]
+			}
+			for _, c := range key {
+				graph[c] = adjacent
+			}
+		}
+		graphs[layout.name] = graph
+	}
+	return graphs
+})
+
+// averageDegree is the mean number of neighbours of a key in graph.
+func (g adjacencyGraph) averageDegree() float64 {
+	total := 0
+	for _, adjacent := range g {
+		for _, key := range adjacent {
+			if key != "" {
+				total++
+			}
+		}
+	}
+	return float64(total) / float64(len(g))
+}
+
+// shiftedChars are the characters typed with shift on a US keyboard.
+const shiftedChars = "~!@#$%^&*()_+{}|:\"<>?QWERTYUIOPASDFGHJKLZXCVBNM"
+
+// spatialMatch finds runs of three or more adjacent keys on each layout,
+// counting direction changes and shifted characters.
+func (m *strengthMatcher) spatialMatch(pw []rune) []*PasswordMatch {
+	var matches []*PasswordMatch
+	for _, name := range m.graphList {
+		graph := m.graphs[name]
+		keyboard := name != "keypad"
+		for i := 0; i < len(pw)-1; {
+			j := i + 1
+			lastDirection, turns, shifted := -1, 0, 0
+			if keyboard && strings.ContainsRune(shiftedChars, pw[i]) {
+				shifted = 1
+			}
+			for {
+				found := false
+				if j < len(pw) {
+					for dir, adjacent := range graph[pw[j-1]] {
+						idx := strings.IndexRune(adjacent, pw[j])
+						if adjacent == "" || idx < 0 {
+							continue
+						}
+						found = true
+						if idx == 1 {
+							shifted++
+						}
+						if dir != lastDirection {
+							turns++
+							lastDirection = dir
+						}
+						break
+					}
+				}
+				if found {
+					j++
+					continue
+				}
+				if j-i > 2 {
+					matches = append(matches, &PasswordMatch{
+						Pattern: "spatial", Start: i, End: j - 1, Token: string(pw[i:j]),
+						Graph: name, Turns: turns, ShiftedCount: shifted,
+					})
+				}
+				i = j
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// repeatMatch finds a substring repeated back to back, such as "aaa" or
+// "abcabc", taking the longest run at each position. The repeated base is
+// itself scored, so "passwordpassword" costs little more than "password".
+func (m *strengthMatcher) repeatMatch(pw []rune) []*PasswordMatch {
+	var matches []*PasswordMatch
+	for i := 0; i < len(pw)-1; {
+		span, base := 0, 0
+		for l := 1; i+2*l <= len(pw); l++ {
+			n := l
+			for i+n+l <= len(pw) && slices.Equal(pw[i+n:i+n+l], pw[i:i+l]) {
+				n += l
+			}
+			if n > l && n > span {
+				span, base = n, l
+			}
+		}
+		if span == 0 {
+			i++
+			continue
+		}
+		baseRunes := pw[i : i+base]
+		baseGuesses, _ := m.mostGuessable(baseRunes, m.omnimatch(baseRunes), false)
+		matches = append(matches, &PasswordMatch{
+			Pattern: "repeat", Start: i, End: i + span - 1, Token: string(pw[i : i+span]),
+			BaseToken: string(baseRunes), RepeatCount: span / base, baseGuesses: baseGuesses,
+		})
+		i += span
+	}
+	return matches
+}
+
+// sequenceMatch finds runs of characters with a constant code point step
+// of at most 5, such as "abc", "7531" or "zyx".
+func sequenceMatch(pw []rune) []*PasswordMatch {
+	const maxDelta = 5
+	var matches []*PasswordMatch
+	update := func(i, j, delta int) {
+		if j-i <= 1 && abs(delta) != 1 {
+			return
+		}
+		if delta == 0 || abs(delta) > maxDelta {
+			return
+		}
+		token := pw[i : j+1]
+		name := "unicode"
+		switch {
+		case allRunes(token, func(r rune) bool { return r >= 'a' && r <= 'z' }):
+			name = "lower"
+		case allRunes(token, func(r rune) bool { return r >= 'A' && r <= 'Z' }):
+			name = "upper"
+		case allRunes(token, isDigit):
+			name = "digits"
+		}
+		direction := "ascending"
+		if delta < 0 {
+			direction = "descending"
+		}
+		matches = append(matches, &PasswordMatch{
+			Pattern: "sequence", Start: i, End: j, Token: string(token),
+			SequenceName: name, Direction: direction, ascending: delta > 0,
+		})
+	}
+	if len(pw) <= 1 {
+		return nil
+	}
+	i, lastDelta := 0, int(pw[1]-pw[0])
+	for k := 1; k < len(pw); k++ {
+		delta := int(pw[k] - pw[k-1])
+		if delta == lastDelta {
+			continue
+		}
+		update(i, k-1, lastDelta)
+		i, lastDelta = k-1, delta
+	}
+	update(i, len(pw)-1, lastDelta)
+	return matches
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+func allRunes(rs []rune, f func(rune) bool) bool {
+	for _, r := range rs {
+		if !f(r) {
+			return false
+		}
+	}
+	return len(rs) > 0
+}
+
+// yearMatch finds four-digit years from 1900 to 2099.
+func (m *strengthMatcher) yearMatch(pw []rune) []*PasswordMatch {
+	var matches []*PasswordMatch
+	for i := 0; i+4 <= len(pw); {
+		token := pw[i : i+4]
+		if allRunes(token, isDigit) && (string(token[:2]) == "19" || string(token[:2]) == "20") {
+			year, _ := strconv.Atoi(string(token))
+			matches = append(matches, &PasswordMatch{Pattern: "year", Start: i, End: i + 3, Token: string(token), Year: year})
+			i += 4
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// Dates are years from dateMinYear to dateMaxYear, or two-digit years.
+const (
+	dateMinYear = 1000
+	dateMaxYear = 2050
+)
+
+// dateSplits lists, by token length, where a run of digits without
+// separators can be cut into three date parts.
+var dateSplits = map[int][][2]int{
+	4: {{1, 2}, {2, 3}},
+	5: {{1, 3}, {2, 3}},
+	6: {{1, 2}, {2, 4}, {4, 5}},
+	7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+	8: {{2, 4}, {4, 6}},
+}
+
+// dateMatch finds dates written as 4-8 digits ("13091985", "1391") or
+// with a repeated separator ("13.9.85", "1985-09-13"), in any day, month
+// and year order. Dates inside a longer date are dropped.
+func (m *strengthMatcher) dateMatch(pw []rune) []*PasswordMatch {
+	var matches []*PasswordMatch
+	for i := range pw {
+		for j := i + 3; j <= i+7 && j < len(pw); j++ {
+			token := pw[i : j+1]
+			if !allRunes(token, isDigit) {
+				break
+			}
+			var best *PasswordMatch
+			for _, split := range dateSplits[len(token)] {
+				d, ok := m.mapIntsToDMY(atoi(token[:split[0]]), atoi(token[split[0]:split[1]]), atoi(token[split[1]:]))
+				if ok && (best == nil || abs(d.Year-m.refYear) < abs(best.Year-m.refYear)) {
+					best = d
+				}
+			}
+			if best != nil {
+				best.Start, best.End, best.Token = i, j, string(token)
+				matches = append(matches, best)
+			}
+		}
+		for j := i + 5; j <= i+9 && j < len(pw); j++ {
+			token := pw[i : j+1]
+			parts, sep, ok := splitSeparatedDate(token)
+			if !ok {
+				continue
+			}
+			if d, ok := m.mapIntsToDMY(parts[0], parts[1], parts[2]); ok {
+				d.Start, d.End, d.Token, d.Separator = i, j, string(token), string(sep)
+				matches = append(matches, d)
+			}
+		}
+	}
+
+	var outer []*PasswordMatch
+	for _, match := range matches {
+		contained := slices.ContainsFunc(matches, func(other *PasswordMatch) bool {
+			return other != match && other.Start <= match.Start && other.End >= match.End
+		})
+		if !contained {
+			outer = append(outer, match)
+		}
+	}
+	return outer
+}
+
+func atoi(rs []rune) int {
+	n, _ := strconv.Atoi(string(rs))
+	return n
+}
+
+// splitSeparatedDate parses 1-4 digits, a separator, 1-2 digits, the same
+// separator and 1-4 digits.
+func splitSeparatedDate(token []rune) ([3]int, rune, bool) {
+	var parts [3]int
+	digits := func(start, lo, hi int) (int, int, bool) {
+		end := start
+		for end < len(token) && isDigit(token[end]) {
+			end++
+		}
+		if n := end - start; n < lo || n > hi {
+			return 0, 0, false
+		}
+		return atoi(token[start:end]), end, true
+	}
+	isSep := func(r rune) bool { return unicode.IsSpace(r) || strings.ContainsRune("/\\_.-", r) }
+
+	var ok bool
+	var pos int
+	if parts[0], pos, ok = digits(0, 1, 4); !ok || pos >= len(token) || !isSep(token[pos]) {
+		return parts, 0, false
+	}
+	sep := token[pos]
+	if parts[1], pos, ok = digits(pos+1, 1, 2); !ok || pos >= len(token) || token[pos] != sep {
+		return parts, 0, false
+	}
+	if parts[2], pos, ok = digits(pos+1, 1, 4); !ok || pos != len(token) {
+		return parts, 0, false
+	}
+	return parts, sep, true
+}
+
+// mapIntsToDMY reads three numbers as a day, month and year in any of
+// the orders people write dates, preferring a four-digit year.
+func (m *strengthMatcher) mapIntsToDMY(a, b, c int) (*PasswordMatch, bool) {
+	if b > 31 || b <= 0 {
+		return nil, false
+	}
+	over12, over31, under1 := 0, 0, 0
+	for _, n := range []int{a, b, c} {
+		if (n > 99 && n < dateMinYear) || n > dateMaxYear {
+			return nil, false
+		}
+		if n > 31 {
+			over31++
+		}
+		if n > 12 {
+			over12++
+		}
+		if n <= 0 {
+			under1++
+		}
+	}
+	if over31 >= 2 || over12 == 3 || under1 >= 2 {
+		return nil, false
+	}
+
+	splits := [][3]int{{c, a, b}, {a, b, c}}
+	for _, s := range splits {
+		if s[0] >= dateMinYear && s[0] <= dateMaxYear {
+			day, month, ok := mapIntsToDM(s[1], s[2])
+			if !ok {
+				return nil, false
+			}
+			return &PasswordMatch{Pattern: "date", Year: s[0], Month: month, Day: day}, true
+		}
+	}
+	for _, s := range splits {
+		if day, month, ok := mapIntsToDM(s[1], s[2]); ok {
+			year := s[0]
+			switch {
+			case year > 99:
+			case year > 50:
+				year += 1900
+			default:
+				year += 2000
+			}
+			return &PasswordMatch{Pattern: "date", Year: year, Month: month, Day: day}, true
+		}
+	}
+	return nil, false
+}
+
+func mapIntsToDM(a, b int) (day, month int, ok bool) {
+	for _, dm := range [][2]int{{a, b}, {b, a}} {
+		if dm[0] >= 1 && dm[0] <= 31 && dm[1] >= 1 && dm[1] <= 12 {
+			return dm[0], dm[1], true
+		}
+	}
+	return 0, 0, false
+}
+
+// Guess estimation constants, from zxcvbn.
+const (
+	bruteforceCardinality        = 10
+	minGuessesBeforeGrowingSeq   = 10000
+	minSubmatchGuessesSingleChar = 10
+	minSubmatchGuessesMultiChar  = 50
+	minYearSpace                 = 20
+)
+
+// mostGuessable finds the sequence of non-overlapping matches, filled out
+// with bruteforce runs, that covers pw in the fewest guesses. A sequence
+// of l matches costs l! times the product of its matches' guesses (the
+// order they appear in is unknown), plus 10000^(l-1) so that many short
+// matches don't beat a few long ones.
+func (m *strengthMatcher) mostGuessable(pw []rune, matches []*PasswordMatch, excludeAdditive bool) (float64, []*PasswordMatch) {
+	n := len(pw)
+	if n == 0 {
+		return 1, nil
+	}
+	byEnd := make([][]*PasswordMatch, n)
+	for _, match := range matches {
+		byEnd[match.End] = append(byEnd[match.End], match)
+	}
+
+	// For each end position k and sequence length l: the last match of
+	// the best sequence, its product of guesses and its total guesses.
+	optM := make([]map[int]*PasswordMatch, n)
+	optPi := make([]map[int]float64, n)
+	optG := make([]map[int]float64, n)
+	for k := range n {
+		optM[k], optPi[k], optG[k] = map[int]*PasswordMatch{}, map[int]float64{}, map[int]float64{}
+	}
+
+	update := func(match *PasswordMatch, l int) {
+		k := match.End
+		pi := m.estimateGuesses(match, n)
+		if l > 1 {
+			pi *= optPi[match.Start-1][l-1]
+		}
+		g := factorial(l) * pi
+		if !excludeAdditive {
+			g += math.Pow(minGuessesBeforeGrowingSeq, float64(l-1))
+		}
+		for cl, cg := range optG[k] {
+			if cl <= l && cg <= g {
+				return
+			}
+		}
+		optM[k][l], optPi[k][l], optG[k][l] = match, pi, g
+	}
+	bruteforce := func(i, j int) *PasswordMatch {
+		return &PasswordMatch{Pattern: "bruteforce", Start: i, End: j, Token: string(pw[i : j+1])}
+	}
+
+	for k := range n {
+		for _, match := range byEnd[k] {
+			if match.Start == 0 {
+				update(match, 1)
+				continue
+			}
+			for _, l := range sortedLengths(optM[match.Start-1]) {
+				update(match, l+1)
+			}
+		}
+		update(bruteforce(0, k), 1)
+		for i := 1; i <= k; i++ {
+			for _, l := range sortedLengths(optM[i-1]) {
+				if optM[i-1][l].Pattern != "bruteforce" {
+					update(bruteforce(i, k), l+1)
+				}
+			}
+		}
+	}
+
+	bestL, bestG := 0, math.Inf(1)
+	for _, l := range sortedLengths(optM[n-1]) {
+		if optG[n-1][l] < bestG {
+			bestL, bestG = l, optG[n-1][l]
+		}
+	}
+	seq := make([]*PasswordMatch, bestL)
+	for k, l := n-1, bestL; k >= 0; l-- {
+		match := optM[k][l]
+		seq[l-1] = match
+		k = match.Start - 1
+	}
+	return bestG, seq
+}
+
+func sortedLengths(opt map[int]*PasswordMatch) []int {
+	ls := make([]int, 0, len(opt))
+	for l := range opt {
+		ls = append(ls, l)
+	}
+	slices.Sort(ls)
+	return ls
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+	return f
+}
+
+// nCk is the binomial coefficient.
+func nCk(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+	r := 1.0
+	for d := 1; d <= k; d++ {
+		r = r * float64(n) / float64(d)
+		n--
+	}
+	return r
+}
+
+// estimateGuesses returns, and records on match, how many guesses an
+// attacker who knows the pattern needs for it. A match that is only part
+// of an n-character password costs at least a small floor, so a one or
+// two character match never beats bruteforcing it.
+func (m *strengthMatcher) estimateGuesses(match *PasswordMatch, n int) float64 {
+	if match.Guesses > 0 {
+		return match.Guesses
+	}
+	tokenLen := len([]rune(match.Token))
+	minGuesses := 1.0
+	if tokenLen < n {
+		minGuesses = minSubmatchGuessesMultiChar
+		if tokenLen == 1 {
+			minGuesses = minSubmatchGuessesSingleChar
+		}
+	}
+
+	var guesses float64
+	switch match.Pattern {
+	case "bruteforce":
+		guesses = math.Pow(bruteforceCardinality, float64(tokenLen))
+		floor := float64(minSubmatchGuessesMultiChar + 1)
+		if tokenLen == 1 {
+			floor = minSubmatchGuessesSingleChar + 1
+		}
+		guesses = max(min(guesses, math.MaxFloat64), floor)
+	case "dictionary":
+		guesses = float64(match.Rank) * uppercaseVariations(match.Token) * l33tVariations(match)
+		if match.Reversed {
+			guesses *= 2
+		}
+	case "spatial":
+		guesses = m.spatialGuesses(match, tokenLen)
+	case "repeat":
+		guesses = match.baseGuesses * float64(match.RepeatCount)
+	case "sequence":
+		base := 26.0
+		switch first := []rune(match.Token)[0]; {
+		case strings.ContainsRune("aAzZ019", first):
+			base = 4
+		case isDigit(first):
+			base = 10
+		}
+		if !match.ascending {
+			base *= 2
+		}
+		guesses = base * float64(tokenLen)
+	case "year":
+		guesses = float64(max(abs(match.Year-m.refYear), minYearSpace))
+	case "date":
+		guesses = float64(max(abs(match.Year-m.refYear), minYearSpace) * 365)
+		if match.Separator != "" {
+			guesses *= 4
+		}
+	}
+	match.Guesses = max(guesses, minGuesses)
+	match.GuessesLog10 = math.Log10(match.Guesses)
+	return match.Guesses
+}
+
+// spatialGuesses counts the keyboard walks of the match's length with at
+// most its number of turns, times the ways to shift some of its keys.
+func (m *strengthMatcher) spatialGuesses(match *PasswordMatch, length int) float64 {
+	graph := m.graphs[match.Graph]
+	if match.Graph == "dvorak" {
+		// zxcvbn scores both keyboard layouts with qwerty's statistics.
+		graph = m.graphs["qwerty"]
+	}
+	starts, degree := float64(len(graph)), graph.averageDegree()
+
+	guesses := 0.0
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= min(match.Turns, i-1); j++ {
+			guesses += nCk(i-1, j-1) * starts * math.Pow(degree, float64(j))
+		}
+	}
+	if s := match.ShiftedCount; s > 0 {
+		u := length - s
+		if u == 0 {
+			guesses *= 2
+		} else {
+			variations := 0.0
+			for i := 1; i <= min(s, u); i++ {
+				variations += nCk(s+u, i)
+			}
+			guesses *= variations
+		}
+	}
+	return guesses
+}
+
+// uppercaseVariations is how many ways of capitalising a word an attacker
+// tries before token's: 1 for lowercase, 2 for a leading or trailing
+// capital or all caps, and otherwise every placement of its capitals.
+func uppercaseVariations(token string) float64 {
+	upper, lower := 0, 0
+	for _, r := range token {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || startUpper(token) || endUpper(token) {
+		return 2
+	}
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += nCk(upper+lower, i)
+	}
+	return variations
+}
+
+func startUpper(token string) bool {
+	rs := []rune(token)
+	return len(rs) > 1 && unicode.IsUpper(rs[0]) && !slices.ContainsFunc(rs[1:], unicode.IsUpper)
+}
+
+func endUpper(token string) bool {
+	rs := []rune(token)
+	return len(rs) > 1 && unicode.IsUpper(rs[len(rs)-1]) && !slices.ContainsFunc(rs[:len(rs)-1], unicode.IsUpper)
+}
+
+// l33tVariations is, for each substitution, the number of ways to choose
+// which occurrences of the letter were substituted.
+func l33tVariations(match *PasswordMatch) float64 {
+	if !match.L33t {
+		return 1
+	}
+	variations := 1.0
+	token := lowerRunes([]rune(match.Token))
+	for subbed, unsubbed := range match.sub {
+		s, u := 0, 0
+		for _, r := range token {
+			switch r {
+			case subbed:
+				s++
+			case unsubbed:
+				u++
+			}
+		}
+		if s == 0 || u == 0 {
+			variations *= 2
+			continue
+		}
+		possibilities := 0.0
+		for i := 1; i <= min(s, u); i++ {
+			possibilities += nCk(s+u, i)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+// passwordFeedback explains the longest match of a password scoring 3 or
+// less.
+func passwordFeedback(score int, seq []*PasswordMatch) PasswordFeedback {
+	if len(seq) == 0 {
+		return PasswordFeedback{Suggestions: []string{
+			"Use a few words, avoid common phrases",
+			"No need for symbols, digits, or uppercase letters",
+		}}
+	}
+	if score > 3 {
+		return PasswordFeedback{Suggestions: []string{}}
+	}
+
+	longest := seq[0]
+	for _, match := range seq[1:] {
+		if len([]rune(match.Token)) > len([]rune(longest.Token)) {
+			longest = match
+		}
+	}
+	fb := matchFeedback(longest, len(seq) == 1)
+	fb.Suggestions = append([]string{"Add another word or two. Uncommon words are better."}, fb.Suggestions...)
+	return fb
+}
+
+func matchFeedback(match *PasswordMatch, sole bool) PasswordFeedback {
+	switch match.Pattern {
+	case "dictionary":
+		return dictionaryFeedback(match, sole)
+	case "spatial":
+		warning := "Short keyboard patterns are easy to guess"
+		if match.Turns == 1 {
+			warning = "Straight rows of keys are easy to guess"
+		}
+		return PasswordFeedback{warning, []string{"Use a longer keyboard pattern with more turns"}}
+	case "repeat":
+		warning := `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		if len([]rune(match.BaseToken)) == 1 {
+			warning = `Repeats like "aaa" are easy to guess`
+		}
+		return PasswordFeedback{warning, []string{"Avoid repeated words and characters"}}
+	case "sequence":
+		return PasswordFeedback{"Sequences like abc or 6543 are easy to guess", []string{"Avoid sequences"}}
+	case "year":
+		return PasswordFeedback{"Recent years are easy to guess", []string{"Avoid recent years", "Avoid years that are associated with you"}}
+	case "date":
+		return PasswordFeedback{"Dates are often easy to guess", []string{"Avoid dates and years that are associated with you"}}
+	}
+	return PasswordFeedback{}
+}
+
+func dictionaryFeedback(match *PasswordMatch, sole bool) PasswordFeedback {
+	var fb PasswordFeedback
+	switch match.Dictionary {
+	case "passwords":
+		switch {
+		case sole && !match.L33t && !match.Reversed && match.Rank <= 10:
+			fb.Warning = "This is a top-10 common password"
+		case sole && !match.L33t && !match.Reversed && match.Rank <= 100:
+			fb.Warning = "This is a top-100 common password"
+		case sole && !match.L33t && !match.Reversed:
+			fb.Warning = "This is a very common password"
+		case match.GuessesLog10 <= 4:
+			fb.Warning = "This is similar to a commonly used password"
+		}
+	case "english":
+		if sole {
+			fb.Warning = "A word by itself is easy to guess"
+		}
+	case "female_names", "male_names", "surnames":
+		fb.Warning = "Common names and surnames are easy to guess"
+		if sole {
+			fb.Warning = "Names and surnames by themselves are easy to guess"
+		}
+	case "user_inputs":
+		fb.Warning = "Avoid words tied to your account, such as your name or email address"
+	}
+
+	hasLower := strings.ToUpper(match.Token) != match.Token
+	switch {
+	case startUpper(match.Token):
+		fb.Suggestions = append(fb.Suggestions, "Capitalization doesn't help very much")
+	case !hasLower && strings.ToLower(match.Token) != match.Token:
+		fb.Suggestions = append(fb.Suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+	}
+	if match.Reversed && len([]rune(match.Token)) >= 4 {
+		fb.Suggestions = append(fb.Suggestions, "Reversed words aren't much harder to guess")
+	}
+	if match.L33t {
+		fb.Suggestions = append(fb.Suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+	}
+	return fb
+}
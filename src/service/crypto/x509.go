@@ -0,0 +1,908 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// CertificateKeyTypes lists the key types GenerateCSR can create.
+var CertificateKeyTypes = []string{"ecdsa-p256", "ecdsa-p384", "rsa-2048", "rsa-3072", "rsa-4096", "ed25519"}
+
+// keyUsages maps RFC 5280 keyUsage bits to the names used in requests and
+// results, in bit order.
+var keyUsages = []struct {
+	name  string
+	usage x509.KeyUsage
+}{
+	{"digital_signature", x509.KeyUsageDigitalSignature},
+	{"content_commitment", x509.KeyUsageContentCommitment},
+	{"key_encipherment", x509.KeyUsageKeyEncipherment},
+	{"data_encipherment", x509.KeyUsageDataEncipherment},
+	{"key_agreement", x509.KeyUsageKeyAgreement},
+	{"cert_sign", x509.KeyUsageCertSign},
+	{"crl_sign", x509.KeyUsageCRLSign},
+	{"encipher_only", x509.KeyUsageEncipherOnly},
+	{"decipher_only", x509.KeyUsageDecipherOnly},
+}
+
+// extKeyUsages maps extended key usages to their names and OIDs.
+var extKeyUsages = []struct {
+	name  string
+	usage x509.ExtKeyUsage
+	oid   asn1.ObjectIdentifier
+}{
+	{"any", x509.ExtKeyUsageAny, asn1.ObjectIdentifier{2, 5, 29, 37, 0}},
+	{"server_auth", x509.ExtKeyUsageServerAuth, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 1}},
+	{"client_auth", x509.ExtKeyUsageClientAuth, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 2}},
+	{"code_signing", x509.ExtKeyUsageCodeSigning, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 3}},
+	{"email_protection", x509.ExtKeyUsageEmailProtection, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 4}},
+	{"time_stamping", x509.ExtKeyUsageTimeStamping, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}},
+	{"ocsp_signing", x509.ExtKeyUsageOCSPSigning, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 9}},
+}
+
+var (
+	oidExtensionKeyUsage    = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionExtKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+// crlReasons names the RFC 5280 CRLReason codes (7 is unused).
+var crlReasons = map[int]string{
+	0:  "unspecified",
+	1:  "key_compromise",
+	2:  "ca_compromise",
+	3:  "affiliation_changed",
+	4:  "superseded",
+	5:  "cessation_of_operation",
+	6:  "certificate_hold",
+	8:  "remove_from_crl",
+	9:  "privilege_withdrawn",
+	10: "aa_compromise",
+}
+
+// CertificateSubject is the distinguished name of a CSR.
+type CertificateSubject struct {
+	CommonName         string `json:"common_name"`
+	Organization       string `json:"organization,omitempty"`
+	OrganizationalUnit string `json:"organizational_unit,omitempty"`
+	Country            string `json:"country,omitempty"`
+	Province           string `json:"province,omitempty"`
+	Locality           string `json:"locality,omitempty"`
+}
+
+// pkixName converts s to a pkix.Name, leaving empty attributes out.
+func (s CertificateSubject) pkixName() pkix.Name {
+	name := pkix.Name{CommonName: s.CommonName}
+	for _, attr := range []struct {
+		dst *[]string
+		v   string
+	}{
+		{&name.Organization, s.Organization},
+		{&name.OrganizationalUnit, s.OrganizationalUnit},
+		{&name.Country, s.Country},
+		{&name.Province, s.Province},
+		{&name.Locality, s.Locality},
+	} {
+		if attr.v != "" {
+			*attr.dst = []string{attr.v}
+		}
+	}
+	return name
+}
+
+// CSROptions describes a certificate signing request.
+type CSROptions struct {
+	Subject        CertificateSubject
+	DNSNames       []string
+	IPAddresses    []string
+	EmailAddresses []string
+	URIs           []string
+	// KeyUsage and ExtKeyUsage are requested as extensions; the CA
+	// decides whether to honour them.
+	KeyUsage    []string
+	ExtKeyUsage []string
+	// Key is an existing private key (any format ParseKey accepts). When
+	// empty a new key of KeyType (default ecdsa-p256) is generated.
+	Key     string
+	KeyType string
+}
+
+// GenerateCSR creates a PEM certificate signing request from opts and
+// returns it with the PKCS#8 PEM of the key it was signed with. keyPEM is
+// empty when opts.Key was supplied.
+func GenerateCSR(opts CSROptions) (csrPEM, keyPEM string, err error) {
+	if opts.Subject.CommonName == "" && len(opts.DNSNames)+len(opts.IPAddresses)+len(opts.EmailAddresses)+len(opts.URIs) == 0 {
+		return "", "", fmt.Errorf("a common name or at least one subject alternative name is required")
+	}
+
+	template := &x509.CertificateRequest{
+		Subject:        opts.Subject.pkixName(),
+		DNSNames:       opts.DNSNames,
+		EmailAddresses: opts.EmailAddresses,
+	}
+	if template.IPAddresses, err = parseIPAddresses(opts.IPAddresses); err != nil {
+		return "", "", err
+	}
+	if template.URIs, err = parseURIs(opts.URIs); err != nil {
+		return "", "", err
+	}
+	if len(opts.KeyUsage) > 0 {
+		ku, err := parseKeyUsages(opts.KeyUsage)
+		if err != nil {
+			return "", "", err
+		}
+		ext, err := marshalKeyUsage(ku)
+		if err != nil {
+			return "", "", err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	}
+	if len(opts.ExtKeyUsage) > 0 {
+		ext, err := marshalExtKeyUsage(opts.ExtKeyUsage)
+		if err != nil {
+			return "", "", err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	}
+
+	var signer stdcrypto.Signer
+	if opts.Key != "" {
+		parsed, err := ParseKey(opts.Key)
+		if err != nil {
+			return "", "", err
+		}
+		if !isPrivateKey(parsed) {
+			return "", "", fmt.Errorf("key must be a private key")
+		}
+		signer = parsed.(stdcrypto.Signer)
+	} else {
+		if signer, err = generateCertificateKey(opts.KeyType); err != nil {
+			return "", "", err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(signer)
+		if err != nil {
+			return "", "", err
+		}
+		keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, signer)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create CSR: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), keyPEM, nil
+}
+
+// generateCertificateKey creates a private key of keyType, one of
+// CertificateKeyTypes ("" means ecdsa-p256).
+func generateCertificateKey(keyType string) (stdcrypto.Signer, error) {
+	switch strings.ToLower(keyType) {
+	case "", "ecdsa-p256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ecdsa-p384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "rsa-2048":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "rsa-3072":
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case "rsa-4096":
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case "ed25519":
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	}
+	return nil, fmt.Errorf("unsupported key type %q (supported: %s)", keyType, strings.Join(CertificateKeyTypes, ", "))
+}
+
+// CSRInfo describes a decoded certificate signing request.
+type CSRInfo struct {
+	Subject            string   `json:"subject"`
+	DNSNames           []string `json:"dns_names"`
+	IPAddresses        []string `json:"ip_addresses"`
+	EmailAddresses     []string `json:"email_addresses"`
+	URIs               []string `json:"uris"`
+	KeyUsage           []string `json:"key_usage"`
+	ExtKeyUsage        []string `json:"ext_key_usage"`
+	SignatureAlgorithm string   `json:"signature_algorithm"`
+	SignatureValid     bool     `json:"signature_valid"`
+	PublicKey          *KeyInfo `json:"public_key,omitempty"`
+}
+
+// ParseCSR decodes a PEM certificate signing request, including the key
+// usages it requests, and checks its self-signature.
+func ParseCSR(csrPEM string) (*CSRInfo, error) {
+	csr, err := parseCSR(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+	ku, ekus, err := requestedUsages(csr)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CSRInfo{
+		Subject:            csr.Subject.String(),
+		DNSNames:           nonNil(csr.DNSNames),
+		EmailAddresses:     nonNil(csr.EmailAddresses),
+		KeyUsage:           keyUsageNames(ku),
+		ExtKeyUsage:        ekus,
+		SignatureAlgorithm: csr.SignatureAlgorithm.String(),
+		SignatureValid:     csr.CheckSignature() == nil,
+		IPAddresses:        ipStrings(csr.IPAddresses),
+		URIs:               uriStrings(csr.URIs),
+	}
+	if keyInfo, err := DescribeKey(csr.PublicKey); err == nil {
+		info.PublicKey = keyInfo
+	}
+	return info, nil
+}
+
+// parseCSR decodes the first CERTIFICATE REQUEST block of csrPEM.
+func parseCSR(csrPEM string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || (block.Type != "CERTIFICATE REQUEST" && block.Type != "NEW CERTIFICATE REQUEST") {
+		return nil, fmt.Errorf("input is not a PEM-encoded certificate request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate request: %w", err)
+	}
+	return csr, nil
+}
+
+// requestedUsages reads the keyUsage and extKeyUsage extensions of csr.
+// Extended usages without a name are reported by OID.
+func requestedUsages(csr *x509.CertificateRequest) (x509.KeyUsage, []string, error) {
+	var ku x509.KeyUsage
+	ekus := []string{}
+	for _, ext := range csr.Extensions {
+		switch {
+		case ext.Id.Equal(oidExtensionKeyUsage):
+			var bits asn1.BitString
+			if _, err := asn1.Unmarshal(ext.Value, &bits); err != nil {
+				return 0, nil, fmt.Errorf("malformed key usage extension: %w", err)
+			}
+			for i := range keyUsages {
+				if bits.At(i) != 0 {
+					ku |= 1 << i
+				}
+			}
+		case ext.Id.Equal(oidExtensionExtKeyUsage):
+			var oids []asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(ext.Value, &oids); err != nil {
+				return 0, nil, fmt.Errorf("malformed extended key usage extension: %w", err)
+			}
+			for _, oid := range oids {
+				name := oid.String()
+				for _, e := range extKeyUsages {
+					if e.oid.Equal(oid) {
+						name = e.name
+						break
+					}
+				}
+				ekus = append(ekus, name)
+			}
+		}
+	}
+	return ku, ekus, nil
+}
+
+// SignCSROptions controls the certificate SignCSR issues.
+type SignCSROptions struct {
+	// ValidDays defaults to 365. The certificate never outlives the CA.
+	ValidDays int
+	IsCA      bool
+	// PathLen limits the CA certificates that may follow this one in a
+	// chain; nil means no limit. Only used with IsCA.
+	PathLen *int
+	// KeyUsage and ExtKeyUsage override what the CSR requested. With
+	// neither given nor requested, leaf certificates get
+	// digital_signature (plus key_encipherment for RSA) and
+	// server_auth/client_auth, and CA certificates get cert_sign,
+	// crl_sign and digital_signature.
+	KeyUsage    []string
+	ExtKeyUsage []string
+}
+
+// SignCSR issues a certificate for the request in csrPEM, signed by the CA
+// certificate caCertPEM and its private key caKeyPEM (any format ParseKey
+// accepts), and returns it as PEM. The subject and subject alternative
+// names are copied from the request; other requested extensions, such as
+// basic constraints, are ignored in favour of opts.
+func SignCSR(csrPEM, caCertPEM, caKeyPEM string, opts SignCSROptions) (string, error) {
+	csr, err := parseCSR(csrPEM)
+	if err != nil {
+		return "", err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return "", fmt.Errorf("certificate request signature is invalid: %w", err)
+	}
+
+	caCerts, err := parseCertificateBundle(caCertPEM)
+	if err != nil {
+		return "", fmt.Errorf("CA certificate: %w", err)
+	}
+	caCert := caCerts[0]
+	if !caCert.BasicConstraintsValid || !caCert.IsCA {
+		return "", fmt.Errorf("CA certificate is not a CA")
+	}
+	if caCert.KeyUsage != 0 && caCert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return "", fmt.Errorf("CA certificate does not allow certificate signing")
+	}
+
+	caKey, err := ParseKey(caKeyPEM)
+	if err != nil {
+		return "", fmt.Errorf("CA key: %w", err)
+	}
+	if !isPrivateKey(caKey) {
+		return "", fmt.Errorf("CA key must be a private key")
+	}
+	if !keyMatches(caCert.PublicKey, caKey) {
+		return "", fmt.Errorf("CA key does not match the CA certificate")
+	}
+
+	ku, requestedEKUs, err := requestedUsages(csr)
+	if err != nil {
+		return "", err
+	}
+	if len(opts.KeyUsage) > 0 {
+		if ku, err = parseKeyUsages(opts.KeyUsage); err != nil {
+			return "", err
+		}
+	}
+	ekuNames := requestedEKUs
+	if len(opts.ExtKeyUsage) > 0 {
+		ekuNames = opts.ExtKeyUsage
+	}
+	ekus, unknownEKUs, err := parseExtKeyUsages(ekuNames)
+	if err != nil {
+		return "", err
+	}
+	if ku == 0 && len(ekuNames) == 0 {
+		if opts.IsCA {
+			ku = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+		} else {
+			ku = x509.KeyUsageDigitalSignature
+			if _, ok := csr.PublicKey.(*rsa.PublicKey); ok {
+				ku |= x509.KeyUsageKeyEncipherment
+			}
+			ekus = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+		}
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	if opts.ValidDays <= 0 {
+		opts.ValidDays = 365
+	}
+	notBefore := time.Now()
+	if !notBefore.Before(caCert.NotAfter) {
+		return "", fmt.Errorf("CA certificate expired on %s", caCert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	notAfter := notBefore.AddDate(0, 0, opts.ValidDays)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+	if !notAfter.After(notBefore) {
+		return "", fmt.Errorf("certificate would expire before it becomes valid")
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               csr.Subject,
+		DNSNames:              csr.DNSNames,
+		IPAddresses:           csr.IPAddresses,
+		EmailAddresses:        csr.EmailAddresses,
+		URIs:                  csr.URIs,
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              ku,
+		ExtKeyUsage:           ekus,
+		UnknownExtKeyUsage:    unknownEKUs,
+		BasicConstraintsValid: true,
+		IsCA:                  opts.IsCA,
+		MaxPathLen:            -1,
+	}
+	if opts.IsCA && opts.PathLen != nil {
+		if *opts.PathLen < 0 {
+			return "", fmt.Errorf("path length must not be negative")
+		}
+		template.MaxPathLen = *opts.PathLen
+		template.MaxPathLenZero = *opts.PathLen == 0
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, csr.PublicKey, caKey.(stdcrypto.Signer))
+	if err != nil {
+		return "", fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+// DecodeCertificates is ParseCertificate for every certificate in a PEM
+// bundle, in order.
+func DecodeCertificates(bundle string) ([]map[string]interface{}, error) {
+	certs, err := parseCertificateBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	details := make([]map[string]interface{}, len(certs))
+	for i, cert := range certs {
+		details[i] = describeCertificate(cert)
+	}
+	return details, nil
+}
+
+// parseCertificateBundle decodes every CERTIFICATE block of bundle, in
+// order, skipping other block types such as keys.
+func parseCertificateBundle(bundle string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", len(certs), err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("input holds no PEM-encoded certificates")
+	}
+	return certs, nil
+}
+
+// ChainOptions controls VerifyCertificateChain.
+type ChainOptions struct {
+	// Roots is a PEM bundle of trusted roots; empty means the system
+	// trust store.
+	Roots string
+	// DNSName, when set, must be covered by the leaf certificate.
+	DNSName string
+	// At is the verification time; zero means now.
+	At time.Time
+	// ExtKeyUsage the leaf must allow; empty accepts any usage.
+	ExtKeyUsage []string
+}
+
+// ChainCertificate summarises one certificate of a verified chain.
+type ChainCertificate struct {
+	Subject           string `json:"subject"`
+	Issuer            string `json:"issuer"`
+	SerialNumber      string `json:"serial_number"`
+	NotBefore         string `json:"not_before"`
+	NotAfter          string `json:"not_after"`
+	IsCA              bool   `json:"is_ca"`
+	FingerprintSHA256 string `json:"fingerprint_sha256"`
+}
+
+// ChainVerification is the result of VerifyCertificateChain. Chains
+// lists every path found from the leaf to a root, leaf first.
+type ChainVerification struct {
+	Valid  bool                 `json:"valid"`
+	Chains [][]ChainCertificate `json:"chains"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// VerifyCertificateChain builds and validates chains from the leaf
+// certificate to opts.Roots. chain holds PEM certificates, leaf first and
+// then any intermediates, in one or several strings, so the chain field
+// of an OSINT SSLInfo lookup can be passed unchanged. Malformed input is
+// an error; a chain that does not verify is Valid=false with the reason
+// in Error.
+func VerifyCertificateChain(chain []string, opts ChainOptions) (*ChainVerification, error) {
+	certs, err := parseCertificateBundle(strings.Join(chain, "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	verifyOpts := x509.VerifyOptions{
+		DNSName:       opts.DNSName,
+		CurrentTime:   opts.At,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range certs[1:] {
+		verifyOpts.Intermediates.AddCert(cert)
+	}
+	if opts.Roots != "" {
+		roots, err := parseCertificateBundle(opts.Roots)
+		if err != nil {
+			return nil, fmt.Errorf("roots: %w", err)
+		}
+		verifyOpts.Roots = x509.NewCertPool()
+		for _, root := range roots {
+			verifyOpts.Roots.AddCert(root)
+		}
+	}
+	if len(opts.ExtKeyUsage) > 0 {
+		ekus, unknown, err := parseExtKeyUsages(opts.ExtKeyUsage)
+		if err != nil {
+			return nil, err
+		}
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("unsupported extended key usage %s", unknown[0])
+		}
+		verifyOpts.KeyUsages = ekus
+	}
+
+	result := &ChainVerification{Chains: [][]ChainCertificate{}}
+	chains, err := certs[0].Verify(verifyOpts)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Valid = true
+	for _, c := range chains {
+		summary := make([]ChainCertificate, len(c))
+		for i, cert := range c {
+			sum := sha256.Sum256(cert.Raw)
+			summary[i] = ChainCertificate{
+				Subject:           cert.Subject.String(),
+				Issuer:            cert.Issuer.String(),
+				SerialNumber:      cert.SerialNumber.String(),
+				NotBefore:         cert.NotBefore.UTC().Format(time.RFC3339),
+				NotAfter:          cert.NotAfter.UTC().Format(time.RFC3339),
+				IsCA:              cert.IsCA,
+				FingerprintSHA256: hex.EncodeToString(sum[:]),
+			}
+		}
+		result.Chains = append(result.Chains, summary)
+	}
+	return result, nil
+}
+
+// PKCS12Encode bundles a private key (any format ParseKey accepts) with
+// its certificate and returns the base64 PFX. certPEM holds the matching
+// certificate first, optionally followed by CA certificates to include.
+// The bundle is encrypted with AES-256 and PBKDF2 unless legacy is set,
+// which selects 3DES for older Java and Windows versions.
+func PKCS12Encode(keyPEM, certPEM, password string, legacy bool) (string, error) {
+	key, err := ParseKey(keyPEM)
+	if err != nil {
+		return "", err
+	}
+	if !isPrivateKey(key) {
+		return "", fmt.Errorf("key must be a private key")
+	}
+	certs, err := parseCertificateBundle(certPEM)
+	if err != nil {
+		return "", err
+	}
+	if !keyMatches(certs[0].PublicKey, key) {
+		return "", fmt.Errorf("key does not match the first certificate")
+	}
+
+	encoder := pkcs12.Modern
+	if legacy {
+		encoder = pkcs12.LegacyDES
+	}
+	pfx, err := encoder.Encode(key, certs[0], certs[1:], password)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode PKCS#12: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(pfx), nil
+}
+
+// PKCS12Contents is a decoded PKCS#12 bundle. PrivateKey is PKCS#8 PEM and
+// is empty for a trust store holding only certificates.
+type PKCS12Contents struct {
+	PrivateKey     string   `json:"private_key,omitempty"`
+	Certificate    string   `json:"certificate,omitempty"`
+	CACertificates []string `json:"ca_certificates"`
+}
+
+// PKCS12Decode opens a base64 PFX with password.
+func PKCS12Decode(pfxBase64, password string) (*PKCS12Contents, error) {
+	pfx, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(pfxBase64), ""))
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#12 data must be base64: %w", err)
+	}
+
+	certPEM := func(cert *x509.Certificate) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	contents := &PKCS12Contents{CACertificates: []string{}}
+
+	key, cert, caCerts, err := pkcs12.DecodeChain(pfx, password)
+	if err != nil {
+		// A trust store has certificates but no key bag.
+		trusted, trustErr := pkcs12.DecodeTrustStore(pfx, password)
+		if trustErr != nil {
+			return nil, fmt.Errorf("failed to decode PKCS#12: %w", err)
+		}
+		for _, c := range trusted {
+			contents.CACertificates = append(contents.CACertificates, certPEM(c))
+		}
+		return contents, nil
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	contents.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	contents.Certificate = certPEM(cert)
+	for _, c := range caCerts {
+		contents.CACertificates = append(contents.CACertificates, certPEM(c))
+	}
+	return contents, nil
+}
+
+// CRLEntry is one revoked certificate of a CRL.
+type CRLEntry struct {
+	SerialNumber   string `json:"serial_number"`
+	RevocationTime string `json:"revocation_time"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+// CRLInfo describes a decoded certificate revocation list.
+// SignatureValid is only set when the issuer certificate was supplied.
+type CRLInfo struct {
+	Issuer             string     `json:"issuer"`
+	Number             string     `json:"number,omitempty"`
+	ThisUpdate         string     `json:"this_update"`
+	NextUpdate         string     `json:"next_update,omitempty"`
+	SignatureAlgorithm string     `json:"signature_algorithm"`
+	SignatureValid     *bool      `json:"signature_valid,omitempty"`
+	RevokedCount       int        `json:"revoked_count"`
+	Revoked            []CRLEntry `json:"revoked"`
+}
+
+// ParseCRL decodes a certificate revocation list given as PEM ("X509 CRL")
+// or base64 DER. When issuerPEM is non-empty the CRL's signature is
+// checked against that certificate.
+func ParseCRL(crl, issuerPEM string) (*CRLInfo, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(crl)); block != nil {
+		der = block.Bytes
+	} else {
+		var err error
+		if der, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(crl), "")); err != nil {
+			return nil, fmt.Errorf("CRL must be PEM or base64 DER")
+		}
+	}
+	list, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CRL: %w", err)
+	}
+
+	info := &CRLInfo{
+		Issuer:             list.Issuer.String(),
+		ThisUpdate:         list.ThisUpdate.UTC().Format(time.RFC3339),
+		SignatureAlgorithm: list.SignatureAlgorithm.String(),
+		RevokedCount:       len(list.RevokedCertificateEntries),
+		Revoked:            make([]CRLEntry, len(list.RevokedCertificateEntries)),
+	}
+	if list.Number != nil {
+		info.Number = list.Number.String()
+	}
+	if !list.NextUpdate.IsZero() {
+		info.NextUpdate = list.NextUpdate.UTC().Format(time.RFC3339)
+	}
+	for i, entry := range list.RevokedCertificateEntries {
+		info.Revoked[i] = CRLEntry{
+			SerialNumber:   entry.SerialNumber.String(),
+			RevocationTime: entry.RevocationTime.UTC().Format(time.RFC3339),
+			Reason:         crlReasons[entry.ReasonCode],
+		}
+	}
+
+	if issuerPEM != "" {
+		issuers, err := parseCertificateBundle(issuerPEM)
+		if err != nil {
+			return nil, fmt.Errorf("issuer: %w", err)
+		}
+		valid := list.CheckSignatureFrom(issuers[0]) == nil
+		info.SignatureValid = &valid
+	}
+	return info, nil
+}
+
+// keyMatches reports whether pub is the public half of the private key.
+func keyMatches(pub, key interface{}) bool {
+	type equaler interface {
+		Equal(stdcrypto.PublicKey) bool
+	}
+	p, ok := pub.(equaler)
+	return ok && p.Equal(PublicKey(key))
+}
+
+// parseKeyUsages ORs together the named key usages.
+func parseKeyUsages(names []string) (x509.KeyUsage, error) {
+	var ku x509.KeyUsage
+next:
+	for _, name := range names {
+		for _, u := range keyUsages {
+			if strings.EqualFold(name, u.name) {
+				ku |= u.usage
+				continue next
+			}
+		}
+		return 0, fmt.Errorf("unknown key usage %q", name)
+	}
+	return ku, nil
+}
+
+// keyUsageNames lists the names of the bits set in ku.
+func keyUsageNames(ku x509.KeyUsage) []string {
+	names := []string{}
+	for _, u := range keyUsages {
+		if ku&u.usage != 0 {
+			names = append(names, u.name)
+		}
+	}
+	return names
+}
+
+// parseExtKeyUsages resolves extended key usage names; dotted OIDs are
+// accepted for usages without a name and returned separately.
+func parseExtKeyUsages(names []string) ([]x509.ExtKeyUsage, []asn1.ObjectIdentifier, error) {
+	var ekus []x509.ExtKeyUsage
+	var unknown []asn1.ObjectIdentifier
+next:
+	for _, name := range names {
+		for _, e := range extKeyUsages {
+			if strings.EqualFold(name, e.name) {
+				ekus = append(ekus, e.usage)
+				continue next
+			}
+		}
+		oid, err := parseOID(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unknown extended key usage %q", name)
+		}
+		unknown = append(unknown, oid)
+	}
+	return ekus, unknown, nil
+}
+
+// extKeyUsageNames names the extended key usages of cert.
+func extKeyUsageNames(cert *x509.Certificate) []string {
+	names := []string{}
+	for _, eku := range cert.ExtKeyUsage {
+		name := fmt.Sprintf("unknown(%d)", eku)
+		for _, e := range extKeyUsages {
+			if e.usage == eku {
+				name = e.name
+				break
+			}
+		}
+		names = append(names, name)
+	}
+	for _, oid := range cert.UnknownExtKeyUsage {
+		names = append(names, oid.String())
+	}
+	return names
+}
+
+// parseOID parses a dotted object identifier such as 1.3.6.1.4.1.311.
+func parseOID(s string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid OID %q", s)
+	}
+	oid := make(asn1.ObjectIdentifier, len(parts))
+	for i, part := range parts {
+		n, ok := new(big.Int).SetString(part, 10)
+		if !ok || n.Sign() < 0 || !n.IsInt64() || n.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid OID %q", s)
+		}
+		oid[i] = int(n.Int64())
+	}
+	return oid, nil
+}
+
+// marshalKeyUsage encodes ku as a critical keyUsage extension.
+func marshalKeyUsage(ku x509.KeyUsage) (pkix.Extension, error) {
+	var bits asn1.BitString
+	for i := range keyUsages {
+		if ku&(1<<i) != 0 {
+			bits.BitLength = i + 1
+		}
+	}
+	bits.Bytes = make([]byte, (bits.BitLength+7)/8)
+	for i := 0; i < bits.BitLength; i++ {
+		if ku&(1<<i) != 0 {
+			bits.Bytes[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	der, err := asn1.Marshal(bits)
+	return pkix.Extension{Id: oidExtensionKeyUsage, Critical: true, Value: der}, err
+}
+
+// marshalExtKeyUsage encodes the named extended key usages as an
+// extKeyUsage extension.
+func marshalExtKeyUsage(names []string) (pkix.Extension, error) {
+	ekus, oids, err := parseExtKeyUsages(names)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	for _, eku := range ekus {
+		for _, e := range extKeyUsages {
+			if e.usage == eku {
+				oids = append(oids, e.oid)
+			}
+		}
+	}
+	der, err := asn1.Marshal(oids)
+	return pkix.Extension{Id: oidExtensionExtKeyUsage, Value: der}, err
+}
+
+// parseIPAddresses parses each of ips as an IPv4 or IPv6 address.
+func parseIPAddresses(ips []string) ([]net.IP, error) {
+	var out []net.IP
+	for _, s := range ips {
+		ip := net.ParseIP(strings.TrimSpace(s))
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", s)
+		}
+		out = append(out, ip)
+	}
+	return out, nil
+}
+
+// parseURIs parses each of uris, which must be absolute.
+func parseURIs(uris []string) ([]*url.URL, error) {
+	var out []*url.URL
+	for _, s := range uris {
+		u, err := url.Parse(s)
+		if err != nil || !u.IsAbs() {
+			return nil, fmt.Errorf("invalid URI %q", s)
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// ipStrings formats ips.
+func ipStrings(ips []net.IP) []string {
+	out := make([]string, len(ips))
+	for i, ip := range ips {
+		out[i] = ip.String()
+	}
+	return out
+}
+
+// uriStrings formats uris.
+func uriStrings(uris []*url.URL) []string {
+	out := make([]string, len(uris))
+	for i, u := range uris {
+		out[i] = u.String()
+	}
+	return out
+}
+
+// nonNil returns s, or an empty slice if s is nil, so it encodes as [].
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
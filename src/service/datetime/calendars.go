@@ -0,0 +1,743 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CalendarOptions controls ConvertCalendar. From names the calendar or
+// time scale the value is written in (see CalendarSystems; default
+// "gregorian"), Timezone (IANA, default UTC) is the zone calendar dates
+// are read and reported in, and Format, when set, adds a FormatDatetime
+// rendering of the instant.
+type CalendarOptions struct {
+	From     string
+	Timezone string
+	Format   string
+}
+
+// CalendarSystems lists the values ConvertCalendar reads.
+var CalendarSystems = []string{
+	"gregorian", "iso_week", "ordinal",
+	"julian_day", "modified_julian_day", "unix", "unix_ms", "ntp", "gps", "tai", "filetime", "dotnet_ticks", "excel_serial",
+	"hebrew", "islamic", "persian", "chinese",
+}
+
+// calendarAliases maps shorter names to CalendarSystems entries.
+var calendarAliases = map[string]string{
+	"jd":          "julian_day",
+	"mjd":         "modified_julian_day",
+	"dotnet":      "dotnet_ticks",
+	"excel":       "excel_serial",
+	"isoweek":     "iso_week",
+	"week":        "iso_week",
+	"hijri":       "islamic",
+	"jalali":      "persian",
+	"solar_hijri": "persian",
+}
+
+// Offsets between the Unix epoch and the other epochs, in seconds.
+const (
+	ntpEpochOffset      = 2208988800  // 1900-01-01
+	gpsEpochOffset      = 315964800   // 1980-01-06
+	filetimeEpochOffset = 11644473600 // 1601-01-01
+	dotnetEpochOffset   = 62135596800 // 0001-01-01
+	unixJulianDay       = 2440587.5
+	unixMJD             = 40587
+
+	// unixFixed is the fixed date (day 1 = 1 January 1 of the proleptic
+	// Gregorian calendar) of 1 January 1970.
+	unixFixed = 719163
+)
+
+// leapSeconds lists when each leap second took effect and TAI − UTC from
+// then on. Before 1972 TAI − UTC is taken as 10 seconds.
+var leapSeconds = []struct {
+	year   int
+	month  time.Month
+	offset int64
+}{
+	{1972, time.July, 11}, {1973, time.January, 12}, {1974, time.January, 13},
+	{1975, time.January, 14}, {1976, time.January, 15}, {1977, time.January, 16},
+	{1978, time.January, 17}, {1979, time.January, 18}, {1980, time.January, 19},
+	{1981, time.July, 20}, {1982, time.July, 21}, {1983, time.July, 22},
+	{1985, time.July, 23}, {1988, time.January, 24}, {1990, time.January, 25},
+	{1991, time.January, 26}, {1992, time.July, 27}, {1993, time.July, 28},
+	{1994, time.July, 29}, {1996, time.January, 30}, {1997, time.July, 31},
+	{1999, time.January, 32}, {2006, time.January, 33}, {2009, time.January, 34},
+	{2012, time.July, 35}, {2015, time.July, 36}, {2017, time.January, 37},
+}
+
+// taiOffset returns TAI − UTC in seconds at Unix time sec.
+func taiOffset(sec int64) int64 {
+	offset := int64(10)
+	for _, l := range leapSeconds {
+		if sec < time.Date(l.year, l.month, 1, 0, 0, 0, 0, time.UTC).Unix() {
+			break
+		}
+		offset = l.offset
+	}
+	return offset
+}
+
+// utcFromTAI converts seconds on the TAI scale (counted like Unix time)
+// to Unix time. A leap second maps to the midnight that follows it.
+func utcFromTAI(tai int64) int64 {
+	for i := len(leapSeconds) - 1; i >= 0; i-- {
+		l := leapSeconds[i]
+		if start := time.Date(l.year, l.month, 1, 0, 0, 0, 0, time.UTC).Unix(); tai >= start+l.offset {
+			return tai - l.offset
+		}
+	}
+	return tai - 10
+}
+
+var (
+	// hebrewMonths are numbered from Nisan; the year begins with Tishrei
+	// (7), and leap years add Adar II (13) after Adar I (12).
+	hebrewMonths  = []string{"Nisan", "Iyar", "Sivan", "Tammuz", "Av", "Elul", "Tishrei", "Cheshvan", "Kislev", "Tevet", "Shevat", "Adar", "Adar II"}
+	islamicMonths = []string{"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani", "Jumada al-Awwal", "Jumada al-Thani", "Rajab", "Shaban", "Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah"}
+	persianMonths = []string{"Farvardin", "Ordibehesht", "Khordad", "Tir", "Mordad", "Shahrivar", "Mehr", "Aban", "Azar", "Dey", "Bahman", "Esfand"}
+)
+
+// ConvertCalendar reads value in the calendar or time scale opts.From
+// names and returns the instant in all of them: the FromUnix breakdown
+// in opts.Timezone (plus a FormatDatetime rendering when opts.Format is
+// set), calendar dates for the local day, and time-scale values.
+//
+// Calendar dates are written year-month-day in that calendar's own
+// numbering, and months can also be given by name ("5787-Tishrei-1");
+// they convert to midnight in opts.Timezone. Dates follow the civil day,
+// so a Hebrew or Islamic date changes at midnight rather than sunset.
+// The Islamic calendar is the tabular one (leap years 2, 5, 7, 10, 13,
+// 16, 18, 21, 24, 26 and 29 of each 30-year cycle), the Persian
+// calendar follows Borkowski's 33-year arithmetic, and the Chinese
+// calendar is computed astronomically for Beijing; a leap month is
+// marked with an L ("2025-6L-1").
+func ConvertCalendar(value string, opts CalendarOptions) (map[string]interface{}, error) {
+	loc := time.UTC
+	if opts.Timezone != "" {
+		l, err := time.LoadLocation(opts.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", opts.Timezone)
+		}
+		loc = l
+	}
+
+	from := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(opts.From)), "-", "_")
+	if from == "" {
+		from = "gregorian"
+	}
+	if alias, ok := calendarAliases[from]; ok {
+		from = alias
+	}
+	parse, ok := calendarParsers[from]
+	if !ok {
+		return nil, fmt.Errorf("unknown calendar %q (supported: %s)", opts.From, strings.Join(CalendarSystems, ", "))
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("value is required")
+	}
+	t, err := parse(value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %w", from, value, err)
+	}
+	t = t.In(loc)
+	if y, yUTC := t.Year(), t.UTC().Year(); y < 1 || y > 9999 || yUTC < 1 || yUTC > 9999 {
+		return nil, fmt.Errorf("%q is outside the supported range of years 1-9999", value)
+	}
+
+	instant, err := FromUnix(t.Unix(), loc.String())
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{
+		"input":     value,
+		"from":      from,
+		"timezone":  loc.String(),
+		"datetime":  instant,
+		"calendars": calendarDates(t),
+		"epochs":    epochValues(t),
+	}
+	if opts.Format != "" {
+		formatted, err := formatTime(t, opts.Format)
+		if err != nil {
+			return nil, err
+		}
+		result["formatted"] = formatted
+	}
+	return result, nil
+}
+
+// calendarParsers read a value in each of CalendarSystems.
+var calendarParsers = map[string]func(string, *time.Location) (time.Time, error){
+	"gregorian": func(s string, loc *time.Location) (time.Time, error) {
+		t, _, err := parseLocalTime(s, loc)
+		return t, err
+	},
+	"iso_week":            parseISOWeekDate,
+	"ordinal":             parseOrdinalDate,
+	"julian_day":          dayScaleParser(unixJulianDay),
+	"modified_julian_day": dayScaleParser(unixMJD),
+	"unix":                secondScaleParser(func(sec int64) int64 { return sec }),
+	"unix_ms": func(s string, _ *time.Location) (time.Time, error) {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || ms > 1e15 || ms < -1e15 {
+			return time.Time{}, fmt.Errorf("expected an integer number of milliseconds")
+		}
+		return time.UnixMilli(ms), nil
+	},
+	"ntp":          secondScaleParser(func(sec int64) int64 { return sec - ntpEpochOffset }),
+	"gps":          secondScaleParser(func(sec int64) int64 { return utcFromTAI(sec + gpsEpochOffset + 19) }),
+	"tai":          secondScaleParser(utcFromTAI),
+	"filetime":     tickParser(filetimeEpochOffset),
+	"dotnet_ticks": tickParser(dotnetEpochOffset),
+	"excel_serial": parseExcelSerial,
+	"hebrew": calendarDateParser(hebrewMonths, func(y, m int, _ bool, d int) (int, error) {
+		if m < 1 || m > hebrewLastMonth(y) {
+			return 0, fmt.Errorf("month must be 1-%d in %d", hebrewLastMonth(y), y)
+		}
+		if d < 1 || d > hebrewMonthDays(y, m) {
+			return 0, fmt.Errorf("%s %d has %d days", hebrewMonthName(y, m), y, hebrewMonthDays(y, m))
+		}
+		return fixedFromHebrew(y, m, d), nil
+	}),
+	"islamic": calendarDateParser(islamicMonths, func(y, m int, _ bool, d int) (int, error) {
+		if m < 1 || m > 12 {
+			return 0, fmt.Errorf("month must be 1-12")
+		}
+		if d < 1 || d > islamicMonthDays(y, m) {
+			return 0, fmt.Errorf("%s %d has %d days", islamicMonths[m-1], y, islamicMonthDays(y, m))
+		}
+		return fixedFromIslamic(y, m, d), nil
+	}),
+	"persian": calendarDateParser(persianMonths, func(y, m int, _ bool, d int) (int, error) {
+		newYear, leap, ok := persianYear(y)
+		if !ok {
+			return 0, fmt.Errorf("persian years must be between %d and %d", persianBreaks[0], persianBreaks[len(persianBreaks)-1]-1)
+		}
+		if m < 1 || m > 12 {
+			return 0, fmt.Errorf("month must be 1-12")
+		}
+		if days := persianMonthDays(m, leap); d < 1 || d > days {
+			return 0, fmt.Errorf("%s %d has %d days", persianMonths[m-1], y, days)
+		}
+		return newYear + persianDayOfYear(m, d) - 1, nil
+	}),
+	"chinese": calendarDateParser(nil, fixedFromChinese),
+}
+
+// dayScaleParser reads a (fractional) day count whose value at the Unix
+// epoch is epoch, such as a Julian day.
+func dayScaleParser(epoch float64) func(string, *time.Location) (time.Time, error) {
+	return func(s string, _ *time.Location) (time.Time, error) {
+		days, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(days) || math.Abs(days-epoch) > 4e6 {
+			return time.Time{}, fmt.Errorf("expected a number of days")
+		}
+		ms := math.Round((days - epoch) * 86400e3)
+		return time.UnixMilli(int64(ms)), nil
+	}
+}
+
+var secondsPattern = regexp.MustCompile(`^(-?)(\d{1,13})(?:\.(\d{1,9}))?$`)
+
+// secondScaleParser reads a (fractional) second count and converts its
+// whole seconds to Unix time with toUnix.
+func secondScaleParser(toUnix func(int64) int64) func(string, *time.Location) (time.Time, error) {
+	return func(s string, _ *time.Location) (time.Time, error) {
+		m := secondsPattern.FindStringSubmatch(s)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("expected a number of seconds")
+		}
+		sec, _ := strconv.ParseInt(m[2], 10, 64)
+		var nsec int64
+		if m[3] != "" {
+			nsec, _ = strconv.ParseInt(m[3]+strings.Repeat("0", 9-len(m[3])), 10, 64)
+		}
+		if m[1] == "-" {
+			sec = -sec
+			if nsec > 0 {
+				sec--
+				nsec = 1e9 - nsec
+			}
+		}
+		return time.Unix(toUnix(sec), nsec), nil
+	}
+}
+
+// tickParser reads a count of 100-nanosecond ticks from an epoch offset
+// seconds before the Unix epoch.
+func tickParser(offset int64) func(string, *time.Location) (time.Time, error) {
+	return func(s string, _ *time.Location) (time.Time, error) {
+		ticks, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || ticks < 0 {
+			return time.Time{}, fmt.Errorf("expected a non-negative integer number of ticks")
+		}
+		return time.Unix(ticks/1e7-offset, ticks%1e7*100), nil
+	}
+}
+
+// parseExcelSerial reads an Excel (1900 date system) serial date in loc.
+// Excel counts the nonexistent 29 February 1900 as serial 60, so earlier
+// serials are one day off from later ones.
+func parseExcelSerial(s string, loc *time.Location) (time.Time, error) {
+	serial, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(serial) || serial < 0 || serial > 3e6 {
+		return time.Time{}, fmt.Errorf("expected a non-negative number of days")
+	}
+	day := math.Floor(serial)
+	if day == 60 {
+		return time.Time{}, fmt.Errorf("serial 60 is Excel's nonexistent 29 February 1900")
+	}
+	if day < 60 {
+		day++
+	}
+	g := gregorianFromFixed(excelEpoch + int(day))
+	ms := math.Round((serial - math.Floor(serial)) * 86400e3)
+	return time.Date(g.Year(), g.Month(), g.Day(), 0, 0, 0, int(ms)*1e6, loc), nil
+}
+
+// excelEpoch is the fixed date of Excel serial 0 from March 1900 on.
+var excelEpoch = fixedFromGregorian(1899, time.December, 30)
+
+var (
+	isoWeekPattern = regexp.MustCompile(`^(\d{4})-?[Ww](\d{2})(?:-?([1-7]))?$`)
+	ordinalPattern = regexp.MustCompile(`^(\d{4})-?(\d{3})$`)
+	// calendarDatePattern matches year-month-day, where the month may be
+	// a name ("Rabi al-Awwal").
+	calendarDatePattern = regexp.MustCompile(`^(-?\d{1,4})[-/ ](.+?)[-/ ](\d{1,2})$`)
+	// leapMonthPattern matches a numeric month with a leap marker ("6L"
+	// or "L6").
+	leapMonthPattern = regexp.MustCompile(`^([lL]?)(\d{1,2})([lL]?)$`)
+)
+
+// parseISOWeekDate reads an ISO 8601 week date ("2026-W42-5" or
+// "2026W425"); a missing weekday means Monday.
+func parseISOWeekDate(s string, loc *time.Location) (time.Time, error) {
+	m := isoWeekPattern.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-Www[-D]")
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	day := 1
+	if m[3] != "" {
+		day, _ = strconv.Atoi(m[3])
+	}
+	jan4 := fixedFromGregorian(year, time.January, 4)
+	g := gregorianFromFixed(jan4 - isoWeekday(jan4) + 1 + (week-1)*7 + day - 1)
+	if y, w := g.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, week)
+	}
+	return time.Date(g.Year(), g.Month(), g.Day(), 0, 0, 0, 0, loc), nil
+}
+
+// parseOrdinalDate reads an ISO 8601 ordinal date ("2026-289").
+func parseOrdinalDate(s string, loc *time.Location) (time.Time, error) {
+	m := ordinalPattern.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-DDD")
+	}
+	year, _ := strconv.Atoi(m[1])
+	day, _ := strconv.Atoi(m[2])
+	days := 365
+	if isLeapYear(year) {
+		days = 366
+	}
+	if day < 1 || day > days {
+		return time.Time{}, fmt.Errorf("%d has %d days", year, days)
+	}
+	return time.Date(year, time.January, day, 0, 0, 0, 0, loc), nil
+}
+
+// calendarDateParser reads year-month-day in a non-Gregorian calendar,
+// resolving month names against months, and converts it with toFixed.
+func calendarDateParser(months []string, toFixed func(y, m int, leap bool, d int) (int, error)) func(string, *time.Location) (time.Time, error) {
+	return func(s string, loc *time.Location) (time.Time, error) {
+		p := calendarDatePattern.FindStringSubmatch(s)
+		if p == nil {
+			return time.Time{}, fmt.Errorf("expected YYYY-MM-DD")
+		}
+		year, _ := strconv.Atoi(p[1])
+		day, _ := strconv.Atoi(p[3])
+		var month int
+		var leap bool
+		if lm := leapMonthPattern.FindStringSubmatch(p[2]); lm != nil {
+			month, _ = strconv.Atoi(lm[2])
+			leap = lm[1] != "" || lm[3] != ""
+			if leap && months != nil {
+				return time.Time{}, fmt.Errorf("leap months only apply to the chinese calendar")
+			}
+		} else if month = monthByName(months, p[2]); month == 0 {
+			return time.Time{}, fmt.Errorf("unknown month %q", p[2])
+		}
+		f, err := toFixed(year, month, leap, day)
+		if err != nil {
+			return time.Time{}, err
+		}
+		g := gregorianFromFixed(f)
+		return time.Date(g.Year(), g.Month(), g.Day(), 0, 0, 0, 0, loc), nil
+	}
+}
+
+// monthByName returns the 1-based index of name in months, ignoring case
+// and anything but letters, or 0. "Adar I" is accepted for Adar.
+func monthByName(months []string, name string) int {
+	key := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+				return r | 0x20
+			}
+			return -1
+		}, s)
+	}
+	name = key(name)
+	if name == "adari" {
+		name = "adar"
+	}
+	for i, m := range months {
+		if key(m) == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// calendarDates breaks the local day of t down in every calendar.
+func calendarDates(t time.Time) map[string]interface{} {
+	f := fixedFromGregorian(t.Year(), t.Month(), t.Day())
+	isoYear, isoWeek := t.ISOWeek()
+
+	dates := map[string]interface{}{
+		"gregorian": map[string]interface{}{
+			"year":        t.Year(),
+			"month":       int(t.Month()),
+			"day":         t.Day(),
+			"month_name":  t.Month().String(),
+			"day_of_week": t.Weekday().String(),
+			"leap_year":   isLeapYear(t.Year()),
+			"date":        t.Format("2006-01-02"),
+		},
+		"iso_week": map[string]interface{}{
+			"year":    isoYear,
+			"week":    isoWeek,
+			"weekday": isoWeekday(f),
+			"date":    fmt.Sprintf("%04d-W%02d-%d", isoYear, isoWeek, isoWeekday(f)),
+		},
+		"ordinal": map[string]interface{}{
+			"year": t.Year(),
+			"day":  t.YearDay(),
+			"date": fmt.Sprintf("%04d-%03d", t.Year(), t.YearDay()),
+		},
+	}
+
+	hy, hm, hd := hebrewFromFixed(f)
+	dates["hebrew"] = yearMonthDay(hy, hm, hd, hebrewMonthName(hy, hm), hebrewLeapYear(hy))
+
+	iy, im, id := islamicFromFixed(f)
+	dates["islamic"] = yearMonthDay(iy, im, id, islamicMonths[im-1], islamicLeapYear(iy))
+
+	dates["persian"] = nil
+	if py, pm, pd, ok := persianFromFixed(f); ok {
+		_, leap, _ := persianYear(py)
+		dates["persian"] = yearMonthDay(py, pm, pd, persianMonths[pm-1], leap)
+	}
+
+	dates["chinese"] = nil
+	if t.Year() >= ChineseMinYear && t.Year() <= ChineseMaxYear {
+		c := chineseFromFixed(f)
+		month, leap := strconv.Itoa(c.Month), ""
+		if c.Leap {
+			month += "L"
+			leap = "leap "
+		}
+		dates["chinese"] = map[string]interface{}{
+			"year":        c.Year,
+			"cycle":       c.Cycle,
+			"cycle_year":  c.CycleYear,
+			"month":       c.Month,
+			"leap_month":  c.Leap,
+			"day":         c.Day,
+			"stem_branch": c.stemBranch(),
+			"zodiac":      c.zodiac(),
+			"date":        fmt.Sprintf("%d-%s-%d", c.Year, month, c.Day),
+			"formatted":   fmt.Sprintf("Day %d of %smonth %d, %s (%s) year", c.Day, leap, c.Month, c.stemBranch(), c.zodiac()),
+		}
+	}
+	return dates
+}
+
+// yearMonthDay is the breakdown of a Hebrew, Islamic or Persian date.
+func yearMonthDay(year, month, day int, monthName string, leapYear bool) map[string]interface{} {
+	return map[string]interface{}{
+		"year":       year,
+		"month":      month,
+		"day":        day,
+		"month_name": monthName,
+		"leap_year":  leapYear,
+		"date":       fmt.Sprintf("%d-%02d-%02d", year, month, day),
+		"formatted":  fmt.Sprintf("%d %s %d", day, monthName, year),
+	}
+}
+
+// epochValues expresses t on each time scale. Second-based scales are
+// fractional seconds, day-based ones fractional days; the Excel serial
+// uses t's wall clock and is nil before 1900.
+func epochValues(t time.Time) map[string]interface{} {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	seconds := func(offset int64) float64 {
+		return float64(sec+offset) + float64(nsec)/1e9
+	}
+	days := func(v float64) float64 {
+		return math.Round(v*1e8) / 1e8
+	}
+	leap := taiOffset(sec)
+	gps := leap - 19 - gpsEpochOffset
+
+	values := map[string]interface{}{
+		"unix":                seconds(0),
+		"unix_ms":             t.UnixMilli(),
+		"julian_day":          days(seconds(0)/86400 + unixJulianDay),
+		"modified_julian_day": days(seconds(0)/86400 + unixMJD),
+		"ntp":                 seconds(ntpEpochOffset),
+		"gps":                 seconds(gps),
+		"gps_week":            floorDiv(int(sec+gps), 604800),
+		"tai":                 seconds(leap),
+		"tai_utc_offset":      leap,
+		"filetime":            (sec+filetimeEpochOffset)*1e7 + nsec/100,
+		"dotnet_ticks":        (sec+dotnetEpochOffset)*1e7 + nsec/100,
+		"excel_serial":        nil,
+	}
+
+	f := fixedFromGregorian(t.Year(), t.Month(), t.Day()) - excelEpoch
+	if f < 61 {
+		f--
+	}
+	if f >= 0 {
+		wall := float64(t.Hour()*3600+t.Minute()*60+t.Second()) + float64(nsec)/1e9
+		values["excel_serial"] = days(float64(f) + wall/86400)
+	}
+	return values
+}
+
+// fixedFromGregorian returns the fixed day number of a proleptic
+// Gregorian date, counting 1 January 1 as day 1.
+func fixedFromGregorian(year int, month time.Month, day int) int {
+	return floorDiv(int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()), 86400) + unixFixed
+}
+
+// gregorianFromFixed returns midnight UTC on fixed day f.
+func gregorianFromFixed(f int) time.Time {
+	return time.Unix(int64(f-unixFixed)*86400, 0).UTC()
+}
+
+// isoWeekday returns the ISO weekday (Monday 1 to Sunday 7) of fixed day f.
+func isoWeekday(f int) int {
+	return floorMod(f-1, 7) + 1
+}
+
+func floorMod(a, b int) int {
+	return a - b*floorDiv(a, b)
+}
+
+// hebrewEpoch is the fixed date of 1 Tishrei AM 1 (7 October 3761 BCE
+// in the Julian calendar).
+const hebrewEpoch = -1373427
+
+func hebrewLeapYear(y int) bool {
+	return floorMod(7*y+1, 19) < 7
+}
+
+func hebrewLastMonth(y int) int {
+	if hebrewLeapYear(y) {
+		return 13
+	}
+	return 12
+}
+
+// hebrewElapsedDays returns the days from the epoch to the molad of
+// Tishrei of year y, postponed by the rule that Rosh Hashanah never
+// falls on Sunday, Wednesday or Friday.
+func hebrewElapsedDays(y int) int {
+	months := floorDiv(235*y-234, 19)
+	parts := 12084 + 13753*months
+	days := 29*months + floorDiv(parts, 25920)
+	if floorMod(3*(days+1), 7) < 3 {
+		return days + 1
+	}
+	return days
+}
+
+// hebrewNewYear returns the fixed date of 1 Tishrei of year y, delayed
+// where needed to keep every year 353-355 or 383-385 days long.
+func hebrewNewYear(y int) int {
+	ny0, ny1, ny2 := hebrewElapsedDays(y-1), hebrewElapsedDays(y), hebrewElapsedDays(y+1)
+	correction := 0
+	switch {
+	case ny2-ny1 == 356:
+		correction = 2
+	case ny1-ny0 == 382:
+		correction = 1
+	}
+	return hebrewEpoch + ny1 + correction
+}
+
+func hebrewMonthDays(y, m int) int {
+	yearDays := hebrewNewYear(y+1) - hebrewNewYear(y)
+	switch {
+	case m == 2 || m == 4 || m == 6 || m == 10 || m == 13,
+		m == 12 && !hebrewLeapYear(y),
+		m == 8 && yearDays%10 != 5,
+		m == 9 && yearDays%10 == 3:
+		return 29
+	}
+	return 30
+}
+
+func hebrewMonthName(y, m int) string {
+	if m == 12 && hebrewLeapYear(y) {
+		return "Adar I"
+	}
+	return hebrewMonths[m-1]
+}
+
+func fixedFromHebrew(y, m, d int) int {
+	f := hebrewNewYear(y) + d - 1
+	if m < 7 {
+		for i := 7; i <= hebrewLastMonth(y); i++ {
+			f += hebrewMonthDays(y, i)
+		}
+		for i := 1; i < m; i++ {
+			f += hebrewMonthDays(y, i)
+		}
+	} else {
+		for i := 7; i < m; i++ {
+			f += hebrewMonthDays(y, i)
+		}
+	}
+	return f
+}
+
+func hebrewFromFixed(f int) (y, m, d int) {
+	y = floorDiv((f-hebrewEpoch)*98496, 35975351)
+	for hebrewNewYear(y+1) <= f {
+		y++
+	}
+	m = 1
+	if f < fixedFromHebrew(y, 1, 1) {
+		m = 7
+	}
+	for f > fixedFromHebrew(y, m, hebrewMonthDays(y, m)) {
+		m++
+	}
+	return y, m, f - fixedFromHebrew(y, m, 1) + 1
+}
+
+// islamicEpoch is the fixed date of 1 Muharram AH 1 (16 July 622 in the
+// Julian calendar).
+const islamicEpoch = 227015
+
+func islamicLeapYear(y int) bool {
+	return floorMod(14+11*y, 30) < 11
+}
+
+func islamicMonthDays(y, m int) int {
+	if m%2 == 1 || m == 12 && islamicLeapYear(y) {
+		return 30
+	}
+	return 29
+}
+
+func fixedFromIslamic(y, m, d int) int {
+	return d + 29*(m-1) + floorDiv(6*m-1, 11) + (y-1)*354 + floorDiv(3+11*y, 30) + islamicEpoch - 1
+}
+
+func islamicFromFixed(f int) (y, m, d int) {
+	y = floorDiv(30*(f-islamicEpoch)+10646, 10631)
+	m = floorDiv(11*(f-fixedFromIslamic(y, 1, 1))+330, 325)
+	return y, m, f - fixedFromIslamic(y, m, 1) + 1
+}
+
+// persianBreaks are the years that begin a new run of 33-year cycles in
+// Borkowski's arithmetic for the Persian calendar, valid from the first
+// to just before the last entry.
+var persianBreaks = []int{-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210, 1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178}
+
+// persianYear returns the fixed date of 1 Farvardin of year y and whether
+// y is a leap year; ok is false outside persianBreaks.
+func persianYear(y int) (newYear int, leap, ok bool) {
+	last := persianBreaks[len(persianBreaks)-1]
+	if y < persianBreaks[0] || y >= last {
+		return 0, false, false
+	}
+	gy := y + 621
+	leapJ, jp, jump := -14, persianBreaks[0], 0
+	for _, jm := range persianBreaks[1:] {
+		jump = jm - jp
+		if y < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := y - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march := 20 + leapJ - leapG
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	return fixedFromGregorian(gy, time.March, march), ((n+1)%33-1)%4 == 0, true
+}
+
+// persianDayOfYear returns the day of the year (from 1) of day d of
+// month m: six months of 31 days, then five of 30 and Esfand.
+func persianDayOfYear(m, d int) int {
+	return (m-1)*31 - m/7*(m-7) + d
+}
+
+func persianMonthDays(m int, leap bool) int {
+	switch {
+	case m <= 6:
+		return 31
+	case m < 12 || leap:
+		return 30
+	}
+	return 29
+}
+
+func persianFromFixed(f int) (y, m, d int, ok bool) {
+	y = gregorianFromFixed(f).Year() - 621
+	newYear, _, ok := persianYear(y)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	k := f - newYear
+	if k >= 0 && k <= 185 {
+		return y, 1 + k/31, k%31 + 1, true
+	}
+	if k > 185 {
+		k -= 186
+	} else {
+		y--
+		_, leap, ok := persianYear(y)
+		if !ok {
+			return 0, 0, 0, false
+		}
+		k += 179
+		if leap {
+			k++
+		}
+	}
+	return y, 7 + k/30, k%30 + 1, true
+}
@@ -0,0 +1,132 @@
+package datetime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// convertedDate converts value and returns the local Gregorian date.
+func convertedDate(t *testing.T, value, from string) string {
+	t.Helper()
+	result, err := ConvertCalendar(value, CalendarOptions{From: from})
+	require.NoError(t, err, "%s %s", from, value)
+	return result["calendars"].(map[string]interface{})["gregorian"].(map[string]interface{})["date"].(string)
+}
+
+func TestConvertCalendar(t *testing.T) {
+	result, err := ConvertCalendar("2026-10-16T10:30:00Z", CalendarOptions{Timezone: "Asia/Tehran", Format: "rfc1123"})
+	require.NoError(t, err)
+	assert.Equal(t, "gregorian", result["from"])
+	assert.Equal(t, "2026-10-16T14:00:00+03:30", result["datetime"].(map[string]interface{})["iso8601"])
+	// The formatted time follows the requested timezone too.
+	assert.Equal(t, "Fri, 16 Oct 2026 14:00:00 +0330", result["formatted"].(map[string]interface{})["result"])
+
+	cals := result["calendars"].(map[string]interface{})
+	assert.Equal(t, "2026-W42-5", cals["iso_week"].(map[string]interface{})["date"])
+	assert.Equal(t, "2026-289", cals["ordinal"].(map[string]interface{})["date"])
+	assert.Equal(t, "5 Cheshvan 5787", cals["hebrew"].(map[string]interface{})["formatted"])
+	assert.Equal(t, true, cals["hebrew"].(map[string]interface{})["leap_year"])
+	assert.Equal(t, "4 Jumada al-Awwal 1448", cals["islamic"].(map[string]interface{})["formatted"])
+	assert.Equal(t, "24 Mehr 1405", cals["persian"].(map[string]interface{})["formatted"])
+	chinese := cals["chinese"].(map[string]interface{})
+	assert.Equal(t, "2026-9-7", chinese["date"])
+	assert.Equal(t, "Bing-Wu", chinese["stem_branch"])
+	assert.Equal(t, "Horse", chinese["zodiac"])
+
+	epochs := result["epochs"].(map[string]interface{})
+	assert.Equal(t, 2461329.9375, epochs["julian_day"])
+	assert.Equal(t, 61329.4375, epochs["modified_julian_day"])
+	assert.Equal(t, float64(4001135400), epochs["ntp"])
+	assert.Equal(t, float64(1476181818), epochs["gps"])
+	assert.Equal(t, float64(1792146637), epochs["tai"])
+	assert.Equal(t, int64(134366202000000000), epochs["filetime"])
+	assert.Equal(t, int64(639277434000000000), epochs["dotnet_ticks"])
+	// The Excel serial follows the Tehran wall clock.
+	assert.Equal(t, 46311.58333333, epochs["excel_serial"])
+}
+
+func TestConvertCalendarInputs(t *testing.T) {
+	tests := []struct {
+		value, from, want string
+	}{
+		{"2026-W53", "iso_week", "2026-12-28"},
+		{"2020W537", "iso-week", "2021-01-03"},
+		{"2024-366", "ordinal", "2024-12-31"},
+		{"2460000.5", "jd", "2023-02-25"},
+		{"60000", "mjd", "2023-02-25"},
+		{"0", "ntp", "1900-01-01"},
+		{"0", "gps", "1980-01-06"},
+		{"116444736000000000", "filetime", "1970-01-01"},
+		{"0", "dotnet", "0001-01-01"},
+		{"59", "excel", "1900-02-28"},
+		{"61", "excel", "1900-03-01"},
+		{"5785-7-1", "hebrew", "2024-10-03"},
+		{"5787-Tishrei-1", "hebrew", "2026-09-12"},
+		{"5784-Adar I-1", "hebrew", "2024-02-10"},
+		{"5784-adar ii-1", "hebrew", "2024-03-11"},
+		{"1445-9-1", "islamic", "2024-03-11"},
+		{"1447-Rabi' al-Awwal-1", "hijri", "2025-08-25"},
+		{"1403-1-1", "persian", "2024-03-20"},
+		{"1403-12-30", "persian", "2025-03-20"},
+		{"2026-1-1", "chinese", "2026-02-17"},
+		{"2023-2L-1", "chinese", "2023-03-22"},
+		{"2025-L6-1", "chinese", "2025-07-25"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, convertedDate(t, tt.value, tt.from), "%s %s", tt.from, tt.value)
+	}
+
+	// A TAI leap second maps to the following midnight UTC.
+	for _, tai := range []string{"1483228836", "1483228837"} {
+		result, err := ConvertCalendar(tai, CalendarOptions{From: "tai"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1483228800), result["datetime"].(map[string]interface{})["unix"])
+	}
+
+	result, err := ConvertCalendar("-1.5", CalendarOptions{From: "unix"})
+	require.NoError(t, err)
+	assert.Equal(t, -1.5, result["epochs"].(map[string]interface{})["unix"])
+
+	for _, bad := range [][2]string{
+		{"2026-10-16", "mayan"},
+		{"", "gregorian"},
+		{"2025-W53", "iso_week"},
+		{"2025-366", "ordinal"},
+		{"60", "excel"},
+		{"5785-13-1", "hebrew"},
+		{"5787-Nisanx-1", "hebrew"},
+		{"1404-12-30", "persian"},
+		{"1445-6L-1", "islamic"},
+		{"2024-2L-1", "chinese"},
+		{"2024-1-30", "chinese"},
+		{"1850-1-1", "chinese"},
+		{"999999999999", "unix"},
+		{"abc", "jd"},
+	} {
+		_, err := ConvertCalendar(bad[0], CalendarOptions{From: bad[1]})
+		assert.Error(t, err, "%s %s", bad[1], bad[0])
+	}
+	_, err = ConvertCalendar("0", CalendarOptions{From: "unix", Timezone: "Mars/Olympus"})
+	assert.Error(t, err)
+}
+
+func TestCalendarRoundTrips(t *testing.T) {
+	// Every day from 2023 to 2026 converts back to itself.
+	for f := fixedFromGregorian(2023, 1, 1); f < fixedFromGregorian(2027, 1, 1); f++ {
+		y, m, d := hebrewFromFixed(f)
+		require.Equal(t, f, fixedFromHebrew(y, m, d))
+		y, m, d = islamicFromFixed(f)
+		require.Equal(t, f, fixedFromIslamic(y, m, d))
+		y, m, d, ok := persianFromFixed(f)
+		require.True(t, ok)
+		newYear, _, _ := persianYear(y)
+		require.Equal(t, f, newYear+persianDayOfYear(m, d)-1)
+	}
+
+	// The Hebrew year 5784 is a 383-day leap year; 5785 has 355 days.
+	assert.True(t, hebrewLeapYear(5784))
+	assert.Equal(t, 383, hebrewNewYear(5785)-hebrewNewYear(5784))
+	assert.Equal(t, 355, hebrewNewYear(5786)-hebrewNewYear(5785))
+}
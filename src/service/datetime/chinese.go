@@ -0,0 +1,360 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// The Chinese calendar follows the rules in Reingold and Dershowitz's
+// Calendrical Calculations: a month begins on the day (in Beijing) of a
+// new moon, the winter solstice always falls in month 11, and in a year
+// of 13 months the first month containing no major solar term is the
+// leap month. New moons use Meeus' series (Astronomical Algorithms, ch.
+// 49) and the Sun's longitude his low-precision theory (ch. 25), which
+// place both to within minutes over the supported years.
+
+const (
+	meanSynodicMonth = 29.530588861
+	meanTropicalYear = 365.242189
+
+	// fixedJulianDay is the Julian day at the start of fixed date 0.
+	fixedJulianDay = 1721424.5
+
+	// ChineseMinYear and ChineseMaxYear bound the Gregorian years
+	// ConvertCalendar reports Chinese dates for.
+	ChineseMinYear = 1900
+	ChineseMaxYear = 2100
+)
+
+// chinaStandardTimeStart is the Julian day Beijing moved from local mean
+// time to UTC+8, 1 January 1929.
+var chinaStandardTimeStart = float64(fixedFromGregorian(1929, time.January, 1)) + fixedJulianDay - 8.0/24
+
+// chineseEpoch is the fixed date of the first year of the first
+// sexagenary cycle, 15 February 2637 BCE.
+var chineseEpoch = fixedFromGregorian(-2636, time.February, 15)
+
+var (
+	celestialStems      = []string{"Jia", "Yi", "Bing", "Ding", "Wu", "Ji", "Geng", "Xin", "Ren", "Gui"}
+	terrestrialBranches = []string{"Zi", "Chou", "Yin", "Mao", "Chen", "Si", "Wu", "Wei", "Shen", "You", "Xu", "Hai"}
+	chineseZodiac       = []string{"Rat", "Ox", "Tiger", "Rabbit", "Dragon", "Snake", "Horse", "Goat", "Monkey", "Rooster", "Dog", "Pig"}
+)
+
+// chineseDate is a date in the Chinese calendar. Year is the Gregorian
+// year in which the Chinese year begins; Cycle and CycleYear (1-60)
+// number it within the sexagenary cycles counted from chineseEpoch.
+type chineseDate struct {
+	Year      int
+	Cycle     int
+	CycleYear int
+	Month     int
+	Leap      bool
+	Day       int
+}
+
+// deltaT estimates TT − UT in seconds at Julian day jd with the
+// polynomials of Espenak and Meeus.
+func deltaT(jd float64) float64 {
+	y := 2000 + (jd-2451545)/365.25
+	switch {
+	case y < 1920:
+		t := y - 1900
+		return -2.79 + 1.494119*t - 0.0598939*t*t + 0.0061966*t*t*t - 0.000197*t*t*t*t
+	case y < 1941:
+		t := y - 1920
+		return 21.20 + 0.84493*t - 0.076100*t*t + 0.0020936*t*t*t
+	case y < 1961:
+		t := y - 1950
+		return 29.07 + 0.407*t - t*t/233 + t*t*t/2547
+	case y < 1986:
+		t := y - 1975
+		return 45.45 + 1.067*t - t*t/260 - t*t*t/718
+	case y < 2005:
+		t := y - 2000
+		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t + 0.000651814*t*t*t*t + 0.00002373599*t*t*t*t*t
+	case y < 2050:
+		t := y - 2000
+		return 62.92 + 0.32217*t + 0.005589*t*t
+	default:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-y)
+	}
+}
+
+// solarLongitude returns the Sun's apparent longitude in degrees at
+// Julian day jd (UT).
+func solarLongitude(jd float64) float64 {
+	t := (jd + deltaT(jd)/86400 - 2451545) / 36525
+	l0 := 280.46646 + 36000.76983*t + 0.0003032*t*t
+	m := 357.52911 + 35999.05029*t - 0.0001537*t*t
+	c := (1.914602-0.004817*t-0.000014*t*t)*sinDeg(m) +
+		(0.019993-0.000101*t)*sinDeg(2*m) +
+		0.000289*sinDeg(3*m)
+	omega := 125.04 - 1934.136*t
+	return normalizeDegrees(l0 + c - 0.00569 - 0.00478*sinDeg(omega))
+}
+
+// solarLongitudeAfter returns the first Julian day after jd at which the
+// Sun's longitude is lambda degrees.
+func solarLongitudeAfter(lambda, jd float64) float64 {
+	rate := 360 / meanTropicalYear
+	t := jd + math.Mod(lambda-solarLongitude(jd)+360, 360)/rate
+	for i := 0; i < 10; i++ {
+		diff := math.Mod(lambda-solarLongitude(t)+540, 360) - 180
+		t += diff / rate
+		if math.Abs(diff) < 1e-7 {
+			break
+		}
+	}
+	return t
+}
+
+// newMoonTerm is one periodic term of the new moon correction: the
+// coefficient, the power of the eccentricity factor E, and the
+// multiples of M, M', F and Ω in the argument.
+type newMoonTerm struct {
+	coeff           float64
+	e               int
+	m, mp, f, omega float64
+}
+
+var newMoonTerms = []newMoonTerm{
+	{-0.40720, 0, 0, 1, 0, 0},
+	{0.17241, 1, 1, 0, 0, 0},
+	{0.01608, 0, 0, 2, 0, 0},
+	{0.01039, 0, 0, 0, 2, 0},
+	{0.00739, 1, -1, 1, 0, 0},
+	{-0.00514, 1, 1, 1, 0, 0},
+	{0.00208, 2, 2, 0, 0, 0},
+	{-0.00111, 0, 0, 1, -2, 0},
+	{-0.00057, 0, 0, 1, 2, 0},
+	{0.00056, 1, 1, 2, 0, 0},
+	{-0.00042, 0, 0, 3, 0, 0},
+	{0.00042, 1, 1, 0, 2, 0},
+	{0.00038, 1, 1, 0, -2, 0},
+	{-0.00024, 1, -1, 2, 0, 0},
+	{-0.00017, 0, 0, 0, 0, 1},
+	{-0.00007, 0, 2, 1, 0, 0},
+	{0.00004, 0, 0, 2, -2, 0},
+	{0.00004, 0, 3, 0, 0, 0},
+	{0.00003, 0, 1, 1, -2, 0},
+	{0.00003, 0, 0, 2, 2, 0},
+	{-0.00003, 0, 1, 1, 2, 0},
+	{0.00003, 0, -1, 1, 2, 0},
+	{-0.00002, 0, -1, 1, -2, 0},
+	{-0.00002, 0, 1, 3, 0, 0},
+	{0.00002, 0, 0, 4, 0, 0},
+}
+
+// newMoonPlanetary holds the constant, k rate and coefficient of the
+// fourteen planetary arguments added to every new moon.
+var newMoonPlanetary = [][3]float64{
+	{299.77, 0.107408, 0.000325},
+	{251.88, 0.016321, 0.000165},
+	{251.83, 26.651886, 0.000164},
+	{349.42, 36.412478, 0.000126},
+	{84.66, 18.206239, 0.000110},
+	{141.74, 53.303771, 0.000062},
+	{207.14, 2.453732, 0.000060},
+	{154.84, 7.306860, 0.000056},
+	{34.52, 27.261239, 0.000047},
+	{207.19, 0.121824, 0.000042},
+	{291.34, 1.844379, 0.000040},
+	{161.72, 24.198154, 0.000037},
+	{239.56, 25.513099, 0.000035},
+	{331.55, 3.592518, 0.000023},
+}
+
+// newMoon returns the Julian day (UT) of new moon number k, counted from
+// the new moon of 6 January 2000.
+func newMoon(k float64) float64 {
+	t := k / 1236.85
+	jde := 2451550.09766 + meanSynodicMonth*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	m := 2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t
+	mp := 201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t
+	f := 160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t
+	omega := 124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t
+
+	for _, term := range newMoonTerms {
+		arg := term.m*m + term.mp*mp + term.f*f + term.omega*omega
+		jde += term.coeff * math.Pow(e, float64(term.e)) * sinDeg(arg)
+	}
+	for i, p := range newMoonPlanetary {
+		arg := p[0] + p[1]*k
+		if i == 0 {
+			arg -= 0.009173 * t * t
+		}
+		jde += p[2] * sinDeg(arg)
+	}
+	return jde - deltaT(jde)/86400
+}
+
+// newMoonAtOrAfter returns the Julian day of the first new moon at or
+// after jd.
+func newMoonAtOrAfter(jd float64) float64 {
+	k := math.Floor((jd-2451550.09766)/meanSynodicMonth) - 1
+	for newMoon(k) < jd {
+		k++
+	}
+	return newMoon(k)
+}
+
+// newMoonBefore returns the Julian day of the last new moon before jd.
+func newMoonBefore(jd float64) float64 {
+	k := math.Floor((jd-2451550.09766)/meanSynodicMonth) + 2
+	for newMoon(k) >= jd {
+		k--
+	}
+	return newMoon(k)
+}
+
+// chinaOffset is Beijing's offset from UT in days at Julian day jd: its
+// local mean time until 1929 and UTC+8 since.
+func chinaOffset(jd float64) float64 {
+	if jd < chinaStandardTimeStart {
+		return 1397.0 / 180 / 24
+	}
+	return 8.0 / 24
+}
+
+// chinaDate returns the fixed date in Beijing at Julian day jd.
+func chinaDate(jd float64) int {
+	return int(math.Floor(jd + chinaOffset(jd) - fixedJulianDay))
+}
+
+// midnightInChina returns the Julian day at which fixed date d begins in
+// Beijing.
+func midnightInChina(d int) float64 {
+	jd := float64(d) + fixedJulianDay
+	return jd - chinaOffset(jd)
+}
+
+func chineseWinterSolsticeOnOrBefore(d int) int {
+	end := midnightInChina(d + 1)
+	s := solarLongitudeAfter(270, end-370)
+	for {
+		next := solarLongitudeAfter(270, s+1)
+		if next >= end {
+			break
+		}
+		s = next
+	}
+	return chinaDate(s)
+}
+
+func chineseNewMoonOnOrAfter(d int) int {
+	return chinaDate(newMoonAtOrAfter(midnightInChina(d)))
+}
+
+func chineseNewMoonBefore(d int) int {
+	return chinaDate(newMoonBefore(midnightInChina(d)))
+}
+
+// currentMajorSolarTerm returns the major solar term (1-12, where 1
+// begins at longitude 330°) in effect at the start of fixed date d.
+func currentMajorSolarTerm(d int) int {
+	s := solarLongitude(midnightInChina(d))
+	return floorMod(2+int(math.Floor(s/30))-1, 12) + 1
+}
+
+// chineseNoMajorSolarTerm reports whether the month beginning on fixed
+// date d contains no major solar term.
+func chineseNoMajorSolarTerm(d int) bool {
+	return currentMajorSolarTerm(d) == currentMajorSolarTerm(chineseNewMoonOnOrAfter(d+1))
+}
+
+// chinesePriorLeapMonth reports whether any month from the one starting
+// on mPrime to the one starting on m lacks a major solar term.
+func chinesePriorLeapMonth(mPrime, m int) bool {
+	for m >= mPrime {
+		if chineseNoMajorSolarTerm(m) {
+			return true
+		}
+		m = chineseNewMoonBefore(m)
+	}
+	return false
+}
+
+// chineseNewYearInSui returns the new year in the solar year (sui) from
+// the winter solstice on or before fixed date d to the next one.
+func chineseNewYearInSui(d int) int {
+	s1 := chineseWinterSolsticeOnOrBefore(d)
+	s2 := chineseWinterSolsticeOnOrBefore(s1 + 370)
+	m12 := chineseNewMoonOnOrAfter(s1 + 1)
+	m13 := chineseNewMoonOnOrAfter(m12 + 1)
+	nextM11 := chineseNewMoonBefore(s2 + 1)
+	if math.Round(float64(nextM11-m12)/meanSynodicMonth) == 12 &&
+		(chineseNoMajorSolarTerm(m12) || chineseNoMajorSolarTerm(m13)) {
+		return chineseNewMoonOnOrAfter(m13 + 1)
+	}
+	return m13
+}
+
+func chineseNewYearOnOrBefore(d int) int {
+	if newYear := chineseNewYearInSui(d); d >= newYear {
+		return newYear
+	}
+	return chineseNewYearInSui(d - 180)
+}
+
+// chineseFromFixed converts fixed date d to the Chinese calendar.
+func chineseFromFixed(d int) chineseDate {
+	s1 := chineseWinterSolsticeOnOrBefore(d)
+	s2 := chineseWinterSolsticeOnOrBefore(s1 + 370)
+	m12 := chineseNewMoonOnOrAfter(s1 + 1)
+	nextM11 := chineseNewMoonBefore(s2 + 1)
+	m := chineseNewMoonBefore(d + 1)
+	leapYear := math.Round(float64(nextM11-m12)/meanSynodicMonth) == 12
+
+	month := int(math.Round(float64(m-m12) / meanSynodicMonth))
+	if leapYear && chinesePriorLeapMonth(m12, m) {
+		month--
+	}
+	month = floorMod(month-1, 12) + 1
+	leap := leapYear && chineseNoMajorSolarTerm(m) && !chinesePriorLeapMonth(m12, chineseNewMoonBefore(m))
+
+	elapsed := int(math.Floor(1.5 - float64(month)/12 + float64(d-chineseEpoch)/meanTropicalYear))
+	return chineseDate{
+		Year:      elapsed - 2637,
+		Cycle:     floorDiv(elapsed-1, 60) + 1,
+		CycleYear: floorMod(elapsed-1, 60) + 1,
+		Month:     month,
+		Leap:      leap,
+		Day:       d - m + 1,
+	}
+}
+
+// fixedFromChinese converts a Chinese date, whose year is given as the
+// Gregorian year it begins in, to a fixed date.
+func fixedFromChinese(year, month int, leap bool, day int) (int, error) {
+	if year < ChineseMinYear || year > ChineseMaxYear {
+		return 0, fmt.Errorf("chinese dates are supported for years %d-%d", ChineseMinYear, ChineseMaxYear)
+	}
+	if month < 1 || month > 12 || day < 1 || day > 30 {
+		return 0, fmt.Errorf("month must be 1-12 and day 1-30")
+	}
+	newYear := chineseNewYearOnOrBefore(fixedFromGregorian(year, time.July, 1))
+	p := chineseNewMoonOnOrAfter(newYear + (month-1)*29)
+	if c := chineseFromFixed(p); c.Month != month || c.Leap != leap {
+		p = chineseNewMoonOnOrAfter(p + 1)
+	}
+	d := p + day - 1
+	if c := chineseFromFixed(d); c.Year != year || c.Month != month || c.Leap != leap || c.Day != day {
+		if leap && (c.Month != month || !c.Leap) {
+			return 0, fmt.Errorf("%d has no leap month %d", year, month)
+		}
+		return 0, fmt.Errorf("month %d of %d has fewer than %d days", month, year, day)
+	}
+	return d, nil
+}
+
+// stemBranch names the sexagenary year, e.g. "Bing-Wu".
+func (c chineseDate) stemBranch() string {
+	return celestialStems[(c.CycleYear-1)%10] + "-" + terrestrialBranches[(c.CycleYear-1)%12]
+}
+
+func (c chineseDate) zodiac() string {
+	return chineseZodiac[(c.CycleYear-1)%12]
+}
@@ -0,0 +1,70 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChineseNewYear(t *testing.T) {
+	tests := []struct {
+		year      int
+		newYear   string
+		leapMonth int
+	}{
+		{1900, "1900-01-31", 8},
+		{2001, "2001-01-24", 4},
+		{2014, "2014-01-31", 9},
+		{2017, "2017-01-28", 6},
+		{2020, "2020-01-25", 4},
+		{2023, "2023-01-22", 2},
+		{2024, "2024-02-10", 0},
+		{2025, "2025-01-29", 6},
+		{2026, "2026-02-17", 0},
+		{2028, "2028-01-26", 5},
+		// 2033 is the year whose leap month only the astronomical rules
+		// place correctly.
+		{2033, "2033-01-31", 11},
+	}
+	for _, tt := range tests {
+		newYear := chineseNewYearOnOrBefore(fixedFromGregorian(tt.year, time.July, 1))
+		assert.Equal(t, tt.newYear, gregorianFromFixed(newYear).Format("2006-01-02"), "%d", tt.year)
+
+		leapMonth := 0
+		for m := newYear; ; m = chineseNewMoonOnOrAfter(m + 1) {
+			c := chineseFromFixed(m)
+			if c.Year != tt.year {
+				break
+			}
+			if c.Leap {
+				leapMonth = c.Month
+			}
+		}
+		assert.Equal(t, tt.leapMonth, leapMonth, "%d", tt.year)
+	}
+}
+
+func TestChineseRoundTrip(t *testing.T) {
+	for f := fixedFromGregorian(2025, 1, 1); f < fixedFromGregorian(2026, 1, 1); f++ {
+		c := chineseFromFixed(f)
+		back, err := fixedFromChinese(c.Year, c.Month, c.Leap, c.Day)
+		require.NoError(t, err)
+		require.Equal(t, f, back)
+	}
+
+	c := chineseFromFixed(fixedFromGregorian(2026, time.February, 17))
+	assert.Equal(t, chineseDate{Year: 2026, Cycle: 78, CycleYear: 43, Month: 1, Day: 1}, c)
+	assert.Equal(t, "Bing-Wu", c.stemBranch())
+	assert.Equal(t, "Horse", c.zodiac())
+}
+
+func TestAstronomy(t *testing.T) {
+	// Within minutes of the published December solstice and January new
+	// moon of 2025.
+	solstice := solarLongitudeAfter(270, float64(fixedFromGregorian(2025, time.December, 1))+fixedJulianDay)
+	assert.InDelta(t, 2461031.1271, solstice, 10.0/1440)
+	moon := newMoonAtOrAfter(float64(fixedFromGregorian(2025, time.January, 1)) + fixedJulianDay)
+	assert.InDelta(t, 2460705.0243, moon, 2.0/1440)
+}
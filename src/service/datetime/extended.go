@@ -3,9 +3,10 @@ package datetime
 import (
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"time"
+
+	"github.com/apimgr/api/src/cron"
 )
 
 // namedFormats maps human-friendly format names to Go reference-time
@@ -22,23 +23,26 @@ var namedFormats = map[string]string{
 	"datetime": "2006-01-02 15:04:05",
 }
 
-// FormatDatetime formats a unix timestamp using either a named format
-// (iso8601, rfc3339, rfc1123, rfc822, kitchen, date, time, datetime) or a
-// literal Go reference-time layout string.
+// FormatDatetime formats a unix timestamp in UTC using either a named
+// format (iso8601, rfc3339, rfc1123, rfc822, kitchen, date, time,
+// datetime) or a literal Go reference-time layout string.
 func FormatDatetime(timestamp int64, format string) (map[string]interface{}, error) {
+	return formatTime(time.Unix(timestamp, 0).UTC(), format)
+}
+
+// formatTime is FormatDatetime for t in its own location.
+func formatTime(t time.Time, format string) (map[string]interface{}, error) {
 	if format == "" {
 		return nil, fmt.Errorf("format is required")
 	}
 
-	t := time.Unix(timestamp, 0).UTC()
-
 	layout, ok := namedFormats[strings.ToLower(format)]
 	if !ok {
 		layout = format
 	}
 
 	return map[string]interface{}{
-		"unix":   timestamp,
+		"unix":   t.Unix(),
 		"format": format,
 		"result": t.Format(layout),
 	}, nil
@@ -46,8 +50,15 @@ func FormatDatetime(timestamp int64, format string) (map[string]interface{}, err
 
 // ParseDateString parses a free-form date/time string against a list of
 // common layouts and returns a richer breakdown than ToUnix's bare
-// timestamp (matched layout, date components, weekday).
-func ParseDateString(value string) (map[string]interface{}, error) {
+// timestamp (matched layout, date components, weekday). Strings no
+// layout matches are read as natural-language expressions or ISO 8601
+// durations and intervals (see ParseNatural), relative to opts.
+func ParseDateString(value string, opts ParseOptions) (map[string]interface{}, error) {
+	ref, loc, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+
 	formats := []string{
 		time.RFC3339,
 		time.RFC3339Nano,
@@ -64,31 +75,69 @@ func ParseDateString(value string) (map[string]interface{}, error) {
 	}
 
 	for _, layout := range formats {
-		if t, err := time.Parse(layout, value); err == nil {
-			return map[string]interface{}{
-				"input":          value,
-				"matched_layout": layout,
-				"unix":           t.Unix(),
-				"iso8601":        t.Format(time.RFC3339),
-				"date":           t.Format("2006-01-02"),
-				"time":           t.Format("15:04:05"),
-				"year":           t.Year(),
-				"month":          int(t.Month()),
-				"day":            t.Day(),
-				"day_of_week":    t.Weekday().String(),
-			}, nil
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			res := &NaturalResult{Kind: "instant", Time: t, Interpretation: "an absolute date/time"}
+			if layout == "15:04:05" {
+				res.Time = setClock(ref, t.Hour(), t.Minute(), t.Second())
+				res.Interpretation = "a time of day on the reference date"
+			}
+			return parsedDate(value, layout, res, ref, loc), nil
 		}
 	}
 
-	return nil, fmt.Errorf("unable to parse date string: %s", value)
+	res, err := parseNatural(value, ref, loc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse date string %q: %w", value, err)
+	}
+	return parsedDate(value, "", res, ref, loc), nil
+}
+
+// parsedDate renders a ParseDateString result: the resolved instant's
+// breakdown, plus the end of an interval or the length of a duration.
+func parsedDate(value, layout string, res *NaturalResult, ref time.Time, loc *time.Location) map[string]interface{} {
+	t := res.Time
+	out := map[string]interface{}{
+		"input":          value,
+		"matched_layout": layout,
+		"kind":           res.Kind,
+		"interpretation": res.Interpretation,
+		"reference":      ref.Format(time.RFC3339),
+		"timezone":       loc.String(),
+		"unix":           t.Unix(),
+		"iso8601":        t.Format(time.RFC3339),
+		"date":           t.Format("2006-01-02"),
+		"time":           t.Format("15:04:05"),
+		"year":           t.Year(),
+		"month":          int(t.Month()),
+		"day":            t.Day(),
+		"day_of_week":    t.Weekday().String(),
+	}
+	if res.Duration != nil {
+		out["duration"] = res.Duration.String()
+	}
+	switch res.Kind {
+	case "duration":
+		out["duration_seconds"] = t.Sub(ref).Seconds()
+	case "interval":
+		out["end"] = res.End.Format(time.RFC3339)
+		out["end_unix"] = res.End.Unix()
+		out["duration_seconds"] = res.End.Sub(t).Seconds()
+	}
+	return out
 }
 
 // GenerateCalendar builds a week-grid calendar for the given year/month
-// (weeks start on Sunday), padding leading/trailing days with 0.
-func GenerateCalendar(year, month int) (map[string]interface{}, error) {
+// (weeks start on Sunday), padding leading/trailing days with 0. The
+// month's holidays and business-day count follow opts (see
+// BusinessOptions).
+func GenerateCalendar(year, month int, opts BusinessOptions) (map[string]interface{}, error) {
 	if month < 1 || month > 12 {
 		return nil, fmt.Errorf("month must be between 1 and 12")
 	}
+	cal, err := NewBusinessCalendar(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	days := daysInMonth(year, month)
@@ -110,6 +159,15 @@ func GenerateCalendar(year, month int) (map[string]interface{}, error) {
 		weeks = append(weeks, week)
 	}
 
+	last := first.AddDate(0, 0, days-1)
+	holidays := cal.holidaysBetween(first, last)
+	businessDays := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if !cal.IsWeekend(d) {
+			businessDays++
+		}
+	}
+
 	return map[string]interface{}{
 		"year":          year,
 		"month":         month,
@@ -118,13 +176,17 @@ func GenerateCalendar(year, month int) (map[string]interface{}, error) {
 		"starts_on":     first.Weekday().String(),
 		"weeks":         weeks,
 		"is_leap_year":  isLeapYear(year),
+		"weekend":       cal.WeekendDays(),
+		"holidays":      holidays,
+		"business_days": businessDays - len(holidays),
 	}, nil
 }
 
-// WorkdaysBetween counts weekdays (Mon-Fri) between two dates inclusive
-// of both endpoints, skipping Saturdays and Sundays. No holiday calendar
-// is applied -- only weekends are excluded.
-func WorkdaysBetween(startStr, endStr string) (map[string]interface{}, error) {
+// WorkdaysBetween counts business days between two dates inclusive of
+// both endpoints, at most MaxWorkdaysYears apart. Weekend days (Saturday and Sunday unless opts.Weekend
+// says otherwise) are skipped, and so are the public holidays of
+// opts.Country and opts.Region when a country is given.
+func WorkdaysBetween(startStr, endStr string, opts BusinessOptions) (map[string]interface{}, error) {
 	start, err := time.Parse("2006-01-02", startStr)
 	if err != nil {
 		return nil, fmt.Errorf("start must be YYYY-MM-DD: %w", err)
@@ -136,22 +198,35 @@ func WorkdaysBetween(startStr, endStr string) (map[string]interface{}, error) {
 	if end.Before(start) {
 		start, end = end, start
 	}
+	if end.After(start.AddDate(MaxWorkdaysYears, 0, 0)) {
+		return nil, fmt.Errorf("start and end must be at most %d years apart", MaxWorkdaysYears)
+	}
+	cal, err := NewBusinessCalendar(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	workdays := 0
 	totalDays := 0
+	weekendDays := 0
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
 		totalDays++
-		if wd := d.Weekday(); wd != time.Saturday && wd != time.Sunday {
-			workdays++
+		if cal.IsWeekend(d) {
+			weekendDays++
 		}
 	}
+	holidays := cal.holidaysBetween(start, end)
 
 	return map[string]interface{}{
 		"start":        start.Format("2006-01-02"),
 		"end":          end.Format("2006-01-02"),
 		"total_days":   totalDays,
-		"workdays":     workdays,
-		"weekend_days": totalDays - workdays,
+		"workdays":     totalDays - weekendDays - len(holidays),
+		"weekend_days": weekendDays,
+		"holiday_days": len(holidays),
+		"holidays":     holidays,
+		"weekend":      cal.WeekendDays(),
+		"country":      cal.Country,
+		"region":       cal.Region,
 	}, nil
 }
 
@@ -341,149 +416,81 @@ func MoonPhase(dateStr string) (map[string]interface{}, error) {
 	}, nil
 }
 
-// ParseCron parses a standard 5-field cron expression (minute hour
-// day-of-month month day-of-week) and returns a human-readable
-// breakdown of each field plus the next scheduled run times, computed
-// by brute-force minute-stepping -- the same approach most widely-used
-// cron-description libraries take. POSIX cron semantics: when both
-// day-of-month and day-of-week are restricted (not "*"), a match on
-// either field is sufficient.
-func ParseCron(expr string) (map[string]interface{}, error) {
-	fields := strings.Fields(expr)
-	if len(fields) != 5 {
-		return nil, fmt.Errorf("cron expression must have exactly 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
-	}
+// CronMaxRuns caps how many runs ParseCron lists.
+const CronMaxRuns = 100
+
+// CronOptions controls the runs ParseCron lists. Zero values mean UTC,
+// five runs, and starting from now; when Until is set, next_runs lists
+// the runs between From and Until, still capped at Count.
+type CronOptions struct {
+	Timezone string
+	Count    int
+	From     time.Time
+	Until    time.Time
+}
 
-	minutes, err := parseCronField(fields[0], 0, 59)
-	if err != nil {
-		return nil, fmt.Errorf("minute field: %w", err)
+// ParseCron parses a cron expression with the shared cron engine (5, 6 or
+// 7 fields, L/W/# modifiers and @daily-style macros; see package cron)
+// and returns a field breakdown, a plain-English description, and the
+// next and previous run times in the requested IANA time zone. Runs
+// follow the zone's daylight saving transitions: a time skipped by a
+// spring-forward change runs at the transition, and a repeated time runs
+// once.
+func ParseCron(expr string, opts CronOptions) (map[string]interface{}, error) {
+	loc := time.UTC
+	if opts.Timezone != "" {
+		l, err := time.LoadLocation(opts.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", opts.Timezone)
+		}
+		loc = l
 	}
-	hours, err := parseCronField(fields[1], 0, 23)
-	if err != nil {
-		return nil, fmt.Errorf("hour field: %w", err)
+	if opts.Count == 0 {
+		opts.Count = 5
 	}
-	doms, err := parseCronField(fields[2], 1, 31)
-	if err != nil {
-		return nil, fmt.Errorf("day-of-month field: %w", err)
+	if opts.Count < 1 || opts.Count > CronMaxRuns {
+		return nil, fmt.Errorf("count must be between 1 and %d", CronMaxRuns)
 	}
-	months, err := parseCronField(fields[3], 1, 12)
-	if err != nil {
-		return nil, fmt.Errorf("month field: %w", err)
+	if opts.From.IsZero() {
+		opts.From = time.Now()
 	}
-	dows, err := parseCronField(fields[4], 0, 6)
-	if err != nil {
-		return nil, fmt.Errorf("day-of-week field: %w", err)
+	if !opts.Until.IsZero() && opts.Until.Before(opts.From) {
+		return nil, fmt.Errorf("end must not be before start")
 	}
 
-	domRestricted := fields[2] != "*"
-	dowRestricted := fields[4] != "*"
-
-	next := nextCronRuns(minutes, hours, doms, months, dows, domRestricted, dowRestricted, 5)
-
-	nextStrs := make([]string, len(next))
-	for i, t := range next {
-		nextStrs[i] = t.Format(time.RFC3339)
+	sched, err := cron.Parse(expr, loc)
+	if err != nil {
+		return nil, err
 	}
 
-	return map[string]interface{}{
-		"expression": expr,
-		"fields": map[string]string{
-			"minute":       fields[0],
-			"hour":         fields[1],
-			"day_of_month": fields[2],
-			"month":        fields[3],
-			"day_of_week":  fields[4],
-		},
-		"next_runs": nextStrs,
-	}, nil
-}
-
-func parseCronField(field string, min, max int) (map[int]bool, error) {
-	values := map[int]bool{}
-
-	for _, part := range strings.Split(field, ",") {
-		step := 1
-		rangePart := part
-		if idx := strings.Index(part, "/"); idx != -1 {
-			rangePart = part[:idx]
-			s, err := strconv.Atoi(part[idx+1:])
-			if err != nil || s <= 0 {
-				return nil, fmt.Errorf("invalid step in %q", part)
-			}
-			step = s
-		}
-
-		var lo, hi int
-		switch {
-		case rangePart == "*":
-			lo, hi = min, max
-		case strings.Contains(rangePart, "-"):
-			bounds := strings.SplitN(rangePart, "-", 2)
-			l, err1 := strconv.Atoi(bounds[0])
-			h, err2 := strconv.Atoi(bounds[1])
-			if err1 != nil || err2 != nil || l < min || h > max || l > h {
-				return nil, fmt.Errorf("invalid range %q", rangePart)
-			}
-			lo, hi = l, h
-		default:
-			v, err := strconv.Atoi(rangePart)
-			if err != nil || v < min || v > max {
-				return nil, fmt.Errorf("invalid value %q", rangePart)
-			}
-			lo, hi = v, v
-		}
-
-		for v := lo; v <= hi; v += step {
-			values[v] = true
+	var next []time.Time
+	if opts.Until.IsZero() {
+		for t := sched.Next(opts.From); !t.IsZero() && len(next) < opts.Count; t = sched.Next(t) {
+			next = append(next, t)
 		}
+	} else {
+		next = sched.Between(opts.From, opts.Until, opts.Count)
+	}
+	var prev []time.Time
+	for t := sched.Prev(opts.From); !t.IsZero() && len(prev) < opts.Count; t = sched.Prev(t) {
+		prev = append(prev, t)
 	}
 
-	return values, nil
+	return map[string]interface{}{
+		"expression":    expr,
+		"normalized":    sched.String(),
+		"description":   sched.Describe(),
+		"timezone":      sched.Location().String(),
+		"fields":        sched.Fields(),
+		"next_runs":     formatCronRuns(next),
+		"previous_runs": formatCronRuns(prev),
+	}, nil
 }
 
-func nextCronRuns(minutes, hours, doms, months, dows map[int]bool, domRestricted, dowRestricted bool, count int) []time.Time {
-	var results []time.Time
-	t := time.Now().UTC().Truncate(time.Minute).Add(time.Minute)
-	limit := t.AddDate(2, 0, 0)
-
-	for t.Before(limit) && len(results) < count {
-		if !months[int(t.Month())] {
-			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
-			continue
-		}
-
-		domMatch := doms[t.Day()]
-		dowMatch := dows[int(t.Weekday())]
-		var dayMatches bool
-		switch {
-		case domRestricted && dowRestricted:
-			dayMatches = domMatch || dowMatch
-		case domRestricted:
-			dayMatches = domMatch
-		case dowRestricted:
-			dayMatches = dowMatch
-		default:
-			dayMatches = true
-		}
-
-		if !dayMatches {
-			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
-			continue
-		}
-
-		if !hours[t.Hour()] {
-			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.UTC).Add(time.Hour)
-			continue
-		}
-
-		if !minutes[t.Minute()] {
-			t = t.Add(time.Minute)
-			continue
-		}
-
-		results = append(results, t)
-		t = t.Add(time.Minute)
+func formatCronRuns(runs []time.Time) []string {
+	out := make([]string, len(runs))
+	for i, t := range runs {
+		out[i] = t.Format(time.RFC3339)
 	}
-
-	return results
+	return out
 }
@@ -75,7 +75,7 @@ func TestParseDateString(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ParseDateString(tt.input)
+			result, err := ParseDateString(tt.input, ParseOptions{})
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Nil(t, result)
@@ -94,7 +94,7 @@ func TestParseDateString(t *testing.T) {
 // February, and the out-of-range month error.
 func TestGenerateCalendar(t *testing.T) {
 	t.Run("november 2023", func(t *testing.T) {
-		result, err := GenerateCalendar(2023, 11)
+		result, err := GenerateCalendar(2023, 11, BusinessOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, 30, result["days_in_month"])
 		assert.Equal(t, "November", result["month_name"])
@@ -107,32 +107,49 @@ func TestGenerateCalendar(t *testing.T) {
 	})
 
 	t.Run("leap february", func(t *testing.T) {
-		result, err := GenerateCalendar(2024, 2)
+		result, err := GenerateCalendar(2024, 2, BusinessOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, 29, result["days_in_month"])
 		assert.Equal(t, true, result["is_leap_year"])
 	})
 
+	t.Run("holidays and business days", func(t *testing.T) {
+		result, err := GenerateCalendar(2024, 12, BusinessOptions{Country: "GB", Region: "SCT"})
+		require.NoError(t, err)
+		// St Andrew's Day falls on Saturday 30 November and is observed
+		// on the following Monday.
+		assert.Equal(t, []holidayDay{
+			{Date: "2024-12-02", Name: "St Andrew's Day (observed)"},
+			{Date: "2024-12-25", Name: "Christmas Day"},
+			{Date: "2024-12-26", Name: "Boxing Day"},
+		}, result["holidays"])
+		// 22 weekdays, less the three holidays.
+		assert.Equal(t, 19, result["business_days"])
+
+		_, err = GenerateCalendar(2024, 12, BusinessOptions{Country: "XX"})
+		assert.ErrorContains(t, err, "no holiday calendar")
+	})
+
 	t.Run("month too low", func(t *testing.T) {
-		result, err := GenerateCalendar(2023, 0)
+		result, err := GenerateCalendar(2023, 0, BusinessOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 	})
 
 	t.Run("month too high", func(t *testing.T) {
-		result, err := GenerateCalendar(2023, 13)
+		result, err := GenerateCalendar(2023, 13, BusinessOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 	})
 }
 
 // Covers WorkdaysBetween for a normal range, a range given in reverse
-// order (must swap), a same-day range, and both invalid-format error
-// paths.
+// order (must swap), a same-day range, both invalid-format error paths
+// and the span limit.
 func TestWorkdaysBetween(t *testing.T) {
 	t.Run("normal week", func(t *testing.T) {
 		// 2023-11-13 is a Monday, 2023-11-19 is a Sunday.
-		result, err := WorkdaysBetween("2023-11-13", "2023-11-19")
+		result, err := WorkdaysBetween("2023-11-13", "2023-11-19", BusinessOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, 7, result["total_days"])
 		assert.Equal(t, 5, result["workdays"])
@@ -140,30 +157,63 @@ func TestWorkdaysBetween(t *testing.T) {
 	})
 
 	t.Run("reversed order swaps", func(t *testing.T) {
-		result, err := WorkdaysBetween("2023-11-19", "2023-11-13")
+		result, err := WorkdaysBetween("2023-11-19", "2023-11-13", BusinessOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, "2023-11-13", result["start"])
 		assert.Equal(t, "2023-11-19", result["end"])
 	})
 
 	t.Run("same day", func(t *testing.T) {
-		result, err := WorkdaysBetween("2023-11-13", "2023-11-13")
+		result, err := WorkdaysBetween("2023-11-13", "2023-11-13", BusinessOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, 1, result["total_days"])
 		assert.Equal(t, 1, result["workdays"])
 	})
 
+	t.Run("country holidays are skipped", func(t *testing.T) {
+		// November 2023 in the US: Veterans Day falls on Saturday the
+		// 11th and is observed Friday the 10th; Thanksgiving is the 23rd.
+		result, err := WorkdaysBetween("2023-11-01", "2023-11-30", BusinessOptions{Country: "us"})
+		require.NoError(t, err)
+		assert.Equal(t, 30, result["total_days"])
+		assert.Equal(t, 8, result["weekend_days"])
+		assert.Equal(t, 2, result["holiday_days"])
+		assert.Equal(t, 20, result["workdays"])
+		assert.Equal(t, []holidayDay{
+			{Date: "2023-11-10", Name: "Veterans Day (observed)"},
+			{Date: "2023-11-23", Name: "Thanksgiving Day"},
+		}, result["holidays"])
+		assert.Equal(t, "US", result["country"])
+	})
+
+	t.Run("custom weekend", func(t *testing.T) {
+		result, err := WorkdaysBetween("2023-11-13", "2023-11-19", BusinessOptions{Weekend: "fri,sat"})
+		require.NoError(t, err)
+		assert.Equal(t, 5, result["workdays"])
+		assert.Equal(t, []string{"Friday", "Saturday"}, result["weekend"])
+
+		_, err = WorkdaysBetween("2023-11-13", "2023-11-19", BusinessOptions{Weekend: "caturday"})
+		assert.ErrorContains(t, err, "invalid weekend day")
+	})
+
 	t.Run("invalid start", func(t *testing.T) {
-		result, err := WorkdaysBetween("bogus", "2023-11-13")
+		result, err := WorkdaysBetween("bogus", "2023-11-13", BusinessOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 	})
 
 	t.Run("invalid end", func(t *testing.T) {
-		result, err := WorkdaysBetween("2023-11-13", "bogus")
+		result, err := WorkdaysBetween("2023-11-13", "bogus", BusinessOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 	})
+
+	t.Run("span limit", func(t *testing.T) {
+		_, err := WorkdaysBetween("2000-01-01", "2040-01-01", BusinessOptions{Country: "gb"})
+		require.NoError(t, err)
+		_, err = WorkdaysBetween("0001-01-01", "9999-12-31", BusinessOptions{Country: "gb"})
+		assert.ErrorContains(t, err, "at most 40 years apart")
+	})
 }
 
 // Covers SunriseSunset for a typical mid-latitude location, the
@@ -283,7 +333,7 @@ func addDaysToDateString(dateStr string, n int) string {
 // OR-match behavior when both are restricted.
 func TestParseCron(t *testing.T) {
 	t.Run("every 5 minutes", func(t *testing.T) {
-		result, err := ParseCron("*/5 * * * *")
+		result, err := ParseCron("*/5 * * * *", CronOptions{})
 		require.NoError(t, err)
 		fields, ok := result["fields"].(map[string]string)
 		require.True(t, ok)
@@ -291,44 +341,47 @@ func TestParseCron(t *testing.T) {
 		nextRuns, ok := result["next_runs"].([]string)
 		require.True(t, ok)
 		assert.Len(t, nextRuns, 5)
+		assert.Equal(t, "Every 5 minutes", result["description"])
+		assert.Equal(t, "UTC", result["timezone"])
 	})
 
 	t.Run("wrong field count", func(t *testing.T) {
-		result, err := ParseCron("* * * *")
+		result, err := ParseCron("* * * *", CronOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 	})
 
 	t.Run("invalid minute", func(t *testing.T) {
-		result, err := ParseCron("60 * * * *")
+		result, err := ParseCron("60 * * * *", CronOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "minute field")
 	})
 
 	t.Run("invalid hour", func(t *testing.T) {
-		result, err := ParseCron("0 24 * * *")
+		result, err := ParseCron("0 24 * * *", CronOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "hour field")
 	})
 
 	t.Run("invalid day of month", func(t *testing.T) {
-		result, err := ParseCron("0 0 32 * *")
+		result, err := ParseCron("0 0 32 * *", CronOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "day-of-month field")
 	})
 
 	t.Run("invalid month", func(t *testing.T) {
-		result, err := ParseCron("0 0 1 13 *")
+		result, err := ParseCron("0 0 1 13 *", CronOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "month field")
 	})
 
 	t.Run("invalid day of week", func(t *testing.T) {
-		result, err := ParseCron("0 0 * * 7")
+		// 7 is Sunday, as in the scheduler; 8 is out of range.
+		result, err := ParseCron("0 0 * * 8", CronOptions{})
 		require.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "day-of-week field")
@@ -336,57 +389,61 @@ func TestParseCron(t *testing.T) {
 
 	t.Run("dom and dow both restricted are OR matched", func(t *testing.T) {
 		// Runs on the 1st of the month OR any Monday.
-		result, err := ParseCron("0 0 1 * 1")
+		from := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+		result, err := ParseCron("0 0 1 * 1", CronOptions{From: from})
 		require.NoError(t, err)
-		nextRuns, ok := result["next_runs"].([]string)
-		require.True(t, ok)
-		assert.Len(t, nextRuns, 5)
+		assert.Equal(t, []string{
+			"2026-07-06T00:00:00Z", "2026-07-13T00:00:00Z", "2026-07-20T00:00:00Z",
+			"2026-07-27T00:00:00Z", "2026-08-01T00:00:00Z",
+		}, result["next_runs"])
 	})
-}
 
-// Covers parseCronField across comma lists, ranges, steps, the wildcard,
-// and every documented error condition.
-func TestParseCronField(t *testing.T) {
-	tests := []struct {
-		name    string
-		field   string
-		min     int
-		max     int
-		wantErr bool
-		want    map[int]bool
-	}{
-		{name: "wildcard", field: "*", min: 0, max: 3, want: map[int]bool{0: true, 1: true, 2: true, 3: true}},
-		{name: "single value", field: "5", min: 0, max: 59, want: map[int]bool{5: true}},
-		{name: "comma list", field: "1,3,5", min: 0, max: 59, want: map[int]bool{1: true, 3: true, 5: true}},
-		{name: "range", field: "1-3", min: 0, max: 59, want: map[int]bool{1: true, 2: true, 3: true}},
-		{name: "step", field: "*/15", min: 0, max: 59, want: map[int]bool{0: true, 15: true, 30: true, 45: true}},
-		{name: "range with step", field: "0-10/5", min: 0, max: 59, want: map[int]bool{0: true, 5: true, 10: true}},
-		{name: "invalid step", field: "*/0", min: 0, max: 59, wantErr: true},
-		{name: "invalid step non-numeric", field: "*/x", min: 0, max: 59, wantErr: true},
-		{name: "invalid range bounds", field: "1-abc", min: 0, max: 59, wantErr: true},
-		{name: "range out of bounds", field: "1-100", min: 0, max: 59, wantErr: true},
-		{name: "range reversed", field: "10-1", min: 0, max: 59, wantErr: true},
-		{name: "invalid single value", field: "abc", min: 0, max: 59, wantErr: true},
-		{name: "single value out of range", field: "100", min: 0, max: 59, wantErr: true},
-	}
+	t.Run("seconds, year and macros", func(t *testing.T) {
+		result, err := ParseCron("30 0 12 * * * 2030", CronOptions{})
+		require.NoError(t, err)
+		fields := result["fields"].(map[string]string)
+		assert.Equal(t, "30", fields["second"])
+		assert.Equal(t, "2030", fields["year"])
+		assert.Equal(t, "2030-01-01T12:00:30Z", result["next_runs"].([]string)[0])
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseCronField(tt.field, tt.min, tt.max)
-			if tt.wantErr {
-				require.Error(t, err)
-				return
-			}
-			require.NoError(t, err)
-			assert.Equal(t, tt.want, got)
-		})
-	}
+		result, err = ParseCron("@weekly", CronOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "0 0 * * 0", result["normalized"])
+	})
+}
+
+// Covers ParseCron's options: time zone, count, a start/end window and
+// previous runs, including a daylight saving transition.
+func TestParseCron_Options(t *testing.T) {
+	from := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+
+	result, err := ParseCron("30 2 * * *", CronOptions{Timezone: "America/New_York", Count: 3, From: from})
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", result["timezone"])
+	// 02:30 does not exist on 2026-03-08, so that run is at 03:00 EDT.
+	assert.Equal(t, []string{
+		"2026-03-07T02:30:00-05:00", "2026-03-08T03:00:00-04:00", "2026-03-09T02:30:00-04:00",
+	}, result["next_runs"])
+	assert.Equal(t, []string{
+		"2026-03-06T02:30:00-05:00", "2026-03-05T02:30:00-05:00", "2026-03-04T02:30:00-05:00",
+	}, result["previous_runs"])
+
+	result, err = ParseCron("0 9 * * 1-5", CronOptions{Count: 100, From: from, Until: from.AddDate(0, 0, 7)})
+	require.NoError(t, err)
+	assert.Len(t, result["next_runs"], 5)
+
+	_, err = ParseCron("* * * * *", CronOptions{Timezone: "Mars/Olympus"})
+	assert.ErrorContains(t, err, "invalid timezone")
+	_, err = ParseCron("* * * * *", CronOptions{Count: CronMaxRuns + 1})
+	assert.ErrorContains(t, err, "count must be")
+	_, err = ParseCron("* * * * *", CronOptions{From: from, Until: from.Add(-time.Hour)})
+	assert.ErrorContains(t, err, "end must not be before start")
 }
 
 // Covers ParseCron's error-message wrapping for the day-of-week field
-// specifically (parseCronField returning an error via the last field).
+// specifically (the last field of a 5-field expression).
 func TestParseCron_FieldErrorMessages(t *testing.T) {
-	_, err := ParseCron("* * * * 99")
+	_, err := ParseCron("* * * * 99", CronOptions{})
 	require.Error(t, err)
 	assert.True(t, strings.Contains(err.Error(), "day-of-week field"))
 }
@@ -0,0 +1,544 @@
+package datetime
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HolidayCountries lists the ISO 3166-1 codes with an embedded holiday
+// calendar. "UK" is accepted as an alias for GB.
+var HolidayCountries = []string{"AU", "CA", "DE", "ES", "FR", "GB", "IT", "US"}
+
+// MaxBusinessDays caps AddBusinessDays, roughly 40 years of business days.
+const MaxBusinessDays = 10000
+
+// MaxWorkdaysYears caps the span WorkdaysBetween counts, matching the
+// reach of MaxBusinessDays.
+const MaxWorkdaysYears = 40
+
+//go:embed holidays/*.json
+var holidayFiles embed.FS
+
+// holidayRule is one holiday definition from holidays/*.json. Rule is
+// one of:
+//
+//	"MM-DD"        a fixed date
+//	"easter+N"     N days after (or "-N" before) Western Easter Sunday
+//	"N dow mon"    the N-th weekday of a month, "-1" for the last one,
+//	               e.g. "4 thu nov"
+//	"dow>=MM-DD"   the first weekday on or after a date ("<=" for the
+//	               last one on or before it), e.g. "mon<=05-24"
+//
+// Since and Until bound the years the rule applies to, Regions limits it
+// to those subdivisions (none means nationwide), and Observed overrides
+// the country's weekend substitution rule.
+type holidayRule struct {
+	Name     string   `json:"name"`
+	Rule     string   `json:"rule"`
+	Since    int      `json:"since"`
+	Until    int      `json:"until"`
+	Regions  []string `json:"regions"`
+	Observed string   `json:"observed"`
+
+	date func(year int) time.Time
+}
+
+// holidayCountry is one holidays/*.json file. Observed is how a holiday
+// falling on a weekend is substituted: "nearest_weekday" (Saturday to
+// Friday, Sunday to Monday), "next_weekday" (the next Monday-Friday that
+// is not already a holiday), or "none".
+type holidayCountry struct {
+	Name     string            `json:"name"`
+	Observed string            `json:"observed"`
+	Regions  map[string]string `json:"regions"`
+	Holidays []*holidayRule    `json:"holidays"`
+}
+
+// loadHolidayCountries parses and compiles every embedded calendar once,
+// on first use.
+var loadHolidayCountries = sync.OnceValue(func() map[string]*holidayCountry {
+	countries := make(map[string]*holidayCountry, len(HolidayCountries))
+	for _, code := range HolidayCountries {
+		data, err := holidayFiles.ReadFile("holidays/" + strings.ToLower(code) + ".json")
+		if err != nil {
+			panic("datetime: missing holiday calendar " + code)
+		}
+		var c holidayCountry
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("datetime: holiday calendar %s: %v", code, err))
+		}
+		for _, h := range c.Holidays {
+			if h.date, err = compileHolidayRule(h.Rule); err != nil {
+				panic(fmt.Sprintf("datetime: holiday calendar %s: %s: %v", code, h.Name, err))
+			}
+		}
+		countries[code] = &c
+	}
+	return countries
+})
+
+func compileHolidayRule(rule string) (func(year int) time.Time, error) {
+	if offset, ok := strings.CutPrefix(rule, "easter"); ok {
+		n := 0
+		if offset != "" {
+			var err error
+			if n, err = strconv.Atoi(offset); err != nil {
+				return nil, fmt.Errorf("invalid Easter offset in %q", rule)
+			}
+		}
+		return func(year int) time.Time { return easterSunday(year).AddDate(0, 0, n) }, nil
+	}
+
+	for _, op := range []string{">=", "<="} {
+		if day, date, ok := strings.Cut(rule, op); ok {
+			wd, ok := parseWeekday(day)
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday in %q", rule)
+			}
+			month, dom, err := parseMonthDay(date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date in %q", rule)
+			}
+			after := op == ">="
+			return func(year int) time.Time {
+				d := time.Date(year, month, dom, 0, 0, 0, 0, time.UTC)
+				if after {
+					return d.AddDate(0, 0, (int(wd)-int(d.Weekday())+7)%7)
+				}
+				return d.AddDate(0, 0, -((int(d.Weekday()) - int(wd) + 7) % 7))
+			}, nil
+		}
+	}
+
+	if fields := strings.Fields(rule); len(fields) == 3 {
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n == 0 || n < -1 || n > 5 {
+			return nil, fmt.Errorf("invalid occurrence in %q (expected 1-5 or -1)", rule)
+		}
+		wd, ok := parseWeekday(fields[1])
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday in %q", rule)
+		}
+		month, ok := parseMonthName(fields[2])
+		if !ok {
+			return nil, fmt.Errorf("invalid month in %q", rule)
+		}
+		return func(year int) time.Time {
+			return nthWeekday(year, month, wd, n)
+		}, nil
+	}
+
+	month, dom, err := parseMonthDay(rule)
+	if err != nil {
+		return nil, fmt.Errorf("unrecognised rule %q", rule)
+	}
+	return func(year int) time.Time { return time.Date(year, month, dom, 0, 0, 0, 0, time.UTC) }, nil
+}
+
+// easterSunday returns Western (Gregorian) Easter Sunday using the
+// anonymous Gregorian algorithm (Meeus/Jones/Butcher).
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the n-th wd of a month, or the last one for n = -1.
+// A fifth occurrence that does not exist rolls into the next month.
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	if n == -1 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if len(s) >= 3 && strings.HasPrefix(name, s) {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+func parseMonthName(s string) (time.Month, bool) {
+	s = strings.ToLower(s)
+	for m := time.January; m <= time.December; m++ {
+		if len(s) >= 3 && strings.HasPrefix(strings.ToLower(m.String()), s) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+func parseMonthDay(s string) (time.Month, int, error) {
+	t, err := time.Parse("01-02", s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Month(), t.Day(), nil
+}
+
+// BusinessOptions selects the calendar for business-day calculations.
+// With no Country only weekends are excluded. Weekend is a comma list of
+// weekday names ("fri,sat"), "none", or empty for Saturday and Sunday.
+type BusinessOptions struct {
+	Country string
+	Region  string
+	Weekend string
+}
+
+// Holiday is one public holiday. Observed is set when a holiday falling
+// on a weekend is observed on another day.
+type Holiday struct {
+	Date     string   `json:"date"`
+	Weekday  string   `json:"weekday"`
+	Observed string   `json:"observed,omitempty"`
+	Name     string   `json:"name"`
+	Regions  []string `json:"regions,omitempty"`
+}
+
+// BusinessCalendar decides which days are business days: neither a
+// weekend day nor a public holiday of its country and region.
+type BusinessCalendar struct {
+	Country string
+	Region  string
+	weekend [7]bool
+	rules   *holidayCountry
+}
+
+// NewBusinessCalendar validates opts and builds a calendar from them.
+func NewBusinessCalendar(opts BusinessOptions) (*BusinessCalendar, error) {
+	c := &BusinessCalendar{
+		Country: strings.ToUpper(strings.TrimSpace(opts.Country)),
+		Region:  strings.ToUpper(strings.TrimSpace(opts.Region)),
+	}
+	if c.Country == "UK" {
+		c.Country = "GB"
+	}
+
+	switch weekend := strings.TrimSpace(opts.Weekend); strings.ToLower(weekend) {
+	case "":
+		c.weekend[time.Saturday], c.weekend[time.Sunday] = true, true
+	case "none":
+	default:
+		for _, name := range strings.Split(weekend, ",") {
+			wd, ok := parseWeekday(name)
+			if !ok {
+				return nil, fmt.Errorf("invalid weekend day %q", strings.TrimSpace(name))
+			}
+			c.weekend[wd] = true
+		}
+		if !slices.Contains(c.weekend[:], false) {
+			return nil, fmt.Errorf("weekend cannot cover the whole week")
+		}
+	}
+
+	if c.Country == "" {
+		if c.Region != "" {
+			return nil, fmt.Errorf("region requires a country")
+		}
+		return c, nil
+	}
+	c.rules = loadHolidayCountries()[c.Country]
+	if c.rules == nil {
+		return nil, fmt.Errorf("no holiday calendar for country %q (supported: %s)", c.Country, strings.Join(HolidayCountries, ", "))
+	}
+	if _, ok := c.rules.Regions[c.Region]; c.Region != "" && !ok {
+		return nil, fmt.Errorf("unknown region %q for %s (supported: %s)", c.Region, c.Country, strings.Join(sortedKeys(c.rules.Regions), ", "))
+	}
+	return c, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WeekendDays lists the calendar's weekend days by name.
+func (c *BusinessCalendar) WeekendDays() []string {
+	days := []string{}
+	for wd, off := range c.weekend {
+		if off {
+			days = append(days, time.Weekday(wd).String())
+		}
+	}
+	return days
+}
+
+// IsWeekend reports whether d falls on one of the calendar's weekend days.
+func (c *BusinessCalendar) IsWeekend(d time.Time) bool {
+	return c.weekend[d.Weekday()]
+}
+
+// Holidays lists the holidays dated in year, in date order. A holiday
+// observed in the previous or next year (New Year's Day moved back to
+// December 31) is still listed under its own year.
+func (c *BusinessCalendar) Holidays(year int) []Holiday {
+	if c.rules == nil {
+		return []Holiday{}
+	}
+
+	type entry struct {
+		rule     *holidayRule
+		date     time.Time
+		observed time.Time
+	}
+	var entries []*entry
+	for _, h := range c.rules.Holidays {
+		if h.Since != 0 && year < h.Since || h.Until != 0 && year > h.Until {
+			continue
+		}
+		if len(h.Regions) > 0 && !slices.Contains(h.Regions, c.Region) {
+			continue
+		}
+		d := h.date(year)
+		entries = append(entries, &entry{rule: h, date: d, observed: d})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].date.Before(entries[j].date) })
+
+	// Substitute days roll forward past each other, so that Christmas Day
+	// and Boxing Day on a weekend become Monday and Tuesday.
+	taken := map[time.Time]bool{}
+	for _, e := range entries {
+		taken[e.date] = true
+	}
+	for _, e := range entries {
+		mode := c.rules.Observed
+		if e.rule.Observed != "" {
+			mode = e.rule.Observed
+		}
+		wd := e.date.Weekday()
+		if wd != time.Saturday && wd != time.Sunday {
+			continue
+		}
+		switch mode {
+		case "nearest_weekday":
+			if wd == time.Saturday {
+				e.observed = e.date.AddDate(0, 0, -1)
+			} else {
+				e.observed = e.date.AddDate(0, 0, 1)
+			}
+		case "next_weekday":
+			d := e.date
+			for d.Weekday() == time.Saturday || d.Weekday() == time.Sunday || taken[d] {
+				d = d.AddDate(0, 0, 1)
+			}
+			e.observed = d
+			taken[d] = true
+		}
+	}
+
+	out := make([]Holiday, 0, len(entries))
+	for _, e := range entries {
+		h := Holiday{
+			Date:    e.date.Format("2006-01-02"),
+			Weekday: e.date.Weekday().String(),
+			Name:    e.rule.Name,
+			Regions: e.rule.Regions,
+		}
+		if !e.observed.Equal(e.date) {
+			h.Observed = e.observed.Format("2006-01-02")
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+// daysOff maps every holiday and observed substitute date in the years
+// first through last to the holiday's name.
+func (c *BusinessCalendar) daysOff(first, last int) map[string]string {
+	off := map[string]string{}
+	for year := first; year <= last; year++ {
+		for _, h := range c.Holidays(year) {
+			off[h.Date] = h.Name
+			if h.Observed != "" {
+				off[h.Observed] = h.Name + " (observed)"
+			}
+		}
+	}
+	return off
+}
+
+// holidayOn returns the name of the holiday on d, if any. The year either
+// side is included for substitutes that cross a year boundary.
+func (c *BusinessCalendar) holidayOn(d time.Time, cache map[int]map[string]string) (string, bool) {
+	off, ok := cache[d.Year()]
+	if !ok {
+		off = c.daysOff(d.Year()-1, d.Year()+1)
+		cache[d.Year()] = off
+	}
+	name, ok := off[d.Format("2006-01-02")]
+	return name, ok
+}
+
+// IsBusinessDay reports whether d is neither a weekend day nor a holiday.
+func (c *BusinessCalendar) IsBusinessDay(d time.Time) bool {
+	if c.IsWeekend(d) {
+		return false
+	}
+	_, holiday := c.holidayOn(d, map[int]map[string]string{})
+	return !holiday
+}
+
+// AddBusinessDays moves n business days from d, backwards when n is
+// negative; d itself is not counted. With n = 0, a non-business d rolls
+// forward to the next business day.
+func (c *BusinessCalendar) AddBusinessDays(d time.Time, n int) time.Time {
+	cache := map[int]map[string]string{}
+	isBusiness := func(d time.Time) bool {
+		if c.IsWeekend(d) {
+			return false
+		}
+		_, holiday := c.holidayOn(d, cache)
+		return !holiday
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	if n == 0 {
+		for !isBusiness(d) {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d
+	}
+	for n > 0 {
+		d = d.AddDate(0, 0, step)
+		if isBusiness(d) {
+			n--
+		}
+	}
+	return d
+}
+
+// Holidays lists a country's public holidays for a year, optionally
+// including those of one region (state, province, nation or
+// department). Regional holidays are left out without a region.
+func Holidays(country, region string, year int) (map[string]interface{}, error) {
+	if country == "" {
+		return nil, fmt.Errorf("country is required")
+	}
+	if year < 1 || year > 9999 {
+		return nil, fmt.Errorf("year must be between 1 and 9999")
+	}
+	cal, err := NewBusinessCalendar(BusinessOptions{Country: country, Region: region})
+	if err != nil {
+		return nil, err
+	}
+	holidays := cal.Holidays(year)
+	result := map[string]interface{}{
+		"country":      cal.Country,
+		"country_name": cal.rules.Name,
+		"year":         year,
+		"holidays":     holidays,
+		"count":        len(holidays),
+	}
+	if cal.Region != "" {
+		result["region"] = cal.Region
+		result["region_name"] = cal.rules.Regions[cal.Region]
+	}
+	return result, nil
+}
+
+// HolidayCalendars lists the embedded holiday calendars with their
+// regions.
+func HolidayCalendars() []map[string]interface{} {
+	countries := loadHolidayCountries()
+	out := make([]map[string]interface{}, 0, len(HolidayCountries))
+	for _, code := range HolidayCountries {
+		c := countries[code]
+		regions := c.Regions
+		if regions == nil {
+			regions = map[string]string{}
+		}
+		out = append(out, map[string]interface{}{
+			"country": code,
+			"name":    c.Name,
+			"regions": regions,
+		})
+	}
+	return out
+}
+
+// AddBusinessDays adds days business days to a YYYY-MM-DD date (see
+// BusinessCalendar.AddBusinessDays), listing the holidays skipped on
+// the way.
+func AddBusinessDays(dateStr string, days int, opts BusinessOptions) (map[string]interface{}, error) {
+	start, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("date must be YYYY-MM-DD: %w", err)
+	}
+	if days < -MaxBusinessDays || days > MaxBusinessDays {
+		return nil, fmt.Errorf("days must be between -%d and %d", MaxBusinessDays, MaxBusinessDays)
+	}
+	cal, err := NewBusinessCalendar(opts)
+	if err != nil {
+		return nil, err
+	}
+	result := cal.AddBusinessDays(start, days)
+
+	from, to := start, result
+	if to.Before(from) {
+		from, to = to, from
+	}
+	skipped := cal.holidaysBetween(from, to)
+
+	return map[string]interface{}{
+		"date":             start.Format("2006-01-02"),
+		"days":             days,
+		"result":           result.Format("2006-01-02"),
+		"result_weekday":   result.Weekday().String(),
+		"calendar_days":    int(result.Sub(start).Hours() / 24),
+		"country":          cal.Country,
+		"region":           cal.Region,
+		"weekend":          cal.WeekendDays(),
+		"skipped_holidays": skipped,
+	}, nil
+}
+
+// holidayDay is a holiday falling on a would-be business day in a range.
+type holidayDay struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// holidaysBetween lists the holidays (and observed substitutes) from
+// start to end inclusive that fall outside the weekend.
+func (c *BusinessCalendar) holidaysBetween(start, end time.Time) []holidayDay {
+	days := []holidayDay{}
+	if c.rules == nil {
+		return days
+	}
+	off := c.daysOff(start.Year()-1, end.Year()+1)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if name, ok := off[d.Format("2006-01-02")]; ok && !c.IsWeekend(d) {
+			days = append(days, holidayDay{Date: d.Format("2006-01-02"), Name: name})
+		}
+	}
+	return days
+}
@@ -0,0 +1,163 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Every embedded calendar parses and compiles; loadHolidayCountries
+// panics otherwise.
+func TestHolidayCalendarsLoad(t *testing.T) {
+	calendars := HolidayCalendars()
+	require.Len(t, calendars, len(HolidayCountries))
+	for _, c := range calendars {
+		assert.NotEmpty(t, c["name"], c["country"])
+	}
+}
+
+// Covers each rule form against known dates.
+func TestCompileHolidayRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		year int
+		want string
+	}{
+		{"07-04", 2026, "2026-07-04"},
+		{"easter", 2024, "2024-03-31"},
+		{"easter", 2025, "2025-04-20"},
+		{"easter", 2038, "2038-04-25"},
+		{"easter-2", 2026, "2026-04-03"},
+		{"easter+39", 2026, "2026-05-14"},
+		{"4 thu nov", 2026, "2026-11-26"},
+		{"1 mon sep", 2026, "2026-09-07"},
+		{"-1 mon may", 2026, "2026-05-25"},
+		{"-1 mon may", 2027, "2027-05-31"},
+		{"mon<=05-24", 2026, "2026-05-18"},
+		{"mon<=05-24", 2027, "2027-05-24"},
+		{"wed<=11-22", 2026, "2026-11-18"},
+		{"sat>=06-20", 2026, "2026-06-20"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.rule, func(t *testing.T) {
+			f, err := compileHolidayRule(tc.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, f(tc.year).Format("2006-01-02"))
+		})
+	}
+
+	for _, bad := range []string{"13-01", "easter+x", "6 mon jan", "1 xyz jan", "1 mon foo", "abc>=01-01", "whenever"} {
+		_, err := compileHolidayRule(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// Covers weekend substitution: the US nearest-weekday rule (including one
+// crossing into the previous year) and the UK roll-forward rule that
+// pushes Boxing Day past a substituted Christmas.
+func TestBusinessCalendarHolidays(t *testing.T) {
+	us, err := NewBusinessCalendar(BusinessOptions{Country: "US"})
+	require.NoError(t, err)
+	h2022 := us.Holidays(2022)
+	require.Len(t, h2022, 11)
+	assert.Equal(t, Holiday{Date: "2022-01-01", Weekday: "Saturday", Observed: "2021-12-31", Name: "New Year's Day"}, h2022[0])
+	assert.False(t, us.IsBusinessDay(date("2021-12-31")))
+	assert.True(t, us.IsBusinessDay(date("2021-12-30")))
+	assert.Len(t, us.Holidays(2020), 10, "before Juneteenth")
+
+	gb, err := NewBusinessCalendar(BusinessOptions{Country: "uk", Region: "eng"})
+	require.NoError(t, err)
+	assert.Equal(t, "GB", gb.Country)
+	var christmas, boxing Holiday
+	for _, h := range gb.Holidays(2021) {
+		switch h.Name {
+		case "Christmas Day":
+			christmas = h
+		case "Boxing Day":
+			boxing = h
+		}
+	}
+	assert.Equal(t, "2021-12-27", christmas.Observed)
+	assert.Equal(t, "2021-12-28", boxing.Observed)
+
+	// Regional holidays only apply with their region.
+	de, err := NewBusinessCalendar(BusinessOptions{Country: "DE"})
+	require.NoError(t, err)
+	assert.True(t, de.IsBusinessDay(date("2026-01-06")))
+	by, err := NewBusinessCalendar(BusinessOptions{Country: "DE", Region: "BY"})
+	require.NoError(t, err)
+	assert.False(t, by.IsBusinessDay(date("2026-01-06")))
+}
+
+func TestNewBusinessCalendarErrors(t *testing.T) {
+	for _, opts := range []BusinessOptions{
+		{Country: "ZZ"},
+		{Country: "GB", Region: "XYZ"},
+		{Region: "BY"},
+		{Weekend: "sun,mon,tue,wed,thu,fri,sat"},
+		{Weekend: "sat,funday"},
+	} {
+		_, err := NewBusinessCalendar(opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+	c, err := NewBusinessCalendar(BusinessOptions{Weekend: "none"})
+	require.NoError(t, err)
+	assert.Empty(t, c.WeekendDays())
+}
+
+// Covers moving forwards and backwards over weekends and holidays, and
+// n = 0 rolling a non-business day forward.
+func TestAddBusinessDays(t *testing.T) {
+	us, err := NewBusinessCalendar(BusinessOptions{Country: "US"})
+	require.NoError(t, err)
+	// Wednesday before Thanksgiving 2026 plus two business days skips
+	// Thanksgiving and the weekend.
+	assert.Equal(t, "2026-11-30", us.AddBusinessDays(date("2026-11-25"), 2).Format("2006-01-02"))
+	assert.Equal(t, "2026-11-25", us.AddBusinessDays(date("2026-11-30"), -2).Format("2006-01-02"))
+	assert.Equal(t, "2026-11-30", us.AddBusinessDays(date("2026-11-28"), 0).Format("2006-01-02"))
+	assert.Equal(t, "2026-11-25", us.AddBusinessDays(date("2026-11-25"), 0).Format("2006-01-02"))
+
+	result, err := AddBusinessDays("2026-12-23", 3, BusinessOptions{Country: "GB", Region: "ENG"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-12-30", result["result"])
+	assert.Equal(t, "Wednesday", result["result_weekday"])
+	assert.Equal(t, []holidayDay{
+		{Date: "2026-12-25", Name: "Christmas Day"},
+		{Date: "2026-12-28", Name: "Boxing Day (observed)"},
+	}, result["skipped_holidays"])
+
+	_, err = AddBusinessDays("2026-12-23", MaxBusinessDays+1, BusinessOptions{})
+	assert.Error(t, err)
+	_, err = AddBusinessDays("23/12/2026", 1, BusinessOptions{})
+	assert.Error(t, err)
+}
+
+func TestHolidays(t *testing.T) {
+	result, err := Holidays("CA", "QC", 2026)
+	require.NoError(t, err)
+	assert.Equal(t, "Canada", result["country_name"])
+	assert.Equal(t, "Quebec", result["region_name"])
+	holidays := result["holidays"].([]Holiday)
+	assert.Equal(t, result["count"], len(holidays))
+	var names []string
+	for _, h := range holidays {
+		names = append(names, h.Name)
+	}
+	assert.Contains(t, names, "Fête nationale du Québec")
+	assert.NotContains(t, names, "Family Day")
+
+	_, err = Holidays("", "", 2026)
+	assert.Error(t, err)
+	_, err = Holidays("US", "", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,1016 @@
+package datetime
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apimgr/api/src/cron"
+	"github.com/google/uuid"
+)
+
+// ICSMaxComponents caps the events and to-dos GenerateICS writes.
+const ICSMaxComponents = 1000
+
+// ICSMaxOccurrences caps the occurrences ParseICS lists per recurring
+// component.
+const ICSMaxOccurrences = 100
+
+// icsMaxScan bounds how many recurrences ParseICS walks through per
+// component while skipping EXDATEs, and how many onsets of one VTIMEZONE
+// observance it walks to resolve an offset.
+const icsMaxScan = 10000
+
+const icsProdID = "-//apimgr//api//EN"
+
+var (
+	icsEventStatuses = []string{"TENTATIVE", "CONFIRMED", "CANCELLED"}
+	icsTodoStatuses  = []string{"NEEDS-ACTION", "COMPLETED", "IN-PROCESS", "CANCELLED"}
+)
+
+// ICSCalendar describes an iCalendar file for GenerateICS. Timezone is
+// the IANA zone component times are written in unless a component sets
+// its own.
+type ICSCalendar struct {
+	Name     string         `json:"name"`
+	ProdID   string         `json:"prod_id"`
+	Timezone string         `json:"timezone"`
+	Events   []ICSComponent `json:"events"`
+	Todos    []ICSComponent `json:"todos"`
+}
+
+// ICSComponent is one VEVENT or VTODO. Start, End, Due and Exdates take
+// RFC3339 times, wall-clock times in the component's time zone (see
+// parseLocalTime), or bare dates for all-day components; without a time
+// zone, wall-clock times are written as floating times. End applies to
+// events and Due to to-dos. RRule is an RFC 5545 recurrence rule (see
+// ParseRRule).
+type ICSComponent struct {
+	UID         string   `json:"uid"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Due         string   `json:"due"`
+	Timezone    string   `json:"timezone"`
+	RRule       string   `json:"rrule"`
+	Exdates     []string `json:"exdates"`
+	Status      string   `json:"status"`
+	Priority    int      `json:"priority"`
+	Categories  []string `json:"categories"`
+}
+
+// icsTime is a component time: a date, a floating wall-clock time, or an
+// instant in loc (UTC or a named zone).
+type icsTime struct {
+	t        time.Time
+	dateOnly bool
+	floating bool
+}
+
+// property renders the time as an iCalendar property line.
+func (v icsTime) property(name string) string {
+	switch {
+	case v.dateOnly:
+		return name + ";VALUE=DATE:" + v.t.Format("20060102")
+	case v.floating:
+		return name + ":" + v.t.Format("20060102T150405")
+	case v.t.Location() == time.UTC:
+		return name + ":" + v.t.Format("20060102T150405Z")
+	default:
+		return name + ";TZID=" + v.t.Location().String() + ":" + v.t.Format("20060102T150405")
+	}
+}
+
+// GenerateICS writes an iCalendar (RFC 5545) file with a VEVENT or VTODO
+// per component and a VTIMEZONE for every time zone they use. Lines end
+// in CRLF and are folded at 75 octets.
+func GenerateICS(cal ICSCalendar) (string, error) {
+	total := len(cal.Events) + len(cal.Todos)
+	if total == 0 {
+		return "", fmt.Errorf("at least one event or todo is required")
+	}
+	if total > ICSMaxComponents {
+		return "", fmt.Errorf("at most %d events and todos are allowed", ICSMaxComponents)
+	}
+	var calLoc *time.Location
+	if cal.Timezone != "" {
+		l, err := time.LoadLocation(cal.Timezone)
+		if err != nil {
+			return "", fmt.Errorf("invalid timezone: %s", cal.Timezone)
+		}
+		calLoc = l
+	}
+
+	zones := map[string]*icsZoneUse{}
+	var body []string
+	stamp := "DTSTAMP:" + time.Now().UTC().Format("20060102T150405Z")
+	for i, c := range cal.Events {
+		lines, err := c.lines("VEVENT", calLoc, stamp, zones)
+		if err != nil {
+			return "", fmt.Errorf("event %d: %w", i+1, err)
+		}
+		body = append(body, lines...)
+	}
+	for i, c := range cal.Todos {
+		lines, err := c.lines("VTODO", calLoc, stamp, zones)
+		if err != nil {
+			return "", fmt.Errorf("todo %d: %w", i+1, err)
+		}
+		body = append(body, lines...)
+	}
+
+	prodID := cal.ProdID
+	if prodID == "" {
+		prodID = icsProdID
+	}
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:" + icsEscape(prodID), "CALSCALE:GREGORIAN"}
+	if cal.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+icsEscape(cal.Name))
+	}
+	if calLoc != nil {
+		lines = append(lines, "X-WR-TIMEZONE:"+calLoc.String())
+	}
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, zones[name].vtimezone()...)
+	}
+	lines = append(lines, body...)
+	lines = append(lines, "END:VCALENDAR")
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(foldICSLine(line))
+		sb.WriteString("\r\n")
+	}
+	return sb.String(), nil
+}
+
+// lines renders the component, recording the time zones it uses.
+func (c ICSComponent) lines(kind string, calLoc *time.Location, stamp string, zones map[string]*icsZoneUse) ([]string, error) {
+	loc := calLoc
+	if c.Timezone != "" {
+		l, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", c.Timezone)
+		}
+		loc = l
+	}
+	event := kind == "VEVENT"
+	if c.Start == "" && event {
+		return nil, fmt.Errorf("start is required")
+	}
+	if !event && c.End != "" {
+		return nil, fmt.Errorf("todos take due, not end")
+	}
+	if event && c.Due != "" {
+		return nil, fmt.Errorf("events take end, not due")
+	}
+	if c.Start == "" && (c.RRule != "" || len(c.Exdates) > 0) {
+		return nil, fmt.Errorf("rrule and exdates need a start")
+	}
+	if c.Priority < 0 || c.Priority > 9 {
+		return nil, fmt.Errorf("priority must be between 0 and 9")
+	}
+
+	uid := c.UID
+	if uid == "" {
+		uid = uuid.NewString()
+	}
+	lines := []string{"BEGIN:" + kind, "UID:" + icsEscape(uid), stamp}
+
+	var start icsTime
+	times := map[string]string{"DTSTART": c.Start, "DTEND": c.End, "DUE": c.Due}
+	for _, name := range []string{"DTSTART", "DTEND", "DUE"} {
+		if times[name] == "" {
+			continue
+		}
+		v, err := parseICSInput(times[name], loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.ToLower(strings.TrimPrefix(name, "DT")), err)
+		}
+		if name == "DTSTART" {
+			start = v
+		} else if c.Start != "" {
+			if v.dateOnly != start.dateOnly {
+				return nil, fmt.Errorf("start and %s must both be dates or both be times", strings.ToLower(strings.TrimPrefix(name, "DT")))
+			}
+			if !v.t.After(start.t) {
+				return nil, fmt.Errorf("%s must be after start", strings.ToLower(strings.TrimPrefix(name, "DT")))
+			}
+		}
+		recordZone(zones, v)
+		lines = append(lines, v.property(name))
+	}
+
+	if c.RRule != "" {
+		r, err := ParseRRule(c.RRule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule: %w", err)
+		}
+		lines = append(lines, "RRULE:"+r.String())
+	}
+	if len(c.Exdates) > 0 {
+		// EXDATE shares DTSTART's form, so every value takes its TZID.
+		var name string
+		values := make([]string, len(c.Exdates))
+		for i, raw := range c.Exdates {
+			v, err := parseICSInput(raw, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exdate %q: %w", raw, err)
+			}
+			if v.dateOnly != start.dateOnly || v.floating != start.floating {
+				return nil, fmt.Errorf("exdate %q must take the same form as start", raw)
+			}
+			if !v.dateOnly && !v.floating {
+				v.t = v.t.In(start.t.Location())
+			}
+			line := v.property("EXDATE")
+			cut := strings.LastIndex(line, ":")
+			name, values[i] = line[:cut], line[cut+1:]
+		}
+		lines = append(lines, name+":"+strings.Join(values, ","))
+	}
+
+	for _, p := range []struct{ name, value string }{
+		{"SUMMARY", c.Summary}, {"DESCRIPTION", c.Description}, {"LOCATION", c.Location},
+	} {
+		if p.value != "" {
+			lines = append(lines, p.name+":"+icsEscape(p.value))
+		}
+	}
+	if len(c.Categories) > 0 {
+		escaped := make([]string, len(c.Categories))
+		for i, cat := range c.Categories {
+			escaped[i] = icsEscape(cat)
+		}
+		lines = append(lines, "CATEGORIES:"+strings.Join(escaped, ","))
+	}
+	if c.Status != "" {
+		status := strings.ToUpper(c.Status)
+		allowed := icsEventStatuses
+		if !event {
+			allowed = icsTodoStatuses
+		}
+		if !slices.Contains(allowed, status) {
+			return nil, fmt.Errorf("invalid status %q (expected one of %s)", c.Status, strings.Join(allowed, ", "))
+		}
+		lines = append(lines, "STATUS:"+status)
+	}
+	if c.Priority > 0 {
+		lines = append(lines, "PRIORITY:"+strconv.Itoa(c.Priority))
+	}
+	return append(lines, "END:"+kind), nil
+}
+
+// parseICSInput parses a component time given to GenerateICS. Without
+// loc, an RFC3339 time is written in UTC and a wall-clock time floats.
+func parseICSInput(s string, loc *time.Location) (icsTime, error) {
+	target := loc
+	if target == nil {
+		target = time.UTC
+	}
+	t, dateOnly, err := parseLocalTime(s, target)
+	if err != nil {
+		return icsTime{}, err
+	}
+	floating := false
+	if loc == nil && !dateOnly {
+		_, rfcErr := time.Parse(time.RFC3339, strings.TrimSpace(s))
+		_, basicErr := time.Parse("20060102T150405Z", strings.TrimSpace(s))
+		floating = rfcErr != nil && basicErr != nil
+	}
+	return icsTime{t: t, dateOnly: dateOnly, floating: floating}, nil
+}
+
+// icsZoneUse is a named time zone used by a calendar and the years its
+// times fall in, which its VTIMEZONE has to cover.
+type icsZoneUse struct {
+	loc                 *time.Location
+	firstYear, lastYear int
+}
+
+func recordZone(zones map[string]*icsZoneUse, v icsTime) {
+	if v.dateOnly || v.floating || v.t.Location() == time.UTC {
+		return
+	}
+	name := v.t.Location().String()
+	y := v.t.Year()
+	if z, ok := zones[name]; ok {
+		z.firstYear, z.lastYear = min(z.firstYear, y), max(z.lastYear, y)
+		return
+	}
+	zones[name] = &icsZoneUse{loc: v.t.Location(), firstYear: y, lastYear: y}
+}
+
+// icsTransition is one change of UTC offset: its instant and the offsets
+// and abbreviation around it.
+type icsTransition struct {
+	at       time.Time
+	from, to int
+	name     string
+	dst      bool
+}
+
+// onset is the transition's local time under the offset before it, as
+// VTIMEZONE's DTSTART wants.
+func (tr icsTransition) onset() time.Time {
+	return tr.at.UTC().Add(time.Duration(tr.from) * time.Second)
+}
+
+// vtimezone renders the zone as a VTIMEZONE covering a year either side
+// of the times that use it. Zones whose changes follow a fixed "n-th
+// weekday of a month" pattern across those years get one observance per
+// change with a yearly RRULE; others list each change.
+func (z *icsZoneUse) vtimezone() []string {
+	from := time.Date(z.firstYear-1, 1, 1, 0, 0, 0, 0, z.loc)
+	to := time.Date(z.lastYear+2, 1, 1, 0, 0, 0, 0, z.loc)
+	var transitions []icsTransition
+	for t := from; ; {
+		_, end := cron.ZoneBounds(t, z.loc)
+		if end.IsZero() || !end.Before(to) {
+			break
+		}
+		name, offset := end.Zone()
+		_, before := end.Add(-time.Second).Zone()
+		if offset != before {
+			transitions = append(transitions, icsTransition{at: end, from: before, to: offset, name: name, dst: end.IsDST()})
+		}
+		t = end
+	}
+
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + z.loc.String()}
+	if len(transitions) == 0 {
+		name, offset := from.Zone()
+		lines = append(lines, observanceLines(false, "19700101T000000", offset, offset, name, "")...)
+		return append(lines, "END:VTIMEZONE")
+	}
+
+	if rules, ok := yearlyTransitionRules(transitions, z.lastYear+1-(z.firstYear-1)+1); ok {
+		for _, r := range rules {
+			tr := r.first
+			lines = append(lines, observanceLines(tr.dst, tr.onset().Format("20060102T150405"), tr.from, tr.to, tr.name, r.rule)...)
+		}
+	} else {
+		for _, tr := range transitions {
+			lines = append(lines, observanceLines(tr.dst, tr.onset().Format("20060102T150405"), tr.from, tr.to, tr.name, "")...)
+		}
+	}
+	return append(lines, "END:VTIMEZONE")
+}
+
+type transitionRule struct {
+	first icsTransition
+	rule  string
+}
+
+// yearlyTransitionRules groups transitions by their "n-th weekday of a
+// month at a local time" pattern, succeeding when every pattern recurs
+// in each of the years covered.
+func yearlyTransitionRules(transitions []icsTransition, years int) ([]transitionRule, bool) {
+	var rules []transitionRule
+	counts := map[string]int{}
+	for _, tr := range transitions {
+		onset := tr.onset()
+		n := (onset.Day()-1)/7 + 1
+		if onset.Day()+7 > daysInMonth(onset.Year(), int(onset.Month())) {
+			n = -1
+		}
+		rule := fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(onset.Month()), n, rruleWeekdays[onset.Weekday()])
+		key := fmt.Sprintf("%s|%s|%d|%d|%t", rule, onset.Format("150405"), tr.from, tr.to, tr.dst)
+		if counts[key] == 0 {
+			rules = append(rules, transitionRule{first: tr, rule: rule})
+		}
+		counts[key]++
+	}
+	for _, c := range counts {
+		if c != years {
+			return nil, false
+		}
+	}
+	return rules, true
+}
+
+func observanceLines(dst bool, dtstart string, from, to int, name, rule string) []string {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	lines := []string{
+		"BEGIN:" + kind,
+		"DTSTART:" + dtstart,
+		"TZOFFSETFROM:" + icsOffset(from),
+		"TZOFFSETTO:" + icsOffset(to),
+	}
+	if name != "" {
+		lines = append(lines, "TZNAME:"+name)
+	}
+	if rule != "" {
+		lines = append(lines, "RRULE:"+rule)
+	}
+	return append(lines, "END:"+kind)
+}
+
+// icsOffset renders a UTC offset as iCalendar's "+hhmm[ss]".
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	out := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if s := seconds % 60; s != 0 {
+		out += fmt.Sprintf("%02d", s)
+	}
+	return out
+}
+
+func parseICSOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 || (s[0] != '+' && s[0] != '-') {
+		return 0, fmt.Errorf("invalid UTC offset %q", s)
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid UTC offset %q", s)
+	}
+	if len(s) == 5 {
+		n *= 100
+	}
+	seconds := n/10000*3600 + n/100%100*60 + n%100
+	if s[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+var (
+	icsEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	icsUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func icsEscape(s string) string { return icsEscaper.Replace(s) }
+
+func icsUnescape(s string) string { return icsUnescaper.Replace(s) }
+
+// foldICSLine splits a line longer than 75 octets into continuation
+// lines starting with a space, without breaking a UTF-8 sequence.
+func foldICSLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	var sb strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	sb.WriteString(line)
+	return sb.String()
+}
+
+// icsProperty is one content line: NAME;PARAM=value:VALUE.
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// icsNode is a BEGIN/END component with its properties and children.
+type icsNode struct {
+	name       string
+	properties []icsProperty
+	children   []*icsNode
+}
+
+func (n *icsNode) get(name string) (icsProperty, bool) {
+	for _, p := range n.properties {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+func (n *icsNode) text(name string) string {
+	p, _ := n.get(name)
+	return icsUnescape(p.value)
+}
+
+// parseICSProperty splits a content line into name, parameters (quoted
+// values may hold ":" and ";") and value.
+func parseICSProperty(line string) (icsProperty, error) {
+	p := icsProperty{params: map[string]string{}}
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, fmt.Errorf("invalid content line %q", line)
+	}
+	p.name = strings.ToUpper(line[:i])
+	rest := line[i:]
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return p, fmt.Errorf("invalid parameter in %q", line)
+		}
+		key := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return p, fmt.Errorf("unterminated quoted parameter in %q", line)
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return p, fmt.Errorf("invalid content line %q", line)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		p.params[key] = value
+	}
+	if !strings.HasPrefix(rest, ":") {
+		return p, fmt.Errorf("invalid content line %q", line)
+	}
+	p.value = rest[1:]
+	return p, nil
+}
+
+// parseICSNodes unfolds the data and builds its component tree.
+func parseICSNodes(data string) ([]*icsNode, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var roots, stack []*icsNode
+	for _, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		p, err := parseICSProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		switch p.name {
+		case "BEGIN":
+			node := &icsNode{name: strings.ToUpper(p.value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				roots = append(roots, node)
+			}
+			stack = append(stack, node)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("unexpected END:%s", p.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property %s outside a component", p.name)
+			}
+			node := stack[len(stack)-1]
+			node.properties = append(node.properties, p)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].name)
+	}
+	return roots, nil
+}
+
+// vtzObservance is one STANDARD or DAYLIGHT block of a VTIMEZONE. start
+// and rdates are local wall times held as UTC.
+type vtzObservance struct {
+	start    time.Time
+	from, to int
+	rule     *RRule
+	rdates   []time.Time
+}
+
+// vtimezone is a VTIMEZONE whose TZID is not an IANA name, resolved
+// from its own observances.
+type vtimezone struct {
+	tzid        string
+	observances []vtzObservance
+}
+
+func parseVTimezone(n *icsNode) (*vtimezone, error) {
+	tz := &vtimezone{tzid: n.text("TZID")}
+	if tz.tzid == "" {
+		return nil, fmt.Errorf("VTIMEZONE without TZID")
+	}
+	for _, child := range n.children {
+		if child.name != "STANDARD" && child.name != "DAYLIGHT" {
+			continue
+		}
+		var o vtzObservance
+		var err error
+		if o.start, err = parseICSWall(child.text("DTSTART")); err != nil {
+			return nil, fmt.Errorf("VTIMEZONE %s: %w", tz.tzid, err)
+		}
+		if o.from, err = parseICSOffset(child.text("TZOFFSETFROM")); err != nil {
+			return nil, fmt.Errorf("VTIMEZONE %s: %w", tz.tzid, err)
+		}
+		if o.to, err = parseICSOffset(child.text("TZOFFSETTO")); err != nil {
+			return nil, fmt.Errorf("VTIMEZONE %s: %w", tz.tzid, err)
+		}
+		if p, ok := child.get("RRULE"); ok {
+			if o.rule, err = ParseRRule(p.value); err != nil {
+				return nil, fmt.Errorf("VTIMEZONE %s: %w", tz.tzid, err)
+			}
+			if o.rule.Freq != "YEARLY" {
+				return nil, fmt.Errorf("VTIMEZONE %s: observance RRULE must be FREQ=YEARLY", tz.tzid)
+			}
+		}
+		for _, p := range child.properties {
+			if p.name != "RDATE" {
+				continue
+			}
+			for _, v := range strings.Split(p.value, ",") {
+				d, err := parseICSWall(v)
+				if err != nil {
+					return nil, fmt.Errorf("VTIMEZONE %s: %w", tz.tzid, err)
+				}
+				o.rdates = append(o.rdates, d)
+			}
+		}
+		tz.observances = append(tz.observances, o)
+	}
+	if len(tz.observances) == 0 {
+		return nil, fmt.Errorf("VTIMEZONE %s has no STANDARD or DAYLIGHT block", tz.tzid)
+	}
+	return tz, nil
+}
+
+// offsetAt returns the UTC offset in force at wall time w: that of the
+// latest onset at or before w, or the earliest observance's
+// TZOFFSETFROM when w precedes them all.
+func (z *vtimezone) offsetAt(w time.Time) int {
+	earliest := z.observances[0]
+	for _, o := range z.observances[1:] {
+		if o.start.Before(earliest.start) {
+			earliest = o
+		}
+	}
+	var latest time.Time
+	offset := earliest.from
+	for _, o := range z.observances {
+		if o.start.After(w) {
+			continue
+		}
+		onset := o.start
+		if o.rule != nil {
+			start, skip := o.ruleStart(w)
+			scanned := 0
+			for t := range o.rule.Occurrences(start) {
+				if skip {
+					skip = false
+					continue
+				}
+				if scanned++; t.After(w) || scanned > icsMaxScan {
+					break
+				}
+				onset = t
+			}
+		}
+		for _, d := range o.rdates {
+			if !d.After(w) && d.After(onset) {
+				onset = d
+			}
+		}
+		if onset.After(latest) {
+			latest, offset = onset, o.to
+		}
+	}
+	return offset
+}
+
+// ruleStart returns where to start walking the observance's yearly rule
+// to find the onsets around wall time w: DTSTART moved forward by whole
+// intervals to two intervals before w's year, so the walk covers a few
+// onsets rather than every year since DTSTART. skip reports that the
+// moved start, which need not match the rule, is not itself an onset.
+// Rules bounded by COUNT or UNTIL and February 29 starts are walked from
+// DTSTART.
+func (o vtzObservance) ruleStart(w time.Time) (start time.Time, skip bool) {
+	if o.rule.Count > 0 || !o.rule.Until.IsZero() || (o.start.Month() == time.February && o.start.Day() == 29) {
+		return o.start, false
+	}
+	k := o.rule.Interval
+	years := (w.Year()-o.start.Year())/k*k - 2*k
+	if years <= 0 {
+		return o.start, false
+	}
+	return o.start.AddDate(years, 0, 0), true
+}
+
+// instant returns the time at wall time w (held as UTC) in the zone.
+func (z *vtimezone) instant(w time.Time) time.Time {
+	offset := z.offsetAt(w)
+	return w.Add(-time.Duration(offset) * time.Second).In(time.FixedZone(z.tzid, offset))
+}
+
+// parseICSWall parses a DATE-TIME value without time zone handling,
+// returning its wall clock as UTC.
+func parseICSWall(v string) (time.Time, error) {
+	t, err := time.Parse("20060102T150405", strings.TrimSuffix(v, "Z"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date-time %q", v)
+	}
+	return t, nil
+}
+
+// icsResolved is a parsed component time. For floating times and dates
+// t holds the wall clock as UTC; zone is nil unless the TZID named a
+// calendar-defined VTIMEZONE.
+type icsResolved struct {
+	t        time.Time
+	dateOnly bool
+	floating bool
+	tzid     string
+	zone     *vtimezone
+}
+
+func (v icsResolved) format() string {
+	switch {
+	case v.dateOnly:
+		return v.t.Format("2006-01-02")
+	case v.floating:
+		return v.t.Format("2006-01-02T15:04:05")
+	}
+	return v.t.Format(time.RFC3339)
+}
+
+// resolveICSValues parses a DATE or DATE-TIME property value (a comma
+// list for EXDATE), honouring VALUE=DATE and TZID.
+func resolveICSValues(p icsProperty, zones map[string]*vtimezone) ([]icsResolved, error) {
+	var out []icsResolved
+	tzid := strings.TrimPrefix(p.params["TZID"], "/")
+	for _, raw := range strings.Split(p.value, ",") {
+		raw = strings.TrimSpace(raw)
+		if strings.EqualFold(p.params["VALUE"], "DATE") || len(raw) == 8 {
+			d, err := time.Parse("20060102", raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s date %q", p.name, raw)
+			}
+			out = append(out, icsResolved{t: d, dateOnly: true})
+			continue
+		}
+		w, err := parseICSWall(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.name, err)
+		}
+		switch {
+		case strings.HasSuffix(raw, "Z"):
+			out = append(out, icsResolved{t: w, tzid: "UTC"})
+		case tzid == "":
+			out = append(out, icsResolved{t: w, floating: true})
+		default:
+			if loc, err := time.LoadLocation(tzid); err == nil {
+				out = append(out, icsResolved{t: wallTime(w, loc), tzid: loc.String()})
+			} else if z, ok := zones[tzid]; ok {
+				out = append(out, icsResolved{t: z.instant(w), tzid: tzid, zone: z})
+			} else {
+				return nil, fmt.Errorf("%s: unknown TZID %q", p.name, tzid)
+			}
+		}
+	}
+	return out, nil
+}
+
+// ICSItem is a VEVENT or VTODO read by ParseICS. Times are RFC3339,
+// YYYY-MM-DD for all-day components, or YYYY-MM-DDTHH:MM:SS for floating
+// times. Occurrences lists the first occurrences of a recurring item,
+// less its exdates.
+type ICSItem struct {
+	UID              string   `json:"uid"`
+	Summary          string   `json:"summary"`
+	Description      string   `json:"description,omitempty"`
+	Location         string   `json:"location,omitempty"`
+	Status           string   `json:"status,omitempty"`
+	Priority         int      `json:"priority,omitempty"`
+	Categories       []string `json:"categories,omitempty"`
+	Start            string   `json:"start,omitempty"`
+	End              string   `json:"end,omitempty"`
+	Due              string   `json:"due,omitempty"`
+	AllDay           bool     `json:"all_day"`
+	Timezone         string   `json:"timezone,omitempty"`
+	RRule            string   `json:"rrule,omitempty"`
+	RRuleDescription string   `json:"rrule_description,omitempty"`
+	Exdates          []string `json:"exdates,omitempty"`
+	Occurrences      []string `json:"occurrences,omitempty"`
+}
+
+// ParseICS reads an iCalendar file's events and to-dos, resolving times
+// through IANA zone names or the file's own VTIMEZONE definitions, and
+// lists up to count occurrences (default 5) of each recurring item.
+func ParseICS(data string, count int) (map[string]interface{}, error) {
+	if count == 0 {
+		count = 5
+	}
+	if count < 1 || count > ICSMaxOccurrences {
+		return nil, fmt.Errorf("count must be between 1 and %d", ICSMaxOccurrences)
+	}
+	roots, err := parseICSNodes(data)
+	if err != nil {
+		return nil, err
+	}
+	var cal *icsNode
+	for _, n := range roots {
+		if n.name == "VCALENDAR" {
+			cal = n
+			break
+		}
+	}
+	if cal == nil {
+		return nil, fmt.Errorf("no VCALENDAR found")
+	}
+
+	zones := map[string]*vtimezone{}
+	timezones := []string{}
+	for _, n := range cal.children {
+		if n.name != "VTIMEZONE" {
+			continue
+		}
+		z, err := parseVTimezone(n)
+		if err != nil {
+			return nil, err
+		}
+		zones[z.tzid] = z
+		timezones = append(timezones, z.tzid)
+	}
+
+	events, todos := []ICSItem{}, []ICSItem{}
+	for _, n := range cal.children {
+		if n.name != "VEVENT" && n.name != "VTODO" {
+			continue
+		}
+		item, err := parseICSItem(n, zones, count)
+		if err != nil {
+			kind := "event"
+			if n.name == "VTODO" {
+				kind = "todo"
+			}
+			return nil, fmt.Errorf("%s %q: %w", kind, n.text("UID"), err)
+		}
+		if n.name == "VEVENT" {
+			events = append(events, item)
+		} else {
+			todos = append(todos, item)
+		}
+	}
+
+	return map[string]interface{}{
+		"prod_id":   cal.text("PRODID"),
+		"version":   cal.text("VERSION"),
+		"name":      cal.text("X-WR-CALNAME"),
+		"method":    cal.text("METHOD"),
+		"timezones": timezones,
+		"events":    events,
+		"todos":     todos,
+		"count":     len(events) + len(todos),
+	}, nil
+}
+
+func parseICSItem(n *icsNode, zones map[string]*vtimezone, count int) (ICSItem, error) {
+	item := ICSItem{
+		UID:         n.text("UID"),
+		Summary:     n.text("SUMMARY"),
+		Description: n.text("DESCRIPTION"),
+		Location:    n.text("LOCATION"),
+		Status:      strings.ToUpper(n.text("STATUS")),
+	}
+	if v := n.text("PRIORITY"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return item, fmt.Errorf("invalid PRIORITY %q", v)
+		}
+		item.Priority = p
+	}
+	for _, p := range n.properties {
+		if p.name == "CATEGORIES" {
+			for _, c := range splitICSList(p.value) {
+				item.Categories = append(item.Categories, icsUnescape(c))
+			}
+		}
+	}
+
+	var start *icsResolved
+	for _, name := range []string{"DTSTART", "DTEND", "DUE"} {
+		p, ok := n.get(name)
+		if !ok {
+			continue
+		}
+		values, err := resolveICSValues(p, zones)
+		if err != nil {
+			return item, err
+		}
+		v := values[0]
+		switch name {
+		case "DTSTART":
+			start = &v
+			item.Start, item.AllDay = v.format(), v.dateOnly
+			item.Timezone = v.tzid
+		case "DTEND":
+			item.End = v.format()
+		case "DUE":
+			item.Due = v.format()
+			if start == nil {
+				item.AllDay = v.dateOnly
+			}
+		}
+	}
+
+	var exdates []icsResolved
+	for _, p := range n.properties {
+		if p.name != "EXDATE" {
+			continue
+		}
+		values, err := resolveICSValues(p, zones)
+		if err != nil {
+			return item, err
+		}
+		for _, v := range values {
+			exdates = append(exdates, v)
+			item.Exdates = append(item.Exdates, v.format())
+		}
+	}
+
+	p, ok := n.get("RRULE")
+	if !ok {
+		return item, nil
+	}
+	r, err := ParseRRule(p.value)
+	if err != nil {
+		return item, err
+	}
+	if start == nil {
+		return item, fmt.Errorf("RRULE without DTSTART")
+	}
+	item.RRule, item.RRuleDescription = r.String(), r.Describe()
+
+	dtstart := start.t
+	if start.zone != nil {
+		// Expand in wall-clock time and resolve each occurrence through
+		// the calendar's VTIMEZONE.
+		dtstart = time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC)
+	}
+	scanned := 0
+	for t := range r.Occurrences(dtstart) {
+		if scanned++; scanned > icsMaxScan {
+			break
+		}
+		occ := *start
+		occ.t = t
+		if start.zone != nil {
+			occ.t = start.zone.instant(t)
+		}
+		if slices.ContainsFunc(exdates, func(ex icsResolved) bool {
+			if ex.dateOnly {
+				return ex.t.Format("2006-01-02") == occ.t.Format("2006-01-02")
+			}
+			return ex.t.Equal(occ.t)
+		}) {
+			continue
+		}
+		item.Occurrences = append(item.Occurrences, occ.format())
+		if len(item.Occurrences) == count {
+			break
+		}
+	}
+	return item, nil
+}
+
+// splitICSList splits a comma list, keeping escaped commas.
+func splitICSList(v string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case ',':
+			out = append(out, v[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, v[start:])
+}
@@ -0,0 +1,203 @@
+package datetime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateICS(t *testing.T) {
+	ics, err := GenerateICS(ICSCalendar{
+		Name:     "Team",
+		Timezone: "America/New_York",
+		Events: []ICSComponent{{
+			UID:        "standup@example.com",
+			Summary:    "Standup; daily, short",
+			Start:      "2026-03-02T09:00",
+			End:        "2026-03-02T09:15",
+			RRule:      "freq=weekly;byday=MO,WE,FR;count=6",
+			Exdates:    []string{"2026-03-04T09:00"},
+			Status:     "confirmed",
+			Categories: []string{"work", "meetings"},
+		}},
+		Todos: []ICSComponent{{
+			UID:      "taxes@example.com",
+			Summary:  "File taxes",
+			Due:      "2026-04-15",
+			Priority: 1,
+		}},
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+	assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+	assert.Equal(t, "END:VCALENDAR", lines[len(lines)-1])
+	for _, want := range []string{
+		"X-WR-CALNAME:Team",
+		"TZID:America/New_York",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+		"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+		"TZOFFSETFROM:-0500",
+		"TZOFFSETTO:-0400",
+		"DTSTART;TZID=America/New_York:20260302T090000",
+		"DTEND;TZID=America/New_York:20260302T091500",
+		"RRULE:FREQ=WEEKLY;COUNT=6;BYDAY=MO,WE,FR",
+		"EXDATE;TZID=America/New_York:20260304T090000",
+		`SUMMARY:Standup\; daily\, short`,
+		"CATEGORIES:work,meetings",
+		"STATUS:CONFIRMED",
+		"DUE;VALUE=DATE:20260415",
+		"PRIORITY:1",
+	} {
+		assert.Contains(t, lines, want)
+	}
+
+	parsed, err := ParseICS(ics, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "Team", parsed["name"])
+	assert.Equal(t, []string{"America/New_York"}, parsed["timezones"])
+	events := parsed["events"].([]ICSItem)
+	require.Len(t, events, 1)
+	e := events[0]
+	assert.Equal(t, "Standup; daily, short", e.Summary)
+	assert.Equal(t, "2026-03-02T09:00:00-05:00", e.Start)
+	assert.Equal(t, "America/New_York", e.Timezone)
+	assert.Equal(t, []string{"work", "meetings"}, e.Categories)
+	assert.Equal(t, "Every week on Monday, Wednesday and Friday, 6 times", e.RRuleDescription)
+	assert.Equal(t, []string{
+		"2026-03-02T09:00:00-05:00",
+		"2026-03-06T09:00:00-05:00",
+		"2026-03-09T09:00:00-04:00",
+		"2026-03-11T09:00:00-04:00",
+		"2026-03-13T09:00:00-04:00",
+	}, e.Occurrences)
+	todos := parsed["todos"].([]ICSItem)
+	require.Len(t, todos, 1)
+	assert.Equal(t, "2026-04-15", todos[0].Due)
+	assert.True(t, todos[0].AllDay)
+	assert.Equal(t, 1, todos[0].Priority)
+
+	for _, bad := range []ICSCalendar{
+		{},
+		{Timezone: "Mars/Olympus", Events: []ICSComponent{{Start: "2026-03-02"}}},
+		{Events: []ICSComponent{{Summary: "no start"}}},
+		{Events: []ICSComponent{{Start: "2026-03-02", End: "2026-03-01"}}},
+		{Events: []ICSComponent{{Start: "2026-03-02", End: "2026-03-02T10:00:00Z"}}},
+		{Events: []ICSComponent{{Start: "2026-03-02", RRule: "FREQ=SOMETIMES"}}},
+		{Events: []ICSComponent{{Start: "2026-03-02", Status: "NEEDS-ACTION"}}},
+		{Events: []ICSComponent{{Start: "2026-03-02", Due: "2026-03-03"}}},
+		{Todos: []ICSComponent{{Summary: "x", Priority: 10}}},
+	} {
+		_, err := GenerateICS(bad)
+		assert.Error(t, err, "%+v", bad)
+	}
+}
+
+// Zones past their listed transitions (after 2040) still get their
+// yearly rules rather than stalling the transition walk.
+func TestGenerateICSAfter2040(t *testing.T) {
+	ics, err := GenerateICS(ICSCalendar{
+		Timezone: "America/New_York",
+		Events:   []ICSComponent{{UID: "late@example.com", Summary: "Late", Start: "2045-06-01T09:00"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, ics, "RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU")
+	assert.Contains(t, ics, "RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU")
+}
+
+func TestICSFloatingAndUTC(t *testing.T) {
+	ics, err := GenerateICS(ICSCalendar{Events: []ICSComponent{
+		{UID: "a", Start: "2026-07-01T12:00:00Z"},
+		{UID: "b", Start: "2026-07-01T12:00"},
+		{UID: "c", Start: "2026-07-01", End: "2026-07-02"},
+	}})
+	require.NoError(t, err)
+	assert.Contains(t, ics, "DTSTART:20260701T120000Z\r\n")
+	assert.Contains(t, ics, "DTSTART:20260701T120000\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20260701\r\n")
+	assert.NotContains(t, ics, "VTIMEZONE")
+
+	parsed, err := ParseICS(ics, 0)
+	require.NoError(t, err)
+	events := parsed["events"].([]ICSItem)
+	require.Len(t, events, 3)
+	assert.Equal(t, "2026-07-01T12:00:00Z", events[0].Start)
+	assert.Equal(t, "2026-07-01T12:00:00", events[1].Start)
+	assert.Equal(t, "2026-07-01", events[2].Start)
+	assert.True(t, events[2].AllDay)
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 80)
+	folded := foldICSLine(line)
+	parts := strings.Split(folded, "\r\n")
+	require.Greater(t, len(parts), 1)
+	for _, p := range parts {
+		assert.LessOrEqual(t, len(p), 75)
+		assert.True(t, strings.ToValidUTF8(p, "?") == p, "fold split a rune: %q", p)
+	}
+
+	parsed, err := ParseICS("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\n"+folded+"\r\nSUMMARY:a\\nb\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", 0)
+	require.NoError(t, err)
+	e := parsed["events"].([]ICSItem)[0]
+	assert.Equal(t, strings.Repeat("é", 80), e.Description)
+	assert.Equal(t, "a\nb", e.Summary)
+}
+
+func TestParseICSCustomTimezone(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:Eastern Standard Time",
+		"BEGIN:STANDARD",
+		"DTSTART:16011104T020000",
+		"RRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11",
+		"TZOFFSETFROM:-0400",
+		"TZOFFSETTO:-0500",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:16010311T020000",
+		"RRULE:FREQ=YEARLY;BYDAY=2SU;BYMONTH=3",
+		"TZOFFSETFROM:-0500",
+		"TZOFFSETTO:-0400",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:review",
+		`DTSTART;TZID="Eastern Standard Time":20261028T100000`,
+		"RRULE:FREQ=WEEKLY;COUNT=3",
+		`EXDATE;TZID="Eastern Standard Time":20261104T100000`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	parsed, err := ParseICS(ics, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Eastern Standard Time"}, parsed["timezones"])
+	e := parsed["events"].([]ICSItem)[0]
+	assert.Equal(t, "2026-10-28T10:00:00-04:00", e.Start)
+	assert.Equal(t, []string{"2026-11-04T10:00:00-05:00"}, e.Exdates)
+	assert.Equal(t, []string{"2026-10-28T10:00:00-04:00", "2026-11-11T10:00:00-05:00"}, e.Occurrences)
+
+	for _, bad := range []string{
+		"",
+		"BEGIN:VEVENT\r\nEND:VEVENT",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;TZID=Nowhere:20260101T000000\r\nEND:VEVENT\r\nEND:VCALENDAR",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nRRULE:FREQ=DAILY\r\nEND:VEVENT\r\nEND:VCALENDAR",
+	} {
+		_, err := ParseICS(bad, 0)
+		assert.Error(t, err, bad)
+	}
+	_, err = ParseICS(ics, ICSMaxOccurrences+1)
+	assert.Error(t, err)
+
+	// Observances only recur yearly; a finer rule would be walked from
+	// DTSTART for every occurrence resolved.
+	_, err = ParseICS(strings.Replace(ics, "RRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11", "RRULE:FREQ=MINUTELY", 1), 0)
+	assert.Error(t, err)
+}
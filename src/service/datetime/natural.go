@@ -0,0 +1,950 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseOptions sets what ParseDateString and ParseNatural resolve
+// relative expressions against: Reference defaults to now and Timezone
+// (IANA) to UTC. Times given without a zone are read in Timezone.
+type ParseOptions struct {
+	Reference time.Time
+	Timezone  string
+}
+
+// resolve returns the reference time in the requested zone.
+func (o ParseOptions) resolve() (time.Time, *time.Location, error) {
+	loc := time.UTC
+	if o.Timezone != "" {
+		l, err := time.LoadLocation(o.Timezone)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("invalid timezone: %s", o.Timezone)
+		}
+		loc = l
+	}
+	ref := o.Reference
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+	return ref.In(loc), loc, nil
+}
+
+// NaturalResult is a resolved natural-language or ISO 8601 expression.
+// Kind is "instant", "duration" (Time is the reference plus Duration) or
+// "interval" (from Time to End). Interpretation says in words how the
+// input was read.
+type NaturalResult struct {
+	Kind           string
+	Time           time.Time
+	End            time.Time
+	Duration       *ISODuration
+	Interpretation string
+}
+
+// ISODuration is an ISO 8601 duration such as P1Y2M10DT2H30M. Added to a
+// time, the calendar parts (years, months, weeks, days) keep the wall
+// clock and clamp to the end of shorter months; hours, minutes and
+// seconds are elapsed time.
+type ISODuration struct {
+	Negative bool
+	Years    int
+	Months   int
+	Weeks    int
+	Days     int
+	Hours    float64
+	Minutes  float64
+	Seconds  float64
+}
+
+var isoDurationRe = regexp.MustCompile(`^([+-])?P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// ParseISODuration parses an ISO 8601 duration (PnYnMnWnDTnHnMnS, with
+// an optional sign). Hours, minutes and seconds may be fractional.
+func ParseISODuration(s string) (ISODuration, error) {
+	var d ISODuration
+	s = strings.ToUpper(strings.TrimSpace(s))
+	m := isoDurationRe.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+		return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	d.Negative = m[1] == "-"
+	ints := []*int{&d.Years, &d.Months, &d.Weeks, &d.Days}
+	for i, p := range ints {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return d, fmt.Errorf("invalid ISO 8601 duration %q: fractional years, months, weeks and days are not supported", s)
+		}
+		*p = n
+	}
+	floats := []*float64{&d.Hours, &d.Minutes, &d.Seconds}
+	for i, p := range floats {
+		if m[i+6] == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.Replace(m[i+6], ",", ".", 1), 64)
+		if err != nil {
+			return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+		*p = f
+	}
+	return d, nil
+}
+
+// AddTo adds the duration to t (subtracts it when negative). It fails
+// when the result falls outside years 1-9999 or the hours, minutes and
+// seconds together overflow a time.Duration.
+func (d ISODuration) AddTo(t time.Time) (time.Time, error) {
+	sign := 1
+	if d.Negative {
+		sign = -1
+	}
+	// Any part past these moves t out of range anyway; rejecting it first
+	// keeps the calendar arithmetic from overflowing.
+	if d.Years > 10000 || d.Months > 12*10000 || d.Weeks > 53*10000 || d.Days > 366*10000 {
+		return time.Time{}, fmt.Errorf("duration %s moves the date outside years 1-9999", d)
+	}
+	elapsed := (d.Hours*3600 + d.Minutes*60 + d.Seconds) * float64(time.Second)
+	if elapsed >= math.MaxInt64 {
+		return time.Time{}, fmt.Errorf("duration %s is too long: hours, minutes and seconds must total under 292 years", d)
+	}
+	t = addCalendar(t, sign*d.Years, sign*d.Months, sign*(d.Weeks*7+d.Days))
+	t = t.Add(time.Duration(float64(sign) * elapsed))
+	if t.Year() < 1 || t.Year() > 9999 {
+		return time.Time{}, fmt.Errorf("duration %s moves the date outside years 1-9999", d)
+	}
+	return t, nil
+}
+
+// String renders the duration in canonical ISO 8601 form.
+func (d ISODuration) String() string {
+	var sb strings.Builder
+	if d.Negative {
+		sb.WriteByte('-')
+	}
+	sb.WriteByte('P')
+	for _, p := range []struct {
+		n    int
+		unit byte
+	}{{d.Years, 'Y'}, {d.Months, 'M'}, {d.Weeks, 'W'}, {d.Days, 'D'}} {
+		if p.n != 0 {
+			sb.WriteString(strconv.Itoa(p.n))
+			sb.WriteByte(p.unit)
+		}
+	}
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 {
+		sb.WriteByte('T')
+		for _, p := range []struct {
+			n    float64
+			unit byte
+		}{{d.Hours, 'H'}, {d.Minutes, 'M'}, {d.Seconds, 'S'}} {
+			if p.n != 0 {
+				sb.WriteString(strconv.FormatFloat(p.n, 'f', -1, 64))
+				sb.WriteByte(p.unit)
+			}
+		}
+	}
+	if sb.Len() == 1 || (d.Negative && sb.Len() == 2) {
+		return "PT0S"
+	}
+	return sb.String()
+}
+
+// Describe renders the duration in words, e.g. "2 hours and 45 minutes".
+func (d ISODuration) Describe() string {
+	var parts []string
+	for _, p := range []struct {
+		n    float64
+		unit string
+	}{
+		{float64(d.Years), "year"}, {float64(d.Months), "month"}, {float64(d.Weeks), "week"}, {float64(d.Days), "day"},
+		{d.Hours, "hour"}, {d.Minutes, "minute"}, {d.Seconds, "second"},
+	} {
+		if p.n == 0 {
+			continue
+		}
+		unit := p.unit
+		if p.n != 1 {
+			unit += "s"
+		}
+		parts = append(parts, strconv.FormatFloat(p.n, 'f', -1, 64)+" "+unit)
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return joinWords(parts)
+}
+
+// addCalendar adds years, months and days to t on its wall clock,
+// clamping the day to the end of shorter months.
+func addCalendar(t time.Time, years, months, days int) time.Time {
+	y, m, d := t.Date()
+	if years != 0 || months != 0 {
+		total := y*12 + int(m) - 1 + years*12 + months
+		y, m = floorDiv(total, 12), time.Month(total-floorDiv(total, 12)*12+1)
+		d = min(d, daysInMonth(y, int(m)))
+	}
+	h, mi, s := t.Clock()
+	return wallTime(time.Date(y, m, d+days, h, mi, s, t.Nanosecond(), time.UTC), t.Location())
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// ParseNatural resolves a natural-language expression ("next Tuesday
+// 3pm", "in 2 weeks", "last day of next month", "2h45m ago"), an ISO 8601
+// duration (P1Y2M10DT2H30M) or an ISO 8601 interval (start/end,
+// start/duration or duration/end) against opts.
+func ParseNatural(input string, opts ParseOptions) (*NaturalResult, error) {
+	ref, loc, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return parseNatural(input, ref, loc)
+}
+
+func parseNatural(input string, ref time.Time, loc *time.Location) (*NaturalResult, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return nil, fmt.Errorf("date string is required")
+	}
+	if strings.Count(s, "/") == 2 && strings.HasPrefix(strings.ToUpper(s), "R") {
+		return nil, fmt.Errorf("repeating intervals are not supported")
+	}
+	if strings.Count(s, "/") == 1 {
+		return parseISOInterval(s, loc)
+	}
+	if isoDurationRe.MatchString(strings.ToUpper(s)) {
+		d, err := ParseISODuration(s)
+		if err != nil {
+			return nil, err
+		}
+		t, err := d.AddTo(ref)
+		if err != nil {
+			return nil, err
+		}
+		return &NaturalResult{
+			Kind:           "duration",
+			Time:           t,
+			Duration:       &d,
+			Interpretation: relativeWords(d, "after", "the reference time"),
+		}, nil
+	}
+
+	p := &naturalParser{toks: naturalTokens(s), ref: ref, loc: loc}
+	res, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unrecognised %q", strings.Join(p.toks[p.pos:], " "))
+	}
+	return res, nil
+}
+
+// relativeWords describes a duration before or after an anchor.
+func relativeWords(d ISODuration, direction, anchor string) string {
+	if d.Negative {
+		d.Negative = false
+		if direction == "after" {
+			direction = "before"
+		} else {
+			direction = "after"
+		}
+	}
+	return d.Describe() + " " + direction + " " + anchor
+}
+
+// parseISOInterval parses start/end, start/duration or duration/end,
+// reading dates and times without a zone in loc.
+func parseISOInterval(s string, loc *time.Location) (*NaturalResult, error) {
+	a, b, _ := strings.Cut(s, "/")
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	aDur := isoDurationRe.MatchString(strings.ToUpper(a))
+	bDur := isoDurationRe.MatchString(strings.ToUpper(b))
+	res := &NaturalResult{Kind: "interval"}
+	switch {
+	case aDur && bDur:
+		return nil, fmt.Errorf("an interval needs a start or an end")
+	case aDur:
+		d, err := ParseISODuration(a)
+		if err != nil {
+			return nil, err
+		}
+		end, _, err := parseLocalTime(b, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval end: %w", err)
+		}
+		neg := d
+		neg.Negative = !d.Negative
+		if res.Time, err = neg.AddTo(end); err != nil {
+			return nil, err
+		}
+		res.End, res.Duration = end, &d
+		res.Interpretation = d.Describe() + " ending " + end.Format(time.RFC3339)
+	case bDur:
+		d, err := ParseISODuration(b)
+		if err != nil {
+			return nil, err
+		}
+		start, _, err := parseLocalTime(a, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval start: %w", err)
+		}
+		if res.End, err = d.AddTo(start); err != nil {
+			return nil, err
+		}
+		res.Time, res.Duration = start, &d
+		res.Interpretation = d.Describe() + " starting " + start.Format(time.RFC3339)
+	default:
+		start, _, err := parseLocalTime(a, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval start: %w", err)
+		}
+		end, _, err := parseLocalTime(b, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval end: %w", err)
+		}
+		res.Time, res.End = start, end
+		res.Interpretation = "from " + start.Format(time.RFC3339) + " to " + end.Format(time.RFC3339)
+	}
+	if res.End.Before(res.Time) {
+		return nil, fmt.Errorf("interval end is before its start")
+	}
+	return res, nil
+}
+
+var naturalNormalizer = strings.NewReplacer(",", " ", "a.m.", "am", "p.m.", "pm")
+
+// naturalTokens lower-cases the input and splits it into words.
+func naturalTokens(s string) []string {
+	s = naturalNormalizer.Replace(strings.ToLower(s))
+	return strings.Fields(strings.TrimSuffix(strings.TrimSpace(s), "."))
+}
+
+var (
+	naturalWeekdays = map[string]time.Weekday{
+		"sunday": time.Sunday, "sun": time.Sunday,
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+	}
+	naturalMonths = map[string]time.Month{
+		"january": time.January, "jan": time.January,
+		"february": time.February, "feb": time.February,
+		"march": time.March, "mar": time.March,
+		"april": time.April, "apr": time.April,
+		"may":  time.May,
+		"june": time.June, "jun": time.June,
+		"july": time.July, "jul": time.July,
+		"august": time.August, "aug": time.August,
+		"september": time.September, "sep": time.September, "sept": time.September,
+		"october": time.October, "oct": time.October,
+		"november": time.November, "nov": time.November,
+		"december": time.December, "dec": time.December,
+	}
+	naturalNumbers = map[string]int{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+		"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	}
+	naturalOrdinals = map[string]int{
+		"first": 1, "1st": 1, "second": 2, "2nd": 2, "third": 3, "3rd": 3,
+		"fourth": 4, "4th": 4, "fifth": 5, "5th": 5, "last": -1,
+	}
+	compactDurationRe = regexp.MustCompile(`^(?:\d+(?:\.\d+)?(?:ms|s|m|h|d|w))+$`)
+	compactPartRe     = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w)`)
+	clockRe           = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?(am|pm)?$`)
+	dayNumberRe       = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+)
+
+// naturalParser reads tokens left to right; each method consumes what it
+// recognises and leaves pos unchanged when it does not match.
+type naturalParser struct {
+	toks []string
+	pos  int
+	ref  time.Time
+	loc  *time.Location
+}
+
+func (p *naturalParser) peek(n int) string {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return ""
+}
+
+// accept consumes the next tokens when they equal words.
+func (p *naturalParser) accept(words ...string) bool {
+	for i, w := range words {
+		if p.peek(i) != w {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// parse reads a whole expression: a relative amount ("in 2 weeks", "3
+// days ago", "2h45m"), or a day with an optional time of day in either
+// order ("next tuesday 3pm", "3pm tomorrow", "noon").
+func (p *naturalParser) parse() (*NaturalResult, error) {
+	if p.accept("in") {
+		d, ok, err := p.amount()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("expected an amount of time after \"in\"")
+		}
+		t, err := d.AddTo(p.ref)
+		if err != nil {
+			return nil, err
+		}
+		return p.withClock(t, relativeWords(d, "after", "the reference time"))
+	}
+
+	d, ok, err := p.amount()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		neg := d
+		neg.Negative = !d.Negative
+		switch {
+		case p.accept("ago") || p.accept("earlier"):
+			t, err := neg.AddTo(p.ref)
+			if err != nil {
+				return nil, err
+			}
+			return p.withClock(t, relativeWords(d, "before", "the reference time"))
+		case p.accept("from", "now") || p.accept("later") || p.accept("hence"):
+			t, err := d.AddTo(p.ref)
+			if err != nil {
+				return nil, err
+			}
+			return p.withClock(t, relativeWords(d, "after", "the reference time"))
+		case p.peek(0) == "before" || p.peek(0) == "after" || p.peek(0) == "from":
+			direction := p.peek(0)
+			p.pos++
+			anchor, desc, err := p.anchor()
+			if err != nil {
+				return nil, err
+			}
+			if direction == "before" {
+				t, err := neg.AddTo(anchor)
+				if err != nil {
+					return nil, err
+				}
+				return p.withClock(t, relativeWords(d, "before", desc))
+			}
+			t, err := d.AddTo(anchor)
+			if err != nil {
+				return nil, err
+			}
+			return p.withClock(t, relativeWords(d, "after", desc))
+		case p.pos == len(p.toks):
+			t, err := d.AddTo(p.ref)
+			if err != nil {
+				return nil, err
+			}
+			return &NaturalResult{
+				Kind:           "duration",
+				Time:           t,
+				Duration:       &d,
+				Interpretation: relativeWords(d, "after", "the reference time"),
+			}, nil
+		}
+		return nil, fmt.Errorf("expected \"ago\", \"from now\", \"before\" or \"after\" after %s", d.Describe())
+	}
+
+	// A time of day may come first ("3pm tomorrow") or stand alone.
+	if h, m, s, desc, ok, err := p.clock(); err != nil {
+		return nil, err
+	} else if ok {
+		day, dayDesc, found, err := p.day()
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			day, dayDesc = startOfDay(p.ref), "the reference date"
+		}
+		return &NaturalResult{Kind: "instant", Time: setClock(day, h, m, s), Interpretation: dayDesc + ", at " + desc}, nil
+	}
+
+	t, desc, err := p.anchor()
+	if err != nil {
+		return nil, err
+	}
+	return p.withClock(t, desc)
+}
+
+// withClock applies a trailing time of day ("at 9am") to t.
+func (p *naturalParser) withClock(t time.Time, desc string) (*NaturalResult, error) {
+	h, m, s, clockDesc, ok, err := p.clock()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t, desc = setClock(t, h, m, s), desc+", at "+clockDesc
+	}
+	return &NaturalResult{Kind: "instant", Time: t, Interpretation: desc}, nil
+}
+
+// anchor reads a day or period expression, failing when there is none.
+func (p *naturalParser) anchor() (time.Time, string, error) {
+	t, desc, ok, err := p.day()
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if !ok {
+		if p.pos < len(p.toks) {
+			return time.Time{}, "", fmt.Errorf("unrecognised %q", strings.Join(p.toks[p.pos:], " "))
+		}
+		return time.Time{}, "", fmt.Errorf("expected a date")
+	}
+	return t, desc, nil
+}
+
+// amount reads one or more "<number> <unit>" pairs ("2 weeks", "an hour
+// and 30 minutes") or a compact duration ("2h45m", "1d12h").
+func (p *naturalParser) amount() (ISODuration, bool, error) {
+	var d ISODuration
+	if tok := p.peek(0); compactDurationRe.MatchString(tok) {
+		for _, m := range compactPartRe.FindAllStringSubmatch(tok, -1) {
+			if err := addAmount(&d, m[1], m[2]); err != nil {
+				return d, false, err
+			}
+		}
+		p.pos++
+		return d, true, nil
+	}
+
+	found := false
+	for {
+		start := p.pos
+		if found {
+			p.accept("and")
+		}
+		n := p.peek(0)
+		if v, ok := naturalNumbers[n]; ok {
+			n = strconv.Itoa(v)
+		}
+		if _, err := strconv.ParseFloat(n, 64); err != nil || !isAmountUnit(p.peek(1)) {
+			p.pos = start
+			return d, found, nil
+		}
+		if err := addAmount(&d, n, p.peek(1)); err != nil {
+			return d, false, err
+		}
+		p.pos += 2
+		found = true
+	}
+}
+
+var amountUnits = map[string]string{
+	"ms": "ms", "millisecond": "ms", "milliseconds": "ms",
+	"s": "s", "sec": "s", "secs": "s", "second": "s", "seconds": "s",
+	"m": "m", "min": "m", "mins": "m", "minute": "m", "minutes": "m",
+	"h": "h", "hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"d": "d", "day": "d", "days": "d",
+	"w": "w", "wk": "w", "wks": "w", "week": "w", "weeks": "w",
+	"fortnight": "f", "fortnights": "f",
+	"mo": "mo", "month": "mo", "months": "mo",
+	"y": "y", "yr": "y", "yrs": "y", "year": "y", "years": "y",
+}
+
+func isAmountUnit(tok string) bool {
+	_, ok := amountUnits[tok]
+	return ok
+}
+
+// maxCalendarAmount bounds a day, week, month or year count, well past
+// any that AddTo can apply, so that converting and summing the counts
+// cannot overflow an int.
+const maxCalendarAmount = 1e7
+
+// addAmount adds n of unit to d. Amounts must be finite and not
+// negative, and calendar units whole numbers no larger than
+// maxCalendarAmount in total.
+func addAmount(d *ISODuration, n, unit string) error {
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || f < 0 {
+		return fmt.Errorf("invalid number %q", n)
+	}
+	unit = amountUnits[unit]
+	switch unit {
+	case "ms":
+		d.Seconds += f / 1000
+		return nil
+	case "s":
+		d.Seconds += f
+		return nil
+	case "m":
+		d.Minutes += f
+		return nil
+	case "h":
+		d.Hours += f
+		return nil
+	}
+	if f != math.Trunc(f) {
+		return fmt.Errorf("fractional days, weeks, months and years are not supported (%s)", n)
+	}
+	var total *int
+	switch unit {
+	case "d":
+		total = &d.Days
+	case "w":
+		total = &d.Weeks
+	case "f":
+		total, f = &d.Weeks, 2*f
+	case "mo":
+		total = &d.Months
+	case "y":
+		total = &d.Years
+	}
+	if f > maxCalendarAmount-float64(*total) {
+		return fmt.Errorf("%s is too large: days, weeks, months and years are limited to %d in total", n, int(maxCalendarAmount))
+	}
+	*total += int(f)
+	return nil
+}
+
+// clock reads a time of day: "noon", "midnight", "15:30", "3pm", "3:30
+// pm", optionally after "at". A bare number is only a time after "at".
+func (p *naturalParser) clock() (h, m, s int, desc string, ok bool, err error) {
+	start := p.pos
+	at := p.accept("at")
+	switch {
+	case p.accept("noon") || p.accept("midday"):
+		return 12, 0, 0, "12:00", true, nil
+	case p.accept("midnight"):
+		return 0, 0, 0, "00:00", true, nil
+	}
+	match := clockRe.FindStringSubmatch(p.peek(0))
+	if match == nil {
+		p.pos = start
+		if at {
+			return 0, 0, 0, "", false, fmt.Errorf("expected a time of day")
+		}
+		return 0, 0, 0, "", false, nil
+	}
+	meridiem := match[4]
+	if meridiem == "" && (p.peek(1) == "am" || p.peek(1) == "pm") {
+		meridiem = p.peek(1)
+		p.pos++
+	}
+	if !at && match[2] == "" && meridiem == "" {
+		p.pos = start
+		return 0, 0, 0, "", false, nil
+	}
+	p.pos++
+	h, _ = strconv.Atoi(match[1])
+	m, _ = strconv.Atoi(match[2])
+	s, _ = strconv.Atoi(match[3])
+	switch {
+	case meridiem != "" && (h < 1 || h > 12):
+		return 0, 0, 0, "", false, fmt.Errorf("hour %d is out of range for %s", h, meridiem)
+	case meridiem == "pm" && h != 12:
+		h += 12
+	case meridiem == "am" && h == 12:
+		h = 0
+	}
+	if h > 23 || m > 59 || s > 59 {
+		return 0, 0, 0, "", false, fmt.Errorf("invalid time of day %q", p.toks[p.pos-1])
+	}
+	desc = fmt.Sprintf("%02d:%02d", h, m)
+	if s != 0 {
+		desc += fmt.Sprintf(":%02d", s)
+	}
+	return h, m, s, desc, true, nil
+}
+
+// day reads a day or point-in-period expression. Days resolve to
+// midnight; "now", "next week" and the like keep the reference clock.
+func (p *naturalParser) day() (time.Time, string, bool, error) {
+	start := p.pos
+	p.accept("the")
+	today := startOfDay(p.ref)
+
+	switch {
+	case p.accept("now") || p.accept("right", "now"):
+		return p.ref, "the reference time", true, nil
+	case p.accept("today"):
+		return today, "the reference date", true, nil
+	case p.accept("tomorrow"):
+		return addCalendar(today, 0, 0, 1), "the day after the reference date", true, nil
+	case p.accept("yesterday"):
+		return addCalendar(today, 0, 0, -1), "the day before the reference date", true, nil
+	case p.accept("day", "after", "tomorrow"):
+		return addCalendar(today, 0, 0, 2), "2 days after the reference date", true, nil
+	case p.accept("day", "before", "yesterday"):
+		return addCalendar(today, 0, 0, -2), "2 days before the reference date", true, nil
+	}
+
+	// "first monday of next month", "last day of the year".
+	if n, ok := naturalOrdinals[p.peek(0)]; ok && p.peek(2) == "of" {
+		what := p.peek(1)
+		wd, isWeekday := naturalWeekdays[what]
+		if what == "day" || isWeekday {
+			p.pos += 3
+			period, err := p.period()
+			if err != nil {
+				return time.Time{}, "", false, err
+			}
+			if what == "day" {
+				t, err := period.nthDay(n)
+				return t, "the " + ordinalWords(n) + " day of " + period.desc, err == nil, err
+			}
+			t, err := period.nthWeekday(n, wd)
+			return t, "the " + ordinalWords(n) + " " + wd.String() + " of " + period.desc, err == nil, err
+		}
+	}
+
+	// "start of next week", "end of the month".
+	for _, edge := range []string{"start", "beginning", "end"} {
+		if p.accept(edge, "of") {
+			period, err := p.period()
+			if err != nil {
+				return time.Time{}, "", false, err
+			}
+			if edge == "end" {
+				return period.end.Add(-time.Second), "the end of " + period.desc, true, nil
+			}
+			return period.start, "the start of " + period.desc, true, nil
+		}
+	}
+
+	// Weekdays: "tuesday", "this tuesday", "next tuesday", "last tuesday".
+	qualifier := ""
+	switch q := p.peek(0); q {
+	case "this", "next", "coming", "last", "previous":
+		qualifier = q
+	}
+	word := p.peek(0)
+	if qualifier != "" {
+		word = p.peek(1)
+	}
+	if wd, ok := naturalWeekdays[word]; ok {
+		if qualifier != "" {
+			p.pos++
+		}
+		p.pos++
+		ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+		switch qualifier {
+		case "next", "coming":
+			if ahead == 0 {
+				ahead = 7
+			}
+			return addCalendar(today, 0, 0, ahead), "the first " + wd.String() + " after the reference date", true, nil
+		case "last", "previous":
+			back := (int(today.Weekday()) - int(wd) + 7) % 7
+			if back == 0 {
+				back = 7
+			}
+			return addCalendar(today, 0, 0, -back), "the last " + wd.String() + " before the reference date", true, nil
+		}
+		return addCalendar(today, 0, 0, ahead), wd.String() + " on or after the reference date", true, nil
+	}
+
+	// "next week", "last month", "this year": shift the reference time.
+	if qualifier != "" && (word == "week" || word == "month" || word == "year") {
+		p.pos += 2
+		if qualifier == "this" {
+			return p.ref, "the reference time", true, nil
+		}
+		var d ISODuration
+		switch word {
+		case "week":
+			d.Weeks = 1
+		case "month":
+			d.Months = 1
+		default:
+			d.Years = 1
+		}
+		d.Negative = qualifier == "last" || qualifier == "previous"
+		t, err := d.AddTo(p.ref)
+		if err != nil {
+			return time.Time{}, "", false, err
+		}
+		return t, relativeWords(d, "after", "the reference time"), true, nil
+	}
+
+	// Calendar dates: "2024-03-15", "march 5", "5th march 2025",
+	// "5th of march".
+	if t, _, err := parseLocalTime(p.peek(0), p.loc); err == nil && strings.Count(p.peek(0), "-") == 2 {
+		p.pos++
+		return t, t.Format("Monday, 2 January 2006"), true, nil
+	}
+	if month, ok := naturalMonths[p.peek(0)]; ok {
+		if m := dayNumberRe.FindStringSubmatch(p.peek(1)); m != nil {
+			p.pos += 2
+			day, _ := strconv.Atoi(m[1])
+			return p.calendarDate(month, day)
+		}
+	}
+	if m := dayNumberRe.FindStringSubmatch(p.peek(0)); m != nil {
+		skip := 1
+		if p.peek(1) == "of" {
+			skip = 2
+		}
+		if month, ok := naturalMonths[p.peek(skip)]; ok {
+			p.pos += skip + 1
+			day, _ := strconv.Atoi(m[1])
+			return p.calendarDate(month, day)
+		}
+	}
+
+	p.pos = start
+	return time.Time{}, "", false, nil
+}
+
+// calendarDate builds a month/day date, taking an optional following
+// year and defaulting to the reference year.
+func (p *naturalParser) calendarDate(month time.Month, day int) (time.Time, string, bool, error) {
+	year := p.ref.Year()
+	if y, err := strconv.Atoi(p.peek(0)); err == nil && len(p.peek(0)) == 4 {
+		year = y
+		p.pos++
+	}
+	if day < 1 || day > daysInMonth(year, int(month)) {
+		return time.Time{}, "", false, fmt.Errorf("%s has no day %d in %d", month, day, year)
+	}
+	t := wallTime(time.Date(year, month, day, 0, 0, 0, 0, time.UTC), p.loc)
+	return t, t.Format("Monday, 2 January 2006"), true, nil
+}
+
+// naturalPeriod is a week (Monday to Sunday), month, year or day, from
+// start (inclusive) to end (exclusive).
+type naturalPeriod struct {
+	start, end time.Time
+	desc       string
+}
+
+// period reads "this/next/last week|month|year", "the month", a month
+// name with an optional year, a year, or a day expression.
+func (p *naturalParser) period() (naturalPeriod, error) {
+	p.accept("the")
+	today := startOfDay(p.ref)
+	qualifier := 0
+	switch {
+	case p.accept("this"), p.accept("current"):
+	case p.accept("next"), p.accept("coming"):
+		qualifier = 1
+	case p.accept("last"), p.accept("previous"):
+		qualifier = -1
+	}
+
+	var start time.Time
+	var unit string
+	switch p.peek(0) {
+	case "week":
+		start = addCalendar(today, 0, 0, -((int(today.Weekday())+6)%7)+7*qualifier)
+		unit = "week"
+	case "month":
+		start = addCalendar(wallTime(time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), p.loc), 0, qualifier, 0)
+		unit = "month"
+	case "year":
+		start = wallTime(time.Date(today.Year()+qualifier, 1, 1, 0, 0, 0, 0, time.UTC), p.loc)
+		unit = "year"
+	}
+	if unit != "" {
+		p.pos++
+		switch unit {
+		case "week":
+			return naturalPeriod{start, addCalendar(start, 0, 0, 7), "the week of " + start.Format("2 January 2006")}, nil
+		case "month":
+			return naturalPeriod{start, addCalendar(start, 0, 1, 0), start.Format("January 2006")}, nil
+		}
+		return naturalPeriod{start, addCalendar(start, 1, 0, 0), start.Format("2006")}, nil
+	}
+
+	if month, ok := naturalMonths[p.peek(0)]; ok {
+		p.pos++
+		year := today.Year() + qualifier
+		if y, err := strconv.Atoi(p.peek(0)); err == nil && len(p.peek(0)) == 4 {
+			year = y
+			p.pos++
+		}
+		start := wallTime(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), p.loc)
+		return naturalPeriod{start, addCalendar(start, 0, 1, 0), start.Format("January 2006")}, nil
+	}
+	if y, err := strconv.Atoi(p.peek(0)); err == nil && len(p.peek(0)) == 4 && qualifier == 0 {
+		p.pos++
+		start := wallTime(time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC), p.loc)
+		return naturalPeriod{start, addCalendar(start, 1, 0, 0), start.Format("2006")}, nil
+	}
+	if qualifier == 0 {
+		if p.accept("day") {
+			return naturalPeriod{today, addCalendar(today, 0, 0, 1), "the reference date"}, nil
+		}
+		if t, desc, ok, err := p.day(); err != nil {
+			return naturalPeriod{}, err
+		} else if ok {
+			day := startOfDay(t)
+			return naturalPeriod{day, addCalendar(day, 0, 0, 1), desc}, nil
+		}
+	}
+	return naturalPeriod{}, fmt.Errorf("expected a week, month, year or day after \"of\"")
+}
+
+// nthDay returns the period's n-th day, or its last for n = -1.
+func (pd naturalPeriod) nthDay(n int) (time.Time, error) {
+	t := addCalendar(pd.start, 0, 0, n-1)
+	if n < 0 {
+		t = addCalendar(pd.end, 0, 0, n)
+	}
+	if t.Before(pd.start) || !t.Before(pd.end) {
+		return time.Time{}, fmt.Errorf("%s has no %s day", pd.desc, ordinalWords(n))
+	}
+	return t, nil
+}
+
+// nthWeekday returns the period's n-th given weekday, or its last for
+// n = -1.
+func (pd naturalPeriod) nthWeekday(n int, wd time.Weekday) (time.Time, error) {
+	var t time.Time
+	if n > 0 {
+		t = addCalendar(pd.start, 0, 0, (int(wd)-int(pd.start.Weekday())+7)%7+7*(n-1))
+	} else {
+		last := addCalendar(pd.end, 0, 0, -1)
+		t = addCalendar(last, 0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	if t.Before(pd.start) || !t.Before(pd.end) {
+		return time.Time{}, fmt.Errorf("%s has no %s %s", pd.desc, ordinalWords(n), wd)
+	}
+	return t, nil
+}
+
+// ordinalWords names position n ("fifth", or "last" for n = -1).
+func ordinalWords(n int) string {
+	if n < 0 {
+		return "last"
+	}
+	return ordinal(n)
+}
+
+// startOfDay returns midnight (or the first instant) of t's day.
+func startOfDay(t time.Time) time.Time {
+	return setClock(t, 0, 0, 0)
+}
+
+// setClock returns t's day at the given wall-clock time.
+func setClock(t time.Time, h, m, s int) time.Time {
+	y, mo, d := t.Date()
+	return wallTime(time.Date(y, mo, d, h, m, s, 0, time.UTC), t.Location())
+}
@@ -0,0 +1,188 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// naturalRef is Friday 16 October 2026, 10:30 in New York.
+var naturalRef = ParseOptions{Reference: time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC), Timezone: "America/New_York"}
+
+func TestParseNatural(t *testing.T) {
+	tests := []struct {
+		input, want, interpretation string
+	}{
+		{"now", "2026-10-16T10:30:00-04:00", "the reference time"},
+		{"today", "2026-10-16T00:00:00-04:00", "the reference date"},
+		{"Tomorrow at noon", "2026-10-17T12:00:00-04:00", "the day after the reference date, at 12:00"},
+		{"3pm tomorrow", "2026-10-17T15:00:00-04:00", "the day after the reference date, at 15:00"},
+		{"the day before yesterday", "2026-10-14T00:00:00-04:00", "2 days before the reference date"},
+		{"next Tuesday 3pm", "2026-10-20T15:00:00-04:00", "the first Tuesday after the reference date, at 15:00"},
+		{"friday", "2026-10-16T00:00:00-04:00", "Friday on or after the reference date"},
+		{"next friday", "2026-10-23T00:00:00-04:00", "the first Friday after the reference date"},
+		{"last fri at 9:15 a.m.", "2026-10-09T09:15:00-04:00", "the last Friday before the reference date, at 09:15"},
+		{"in 2 weeks", "2026-10-30T10:30:00-04:00", "2 weeks after the reference time"},
+		{"in 2 days at 9am", "2026-10-18T09:00:00-04:00", "2 days after the reference time, at 09:00"},
+		{"two weeks from now", "2026-10-30T10:30:00-04:00", "2 weeks after the reference time"},
+		{"an hour and 30 minutes ago", "2026-10-16T09:00:00-04:00", "1 hour and 30 minutes before the reference time"},
+		{"2h45m ago", "2026-10-16T07:45:00-04:00", "2 hours and 45 minutes before the reference time"},
+		{"3 days after tomorrow", "2026-10-20T00:00:00-04:00", "3 days after the day after the reference date"},
+		{"a fortnight before next monday", "2026-10-05T00:00:00-04:00", "2 weeks before the first Monday after the reference date"},
+		{"next month", "2026-11-16T10:30:00-05:00", "1 month after the reference time"},
+		{"last year", "2025-10-16T10:30:00-04:00", "1 year before the reference time"},
+		{"last day of next month", "2026-11-30T00:00:00-05:00", "the last day of November 2026"},
+		{"first Monday of January 2027", "2027-01-04T00:00:00-05:00", "the first Monday of January 2027"},
+		{"the last friday of the month at 17:00", "2026-10-30T17:00:00-04:00", "the last Friday of October 2026, at 17:00"},
+		{"start of next week", "2026-10-19T00:00:00-04:00", "the start of the week of 19 October 2026"},
+		{"end of this month", "2026-10-31T23:59:59-04:00", "the end of October 2026"},
+		{"end of day", "2026-10-16T23:59:59-04:00", "the end of the reference date"},
+		{"march 5th", "2026-03-05T00:00:00-05:00", "Thursday, 5 March 2026"},
+		{"5th of March 2027 at 9:30 pm", "2027-03-05T21:30:00-05:00", "Friday, 5 March 2027, at 21:30"},
+		{"2026-12-24 6pm", "2026-12-24T18:00:00-05:00", "Thursday, 24 December 2026, at 18:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			res, err := ParseNatural(tt.input, naturalRef)
+			require.NoError(t, err)
+			assert.Equal(t, "instant", res.Kind)
+			assert.Equal(t, tt.want, res.Time.Format(time.RFC3339))
+			assert.Equal(t, tt.interpretation, res.Interpretation)
+		})
+	}
+
+	for _, bad := range []string{
+		"",
+		"not a date",
+		"in 3",
+		"3 days hence ago",
+		"at",
+		"25:00",
+		"13pm",
+		"feb 30",
+		"1.5 days ago",
+		"fifth friday of november",
+		"2 weeks before christmas",
+		"in 99999999999999999999 days",
+		"in 10000001 years",
+		"in 5000001 fortnights",
+		"in 9999999 days and 2 days",
+		"in NaN hours",
+		"in -3 days",
+	} {
+		_, err := ParseNatural(bad, naturalRef)
+		assert.Error(t, err, bad)
+	}
+	_, err := ParseNatural("fifth friday of november", naturalRef)
+	assert.EqualError(t, err, "November 2026 has no fifth Friday")
+	_, err = ParseNatural("now", ParseOptions{Timezone: "Mars/Olympus"})
+	assert.Error(t, err)
+}
+
+func TestParseISODuration(t *testing.T) {
+	d, err := ParseISODuration("P1Y2M10DT2H30M")
+	require.NoError(t, err)
+	assert.Equal(t, ISODuration{Years: 1, Months: 2, Days: 10, Hours: 2, Minutes: 30}, d)
+	assert.Equal(t, "P1Y2M10DT2H30M", d.String())
+	assert.Equal(t, "1 year, 2 months, 10 days, 2 hours and 30 minutes", d.Describe())
+
+	d, err = ParseISODuration("-pt1,5h")
+	require.NoError(t, err)
+	assert.Equal(t, "-PT1.5H", d.String())
+	got, err := d.AddTo(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-01T10:30:00Z", got.Format(time.RFC3339))
+
+	// Months clamp to the end of shorter months; days keep the wall clock
+	// across daylight saving changes.
+	d, _ = ParseISODuration("P1M1D")
+	ny, _ := time.LoadLocation("America/New_York")
+	got, err = d.AddTo(time.Date(2024, 1, 31, 12, 0, 0, 0, ny))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01T12:00:00-05:00", got.Format(time.RFC3339))
+	d, _ = ParseISODuration("P1D")
+	got, err = d.AddTo(time.Date(2024, 3, 9, 12, 0, 0, 0, ny))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-10T12:00:00-04:00", got.Format(time.RFC3339))
+
+	// Durations that overflow time.Duration or the calendar fail rather
+	// than wrapping around.
+	ref := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, huge := range []string{"PT99999999999999H", "PT2562048H", "P8000Y", "-P2026Y", "P9223372036854775807Y", "P99999999999M", "P999999999999D"} {
+		d, err := ParseISODuration(huge)
+		if err == nil {
+			_, err = d.AddTo(ref)
+		}
+		assert.Error(t, err, huge)
+	}
+	d, _ = ParseISODuration("PT2562047H")
+	_, err = d.AddTo(ref)
+	assert.NoError(t, err)
+
+	for _, bad := range []string{"P", "PT", "P1.5D", "1Y", "P1H", "PT1D"} {
+		_, err := ParseISODuration(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseNaturalISO(t *testing.T) {
+	res, err := ParseNatural("P1Y2M10DT2H30M", naturalRef)
+	require.NoError(t, err)
+	assert.Equal(t, "duration", res.Kind)
+	assert.Equal(t, "2027-12-26T13:00:00-05:00", res.Time.Format(time.RFC3339))
+
+	res, err = ParseNatural("2h45m", naturalRef)
+	require.NoError(t, err)
+	assert.Equal(t, "duration", res.Kind)
+	assert.Equal(t, "PT2H45M", res.Duration.String())
+
+	tests := []struct {
+		input, start, end string
+	}{
+		{"2024-01-01/P1M", "2024-01-01T00:00:00-05:00", "2024-02-01T00:00:00-05:00"},
+		{"P1D/2024-03-10T12:00", "2024-03-09T12:00:00-05:00", "2024-03-10T12:00:00-04:00"},
+		{"2024-01-01T00:00:00Z/2024-01-02T06:00:00Z", "2023-12-31T19:00:00-05:00", "2024-01-02T01:00:00-05:00"},
+	}
+	for _, tt := range tests {
+		res, err := ParseNatural(tt.input, naturalRef)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, "interval", res.Kind)
+		assert.Equal(t, tt.start, res.Time.Format(time.RFC3339), tt.input)
+		assert.Equal(t, tt.end, res.End.Format(time.RFC3339), tt.input)
+	}
+
+	for _, bad := range []string{"P1D/P2D", "2024-02-01/2024-01-01", "R5/2024-01-01/P1D", "2024-01-01/soon", "PT99999999999999H", "in 99999999999999 hours", "2024-01-01/P9000Y"} {
+		_, err := ParseNatural(bad, naturalRef)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseDateStringNatural(t *testing.T) {
+	result, err := ParseDateString("next tuesday 3pm", naturalRef)
+	require.NoError(t, err)
+	assert.Equal(t, "", result["matched_layout"])
+	assert.Equal(t, "instant", result["kind"])
+	assert.Equal(t, "2026-10-20T15:00:00-04:00", result["iso8601"])
+	assert.Equal(t, "America/New_York", result["timezone"])
+	assert.Equal(t, "2026-10-16T10:30:00-04:00", result["reference"])
+
+	result, err = ParseDateString("2024-01-01/P1M", naturalRef)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02-01T00:00:00-05:00", result["end"])
+	assert.Equal(t, "P1M", result["duration"])
+	assert.Equal(t, float64(31*24*3600), result["duration_seconds"])
+
+	// Layouts without a zone are read in the requested timezone, and a
+	// bare time of day falls on the reference date.
+	result, err = ParseDateString("2024-07-04 09:00:00", naturalRef)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-07-04T09:00:00-04:00", result["iso8601"])
+	result, err = ParseDateString("22:13:20", naturalRef)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16T22:13:20-04:00", result["iso8601"])
+
+	_, err = ParseDateString("whenever", naturalRef)
+	assert.ErrorContains(t, err, `unrecognised "whenever"`)
+}
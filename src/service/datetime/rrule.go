@@ -0,0 +1,789 @@
+package datetime
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apimgr/api/src/cron"
+)
+
+// RRuleMaxOccurrences caps how many occurrences ExpandRRule lists.
+const RRuleMaxOccurrences = 1000
+
+// rruleSearchYears bounds how far past the last occurrence the expander
+// looks for the next one, so a rule that can never match (e.g.
+// BYMONTH=2;BYMONTHDAY=30) ends rather than loops.
+const rruleSearchYears = 100
+
+// rruleMaxScan bounds how many occurrences ExpandRRule walks through
+// while skipping those before From or in Exdates.
+const rruleMaxScan = 1000000
+
+// rruleMaxInterval caps INTERVAL so a SECONDLY step times the interval
+// stays well inside time.Duration instead of overflowing to a negative
+// step that walks backwards in time.
+const rruleMaxInterval = 10000
+
+var rruleFreqs = []string{"SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+var rruleUnits = map[string]string{
+	"SECONDLY": "second", "MINUTELY": "minute", "HOURLY": "hour",
+	"DAILY": "day", "WEEKLY": "week", "MONTHLY": "month", "YEARLY": "year",
+}
+
+// rruleSteps holds the sub-daily frequencies, which step through
+// absolute time rather than calendar days.
+var rruleSteps = map[string]time.Duration{
+	"SECONDLY": time.Second, "MINUTELY": time.Minute, "HOURLY": time.Hour,
+}
+
+var rruleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RRuleDay is one BYDAY entry: a weekday with an optional ordinal, so
+// that 1MO is the first Monday and -1FR the last Friday of the month (or
+// of the year for a YEARLY rule without BYMONTH).
+type RRuleDay struct {
+	N       int
+	Weekday time.Weekday
+}
+
+func (d RRuleDay) String() string {
+	if d.N == 0 {
+		return rruleWeekdays[d.Weekday]
+	}
+	return strconv.Itoa(d.N) + rruleWeekdays[d.Weekday]
+}
+
+// RRule is a parsed RFC 5545 recurrence rule. It supports FREQ,
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS and WKST;
+// the hour, minute, second, week-number and year-day filters are not
+// supported, nor is BYSETPOS with a sub-daily FREQ.
+type RRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []RRuleDay
+	ByMonthDay []int
+	ByMonth    []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+
+	// untilLocal marks an UNTIL without a trailing "Z": a wall-clock
+	// time in the start's time zone. untilDate marks a bare date, which
+	// runs to the end of that day.
+	untilLocal bool
+	untilDate  bool
+}
+
+// ParseRRule parses a recurrence rule such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10", with or without a
+// leading "RRULE:".
+func ParseRRule(s string) (*RRule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return nil, fmt.Errorf("rule is empty")
+	}
+
+	r := &RRule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q (expected NAME=value)", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			if !slices.Contains(rruleFreqs, value) {
+				return nil, fmt.Errorf("invalid FREQ %q (expected one of %s)", value, strings.Join(rruleFreqs, ", "))
+			}
+			r.Freq = value
+		case "INTERVAL":
+			r.Interval, err = parseRRuleCount(name, value)
+			if err == nil && r.Interval > rruleMaxInterval {
+				err = fmt.Errorf("invalid INTERVAL %d (expected at most %d)", r.Interval, rruleMaxInterval)
+			}
+		case "COUNT":
+			r.Count, err = parseRRuleCount(name, value)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseRRuleDays(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseRRuleInts(name, value, 31)
+		case "BYMONTH":
+			r.ByMonth, err = parseRRuleInts(name, value, 12)
+			for _, m := range r.ByMonth {
+				if m < 0 {
+					return nil, fmt.Errorf("invalid BYMONTH value %d (expected 1-12)", m)
+				}
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseRRuleInts(name, value, 366)
+		case "WKST":
+			i := slices.Index(rruleWeekdays, value)
+			if i < 0 {
+				return nil, fmt.Errorf("invalid WKST %q (expected one of %s)", value, strings.Join(rruleWeekdays, ", "))
+			}
+			r.WeekStart = time.Weekday(i)
+		case "BYSECOND", "BYMINUTE", "BYHOUR", "BYYEARDAY", "BYWEEKNO":
+			return nil, fmt.Errorf("%s is not supported", name)
+		default:
+			return nil, fmt.Errorf("unknown rule part %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case r.Freq == "":
+		return nil, fmt.Errorf("FREQ is required")
+	case r.Count > 0 && !r.Until.IsZero():
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be given")
+	case r.Freq == "WEEKLY" && len(r.ByMonthDay) > 0:
+		return nil, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	case len(r.BySetPos) > 0 && len(r.ByDay)+len(r.ByMonthDay)+len(r.ByMonth) == 0:
+		return nil, fmt.Errorf("BYSETPOS requires BYDAY, BYMONTHDAY or BYMONTH")
+	case len(r.BySetPos) > 0 && rruleSteps[r.Freq] != 0:
+		// Each sub-daily period holds one instant, so any position but
+		// 1 or -1 never matches and the walk would step through a
+		// century of seconds looking for one.
+		return nil, fmt.Errorf("BYSETPOS cannot be used with FREQ=%s", r.Freq)
+	}
+	if r.Freq != "MONTHLY" && r.Freq != "YEARLY" {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return nil, fmt.Errorf("BYDAY ordinals such as %s need FREQ=MONTHLY or FREQ=YEARLY", d)
+			}
+		}
+	}
+	return r, nil
+}
+
+func parseRRuleCount(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q (expected a positive integer)", name, value)
+	}
+	return n, nil
+}
+
+// parseRRuleInts parses a comma list of non-zero integers within
+// -limit..limit.
+func parseRRuleInts(name, value string, limit int) ([]int, error) {
+	var out []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(item), "+"))
+		if err != nil || n == 0 || n < -limit || n > limit {
+			return nil, fmt.Errorf("invalid %s value %q (expected 1-%d or -%d to -1)", name, item, limit, limit)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func parseRRuleDays(value string) ([]RRuleDay, error) {
+	var out []RRuleDay
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		wd := slices.Index(rruleWeekdays, item[len(item)-2:])
+		if wd < 0 {
+			return nil, fmt.Errorf("invalid BYDAY value %q (expected a weekday such as MO or -1FR)", item)
+		}
+		day := RRuleDay{Weekday: time.Weekday(wd)}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid BYDAY ordinal in %q (expected 1-53 or -53 to -1)", item)
+			}
+			day.N = n
+		}
+		out = append(out, day)
+	}
+	return out, nil
+}
+
+// parseUntil accepts a UTC date-time ("20261231T235959Z"), a local one
+// ("20261231T235959") or a date ("20261231").
+func (r *RRule) parseUntil(value string) error {
+	var err error
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		r.Until, err = time.Parse("20060102T150405Z", value)
+	case strings.Contains(value, "T"):
+		r.Until, err = time.Parse("20060102T150405", value)
+		r.untilLocal = true
+	default:
+		r.Until, err = time.Parse("20060102", value)
+		r.Until = r.Until.Add(24*time.Hour - time.Second)
+		r.untilLocal, r.untilDate = true, true
+	}
+	if err != nil {
+		return fmt.Errorf("invalid UNTIL %q (expected YYYYMMDD or YYYYMMDDTHHMMSS[Z])", value)
+	}
+	return nil
+}
+
+// until returns UNTIL as an instant, reading a local UNTIL in loc.
+func (r *RRule) until(loc *time.Location) time.Time {
+	if r.Until.IsZero() || !r.untilLocal {
+		return r.Until
+	}
+	return sameWall(r.Until, loc)
+}
+
+// sameWall returns the time with t's wall clock in loc.
+func sameWall(t time.Time, loc *time.Location) time.Time {
+	return wallTime(t.UTC().Add(time.Duration(offsetOf(t))*time.Second), loc)
+}
+
+func offsetOf(t time.Time) int {
+	_, offset := t.Zone()
+	return offset
+}
+
+// wallTime reads the wall clock of w (a UTC time) in loc. A wall time
+// skipped by a daylight saving change is read with the offset in force
+// before the change, moving it forward by the gap (RFC 5545 section
+// 3.3.5), and a repeated one resolves to its first instance.
+func wallTime(w time.Time, loc *time.Location) time.Time {
+	t := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+	if t.Add(time.Duration(offsetOf(t)) * time.Second).UTC().Equal(w) {
+		// time.Date may pick either instance of a repeated wall time;
+		// prefer the one under the previous zone's offset when it exists.
+		if start, _ := cron.ZoneBounds(t, loc); !start.IsZero() {
+			earlier := w.Add(-time.Duration(offsetOf(start.Add(-time.Second).In(loc))) * time.Second).In(loc)
+			if earlier.Before(start) && earlier.Before(t) && earlier.Add(time.Duration(offsetOf(earlier))*time.Second).UTC().Equal(w) {
+				return earlier
+			}
+		}
+		return t
+	}
+	// In a gap: find the transition next to t and use the offset before it.
+	start, end := cron.ZoneBounds(t, loc)
+	transition := start
+	if !end.IsZero() && (start.IsZero() || end.Sub(t) < t.Sub(start)) {
+		transition = end
+	}
+	before := offsetOf(transition.Add(-time.Second).In(loc))
+	return w.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// String renders the rule in a canonical part order.
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		switch {
+		case r.untilDate:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		case r.untilLocal:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		default:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+rruleWeekdays[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+func joinInts(values []int) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strconv.Itoa(v)
+	}
+	return strings.Join(out, ",")
+}
+
+// Occurrences iterates over the rule's occurrences from dtstart, which
+// is always the first one (RFC 5545 section 3.8.5.3). The rule is
+// evaluated in dtstart's time zone, so each occurrence keeps dtstart's
+// wall-clock time of day across daylight saving changes; a time skipped
+// by a spring-forward change moves forward by the gap. The sequence ends
+// at COUNT or UNTIL, at year 9999, or when no occurrence turns up for a
+// century.
+func (r *RRule) Occurrences(dtstart time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		until := r.until(dtstart.Location())
+		n := 0
+		last := dtstart
+		emit := func(t time.Time) bool {
+			if !until.IsZero() && t.After(until) {
+				return false
+			}
+			n++
+			last = t
+			return yield(t) && (r.Count == 0 || n < r.Count)
+		}
+		exhausted := func(periodStart time.Time) bool {
+			return periodStart.Year() > 9999 || last.AddDate(rruleSearchYears, 0, 0).Before(periodStart)
+		}
+
+		if !emit(dtstart) {
+			return
+		}
+
+		if step, ok := rruleSteps[r.Freq]; ok {
+			step *= time.Duration(r.Interval)
+			for t := dtstart.Add(step); !exhausted(t); t = t.Add(step) {
+				y, m, d := t.Date()
+				if !r.dayMatches(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+					// Jump to the last step before the next local day.
+					next := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+					t = t.Add((next.Sub(t) - 1) / step * step)
+					continue
+				}
+				if !emit(t) {
+					return
+				}
+			}
+			return
+		}
+
+		hour, min, sec := dtstart.Clock()
+		for p := 0; ; p++ {
+			first, days := r.periodDays(dtstart, p)
+			if exhausted(first) {
+				return
+			}
+			for _, day := range r.setPositions(days) {
+				t := wallTime(time.Date(day.Year(), day.Month(), day.Day(), hour, min, sec, dtstart.Nanosecond(), time.UTC), dtstart.Location())
+				if !t.After(dtstart) {
+					continue
+				}
+				if !emit(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// periodDays returns the first day of the p-th period (DAILY, WEEKLY,
+// MONTHLY or YEARLY) after dtstart's and the days in it the rule
+// selects, in order. Days are midnight UTC dates.
+func (r *RRule) periodDays(dtstart time.Time, p int) (time.Time, []time.Time) {
+	y, m, d := dtstart.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	step := p * r.Interval
+	var days []time.Time
+
+	switch r.Freq {
+	case "DAILY":
+		day := start.AddDate(0, 0, step)
+		if r.dayMatches(day) {
+			days = append(days, day)
+		}
+		return day, days
+
+	case "WEEKLY":
+		back := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		week := start.AddDate(0, 0, 7*step-back)
+		for i := 0; i < 7; i++ {
+			day := week.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() != start.Weekday() {
+				continue
+			}
+			if r.dayMatches(day) {
+				days = append(days, day)
+			}
+		}
+		return week, days
+
+	case "MONTHLY":
+		month := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, int(month.Month())) {
+			days = r.monthDays(month, d)
+		}
+		return month, days
+
+	default: // YEARLY
+		year := time.Date(y+step, 1, 1, 0, 0, 0, 0, time.UTC)
+		switch {
+		case len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+			days = r.monthDays(time.Date(year.Year(), m, 1, 0, 0, 0, 0, time.UTC), d)
+		case len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0:
+			// BYDAY ordinals count through the whole year.
+			last := year.AddDate(1, 0, -1)
+			for day := year; !day.After(last); day = day.AddDate(0, 0, 1) {
+				if r.weekdayMatches(day, year, last) {
+					days = append(days, day)
+				}
+			}
+		default:
+			for month := 1; month <= 12; month++ {
+				if len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, month) {
+					days = append(days, r.monthDays(time.Date(year.Year(), time.Month(month), 1, 0, 0, 0, 0, time.UTC), d)...)
+				}
+			}
+		}
+		return year, days
+	}
+}
+
+// monthDays lists the days of month the rule selects. Without BYDAY or
+// BYMONTHDAY that is day dom, skipped in months too short for it.
+func (r *RRule) monthDays(month time.Time, dom int) []time.Time {
+	last := month.AddDate(0, 1, -1)
+	var days []time.Time
+	for day := month; !day.After(last); day = day.AddDate(0, 0, 1) {
+		switch {
+		case len(r.ByDay) == 0 && len(r.ByMonthDay) == 0:
+			if day.Day() != dom {
+				continue
+			}
+		case !r.monthDayMatches(day) || !r.weekdayMatches(day, month, last):
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// dayMatches applies BYMONTH, BYMONTHDAY and plain BYDAY weekdays to a
+// single day, for the frequencies where they only filter.
+func (r *RRule) dayMatches(day time.Time) bool {
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(day.Month())) {
+		return false
+	}
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	return r.monthDayMatches(day) && r.weekdayMatches(day, day, last)
+}
+
+func (r *RRule) monthDayMatches(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	n := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return slices.Contains(r.ByMonthDay, day.Day()) || slices.Contains(r.ByMonthDay, day.Day()-n-1)
+}
+
+// weekdayMatches applies BYDAY to day, counting ordinals within
+// first..last.
+func (r *RRule) weekdayMatches(day, first, last time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, bd := range r.ByDay {
+		if bd.Weekday != day.Weekday() {
+			continue
+		}
+		fromStart := int(day.Sub(first).Hours()/24)/7 + 1
+		fromEnd := -(int(last.Sub(day).Hours()/24)/7 + 1)
+		if bd.N == 0 || bd.N == fromStart || bd.N == fromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// setPositions applies BYSETPOS to one period's days.
+func (r *RRule) setPositions(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	var out []time.Time
+	for i, day := range days {
+		if slices.Contains(r.BySetPos, i+1) || slices.Contains(r.BySetPos, i-len(days)) {
+			out = append(out, day)
+		}
+	}
+	return out
+}
+
+// Describe renders the rule in plain English, e.g. "Every 2 weeks on
+// Monday and Wednesday, 10 times".
+func (r *RRule) Describe() string {
+	unit := rruleUnits[r.Freq]
+	out := "every " + unit
+	if r.Interval > 1 {
+		out = fmt.Sprintf("every %d %ss", r.Interval, unit)
+	}
+
+	var days, monthDays []string
+	for _, d := range r.ByDay {
+		name := d.Weekday.String()
+		switch {
+		case d.N == 0:
+			days = append(days, name)
+		case d.N < 0:
+			days = append(days, "the "+fromLast(-d.N)+" "+name)
+		default:
+			days = append(days, "the "+ordinal(d.N)+" "+name)
+		}
+	}
+	for _, v := range r.ByMonthDay {
+		if v < 0 {
+			monthDays = append(monthDays, "the "+fromLast(-v)+" day")
+		} else {
+			monthDays = append(monthDays, "day "+strconv.Itoa(v))
+		}
+	}
+	switch {
+	case len(days) > 0 && len(monthDays) > 0:
+		out += " on " + joinWords(days) + " falling on " + joinWords(monthDays) + " of the month"
+	case len(days) > 0:
+		out += " on " + joinWords(days)
+	case len(monthDays) > 0:
+		out += " on " + joinWords(monthDays) + " of the month"
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = time.Month(m).String()
+		}
+		out += " in " + joinWords(months)
+	}
+	if len(r.BySetPos) > 0 {
+		positions := make([]string, len(r.BySetPos))
+		for i, p := range r.BySetPos {
+			if p < 0 {
+				positions[i] = fromLast(-p)
+			} else {
+				positions[i] = ordinal(p)
+			}
+		}
+		out += fmt.Sprintf(", only the %s of these each %s", joinWords(positions), unit)
+	}
+	switch {
+	case r.Count == 1:
+		out += ", once"
+	case r.Count > 1:
+		out += fmt.Sprintf(", %d times", r.Count)
+	case r.untilDate:
+		out += ", until " + r.Until.Format("2006-01-02")
+	case r.untilLocal:
+		out += ", until " + r.Until.Format("2006-01-02 15:04:05")
+	case !r.Until.IsZero():
+		out += ", until " + r.Until.Format("2006-01-02 15:04:05 UTC")
+	}
+	return strings.ToUpper(out[:1]) + out[1:]
+}
+
+// ordinal renders 1-5 as "first" to "fifth" and larger numbers as "6th",
+// "21st" and so on.
+func ordinal(n int) string {
+	if n >= 1 && n <= 5 {
+		return []string{"first", "second", "third", "fourth", "fifth"}[n-1]
+	}
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// fromLast renders a position counted from the end: "last",
+// "second-to-last" and so on.
+func fromLast(n int) string {
+	if n == 1 {
+		return "last"
+	}
+	return ordinal(n) + "-to-last"
+}
+
+// joinWords joins items as "a", "a and b" or "a, b and c".
+func joinWords(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// RRuleOptions controls ExpandRRule. Start is the first occurrence
+// (DTSTART); it and Exdates accept RFC3339 times or wall-clock times in
+// Timezone (see parseLocalTime), and an Exdate given as a bare date
+// excludes every occurrence on that day. Count caps the occurrences
+// listed (default 10), and From/Until restrict them to a window.
+type RRuleOptions struct {
+	Start    string
+	Timezone string
+	Exdates  []string
+	Count    int
+	From     time.Time
+	Until    time.Time
+}
+
+// ExpandRRule parses an RFC 5545 recurrence rule and lists its
+// occurrences in the requested IANA time zone (UTC by default), skipping
+// excluded dates.
+func ExpandRRule(rule string, opts RRuleOptions) (map[string]interface{}, error) {
+	loc := time.UTC
+	if opts.Timezone != "" {
+		l, err := time.LoadLocation(opts.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", opts.Timezone)
+		}
+		loc = l
+	}
+	if opts.Count == 0 {
+		opts.Count = 10
+	}
+	if opts.Count < 1 || opts.Count > RRuleMaxOccurrences {
+		return nil, fmt.Errorf("count must be between 1 and %d", RRuleMaxOccurrences)
+	}
+	if !opts.From.IsZero() && !opts.Until.IsZero() && opts.Until.Before(opts.From) {
+		return nil, fmt.Errorf("end must not be before start")
+	}
+	if opts.Start == "" {
+		return nil, fmt.Errorf("start is required")
+	}
+	dtstart, _, err := parseLocalTime(opts.Start, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	ex, err := newExdates(opts.Exdates, loc)
+	if err != nil {
+		return nil, err
+	}
+	r, err := ParseRRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []string{}
+	hasMore := false
+	scanned := 0
+	for t := range r.Occurrences(dtstart) {
+		if scanned++; scanned > rruleMaxScan {
+			hasMore = true
+			break
+		}
+		if !opts.Until.IsZero() && t.After(opts.Until) {
+			break
+		}
+		if t.Before(opts.From) || ex.excludes(t) {
+			continue
+		}
+		if len(occurrences) == opts.Count {
+			hasMore = true
+			break
+		}
+		occurrences = append(occurrences, t.Format(time.RFC3339))
+	}
+
+	return map[string]interface{}{
+		"rule":        rule,
+		"normalized":  r.String(),
+		"description": r.Describe(),
+		"timezone":    loc.String(),
+		"start":       dtstart.Format(time.RFC3339),
+		"occurrences": occurrences,
+		"count":       len(occurrences),
+		"finite":      r.Count > 0 || !r.Until.IsZero(),
+		"has_more":    hasMore,
+	}, nil
+}
+
+// exdates holds excluded occurrences: exact instants, and whole days for
+// dates given without a time.
+type exdates struct {
+	instants map[int64]bool
+	days     map[string]bool
+}
+
+func newExdates(values []string, loc *time.Location) (exdates, error) {
+	ex := exdates{instants: map[int64]bool{}, days: map[string]bool{}}
+	for _, v := range values {
+		t, dateOnly, err := parseLocalTime(v, loc)
+		if err != nil {
+			return ex, fmt.Errorf("invalid exdate %q: %w", v, err)
+		}
+		if dateOnly {
+			ex.days[t.Format("2006-01-02")] = true
+		} else {
+			ex.instants[t.Unix()] = true
+		}
+	}
+	return ex, nil
+}
+
+func (ex exdates) excludes(t time.Time) bool {
+	return ex.instants[t.Unix()] || ex.days[t.Format("2006-01-02")]
+}
+
+// localLayouts are the wall-clock layouts parseLocalTime accepts, ISO
+// 8601 and iCalendar's basic format.
+var localLayouts = []struct {
+	layout   string
+	dateOnly bool
+}{
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02 15:04:05", false},
+	{"2006-01-02 15:04", false},
+	{"2006-01-02", true},
+	{"20060102T150405", false},
+	{"20060102", true},
+}
+
+// parseLocalTime parses s as an RFC3339 time or UTC iCalendar time
+// ("20260105T090000Z"), converted to loc, or as a wall-clock time or bare
+// date in loc. dateOnly reports a bare date.
+func parseLocalTime(s string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), false, nil
+	}
+	if t, err := time.Parse("20060102T150405Z", s); err == nil {
+		return t.In(loc), false, nil
+	}
+	for _, l := range localLayouts {
+		if t, err := time.ParseInLocation(l.layout, s, loc); err == nil {
+			return t, l.dateOnly, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("expected RFC3339, YYYY-MM-DDTHH:MM[:SS] or YYYY-MM-DD, got %q", s)
+}
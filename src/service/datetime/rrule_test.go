@@ -0,0 +1,288 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expand lists up to limit occurrences of rule from start (local time
+// in tz), formatted as wall-clock times.
+func expand(t *testing.T, rule, start, tz string, limit int) []string {
+	t.Helper()
+	loc, err := time.LoadLocation(tz)
+	require.NoError(t, err)
+	dtstart, _, err := parseLocalTime(start, loc)
+	require.NoError(t, err)
+	r, err := ParseRRule(rule)
+	require.NoError(t, err)
+	var out []string
+	for occ := range r.Occurrences(dtstart) {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, occ.Format("2006-01-02 15:04"))
+	}
+	return out
+}
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("RRULE:freq=weekly;interval=2;byday=MO,+1WE;wkst=SU;count=4")
+	require.Error(t, err, "ordinal BYDAY needs MONTHLY or YEARLY")
+
+	r, err = ParseRRule("RRULE:freq=monthly;interval=2;byday=MO,-1FR;wkst=SU;count=4")
+	require.NoError(t, err)
+	assert.Equal(t, "MONTHLY", r.Freq)
+	assert.Equal(t, 2, r.Interval)
+	assert.Equal(t, []RRuleDay{{0, time.Monday}, {-1, time.Friday}}, r.ByDay)
+	assert.Equal(t, time.Sunday, r.WeekStart)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;COUNT=4;BYDAY=MO,-1FR;WKST=SU", r.String())
+
+	r, err = ParseRRule("FREQ=DAILY;UNTIL=20261231")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20261231", r.String())
+	r, err = ParseRRule("FREQ=DAILY;UNTIL=20261231T170000Z")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20261231T170000Z", r.String())
+
+	for _, bad := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=10001",
+		"FREQ=SECONDLY;INTERVAL=9223372037",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261231",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTH=-1",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=SECONDLY;BYMONTH=1;BYSETPOS=2",
+		"FREQ=HOURLY;BYDAY=MO;BYSETPOS=1",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COLOR=blue",
+		"FREQ",
+	} {
+		_, err := ParseRRule(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// Examples from RFC 5545 section 3.8.5.3, all starting in New York.
+func TestRRuleOccurrences(t *testing.T) {
+	tests := []struct {
+		name, rule, start string
+		want              []string
+	}{
+		{
+			"daily for 10 occurrences", "FREQ=DAILY;COUNT=10", "1997-09-02T09:00",
+			[]string{"1997-09-02 09:00", "1997-09-03 09:00", "1997-09-04 09:00", "1997-09-05 09:00", "1997-09-06 09:00",
+				"1997-09-07 09:00", "1997-09-08 09:00", "1997-09-09 09:00", "1997-09-10 09:00", "1997-09-11 09:00"},
+		},
+		{
+			"every other day", "FREQ=DAILY;INTERVAL=2", "1997-09-02T09:00",
+			[]string{"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-06 09:00"},
+		},
+		{
+			"weekly until a local date", "FREQ=WEEKLY;UNTIL=19971007", "1997-09-02T09:00",
+			[]string{"1997-09-02 09:00", "1997-09-09 09:00", "1997-09-16 09:00", "1997-09-23 09:00", "1997-09-30 09:00", "1997-10-07 09:00"},
+		},
+		{
+			"every other week on Tuesday and Thursday", "FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH", "1997-09-02T09:00",
+			[]string{"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-16 09:00", "1997-09-18 09:00",
+				"1997-09-30 09:00", "1997-10-02 09:00", "1997-10-14 09:00", "1997-10-16 09:00"},
+		},
+		{
+			"weekdays", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=5", "1997-09-05T09:00",
+			[]string{"1997-09-05 09:00", "1997-09-08 09:00", "1997-09-09 09:00", "1997-09-10 09:00", "1997-09-11 09:00"},
+		},
+		{
+			"first Friday of the month", "FREQ=MONTHLY;COUNT=4;BYDAY=1FR", "1997-09-05T09:00",
+			[]string{"1997-09-05 09:00", "1997-10-03 09:00", "1997-11-07 09:00", "1997-12-05 09:00"},
+		},
+		{
+			"first and last Sunday every other month", "FREQ=MONTHLY;INTERVAL=2;COUNT=6;BYDAY=1SU,-1SU", "1997-09-07T09:00",
+			[]string{"1997-09-07 09:00", "1997-09-28 09:00", "1997-11-02 09:00", "1997-11-30 09:00", "1998-01-04 09:00", "1998-01-25 09:00"},
+		},
+		{
+			"third-to-last day of the month", "FREQ=MONTHLY;BYMONTHDAY=-3", "1997-09-28T09:00",
+			[]string{"1997-09-28 09:00", "1997-10-29 09:00", "1997-11-28 09:00", "1997-12-29 09:00", "1998-01-29 09:00", "1998-02-26 09:00"},
+		},
+		{
+			"the 31st skips short months", "FREQ=MONTHLY;COUNT=4", "2026-01-31T10:00",
+			[]string{"2026-01-31 10:00", "2026-03-31 10:00", "2026-05-31 10:00", "2026-07-31 10:00"},
+		},
+		{
+			"every Friday the 13th", "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", "1997-09-02T09:00",
+			[]string{"1997-09-02 09:00", "1998-02-13 09:00", "1998-03-13 09:00", "1998-11-13 09:00", "1999-08-13 09:00"},
+		},
+		{
+			"last weekday of the month", "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "1997-09-30T09:00",
+			[]string{"1997-09-30 09:00", "1997-10-31 09:00", "1997-11-28 09:00", "1997-12-31 09:00", "1998-01-30 09:00"},
+		},
+		{
+			"yearly in June and July", "FREQ=YEARLY;COUNT=6;BYMONTH=6,7", "1997-06-10T09:00",
+			[]string{"1997-06-10 09:00", "1997-07-10 09:00", "1998-06-10 09:00", "1998-07-10 09:00", "1999-06-10 09:00", "1999-07-10 09:00"},
+		},
+		{
+			"20th Monday of the year", "FREQ=YEARLY;BYDAY=20MO", "1997-05-19T09:00",
+			[]string{"1997-05-19 09:00", "1998-05-18 09:00", "1999-05-17 09:00"},
+		},
+		{
+			"US Thanksgiving", "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "2026-11-26T12:00",
+			[]string{"2026-11-26 12:00", "2027-11-25 12:00", "2028-11-23 12:00"},
+		},
+		{
+			"leap days", "FREQ=YEARLY", "2024-02-29T08:00",
+			[]string{"2024-02-29 08:00", "2028-02-29 08:00", "2032-02-29 08:00"},
+		},
+		{
+			"every 20 minutes", "FREQ=MINUTELY;INTERVAL=20;COUNT=4", "1997-09-02T09:00",
+			[]string{"1997-09-02 09:00", "1997-09-02 09:20", "1997-09-02 09:40", "1997-09-02 10:00"},
+		},
+		{
+			"hourly on Fridays only", "FREQ=HOURLY;INTERVAL=12;BYDAY=FR;COUNT=4", "2026-01-01T12:00",
+			[]string{"2026-01-01 12:00", "2026-01-02 00:00", "2026-01-02 12:00", "2026-01-09 00:00"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, expand(t, tc.rule, tc.start, "America/New_York", len(tc.want)))
+		})
+	}
+
+	t.Run("COUNT and UNTIL end the sequence", func(t *testing.T) {
+		assert.Len(t, expand(t, "FREQ=DAILY;COUNT=3", "2026-01-01", "UTC", 10), 3)
+		assert.Equal(t, []string{"2026-01-01 09:00", "2026-01-02 09:00"},
+			expand(t, "FREQ=DAILY;UNTIL=20260102T140000Z", "2026-01-01T09:00", "America/New_York", 10))
+	})
+
+	t.Run("impossible rule ends", func(t *testing.T) {
+		assert.Equal(t, []string{"2026-01-01 00:00"}, expand(t, "FREQ=DAILY;BYMONTH=2;BYMONTHDAY=30", "2026-01-01", "UTC", 10))
+		assert.Equal(t, []string{"2026-01-01 00:00"}, expand(t, "FREQ=MINUTELY;BYMONTH=2;BYMONTHDAY=30", "2026-01-01", "UTC", 10))
+	})
+}
+
+// Occurrences keep their wall-clock time across DST changes.
+func TestRRuleDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	r, err := ParseRRule("FREQ=DAILY;COUNT=3")
+	require.NoError(t, err)
+
+	var got []string
+	for occ := range r.Occurrences(time.Date(2026, 3, 7, 9, 0, 0, 0, loc)) {
+		got = append(got, occ.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2026-03-07T09:00:00-05:00", "2026-03-08T09:00:00-04:00", "2026-03-09T09:00:00-04:00"}, got)
+
+	// 02:30 does not exist on 8 March and moves forward by the gap.
+	got = nil
+	for occ := range r.Occurrences(time.Date(2026, 3, 7, 2, 30, 0, 0, loc)) {
+		got = append(got, occ.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2026-03-07T02:30:00-05:00", "2026-03-08T03:30:00-04:00", "2026-03-09T02:30:00-04:00"}, got)
+
+	// 01:30 happens twice on 25 October in London; the first counts.
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	got = nil
+	for occ := range r.Occurrences(time.Date(2026, 10, 24, 1, 30, 0, 0, london)) {
+		got = append(got, occ.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2026-10-24T01:30:00+01:00", "2026-10-25T01:30:00+01:00", "2026-10-26T01:30:00Z"}, got)
+
+	// After 2040, where Go derives transitions from the zone's rule.
+	got = nil
+	for occ := range r.Occurrences(time.Date(2045, 3, 11, 2, 30, 0, 0, loc)) {
+		got = append(got, occ.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2045-03-11T02:30:00-05:00", "2045-03-12T03:30:00-04:00", "2045-03-13T02:30:00-04:00"}, got)
+}
+
+func TestRRuleDescribe(t *testing.T) {
+	tests := map[string]string{
+		"FREQ=DAILY": "Every day",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10":   "Every 2 weeks on Monday and Wednesday, 10 times",
+		"FREQ=MONTHLY;BYDAY=-1FR":                       "Every month on the last Friday",
+		"FREQ=MONTHLY;BYMONTHDAY=1,-2":                  "Every month on day 1 and the second-to-last day of the month",
+		"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH":              "Every year on the fourth Thursday in November",
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1": "Every month on Monday, Tuesday, Wednesday, Thursday and Friday, only the last of these each month",
+		"FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=1":   "Every month on Friday falling on day 13 of the month, once",
+		"FREQ=DAILY;UNTIL=20261231":                     "Every day, until 2026-12-31",
+		"FREQ=HOURLY;INTERVAL=6;UNTIL=20261231T120000Z": "Every 6 hours, until 2026-12-31 12:00:00 UTC",
+		"FREQ=YEARLY;BYDAY=20MO":                        "Every year on the 20th Monday",
+	}
+	for rule, want := range tests {
+		r, err := ParseRRule(rule)
+		require.NoError(t, err, rule)
+		assert.Equal(t, want, r.Describe(), rule)
+	}
+}
+
+func TestExpandRRule(t *testing.T) {
+	t.Run("timezone, exdates and window", func(t *testing.T) {
+		result, err := ExpandRRule("FREQ=WEEKLY;BYDAY=MO,WE", RRuleOptions{
+			Start:    "2026-01-05T09:00:00",
+			Timezone: "Europe/London",
+			Exdates:  []string{"2026-01-07T09:00:00", "2026-01-12"},
+			Count:    3,
+			From:     time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2026-01-14T09:00:00Z", "2026-01-19T09:00:00Z", "2026-01-21T09:00:00Z"}, result["occurrences"])
+		assert.Equal(t, "2026-01-05T09:00:00Z", result["start"])
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,WE", result["normalized"])
+		assert.Equal(t, "Every week on Monday and Wednesday", result["description"])
+		assert.Equal(t, false, result["finite"])
+		assert.Equal(t, true, result["has_more"])
+	})
+
+	t.Run("RFC3339 start is converted to the timezone", func(t *testing.T) {
+		result, err := ExpandRRule("FREQ=DAILY;COUNT=2", RRuleOptions{Start: "2026-07-01T13:00:00Z", Timezone: "Asia/Tokyo"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2026-07-01T22:00:00+09:00", "2026-07-02T22:00:00+09:00"}, result["occurrences"])
+		assert.Equal(t, true, result["finite"])
+		assert.Equal(t, false, result["has_more"])
+	})
+
+	t.Run("until window", func(t *testing.T) {
+		result, err := ExpandRRule("FREQ=DAILY", RRuleOptions{Start: "2026-01-01", Until: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result["count"])
+		assert.Equal(t, false, result["has_more"])
+	})
+
+	t.Run("largest interval steps forward", func(t *testing.T) {
+		result, err := ExpandRRule("FREQ=SECONDLY;INTERVAL=10000;COUNT=3", RRuleOptions{Start: "2026-01-01T00:00:00Z"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2026-01-01T00:00:00Z", "2026-01-01T02:46:40Z", "2026-01-01T05:33:20Z"}, result["occurrences"])
+
+		// An impossible date filter ends after the search window with
+		// only DTSTART.
+		result, err = ExpandRRule("FREQ=SECONDLY;INTERVAL=10000;BYMONTH=2;BYMONTHDAY=30", RRuleOptions{Start: "2026-01-01T00:00:00Z"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2026-01-01T00:00:00Z"}, result["occurrences"])
+	})
+
+	for name, opts := range map[string]RRuleOptions{
+		"missing start":  {},
+		"bad start":      {Start: "soon"},
+		"bad timezone":   {Start: "2026-01-01", Timezone: "Mars/Olympus"},
+		"bad exdate":     {Start: "2026-01-01", Exdates: []string{"never"}},
+		"count too high": {Start: "2026-01-01", Count: RRuleMaxOccurrences + 1},
+		"reversed window": {Start: "2026-01-01", From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ExpandRRule("FREQ=DAILY", opts)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -354,6 +354,8 @@ func TestSSHKey(t *testing.T) {
 	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(pair.PublicKey))
 	require.NoError(t, err)
 	assert.Equal(t, "ssh-ed25519", pub.Type())
+	assert.Equal(t, ssh.FingerprintSHA256(pub), pair.Fingerprints.SSHSHA256)
+	assert.NotEmpty(t, pair.Fingerprints.JWKThumbprint)
 }
 
 func TestSSHKeyUnique(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"encoding/pem"
 	"fmt"
 
+	"github.com/apimgr/api/src/service/crypto"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -13,6 +14,8 @@ import (
 type SSHKeyPair struct {
 	PrivateKey string
 	PublicKey  string
+	// Fingerprints are the key's fingerprints, from crypto.DescribeKey.
+	Fingerprints crypto.KeyFingerprints
 }
 
 // SSHKey generates a new Ed25519 SSH key pair and returns the private key in
@@ -37,8 +40,14 @@ func (s *Service) SSHKey() (*SSHKeyPair, error) {
 	}
 	privatePEM := pem.EncodeToMemory(pemBlock)
 
+	info, err := crypto.DescribeKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint key: %w", err)
+	}
+
 	return &SSHKeyPair{
-		PrivateKey: string(privatePEM),
-		PublicKey:  string(authorizedKey),
+		PrivateKey:   string(privatePEM),
+		PublicKey:    string(authorizedKey),
+		Fingerprints: info.Fingerprints,
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -68,6 +69,11 @@ func fetchNominatim(ctx context.Context, endpoint string, out interface{}) error
 	return nil
 }
 
+// GeocodeCacheKey builds the response-cache key for a Geocode query.
+func GeocodeCacheKey(query string) string {
+	return "search:" + strings.ToLower(strings.TrimSpace(query))
+}
+
 // Geocode converts an address or place name to coordinates using the free,
 // keyless Nominatim (OpenStreetMap) search API
 func (s *Service) Geocode(query string) ([]*GeocodeResult, error) {
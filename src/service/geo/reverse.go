@@ -37,6 +37,12 @@ type ReverseGeocodeResult struct {
 	CountryCode string `json:"country_code,omitempty"`
 }
 
+// ReverseGeocodeCacheKey builds the response-cache key for a
+// ReverseGeocode lookup.
+func ReverseGeocodeCacheKey(lat, lon float64) string {
+	return fmt.Sprintf("reverse:%g,%g", lat, lon)
+}
+
 // ReverseGeocode converts coordinates to a human-readable address using the
 // free, keyless Nominatim (OpenStreetMap) reverse geocoding API
 func (s *Service) ReverseGeocode(lat, lon float64) (*ReverseGeocodeResult, error) {
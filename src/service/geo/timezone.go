@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+
+	"github.com/apimgr/api/src/tzboundary"
 )
 
 // openMeteoForecastEndpoint reuses the same free, keyless Open-Meteo
@@ -23,20 +25,65 @@ type openMeteoTimezoneResult struct {
 	UTCOffsetSeconds int    `json:"utc_offset_seconds"`
 }
 
+// Timezone resolution sources reported in TimezoneResult.Source
+const (
+	TimezoneSourceOffline   = "offline"
+	TimezoneSourceOpenMeteo = "open-meteo"
+)
+
+// offlineTimezone resolves a coordinate from the local timezone-boundary
+// dataset, reporting false when no dataset is loaded. It is a variable so
+// tests can exercise the online fallback regardless of the loaded dataset.
+var offlineTimezone = func(lat, lon float64) (string, bool) {
+	return tzboundary.Get().Lookup(lat, lon)
+}
+
 // TimezoneResult represents a resolved IANA timezone for a coordinate
 type TimezoneResult struct {
 	Timezone         string `json:"timezone"`
 	Abbreviation     string `json:"abbreviation"`
 	UTCOffsetSeconds int    `json:"utc_offset_seconds"`
+	Source           string `json:"source"`
 }
 
-// Timezone resolves the IANA timezone name for a coordinate using the free,
-// keyless Open-Meteo forecast API's timezone=auto resolution
+// Timezone resolves the IANA timezone name for a coordinate, from the
+// offline timezone-boundary dataset when one is loaded and otherwise via
+// the free, keyless Open-Meteo forecast API's timezone=auto resolution
 func (s *Service) Timezone(lat, lon float64) (*TimezoneResult, error) {
 	if !s.IsValidCoordinate(lat, lon) {
 		return nil, fmt.Errorf("invalid coordinate: latitude must be -90..90 and longitude -180..180")
 	}
 
+	if result, ok := LocalTimezone(lat, lon, time.Now()); ok {
+		return result, nil
+	}
+	return s.onlineTimezone(lat, lon)
+}
+
+// LocalTimezone resolves a coordinate's timezone from the offline dataset
+// only, with the abbreviation and UTC offset in effect at t. ok is false
+// when no dataset is loaded or the zone is unknown to the embedded tzdata.
+func LocalTimezone(lat, lon float64, t time.Time) (*TimezoneResult, bool) {
+	zone, ok := offlineTimezone(lat, lon)
+	if !ok {
+		return nil, false
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, false
+	}
+	abbrev, offset := t.In(loc).Zone()
+	return &TimezoneResult{
+		Timezone:         zone,
+		Abbreviation:     abbrev,
+		UTCOffsetSeconds: offset,
+		Source:           TimezoneSourceOffline,
+	}, true
+}
+
+// onlineTimezone resolves a coordinate through Open-Meteo
+func (s *Service) onlineTimezone(lat, lon float64) (*TimezoneResult, error) {
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -75,5 +122,6 @@ func (s *Service) Timezone(lat, lon float64) (*TimezoneResult, error) {
 		Timezone:         result.Timezone,
 		Abbreviation:     result.TimezoneAbbrev,
 		UTCOffsetSeconds: result.UTCOffsetSeconds,
+		Source:           TimezoneSourceOpenMeteo,
 	}, nil
 }
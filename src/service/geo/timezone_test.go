@@ -4,16 +4,32 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// withOfflineTimezone replaces the offline dataset lookup for the duration
+// of the test; a nil lookup behaves as if no dataset were loaded.
+func withOfflineTimezone(t *testing.T, lookup func(lat, lon float64) (string, bool)) {
+	t.Helper()
+	original := offlineTimezone
+	if lookup == nil {
+		lookup = func(float64, float64) (string, bool) { return "", false }
+	}
+	offlineTimezone = lookup
+	t.Cleanup(func() {
+		offlineTimezone = original
+	})
+}
+
 // Covers Timezone: invalid coordinate rejected without any network call, a
 // successful Open-Meteo response, an empty timezone field treated as not
 // found, and a non-200 upstream status.
 func TestTimezone(t *testing.T) {
 	s := New()
+	withOfflineTimezone(t, nil)
 
 	t.Run("invalid coordinate", func(t *testing.T) {
 		result, err := s.Timezone(999, 0)
@@ -36,6 +52,7 @@ func TestTimezone(t *testing.T) {
 		assert.Equal(t, "America/New_York", result.Timezone)
 		assert.Equal(t, "EDT", result.Abbreviation)
 		assert.Equal(t, -14400, result.UTCOffsetSeconds)
+		assert.Equal(t, TimezoneSourceOpenMeteo, result.Source)
 	})
 
 	t.Run("empty timezone in response", func(t *testing.T) {
@@ -64,3 +81,49 @@ func TestTimezone(t *testing.T) {
 		assert.Contains(t, err.Error(), "status 502")
 	})
 }
+
+// With a dataset loaded, Timezone must resolve locally without contacting
+// the upstream, computing the abbreviation and offset from tzdata; a zone
+// unknown to tzdata falls back to the upstream.
+func TestTimezoneOffline(t *testing.T) {
+	s := New()
+	var upstreamCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		_, _ = w.Write([]byte(`{"timezone":"Europe/Paris","timezone_abbreviation":"CET","utc_offset_seconds":3600}`))
+	}))
+	defer server.Close()
+	withMockServer(t, server)
+
+	withOfflineTimezone(t, func(lat, lon float64) (string, bool) {
+		return "Asia/Tokyo", true
+	})
+	result, err := s.Timezone(35.68, 139.69)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", result.Timezone)
+	assert.Equal(t, "JST", result.Abbreviation)
+	assert.Equal(t, 9*3600, result.UTCOffsetSeconds)
+	assert.Equal(t, TimezoneSourceOffline, result.Source)
+	assert.Zero(t, upstreamCalls)
+
+	winter := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC)
+	withOfflineTimezone(t, func(lat, lon float64) (string, bool) {
+		return "America/New_York", true
+	})
+	local, ok := LocalTimezone(40.71, -74.01, winter)
+	require.True(t, ok)
+	assert.Equal(t, -5*3600, local.UTCOffsetSeconds)
+	local, ok = LocalTimezone(40.71, -74.01, summer)
+	require.True(t, ok)
+	assert.Equal(t, "EDT", local.Abbreviation)
+
+	withOfflineTimezone(t, func(lat, lon float64) (string, bool) {
+		return "Not/AZone", true
+	})
+	result, err = s.Timezone(48.85, 2.35)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", result.Timezone)
+	assert.Equal(t, TimezoneSourceOpenMeteo, result.Source)
+	assert.Equal(t, 1, upstreamCalls)
+}
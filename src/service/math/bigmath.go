@@ -0,0 +1,346 @@
+package math
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Arbitrary-precision limits. Inputs and results are bounded so that a
+// single request cannot ask for a multi-megabyte integer or an
+// unbounded-precision float (IDEA.md "Threat model & abuse cases").
+const (
+	MaxBigIntBits            = 1 << 16
+	MaxBigFactorial          = 5000
+	DefaultBigFloatPrecision = 256
+	MaxBigFloatPrecision     = 1 << 14
+	MaxBigFloatExponent      = 1 << 20
+
+	// MaxModularBits bounds the operands of ModPow, ModInverse, and
+	// IsProbablePrime, whose cost grows with the cube of the operand size
+	// (4096 bits covers RSA-4096 moduli and their primes).
+	MaxModularBits = 1 << 12
+
+	// PrimalityRounds is the number of Miller-Rabin rounds IsProbablePrime
+	// runs (in addition to the Baillie-PSW test math/big always applies).
+	PrimalityRounds = 20
+)
+
+// Arbitrary-precision errors
+var (
+	ErrBigIntTooLarge     = fmt.Errorf("integer exceeds %d bits", MaxBigIntBits)
+	ErrModularTooLarge    = fmt.Errorf("modular and primality operands must be at most %d bits", MaxModularBits)
+	ErrBigFloatTooLarge   = fmt.Errorf("number magnitude exceeds 2^%d", MaxBigIntBits)
+	ErrInvalidPrecision   = fmt.Errorf("precision must be between 2 and %d bits", MaxBigFloatPrecision)
+	ErrNegativeExponent   = fmt.Errorf("exponent must be non-negative")
+	ErrNoModularInverse   = fmt.Errorf("no modular inverse exists")
+	ErrNonPositiveModulus = fmt.Errorf("modulus must be positive")
+)
+
+// ParseBigInt parses a decimal integer, or a hex/octal/binary one when
+// prefixed with 0x/0o/0b, rejecting values wider than MaxBigIntBits.
+func ParseBigInt(value string) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	// Each input digit contributes at least one bit, so an over-long string
+	// is rejected before math/big spends time parsing it.
+	if len(value) > MaxBigIntBits {
+		return nil, ErrBigIntTooLarge
+	}
+	n, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", value)
+	}
+	if n.BitLen() > MaxBigIntBits {
+		return nil, ErrBigIntTooLarge
+	}
+	return n, nil
+}
+
+// ParseBigFloat parses a decimal or scientific-notation number into a
+// big.Float with the given mantissa precision in bits.
+func ParseBigFloat(value string, prec uint) (*big.Float, error) {
+	if prec < 2 || prec > MaxBigFloatPrecision {
+		return nil, ErrInvalidPrecision
+	}
+	value = strings.TrimSpace(value)
+	if len(value) > MaxBigIntBits {
+		return nil, ErrBigIntTooLarge
+	}
+	f, _, err := big.ParseFloat(value, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", value)
+	}
+	if f.IsInf() {
+		return nil, fmt.Errorf("number %q must be finite", value)
+	}
+	return checkBigFloatResult(f)
+}
+
+// FormatBigFloat renders f with the fewest decimal digits that uniquely
+// identify it at its precision.
+func FormatBigFloat(f *big.Float) string {
+	return f.Text('g', -1)
+}
+
+// checkBigIntResult enforces MaxBigIntBits on a computed result.
+func checkBigIntResult(n *big.Int) (*big.Int, error) {
+	if n.BitLen() > MaxBigIntBits {
+		return nil, ErrBigIntTooLarge
+	}
+	return n, nil
+}
+
+// checkBigFloatResult bounds a float's binary exponent to ±MaxBigIntBits so
+// that rendering it as a decimal string stays cheap.
+func checkBigFloatResult(f *big.Float) (*big.Float, error) {
+	if exp := f.MantExp(nil); exp > MaxBigIntBits || exp < -MaxBigIntBits {
+		return nil, ErrBigFloatTooLarge
+	}
+	return f, nil
+}
+
+// checkModularOperands enforces MaxModularBits on every operand.
+func checkModularOperands(operands ...*big.Int) error {
+	for _, n := range operands {
+		if n.BitLen() > MaxModularBits {
+			return ErrModularTooLarge
+		}
+	}
+	return nil
+}
+
+// BigAdd returns a + b
+func (s *Service) BigAdd(a, b *big.Int) (*big.Int, error) {
+	return checkBigIntResult(new(big.Int).Add(a, b))
+}
+
+// BigSubtract returns a - b
+func (s *Service) BigSubtract(a, b *big.Int) (*big.Int, error) {
+	return checkBigIntResult(new(big.Int).Sub(a, b))
+}
+
+// BigMultiply returns a * b
+func (s *Service) BigMultiply(a, b *big.Int) (*big.Int, error) {
+	if a.BitLen()+b.BitLen() > MaxBigIntBits+1 {
+		return nil, ErrBigIntTooLarge
+	}
+	return checkBigIntResult(new(big.Int).Mul(a, b))
+}
+
+// BigDivide returns the quotient and remainder of a / b, truncated toward
+// zero like Go's integer division.
+func (s *Service) BigDivide(a, b *big.Int) (quotient, remainder *big.Int, err error) {
+	if b.Sign() == 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+	quotient, remainder = new(big.Int).QuoRem(a, b, new(big.Int))
+	return quotient, remainder, nil
+}
+
+// BigModulo returns a mod b with the sign of a, matching Modulo
+func (s *Service) BigModulo(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return new(big.Int).Rem(a, b), nil
+}
+
+// BigPower returns base^exponent for a non-negative exponent, refusing
+// results wider than MaxBigIntBits before computing them.
+func (s *Service) BigPower(base, exponent *big.Int) (*big.Int, error) {
+	if exponent.Sign() < 0 {
+		return nil, ErrNegativeExponent
+	}
+	// |base| <= 1 never grows, so any exponent is fine; otherwise the
+	// result has at least exponent*(bitlen(base)-1) bits.
+	if base.CmpAbs(big.NewInt(1)) > 0 {
+		if exponent.Cmp(big.NewInt(MaxBigIntBits)) > 0 || int64(base.BitLen()-1)*exponent.Int64() > MaxBigIntBits {
+			return nil, ErrBigIntTooLarge
+		}
+	}
+	return checkBigIntResult(new(big.Int).Exp(base, exponent, nil))
+}
+
+// BigSquareRoot returns the integer square root floor(sqrt(n))
+func (s *Service) BigSquareRoot(n *big.Int) (*big.Int, error) {
+	if n.Sign() < 0 {
+		return nil, ErrNegativeSquareRoot
+	}
+	return new(big.Int).Sqrt(n), nil
+}
+
+// BigAbs returns |n|
+func (s *Service) BigAbs(n *big.Int) *big.Int {
+	return new(big.Int).Abs(n)
+}
+
+// BigGCD returns the non-negative greatest common divisor of a and b
+func (s *Service) BigGCD(a, b *big.Int) *big.Int {
+	return new(big.Int).GCD(nil, nil, new(big.Int).Abs(a), new(big.Int).Abs(b))
+}
+
+// BigLCM returns the non-negative least common multiple of a and b;
+// LCM(0, x) is 0 as for LCM.
+func (s *Service) BigLCM(a, b *big.Int) (*big.Int, error) {
+	if a.Sign() == 0 || b.Sign() == 0 {
+		return new(big.Int), nil
+	}
+	product, err := s.BigMultiply(new(big.Int).Abs(a), new(big.Int).Abs(b))
+	if err != nil {
+		return nil, err
+	}
+	return product.Quo(product, s.BigGCD(a, b)), nil
+}
+
+// BigFactorial returns n! for 0 <= n <= MaxBigFactorial using Factorial
+func (s *Service) BigFactorial(n int64) (*big.Int, error) {
+	if n < 0 || n > MaxBigFactorial {
+		return nil, fmt.Errorf("factorial operand must be between 0 and %d", MaxBigFactorial)
+	}
+	return s.Factorial(n), nil
+}
+
+// IsProbablePrime reports whether n is prime using PrimalityRounds rounds
+// of Miller-Rabin with pseudorandom bases plus a Baillie-PSW test. The
+// result is exact for n < 2^64 and wrong with probability below 4^-20
+// otherwise.
+func (s *Service) IsProbablePrime(n *big.Int) (bool, error) {
+	if err := checkModularOperands(n); err != nil {
+		return false, err
+	}
+	if n.Sign() <= 0 {
+		return false, nil
+	}
+	return n.ProbablyPrime(PrimalityRounds), nil
+}
+
+// ModPow returns base^exponent mod modulus. A negative exponent computes
+// the power of the modular inverse, which must exist.
+func (s *Service) ModPow(base, exponent, modulus *big.Int) (*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, ErrNonPositiveModulus
+	}
+	if err := checkModularOperands(base, exponent, modulus); err != nil {
+		return nil, err
+	}
+	result := new(big.Int).Exp(base, exponent, modulus)
+	if result == nil {
+		return nil, ErrNoModularInverse
+	}
+	// Exp leaves a negative base's result in (-modulus, 0]; normalize.
+	return result.Mod(result, modulus), nil
+}
+
+// ModInverse returns x such that a*x ≡ 1 (mod modulus)
+func (s *Service) ModInverse(a, modulus *big.Int) (*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, ErrNonPositiveModulus
+	}
+	if err := checkModularOperands(a, modulus); err != nil {
+		return nil, err
+	}
+	inverse := new(big.Int).ModInverse(new(big.Int).Mod(a, modulus), modulus)
+	if inverse == nil {
+		return nil, ErrNoModularInverse
+	}
+	return inverse, nil
+}
+
+// BigBaseConvert is BaseConvert without the int64 limit: number (expressed
+// in fromBase) is converted to its representation in toBase, both 2-36.
+func (s *Service) BigBaseConvert(number string, fromBase, toBase int) (string, error) {
+	if fromBase < 2 || fromBase > 36 {
+		return "", fmt.Errorf("fromBase must be between 2 and 36")
+	}
+	if toBase < 2 || toBase > 36 {
+		return "", fmt.Errorf("toBase must be between 2 and 36")
+	}
+	trimmed := strings.TrimSpace(number)
+	if len(trimmed) > MaxBigIntBits {
+		return "", ErrBigIntTooLarge
+	}
+	value, ok := new(big.Int).SetString(trimmed, fromBase)
+	if !ok {
+		return "", fmt.Errorf("invalid number %q for base %d", number, fromBase)
+	}
+	if value.BitLen() > MaxBigIntBits {
+		return "", ErrBigIntTooLarge
+	}
+	return value.Text(toBase), nil
+}
+
+// BigFloatAdd returns a + b at the given precision
+func (s *Service) BigFloatAdd(a, b *big.Float, prec uint) (*big.Float, error) {
+	return checkBigFloatResult(new(big.Float).SetPrec(prec).Add(a, b))
+}
+
+// BigFloatSubtract returns a - b at the given precision
+func (s *Service) BigFloatSubtract(a, b *big.Float, prec uint) (*big.Float, error) {
+	return checkBigFloatResult(new(big.Float).SetPrec(prec).Sub(a, b))
+}
+
+// BigFloatMultiply returns a * b at the given precision
+func (s *Service) BigFloatMultiply(a, b *big.Float, prec uint) (*big.Float, error) {
+	return checkBigFloatResult(new(big.Float).SetPrec(prec).Mul(a, b))
+}
+
+// BigFloatDivide returns a / b at the given precision
+func (s *Service) BigFloatDivide(a, b *big.Float, prec uint) (*big.Float, error) {
+	if b.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return checkBigFloatResult(new(big.Float).SetPrec(prec).Quo(a, b))
+}
+
+// BigFloatSquareRoot returns sqrt(n) at the given precision
+func (s *Service) BigFloatSquareRoot(n *big.Float, prec uint) (*big.Float, error) {
+	if n.Sign() < 0 {
+		return nil, ErrNegativeSquareRoot
+	}
+	return new(big.Float).SetPrec(prec).Sqrt(n), nil
+}
+
+// BigFloatAbs returns |n| at the given precision
+func (s *Service) BigFloatAbs(n *big.Float, prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec).Abs(n)
+}
+
+// BigFloatPower returns base^exponent at the given precision by repeated
+// squaring; math/big has no transcendental functions, so the exponent must
+// be an integer with |exponent| <= MaxBigFloatExponent.
+func (s *Service) BigFloatPower(base *big.Float, exponent *big.Float, prec uint) (*big.Float, error) {
+	if !exponent.IsInt() {
+		return nil, fmt.Errorf("exponent must be an integer in bigfloat mode")
+	}
+	e, _ := exponent.Int64()
+	if e > MaxBigFloatExponent || e < -MaxBigFloatExponent {
+		return nil, fmt.Errorf("exponent magnitude must be at most %d", MaxBigFloatExponent)
+	}
+	negative := e < 0
+	if negative {
+		if base.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		e = -e
+	}
+
+	// Intermediate products carry extra guard bits so the final rounding
+	// to prec is accurate.
+	work := prec + 64
+	result := new(big.Float).SetPrec(work).SetInt64(1)
+	square := new(big.Float).SetPrec(work).Set(base)
+	for e > 0 {
+		if e&1 == 1 {
+			result.Mul(result, square)
+		}
+		square.Mul(square, square)
+		e >>= 1
+	}
+	if negative {
+		result.Quo(new(big.Float).SetPrec(work).SetInt64(1), result)
+	}
+	if result.IsInf() {
+		return nil, ErrBigFloatTooLarge
+	}
+	return checkBigFloatResult(new(big.Float).SetPrec(prec).Set(result))
+}
@@ -0,0 +1,208 @@
+package math
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mustBigInt parses a decimal string for test fixtures.
+func mustBigInt(t *testing.T, value string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(value, 10)
+	require.True(t, ok, "invalid fixture %q", value)
+	return n
+}
+
+// ParseBigInt accepts decimal and prefixed hex/octal/binary and rejects
+// garbage and values wider than MaxBigIntBits.
+func TestParseBigInt(t *testing.T) {
+	for input, want := range map[string]string{
+		"123456789012345678901234567890": "123456789012345678901234567890",
+		"-42":                            "-42",
+		"0xff":                           "255",
+		"0o17":                           "15",
+		"0b101":                          "5",
+	} {
+		got, err := ParseBigInt(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got.String(), input)
+	}
+
+	_, err := ParseBigInt("12ab")
+	assert.Error(t, err)
+
+	_, err = ParseBigInt(strings.Repeat("9", MaxBigIntBits+1))
+	assert.ErrorIs(t, err, ErrBigIntTooLarge)
+}
+
+// Big integer arithmetic is exact beyond int64 and enforces the result
+// size limit.
+func TestBigIntArithmetic(t *testing.T) {
+	s := New()
+	a := mustBigInt(t, "9223372036854775807")
+	b := mustBigInt(t, "2")
+
+	got, err := s.BigAdd(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, "9223372036854775809", got.String())
+
+	got, err = s.BigMultiply(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, "18446744073709551614", got.String())
+
+	q, r, err := s.BigDivide(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, "4611686018427387903", q.String())
+	assert.Equal(t, "1", r.String())
+
+	_, _, err = s.BigDivide(a, big.NewInt(0))
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	got, err = s.BigPower(b, big.NewInt(100))
+	require.NoError(t, err)
+	assert.Equal(t, "1267650600228229401496703205376", got.String())
+
+	_, err = s.BigPower(b, big.NewInt(MaxBigIntBits+1))
+	assert.ErrorIs(t, err, ErrBigIntTooLarge)
+
+	_, err = s.BigPower(b, big.NewInt(-1))
+	assert.ErrorIs(t, err, ErrNegativeExponent)
+
+	got, err = s.BigSquareRoot(mustBigInt(t, "1000000000000000000000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000", got.String())
+
+	_, err = s.BigSquareRoot(big.NewInt(-4))
+	assert.ErrorIs(t, err, ErrNegativeSquareRoot)
+
+	got, err = s.BigFactorial(25)
+	require.NoError(t, err)
+	assert.Equal(t, "15511210043330985984000000", got.String())
+
+	_, err = s.BigFactorial(MaxBigFactorial + 1)
+	assert.Error(t, err)
+}
+
+// IsProbablePrime handles values past int64 (a Mersenne prime and its
+// composite neighbour) and rejects operands over MaxModularBits.
+func TestIsProbablePrime(t *testing.T) {
+	s := New()
+	m127 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+
+	prime, err := s.IsProbablePrime(m127)
+	require.NoError(t, err)
+	assert.True(t, prime)
+
+	prime, err = s.IsProbablePrime(new(big.Int).Add(m127, big.NewInt(2)))
+	require.NoError(t, err)
+	assert.False(t, prime)
+
+	prime, err = s.IsProbablePrime(big.NewInt(-7))
+	require.NoError(t, err)
+	assert.False(t, prime)
+
+	_, err = s.IsProbablePrime(new(big.Int).Lsh(big.NewInt(1), MaxModularBits+1))
+	assert.ErrorIs(t, err, ErrModularTooLarge)
+}
+
+// IsPrime stays correct for int64 values above the trial-division range.
+func TestIsPrimeLarge(t *testing.T) {
+	s := New()
+	assert.True(t, s.IsPrime(9223372036854775783))
+	assert.False(t, s.IsPrime(9223372036854775807))
+}
+
+// ModPow and ModInverse cover the textbook case, negative exponents and
+// bases, non-invertible values, and a non-positive modulus.
+func TestModularArithmetic(t *testing.T) {
+	s := New()
+
+	got, err := s.ModPow(big.NewInt(4), big.NewInt(13), big.NewInt(497))
+	require.NoError(t, err)
+	assert.Equal(t, "445", got.String())
+
+	got, err = s.ModPow(big.NewInt(3), big.NewInt(-1), big.NewInt(11))
+	require.NoError(t, err)
+	assert.Equal(t, "4", got.String())
+
+	got, err = s.ModPow(big.NewInt(-2), big.NewInt(3), big.NewInt(5))
+	require.NoError(t, err)
+	assert.Equal(t, "2", got.String())
+
+	_, err = s.ModPow(big.NewInt(2), big.NewInt(-1), big.NewInt(4))
+	assert.ErrorIs(t, err, ErrNoModularInverse)
+
+	_, err = s.ModPow(big.NewInt(2), big.NewInt(3), big.NewInt(0))
+	assert.ErrorIs(t, err, ErrNonPositiveModulus)
+
+	got, err = s.ModInverse(big.NewInt(3), big.NewInt(11))
+	require.NoError(t, err)
+	assert.Equal(t, "4", got.String())
+
+	got, err = s.ModInverse(big.NewInt(-3), big.NewInt(11))
+	require.NoError(t, err)
+	assert.Equal(t, "7", got.String())
+
+	_, err = s.ModInverse(big.NewInt(6), big.NewInt(9))
+	assert.ErrorIs(t, err, ErrNoModularInverse)
+}
+
+// BigBaseConvert converts values wider than int64 that BaseConvert
+// rejects.
+func TestBigBaseConvert(t *testing.T) {
+	s := New()
+
+	got, err := s.BigBaseConvert("ffffffffffffffffffffffffffffffff", 16, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "340282366920938463463374607431768211455", got)
+
+	_, err = s.BigBaseConvert("zz", 10, 16)
+	assert.Error(t, err)
+
+	_, err = s.BigBaseConvert("10", 1, 16)
+	assert.Error(t, err)
+}
+
+// Big float arithmetic honours the requested precision, rejects invalid
+// precisions, and supports integer powers only.
+func TestBigFloatArithmetic(t *testing.T) {
+	s := New()
+
+	one, err := ParseBigFloat("1", 200)
+	require.NoError(t, err)
+	three, err := ParseBigFloat("3", 200)
+	require.NoError(t, err)
+
+	got, err := s.BigFloatDivide(one, three, 200)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(FormatBigFloat(got), "0.33333333333333333333333333333333333333333333333333333333"))
+
+	_, err = s.BigFloatDivide(one, new(big.Float), 200)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	two, err := ParseBigFloat("2", 256)
+	require.NoError(t, err)
+	got, err = s.BigFloatSquareRoot(two, 256)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(FormatBigFloat(got), "1.41421356237309504880168872420969807856967187537694"))
+
+	got, err = s.BigFloatPower(two, big.NewFloat(-2), 64)
+	require.NoError(t, err)
+	assert.Equal(t, "0.25", FormatBigFloat(got))
+
+	_, err = s.BigFloatPower(two, big.NewFloat(0.5), 64)
+	assert.Error(t, err)
+
+	_, err = s.BigFloatPower(two, big.NewFloat(MaxBigFloatExponent), 64)
+	assert.ErrorIs(t, err, ErrBigFloatTooLarge)
+
+	_, err = ParseBigFloat("1", MaxBigFloatPrecision+1)
+	assert.ErrorIs(t, err, ErrInvalidPrecision)
+
+	_, err = ParseBigFloat("abc", 64)
+	assert.Error(t, err)
+}
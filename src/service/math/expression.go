@@ -0,0 +1,622 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Expression evaluation limits. Expressions are tokenized and parsed into a
+// syntax tree of numbers, names, operators, and whitelisted function calls
+// that is then walked — nothing is ever executed — but these bounds keep a
+// single request from burning CPU or stack on pathological input (IDEA.md
+// "Threat model & abuse cases": DoS via expensive operations).
+const (
+	MaxExpressionLength    = 1024
+	MaxExpressionDepth     = 64
+	MaxExpressionVariables = 32
+
+	// maxExpressionFactorial is the largest factorial operand whose result
+	// still fits in a float64 (171! overflows to +Inf).
+	maxExpressionFactorial = 170
+)
+
+// Expression errors
+var (
+	ErrExpressionEmpty   = fmt.Errorf("expression is empty")
+	ErrExpressionTooLong = fmt.Errorf("expression exceeds %d characters", MaxExpressionLength)
+	ErrExpressionTooDeep = fmt.Errorf("expression nesting exceeds %d levels", MaxExpressionDepth)
+	ErrTooManyVariables  = fmt.Errorf("at most %d variables may be bound", MaxExpressionVariables)
+	ErrNonFiniteResult   = fmt.Errorf("expression result is not a finite number")
+)
+
+// expressionConstants are the named constants available to every
+// expression. Variables may not shadow them.
+var expressionConstants = map[string]float64{
+	"pi":  math.Pi,
+	"e":   math.E,
+	"tau": 2 * math.Pi,
+	"phi": math.Phi,
+}
+
+// expressionFunc describes one callable function: its arity (-1 for
+// variadic with at least one argument) and its implementation in terms of
+// the existing Service methods.
+type expressionFunc struct {
+	arity int
+	call  func(s *Service, args []float64) (float64, error)
+}
+
+// expressionFunctions is the whitelist of functions an expression may call.
+var expressionFunctions = map[string]expressionFunc{
+	"sin":   unaryFunc((*Service).Sin),
+	"cos":   unaryFunc((*Service).Cos),
+	"tan":   unaryFunc((*Service).Tan),
+	"asin":  unaryFunc((*Service).Asin),
+	"acos":  unaryFunc((*Service).Acos),
+	"atan":  unaryFunc((*Service).Atan),
+	"rad":   unaryFunc((*Service).DegreesToRadians),
+	"deg":   unaryFunc((*Service).RadiansToDegrees),
+	"log":   unaryFunc((*Service).Log),
+	"ln":    unaryFunc((*Service).Log),
+	"log10": unaryFunc((*Service).Log10),
+	"log2":  unaryFunc((*Service).Log2),
+	"exp":   unaryFunc((*Service).Exp),
+	"cbrt":  unaryFunc((*Service).CubeRoot),
+	"abs":   unaryFunc((*Service).Abs),
+	"round": unaryFunc((*Service).Round),
+	"floor": unaryFunc((*Service).Floor),
+	"ceil":  unaryFunc((*Service).Ceil),
+	"sqrt": {arity: 1, call: func(s *Service, args []float64) (float64, error) {
+		return s.SquareRoot(args[0])
+	}},
+	"pow": {arity: 2, call: func(s *Service, args []float64) (float64, error) {
+		return s.Power(args[0], args[1]), nil
+	}},
+	"factorial": {arity: 1, call: func(s *Service, args []float64) (float64, error) {
+		return s.expressionFactorial(args[0])
+	}},
+	"gcd": {arity: 2, call: func(s *Service, args []float64) (float64, error) {
+		a, b, err := expressionIntegerPair("gcd", args)
+		if err != nil {
+			return 0, err
+		}
+		return math.Abs(float64(s.GCD(a, b))), nil
+	}},
+	"lcm": {arity: 2, call: func(s *Service, args []float64) (float64, error) {
+		a, b, err := expressionIntegerPair("lcm", args)
+		if err != nil {
+			return 0, err
+		}
+		return math.Abs(float64(s.LCM(a, b))), nil
+	}},
+	"min":    variadicFunc((*Service).Min),
+	"max":    variadicFunc((*Service).Max),
+	"sum":    variadicFunc((*Service).Sum),
+	"avg":    variadicFunc((*Service).Average),
+	"median": variadicFunc((*Service).Median),
+}
+
+// unaryFunc adapts a single-argument Service method to an expressionFunc.
+func unaryFunc(fn func(*Service, float64) float64) expressionFunc {
+	return expressionFunc{arity: 1, call: func(s *Service, args []float64) (float64, error) {
+		return fn(s, args[0]), nil
+	}}
+}
+
+// variadicFunc adapts a slice-aggregating Service method to an
+// expressionFunc.
+func variadicFunc(fn func(*Service, []float64) float64) expressionFunc {
+	return expressionFunc{arity: -1, call: func(s *Service, args []float64) (float64, error) {
+		return fn(s, args), nil
+	}}
+}
+
+// ExpressionFunctions returns the sorted names of every function an
+// expression may call.
+func ExpressionFunctions() []string {
+	names := make([]string, 0, len(expressionFunctions))
+	for name := range expressionFunctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExpressionConstants returns the sorted names of every built-in constant.
+func ExpressionConstants() []string {
+	names := make([]string, 0, len(expressionConstants))
+	for name := range expressionConstants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Evaluate parses and evaluates an arithmetic expression such as
+// "(2^10 + sqrt(81)) * sin(pi/4)". Supported syntax: decimal and scientific
+// number literals, + - * / % ^ (right-associative), unary +/-, postfix !,
+// parentheses, the constants listed by ExpressionConstants, the functions
+// listed by ExpressionFunctions, and caller-bound variables.
+func (s *Service) Evaluate(expression string, variables map[string]float64) (float64, error) {
+	if len(expression) > MaxExpressionLength {
+		return 0, ErrExpressionTooLong
+	}
+	if strings.TrimSpace(expression) == "" {
+		return 0, ErrExpressionEmpty
+	}
+	if len(variables) > MaxExpressionVariables {
+		return 0, ErrTooManyVariables
+	}
+	for name := range variables {
+		if !isExpressionName(name) {
+			return 0, fmt.Errorf("invalid variable name %q", name)
+		}
+		if _, ok := expressionConstants[name]; ok {
+			return 0, fmt.Errorf("variable %q shadows a built-in constant", name)
+		}
+		if _, ok := expressionFunctions[name]; ok {
+			return 0, fmt.Errorf("variable %q shadows a built-in function", name)
+		}
+	}
+
+	tokens, err := tokenizeExpression(expression)
+	if err != nil {
+		return 0, err
+	}
+	p := &expressionParser{tokens: tokens}
+	root, err := p.parseExpression()
+	if err != nil {
+		return 0, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return 0, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	result, err := root.eval(s, variables)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrNonFiniteResult
+	}
+	return result, nil
+}
+
+// ParseExpressionVariables parses a comma-separated "name:value" list
+// (e.g. "x:3,y:-1.5") into a variable binding map for Evaluate.
+func ParseExpressionVariables(raw string) (map[string]float64, error) {
+	variables := map[string]float64{}
+	if strings.TrimSpace(raw) == "" {
+		return variables, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("variable %q must be written as name:value", strings.TrimSpace(pair))
+		}
+		name = strings.TrimSpace(name)
+		if !isExpressionName(name) {
+			return nil, fmt.Errorf("invalid variable name %q", name)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("variable %q must have a finite numeric value", name)
+		}
+		variables[name] = v
+	}
+	if len(variables) > MaxExpressionVariables {
+		return nil, ErrTooManyVariables
+	}
+	return variables, nil
+}
+
+// expressionFactorial computes n! for an integral n in [0, 170] using
+// Service.Factorial, returning it as a float64.
+func (s *Service) expressionFactorial(n float64) (float64, error) {
+	if n != math.Trunc(n) || n < 0 || n > maxExpressionFactorial {
+		return 0, fmt.Errorf("factorial operand must be an integer between 0 and %d", maxExpressionFactorial)
+	}
+	f, _ := new(big.Float).SetInt(s.Factorial(int64(n))).Float64()
+	return f, nil
+}
+
+// expressionIntegerPair converts a two-argument call's operands to int64,
+// rejecting fractional or out-of-range values.
+func expressionIntegerPair(name string, args []float64) (int64, int64, error) {
+	const limit = 1 << 53 // largest range where float64 represents every integer
+	for _, v := range args {
+		if v != math.Trunc(v) || math.Abs(v) > limit {
+			return 0, 0, fmt.Errorf("%s requires integer arguments", name)
+		}
+	}
+	return int64(args[0]), int64(args[1]), nil
+}
+
+// isExpressionName reports whether name is a valid identifier:
+// [A-Za-z_][A-Za-z0-9_]*
+func isExpressionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !isExpressionNameStart(c) && !(i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isExpressionNameStart(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// expressionTokenKind classifies a lexical token.
+type expressionTokenKind int
+
+const (
+	tokenEOF expressionTokenKind = iota
+	tokenNumber
+	tokenName
+	tokenOperator
+)
+
+// expressionToken is one lexical token with its byte offset in the source.
+type expressionToken struct {
+	kind  expressionTokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// tokenizeExpression splits an expression into number, name, and
+// single-character operator tokens, terminated by a tokenEOF sentinel.
+func tokenizeExpression(src string) ([]expressionToken, error) {
+	var tokens []expressionToken
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case (c >= '0' && c <= '9') || c == '.':
+			start := i
+			for i < len(src) && ((src[i] >= '0' && src[i] <= '9') || src[i] == '.') {
+				i++
+			}
+			// Optional exponent: e/E, optional sign, at least one digit.
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				j := i + 1
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				if j < len(src) && src[j] >= '0' && src[j] <= '9' {
+					for j < len(src) && src[j] >= '0' && src[j] <= '9' {
+						j++
+					}
+					i = j
+				}
+			}
+			text := src[start:i]
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, start)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenNumber, text: text, value: v, pos: start})
+		case isExpressionNameStart(c):
+			start := i
+			for i < len(src) && (isExpressionNameStart(rune(src[i])) || (src[i] >= '0' && src[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, expressionToken{kind: tokenName, text: src[start:i], pos: start})
+		case strings.ContainsRune("+-*/%^!(),", c):
+			tokens = append(tokens, expressionToken{kind: tokenOperator, text: string(c), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", src[i], i)
+		}
+	}
+	return append(tokens, expressionToken{kind: tokenEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+// expressionParser is a recursive-descent parser over the token stream.
+// Grammar, lowest to highest precedence:
+//
+//	expression := term (("+" | "-") term)*
+//	term       := unary (("*" | "/" | "%") unary)*
+//	unary      := ("+" | "-") unary | power
+//	power      := postfix ("^" unary)?
+//	postfix    := primary "!"*
+//	primary    := number | name | name "(" arguments ")" | "(" expression ")"
+type expressionParser struct {
+	tokens []expressionToken
+	pos    int
+	depth  int
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() expressionToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// acceptOperator consumes the next token if it is one of the given
+// single-character operators.
+func (p *expressionParser) acceptOperator(ops string) (string, bool) {
+	tok := p.peek()
+	if tok.kind == tokenOperator && strings.Contains(ops, tok.text) {
+		p.pos++
+		return tok.text, true
+	}
+	return "", false
+}
+
+func (p *expressionParser) expectOperator(op string) error {
+	if _, ok := p.acceptOperator(op); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected %q but found %q at position %d", op, tok.text, tok.pos)
+	}
+	return nil
+}
+
+// enter tracks recursion depth so deeply nested input is rejected before it
+// can exhaust the stack.
+func (p *expressionParser) enter() error {
+	p.depth++
+	if p.depth > MaxExpressionDepth {
+		return ErrExpressionTooDeep
+	}
+	return nil
+}
+
+func (p *expressionParser) leave() {
+	p.depth--
+}
+
+func (p *expressionParser) parseExpression() (expressionNode, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseTerm() (expressionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*/%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseUnary() (expressionNode, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if op, ok := p.acceptOperator("+-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" {
+			return negateNode{operand: operand}, nil
+		}
+		return operand, nil
+	}
+	return p.parsePower()
+}
+
+func (p *expressionParser) parsePower() (expressionNode, error) {
+	base, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.acceptOperator("^"); !ok {
+		return base, nil
+	}
+	// The exponent is parsed as a unary so "2^-1" works and "2^3^2" groups
+	// right-to-left as 2^(3^2).
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: "^", left: base, right: exponent}, nil
+}
+
+func (p *expressionParser) parsePostfix() (expressionNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("!"); !ok {
+			return node, nil
+		}
+		node = callNode{name: "factorial", args: []expressionNode{node}}
+	}
+}
+
+func (p *expressionParser) parsePrimary() (expressionNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		return numberNode(tok.value), nil
+	case tokenName:
+		if _, ok := p.acceptOperator("("); !ok {
+			if _, isFunc := expressionFunctions[tok.text]; isFunc {
+				return nil, fmt.Errorf("function %q at position %d must be called with parentheses", tok.text, tok.pos)
+			}
+			return nameNode(tok.text), nil
+		}
+		fn, ok := expressionFunctions[tok.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q at position %d", tok.text, tok.pos)
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		if fn.arity >= 0 && len(args) != fn.arity {
+			return nil, fmt.Errorf("function %q expects %d argument(s), got %d", tok.text, fn.arity, len(args))
+		}
+		if fn.arity < 0 && len(args) == 0 {
+			return nil, fmt.Errorf("function %q expects at least one argument", tok.text)
+		}
+		return callNode{name: tok.text, args: args}, nil
+	case tokenOperator:
+		if tok.text == "(" {
+			inner, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// parseArguments parses a comma-separated argument list after the opening
+// parenthesis of a function call, consuming the closing parenthesis.
+func (p *expressionParser) parseArguments() ([]expressionNode, error) {
+	var args []expressionNode
+	if _, ok := p.acceptOperator(")"); ok {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if _, ok := p.acceptOperator(","); ok {
+			continue
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+}
+
+// expressionNode is one node of a parsed expression tree.
+type expressionNode interface {
+	eval(s *Service, variables map[string]float64) (float64, error)
+}
+
+type numberNode float64
+
+func (n numberNode) eval(*Service, map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+// nameNode is a reference to a built-in constant or a bound variable.
+type nameNode string
+
+func (n nameNode) eval(_ *Service, variables map[string]float64) (float64, error) {
+	if v, ok := expressionConstants[string(n)]; ok {
+		return v, nil
+	}
+	if v, ok := variables[string(n)]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown variable %q", string(n))
+}
+
+type negateNode struct {
+	operand expressionNode
+}
+
+func (n negateNode) eval(s *Service, variables map[string]float64) (float64, error) {
+	v, err := n.operand.eval(s, variables)
+	return -v, err
+}
+
+type binaryNode struct {
+	op          string
+	left, right expressionNode
+}
+
+func (n binaryNode) eval(s *Service, variables map[string]float64) (float64, error) {
+	a, err := n.left.eval(s, variables)
+	if err != nil {
+		return 0, err
+	}
+	b, err := n.right.eval(s, variables)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "+":
+		return s.Add(a, b), nil
+	case "-":
+		return s.Subtract(a, b), nil
+	case "*":
+		return s.Multiply(a, b), nil
+	case "/":
+		return s.Divide(a, b)
+	case "%":
+		if b == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return math.Mod(a, b), nil
+	case "^":
+		return s.Power(a, b), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", n.op)
+}
+
+type callNode struct {
+	name string
+	args []expressionNode
+}
+
+func (n callNode) eval(s *Service, variables map[string]float64) (float64, error) {
+	values := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(s, variables)
+		if err != nil {
+			return 0, err
+		}
+		values[i] = v
+	}
+	return expressionFunctions[n.name].call(s, values)
+}
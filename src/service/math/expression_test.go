@@ -0,0 +1,109 @@
+package math
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Evaluate must honor operator precedence, associativity, unary minus,
+// constants, functions, postfix factorial, and bound variables.
+func TestEvaluate(t *testing.T) {
+	s := New()
+
+	tests := []struct {
+		name string
+		expr string
+		vars map[string]float64
+		want float64
+	}{
+		{"precedence", "2 + 3 * 4", nil, 14},
+		{"parentheses", "(2 + 3) * 4", nil, 20},
+		{"power is right-associative", "2^3^2", nil, 512},
+		{"unary minus binds looser than power", "-2^2", nil, -4},
+		{"negative exponent", "2^-1", nil, 0.5},
+		{"double unary", "--3", nil, 3},
+		{"modulo", "10 % 4", nil, 2},
+		{"scientific literal", "1.5e3 / 3", nil, 500},
+		{"constants", "2 * pi / tau", nil, 1},
+		{"request example", "(2^10 + sqrt(81)) * sin(pi/4)", nil, 1033 * math.Sin(math.Pi/4)},
+		{"postfix factorial", "5!", nil, 120},
+		{"factorial function", "factorial(6)", nil, 720},
+		{"gcd and lcm", "gcd(48, 18) + lcm(4, 6)", nil, 18},
+		{"variadic", "max(1, 7, 3) - min(4, 2)", nil, 5},
+		{"log base", "log10(1000) + log2(8)", nil, 6},
+		{"variables", "x^2 + y", map[string]float64{"x": 3, "y": 4}, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Evaluate(tt.expr, tt.vars)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+// Evaluate must reject malformed input, unknown names, non-finite results,
+// and input that exceeds the length/depth limits.
+func TestEvaluateErrors(t *testing.T) {
+	s := New()
+
+	tests := []struct {
+		name    string
+		expr    string
+		vars    map[string]float64
+		wantErr error
+	}{
+		{"empty", "   ", nil, ErrExpressionEmpty},
+		{"division by zero", "1 / (2 - 2)", nil, ErrDivisionByZero},
+		{"modulo by zero", "5 % 0", nil, ErrDivisionByZero},
+		{"negative sqrt", "sqrt(-1)", nil, ErrNegativeSquareRoot},
+		{"non-finite", "log(0)", nil, ErrNonFiniteResult},
+		{"too long", strings.Repeat("1+", MaxExpressionLength) + "1", nil, ErrExpressionTooLong},
+		{"too deep", strings.Repeat("(", MaxExpressionDepth) + "1" + strings.Repeat(")", MaxExpressionDepth), nil, ErrExpressionTooDeep},
+		{"unary chain too deep", strings.Repeat("-", MaxExpressionDepth+1) + "1", nil, ErrExpressionTooDeep},
+		{"unbalanced", "(1 + 2", nil, nil},
+		{"trailing operator", "1 +", nil, nil},
+		{"unknown variable", "x + 1", nil, nil},
+		{"unknown function", "eval(1)", nil, nil},
+		{"wrong arity", "sin(1, 2)", nil, nil},
+		{"function without call", "sin + 1", nil, nil},
+		{"invalid character", "1 & 2", nil, nil},
+		{"fractional factorial", "2.5!", nil, nil},
+		{"factorial overflow", "171!", nil, nil},
+		{"fractional gcd", "gcd(1.5, 2)", nil, nil},
+		{"shadowed constant", "pi", map[string]float64{"pi": 3}, nil},
+		{"shadowed function", "sin", map[string]float64{"sin": 3}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Evaluate(tt.expr, tt.vars)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ParseExpressionVariables accepts "name:value" pairs and rejects malformed
+// names, values, and pairs.
+func TestParseExpressionVariables(t *testing.T) {
+	got, err := ParseExpressionVariables(" x:3, y:-1.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"x": 3, "y": -1.5}, got)
+
+	got, err = ParseExpressionVariables("")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	for _, raw := range []string{"x", "1x:2", "x:abc", "x:Inf"} {
+		_, err := ParseExpressionVariables(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -215,6 +215,12 @@ func (s *Service) IsPrime(n int64) bool {
 	if n%2 == 0 {
 		return false
 	}
+	// Trial division is cheap up to 2^32 (at most 2^15 odd divisors);
+	// beyond that, math/big's Miller-Rabin + Baillie-PSW test is exact for
+	// every int64 and avoids billions of divisions.
+	if n > 1<<32 {
+		return big.NewInt(n).ProbablyPrime(0)
+	}
 	sqrt := int64(math.Sqrt(float64(n)))
 	for i := int64(3); i <= sqrt; i += 2 {
 		if n%i == 0 {
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net"
@@ -294,6 +295,8 @@ const sslDialTimeout = 8 * time.Second
 // SSLInfo connects to host:443 (or host:port) and reads the peer's TLS
 // certificate. No data beyond the TLS handshake is sent. The target is
 // validated (loopback/link-local/private blocked) before any connection.
+// "chain" holds every certificate the peer presented as PEM, leaf first,
+// in the shape crypto.VerifyCertificateChain accepts.
 func (s *Service) SSLInfo(domain string) (map[string]interface{}, error) {
 	domain = strings.TrimSpace(domain)
 	if domain == "" {
@@ -325,6 +328,11 @@ func (s *Service) SSLInfo(domain string) (map[string]interface{}, error) {
 	}
 	cert := certs[0]
 
+	chain := make([]string, len(certs))
+	for i, c := range certs {
+		chain[i] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+	}
+
 	return map[string]interface{}{
 		"subject":             cert.Subject.String(),
 		"issuer":              cert.Issuer.String(),
@@ -335,6 +343,7 @@ func (s *Service) SSLInfo(domain string) (map[string]interface{}, error) {
 		"version":             cert.Version,
 		"signature_algorithm": cert.SignatureAlgorithm.String(),
 		"is_expired":          time.Now().After(cert.NotAfter),
+		"chain":               chain,
 	}, nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/apimgr/api/src/service/datetime"
 	"golang.org/x/net/html"
 	"gopkg.in/yaml.v3"
 )
@@ -141,6 +142,23 @@ func (s *Service) ParseQueryString(query string) (map[string][]string, error) {
 
 // Date/Time parsing
 func (s *Service) ParseDateTime(dateStr string) (time.Time, error) {
+	return s.ParseDateTimeAt(dateStr, datetime.ParseOptions{})
+}
+
+// ParseDateTimeAt parses dateStr in one of the common layouts, reading
+// layouts without a zone in opts.Timezone, and otherwise as a
+// natural-language expression or ISO 8601 duration or interval resolved
+// against opts.Reference (see datetime.ParseNatural).
+func (s *Service) ParseDateTimeAt(dateStr string, opts datetime.ParseOptions) (time.Time, error) {
+	loc := time.UTC
+	if opts.Timezone != "" {
+		l, err := time.LoadLocation(opts.Timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timezone: %s", opts.Timezone)
+		}
+		loc = l
+	}
+
 	// Try common formats
 	formats := []string{
 		time.RFC3339,
@@ -154,12 +172,16 @@ func (s *Service) ParseDateTime(dateStr string) (time.Time, error) {
 	}
 
 	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
+		if t, err := time.ParseInLocation(format, dateStr, loc); err == nil {
 			return t, nil
 		}
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
+	res, err := datetime.ParseNatural(dateStr, opts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse date: %s (%v)", dateStr, err)
+	}
+	return res.Time, nil
 }
 
 // Number parsing
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/apimgr/api/src/service/datetime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -119,6 +120,29 @@ func TestParseDateTime(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// ParseDateTimeAt reads zoneless layouts in the requested timezone and
+// falls back to natural-language and ISO 8601 expressions relative to
+// the reference time.
+func TestParseDateTimeAt(t *testing.T) {
+	s := New()
+	opts := datetime.ParseOptions{Reference: time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC), Timezone: "Europe/Paris"}
+
+	got, err := s.ParseDateTimeAt("2024-03-15 10:30:00", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-15T10:30:00+01:00", got.Format(time.RFC3339))
+
+	got, err = s.ParseDateTimeAt("next tuesday 3pm", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-20T15:00:00+02:00", got.Format(time.RFC3339))
+
+	got, err = s.ParseDateTimeAt("PT90M", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16T18:00:00+02:00", got.Format(time.RFC3339))
+
+	_, err = s.ParseDateTimeAt("2024-03-15", datetime.ParseOptions{Timezone: "Nowhere/Special"})
+	assert.Error(t, err)
+}
+
 // ParseInt/ParseFloat cover whitespace trimming, negatives, and
 // non-numeric input.
 func TestParseIntAndFloat(t *testing.T) {
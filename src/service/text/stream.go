@@ -0,0 +1,180 @@
+package text
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// The functions in this file are the io.Reader/io.Writer counterparts of
+// Hash, HashAll, the Encode/Decode pairs and Compress/Decompress. They hold
+// only a fixed-size buffer at a time, so input of any size can be piped
+// through them.
+
+// HashReader hashes everything read from r with algorithm and returns the
+// hex digest and the number of bytes hashed.
+func HashReader(algorithm string, r io.Reader) (string, int64, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", n, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// HashAllReader is HashAll for a stream: r is read once and fed to every
+// algorithm in HashAlgorithms.
+func HashAllReader(r io.Reader) (map[string]string, int64, error) {
+	writers := make([]io.Writer, len(HashAlgorithms))
+	hashes := make(map[string]hash.Hash, len(HashAlgorithms))
+	for i, alg := range HashAlgorithms {
+		h, _ := newHash(alg)
+		writers[i] = h
+		hashes[alg] = h
+	}
+	n, err := io.Copy(io.MultiWriter(writers...), r)
+	if err != nil {
+		return nil, n, err
+	}
+	digests := make(map[string]string, len(hashes))
+	for alg, h := range hashes {
+		digests[alg] = hex.EncodeToString(h.Sum(nil))
+	}
+	return digests, n, nil
+}
+
+// NewEncoder returns a writer that encodes what is written to it with
+// encoding (base64, base64url, base32, hex/base16 or url) and writes the
+// result to w. Close flushes any partial block; it does not close w.
+func NewEncoder(encoding string, w io.Writer) (io.WriteCloser, error) {
+	switch strings.ToLower(encoding) {
+	case "base64":
+		return base64.NewEncoder(base64.StdEncoding, w), nil
+	case "base64url":
+		return base64.NewEncoder(base64.URLEncoding, w), nil
+	case "base32":
+		return base32.NewEncoder(base32.StdEncoding, w), nil
+	case "hex", "base16":
+		return nopWriteCloser{hex.NewEncoder(w)}, nil
+	case "url":
+		return nopWriteCloser{urlEncoder{w}}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+}
+
+// NewDecoder returns a reader that decodes the encoding (as accepted by
+// NewEncoder) read from r. Line breaks in base64 and base32 input are
+// ignored.
+func NewDecoder(encoding string, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(encoding) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r), nil
+	case "base64url":
+		return base64.NewDecoder(base64.URLEncoding, r), nil
+	case "base32":
+		return base32.NewDecoder(base32.StdEncoding, r), nil
+	case "hex", "base16":
+		return hex.NewDecoder(r), nil
+	case "url":
+		return &urlDecoder{r: bufio.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+}
+
+// NewCompressor returns a writer that compresses what is written to it
+// with algorithm (gzip, zlib or flate/deflate) and writes the result to w.
+// Close must be called to flush the stream; it does not close w.
+func NewCompressor(algorithm string, w io.Writer) (io.WriteCloser, error) {
+	switch strings.ToLower(algorithm) {
+	case "gzip":
+		return gzip.NewWriter(w), nil
+	case "zlib":
+		return zlib.NewWriter(w), nil
+	case "flate", "deflate":
+		return flate.NewWriter(w, flate.DefaultCompression)
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+}
+
+// NewDecompressor returns a reader of the data compressed with algorithm
+// in r. For gzip and zlib the stream header is read, and checked, right
+// away.
+func NewDecompressor(algorithm string, r io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(algorithm) {
+	case "gzip":
+		return gzip.NewReader(r)
+	case "zlib":
+		return zlib.NewReader(r)
+	case "flate", "deflate":
+		return flate.NewReader(r), nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// urlEncoder query-escapes what is written to it. QueryEscape maps each
+// byte on its own, so chunks can be escaped independently.
+type urlEncoder struct {
+	w io.Writer
+}
+
+func (e urlEncoder) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(e.w, url.QueryEscape(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// urlDecoder reverses urlEncoder, one byte or %XX escape at a time, the
+// way url.QueryUnescape does.
+type urlDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *urlDecoder) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if n > 0 && d.r.Buffered() == 0 {
+			// Return what is decoded rather than block for more input.
+			return n, nil
+		}
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		switch c {
+		case '+':
+			c = ' '
+		case '%':
+			var esc [2]byte
+			if _, err := io.ReadFull(d.r, esc[:]); err != nil {
+				return n, fmt.Errorf("invalid URL escape %q", "%"+string(esc[:]))
+			}
+			b, err := hex.DecodeString(string(esc[:]))
+			if err != nil {
+				return n, fmt.Errorf("invalid URL escape %q", "%"+string(esc[:]))
+			}
+			c = b[0]
+		}
+		p[n] = c
+		n++
+	}
+	return n, nil
+}
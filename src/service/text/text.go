@@ -2,13 +2,11 @@ package text
 
 import (
 	"bytes"
-	"compress/flate"
-	"compress/gzip"
-	"compress/zlib"
 	"crypto/md5"
 	crand "crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha3"
 	"crypto/sha512"
 	"encoding/base32"
 	"encoding/base64"
@@ -16,6 +14,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/crc64"
 	"html"
 	"io"
 	"math/big"
@@ -30,9 +31,14 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/google/uuid"
+	"github.com/twmb/murmur3"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"lukechampine.com/blake3"
 )
 
 // UUID generates a UUID of the specified version
@@ -81,33 +87,80 @@ func UUIDs(version, count int) ([]string, error) {
 
 // Hash computes a hash of the input using the specified algorithm
 func Hash(algorithm, input string) (string, error) {
-	var h hash.Hash
-
-	switch strings.ToLower(algorithm) {
-	case "md5":
-		h = md5.New()
-	case "sha1":
-		h = sha1.New()
-	case "sha256":
-		h = sha256.New()
-	case "sha384":
-		h = sha512.New384()
-	case "sha512":
-		h = sha512.New()
-	default:
-		return "", fmt.Errorf("unsupported algorithm: %s", algorithm)
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
 	}
 
 	h.Write([]byte(input))
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
+// newHash returns a fresh hash.Hash for algorithm or one of its aliases.
+func newHash(algorithm string) (hash.Hash, error) {
+	name := strings.ToLower(algorithm)
+	if canonical, ok := hashAliases[name]; ok {
+		name = canonical
+	}
+	if ctor, ok := hashConstructors[name]; ok {
+		return ctor(), nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+}
+
+// hashConstructors builds each algorithm in HashAlgorithms. The CRC,
+// Adler-32, xxHash and Murmur3 checksums are not cryptographic; they are
+// here so checksums produced by other systems can be matched.
+var hashConstructors = map[string]func() hash.Hash{
+	"md5":         md5.New,
+	"sha1":        sha1.New,
+	"sha256":      sha256.New,
+	"sha384":      sha512.New384,
+	"sha512":      sha512.New,
+	"sha3-224":    func() hash.Hash { return sha3.New224() },
+	"sha3-256":    func() hash.Hash { return sha3.New256() },
+	"sha3-384":    func() hash.Hash { return sha3.New384() },
+	"sha3-512":    func() hash.Hash { return sha3.New512() },
+	"blake2b-256": func() hash.Hash { h, _ := blake2b.New256(nil); return h },
+	"blake2b-512": func() hash.Hash { h, _ := blake2b.New512(nil); return h },
+	"blake2s-256": func() hash.Hash { h, _ := blake2s.New256(nil); return h },
+	"blake3":      func() hash.Hash { return blake3.New(32, nil) },
+	"crc32":       func() hash.Hash { return crc32.NewIEEE() },
+	"crc32c":      func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) },
+	"crc64":       func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) },
+	"crc64-iso":   func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ISO)) },
+	"adler32":     func() hash.Hash { return adler32.New() },
+	"xxhash64":    func() hash.Hash { return xxhash.New() },
+	"murmur3-32":  func() hash.Hash { return murmur3.New32() },
+	"murmur3-128": func() hash.Hash { return murmur3.New128() },
+}
+
+// hashAliases maps alternative algorithm names to their HashAlgorithms
+// entry.
+var hashAliases = map[string]string{
+	"blake2b":    "blake2b-512",
+	"blake2s":    "blake2s-256",
+	"crc64-ecma": "crc64",
+	"xxhash":     "xxhash64",
+	"xxh64":      "xxhash64",
+	"murmur3":    "murmur3-32",
+}
+
+// HashAlgorithms lists the algorithms Hash accepts, the ones HashAll
+// reports. Hash also takes the names in hashAliases.
+var HashAlgorithms = []string{
+	"md5", "sha1", "sha256", "sha384", "sha512",
+	"sha3-224", "sha3-256", "sha3-384", "sha3-512",
+	"blake2b-256", "blake2b-512", "blake2s-256", "blake3",
+	"crc32", "crc32c", "crc64", "crc64-iso", "adler32",
+	"xxhash64", "murmur3-32", "murmur3-128",
+}
+
 // HashAll returns all common hashes
 func HashAll(input string) map[string]string {
 	hashes := make(map[string]string)
 
-	algorithms := []string{"md5", "sha1", "sha256", "sha384", "sha512"}
-	for _, alg := range algorithms {
+	for _, alg := range HashAlgorithms {
 		if h, err := Hash(alg, input); err == nil {
 			hashes[alg] = h
 		}
@@ -941,21 +994,9 @@ func Metaphone(input string) string {
 // flate) and returns the result base64-encoded.
 func Compress(data, algorithm string) (string, error) {
 	var buf bytes.Buffer
-	var w io.WriteCloser
-	var err error
-
-	switch strings.ToLower(algorithm) {
-	case "gzip":
-		w = gzip.NewWriter(&buf)
-	case "zlib":
-		w = zlib.NewWriter(&buf)
-	case "flate", "deflate":
-		w, err = flate.NewWriter(&buf, flate.DefaultCompression)
-		if err != nil {
-			return "", err
-		}
-	default:
-		return "", fmt.Errorf("unsupported algorithm: %s", algorithm)
+	w, err := NewCompressor(algorithm, &buf)
+	if err != nil {
+		return "", err
 	}
 
 	if _, err := w.Write([]byte(data)); err != nil {
@@ -975,22 +1016,9 @@ func Decompress(data, algorithm string) (string, error) {
 		return "", err
 	}
 
-	var r io.ReadCloser
-	switch strings.ToLower(algorithm) {
-	case "gzip":
-		r, err = gzip.NewReader(bytes.NewReader(raw))
-		if err != nil {
-			return "", err
-		}
-	case "zlib":
-		r, err = zlib.NewReader(bytes.NewReader(raw))
-		if err != nil {
-			return "", err
-		}
-	case "flate", "deflate":
-		r = flate.NewReader(bytes.NewReader(raw))
-	default:
-		return "", fmt.Errorf("unsupported algorithm: %s", algorithm)
+	r, err := NewDecompressor(algorithm, bytes.NewReader(raw))
+	if err != nil {
+		return "", err
 	}
 	defer r.Close()
 
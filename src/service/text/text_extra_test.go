@@ -90,15 +90,56 @@ func TestHash(t *testing.T) {
 	}
 }
 
+func TestHashFamilies(t *testing.T) {
+	cases := []struct {
+		algo  string
+		input string
+		want  string
+	}{
+		{"sha3-224", "hello world", "dfb7f18c77e928bb56faeb2da27291bd790bc1045cde45f3210bb6c5"},
+		{"sha3-256", "hello world", "644bcc7e564373040999aac89e7622f3ca71fba1d972fd94a31c3bfbf24e3938"},
+		{"sha3-384", "hello world", "83bff28dde1b1bf5810071c6643c08e5b05bdb836effd70b403ea8ea0a634dc4997eb1053aa3593f590f9c63630dd90b"},
+		{"sha3-512", "hello world", "840006653e9ac9e95117a15c915caab81662918e925de9e004f774ff82d7079a40d4d27b1b372657c61d46d470304c88c788b3a4527ad074d1dccbee5dbaa99a"},
+		{"blake2b-256", "hello world", "256c83b297114d201b30179f3f0ef0cace9783622da5974326b436178aeef610"},
+		{"blake2b-512", "hello world", "021ced8799296ceca557832ab941a50b4a11f83478cf141f51f933f653ab9fbcc05a037cddbed06e309bf334942c4e58cdf1a46e237911ccd7fcf9787cbc7fd0"},
+		{"blake2b", "hello world", "021ced8799296ceca557832ab941a50b4a11f83478cf141f51f933f653ab9fbcc05a037cddbed06e309bf334942c4e58cdf1a46e237911ccd7fcf9787cbc7fd0"},
+		{"blake2s-256", "hello world", "9aec6806794561107e594b1f6a8a6b0c92a0cba9acf5e5e93cca06f781813b0b"},
+		{"blake3", "", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{"crc32", "hello world", "0d4a1185"},
+		{"crc32c", "123456789", "e3069283"},
+		{"crc64", "123456789", "995dc9bbdf1939fa"},
+		{"crc64-iso", "123456789", "b90956c775a41001"},
+		{"adler32", "hello world", "1a0b045d"},
+		{"xxhash64", "", "ef46db3751d8e999"},
+		{"XXH64", "", "ef46db3751d8e999"},
+		{"murmur3-32", "hello", "248bfa47"},
+		{"murmur3", "hello", "248bfa47"},
+	}
+	for _, c := range cases {
+		got, err := Hash(c.algo, c.input)
+		if err != nil {
+			t.Fatalf("Hash(%s) error: %v", c.algo, err)
+		}
+		if got != c.want {
+			t.Errorf("Hash(%s, %q) = %q, want %q", c.algo, c.input, got, c.want)
+		}
+	}
+
+	got, err := Hash("murmur3-128", "hello")
+	if err != nil || len(got) != 32 {
+		t.Errorf("Hash(murmur3-128) = %q, %v; want 32 hex chars", got, err)
+	}
+}
+
 func TestHashAll(t *testing.T) {
 	hashes := HashAll("hello")
-	for _, alg := range []string{"md5", "sha1", "sha256", "sha384", "sha512"} {
+	for _, alg := range []string{"md5", "sha1", "sha256", "sha384", "sha512", "sha3-256", "blake3", "crc32", "xxhash64", "murmur3-128"} {
 		if hashes[alg] == "" {
 			t.Errorf("HashAll()[%s] is empty", alg)
 		}
 	}
-	if len(hashes) != 5 {
-		t.Errorf("HashAll() returned %d entries, want 5", len(hashes))
+	if len(hashes) != len(HashAlgorithms) {
+		t.Errorf("HashAll() returned %d entries, want %d", len(hashes), len(HashAlgorithms))
 	}
 }
 
@@ -1,6 +1,8 @@
 package text
 
 import (
+	"bytes"
+	"io"
 	"strings"
 	"testing"
 )
@@ -313,3 +315,109 @@ func TestThemedLorem(t *testing.T) {
 		}
 	}
 }
+
+func TestHashReader(t *testing.T) {
+	input := strings.Repeat("streamed input ", 10000)
+	for _, algo := range HashAlgorithms {
+		want, _ := Hash(algo, input)
+		got, n, err := HashReader(algo, strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("HashReader(%s) error: %v", algo, err)
+		}
+		if got != want || n != int64(len(input)) {
+			t.Errorf("HashReader(%s) = %q, %d; want %q, %d", algo, got, n, want, len(input))
+		}
+	}
+	if _, _, err := HashReader("crc0", strings.NewReader("x")); err == nil {
+		t.Error("HashReader with an unknown algorithm should fail")
+	}
+
+	all, n, err := HashAllReader(strings.NewReader("abc"))
+	if err != nil || n != 3 {
+		t.Fatalf("HashAllReader() = %d, %v", n, err)
+	}
+	for alg, digest := range HashAll("abc") {
+		if all[alg] != digest {
+			t.Errorf("HashAllReader()[%s] = %q, want %q", alg, all[alg], digest)
+		}
+	}
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	input := "a+b c/d?e=f&g\x00\xff " + strings.Repeat("0123456789", 1000)
+	want := map[string]string{
+		"base64":    Base64Encode(input),
+		"base64url": Base64URLEncode(input),
+		"base32":    Base32Encode(input),
+		"hex":       HexEncode(input),
+		"base16":    HexEncode(input),
+		"url":       URLEncode(input),
+	}
+	for encoding, encoded := range want {
+		var buf strings.Builder
+		w, err := NewEncoder(encoding, &buf)
+		if err != nil {
+			t.Fatalf("NewEncoder(%s) error: %v", encoding, err)
+		}
+		// Write in odd-sized chunks so block boundaries fall mid-write.
+		for i := 0; i < len(input); i += 7 {
+			w.Write([]byte(input[i:min(i+7, len(input))]))
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s encoder Close() error: %v", encoding, err)
+		}
+		if buf.String() != encoded {
+			t.Errorf("%s encoder output differs from the string encoder", encoding)
+		}
+
+		r, err := NewDecoder(encoding, strings.NewReader(encoded))
+		if err != nil {
+			t.Fatalf("NewDecoder(%s) error: %v", encoding, err)
+		}
+		decoded, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s decoder error: %v", encoding, err)
+		}
+		if string(decoded) != input {
+			t.Errorf("%s decoder did not round-trip", encoding)
+		}
+	}
+
+	r, _ := NewDecoder("url", strings.NewReader("ok%2"))
+	if _, err := io.ReadAll(r); err == nil {
+		t.Error("url decoder should reject a truncated escape")
+	}
+	if _, err := NewEncoder("base58", io.Discard); err == nil {
+		t.Error("NewEncoder with an unknown encoding should fail")
+	}
+}
+
+func TestCompressorDecompressor(t *testing.T) {
+	input := strings.Repeat("the quick brown fox jumps over the lazy dog ", 5000)
+	for _, algo := range []string{"gzip", "zlib", "flate", "deflate"} {
+		var buf bytes.Buffer
+		w, err := NewCompressor(algo, &buf)
+		if err != nil {
+			t.Fatalf("NewCompressor(%s) error: %v", algo, err)
+		}
+		io.Copy(w, strings.NewReader(input))
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s compressor Close() error: %v", algo, err)
+		}
+		if buf.Len() >= len(input) {
+			t.Errorf("%s output is %d bytes, not smaller than the %d byte input", algo, buf.Len(), len(input))
+		}
+
+		r, err := NewDecompressor(algo, &buf)
+		if err != nil {
+			t.Fatalf("NewDecompressor(%s) error: %v", algo, err)
+		}
+		out, err := io.ReadAll(r)
+		if err != nil || string(out) != input {
+			t.Errorf("%s did not round-trip: %v", algo, err)
+		}
+	}
+	if _, err := NewDecompressor("gzip", strings.NewReader("not gzip")); err == nil {
+		t.Error("NewDecompressor should reject a bad gzip header")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/apimgr/api/src/service/crypto"
 )
 
 // Service provides validation utilities
@@ -260,50 +262,18 @@ func (s *Service) IsVAT(vat string) bool {
 	return bodyFormat.MatchString(body)
 }
 
-// Password strength
-func (s *Service) PasswordStrength(password string) string {
-	if len(password) < 8 {
+// PasswordStrength rates a password weak, medium or strong from
+// crypto.EstimatePassword's guess estimate: weak below 10^6 guesses,
+// medium below 10^8, strong from there. userInputs are words tied to the
+// account, such as its name or email, which make a password weaker.
+func (s *Service) PasswordStrength(password string, userInputs ...string) string {
+	switch score := crypto.EstimatePassword(password, userInputs...).Score; {
+	case score <= 2:
 		return "weak"
-	}
-
-	hasUpper := false
-	hasLower := false
-	hasDigit := false
-	hasSpecial := false
-
-	for _, r := range password {
-		switch {
-		case unicode.IsUpper(r):
-			hasUpper = true
-		case unicode.IsLower(r):
-			hasLower = true
-		case unicode.IsDigit(r):
-			hasDigit = true
-		case unicode.IsPunct(r) || unicode.IsSymbol(r):
-			hasSpecial = true
-		}
-	}
-
-	strength := 0
-	if hasUpper {
-		strength++
-	}
-	if hasLower {
-		strength++
-	}
-	if hasDigit {
-		strength++
-	}
-	if hasSpecial {
-		strength++
-	}
-
-	if strength >= 4 && len(password) >= 12 {
-		return "strong"
-	} else if strength >= 3 {
+	case score == 3:
 		return "medium"
 	}
-	return "weak"
+	return "strong"
 }
 
 // Length validations
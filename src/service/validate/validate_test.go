@@ -168,15 +168,21 @@ func TestPasswordStrength(t *testing.T) {
 		want string
 	}{
 		{"short", "weak"},
-		{"alllowercase", "weak"},
-		// Upper+lower+digit = 3 categories -> medium.
-		{"Password1", "medium"},
-		// Upper+lower+digit+special and length >= 12 -> strong.
+		// Character classes don't matter: a capitalised common password
+		// with a digit is still weak.
+		{"Password1", "weak"},
+		{"qwertyuiop", "weak"},
+		{"jessica1990", "weak"},
+		// Three dictionary words take over 10^8 guesses.
+		{"alllowercase", "strong"},
 		{"Password123!@#", "strong"},
+		{"correcthorsebatterystaple", "strong"},
 	}
 	for _, c := range cases {
 		assert.Equal(t, c.want, s.PasswordStrength(c.in), "PasswordStrength(%q)", c.in)
 	}
+	assert.Equal(t, "strong", s.PasswordStrength("Zb8ronwq4kX"))
+	assert.Equal(t, "weak", s.PasswordStrength("Zb8ronwq4kX", "zb8ronwq4kx"), "user inputs count as dictionary words")
 }
 
 func TestLengthValidations(t *testing.T) {
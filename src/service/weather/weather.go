@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,6 +19,14 @@ func New() *Service {
 	return &Service{}
 }
 
+// CacheKey builds the response-cache key for a lookup kind (current,
+// forecast, ...) at location, folding case and surrounding whitespace so
+// "Paris" and " paris" share an entry across the REST and GraphQL APIs.
+func CacheKey(kind, location string, extra ...string) string {
+	parts := append([]string{kind, strings.ToLower(strings.TrimSpace(location))}, extra...)
+	return strings.Join(parts, ":")
+}
+
 // Weather data structures
 type CurrentWeather struct {
 	Temperature   float64   `json:"temperature"`
@@ -549,3 +549,9 @@ func TestWeatherCodeInfo(t *testing.T) {
 		assert.Equal(t, tt.wantIcon, icon, "code %d icon", tt.code)
 	}
 }
+
+// CacheKey must fold case and whitespace in the location.
+func TestCacheKey(t *testing.T) {
+	assert.Equal(t, CacheKey("current", "paris"), CacheKey("current", "  Paris "))
+	assert.Equal(t, "forecast:paris:5", CacheKey("forecast", "Paris", "5"))
+}
@@ -0,0 +1,448 @@
+package swagger
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ResponseFormat describes how a handler writes its success response.
+type ResponseFormat int
+
+// Response formats
+const (
+	// FormatEnvelope is the PART 14 {"ok":true,"data":...} envelope.
+	FormatEnvelope ResponseFormat = iota
+	// FormatJSON is a bare JSON document (the older text/crypto/datetime
+	// handlers' jsonResponse).
+	FormatJSON
+	// FormatText is a text/plain body, as served by the .txt variants.
+	FormatText
+	// FormatRaw is a body in HandlerDoc.ContentType, such as an image.
+	FormatRaw
+)
+
+// HandlerDoc is the per-handler metadata the spec is built from. Types are
+// carried as typed nil pointers (e.g. (*fooParams)(nil)) and turned into
+// schemas by reflection, so the spec follows the structs the handlers
+// actually decode and validate.
+type HandlerDoc struct {
+	Summary     string
+	Description string
+
+	// Params are the structs passed to validateStruct. Fields matching a
+	// path or query parameter describe it.
+	Params []interface{}
+	// QueryParams are the query parameters the handler reads. When none
+	// are known, param struct fields stand in for them on GET routes.
+	QueryParams []string
+	// Body is the type the handler decodes from a JSON request body.
+	Body interface{}
+	// RawBody marks a handler that reads the request body as raw text.
+	RawBody bool
+	// FormFields and FormFiles are the fields of a multipart form body.
+	FormFields []string
+	FormFiles  []string
+
+	Format        ResponseFormat
+	ContentType   string
+	Response      interface{}
+	SuccessStatus int
+	// ErrorStatuses are the HTTP statuses the handler can fail with.
+	ErrorStatuses []int
+	// LegacyErrors marks errors written as {"error":"..."} rather than the
+	// PART 14 error envelope.
+	LegacyErrors bool
+}
+
+// Endpoint is one routed operation: a method, a chi route pattern and the
+// documentation of the handler serving it.
+type Endpoint struct {
+	Method  string
+	Pattern string
+	Tag     string
+	Doc     HandlerDoc
+}
+
+var (
+	endpointsMu sync.RWMutex
+	endpoints   []Endpoint
+)
+
+// SetEndpoints registers the routed endpoints GenerateSpec describes. The
+// server calls it once its router is built, so the spec always matches
+// the routes actually served.
+func SetEndpoints(eps []Endpoint) {
+	sorted := append([]Endpoint(nil), eps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Pattern != sorted[j].Pattern {
+			return sorted[i].Pattern < sorted[j].Pattern
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	endpointsMu.Lock()
+	endpoints = sorted
+	endpointsMu.Unlock()
+}
+
+// registeredEndpoints returns the endpoints last passed to SetEndpoints.
+func registeredEndpoints() []Endpoint {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	return endpoints
+}
+
+// pathParamPattern matches a chi path parameter, with an optional regexp
+// constraint: {name} or {name:[0-9]+}.
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// openAPIPath converts a chi route pattern to an OpenAPI path template and
+// returns its parameter names in order. A trailing catch-all "*" becomes
+// a {path} parameter.
+func openAPIPath(pattern string) (string, []string) {
+	var names []string
+	path := pathParamPattern.ReplaceAllStringFunc(pattern, func(m string) string {
+		name := pathParamPattern.FindStringSubmatch(m)[1]
+		names = append(names, name)
+		return "{" + name + "}"
+	})
+	if strings.HasSuffix(path, "/*") {
+		path = strings.TrimSuffix(path, "*") + "{path}"
+		names = append(names, "path")
+	}
+	return path, names
+}
+
+// operationID derives a unique camelCase operation ID from the method and
+// path, e.g. GET /api/v1/text/hash/{algorithm} -> getTextHashByAlgorithm.
+// Handler names are not used since several routes share one handler.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	path = strings.TrimPrefix(path, "/api/v1")
+	path = strings.TrimPrefix(path, "/api")
+	for _, seg := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '.' || r == '-' || r == '_'
+	}) {
+		if strings.HasPrefix(seg, "{") {
+			b.WriteString("By")
+			seg = strings.Trim(seg, "{}")
+		}
+		b.WriteString(upperFirst(seg))
+	}
+	return b.String()
+}
+
+// upperFirst capitalises the first rune of s.
+func upperFirst(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
+
+// normalizeName folds a field or parameter name for matching: lower case
+// with separators removed, so FromBase matches {from_base}.
+func normalizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// buildPaths turns the registered endpoints into OpenAPI path items.
+func buildPaths(eps []Endpoint) map[string]PathItem {
+	paths := make(map[string]PathItem)
+	seenIDs := make(map[string]int)
+
+	for _, ep := range eps {
+		path, names := openAPIPath(ep.Pattern)
+		op := buildOperation(ep, path, names)
+
+		if n := seenIDs[op.OperationID]; n > 0 {
+			seenIDs[op.OperationID] = n + 1
+			op.OperationID = fmt.Sprintf("%s%d", op.OperationID, n+1)
+		} else {
+			seenIDs[op.OperationID] = 1
+		}
+
+		item := paths[path]
+		switch ep.Method {
+		case http.MethodGet:
+			item.Get = op
+		case http.MethodPost:
+			item.Post = op
+		case http.MethodPut:
+			item.Put = op
+		case http.MethodDelete:
+			item.Delete = op
+		case http.MethodPatch:
+			item.Patch = op
+		case http.MethodOptions:
+			item.Options = op
+		default:
+			continue
+		}
+		paths[path] = item
+	}
+	return paths
+}
+
+// buildOperation describes one endpoint: its parameters from the path
+// and param structs, its request body, and its success and error
+// responses.
+func buildOperation(ep Endpoint, path string, pathNames []string) *Operation {
+	doc := ep.Doc
+	op := &Operation{
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		OperationID: operationID(ep.Method, path),
+		Responses:   make(map[string]Response),
+	}
+	if ep.Tag != "" {
+		op.Tags = []string{ep.Tag}
+	}
+
+	// Path parameters, typed and constrained by any matching param field
+	fields := paramFields(doc.Params)
+	used := make(map[string]bool)
+	for _, name := range pathNames {
+		p := Parameter{Name: name, In: "path", Required: true, Schema: Schema{Type: "string"}}
+		if f, ok := fields[normalizeName(name)]; ok {
+			p.Schema = f.schema
+			p.Description = f.description
+			used[normalizeName(name)] = true
+		}
+		op.Parameters = append(op.Parameters, p)
+	}
+
+	// Query parameters the handler reads, typed by any matching field. A
+	// handler whose reads are unknown gets its remaining param fields as
+	// query parameters on GET (for methods with a body they are derived
+	// from it and covered by its schema).
+	multipart := len(doc.FormFields) > 0 || len(doc.FormFiles) > 0
+	hasBody := doc.Body != nil || doc.RawBody || multipart
+	for _, name := range doc.QueryParams {
+		p := Parameter{Name: name, In: "query", Schema: Schema{Type: "string"}}
+		if f, ok := fields[normalizeName(name)]; ok && !used[f.key] {
+			p.Schema, p.Description, p.Required = f.schema, f.description, f.required
+			used[f.key] = true
+		}
+		op.Parameters = append(op.Parameters, p)
+	}
+	if len(doc.QueryParams) == 0 && (ep.Method == http.MethodGet || !hasBody) {
+		for _, f := range sortedFields(fields) {
+			if used[f.key] || f.name == "body" {
+				continue
+			}
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        f.name,
+				In:          "query",
+				Description: f.description,
+				Required:    f.required,
+				Schema:      f.schema,
+			})
+			used[f.key] = true
+		}
+	}
+
+	// Request body
+	switch bodyField, hasBodyField := fields["body"]; {
+	case doc.Body != nil:
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: SchemaOf(doc.Body)},
+			},
+		}
+	case multipart:
+		form := Schema{Type: "object", Properties: make(map[string]Schema)}
+		for _, name := range doc.FormFiles {
+			form.Properties[name] = Schema{Type: "string", Format: "binary"}
+			form.Required = append(form.Required, name)
+		}
+		for _, name := range doc.FormFields {
+			field := Schema{Type: "string"}
+			if f, ok := fields[normalizeName(name)]; ok && !used[f.key] {
+				field = f.schema
+				if f.required {
+					form.Required = append(form.Required, name)
+				}
+			}
+			form.Properties[name] = field
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"multipart/form-data": {Schema: form}},
+		}
+	case doc.RawBody || (hasBodyField && ep.Method != http.MethodGet):
+		schema := Schema{Type: "string"}
+		if hasBodyField {
+			schema = bodyField.schema
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"text/plain": {Schema: schema},
+			},
+		}
+	}
+
+	// Success response. Pure stubs (only a 501) have none.
+	status := doc.SuccessStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	notImplemented := len(doc.ErrorStatuses) == 1 && doc.ErrorStatuses[0] == http.StatusNotImplemented
+	if !notImplemented {
+		op.Responses[fmt.Sprint(status)] = successResponse(ep, status)
+	}
+
+	// Error responses
+	errorRef := Schema{Ref: "#/components/schemas/ErrorEnvelope"}
+	if doc.LegacyErrors {
+		errorRef = Schema{Ref: "#/components/schemas/Error"}
+	}
+	for _, code := range errorStatuses(doc) {
+		op.Responses[fmt.Sprint(code)] = Response{
+			Description: http.StatusText(code),
+			Content:     map[string]MediaType{"application/json": {Schema: errorRef}},
+		}
+	}
+
+	return op
+}
+
+// successResponse describes the handler's success body in its format.
+func successResponse(ep Endpoint, status int) Response {
+	doc := ep.Doc
+	description := http.StatusText(status)
+
+	if doc.Format == FormatText || strings.HasSuffix(ep.Pattern, ".txt") {
+		return Response{
+			Description: description,
+			Content:     map[string]MediaType{"text/plain": {Schema: Schema{Type: "string"}}},
+		}
+	}
+	if doc.Format == FormatRaw {
+		contentType := doc.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		schema := Schema{Type: "string", Format: "binary"}
+		if strings.HasPrefix(contentType, "text/") {
+			schema = Schema{Type: "string"}
+		}
+		return Response{
+			Description: description,
+			Content:     map[string]MediaType{contentType: {Schema: schema}},
+		}
+	}
+
+	data := Schema{}
+	if doc.Response != nil {
+		data = SchemaOf(doc.Response)
+	}
+	if doc.Format == FormatJSON {
+		if doc.Response == nil {
+			data = Schema{Type: "object"}
+		}
+		return Response{
+			Description: description,
+			Content:     map[string]MediaType{"application/json": {Schema: data}},
+		}
+	}
+
+	return Response{
+		Description: description,
+		Content: map[string]MediaType{
+			"application/json": {Schema: Schema{
+				AllOf: []Schema{
+					{Ref: "#/components/schemas/Envelope"},
+					{Type: "object", Properties: map[string]Schema{"data": data}},
+				},
+			}},
+		},
+	}
+}
+
+// errorStatuses lists the documented error statuses: the handler's own
+// plus the 429 (rate limit) and 500 (recovered panic) every route can
+// return.
+func errorStatuses(doc HandlerDoc) []int {
+	set := map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+	}
+	for _, code := range doc.ErrorStatuses {
+		set[code] = true
+	}
+	codes := make([]int, 0, len(set))
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// paramField is one field of a validateStruct param struct.
+type paramField struct {
+	key         string
+	name        string
+	description string
+	required    bool
+	schema      Schema
+}
+
+// paramFields collects the fields of every param struct, keyed by their
+// normalized name. The first struct to declare a name wins.
+func paramFields(params []interface{}) map[string]paramField {
+	fields := make(map[string]paramField)
+	for _, p := range params {
+		t := reflect.TypeOf(p)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			continue
+		}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, ok := fieldName(sf)
+			if !ok {
+				continue
+			}
+			key := normalizeName(name)
+			if _, dup := fields[key]; dup {
+				continue
+			}
+			schema, required := fieldSchema(sf)
+			fields[key] = paramField{
+				key:         key,
+				name:        name,
+				description: schema.Description,
+				required:    required,
+				schema:      schema,
+			}
+		}
+	}
+	return fields
+}
+
+// sortedFields returns fields ordered by name for a stable spec.
+func sortedFields(fields map[string]paramField) []paramField {
+	out := make([]paramField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
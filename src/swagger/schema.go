@@ -0,0 +1,303 @@
+package swagger
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaOf returns the JSON schema of v's type as encoding/json would
+// marshal it, with go-playground/validator tags applied to struct fields.
+// v is usually a typed nil pointer such as (*fooParams)(nil); a nil
+// interface yields the empty (any) schema.
+func SchemaOf(v interface{}) Schema {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return Schema{}
+	}
+	return schemaForType(t, make(map[reflect.Type]bool))
+}
+
+// schemaForType maps a Go type to a schema. visiting guards against
+// recursive types, which are cut off as a plain object.
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return Schema{Type: "integer", Format: "int64", Description: "duration in nanoseconds"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return Schema{Type: "integer"}
+	case reflect.Int64:
+		return Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{Type: "string", Format: "byte"}
+		}
+		items := schemaForType(t.Elem(), visiting)
+		return Schema{Type: "array", Items: &items}
+	case reflect.Map:
+		values := schemaForType(t.Elem(), visiting)
+		return Schema{Type: "object", AdditionalProperties: &values}
+	case reflect.Struct:
+		if visiting[t] {
+			return Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := Schema{Type: "object", Properties: make(map[string]Schema)}
+		addStructFields(&s, t, visiting)
+		return s
+	default:
+		// interface{} and anything encoding/json cannot pin down
+		return Schema{}
+	}
+}
+
+// addStructFields adds t's encoded fields to s, flattening untagged
+// embedded structs the way encoding/json does.
+func addStructFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+
+		if sf.Anonymous && tag == "" {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !visiting[ft] {
+				addStructFields(s, ft, visiting)
+				continue
+			}
+		}
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		name := sf.Name
+		if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+			name = tagName
+		}
+		field := schemaForType(sf.Type, visiting)
+		if applyValidateTag(&field, sf) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = field
+	}
+}
+
+// fieldName returns the query/path parameter name for a param struct
+// field: its json tag name, or the field name in snake_case.
+func fieldName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() {
+		return "", false
+	}
+	tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch tag {
+	case "-":
+		return "", false
+	case "":
+		return snakeCase(sf.Name), true
+	default:
+		return tag, true
+	}
+}
+
+// fieldSchema returns a param struct field's schema with its validate
+// tag applied, and whether the field is required.
+func fieldSchema(sf reflect.StructField) (Schema, bool) {
+	s := schemaForType(sf.Type, make(map[reflect.Type]bool))
+	required := applyValidateTag(&s, sf)
+	return s, required
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping initialisms
+// together: FromBase -> from_base, ISBN -> isbn, MaxURLs -> max_urls.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			// An upper-case rune followed by lower case starts a new word
+			// after an initialism (URLPath), unless it is a plural "s"
+			// ending the name (MaxURLs).
+			pluralEnd := i+2 == len(runes) && runes[i+1] == 's'
+			wordAfterInitialism := unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !pluralEnd
+			if prevLower || wordAfterInitialism {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// applyValidateTag maps a field's go-playground/validator rules onto its
+// schema and reports whether the field is required. Rules after "dive"
+// apply to elements and are ignored.
+func applyValidateTag(s *Schema, sf reflect.StructField) bool {
+	tag := sf.Tag.Get("validate")
+	if tag == "" {
+		return false
+	}
+
+	kind := sf.Type.Kind()
+	if kind == reflect.Ptr {
+		kind = sf.Type.Elem().Kind()
+	}
+	isNumber := s.Type == "integer" || s.Type == "number"
+	isCollection := kind == reflect.Slice || kind == reflect.Array
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			break
+		}
+		switch name {
+		case "required":
+			required = true
+		case "min", "gte", "max", "lte", "len", "gt", "lt":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			applyBound(s, name, n, isNumber, isCollection)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(s.Type, v))
+			}
+		case "email":
+			s.Format = "email"
+		case "url", "http_url", "uri":
+			s.Format = "uri"
+		case "uuid", "uuid3", "uuid4", "uuid5":
+			s.Format = "uuid"
+		case "ip", "ip_addr":
+			s.Format = "ip"
+		case "ipv4", "ip4_addr":
+			s.Format = "ipv4"
+		case "ipv6", "ip6_addr":
+			s.Format = "ipv6"
+		case "hostname", "hostname_rfc1123", "fqdn":
+			s.Format = "hostname"
+		case "datetime":
+			s.Description = joinDescription(s.Description, "Go time layout "+param)
+		case "latitude", "longitude":
+			limit := 90.0
+			if name == "longitude" {
+				limit = 180
+			}
+			if isNumber {
+				applyBound(s, "gte", -limit, true, false)
+				applyBound(s, "lte", limit, true, false)
+			} else {
+				s.Description = joinDescription(s.Description, name)
+			}
+		case "numeric", "number":
+			s.Description = joinDescription(s.Description, "numeric")
+		case "alpha", "alphanum", "hexadecimal", "base64", "ascii", "lowercase", "uppercase":
+			s.Description = joinDescription(s.Description, name)
+		}
+	}
+	return required
+}
+
+// applyBound applies a numeric validator rule as a value, length or item
+// count bound depending on the field's type.
+func applyBound(s *Schema, rule string, n float64, isNumber, isCollection bool) {
+	if isNumber {
+		switch rule {
+		case "min", "gte":
+			s.Minimum = &n
+		case "max", "lte":
+			s.Maximum = &n
+		case "gt":
+			s.Minimum, s.ExclusiveMinimum = &n, true
+		case "lt":
+			s.Maximum, s.ExclusiveMaximum = &n, true
+		case "len":
+			s.Minimum, s.Maximum = &n, &n
+		}
+		return
+	}
+
+	v := int(n)
+	switch rule {
+	case "gt":
+		v++
+	case "lt":
+		v--
+	}
+	minPtr, maxPtr := &s.MinLength, &s.MaxLength
+	if isCollection {
+		minPtr, maxPtr = &s.MinItems, &s.MaxItems
+	}
+	switch rule {
+	case "min", "gte", "gt":
+		*minPtr = &v
+	case "max", "lte", "lt":
+		*maxPtr = &v
+	case "len":
+		*minPtr, *maxPtr = &v, &v
+	}
+}
+
+// enumValue converts a oneof value to the schema's type.
+func enumValue(schemaType, v string) interface{} {
+	switch schemaType {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+// joinDescription appends a constraint note to a description.
+func joinDescription(description, note string) string {
+	if description == "" {
+		return note
+	}
+	return description + "; " + note
+}
@@ -4,15 +4,72 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+type testHashParams struct {
+	Algorithm string `validate:"required,oneof=md5 sha1 sha256"`
+	Input     string `validate:"required"`
+}
+
+type testListParams struct {
+	Count  int    `validate:"omitempty,gt=0,lte=100"`
+	Format string `validate:"omitempty,oneof=json csv"`
+}
+
+type testResult struct {
+	Value   string   `json:"value"`
+	Tags    []string `json:"tags,omitempty"`
+	private string
+}
+
+// withEndpoints registers eps for the duration of the test.
+func withEndpoints(t *testing.T, eps []Endpoint) {
+	t.Helper()
+	prev := registeredEndpoints()
+	SetEndpoints(eps)
+	t.Cleanup(func() { SetEndpoints(prev) })
+}
+
+func testEndpoints() []Endpoint {
+	hash := HandlerDoc{
+		Summary:       "Hash text",
+		Params:        []interface{}{(*testHashParams)(nil)},
+		Response:      (*testResult)(nil),
+		ErrorStatuses: []int{http.StatusBadRequest},
+	}
+	return []Endpoint{
+		{Method: http.MethodGet, Pattern: "/api/v1/text/hash/{algorithm}/{input}", Tag: "Text", Doc: hash},
+		{Method: http.MethodGet, Pattern: "/api/v1/text/list", Tag: "Text", Doc: HandlerDoc{
+			Summary: "List items",
+			Params:  []interface{}{(*testListParams)(nil)},
+		}},
+		{Method: http.MethodGet, Pattern: "/api/v1/text/uuid.txt", Tag: "Text", Doc: HandlerDoc{
+			Summary: "UUID as text",
+			Format:  FormatText,
+		}},
+		{Method: http.MethodPost, Pattern: "/api/v1/text/echo", Tag: "Text", Doc: HandlerDoc{
+			Summary:      "Echo",
+			Body:         (*testResult)(nil),
+			Format:       FormatJSON,
+			LegacyErrors: true,
+		}},
+		{Method: http.MethodGet, Pattern: "/api/v1/weather/radar/{location}", Tag: "Weather", Doc: HandlerDoc{
+			Summary:       "Radar",
+			ErrorStatuses: []int{http.StatusNotImplemented},
+		}},
+		{Method: http.MethodGet, Pattern: "/api/v1/files/*", Tag: "Files", Doc: HandlerDoc{Summary: "Files"}},
+	}
+}
+
 // GenerateSpec must populate the top-level OpenAPI fields from its
-// arguments and wire up all the sub-endpoint groups.
+// arguments and describe every registered endpoint.
 func TestGenerateSpec(t *testing.T) {
+	withEndpoints(t, testEndpoints())
 	spec := GenerateSpec("1.2.3", "https://api.example.com")
 
 	require.NotNil(t, spec)
@@ -22,55 +79,121 @@ func TestGenerateSpec(t *testing.T) {
 	require.Len(t, spec.Servers, 1)
 	assert.Equal(t, "https://api.example.com", spec.Servers[0].URL)
 
-	// Base paths.
-	assert.Contains(t, spec.Paths, "/healthz")
-	assert.Contains(t, spec.Paths, "/api/v1/version")
-
-	// Text endpoints.
-	assert.Contains(t, spec.Paths, "/api/v1/text/uuid")
+	assert.Len(t, spec.Paths, 6)
 	assert.Contains(t, spec.Paths, "/api/v1/text/hash/{algorithm}/{input}")
-	assert.Contains(t, spec.Paths, "/api/v1/text/encode/{encoding}/{input}")
-	assert.Contains(t, spec.Paths, "/api/v1/text/decode/{encoding}/{input}")
-
-	// Crypto endpoints.
-	assert.Contains(t, spec.Paths, "/api/v1/crypto/bcrypt/{password}")
-	assert.Contains(t, spec.Paths, "/api/v1/crypto/password")
-	assert.Contains(t, spec.Paths, "/api/v1/crypto/totp/secret")
-
-	// DateTime endpoints.
-	assert.Contains(t, spec.Paths, "/api/v1/datetime/now")
-	assert.Contains(t, spec.Paths, "/api/v1/datetime/convert/{timestamp}")
-	assert.Contains(t, spec.Paths, "/api/v1/datetime/timezones")
-
-	// Network endpoints.
-	assert.Contains(t, spec.Paths, "/api/v1/network/ip")
-	assert.Contains(t, spec.Paths, "/api/v1/network/headers")
+	assert.Contains(t, spec.Paths, "/api/v1/files/{path}")
 
 	// Reusable schema components.
 	require.Contains(t, spec.Components.Schemas, "Error")
 	assert.Equal(t, "object", spec.Components.Schemas["Error"].Type)
-}
-
-// The health check path must document a GET operation with a 200 response.
-func TestGenerateSpecHealthzOperation(t *testing.T) {
-	spec := GenerateSpec("1.0.0", "http://localhost")
-	item := spec.Paths["/healthz"]
-	require.NotNil(t, item.Get)
-	assert.Equal(t, "healthCheck", item.Get.OperationID)
-	assert.Contains(t, item.Get.Responses, "200")
+	assert.Contains(t, spec.Components.Schemas, "Envelope")
+	assert.Contains(t, spec.Components.Schemas, "ErrorEnvelope")
 }
 
 // Path parameters (e.g. {algorithm}, {input}) must be declared as required
-// "path" parameters on the corresponding operation.
+// "path" parameters, typed and constrained by the handler's param struct.
 func TestGenerateSpecHashPathParameters(t *testing.T) {
+	withEndpoints(t, testEndpoints())
 	spec := GenerateSpec("1.0.0", "http://localhost")
 	item := spec.Paths["/api/v1/text/hash/{algorithm}/{input}"]
 	require.NotNil(t, item.Get)
+	assert.Equal(t, "getTextHashByAlgorithmByInput", item.Get.OperationID)
+	assert.Equal(t, []string{"Text"}, item.Get.Tags)
 	require.Len(t, item.Get.Parameters, 2)
 	for _, p := range item.Get.Parameters {
 		assert.Equal(t, "path", p.In)
 		assert.True(t, p.Required)
 	}
+	assert.Equal(t, "algorithm", item.Get.Parameters[0].Name)
+	assert.Equal(t, []interface{}{"md5", "sha1", "sha256"}, item.Get.Parameters[0].Schema.Enum)
+}
+
+// Param struct fields on a GET route without path parameters become
+// optional query parameters with their validate constraints.
+func TestGenerateSpecQueryParameters(t *testing.T) {
+	withEndpoints(t, testEndpoints())
+	spec := GenerateSpec("1.0.0", "http://localhost")
+	op := spec.Paths["/api/v1/text/list"].Get
+	require.NotNil(t, op)
+	require.Len(t, op.Parameters, 2)
+
+	count := op.Parameters[0]
+	assert.Equal(t, "count", count.Name)
+	assert.Equal(t, "query", count.In)
+	assert.False(t, count.Required)
+	assert.Equal(t, "integer", count.Schema.Type)
+	require.NotNil(t, count.Schema.Minimum)
+	assert.Equal(t, 0.0, *count.Schema.Minimum)
+	assert.True(t, count.Schema.ExclusiveMinimum)
+	require.NotNil(t, count.Schema.Maximum)
+	assert.Equal(t, 100.0, *count.Schema.Maximum)
+
+	assert.Equal(t, "format", op.Parameters[1].Name)
+}
+
+// Responses must follow the handler's format: envelope, bare JSON, text,
+// and 501 stubs without a success response.
+func TestGenerateSpecResponses(t *testing.T) {
+	withEndpoints(t, testEndpoints())
+	spec := GenerateSpec("1.0.0", "http://localhost")
+
+	hash := spec.Paths["/api/v1/text/hash/{algorithm}/{input}"].Get
+	ok := hash.Responses["200"].Content["application/json"].Schema
+	require.Len(t, ok.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/Envelope", ok.AllOf[0].Ref)
+	assert.Contains(t, ok.AllOf[1].Properties["data"].Properties, "value")
+	for _, status := range []string{"400", "429", "500"} {
+		require.Contains(t, hash.Responses, status)
+		assert.Equal(t, "#/components/schemas/ErrorEnvelope", hash.Responses[status].Content["application/json"].Schema.Ref)
+	}
+
+	text := spec.Paths["/api/v1/text/uuid.txt"].Get
+	assert.Contains(t, text.Responses["200"].Content, "text/plain")
+
+	echo := spec.Paths["/api/v1/text/echo"].Post
+	require.NotNil(t, echo.RequestBody)
+	assert.Contains(t, echo.RequestBody.Content["application/json"].Schema.Properties, "value")
+	assert.Equal(t, "object", echo.Responses["200"].Content["application/json"].Schema.Type)
+	assert.Equal(t, "#/components/schemas/Error", echo.Responses["500"].Content["application/json"].Schema.Ref)
+
+	radar := spec.Paths["/api/v1/weather/radar/{location}"].Get
+	assert.NotContains(t, radar.Responses, "200")
+	assert.Contains(t, radar.Responses, "501")
+}
+
+// SchemaOf must follow encoding/json naming and apply validate tags.
+func TestSchemaOf(t *testing.T) {
+	s := SchemaOf((*testResult)(nil))
+	assert.Equal(t, "object", s.Type)
+	assert.Len(t, s.Properties, 2)
+	assert.Equal(t, "array", s.Properties["tags"].Type)
+	assert.Equal(t, "string", s.Properties["tags"].Items.Type)
+
+	p := SchemaOf((*testHashParams)(nil))
+	assert.ElementsMatch(t, []string{"Algorithm", "Input"}, p.Required)
+
+	assert.Equal(t, Schema{}, SchemaOf(nil))
+	assert.Equal(t, "byte", SchemaOf([]byte(nil)).Format)
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "from_base", snakeCase("FromBase"))
+	assert.Equal(t, "isbn", snakeCase("ISBN"))
+	assert.Equal(t, "max_urls", snakeCase("MaxURLs"))
+	assert.Equal(t, "url_path", snakeCase("URLPath"))
+}
+
+// String length bounds must map to minLength/maxLength, not minimum.
+func TestApplyValidateTagStringBounds(t *testing.T) {
+	sf := reflect.StructField{Name: "Name", Type: reflect.TypeOf(""), Tag: `validate:"required,min=3,max=10,email"`}
+	s := Schema{Type: "string"}
+	assert.True(t, applyValidateTag(&s, sf))
+	require.NotNil(t, s.MinLength)
+	assert.Equal(t, 3, *s.MinLength)
+	require.NotNil(t, s.MaxLength)
+	assert.Equal(t, 10, *s.MaxLength)
+	assert.Nil(t, s.Minimum)
+	assert.Equal(t, "email", s.Format)
 }
 
 // ServeSpec must write a JSON-encoded spec with the version/baseURL baked
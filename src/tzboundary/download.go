@@ -0,0 +1,120 @@
+package tzboundary
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// releaseURL is the latest timezone-boundary-builder release without
+// ocean zones; points at sea resolve to nautical Etc/GMT zones instead,
+// which keeps the dataset a fraction of the size.
+const releaseURL = "https://github.com/evansiroky/timezone-boundary-builder/releases/latest/download/timezones.geojson.zip"
+
+// maxArchiveSize caps the downloaded release archive (currently ~50 MB).
+const maxArchiveSize = 512 << 20
+
+// httpClient bounds the whole release download; the archive is large, so
+// the timeout is generous but still stops a stalled transfer.
+var httpClient = &http.Client{
+	Timeout: 10 * time.Minute,
+}
+
+// Download fetches the latest timezone-boundary-builder release, converts
+// it into {data_dir}/geo/timezones.bin.gz and reloads the dataset.
+func Download(ctx context.Context, dataDir string) error {
+	log.Println("TZ boundary: Downloading latest dataset...")
+
+	path := datasetPath(dataDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create geo directory: %w", err)
+	}
+	if err := fetch(ctx, path); err != nil {
+		return err
+	}
+
+	return Get().Load(dataDir)
+}
+
+// fetch downloads the upstream release and atomically writes the built
+// dataset to path. The archive is spooled to a temp file since zip needs
+// random access.
+func fetch(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+
+	archive, err := os.CreateTemp("", "timezones-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	size, err := io.Copy(archive, io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if size > maxArchiveSize {
+		return fmt.Errorf("archive exceeds %d bytes", maxArchiveSize)
+	}
+
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return fmt.Errorf("invalid archive: %w", err)
+	}
+	var geojson *zip.File
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, ".json") || strings.HasSuffix(f.Name, ".geojson") {
+			geojson = f
+			break
+		}
+	}
+	if geojson == nil {
+		return fmt.Errorf("archive contains no GeoJSON file")
+	}
+
+	src, err := geojson.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", geojson.Name, err)
+	}
+	defer src.Close()
+
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	err = Build(src, file)
+	file.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to build dataset: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	log.Printf("TZ boundary: Built dataset from %s into %s", geojson.Name, path)
+	return nil
+}
@@ -0,0 +1,336 @@
+package tzboundary
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The dataset format is a gzip stream of:
+//
+//	"TZB1"
+//	uvarint zone count, then per zone: uvarint length + name bytes
+//	uvarint polygon count, then per polygon:
+//	    uvarint zone index, uvarint ring count, then per ring:
+//	        uvarint point count, then per point: zigzag varint lon, lat
+//	        deltas from the previous point in 1e-5° units
+//
+// Delta-encoded varints keep neighbouring boundary points to a byte or two
+// each, and gzip compresses the rest.
+const magic = "TZB1"
+
+// scale is the quantization factor: 1e-5° is ~1.1 m at the equator, finer
+// than the boundary data's own accuracy.
+const scale = 100000
+
+// Decode limits, bounding what a corrupt or hostile dataset file can make
+// decode allocate.
+const (
+	maxZones         = 1 << 12
+	maxZoneNameLen   = 64
+	maxPolygons      = 1 << 18
+	maxRings         = 1 << 14
+	maxRingPoints    = 1 << 22
+	maxDatasetPoints = 1 << 26
+)
+
+// simplifyTolerance drops a boundary point closer than this (in quantized
+// units, ~11 m) to the previously kept one, shrinking the dataset several
+// times over with no visible effect on lookups.
+const simplifyTolerance = 10
+
+// quantize converts degrees to the dataset's integer units.
+func quantize(deg float64) int32 {
+	return int32(math.Round(deg * scale))
+}
+
+// encode writes zones and polygons in the dataset format to w.
+func encode(w io.Writer, zones []string, polygons []polygon) error {
+	gz := gzip.NewWriter(w)
+	bw := bufio.NewWriter(gz)
+	var buf [binary.MaxVarintLen64]byte
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		bw.Write(buf[:n])
+	}
+	putVarint := func(v int64) {
+		n := binary.PutVarint(buf[:], v)
+		bw.Write(buf[:n])
+	}
+
+	bw.WriteString(magic)
+	putUvarint(uint64(len(zones)))
+	for _, z := range zones {
+		putUvarint(uint64(len(z)))
+		bw.WriteString(z)
+	}
+	putUvarint(uint64(len(polygons)))
+	for _, p := range polygons {
+		putUvarint(uint64(p.zone))
+		putUvarint(uint64(len(p.rings)))
+		for _, ring := range p.rings {
+			putUvarint(uint64(len(ring) / 2))
+			var px, py int32
+			for i := 0; i < len(ring); i += 2 {
+				putVarint(int64(ring[i] - px))
+				putVarint(int64(ring[i+1] - py))
+				px, py = ring[i], ring[i+1]
+			}
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// decode reads a dataset written by encode.
+func decode(r io.Reader) ([]string, []polygon, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timezone dataset: %w", err)
+	}
+	defer gz.Close()
+	br := bufio.NewReader(gz)
+
+	head := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, head); err != nil || string(head) != magic {
+		return nil, nil, fmt.Errorf("invalid timezone dataset: bad header")
+	}
+
+	readCount := func(what string, limit uint64) (int, error) {
+		v, err := binary.ReadUvarint(br)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timezone dataset: reading %s: %w", what, err)
+		}
+		if v > limit {
+			return 0, fmt.Errorf("invalid timezone dataset: %s %d exceeds %d", what, v, limit)
+		}
+		return int(v), nil
+	}
+
+	zoneCount, err := readCount("zone count", maxZones)
+	if err != nil {
+		return nil, nil, err
+	}
+	zones := make([]string, zoneCount)
+	for i := range zones {
+		n, err := readCount("zone name length", maxZoneNameLen)
+		if err != nil {
+			return nil, nil, err
+		}
+		name := make([]byte, n)
+		if _, err := io.ReadFull(br, name); err != nil {
+			return nil, nil, fmt.Errorf("invalid timezone dataset: reading zone name: %w", err)
+		}
+		zones[i] = string(name)
+	}
+
+	polygonCount, err := readCount("polygon count", maxPolygons)
+	if err != nil {
+		return nil, nil, err
+	}
+	polygons := make([]polygon, 0, polygonCount)
+	totalPoints := 0
+	for i := 0; i < polygonCount; i++ {
+		zone, err := readCount("zone index", uint64(zoneCount))
+		if err != nil {
+			return nil, nil, err
+		}
+		if zone >= zoneCount {
+			return nil, nil, fmt.Errorf("invalid timezone dataset: zone index %d out of range", zone)
+		}
+		ringCount, err := readCount("ring count", maxRings)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ringCount == 0 {
+			return nil, nil, fmt.Errorf("invalid timezone dataset: polygon without rings")
+		}
+
+		p := polygon{zone: zone, rings: make([][]int32, ringCount)}
+		for ri := range p.rings {
+			points, err := readCount("ring point count", maxRingPoints)
+			if err != nil {
+				return nil, nil, err
+			}
+			totalPoints += points
+			if totalPoints > maxDatasetPoints {
+				return nil, nil, fmt.Errorf("invalid timezone dataset: more than %d points", maxDatasetPoints)
+			}
+			ring := make([]int32, 2*points)
+			var x, y int64
+			for pi := 0; pi < points; pi++ {
+				dx, err := binary.ReadVarint(br)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid timezone dataset: reading point: %w", err)
+				}
+				dy, err := binary.ReadVarint(br)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid timezone dataset: reading point: %w", err)
+				}
+				x, y = x+dx, y+dy
+				if x < -180*scale || x > 180*scale || y < -90*scale || y > 90*scale {
+					return nil, nil, fmt.Errorf("invalid timezone dataset: point out of range")
+				}
+				ring[2*pi], ring[2*pi+1] = int32(x), int32(y)
+			}
+			p.rings[ri] = ring
+		}
+		if len(p.rings[0]) < 6 {
+			return nil, nil, fmt.Errorf("invalid timezone dataset: outer ring needs at least 3 points")
+		}
+		p.setBounds()
+		polygons = append(polygons, p)
+	}
+
+	return zones, polygons, nil
+}
+
+// setBounds computes the polygon's bounding box from its outer ring.
+func (p *polygon) setBounds() {
+	outer := p.rings[0]
+	p.minLon, p.maxLon = outer[0], outer[0]
+	p.minLat, p.maxLat = outer[1], outer[1]
+	for i := 2; i < len(outer); i += 2 {
+		p.minLon, p.maxLon = min(p.minLon, outer[i]), max(p.maxLon, outer[i])
+		p.minLat, p.maxLat = min(p.minLat, outer[i+1]), max(p.maxLat, outer[i+1])
+	}
+}
+
+// geoJSONFeature is one timezone-boundary-builder feature: a tzid and a
+// Polygon or MultiPolygon geometry.
+type geoJSONFeature struct {
+	Properties struct {
+		TZID string `json:"tzid"`
+	} `json:"properties"`
+	Geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// Build converts a timezone-boundary-builder GeoJSON FeatureCollection
+// into the compressed dataset format, quantizing and simplifying every
+// ring. Features are decoded one at a time, so memory stays proportional
+// to the output rather than the (multi-hundred-megabyte) input.
+func Build(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	if err := seekFeatures(dec); err != nil {
+		return err
+	}
+
+	zoneIndex := make(map[string]int)
+	var zones []string
+	var polygons []polygon
+	for dec.More() {
+		var f geoJSONFeature
+		if err := dec.Decode(&f); err != nil {
+			return fmt.Errorf("decoding feature: %w", err)
+		}
+		if f.Properties.TZID == "" || len(f.Properties.TZID) > maxZoneNameLen {
+			continue
+		}
+
+		var shapes [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var rings [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &rings); err != nil {
+				return fmt.Errorf("decoding %s polygon: %w", f.Properties.TZID, err)
+			}
+			shapes = [][][][2]float64{rings}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &shapes); err != nil {
+				return fmt.Errorf("decoding %s multipolygon: %w", f.Properties.TZID, err)
+			}
+		default:
+			continue
+		}
+
+		zone, ok := zoneIndex[f.Properties.TZID]
+		if !ok {
+			zone = len(zones)
+			zoneIndex[f.Properties.TZID] = zone
+			zones = append(zones, f.Properties.TZID)
+		}
+		for _, shape := range shapes {
+			p := polygon{zone: zone}
+			for _, ring := range shape {
+				if q := quantizeRing(ring); q != nil {
+					p.rings = append(p.rings, q)
+				} else if len(p.rings) == 0 {
+					break
+				}
+			}
+			if len(p.rings) > 0 {
+				polygons = append(polygons, p)
+			}
+		}
+	}
+	if len(zones) > maxZones || len(polygons) > maxPolygons {
+		return fmt.Errorf("dataset too large: %d zones, %d polygons", len(zones), len(polygons))
+	}
+
+	return encode(w, zones, polygons)
+}
+
+// seekFeatures advances dec to just inside the top-level "features" array.
+func seekFeatures(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("expected a GeoJSON FeatureCollection object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("reading GeoJSON: %w", err)
+		}
+		if key, ok := tok.(string); ok && key == "features" {
+			tok, err := dec.Token()
+			if err != nil || tok != json.Delim('[') {
+				return fmt.Errorf("GeoJSON features must be an array")
+			}
+			return nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return fmt.Errorf("reading GeoJSON: %w", err)
+		}
+	}
+	return fmt.Errorf("GeoJSON has no features array")
+}
+
+// quantizeRing converts a GeoJSON ring to quantized coordinates, dropping
+// the closing duplicate point and any point within simplifyTolerance of
+// the last kept one. Rings that collapse below three points return nil.
+func quantizeRing(ring [][2]float64) []int32 {
+	out := make([]int32, 0, 2*len(ring))
+	for i, pt := range ring {
+		x, y := quantize(pt[0]), quantize(pt[1])
+		if x < -180*scale || x > 180*scale || y < -90*scale || y > 90*scale {
+			return nil
+		}
+		if n := len(out); n > 0 && i != len(ring)-1 {
+			dx, dy := x-out[n-2], y-out[n-1]
+			if dx < simplifyTolerance && dx > -simplifyTolerance && dy < simplifyTolerance && dy > -simplifyTolerance {
+				continue
+			}
+		}
+		out = append(out, x, y)
+	}
+	// GeoJSON rings repeat the first point at the end; ringContains closes
+	// the ring implicitly.
+	if n := len(out); n >= 4 && out[0] == out[n-2] && out[1] == out[n-1] {
+		out = out[:n-2]
+	}
+	if len(out) < 6 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,228 @@
+// Package tzboundary resolves the IANA timezone for a coordinate offline,
+// from a compressed copy of the timezone-boundary-builder polygons
+// (github.com/evansiroky/timezone-boundary-builder, ODbL). The dataset is
+// downloaded into {data_dir}/geo/timezones.bin.gz at startup when missing
+// and refreshed by the scheduler's timezone_update task; air-gapped
+// deployments copy that file from a connected instance.
+package tzboundary
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	// The offline lookup is for deployments without network access, which
+	// may also lack a system zoneinfo database; embedding tzdata keeps
+	// time.LoadLocation working for every zone the dataset can return.
+	_ "time/tzdata"
+)
+
+// gridSize is the cell size of the lookup index in quantized units (1°).
+const gridSize = scale
+
+// polygon is one timezone polygon in quantized (1e-5°) coordinates. Ring 0
+// is the outer boundary and any further rings are holes; each ring is a
+// flat lon,lat,lon,lat,... slice.
+type polygon struct {
+	zone                           int
+	minLon, minLat, maxLon, maxLat int32
+	rings                          [][]int32
+}
+
+// DB is a loaded timezone-boundary dataset. A DB with no polygons answers
+// every Lookup with ok=false so that callers fall back to an online
+// provider.
+type DB struct {
+	mu       sync.RWMutex
+	zones    []string
+	polygons []polygon
+	grid     map[int32][]int32
+	source   string
+}
+
+var (
+	db     *DB
+	dbOnce sync.Once
+)
+
+// Get returns the singleton dataset, which is empty until Load finds a
+// downloaded copy.
+func Get() *DB {
+	dbOnce.Do(func() {
+		db = &DB{}
+	})
+	return db
+}
+
+// datasetPath returns the downloaded dataset location under dataDir.
+func datasetPath(dataDir string) string {
+	return filepath.Join(dataDir, "geo", "timezones.bin.gz")
+}
+
+// Load replaces the dataset with {data_dir}/geo/timezones.bin.gz when that
+// file exists and parses. A missing or corrupt file keeps the current
+// dataset rather than failing, mirroring geoip.Load's graceful
+// degradation.
+func (d *DB) Load(dataDir string) error {
+	path := datasetPath(dataDir)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if !d.Loaded() {
+			log.Printf("TZ boundary: no dataset in %s, timezone lookups will use the online provider until downloaded", filepath.Dir(path))
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read timezone dataset: %w", err)
+	}
+	if err := d.loadBytes(data, path); err != nil {
+		log.Printf("TZ boundary: failed to load %s: %v", path, err)
+		return nil
+	}
+	return nil
+}
+
+// loadBytes decodes a dataset and, if it contains any polygons, swaps it
+// in as the active one.
+func (d *DB) loadBytes(data []byte, source string) error {
+	zones, polygons, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if len(polygons) == 0 {
+		return nil
+	}
+	grid := buildGrid(polygons)
+
+	d.mu.Lock()
+	d.zones, d.polygons, d.grid, d.source = zones, polygons, grid, source
+	d.mu.Unlock()
+
+	log.Printf("TZ boundary: loaded %d zones (%d polygons) from %s", len(zones), len(polygons), source)
+	return nil
+}
+
+// Loaded reports whether a dataset with at least one polygon is active.
+func (d *DB) Loaded() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.polygons) > 0
+}
+
+// Source names where the active dataset came from: the downloaded file's
+// path, or "" when none is loaded.
+func (d *DB) Source() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.source
+}
+
+// Lookup returns the IANA timezone containing the coordinate. ok is false
+// only when no dataset is loaded; a point outside every polygon (open sea,
+// for a dataset built without ocean zones) resolves to its nautical
+// Etc/GMT±N zone.
+func (d *DB) Lookup(lat, lon float64) (zone string, ok bool) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return "", false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.polygons) == 0 {
+		return "", false
+	}
+
+	x, y := quantize(lon), quantize(lat)
+	for _, i := range d.grid[cellKey(x, y)] {
+		p := &d.polygons[i]
+		if x < p.minLon || x > p.maxLon || y < p.minLat || y > p.maxLat {
+			continue
+		}
+		if p.contains(x, y) {
+			return d.zones[p.zone], true
+		}
+	}
+	return nauticalZone(lon), true
+}
+
+// contains reports whether (x, y) lies inside the outer ring and outside
+// every hole.
+func (p *polygon) contains(x, y int32) bool {
+	if !ringContains(p.rings[0], x, y) {
+		return false
+	}
+	for _, hole := range p.rings[1:] {
+		if ringContains(hole, x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is the even-odd ray-casting test on a flat lon,lat ring,
+// computed in int64 so quantized coordinates never overflow.
+func ringContains(ring []int32, x, y int32) bool {
+	inside := false
+	n := len(ring) / 2
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		xi, yi := int64(ring[2*i]), int64(ring[2*i+1])
+		xj, yj := int64(ring[2*j]), int64(ring[2*j+1])
+		if (yi > int64(y)) == (yj > int64(y)) {
+			continue
+		}
+		// x-coordinate of the edge at y, compared without division:
+		// x < xi + (y-yi)*(xj-xi)/(yj-yi)
+		lhs := (int64(x) - xi) * (yj - yi)
+		rhs := (int64(y) - yi) * (xj - xi)
+		if (yj-yi > 0 && lhs < rhs) || (yj-yi < 0 && lhs > rhs) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// nauticalZone returns the Etc/GMT zone for a longitude at sea, where
+// nautical time runs in 15° bands centred on multiples of 15°. Etc/GMT
+// names invert the sign: UTC+2 is "Etc/GMT-2".
+func nauticalZone(lon float64) string {
+	offset := int((lon+187.5)/15) - 12
+	if offset > 12 {
+		offset = 12
+	}
+	switch {
+	case offset == 0:
+		return "Etc/GMT"
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", offset)
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+}
+
+// cellKey maps a quantized coordinate to its grid cell.
+func cellKey(x, y int32) int32 {
+	cx := (x + 180*scale) / gridSize
+	cy := (y + 90*scale) / gridSize
+	return cy*361 + cx
+}
+
+// buildGrid indexes every polygon under each grid cell its bounding box
+// touches, so Lookup only tests polygons near the point.
+func buildGrid(polygons []polygon) map[int32][]int32 {
+	grid := make(map[int32][]int32)
+	for i, p := range polygons {
+		minCell, maxCell := cellKey(p.minLon, p.minLat), cellKey(p.maxLon, p.maxLat)
+		minX, minY := minCell%361, minCell/361
+		maxX, maxY := maxCell%361, maxCell/361
+		for cy := minY; cy <= maxY; cy++ {
+			for cx := minX; cx <= maxX; cx++ {
+				key := cy*361 + cx
+				grid[key] = append(grid[key], int32(i))
+			}
+		}
+	}
+	return grid
+}
@@ -0,0 +1,164 @@
+package tzboundary
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGeoJSON is a small timezone-boundary-builder style collection: a
+// square "Test/Outer" zone with a hole filled by "Test/Inner", and a
+// two-part "Test/Multi" MultiPolygon, plus a leading key before
+// "features" and a feature without a tzid.
+const testGeoJSON = `{
+  "type": "FeatureCollection",
+  "name": "combined-shapefile",
+  "features": [
+    {"type": "Feature", "properties": {"tzid": "Test/Outer"}, "geometry": {"type": "Polygon", "coordinates": [
+      [[0,0],[10,0],[10,10],[0,10],[0,0]],
+      [[4,4],[6,4],[6,6],[4,6],[4,4]]
+    ]}},
+    {"type": "Feature", "properties": {"tzid": "Test/Inner"}, "geometry": {"type": "Polygon", "coordinates": [
+      [[4,4],[6,4],[6,6],[4,6],[4,4]]
+    ]}},
+    {"type": "Feature", "properties": {"tzid": "Test/Multi"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+      [[[-20,-20],[-15,-20],[-15,-15],[-20,-15],[-20,-20]]],
+      [[[170,50],[179.5,50],[179.5,55],[170,55],[170,50]]]
+    ]}},
+    {"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [
+      [[50,50],[51,50],[51,51],[50,51],[50,50]]
+    ]}}
+  ]
+}`
+
+// loadTestDB builds testGeoJSON into a fresh DB.
+func loadTestDB(t *testing.T) *DB {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Build(strings.NewReader(testGeoJSON), &buf))
+	d := &DB{}
+	require.NoError(t, d.loadBytes(buf.Bytes(), "test"))
+	require.True(t, d.Loaded())
+	return d
+}
+
+func TestGet_ReturnsSingleton(t *testing.T) {
+	assert.Same(t, Get(), Get())
+}
+
+func TestDatasetPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/data", "geo", "timezones.bin.gz"), datasetPath("/data"))
+}
+
+// Lookup must resolve polygons, respect holes, find every part of a
+// MultiPolygon and fall back to nautical zones outside all polygons.
+func TestLookup(t *testing.T) {
+	d := loadTestDB(t)
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     string
+	}{
+		{"outer", 2, 2, "Test/Outer"},
+		{"hole belongs to inner", 5, 5, "Test/Inner"},
+		{"multipolygon first part", -17, -17, "Test/Multi"},
+		{"multipolygon second part", 52, 175, "Test/Multi"},
+		{"feature without tzid skipped", 50.5, 50.5, "Etc/GMT-3"},
+		{"sea near greenwich", 30, 5, "Etc/GMT"},
+		{"sea west", 30, -100, "Etc/GMT+7"},
+		{"sea east", -30, 150, "Etc/GMT-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, ok := d.Lookup(tt.lat, tt.lon)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, zone)
+		})
+	}
+
+	_, ok := d.Lookup(91, 0)
+	assert.False(t, ok)
+}
+
+// A DB without polygons reports ok=false so callers fall back online.
+func TestLookup_NotLoaded(t *testing.T) {
+	d := &DB{}
+	_, ok := d.Lookup(2, 2)
+	assert.False(t, ok)
+
+	var buf bytes.Buffer
+	require.NoError(t, encode(&buf, nil, nil))
+	require.NoError(t, d.loadBytes(buf.Bytes(), "empty"))
+	assert.False(t, d.Loaded())
+}
+
+func TestNauticalZone(t *testing.T) {
+	assert.Equal(t, "Etc/GMT", nauticalZone(7.4))
+	assert.Equal(t, "Etc/GMT-1", nauticalZone(7.6))
+	assert.Equal(t, "Etc/GMT+1", nauticalZone(-7.6))
+	assert.Equal(t, "Etc/GMT-12", nauticalZone(180))
+	assert.Equal(t, "Etc/GMT+12", nauticalZone(-180))
+}
+
+// Load must prefer a downloaded dataset and keep the current one when the
+// file is missing or corrupt.
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	d := &DB{}
+
+	require.NoError(t, d.Load(dir))
+	assert.False(t, d.Loaded())
+
+	path := datasetPath(dir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("not a dataset"), 0644))
+	require.NoError(t, d.Load(dir))
+	assert.False(t, d.Loaded())
+
+	var buf bytes.Buffer
+	require.NoError(t, Build(strings.NewReader(testGeoJSON), &buf))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	require.NoError(t, d.Load(dir))
+	assert.True(t, d.Loaded())
+	assert.Equal(t, path, d.Source())
+
+	zone, ok := d.Lookup(2, 2)
+	require.True(t, ok)
+	assert.Equal(t, "Test/Outer", zone)
+}
+
+// Build must reject input that is not a FeatureCollection.
+func TestBuild_InvalidInput(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, Build(strings.NewReader(`[]`), &buf))
+	assert.Error(t, Build(strings.NewReader(`{"type":"FeatureCollection"}`), &buf))
+	assert.Error(t, Build(strings.NewReader(`{"features":{}}`), &buf))
+}
+
+// decode must reject truncated or garbage datasets without panicking.
+func TestDecode_Invalid(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Build(strings.NewReader(testGeoJSON), &buf))
+	data := buf.Bytes()
+
+	_, _, err := decode(bytes.NewReader([]byte("garbage")))
+	assert.Error(t, err)
+	_, _, err = decode(bytes.NewReader(data[:len(data)/2]))
+	assert.Error(t, err)
+}
+
+// quantizeRing must drop the closing point and near-duplicate points, and
+// discard rings that collapse.
+func TestQuantizeRing(t *testing.T) {
+	ring := quantizeRing([][2]float64{{0, 0}, {0.00001, 0}, {1, 0}, {1, 1}, {0, 0}})
+	assert.Equal(t, []int32{0, 0, 100000, 0, 100000, 100000}, ring)
+
+	assert.Nil(t, quantizeRing([][2]float64{{0, 0}, {0.00001, 0.00001}, {0, 0}}))
+	assert.Nil(t, quantizeRing([][2]float64{{0, 0}, {200, 0}, {1, 1}, {0, 0}}))
+}